@@ -74,6 +74,9 @@ func (s *bootstrapSuite) TestInitializeState(c *gc.C) {
 		} else if name == network.DefaultKVMBridge {
 			// claim we don't have a virbr0 bridge
 			return nil, nil
+		} else if name == network.DefaultNspawnBridge {
+			// claim we don't have a nspawnbr0 bridge
+			return nil, nil
 		}
 		c.Fatalf("unknown bridge in testing: %v", name)
 		return nil, nil
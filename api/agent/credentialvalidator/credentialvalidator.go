@@ -103,3 +103,28 @@ func (c *Facade) WatchModelCredential() (watcher.NotifyWatcher, error) {
 	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// ReportClusterHealth records the health of the model's cluster API
+// endpoints in the model status.
+func (c *Facade) ReportClusterHealth(endpoint string, unhealthy []string, healthErr error) error {
+	if c.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("reporting cluster health")
+	}
+	in := params.ClusterHealthArg{
+		Endpoint:  endpoint,
+		Unhealthy: unhealthy,
+	}
+	if healthErr != nil {
+		in.Error = healthErr.Error()
+	}
+	var result params.ErrorResult
+	err := c.facade.FacadeCall("ReportClusterHealth", in, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if result.Error != nil {
+		return errors.Trace(result.Error)
+	}
+	return nil
+}
@@ -170,3 +170,53 @@ func (s *CredentialValidatorSuite) TestWatchModelCredentialCallError(c *gc.C) {
 	_, err := client.WatchModelCredential()
 	c.Assert(err, gc.ErrorMatches, "foo")
 }
+
+func (s *CredentialValidatorSuite) TestReportClusterHealth(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "CredentialValidator")
+			c.Check(request, gc.Equals, "ReportClusterHealth")
+			c.Assert(arg, jc.DeepEquals, params.ClusterHealthArg{
+				Endpoint:  "https://10.0.0.2:6443",
+				Unhealthy: []string{"https://10.0.0.1:6443: connection refused"},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResult{})
+			*(result.(*params.ErrorResult)) = params.ErrorResult{}
+			return nil
+		}),
+		BestVersion: 3,
+	}
+
+	client := credentialvalidator.NewFacade(apiCaller)
+	err := client.ReportClusterHealth("https://10.0.0.2:6443", []string{"https://10.0.0.1:6443: connection refused"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CredentialValidatorSuite) TestReportClusterHealthUnhealthy(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Assert(arg, jc.DeepEquals, params.ClusterHealthArg{Error: "no healthy cluster API endpoint"})
+			*(result.(*params.ErrorResult)) = params.ErrorResult{Error: apiservererrors.ServerError(errors.New("boom"))}
+			return nil
+		}),
+		BestVersion: 3,
+	}
+
+	client := credentialvalidator.NewFacade(apiCaller)
+	err := client.ReportClusterHealth("", nil, errors.New("no healthy cluster API endpoint"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *CredentialValidatorSuite) TestReportClusterHealthNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected API call %q", request)
+			return nil
+		}),
+		BestVersion: 2,
+	}
+
+	client := credentialvalidator.NewFacade(apiCaller)
+	err := client.ReportClusterHealth("https://10.0.0.2:6443", nil, nil)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
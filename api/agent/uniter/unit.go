@@ -264,6 +264,31 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	return charm.Settings(result.Settings), nil
 }
 
+// HookTimeouts returns the hook timeouts set in the application config
+// of the unit's application, or an empty string if none are set.
+func (u *Unit) HookTimeouts() (string, error) {
+	if u.st.BestAPIVersion() < 20 {
+		// HookTimeouts() was introduced in UniterAPIV20.
+		return "", errors.NotImplementedf("HookTimeouts() (need V20+)")
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookTimeouts", args, &results)
+	if err != nil {
+		return "", errors.Trace(apiservererrors.RestoreError(err))
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // ApplicationName returns the application name.
 func (u *Unit) ApplicationName() string {
 	application, err := names.UnitApplication(u.Name())
@@ -776,6 +776,44 @@ func (s *unitSuite) TestConfigSettingsNotImplemented(c *gc.C) {
 	c.Assert(err, jc.ErrorIs, errors.NotImplemented)
 }
 
+func (s *unitSuite) TestHookTimeouts(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Uniter")
+			c.Assert(request, gc.Equals, "HookTimeouts")
+			c.Assert(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "unit-mysql-0"}}})
+			c.Assert(result, gc.FitsTypeOf, &params.StringResults{})
+			*(result.(*params.StringResults)) = params.StringResults{
+				Results: []params.StringResult{{Result: "config-changed=10m"}},
+			}
+			return nil
+		},
+		BestVersion: 20,
+	}
+
+	client := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+
+	unit := uniter.CreateUnit(client, names.NewUnitTag("mysql/0"))
+	timeouts, err := unit.HookTimeouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeouts, gc.Equals, "config-changed=10m")
+}
+
+func (s *unitSuite) TestHookTimeoutsOldController(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call %q", request)
+			return nil
+		},
+		BestVersion: 19,
+	}
+	client := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+
+	unit := uniter.CreateUnit(client, names.NewUnitTag("mysql/0"))
+	_, err := unit.HookTimeouts()
+	c.Assert(err, jc.ErrorIs, errors.NotImplemented)
+}
+
 func (s *unitSuite) TestWatchConfigSettingsHash(c *gc.C) {
 	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		if objType == "StringsWatcher" {
@@ -968,6 +968,28 @@ func (c *Client) UnitsInfo(units []names.UnitTag) ([]UnitInfo, error) {
 	return infos, nil
 }
 
+// UnitsHookHistory retrieves the timing of the operations most recently
+// run by the units' agents.
+func (c *Client) UnitsHookHistory(units []names.UnitTag) ([]params.UnitHookHistoryResult, error) {
+	if c.facade.BestAPIVersion() < 21 {
+		return nil, errors.NotSupportedf("hook history on this controller")
+	}
+	all := make([]params.Entity, len(units))
+	for i, one := range units {
+		all[i] = params.Entity{Tag: one.String()}
+	}
+	in := params.Entities{Entities: all}
+	var out params.UnitHookHistoryResults
+	err := c.facade.FacadeCall("UnitsHookHistory", in, &out)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resultsLen := len(out.Results); resultsLen != len(units) {
+		return nil, errors.Errorf("expected %d results, got %d", len(units), resultsLen)
+	}
+	return out.Results, nil
+}
+
 func unitInfoFromParams(in params.UnitInfoResult) UnitInfo {
 	if in.Error != nil {
 		return UnitInfo{Error: stderrors.New(in.Error.Error())}
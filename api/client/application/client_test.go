@@ -1326,6 +1326,52 @@ func (s *applicationSuite) TestUnitsInfoResultMismatch(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 3")
 }
 
+func (s *applicationSuite) TestUnitsHookHistory(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.Entities{
+		Entities: []params.Entity{
+			{Tag: "unit-foo-0"},
+			{Tag: "unit-bar-1"},
+		}}
+	result := new(params.UnitHookHistoryResults)
+	results := params.UnitHookHistoryResults{
+		Results: []params.UnitHookHistoryResult{
+			{Error: &params.Error{Message: "boom"}},
+			{History: []params.HookSpan{{
+				Operation: "run install hook",
+				Execute:   time.Second,
+			}}},
+		},
+	}
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(21)
+	mockFacadeCaller.EXPECT().FacadeCall("UnitsHookHistory", args, result).SetArg(2, results).Return(nil)
+
+	client := application.NewClientFromCaller(mockFacadeCaller)
+	res, err := client.UnitsHookHistory(
+		[]names.UnitTag{
+			names.NewUnitTag("foo/0"),
+			names.NewUnitTag("bar/1"),
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res, jc.DeepEquals, results.Results)
+}
+
+func (s *applicationSuite) TestUnitsHookHistoryNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := mocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(20)
+
+	client := application.NewClientFromCaller(mockFacadeCaller)
+	_, err := client.UnitsHookHistory([]names.UnitTag{names.NewUnitTag("foo/0")})
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
+
 func (s *applicationSuite) TestExpose(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
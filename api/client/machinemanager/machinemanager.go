@@ -107,6 +107,24 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (scrip
 	return result.Script, nil
 }
 
+// MachineUserData returns the userdata which would be passed to the
+// provider when starting an instance for each of the machines. Machines
+// without a tag preview a new machine.
+func (c *Client) MachineUserData(machines ...params.MachineUserDataParam) ([]params.MachineUserDataResult, error) {
+	if c.BestAPIVersion() < 11 {
+		return nil, errors.NotSupportedf("previewing machine userdata")
+	}
+	args := params.MachineUserDataParams{Machines: machines}
+	var results params.MachineUserDataResults
+	if err := c.facade.FacadeCall("MachineUserData", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(machines), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(all bool, machines ...names.MachineTag) ([]params.ErrorResult, error) {
@@ -99,3 +99,29 @@ func (s *NewMachineManagerSuite) setup(c *gc.C) *gomock.Controller {
 
 	return ctrl
 }
+
+func (s *NewMachineManagerSuite) TestMachineUserData(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	machine := params.MachineUserDataParam{MachineTag: s.tag.String()}
+	args := params.MachineUserDataParams{Machines: []params.MachineUserDataParam{machine}}
+	results := params.MachineUserDataResults{Results: []params.MachineUserDataResult{{
+		UserData:     []byte("#cloud-config\npackages: [jq]\n"),
+		BaseUserData: []byte("#cloud-config\n"),
+	}}}
+	s.clientFacade.EXPECT().BestAPIVersion().Return(11)
+	s.facade.EXPECT().FacadeCall("MachineUserData", args, gomock.Any()).SetArg(2, results)
+
+	result, err := s.client.MachineUserData(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, results.Results)
+}
+
+func (s *NewMachineManagerSuite) TestMachineUserDataNotSupported(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.clientFacade.EXPECT().BestAPIVersion().Return(10)
+
+	_, err := s.client.MachineUserData(params.MachineUserDataParam{MachineTag: s.tag.String()})
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
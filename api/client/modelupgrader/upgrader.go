@@ -4,6 +4,7 @@
 package modelupgrader
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -71,13 +72,77 @@ func (c *Client) UpgradeModel(
 	return result.ChosenVersion, errors.Trace(err)
 }
 
+// StageModelUpgrade starts a staged upgrade of the model to the provided
+// agent version. Canary machines or units are upgraded first, then the
+// remaining machines in batches, and the upgrade halts if too many of
+// the upgraded machines report errors.
+func (c *Client) StageModelUpgrade(
+	modelUUID string, targetVersion version.Number, stages params.UpgradeStages, ignoreAgentVersions, dryRun bool,
+) (version.Number, error) {
+	if c.BestAPIVersion() < 2 {
+		return version.Zero, errors.NotSupportedf("staged model upgrades")
+	}
+	args := params.UpgradeModelParams{
+		ModelTag:            names.NewModelTag(modelUUID).String(),
+		TargetVersion:       targetVersion,
+		IgnoreAgentVersions: ignoreAgentVersions,
+		DryRun:              dryRun,
+		Stages:              &stages,
+	}
+	var result params.UpgradeModelResult
+	err := c.facade.FacadeCall("UpgradeModel", args, &result)
+	if err != nil {
+		return result.ChosenVersion, errors.Trace(err)
+	}
+	if result.Error != nil {
+		err = apiservererrors.RestoreError(result.Error)
+	}
+	return result.ChosenVersion, errors.Trace(err)
+}
+
+// PauseUpgradeRollout stops the model's staged upgrade from upgrading
+// any more machines until it is resumed.
+func (c *Client) PauseUpgradeRollout(modelUUID string) error {
+	return c.changeUpgradeRollout("PauseUpgradeRollout", modelUUID)
+}
+
+// ResumeUpgradeRollout resumes the model's paused or halted staged upgrade.
+func (c *Client) ResumeUpgradeRollout(modelUUID string) error {
+	return c.changeUpgradeRollout("ResumeUpgradeRollout", modelUUID)
+}
+
+// AbortUpgradeRollout abandons the model's staged upgrade.
+func (c *Client) AbortUpgradeRollout(modelUUID string) error {
+	return c.changeUpgradeRollout("AbortUpgradeRollout", modelUUID)
+}
+
+func (c *Client) changeUpgradeRollout(method, modelUUID string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("staged model upgrades")
+	}
+	args := params.ModelParam{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+	}
+	err := c.facade.FacadeCall(method, args, nil)
+	return errors.Trace(apiservererrors.RestoreError(err))
+}
+
 // UploadTools uploads tools at the specified location to the API server over HTTPS.
 func (c *Client) UploadTools(r io.ReadSeeker, vers version.Binary) (tools.List, error) {
+	return c.UploadSignedTools(r, vers, nil)
+}
+
+// UploadSignedTools uploads tools at the specified location to the API server
+// over HTTPS, along with their detached signature if it isn't empty.
+func (c *Client) UploadSignedTools(r io.ReadSeeker, vers version.Binary, signature []byte) (tools.List, error) {
 	req, err := http.NewRequest("POST", fmt.Sprintf("/tools?binaryVersion=%s", vers), r)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create upload request")
 	}
 	req.Header.Set("Content-Type", "application/x-tar-gz")
+	if len(signature) > 0 {
+		req.Header.Set(params.AgentBinarySignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	}
 
 	var resp params.ToolsResult
 	// The returned httpClient sets the base url to /model/<uuid> if it can.
@@ -4,11 +4,13 @@
 package modelupgrader_test
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version/v2"
 	"go.uber.org/mock/gomock"
@@ -80,6 +82,97 @@ func (s *UpgradeModelSuite) TestUpgradeModel(c *gc.C) {
 	c.Assert(chosenVersion, gc.DeepEquals, version.MustParse("2.9.99"))
 }
 
+func (s *UpgradeModelSuite) TestStageModelUpgrade(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	apiCaller := mocks.NewMockAPICallCloser(ctrl)
+
+	stages := params.UpgradeStages{
+		Canaries:       []string{"0"},
+		BatchSize:      2,
+		ErrorThreshold: 10,
+	}
+	gomock.InOrder(
+		apiCaller.EXPECT().BestFacadeVersion("ModelUpgrader").Return(2),
+		apiCaller.EXPECT().APICall(
+			"ModelUpgrader", 2, "", "UpgradeModel",
+			params.UpgradeModelParams{
+				ModelTag:      coretesting.ModelTag.String(),
+				TargetVersion: version.MustParse("2.9.1"),
+				Stages:        &stages,
+			}, &params.UpgradeModelResult{},
+		).DoAndReturn(func(objType string, facadeVersion int, id, request string, args, result interface{}) error {
+			out := result.(*params.UpgradeModelResult)
+			out.ChosenVersion = version.MustParse("2.9.1")
+			return nil
+		}),
+	)
+
+	client := modelupgrader.NewClient(apiCaller)
+	chosenVersion, err := client.StageModelUpgrade(
+		coretesting.ModelTag.Id(),
+		version.MustParse("2.9.1"),
+		stages, false, false,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(chosenVersion, gc.DeepEquals, version.MustParse("2.9.1"))
+}
+
+func (s *UpgradeModelSuite) TestStageModelUpgradeNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	apiCaller := mocks.NewMockAPICallCloser(ctrl)
+	apiCaller.EXPECT().BestFacadeVersion("ModelUpgrader").Return(1)
+
+	client := modelupgrader.NewClient(apiCaller)
+	_, err := client.StageModelUpgrade(
+		coretesting.ModelTag.Id(),
+		version.MustParse("2.9.1"),
+		params.UpgradeStages{BatchSize: 1}, false, false,
+	)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *UpgradeModelSuite) TestPauseUpgradeRollout(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	apiCaller := mocks.NewMockAPICallCloser(ctrl)
+
+	gomock.InOrder(
+		apiCaller.EXPECT().BestFacadeVersion("ModelUpgrader").Return(2),
+		apiCaller.EXPECT().APICall(
+			"ModelUpgrader", 2, "", "PauseUpgradeRollout",
+			params.ModelParam{
+				ModelTag: coretesting.ModelTag.String(),
+			}, nil,
+		).Return(nil),
+	)
+
+	client := modelupgrader.NewClient(apiCaller)
+	err := client.PauseUpgradeRollout(coretesting.ModelTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeModelSuite) TestAbortUpgradeRolloutNotFound(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	apiCaller := mocks.NewMockAPICallCloser(ctrl)
+
+	gomock.InOrder(
+		apiCaller.EXPECT().BestFacadeVersion("ModelUpgrader").Return(2),
+		apiCaller.EXPECT().APICall(
+			"ModelUpgrader", 2, "", "AbortUpgradeRollout",
+			params.ModelParam{
+				ModelTag: coretesting.ModelTag.String(),
+			}, nil,
+		).Return(&params.Error{Code: params.CodeNotFound, Message: "staged model upgrade not found"}),
+	)
+
+	client := modelupgrader.NewClient(apiCaller)
+	err := client.AbortUpgradeRollout(coretesting.ModelTag.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *UpgradeModelSuite) TestUploadTools(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -123,3 +216,48 @@ func (s *UpgradeModelSuite) TestUploadTools(c *gc.C) {
 		{Version: version.MustParseBinary("2.9.100-ubuntu-amd64")},
 	})
 }
+
+func (s *UpgradeModelSuite) TestUploadSignedTools(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	apiCaller := mocks.NewMockAPICallCloser(ctrl)
+	doer := mocks.NewMockDoer(ctrl)
+	ctx := mocks.NewMockContext(ctrl)
+
+	req, err := http.NewRequest(
+		"POST",
+		fmt.Sprintf(
+			"/tools?binaryVersion=%s",
+			version.MustParseBinary("2.9.100-ubuntu-amd64"),
+		), nil,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Content-Type", "application/x-tar-gz")
+	req.Header.Set(params.AgentBinarySignatureHeader, base64.StdEncoding.EncodeToString([]byte("signature")))
+	req = req.WithContext(ctx)
+
+	resp := &http.Response{
+		Request:    req,
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"tools": [{"version": "2.9.100-ubuntu-amd64"}]}`)),
+	}
+	resp.Header.Set("Content-Type", "application/json")
+
+	gomock.InOrder(
+		apiCaller.EXPECT().BestFacadeVersion("ModelUpgrader").Return(1),
+		apiCaller.EXPECT().HTTPClient().Return(&httprequest.Client{Doer: doer}, nil),
+		apiCaller.EXPECT().Context().Return(ctx),
+		doer.EXPECT().Do(req).Return(resp, nil),
+	)
+
+	client := modelupgrader.NewClient(apiCaller)
+
+	result, err := client.UploadSignedTools(
+		nil, version.MustParseBinary("2.9.100-ubuntu-amd64"), []byte("signature"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, coretools.List{
+		{Version: version.MustParseBinary("2.9.100-ubuntu-amd64")},
+	})
+}
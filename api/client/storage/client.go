@@ -314,3 +314,112 @@ func (c *Client) Import(
 	}
 	return names.ParseStorageTag(results.Results[0].Result.StorageTag)
 }
+
+// Resize grows the volume backing the specified storage instance to the
+// given size, in MiB.
+func (c *Client) Resize(storageId string, size uint64) error {
+	if c.facade.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("resizing storage on this controller")
+	}
+	args := params.ResizeStorageParams{
+		Storage: []params.ResizeStorageParam{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Resize", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CreateSnapshot takes a snapshot with the given name of the volume
+// backing the specified storage instance.
+func (c *Client) CreateSnapshot(storageId, name string) (params.StorageSnapshot, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return params.StorageSnapshot{}, errors.NotSupportedf("storage snapshots on this controller")
+	}
+	args := params.StorageSnapshotParams{
+		Snapshots: []params.StorageSnapshotParam{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Name:       name,
+		}},
+	}
+	var results params.StorageSnapshotResults
+	if err := c.facade.FacadeCall("CreateSnapshot", args, &results); err != nil {
+		return params.StorageSnapshot{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.StorageSnapshot{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.StorageSnapshot{}, err
+	}
+	return *results.Results[0].Result, nil
+}
+
+// ListSnapshots returns the snapshots taken of the volumes backing the
+// specified storage instances.
+func (c *Client) ListSnapshots(storageIds []string) ([]params.StorageSnapshotsResult, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("storage snapshots on this controller")
+	}
+	args := params.Entities{Entities: make([]params.Entity, len(storageIds))}
+	for i, id := range storageIds {
+		args.Entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	var results params.StorageSnapshotsResults
+	if err := c.facade.FacadeCall("ListSnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(storageIds) {
+		return nil, errors.Errorf("expected %d results, got %d", len(storageIds), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// ExportSnapshot creates a new volume, not managed by Juju, from the named
+// snapshot of the volume backing the specified storage instance, returning
+// the provider's identifier for the new volume.
+func (c *Client) ExportSnapshot(storageId, name string) (string, error) {
+	if c.facade.BestAPIVersion() < 7 {
+		return "", errors.NotSupportedf("storage snapshots on this controller")
+	}
+	args := params.StorageSnapshotParams{
+		Snapshots: []params.StorageSnapshotParam{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Name:       name,
+		}},
+	}
+	var results params.ExportStorageSnapshotResults
+	if err := c.facade.FacadeCall("ExportSnapshot", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].VolumeId, nil
+}
+
+// RestoreSnapshot replaces the contents of the volume backing the
+// specified storage instance with the named snapshot of it.
+func (c *Client) RestoreSnapshot(storageId, name string) error {
+	if c.facade.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("storage snapshots on this controller")
+	}
+	args := params.StorageSnapshotParams{
+		Snapshots: []params.StorageSnapshotParam{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Name:       name,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RestoreSnapshot", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -793,3 +793,150 @@ func (s *storageMockSuite) TestUpdatePoolFacadeCallError(c *gc.C) {
 	err := storageClient.UpdatePool("", "", nil)
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 }
+
+func (s *storageMockSuite) TestResize(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.ResizeStorageParams{Storage: []params.ResizeStorageParam{{
+		StorageTag: "storage-data-0",
+		Size:       2048,
+	}}}
+	result := new(params.ErrorResults)
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("Resize", args, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.Resize("data/0", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageMockSuite) TestResizeNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(6)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.Resize("data/0", 2048)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageMockSuite) TestCreateSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}}}
+	result := new(params.StorageSnapshotResults)
+	results := params.StorageSnapshotResults{Results: []params.StorageSnapshotResult{{
+		Result: &params.StorageSnapshot{StorageTag: "storage-data-0", Name: "snap-1"},
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("CreateSnapshot", args, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	snapshot, err := storageClient.CreateSnapshot("data/0", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot, jc.DeepEquals, params.StorageSnapshot{StorageTag: "storage-data-0", Name: "snap-1"})
+}
+
+func (s *storageMockSuite) TestListSnapshots(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.Entities{Entities: []params.Entity{{Tag: "storage-data-0"}}}
+	result := new(params.StorageSnapshotsResults)
+	results := params.StorageSnapshotsResults{Results: []params.StorageSnapshotsResult{{
+		Result: []params.StorageSnapshot{{StorageTag: "storage-data-0", Name: "snap-1", Ready: true}},
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("ListSnapshots", args, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	found, err := storageClient.ListSnapshots([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, results.Results)
+}
+
+func (s *storageMockSuite) TestExportSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}}}
+	result := new(params.ExportStorageSnapshotResults)
+	results := params.ExportStorageSnapshotResults{Results: []params.ExportStorageSnapshotResult{{
+		VolumeId: "data-0-snap-1",
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("ExportSnapshot", args, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	volumeId, err := storageClient.ExportSnapshot("data/0", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeId, gc.Equals, "data-0-snap-1")
+}
+
+func (s *storageMockSuite) TestExportSnapshotError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	result := new(params.ExportStorageSnapshotResults)
+	results := params.ExportStorageSnapshotResults{Results: []params.ExportStorageSnapshotResult{{
+		Error: &params.Error{Message: "nope"},
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("ExportSnapshot", gomock.Any(), result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	_, err := storageClient.ExportSnapshot("data/0", "snap-1")
+	c.Assert(err, gc.ErrorMatches, "nope")
+}
+
+func (s *storageMockSuite) TestRestoreSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	args := params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}}}
+	result := new(params.ErrorResults)
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("RestoreSnapshot", args, result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.RestoreSnapshot("data/0", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageMockSuite) TestRestoreSnapshotError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	result := new(params.ErrorResults)
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: "nope"},
+	}}}
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(7)
+	mockFacadeCaller.EXPECT().FacadeCall("RestoreSnapshot", gomock.Any(), result).SetArg(2, results).Return(nil)
+
+	storageClient := storage.NewClientFromCaller(mockFacadeCaller)
+	err := storageClient.RestoreSnapshot("data/0", "snap-1")
+	c.Assert(err, gc.ErrorMatches, "nope")
+}
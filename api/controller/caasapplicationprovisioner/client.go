@@ -148,6 +148,9 @@ type ProvisioningInfo struct {
 	CharmModifiedVersion int
 	CharmURL             *charm.URL
 	Trust                bool
+	Autoscaling          string
+	MaxUnavailable       string
+	SpreadZones          bool
 	Scale                int
 }
 
@@ -184,6 +187,9 @@ func (c *Client) ProvisioningInfo(applicationName string) (ProvisioningInfo, err
 		ImageDetails:         params.ConvertDockerImageInfo(r.ImageRepo),
 		CharmModifiedVersion: r.CharmModifiedVersion,
 		Trust:                r.Trust,
+		Autoscaling:          r.Autoscaling,
+		MaxUnavailable:       r.MaxUnavailable,
+		SpreadZones:          r.SpreadZones,
 		Scale:                r.Scale,
 	}
 	for _, fs := range r.Filesystems {
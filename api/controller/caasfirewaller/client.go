@@ -40,6 +40,7 @@ func NewClientLegacy(caller base.APICaller) *Client {
 // ClientSidecar allows access to the CAAS firewaller API endpoint for sidecar applications.
 type ClientSidecar struct {
 	*Client
+	*common.ModelWatcher
 }
 
 // NewClientSidecar returns a client used to access the CAAS unit provisioner API.
@@ -49,6 +50,7 @@ func NewClientSidecar(caller base.APICaller) *ClientSidecar {
 	charmInfoClient := charmscommon.NewCharmInfoClient(facadeCaller)
 	appCharmInfoClient := charmscommon.NewApplicationCharmInfoClient(facadeCaller)
 	return &ClientSidecar{
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
 		Client: &Client{
 			facade:                     facadeCaller,
 			CharmInfoClient:            charmInfoClient,
@@ -112,6 +114,67 @@ func (c *ClientSidecar) GetOpenedPorts(appName string) (network.GroupedPortRange
 	return out, nil
 }
 
+// WatchApplicationRelations returns a StringsWatcher that notifies of
+// changes to the lifecycles of relations involving the specified application.
+func (c *ClientSidecar) WatchApplicationRelations(appName string) (watcher.StringsWatcher, error) {
+	appTag, err := applicationTag(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.StringsWatchResults
+	if err := c.facade.FacadeCall("WatchApplicationRelations", entities(appTag), &results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if err := result.Error; err != nil {
+		return nil, maybeNotFound(err)
+	}
+	w := apiwatcher.NewStringsWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// RelatedApplication is an application related to another on one of
+// its endpoints.
+type RelatedApplication struct {
+	// Name is the name of the related application.
+	Name string
+
+	// CrossModel is true when the related application is in another
+	// model, and so connects from outside the cluster.
+	CrossModel bool
+}
+
+// RelatedApplications returns the applications related to the specified
+// application, keyed by the application's endpoint name.
+func (c *ClientSidecar) RelatedApplications(appName string) (map[string][]RelatedApplication, error) {
+	appTag, err := applicationTag(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.ApplicationRelatedEndpointsResults
+	if err := c.facade.FacadeCall("ApplicationRelatedEndpoints", entities(appTag), &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if err := result.Error; err != nil {
+		return nil, maybeNotFound(err)
+	}
+	out := make(map[string][]RelatedApplication)
+	for _, ep := range result.Endpoints {
+		out[ep.Endpoint] = append(out[ep.Endpoint], RelatedApplication{
+			Name:       ep.RelatedApplication,
+			CrossModel: ep.CrossModel,
+		})
+	}
+	return out, nil
+}
+
 func applicationTag(application string) (names.ApplicationTag, error) {
 	if !names.IsValidApplication(application) {
 		return names.ApplicationTag{}, errors.NotValidf("application name %q", application)
@@ -127,6 +127,77 @@ func (s *firewallerSidecarSuite) TestGetOpenedPorts(c *gc.C) {
 	})
 }
 
+func (s *firewallerSidecarSuite) TestWatchApplicationRelations(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, s.objType)
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WatchApplicationRelations")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+		*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+			Results: []params.StringsWatchResult{{
+				Error: &params.Error{Message: "FAIL"},
+			}},
+		}
+		return nil
+	})
+
+	client := caasfirewaller.NewClientSidecar(apiCaller)
+	watcher, err := client.WatchApplicationRelations("gitlab")
+	c.Assert(watcher, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *firewallerSidecarSuite) TestRelatedApplications(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, s.objType)
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ApplicationRelatedEndpoints")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "application-gitlab"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ApplicationRelatedEndpointsResults{})
+		*(result.(*params.ApplicationRelatedEndpointsResults)) = params.ApplicationRelatedEndpointsResults{
+			Results: []params.ApplicationRelatedEndpointsResult{{
+				Endpoints: []params.ApplicationRelatedEndpoint{
+					{Endpoint: "db", RelatedApplication: "mysql", RelatedEndpointName: "server"},
+					{Endpoint: "db", RelatedApplication: "mariadb", RelatedEndpointName: "server"},
+					{Endpoint: "website", RelatedApplication: "haproxy", RelatedEndpointName: "reverseproxy"},
+					{Endpoint: "website", RelatedApplication: "remote-proxy", RelatedEndpointName: "reverseproxy", CrossModel: true},
+				},
+			}},
+		}
+		return nil
+	})
+
+	client := caasfirewaller.NewClientSidecar(apiCaller)
+	result, err := client.RelatedApplications("gitlab")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, map[string][]caasfirewaller.RelatedApplication{
+		"db":      {{Name: "mysql"}, {Name: "mariadb"}},
+		"website": {{Name: "haproxy"}, {Name: "remote-proxy", CrossModel: true}},
+	})
+}
+
+func (s *firewallerSidecarSuite) TestRelatedApplicationsNotFound(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ApplicationRelatedEndpointsResults)) = params.ApplicationRelatedEndpointsResults{
+			Results: []params.ApplicationRelatedEndpointsResult{{
+				Error: &params.Error{Code: params.CodeNotFound, Message: "application gitlab not found"},
+			}},
+		}
+		return nil
+	})
+
+	client := caasfirewaller.NewClientSidecar(apiCaller)
+	_, err := client.RelatedApplications("gitlab")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *firewallerBaseSuite) TestIsExposed(c *gc.C) {
 	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
 		c.Check(objType, gc.Equals, s.objType)
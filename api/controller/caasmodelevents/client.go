@@ -0,0 +1,41 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/rpc/params"
+)
+
+// Client allows access to the CAAS model events API endpoint.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient returns a client used to access the CAAS model events API.
+func NewClient(caller base.APICaller) (*Client, error) {
+	_, isModel := caller.ModelTag()
+	if !isModel {
+		return nil, errors.New("expected model specific API connection")
+	}
+	return &Client{
+		facade: base.NewFacadeCaller(caller, "CAASModelEvents"),
+	}, nil
+}
+
+// RecordUnitEvents adds the specified events to the status history of
+// their units.
+func (c *Client) RecordUnitEvents(events []params.CAASUnitEvent) error {
+	var results params.ErrorResults
+	args := params.CAASUnitEvents{Events: events}
+	if err := c.facade.FacadeCall("RecordUnitEvents", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(events) {
+		return errors.Errorf("expected %d results, got %d", len(events), len(results.Results))
+	}
+	return results.Combine()
+}
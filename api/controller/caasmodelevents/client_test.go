@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controller/caasmodelevents"
+	"github.com/juju/juju/rpc/params"
+)
+
+type caasmodeleventsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&caasmodeleventsSuite{})
+
+func newClient(f basetesting.APICallerFunc) (*caasmodelevents.Client, error) {
+	return caasmodelevents.NewClient(basetesting.BestVersionCaller{APICallerFunc: f, BestVersion: 1})
+}
+
+func (s *caasmodeleventsSuite) TestRecordUnitEvents(c *gc.C) {
+	events := []params.CAASUnitEvent{{
+		UnitTag: "unit-mariadb-0",
+		Type:    "Warning",
+		Reason:  "BackOff",
+		Message: "Back-off restarting failed container",
+		Count:   2,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+	client, err := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "CAASModelEvents")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "RecordUnitEvents")
+		c.Check(arg, jc.DeepEquals, params.CAASUnitEvents{Events: events})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = client.RecordUnitEvents(events)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *caasmodeleventsSuite) TestRecordUnitEventsError(c *gc.C) {
+	client, err := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = client.RecordUnitEvents([]params.CAASUnitEvent{{UnitTag: "unit-mariadb-0"}})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/names/v5"
 
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/rpc/params"
 )
@@ -58,3 +59,38 @@ func (s *Application) ExposeInfo() (bool, map[string]params.ExposedEndpoint, err
 	}
 	return result.Exposed, result.ExposedEndpoints, nil
 }
+
+// EgressRules returns the egress rules set in the application's config.
+func (s *Application) EgressRules() (firewall.EgressRules, error) {
+	if s.st.facade.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("egress rules")
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetApplicationEgressRules", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, errors.NewNotFound(result.Error, "")
+		}
+		return nil, result.Error
+	}
+	return firewall.ParseEgressRules(result.Result)
+}
+
+// WatchEgressRules returns a watcher for observing changes to the
+// application's egress rules.
+func (s *Application) WatchEgressRules() (watcher.NotifyWatcher, error) {
+	if s.st.facade.BestAPIVersion() < 8 {
+		return nil, errors.NotSupportedf("watching egress rules")
+	}
+	return common.Watch(s.st.facade, "WatchApplicationEgressRules", s.tag)
+}
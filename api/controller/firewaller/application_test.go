@@ -7,9 +7,12 @@ import (
 	"github.com/juju/names/v5"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api/controller/firewaller"
+	"github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/watcher/watchertest"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -93,3 +96,41 @@ func (s *applicationSuite) TestExposeInfo(c *gc.C) {
 	c.Assert(isExposed, jc.IsFalse)
 	c.Assert(exposedEndpoints, gc.HasLen, 0)
 }
+
+func (s *applicationSuite) setEgressRules(c *gc.C, rules string) {
+	schema := environschema.Fields{
+		"egress": environschema.Attr{Type: environschema.Tstring},
+	}
+	err := s.application.UpdateApplicationConfig(config.ConfigAttributes{"egress": rules}, nil, schema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *applicationSuite) TestEgressRules(c *gc.C) {
+	rules, err := s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+
+	s.setEgressRules(c, "443/tcp;53/udp to 10.0.0.2/32")
+
+	rules, err = s.apiApplication.EgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp")),
+		firewall.NewEgressRule(network.MustParsePortRange("53/udp"), "10.0.0.2/32"),
+	})
+}
+
+func (s *applicationSuite) TestWatchEgressRules(c *gc.C) {
+	s.WaitForModelWatchersIdle(c, s.Model.UUID())
+
+	w, err := s.apiApplication.WatchEgressRules()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	s.setEgressRules(c, "443/tcp")
+	wc.AssertOneChange()
+}
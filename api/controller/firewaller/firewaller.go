@@ -270,6 +270,23 @@ func (c *Client) SetRelationStatus(relationKey string, status relation.Status, m
 	return results.OneError()
 }
 
+// RecordFirewallDrift records the result of a firewall drift check in
+// the model's status history.
+func (c *Client) RecordFirewallDrift(report params.FirewallDriftReport) error {
+	if c.facade.BestAPIVersion() < 8 {
+		return errors.NotSupportedf("recording firewall drift")
+	}
+	var result params.ErrorResult
+	err := c.facade.FacadeCall("RecordFirewallDrift", report, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // AllSpaceInfos returns the details about the known spaces and their
 // associated subnets.
 func (c *Client) AllSpaceInfos() (network.SpaceInfos, error) {
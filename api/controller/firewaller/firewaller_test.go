@@ -4,6 +4,7 @@
 package firewaller_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names/v5"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
@@ -315,3 +316,52 @@ func (s *firewallerSuite) TestAllSpaceInfos(c *gc.C) {
 	c.Check(callCount, gc.Equals, 1)
 	c.Assert(got, gc.DeepEquals, expSpaceInfos)
 }
+
+func (s *firewallerSuite) TestRecordFirewallDrift(c *gc.C) {
+	report := params.FirewallDriftReport{
+		Mode: "report",
+		Drift: []params.FirewallDrift{{
+			Target: "model",
+			Unexpected: []params.IngressRule{{
+				PortRange:   params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+				SourceCIDRs: []string{"0.0.0.0/0"},
+			}},
+		}},
+	}
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Firewaller")
+			c.Check(version, gc.Equals, 8)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "RecordFirewallDrift")
+			c.Assert(arg, jc.DeepEquals, report)
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResult{})
+			*(result.(*params.ErrorResult)) = params.ErrorResult{
+				Error: &params.Error{Message: "FAIL"},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 8,
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.RecordFirewallDrift(report)
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestRecordFirewallDriftNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 7,
+	}
+	client, err := firewaller.NewClient(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.RecordFirewallDrift(params.FirewallDriftReport{Mode: "report"})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
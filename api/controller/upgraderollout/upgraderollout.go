@@ -0,0 +1,52 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout
+
+import (
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/rpc/params"
+)
+
+const upgradeRolloutFacade = "UpgradeRollout"
+
+// API provides access to the UpgradeRollout API facade.
+type API struct {
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side UpgradeRollout facade.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, upgradeRolloutFacade)
+	return &API{facade: facadeCaller}
+}
+
+// AdvanceUpgradeRollout calls the server-side AdvanceUpgradeRollout method,
+// returning whether the staged upgrade is still running.
+func (api *API) AdvanceUpgradeRollout() (bool, error) {
+	var result params.BoolResult
+	err := api.facade.FacadeCall("AdvanceUpgradeRollout", nil, &result)
+	if err != nil {
+		return false, err
+	}
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// WatchUpgradeRollout calls the server-side WatchUpgradeRollout method.
+func (api *API) WatchUpgradeRollout() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := api.facade.FacadeCall("WatchUpgradeRollout", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(api.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controller/upgraderollout"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type UpgradeRolloutSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&UpgradeRolloutSuite{})
+
+func (s *UpgradeRolloutSuite) newAPI(c *gc.C, method string, results interface{}, err error) *upgraderollout.API {
+	caller := apitesting.APICallChecker(c, apitesting.APICall{
+		Facade:        "UpgradeRollout",
+		VersionIsZero: true,
+		IdIsEmpty:     true,
+		Method:        method,
+		Results:       results,
+		Error:         err,
+	})
+	return upgraderollout.NewAPI(caller)
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceUpgradeRollout(c *gc.C) {
+	api := s.newAPI(c, "AdvanceUpgradeRollout", params.BoolResult{Result: true}, nil)
+	running, err := api.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, jc.IsTrue)
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceUpgradeRolloutError(c *gc.C) {
+	api := s.newAPI(c, "AdvanceUpgradeRollout", nil, errors.New("boom"))
+	_, err := api.AdvanceUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceUpgradeRolloutResultError(c *gc.C) {
+	api := s.newAPI(c, "AdvanceUpgradeRollout", params.BoolResult{
+		Error: &params.Error{Message: "Server Error"},
+	}, nil)
+	_, err := api.AdvanceUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "Server Error")
+}
+
+func (s *UpgradeRolloutSuite) TestWatchUpgradeRolloutFailFacadeCall(c *gc.C) {
+	api := s.newAPI(c, "WatchUpgradeRollout", nil, errors.New("client error!"))
+	w, err := api.WatchUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "client error!")
+	c.Assert(w, gc.IsNil)
+}
+
+func (s *UpgradeRolloutSuite) TestWatchUpgradeRolloutFailFacadeResult(c *gc.C) {
+	api := s.newAPI(c, "WatchUpgradeRollout", params.NotifyWatchResult{
+		Error: &params.Error{Message: "Server Error"},
+	}, nil)
+	w, err := api.WatchUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "Server Error")
+	c.Assert(w, gc.IsNil)
+}
@@ -29,7 +29,7 @@ var facadeVersions = facades.FacadeVersions{
 	"AllModelWatcher":              {4},
 	"AllWatcher":                   {3},
 	"Annotations":                  {2},
-	"Application":                  {15, 16, 17, 18, 19, 20},
+	"Application":                  {15, 16, 17, 18, 19, 20, 21},
 	"ApplicationOffers":            {4, 5},
 	"ApplicationScaler":            {1},
 	"Backups":                      {3},
@@ -40,8 +40,9 @@ var facadeVersions = facades.FacadeVersions{
 	"CAASApplication":              {1},
 	"CAASApplicationProvisioner":   {1},
 	"CAASModelConfigManager":       {1},
+	"CAASModelEvents":              {1},
 	"CAASFirewaller":               {1},
-	"CAASFirewallerSidecar":        {1},
+	"CAASFirewallerSidecar":        {1, 2},
 	"CAASModelOperator":            {1},
 	"CAASOperator":                 {1},
 	"CAASOperatorProvisioner":      {1},
@@ -55,7 +56,7 @@ var facadeVersions = facades.FacadeVersions{
 	"Cloud":                        {7},
 	"Controller":                   {11, 12},
 	"CredentialManager":            {1},
-	"CredentialValidator":          {2},
+	"CredentialValidator":          {2, 3},
 	"CrossController":              {1},
 	"CrossModelRelations":          {2, 3},
 	"CrossModelSecrets":            {1},
@@ -66,7 +67,7 @@ var facadeVersions = facades.FacadeVersions{
 	"ExternalControllerUpdater":    {1},
 	"FanConfigurer":                {1},
 	"FilesystemAttachmentsWatcher": {2},
-	"Firewaller":                   {7},
+	"Firewaller":                   {7, 8},
 	"HighAvailability":             {2},
 	"HostKeyReporter":              {1},
 	"ImageMetadata":                {3},
@@ -80,7 +81,7 @@ var facadeVersions = facades.FacadeVersions{
 	"LogForwarding":                {1},
 	"Logger":                       {1},
 	"MachineActions":               {1},
-	"MachineManager":               {9, 10},
+	"MachineManager":               {9, 10, 11},
 	"MachineUndertaker":            {1},
 	"Machiner":                     {5},
 	"MeterStatus":                  {2},
@@ -96,7 +97,7 @@ var facadeVersions = facades.FacadeVersions{
 	"ModelGeneration":              {4},
 	"ModelManager":                 {9, 10},
 	"ModelSummaryWatcher":          {1},
-	"ModelUpgrader":                {1},
+	"ModelUpgrader":                {1, 2},
 	"NotifyWatcher":                {1},
 	"OfferStatusWatcher":           {1},
 	"Payloads":                     {1},
@@ -126,14 +127,15 @@ var facadeVersions = facades.FacadeVersions{
 	"Spaces":                       {6},
 	"SSHClient":                    {4},
 	"StatusHistory":                {2},
-	"Storage":                      {6},
+	"Storage":                      {6, 7},
 	"StorageProvisioner":           {4},
 	"StringsWatcher":               {1},
 	"Subnets":                      {5},
 	"Undertaker":                   {1},
 	"UnitAssigner":                 {1},
-	"Uniter":                       {18, 19},
+	"Uniter":                       {18, 19, 20},
 	"Upgrader":                     {1},
+	"UpgradeRollout":               {1},
 	"UpgradeSeries":                {3, 4},
 	"UpgradeSteps":                 {2},
 	"UserManager":                  {3},
@@ -78,6 +78,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/caasapplicationprovisioner"
 	"github.com/juju/juju/apiserver/facades/controller/caasfirewaller"
 	"github.com/juju/juju/apiserver/facades/controller/caasmodelconfigmanager"
+	"github.com/juju/juju/apiserver/facades/controller/caasmodelevents"
 	"github.com/juju/juju/apiserver/facades/controller/caasmodeloperator"
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorprovisioner"
 	"github.com/juju/juju/apiserver/facades/controller/caasoperatorupgrader"
@@ -104,6 +105,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/singular"
 	"github.com/juju/juju/apiserver/facades/controller/statushistory"
 	"github.com/juju/juju/apiserver/facades/controller/undertaker"
+	"github.com/juju/juju/apiserver/facades/controller/upgraderollout"
 	"github.com/juju/juju/apiserver/facades/controller/usersecrets"
 	"github.com/juju/juju/apiserver/facades/controller/usersecretsdrain"
 	"github.com/juju/juju/core/facades"
@@ -209,6 +211,7 @@ func AllFacades() *facade.Registry {
 	caasoperator.Register(registry)
 	caasmodeloperator.Register(registry)
 	caasmodelconfigmanager.Register(registry)
+	caasmodelevents.Register(registry)
 	caasoperatorprovisioner.Register(registry)
 	caasoperatorupgrader.Register(registry)
 	caasunitprovisioner.Register(registry)
@@ -280,6 +283,7 @@ func AllFacades() *facade.Registry {
 	unitassigner.Register(registry)
 	uniter.Register(registry)
 	upgrader.Register(registry)
+	upgraderollout.Register(registry)
 	upgradeseries.Register(registry)
 	upgradesteps.Register(registry)
 	usermanager.Register(registry)
@@ -42,6 +42,7 @@ import (
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/stateauthenticator"
 	"github.com/juju/juju/apiserver/websocket"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
@@ -218,6 +219,11 @@ type ServerConfig struct {
 	// CharmhubHTTPClient is the HTTP client used for Charmhub API requests.
 	CharmhubHTTPClient facade.HTTPClient
 
+	// CharmhubCache is shared by the charmhub clients built by facades,
+	// so that repeated metadata requests and downloads can be served
+	// locally. It may be nil, in which case nothing is cached.
+	CharmhubCache *charmhub.Cache
+
 	// DBGetter supplies sql.DB references on request, for named databases.
 	DBGetter coredatabase.DBGetter
 }
@@ -317,6 +323,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		controllerConfig:    controllerConfig,
 		logger:              loggo.GetLogger("juju.apiserver"),
 		charmhubHTTPClient:  cfg.CharmhubHTTPClient,
+		charmhubCache:       cfg.CharmhubCache,
 		dbGetter:            cfg.DBGetter,
 	})
 	if err != nil {
@@ -769,8 +776,9 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 	}, "charms")
 
 	modelToolsUploadHandler := srv.monitoredHandler(&toolsUploadHandler{
-		ctxt:          httpCtxt,
-		stateAuthFunc: httpCtxt.stateForRequestAuthenticatedUser,
+		ctxt:             httpCtxt,
+		stateAuthFunc:    httpCtxt.stateForRequestAuthenticatedUser,
+		verifySignatures: true,
 	}, "tools")
 	modelToolsUploadAuthorizer := tagKindAuthorizer{names.UserTagKind}
 	modelToolsDownloadHandler := srv.monitoredHandler(newToolsDownloadHandler(httpCtxt), "tools")
@@ -811,7 +819,7 @@ func (srv *Server) endpoints() ([]apihttp.Endpoint, error) {
 			if err != nil {
 				return nil, nil, errors.Trace(err)
 			}
-			opener, err := resource.NewResourceOpener(st.State, srv.getResourceDownloadLimiter, tag.Id())
+			opener, err := resource.NewResourceOpener(st.State, srv.shared.charmhubCache, srv.getResourceDownloadLimiter, tag.Id())
 			if err != nil {
 				return nil, nil, errors.Trace(err)
 			}
@@ -22,7 +22,8 @@ type ConfigModel interface {
 }
 
 // CharmhubClient creates a new charmhub Client based on this model's config.
-func CharmhubClient(mg ModelGetter, httpClient charmhub.HTTPClient, logger loggo.Logger) (*charmhub.Client, error) {
+// If cache is not nil, the client serves repeated requests from it.
+func CharmhubClient(mg ModelGetter, httpClient charmhub.HTTPClient, cache *charmhub.Cache, logger loggo.Logger) (*charmhub.Client, error) {
 	model, err := mg.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -36,6 +37,7 @@ func CharmhubClient(mg ModelGetter, httpClient charmhub.HTTPClient, logger loggo
 	client, err := charmhub.NewClient(charmhub.Config{
 		URL:        url,
 		HTTPClient: httpClient,
+		Cache:      cache,
 		Logger:     logger,
 	})
 	if err != nil {
@@ -122,6 +122,16 @@ func (t *ToolsGetter) Tools(args params.Entities) (params.ToolsResults, error) {
 	return result, nil
 }
 
+// AgentTools returns the agent binaries of the given version which suit
+// the agent with the given tag.
+func (t *ToolsGetter) AgentTools(tag names.Tag, agentVersion version.Number) (coretools.List, error) {
+	canRead, err := t.getCanRead()
+	if err != nil {
+		return nil, err
+	}
+	return t.oneAgentTools(canRead, tag, agentVersion)
+}
+
 func (t *ToolsGetter) getGlobalAgentVersion() (version.Number, error) {
 	// Get the Agent Version requested in the Model Config
 	nothing := version.Number{}
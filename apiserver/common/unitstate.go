@@ -127,6 +127,7 @@ func (u *UnitStateAPI) State(args params.Entities) (params.UnitStateResults, err
 		res[i].StorageState, _ = unitState.StorageState()
 		res[i].SecretState, _ = unitState.SecretState()
 		res[i].MeterStatusState, _ = unitState.MeterStatusState()
+		res[i].HookHistory, _ = unitState.HookHistory()
 	}
 
 	return params.UnitStateResults{Results: res}, nil
@@ -183,6 +184,9 @@ func (u *UnitStateAPI) SetState(args params.SetUnitStateArgs) (params.ErrorResul
 		if arg.MeterStatusState != nil {
 			unitState.SetMeterStatusState(*arg.MeterStatusState)
 		}
+		if arg.HookHistory != nil {
+			unitState.SetHookHistory(*arg.HookHistory)
+		}
 
 		ops := unit.SetStateOperation(
 			unitState,
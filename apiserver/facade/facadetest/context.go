@@ -7,6 +7,7 @@ import (
 	"github.com/juju/clock"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
 	"github.com/juju/juju/core/leadership"
@@ -36,6 +37,7 @@ type Context struct {
 	LeadershipReader_   leadership.Reader
 	SingularClaimer_    lease.Claimer
 	CharmhubHTTPClient_ facade.HTTPClient
+	CharmhubCache_      *charmhub.Cache
 	ControllerDB_       coredatabase.TrackedDB
 	// Identity is not part of the facade.Context interface, but is instead
 	// used to make sure that the context objects are the same.
@@ -152,6 +154,11 @@ func (context Context) HTTPClient(purpose facade.HTTPClientPurpose) facade.HTTPC
 	}
 }
 
+// CharmhubCache implements facade.Context.
+func (context Context) CharmhubCache() *charmhub.Cache {
+	return context.CharmhubCache_
+}
+
 func (context Context) ControllerDB() (coredatabase.TrackedDB, error) {
 	return context.ControllerDB_, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/names/v5"
 
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
 	"github.com/juju/juju/core/leadership"
@@ -148,6 +149,10 @@ type Context interface {
 	// HTTPClient returns an HTTP client to use for the given purpose.
 	HTTPClient(purpose HTTPClientPurpose) HTTPClient
 
+	// CharmhubCache returns the controller-wide cache shared by charmhub
+	// clients, or nil if responses should not be cached.
+	CharmhubCache() *charmhub.Cache
+
 	// ControllerDB returns a TrackedDB reference for the controller database.
 	ControllerDB() (coredatabase.TrackedDB, error)
 }
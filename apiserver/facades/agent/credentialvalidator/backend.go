@@ -4,10 +4,13 @@
 package credentialvalidator
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/names/v5"
 
 	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 )
 
@@ -29,6 +32,10 @@ type Backend interface {
 
 	// WatchModelCredential returns a watcher that is keeping an eye on what cloud credential a model uses.
 	WatchModelCredential() (state.NotifyWatcher, error)
+
+	// SetClusterHealth records the health of the model's cluster API
+	// endpoints in the model status.
+	SetClusterHealth(ClusterHealth) error
 }
 
 func NewBackend(st StateAccessor) Backend {
@@ -98,6 +105,45 @@ func (b *backend) WatchModelCredential() (state.NotifyWatcher, error) {
 	return m.WatchModelCredential(), nil
 }
 
+// SetClusterHealth implements Backend.SetClusterHealth.
+func (b *backend) SetClusterHealth(health ClusterHealth) error {
+	m, err := b.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := m.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only a model which is otherwise available has its status changed,
+	// so that a suspended or busy model keeps its status.
+	_, reported := current.Data[status.ClusterEndpointKey]
+	if current.Status != status.Available && !(current.Status == status.Error && reported) {
+		return nil
+	}
+
+	info := status.StatusInfo{
+		Status: status.Available,
+		Data: map[string]interface{}{
+			status.ClusterEndpointKey: health.Endpoint,
+		},
+	}
+	if len(health.Unhealthy) > 0 {
+		info.Data[status.UnhealthyClusterEndpointsKey] = health.Unhealthy
+	}
+	if health.Error != "" {
+		info.Status = status.Error
+		info.Message = fmt.Sprintf("cluster unreachable: %s", health.Error)
+	}
+	// Health is reported periodically, so avoid filling the status
+	// history with identical entries.
+	if current.Status == info.Status && current.Message == info.Message &&
+		fmt.Sprint(current.Data) == fmt.Sprint(info.Data) {
+		return nil
+	}
+	return errors.Trace(m.SetStatus(info))
+}
+
 func (b *backend) cloudSupportsNoAuth(cloudName string) (bool, error) {
 	cloud, err := b.Cloud(cloudName)
 	if err != nil {
@@ -138,3 +184,16 @@ type ModelCredential struct {
 	// the model's credential is not set, this property will be set to 'false'.
 	Valid bool
 }
+
+// ClusterHealth describes the health of a model's cluster API endpoints.
+type ClusterHealth struct {
+	// Endpoint is the API endpoint the model's workers are using.
+	Endpoint string
+
+	// Unhealthy describes why each unhealthy endpoint failed its
+	// health check.
+	Unhealthy []string
+
+	// Error describes why no endpoint is healthy.
+	Error string
+}
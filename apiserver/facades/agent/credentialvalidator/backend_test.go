@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/credentialvalidator"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
@@ -110,6 +111,61 @@ func (s *BackendSuite) TestWatchModelCredentialErr(c *gc.C) {
 	s.state.CheckCallNames(c, "Model")
 }
 
+func (s *BackendSuite) TestSetClusterHealth(c *gc.C) {
+	err := s.backend.SetClusterHealth(credentialvalidator.ClusterHealth{
+		Endpoint:  "https://10.0.0.2:6443",
+		Unhealthy: []string{"https://10.0.0.1:6443: connection refused"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.state.CheckCallNames(c, "Model", "Status", "SetStatus")
+	c.Assert(s.state.aModel.status, jc.DeepEquals, status.StatusInfo{
+		Status: status.Available,
+		Data: map[string]interface{}{
+			"cluster-endpoint":            "https://10.0.0.2:6443",
+			"unhealthy-cluster-endpoints": []string{"https://10.0.0.1:6443: connection refused"},
+		},
+	})
+
+	// Reporting the same health again doesn't change the status.
+	s.state.ResetCalls()
+	err = s.backend.SetClusterHealth(credentialvalidator.ClusterHealth{
+		Endpoint:  "https://10.0.0.2:6443",
+		Unhealthy: []string{"https://10.0.0.1:6443: connection refused"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.state.CheckCallNames(c, "Model", "Status")
+}
+
+func (s *BackendSuite) TestSetClusterHealthUnreachable(c *gc.C) {
+	err := s.backend.SetClusterHealth(credentialvalidator.ClusterHealth{Error: "no healthy cluster API endpoint"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.state.aModel.status, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Error,
+		Message: "cluster unreachable: no healthy cluster API endpoint",
+		Data:    map[string]interface{}{"cluster-endpoint": ""},
+	})
+
+	// Once the cluster is reachable again, the model is available.
+	err = s.backend.SetClusterHealth(credentialvalidator.ClusterHealth{Endpoint: "https://10.0.0.1:6443"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.state.aModel.status, jc.DeepEquals, status.StatusInfo{
+		Status: status.Available,
+		Data:   map[string]interface{}{"cluster-endpoint": "https://10.0.0.1:6443"},
+	})
+}
+
+func (s *BackendSuite) TestSetClusterHealthKeepsSuspendedStatus(c *gc.C) {
+	suspended := status.StatusInfo{
+		Status:  status.Suspended,
+		Message: "suspended since cloud credential is not valid",
+	}
+	s.state.aModel.status = suspended
+	err := s.backend.SetClusterHealth(credentialvalidator.ClusterHealth{Error: "no healthy cluster API endpoint"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.state.CheckCallNames(c, "Model", "Status")
+	c.Assert(s.state.aModel.status, jc.DeepEquals, suspended)
+}
+
 func newMockState() *mockState {
 	b := &mockState{
 		Stub:        &testing.Stub{},
@@ -124,6 +180,7 @@ func newMockState() *mockState {
 		Stub:          b.Stub,
 		credentialTag: names.NewCloudCredentialTag("foo/bob/one"),
 		credentialSet: true,
+		status:        status.StatusInfo{Status: status.Available},
 	}
 	return b
 }
@@ -178,7 +235,8 @@ type mockModel struct {
 	credentialTag names.CloudCredentialTag
 	credentialSet bool
 
-	cloud string
+	cloud  string
+	status status.StatusInfo
 }
 
 func (m *mockModel) CloudCredentialTag() (names.CloudCredentialTag, bool) {
@@ -200,3 +258,14 @@ func (m *mockModel) WatchModelCredential() state.NotifyWatcher {
 	m.MethodCall(m, "WatchModelCredential")
 	return apiservertesting.NewFakeNotifyWatcher()
 }
+
+func (m *mockModel) Status() (status.StatusInfo, error) {
+	m.MethodCall(m, "Status")
+	return m.status, m.NextErr()
+}
+
+func (m *mockModel) SetStatus(info status.StatusInfo) error {
+	m.MethodCall(m, "SetStatus", info)
+	m.status = info
+	return m.NextErr()
+}
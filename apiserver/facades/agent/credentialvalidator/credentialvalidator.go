@@ -16,6 +16,16 @@ import (
 
 var logger = loggo.GetLogger("juju.api.credentialvalidator")
 
+// CredentialValidatorV3 defines the methods on version 3 facade for the
+// credentialvalidator API endpoint.
+type CredentialValidatorV3 interface {
+	InvalidateModelCredential(params.InvalidateCredentialArg) (params.ErrorResult, error)
+	ModelCredential() (params.ModelCredential, error)
+	ReportClusterHealth(params.ClusterHealthArg) (params.ErrorResult, error)
+	WatchCredential(params.Entity) (params.NotifyWatchResult, error)
+	WatchModelCredential() (params.NotifyWatchResult, error)
+}
+
 // CredentialValidatorV2 defines the methods on version 2 facade for the
 // credentialvalidator API endpoint.
 type CredentialValidatorV2 interface {
@@ -40,8 +50,14 @@ type CredentialValidatorAPI struct {
 	resources facade.Resources
 }
 
+// CredentialValidatorAPIV2 implements version 2 of the CredentialValidator API.
+type CredentialValidatorAPIV2 struct {
+	*CredentialValidatorAPI
+}
+
 var (
-	_ CredentialValidatorV2 = (*CredentialValidatorAPI)(nil)
+	_ CredentialValidatorV3 = (*CredentialValidatorAPI)(nil)
+	_ CredentialValidatorV2 = (*CredentialValidatorAPIV2)(nil)
 )
 
 func internalNewCredentialValidatorAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*CredentialValidatorAPI, error) {
@@ -124,3 +140,17 @@ func (api *CredentialValidatorAPI) WatchModelCredential() (params.NotifyWatchRes
 	}
 	return result, nil
 }
+
+// ReportClusterHealth records the health of the model's cluster API
+// endpoints in the model status.
+func (api *CredentialValidatorAPI) ReportClusterHealth(arg params.ClusterHealthArg) (params.ErrorResult, error) {
+	err := api.backend.SetClusterHealth(ClusterHealth{
+		Endpoint:  arg.Endpoint,
+		Unhealthy: arg.Unhealthy,
+		Error:     arg.Error,
+	})
+	return params.ErrorResult{Error: apiservererrors.ServerError(err)}, nil
+}
+
+// ReportClusterHealth isn't on the v2 API.
+func (*CredentialValidatorAPIV2) ReportClusterHealth(_, _ struct{}) {}
@@ -120,6 +120,32 @@ func (s *CredentialValidatorSuite) TestWatchModelCredentialError(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
 
+func (s *CredentialValidatorSuite) TestReportClusterHealth(c *gc.C) {
+	result, err := s.api.ReportClusterHealth(params.ClusterHealthArg{
+		Endpoint:  "https://10.0.0.2:6443",
+		Unhealthy: []string{"https://10.0.0.1:6443: connection refused"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResult{})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"SetClusterHealth", []interface{}{credentialvalidator.ClusterHealth{
+			Endpoint:  "https://10.0.0.2:6443",
+			Unhealthy: []string{"https://10.0.0.1:6443: connection refused"},
+		}}},
+	})
+}
+
+func (s *CredentialValidatorSuite) TestReportClusterHealthError(c *gc.C) {
+	expected := errors.New("boom")
+	s.backend.SetErrors(expected)
+	result, err := s.api.ReportClusterHealth(params.ClusterHealthArg{Error: "no healthy cluster API endpoint"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResult{Error: apiservererrors.ServerError(expected)})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"SetClusterHealth", []interface{}{credentialvalidator.ClusterHealth{Error: "no healthy cluster API endpoint"}}},
+	})
+}
+
 // modelUUID is the model tag we're using in the tests.
 var modelUUID = "01234567-89ab-cdef-0123-456789abcdef"
 
@@ -181,3 +207,8 @@ func (b *testBackend) WatchModelCredential() (state.NotifyWatcher, error) {
 	}
 	return apiservertesting.NewFakeNotifyWatcher(), nil
 }
+
+func (b *testBackend) SetClusterHealth(health credentialvalidator.ClusterHealth) error {
+	b.AddCall("SetClusterHealth", health)
+	return b.NextErr()
+}
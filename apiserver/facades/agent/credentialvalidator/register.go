@@ -12,10 +12,22 @@ import (
 // Register is called to expose a package of facades onto a given registry.
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("CredentialValidator", 2, func(ctx facade.Context) (facade.Facade, error) {
-		return newCredentialValidatorAPI(ctx) // adds WatchModelCredential
+		return newCredentialValidatorAPIV2(ctx) // adds WatchModelCredential
+	}, reflect.TypeOf((*CredentialValidatorAPIV2)(nil)))
+	registry.MustRegister("CredentialValidator", 3, func(ctx facade.Context) (facade.Facade, error) {
+		return newCredentialValidatorAPI(ctx) // adds ReportClusterHealth
 	}, reflect.TypeOf((*CredentialValidatorAPI)(nil)))
 }
 
+// newCredentialValidatorAPIV2 creates a new CredentialValidator v2 API endpoint on server-side.
+func newCredentialValidatorAPIV2(ctx facade.Context) (*CredentialValidatorAPIV2, error) {
+	api, err := newCredentialValidatorAPI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialValidatorAPIV2{api}, nil
+}
+
 // newCredentialValidatorAPI creates a new CredentialValidator API endpoint on server-side.
 func newCredentialValidatorAPI(ctx facade.Context) (*CredentialValidatorAPI, error) {
 	return internalNewCredentialValidatorAPI(NewBackend(NewStateShim(ctx.State())), ctx.Resources(), ctx.Auth())
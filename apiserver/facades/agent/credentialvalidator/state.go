@@ -7,6 +7,7 @@ import (
 	"github.com/juju/names/v5"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 )
 
@@ -16,6 +17,8 @@ type ModelAccessor interface {
 	ModelTag() names.ModelTag
 	CloudName() string
 	WatchModelCredential() state.NotifyWatcher
+	Status() (status.StatusInfo, error)
+	SetStatus(status.StatusInfo) error
 }
 
 // StateAccessor exposes State methods needed by credential validator.
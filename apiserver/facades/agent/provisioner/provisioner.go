@@ -327,6 +327,7 @@ func (api *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerCo
 	switch args.Type {
 	case instance.LXD:
 		cfg[config.LXDSnapChannel] = mConfig.LXDSnapChannel()
+		cfg[config.PreferredAddressFamilyKey] = string(mConfig.PreferredAddressFamily())
 		// TODO(jam): DefaultMTU needs to be handled here
 	}
 
@@ -0,0 +1,58 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v5"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/rpc/params"
+)
+
+// HookTimeouts returns the hook timeouts set in the application config
+// of each given unit, or an empty string when none are set.
+func (u *UniterAPI) HookTimeouts(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		timeouts, err := u.hookTimeouts(tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = timeouts
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) hookTimeouts(tag names.UnitTag) (string, error) {
+	appName, err := names.UnitApplication(tag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	app, err := u.st.Application(appName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	config, err := app.ApplicationConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return config.GetString(application.HookTimeoutConfigOptionName, ""), nil
+}
+
+// HookTimeouts isn't on the v19 API.
+func (*UniterAPIv19) HookTimeouts(_, _ struct{}) {}
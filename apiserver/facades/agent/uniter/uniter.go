@@ -78,11 +78,17 @@ type UniterAPI struct {
 	cloudSpecer     cloudspec.CloudSpecer
 }
 
+// UniterAPIv19 implements version 19 of the uniter API, which doesn't
+// have HookTimeouts.
+type UniterAPIv19 struct {
+	UniterAPI
+}
+
 // UniterAPIv18 Implements version 18 of the uniter API, which includes methods
 // ModelUUID and OpenedApplicationPortRangesByEndpoint that were removed from
 // later versions.
 type UniterAPIv18 struct {
-	UniterAPI
+	UniterAPIv19
 }
 
 // OpenedMachinePortRangesByEndpoint returns the port ranges opened by each
@@ -2746,6 +2752,9 @@ func (u *UniterAPI) commitHookChangesForOneUnit(unitTag names.UnitTag, changes p
 		if changes.SetUnitState.MeterStatusState != nil {
 			newUS.SetMeterStatusState(*changes.SetUnitState.MeterStatusState)
 		}
+		if changes.SetUnitState.HookHistory != nil {
+			newUS.SetHookHistory(*changes.SetUnitState.HookHistory)
+		}
 
 		modelOp := unit.SetStateOperation(
 			newUS,
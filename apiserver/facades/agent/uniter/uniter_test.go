@@ -1348,6 +1348,31 @@ func (s *uniterSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestHookTimeouts(c *gc.C) {
+	schema := environschema.Fields{
+		application.HookTimeoutConfigOptionName: environschema.Attr{Type: environschema.Tstring},
+	}
+	err := s.wordpress.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		application.HookTimeoutConfigOptionName: "config-changed=10m",
+	}, nil, schema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.HookTimeouts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: "config-changed=10m"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterSuite) TestWatchUnitRelations(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 
@@ -3739,7 +3764,7 @@ func (s *uniterSuite) TestOpenedApplicationPortRangesByEndpoint(c *gc.C) {
 
 	uniterAPI := s.newUniterAPI(c, st, s.authorizer)
 
-	api := &uniter.UniterAPIv18{UniterAPI: *uniterAPI}
+	api := &uniter.UniterAPIv18{UniterAPIv19: uniter.UniterAPIv19{UniterAPI: *uniterAPI}}
 	result, err := api.OpenedApplicationPortRangesByEndpoint(arg)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result, gc.DeepEquals, params.ApplicationOpenedPortsResults{
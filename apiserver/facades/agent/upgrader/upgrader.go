@@ -84,7 +84,12 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = apiservererrors.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.m.WatchForModelConfigChanges()
+			// Staged upgrades change the version some agents should
+			// run without changing the model config.
+			watch := common.NewMultiNotifyWatcher(
+				u.m.WatchForModelConfigChanges(),
+				u.st.WatchUpgradeRollout(),
+			)
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -160,15 +165,61 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
+			err = nil
 			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
+				var vers version.Number
+				if vers, err = u.agentVersion(tag, agentVersion); err == nil {
+					results[i].Version = &vers
+				}
 			} else {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
 				results[i].Version = &jujuversion.Current
 			}
-			err = nil
 		}
 		results[i].Error = apiservererrors.ServerError(err)
 	}
 	return params.VersionResults{Results: results}, nil
 }
+
+// Tools finds the agent binaries each agent should run, which during a
+// staged model upgrade depends on the agent's machine.
+func (u *UpgraderAPI) Tools(args params.Entities) (params.ToolsResults, error) {
+	result := params.ToolsResults{
+		Results: make([]params.ToolsResult, len(args.Entities)),
+	}
+	agentVersion, _, err := u.getGlobalAgentVersion()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		vers, err := u.agentVersion(tag, agentVersion)
+		if err == nil {
+			result.Results[i].ToolsList, err = u.ToolsGetter.AgentTools(tag, vers)
+		}
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// agentVersion returns the version the agent with the given tag should
+// run, given the model's agent version. Machines released to a staged
+// model upgrade run its target version before the model's agent version
+// changes; units follow their machine's agent.
+func (u *UpgraderAPI) agentVersion(tag names.Tag, modelVersion version.Number) (version.Number, error) {
+	machineTag, ok := tag.(names.MachineTag)
+	if !ok {
+		return modelVersion, nil
+	}
+	rollout, err := u.st.UpgradeRollout()
+	if errors.Is(err, errors.NotFound) {
+		return modelVersion, nil
+	} else if err != nil {
+		return version.Number{}, errors.Trace(err)
+	}
+	return rollout.AgentVersion(machineTag.Id(), modelVersion), nil
+}
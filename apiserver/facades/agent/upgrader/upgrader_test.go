@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -375,6 +376,45 @@ func (s *upgraderSuite) TestDesiredVersionForAgent(c *gc.C) {
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
 
+func (s *upgraderSuite) TestDesiredVersionStagedUpgrade(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	f := factory.NewFactory(st, s.StatePool)
+	current := coretesting.CurrentVersion()
+	canary := f.MakeMachine(c, nil)
+	c.Assert(canary.SetAgentVersion(current), jc.ErrorIsNil)
+	other := f.MakeMachine(c, nil)
+	c.Assert(other.SetAgentVersion(current), jc.ErrorIsNil)
+
+	newer := current.Number
+	newer.Patch++
+	_, err := st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: newer,
+		Canaries:  []string{canary.Id()},
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	systemState, err := s.StatePool.SystemState()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, m := range []struct {
+		tag      names.Tag
+		expected version.Number
+	}{
+		{canary.Tag(), newer},
+		{other.Tag(), current.Number},
+	} {
+		authorizer := apiservertesting.FakeAuthorizer{Tag: m.tag}
+		upgraderAPI, err := upgrader.NewUpgraderAPI(systemState, st, s.resources, authorizer)
+		c.Assert(err, jc.ErrorIsNil)
+		results, err := upgraderAPI.DesiredVersion(params.Entities{Entities: []params.Entity{{Tag: m.tag.String()}}})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.Results, gc.HasLen, 1)
+		c.Assert(results.Results[0].Error, gc.IsNil)
+		c.Check(*results.Results[0].Version, gc.Equals, m.expected)
+	}
+}
+
 func (s *upgraderSuite) bumpDesiredAgentVersion(c *gc.C) version.Number {
 	// In order to call SetModelAgentVersion we have to first SetTools on
 	// all the existing machines
@@ -4,6 +4,7 @@
 package application
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"net"
@@ -63,11 +64,19 @@ var ClassifyDetachedStorage = storagecommon.ClassifyDetachedStorage
 
 var logger = loggo.GetLogger("juju.apiserver.application")
 
+// APIv21 provides the Application API facade for version 21.
+type APIv21 struct {
+	*APIBase
+}
+
 // APIv20 provides the Application API facade for version 20.
 type APIv20 struct {
-	*APIBase
+	*APIv21
 }
 
+// UnitsHookHistory isn't on the v20 API.
+func (*APIv20) UnitsHookHistory(_, _ struct{}) {}
+
 // APIv19 provides the Application API facade for version 19.
 type APIv19 struct {
 	*APIv20
@@ -178,6 +187,7 @@ func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 	chClient, err := charmhub.NewClient(charmhub.Config{
 		URL:        chURL,
 		HTTPClient: charmhubHTTPClient,
+		Cache:      ctx.CharmhubCache(),
 		Logger:     logger,
 	})
 	if err != nil {
@@ -187,6 +197,7 @@ func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 	updateBase := NewUpdateBaseAPI(st, makeUpdateSeriesValidator(chClient))
 	validatorCfg := validatorConfig{
 		charmhubHTTPClient: charmhubHTTPClient,
+		charmhubCache:      ctx.CharmhubCache(),
 		caasBroker:         caasBroker,
 		model:              m,
 		registry:           registry,
@@ -755,6 +766,18 @@ func parseCharmSettings(
 	if err != nil {
 		return nil, nil, nil, nil, errors.Trace(err)
 	}
+	if err := validateEgressConfig(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
+	}
+	if err := validateHookTimeoutConfig(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
+	}
+	if err := validateAutoscalingConfig(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
+	}
+	if err := validateAvailabilityConfig(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
+	}
 
 	// If there isn't a charm YAML, then we can just return the charmConfig as
 	// the settings and no need to attempt to parse an empty yaml.
@@ -1897,6 +1920,14 @@ func (api *APIBase) ScaleApplications(args params.ScaleApplicationsParams) (para
 			}
 		}
 
+		appConfig, err := app.ApplicationConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if policy := appConfig.GetString(AutoscalingConfigOptionName, ""); policy != "" {
+			return nil, errors.NotSupportedf("scaling autoscaled application %q", name)
+		}
+
 		var info params.ScaleApplicationInfo
 		if arg.ScaleChange != 0 {
 			newScale, err := app.ChangeScale(arg.ScaleChange)
@@ -2871,6 +2902,50 @@ func (api *APIBase) unitResultForUnit(unit Unit) (*params.UnitResult, error) {
 	return result, nil
 }
 
+// UnitsHookHistory returns the timing of the operations most recently run
+// by the agents of the given units, as saved by their uniters.
+func (api *APIBase) UnitsHookHistory(in params.Entities) (params.UnitHookHistoryResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.UnitHookHistoryResults{}, errors.Trace(err)
+	}
+	results := make([]params.UnitHookHistoryResult, len(in.Entities))
+	for i, one := range in.Entities {
+		history, err := api.unitHookHistory(one.Tag)
+		results[i] = params.UnitHookHistoryResult{
+			History: history,
+			Error:   apiservererrors.ServerError(err),
+		}
+	}
+	return params.UnitHookHistoryResults{Results: results}, nil
+}
+
+func (api *APIBase) unitHookHistory(tag string) ([]params.HookSpan, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := api.backend.Unit(unitTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unitState, err := unit.State()
+	if errors.IsNotFound(err) {
+		// Dead units have no state to report.
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, _ := unitState.HookHistory()
+	if data == "" {
+		return nil, nil
+	}
+	var history []params.HookSpan
+	if err := json.Unmarshal([]byte(data), &history); err != nil {
+		return nil, errors.Annotatef(err, "reading hook history for %s", unit.Name())
+	}
+	return history, nil
+}
+
 // openPortsOnMachineForUnit returns the unique set of opened ports for the
 // specified unit and machine arguments without distinguishing between port
 // ranges across subnets. This method is provided for backwards compatibility
@@ -920,6 +920,15 @@ func (s *ApplicationSuite) expectUnit(ctrl *gomock.Controller, name string) *moc
 	return unit
 }
 
+func (s *ApplicationSuite) expectUnitWithHookHistory(ctrl *gomock.Controller, name, history string) *mocks.MockUnit {
+	unitState := state.NewUnitState()
+	unitState.SetHookHistory(history)
+	unit := mocks.NewMockUnit(ctrl)
+	unit.EXPECT().Name().Return(name).AnyTimes()
+	unit.EXPECT().State().Return(unitState, nil)
+	return unit
+}
+
 func (s *ApplicationSuite) expectStorageAttachment(ctrl *gomock.Controller, storage string) *mocks.MockStorageAttachment {
 	sa := mocks.NewMockStorageAttachment(ctrl)
 	sa.EXPECT().StorageInstance().Return(names.NewStorageTag(storage))
@@ -2297,6 +2306,7 @@ func (s *ApplicationSuite) TestScaleApplicationsCAASModel(c *gc.C) {
 	defer ctrl.Finish()
 
 	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().ApplicationConfig().Return(coreconfig.ConfigAttributes{}, nil)
 	app.EXPECT().SetScale(5, int64(0), true).Return(nil)
 	s.backend.EXPECT().Application("postgresql").Return(app, nil)
 
@@ -2368,6 +2378,26 @@ func (s *ApplicationSuite) TestScaleApplicationsNotAllowedForDaemonSet(c *gc.C)
 	c.Assert(msg, gc.Matches, `scale a "daemon" application not supported`)
 }
 
+func (s *ApplicationSuite) TestScaleApplicationsNotAllowedWhenAutoscaled(c *gc.C) {
+	s.modelType = state.ModelTypeCAAS
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().ApplicationConfig().Return(coreconfig.ConfigAttributes{"autoscaling": "max=5,cpu=70"}, nil)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.ScaleApplications(params.ScaleApplicationsParams{
+		Applications: []params.ScaleApplicationParams{{
+			ApplicationTag: "application-postgresql",
+			Scale:          5,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `scaling autoscaled application "postgresql" not supported`)
+}
+
 func (s *ApplicationSuite) TestScaleApplicationsBlocked(c *gc.C) {
 	s.modelType = state.ModelTypeCAAS
 	s.changeAllowed = errors.New("change blocked")
@@ -2388,6 +2418,7 @@ func (s *ApplicationSuite) TestScaleApplicationsCAASModelScaleChange(c *gc.C) {
 	defer ctrl.Finish()
 
 	app := s.expectDefaultApplication(ctrl)
+	app.EXPECT().ApplicationConfig().Return(coreconfig.ConfigAttributes{}, nil)
 	app.EXPECT().ChangeScale(5).Return(7, nil)
 	s.backend.EXPECT().Application("postgresql").Return(app, nil)
 
@@ -2864,6 +2895,82 @@ func (s *ApplicationSuite) TestSetEmptyConfigMasterBranch(c *gc.C) {
 	c.Assert(result.OneError(), jc.ErrorIsNil)
 }
 
+func (s *ApplicationSuite) TestSetConfigInvalidEgress(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"egress":       "443/tcp to 10.0.0.0",
+				"stringOption": "stringVal",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `parsing settings for application: invalid egress config: parsing egress rule .*`)
+}
+
+func (s *ApplicationSuite) TestSetConfigInvalidAutoscaling(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"autoscaling":  "min=5,max=2,cpu=70",
+				"stringOption": "stringVal",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `parsing settings for application: invalid autoscaling config: autoscaling max 2 less than min 5 not valid`)
+}
+
+func (s *ApplicationSuite) TestSetConfigInvalidMaxUnavailable(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"max-unavailable": "half",
+				"stringOption":    "stringVal",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `parsing settings for application: invalid max-unavailable config: max unavailable "half" not valid`)
+}
+
+func (s *ApplicationSuite) TestSetConfigInvalidHookTimeout(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	app := s.expectDefaultApplication(ctrl)
+	s.backend.EXPECT().Application("postgresql").Return(app, nil)
+
+	result, err := s.api.SetConfigs(params.ConfigSetArgs{
+		Args: []params.ConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"hook-timeout": "config-changed=soon",
+				"stringOption": "stringVal",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `parsing settings for application: invalid hook-timeout config: hook timeout config-changed "soon" not valid`)
+}
+
 func (s *ApplicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	s.modelType = state.ModelTypeCAAS
 	ctrl := s.setup(c)
@@ -3288,6 +3395,45 @@ func (s *ApplicationSuite) TestUnitsInfo(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestUnitsHookHistory(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	queued := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	unit := s.expectUnitWithHookHistory(ctrl, "postgresql/0", fmt.Sprintf(`[{
+		"operation": "run config-changed hook",
+		"queued": %q,
+		"lock-wait": 1000000000,
+		"prepare": 0,
+		"execute": 5000000000,
+		"commit": 0
+	}]`, queued.Format(time.RFC3339)))
+	s.backend.EXPECT().Unit("postgresql/0").Return(unit, nil)
+	corrupt := s.expectUnitWithHookHistory(ctrl, "postgresql/1", "[{")
+	s.backend.EXPECT().Unit("postgresql/1").Return(corrupt, nil)
+	empty := s.expectUnitWithHookHistory(ctrl, "postgresql/2", "")
+	s.backend.EXPECT().Unit("postgresql/2").Return(empty, nil)
+
+	result, err := s.api.UnitsHookHistory(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-postgresql-0"},
+		{Tag: "unit-postgresql-1"},
+		{Tag: "unit-postgresql-2"},
+		{Tag: "application-postgresql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].History, jc.DeepEquals, []params.HookSpan{{
+		Operation: "run config-changed hook",
+		Queued:    queued,
+		LockWait:  time.Second,
+		Execute:   5 * time.Second,
+	}})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "reading hook history for postgresql/1: .*")
+	c.Assert(result.Results[2], jc.DeepEquals, params.UnitHookHistoryResult{})
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `"application-postgresql" is not a valid unit tag`)
+}
+
 func (s *ApplicationSuite) TestUnitsInfoForApplication(c *gc.C) {
 	ctrl := s.setup(c)
 	defer ctrl.Finish()
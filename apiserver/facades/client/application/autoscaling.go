@@ -0,0 +1,32 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/config"
+)
+
+// AutoscalingConfigOptionName is the option name used to set the
+// autoscaling policy in application configuration.
+const AutoscalingConfigOptionName = "autoscaling"
+
+// validateAutoscalingConfig ensures any autoscaling policy in the
+// application config can be parsed.
+func validateAutoscalingConfig(attrs config.ConfigAttributes) error {
+	value, ok := attrs[AutoscalingConfigOptionName]
+	if !ok || value == nil || value == "" {
+		return nil
+	}
+	policy, ok := value.(string)
+	if !ok {
+		return errors.NotValidf("%s value %v", AutoscalingConfigOptionName, value)
+	}
+	if _, err := caas.ParseAutoscalingPolicy(policy); err != nil {
+		return errors.NewNotValid(err, "invalid autoscaling config")
+	}
+	return nil
+}
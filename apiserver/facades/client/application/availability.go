@@ -0,0 +1,38 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/config"
+)
+
+const (
+	// MaxUnavailableConfigOptionName is the option name used to limit how
+	// many units may be disrupted at once in application configuration.
+	MaxUnavailableConfigOptionName = "max-unavailable"
+
+	// SpreadZonesConfigOptionName is the option name used to spread
+	// units across availability zones in application configuration.
+	SpreadZonesConfigOptionName = "spread-zones"
+)
+
+// validateAvailabilityConfig ensures any max unavailable limit in the
+// application config can be parsed.
+func validateAvailabilityConfig(attrs config.ConfigAttributes) error {
+	value, ok := attrs[MaxUnavailableConfigOptionName]
+	if !ok || value == nil || value == "" {
+		return nil
+	}
+	maxUnavailable, ok := value.(string)
+	if !ok {
+		return errors.NotValidf("%s value %v", MaxUnavailableConfigOptionName, value)
+	}
+	if _, err := caas.ParseMaxUnavailable(maxUnavailable); err != nil {
+		return errors.NewNotValid(err, "invalid max-unavailable config")
+	}
+	return nil
+}
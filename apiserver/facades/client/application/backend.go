@@ -210,6 +210,7 @@ type Unit interface {
 	AssignWithPolicy(state.AssignmentPolicy) error
 	AssignWithPlacement(*instance.Placement) error
 	ContainerInfo() (state.CloudContainer, error)
+	State() (*state.UnitState, error)
 }
 
 // Model defines a subset of the functionality provided by the
@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/facades/client/charms/services"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/arch"
 	corebase "github.com/juju/juju/core/base"
@@ -251,6 +252,7 @@ type deployTemplate struct {
 
 type validatorConfig struct {
 	charmhubHTTPClient facade.HTTPClient
+	charmhubCache      *charmhub.Cache
 	caasBroker         CaasBrokerInterface
 	model              Model
 	registry           storage.ProviderRegistry
@@ -261,6 +263,7 @@ type validatorConfig struct {
 func makeDeployFromRepositoryValidator(cfg validatorConfig) DeployFromRepositoryValidator {
 	v := &deployFromRepositoryValidator{
 		charmhubHTTPClient: cfg.charmhubHTTPClient,
+		charmhubCache:      cfg.charmhubCache,
 		model:              cfg.model,
 		state:              cfg.state,
 		newRepoFactory: func(cfg services.CharmRepoFactoryConfig) corecharm.RepositoryFactory {
@@ -307,6 +310,7 @@ type deployFromRepositoryValidator struct {
 	// For testing using mocks.
 	newRepoFactory     func(services.CharmRepoFactoryConfig) corecharm.RepositoryFactory
 	charmhubHTTPClient facade.HTTPClient
+	charmhubCache      *charmhub.Cache
 
 	// For testing using mocks.
 	newStateBindings func(st state.EndpointBinding, givenMap map[string]string) (Bindings, error)
@@ -919,6 +923,7 @@ func (v *deployFromRepositoryValidator) getCharmRepository(src corecharm.Source)
 	repoFactory := v.newRepoFactory(services.CharmRepoFactoryConfig{
 		Logger:             deployRepoLogger,
 		CharmhubHTTPClient: v.charmhubHTTPClient,
+		CharmhubCache:      v.charmhubCache,
 		StateBackend:       v.state,
 		ModelBackend:       v.model,
 	})
@@ -0,0 +1,32 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/config"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+// EgressConfigOptionName is the option name used to set the egress rules
+// in application configuration.
+const EgressConfigOptionName = "egress"
+
+// validateEgressConfig ensures any egress rules in the application config
+// can be parsed.
+func validateEgressConfig(attrs config.ConfigAttributes) error {
+	value, ok := attrs[EgressConfigOptionName]
+	if !ok || value == nil {
+		return nil
+	}
+	rules, ok := value.(string)
+	if !ok {
+		return errors.NotValidf("%s value %v", EgressConfigOptionName, value)
+	}
+	if _, err := firewall.ParseEgressRules(rules); err != nil {
+		return errors.NewNotValid(err, "invalid egress config")
+	}
+	return nil
+}
@@ -94,6 +94,31 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmokeTest(c *gc.C) {
 				"source":      "default",
 				"type":        environschema.Tbool,
 				"value":       false,
+			},
+			"egress": map[string]interface{}{
+				"description": "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"autoscaling": map[string]interface{}{
+				"description": "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"max-unavailable": map[string]interface{}{
+				"description": "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"spread-zones": map[string]interface{}{
+				"description": "Whether to spread the application's Kubernetes units across availability zones",
+				"source":      "unset",
+				"type":        environschema.Tbool,
 			}},
 		Constraints: constraints.MustParse("arch=amd64"),
 		Base:        params.Base{Name: "ubuntu", Channel: "12.10/stable"},
@@ -278,6 +303,31 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"egress": map[string]interface{}{
+				"description": "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"autoscaling": map[string]interface{}{
+				"description": "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"max-unavailable": map[string]interface{}{
+				"description": "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"spread-zones": map[string]interface{}{
+				"description": "Whether to spread the application's Kubernetes units across availability zones",
+				"source":      "unset",
+				"type":        "bool",
+			},
 		},
 		Base: params.Base{Name: "ubuntu", Channel: "22.04/stable"},
 		EndpointBindings: map[string]string{
@@ -344,6 +394,31 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"egress": map[string]interface{}{
+				"description": "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"autoscaling": map[string]interface{}{
+				"description": "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"max-unavailable": map[string]interface{}{
+				"description": "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"spread-zones": map[string]interface{}{
+				"description": "Whether to spread the application's Kubernetes units across availability zones",
+				"source":      "unset",
+				"type":        "bool",
+			},
 		},
 		Base: params.Base{Name: "ubuntu", Channel: "22.04/stable"},
 		EndpointBindings: map[string]string{
@@ -368,6 +443,31 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"egress": map[string]interface{}{
+				"description": "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"autoscaling": map[string]interface{}{
+				"description": "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"max-unavailable": map[string]interface{}{
+				"description": "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"spread-zones": map[string]interface{}{
+				"description": "Whether to spread the application's Kubernetes units across availability zones",
+				"source":      "unset",
+				"type":        "bool",
+			},
 		},
 		EndpointBindings: map[string]string{
 			"":                  network.AlphaSpaceName,
@@ -398,6 +498,31 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"egress": map[string]interface{}{
+				"description": "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"autoscaling": map[string]interface{}{
+				"description": "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"max-unavailable": map[string]interface{}{
+				"description": "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"spread-zones": map[string]interface{}{
+				"description": "Whether to spread the application's Kubernetes units across availability zones",
+				"source":      "unset",
+				"type":        "bool",
+			},
 		},
 		EndpointBindings: map[string]string{
 			"":                  network.AlphaSpaceName,
@@ -0,0 +1,33 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+
+	corecharm "github.com/juju/juju/core/charm"
+	"github.com/juju/juju/core/config"
+)
+
+// HookTimeoutConfigOptionName is the option name used to set hook
+// timeouts in application configuration. Timeouts set here override
+// those in the charm's metadata.
+const HookTimeoutConfigOptionName = "hook-timeout"
+
+// validateHookTimeoutConfig ensures any hook timeouts in the application
+// config can be parsed.
+func validateHookTimeoutConfig(attrs config.ConfigAttributes) error {
+	value, ok := attrs[HookTimeoutConfigOptionName]
+	if !ok || value == nil {
+		return nil
+	}
+	timeouts, ok := value.(string)
+	if !ok {
+		return errors.NotValidf("%s value %v", HookTimeoutConfigOptionName, value)
+	}
+	if _, err := corecharm.ParseHookTimeouts(timeouts); err != nil {
+		return errors.NewNotValid(err, "invalid hook-timeout config")
+	}
+	return nil
+}
@@ -2202,6 +2202,21 @@ func (mr *MockUnitMockRecorder) Resolve(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockUnit)(nil).Resolve), arg0)
 }
 
+// State mocks base method.
+func (m *MockUnit) State() (*state.UnitState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State")
+	ret0, _ := ret[0].(*state.UnitState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// State indicates an expected call of State.
+func (mr *MockUnitMockRecorder) State() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockUnit)(nil).State))
+}
+
 // Tag mocks base method.
 func (m *MockUnit) Tag() names.Tag {
 	m.ctrl.T.Helper()
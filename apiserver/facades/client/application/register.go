@@ -31,13 +31,24 @@ func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("Application", 20, func(ctx facade.Context) (facade.Facade, error) {
 		return newFacadeV20(ctx) // Remove remote space
 	}, reflect.TypeOf((*APIv20)(nil)))
+	registry.MustRegister("Application", 21, func(ctx facade.Context) (facade.Facade, error) {
+		return newFacadeV21(ctx) // Added UnitsHookHistory
+	}, reflect.TypeOf((*APIv21)(nil)))
 }
 
-func newFacadeV20(ctx facade.Context) (*APIv20, error) {
+func newFacadeV21(ctx facade.Context) (*APIv21, error) {
 	api, err := newFacadeBase(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	return &APIv21{api}, nil
+}
+
+func newFacadeV20(ctx facade.Context) (*APIv20, error) {
+	api, err := newFacadeV21(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &APIv20{api}, nil
 }
 
@@ -19,6 +19,31 @@ var trustFields = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.JujuGroup,
 	},
+	EgressConfigOptionName: {
+		Description: "Outbound traffic the application's machines may send, as semicolon separated rules like \"443/tcp to 10.0.0.0/8\"; unset allows all outbound traffic",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	HookTimeoutConfigOptionName: {
+		Description: "How long the application's hooks may run before they are killed, as comma separated timeouts like \"default=1h,kill-grace=1m,config-changed=10m\"; overrides the charm's hook-timeout metadata",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	AutoscalingConfigOptionName: {
+		Description: "How Juju autoscales the application's units from the load measured by Kubernetes, like \"min=2,max=10,cpu=70,memory=80\" or \"max=5,metric=requests-per-second:100\"; unset disables autoscaling",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	MaxUnavailableConfigOptionName: {
+		Description: "How many of the application's Kubernetes units may be disrupted at once, such as by a node drain, as a count like \"1\" or a percentage like \"25%\", which must be at least one unit; unset places no limit",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	SpreadZonesConfigOptionName: {
+		Description: "Whether to spread the application's Kubernetes units across availability zones",
+		Type:        environschema.Tbool,
+		Group:       environschema.JujuGroup,
+	},
 }
 
 var trustDefaults = schema.Defaults{
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	charmsinterfaces "github.com/juju/juju/apiserver/facades/client/charms/interfaces"
 	"github.com/juju/juju/apiserver/facades/client/charms/services"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/arch"
 	"github.com/juju/juju/core/base"
 	corecharm "github.com/juju/juju/core/charm"
@@ -61,6 +62,7 @@ type API struct {
 	backendState       charmsinterfaces.BackendState
 	backendModel       charmsinterfaces.BackendModel
 	charmhubHTTPClient facade.HTTPClient
+	charmhubCache      *charmhub.Cache
 
 	tag             names.ModelTag
 	requestRecorder facade.RequestRecorder
@@ -510,6 +512,7 @@ func (a *API) getCharmRepository(src corecharm.Source) (corecharm.Repository, er
 	repoFactory := a.newRepoFactory(services.CharmRepoFactoryConfig{
 		Logger:             logger,
 		CharmhubHTTPClient: a.charmhubHTTPClient,
+		CharmhubCache:      a.charmhubCache,
 		StateBackend:       a.backendState,
 		ModelBackend:       a.backendModel,
 	})
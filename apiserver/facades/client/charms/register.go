@@ -79,6 +79,7 @@ func newFacadeBase(ctx facade.Context) (*API, error) {
 		backendState:       newStateShim(st),
 		backendModel:       m,
 		charmhubHTTPClient: ctx.HTTPClient(facade.CharmhubHTTPClient),
+		charmhubCache:      ctx.CharmhubCache(),
 		newStorage: func(modelUUID string) services.Storage {
 			return storage.NewStorage(modelUUID, st.MongoSession())
 		},
@@ -22,6 +22,9 @@ type CharmDownloaderConfig struct {
 	// An HTTP client that is injected when making Charmhub API calls.
 	CharmhubHTTPClient charmhub.HTTPClient
 
+	// The cache shared by the controller's charmhub clients, if any.
+	CharmhubCache *charmhub.Cache
+
 	// A factory for accessing model-scoped storage for charm blobs.
 	StorageFactory func(modelUUID string) Storage
 
@@ -42,6 +45,7 @@ func NewCharmDownloader(cfg CharmDownloaderConfig) (*charmdownloader.Downloader,
 		factory: NewCharmRepoFactory(CharmRepoFactoryConfig{
 			Logger:             cfg.Logger.Child("charmrepofactory"),
 			CharmhubHTTPClient: cfg.CharmhubHTTPClient,
+			CharmhubCache:      cfg.CharmhubCache,
 			StateBackend:       cfg.StateBackend,
 			ModelBackend:       cfg.ModelBackend,
 		}),
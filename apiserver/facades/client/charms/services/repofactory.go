@@ -24,6 +24,9 @@ type CharmRepoFactoryConfig struct {
 	// An HTTP client that is injected when making Charmhub API calls.
 	CharmhubHTTPClient charmhub.HTTPClient
 
+	// The cache shared by the controller's charmhub clients, if any.
+	CharmhubCache *charmhub.Cache
+
 	StateBackend StateBackend
 	ModelBackend ModelBackend
 }
@@ -34,6 +37,7 @@ type CharmRepoFactoryConfig struct {
 type CharmRepoFactory struct {
 	logger             loggo.Logger
 	charmhubHTTPClient charmhub.HTTPClient
+	charmhubCache      *charmhub.Cache
 	stateBackend       StateBackend
 	modelBackend       ModelBackend
 
@@ -46,6 +50,7 @@ func NewCharmRepoFactory(cfg CharmRepoFactoryConfig) *CharmRepoFactory {
 	return &CharmRepoFactory{
 		logger:             cfg.Logger,
 		charmhubHTTPClient: cfg.CharmhubHTTPClient,
+		charmhubCache:      cfg.CharmhubCache,
 		stateBackend:       cfg.StateBackend,
 		modelBackend:       cfg.ModelBackend,
 		memoizedRepos:      make(map[corecharm.Source]corecharm.Repository),
@@ -74,6 +79,7 @@ func (f *CharmRepoFactory) GetCharmRepository(src corecharm.Source) (corecharm.R
 		chClient, err := charmhub.NewClient(charmhub.Config{
 			URL:        chURL,
 			HTTPClient: f.charmhubHTTPClient,
+			Cache:      f.charmhubCache,
 			Logger:     f.logger.Child("charmhubrepo"),
 		})
 		if err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/version/v2"
 
 	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/crossmodel"
@@ -75,6 +76,7 @@ type Backend interface {
 	SetModelConstraints(constraints.Value) error
 	Unit(string) (Unit, error)
 	UpdateModelConfig(map[string]interface{}, []string, ...state.ValidateConfigFunc) error
+	UpgradeRollout() (*state.UpgradeRollout, error)
 }
 
 // MongoSession provides a way to get the status for the mongo replicaset.
@@ -88,6 +90,7 @@ type Model interface {
 	Type() state.ModelType
 	UUID() string
 	Life() state.Life
+	Cloud() (cloud.Cloud, error)
 	CloudName() string
 	CloudRegion() string
 	CloudCredentialTag() (names.CloudCredentialTag, bool)
@@ -15,6 +15,7 @@ import (
 
 	charm "github.com/juju/charm/v12"
 	client "github.com/juju/juju/apiserver/facades/client/client"
+	cloud "github.com/juju/juju/cloud"
 	controller "github.com/juju/juju/controller"
 	constraints "github.com/juju/juju/core/constraints"
 	crossmodel "github.com/juju/juju/core/crossmodel"
@@ -748,6 +749,21 @@ func (mr *MockBackendMockRecorder) UpdateModelConfig(arg0, arg1 any, arg2 ...any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModelConfig", reflect.TypeOf((*MockBackend)(nil).UpdateModelConfig), varargs...)
 }
 
+// UpgradeRollout mocks base method.
+func (m *MockBackend) UpgradeRollout() (*state.UpgradeRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeRollout")
+	ret0, _ := ret[0].(*state.UpgradeRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRollout indicates an expected call of UpgradeRollout.
+func (mr *MockBackendMockRecorder) UpgradeRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRollout", reflect.TypeOf((*MockBackend)(nil).UpgradeRollout))
+}
+
 // MockModel is a mock of Model interface.
 type MockModel struct {
 	ctrl     *gomock.Controller
@@ -786,6 +802,21 @@ func (mr *MockModelMockRecorder) AddUser(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockModel)(nil).AddUser), arg0)
 }
 
+// Cloud mocks base method.
+func (m *MockModel) Cloud() (cloud.Cloud, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cloud")
+	ret0, _ := ret[0].(cloud.Cloud)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cloud indicates an expected call of Cloud.
+func (mr *MockModelMockRecorder) Cloud() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cloud", reflect.TypeOf((*MockModel)(nil).Cloud))
+}
+
 // CloudCredentialTag mocks base method.
 func (m *MockModel) CloudCredentialTag() (names.CloudCredentialTag, bool) {
 	m.ctrl.T.Helper()
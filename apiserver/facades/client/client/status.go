@@ -12,10 +12,12 @@ import (
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/client/application"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/cache"
@@ -26,6 +28,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 )
@@ -523,6 +526,10 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot determine model status")
 	}
+	modelStatus.UnenforcedEgress, err = c.unenforcedEgress(context.allAppsUnitsCharmBindings.applications)
+	if err != nil {
+		return noStatus, errors.Annotate(err, "cannot determine egress status")
+	}
 
 	var storageDetails []params.StorageDetails
 	var filesystemDetails []params.FilesystemDetails
@@ -612,6 +619,43 @@ func filterBranches(ctxBranches map[string]cache.Branch,
 
 // newToolsVersionAvailable will return a string representing a tools
 // version only if the latest check is newer than current tools.
+// unenforcedEgress returns the names of the applications with egress
+// rules, if the model's cloud doesn't enforce them.
+func (c *Client) unenforcedEgress(applications map[string]*state.Application) ([]string, error) {
+	if len(applications) == 0 {
+		return nil, nil
+	}
+	m, err := c.stateAccessor.Model()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get model")
+	}
+	modelCloud, err := m.Cloud()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	provider, err := environs.Provider(modelCloud.Type)
+	if err != nil {
+		logger.Warningf("cannot determine egress status: %v", err)
+		return nil, nil
+	}
+	if supporter, ok := provider.(environs.EgressRulesSupporter); ok && supporter.SupportsEgressRules() {
+		return nil, nil
+	}
+
+	var appNames []string
+	for name, app := range applications {
+		cfg, err := app.ApplicationConfig()
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get config for application %q", name)
+		}
+		if cfg.GetString(application.EgressConfigOptionName, "") != "" {
+			appNames = append(appNames, name)
+		}
+	}
+	sort.Strings(appNames)
+	return appNames, nil
+}
+
 func (c *Client) modelStatus() (params.ModelStatusInfo, error) {
 	var info params.ModelStatusInfo
 
@@ -659,9 +703,51 @@ func (c *Client) modelStatus() (params.ModelStatusInfo, error) {
 		}
 	}
 
+	if ok {
+		if info.UpgradeRollout, err = c.upgradeRolloutStatus(current); err != nil {
+			return params.ModelStatusInfo{}, errors.Annotate(err, "cannot obtain staged upgrade status")
+		}
+	}
+
 	return info, nil
 }
 
+// upgradeRolloutStatus returns the progress of the model's staged
+// upgrade from the current agent version, if one is underway.
+func (c *Client) upgradeRolloutStatus(current version.Number) (*params.UpgradeRolloutInfo, error) {
+	rollout, err := c.stateAccessor.UpgradeRollout()
+	if errors.Is(err, errors.NotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !rollout.Status().Active() || rollout.FromVersion() != current {
+		return nil, nil
+	}
+	machines, err := c.stateAccessor.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Released machines count towards the total even once removed.
+	released := rollout.Released()
+	all := set.NewStrings(released...)
+	for _, m := range machines {
+		if m.Life() == state.Alive {
+			all.Add(m.Id())
+		}
+	}
+	return &params.UpgradeRolloutInfo{
+		FromVersion: rollout.FromVersion().String(),
+		ToVersion:   rollout.ToVersion().String(),
+		Status:      string(rollout.Status()),
+		Message:     rollout.Message(),
+		Released:    len(released),
+		Machines:    all.Size(),
+		Batch:       rollout.Batch(),
+		Failed:      rollout.Failed(),
+	}, nil
+}
+
 type applicationStatusInfo struct {
 	// application: application name -> application
 	applications map[string]*state.Application
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/utils/v3"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api"
 	apiclient "github.com/juju/juju/api/client/client"
@@ -30,6 +31,7 @@ import (
 	corearch "github.com/juju/juju/core/arch"
 	"github.com/juju/juju/core/base"
 	corecharm "github.com/juju/juju/core/charm"
+	coreconfig "github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/network"
@@ -97,6 +99,25 @@ func (s *statusSuite) TestUnsupportedNoModelMeterStatus(c *gc.C) {
 	c.Check(status.Model.MeterStatus.Message, gc.Equals, "")
 }
 
+func (s *statusSuite) TestFullStatusUnenforcedEgress(c *gc.C) {
+	u := s.Factory.MakeUnit(c, nil)
+	app, err := u.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.UpdateApplicationConfig(coreconfig.ConfigAttributes{
+		"egress": "443/tcp 10.0.0.0/8",
+	}, nil, environschema.Fields{
+		"egress": environschema.Attr{Type: environschema.Tstring},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "mysql"})
+
+	// The dummy provider doesn't enforce egress rules.
+	client := apiclient.NewClient(s.APIState, coretesting.NoopLogger{})
+	status, err := client.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Model.UnenforcedEgress, jc.DeepEquals, []string{u.ApplicationName()})
+}
+
 func (s *statusSuite) TestFullStatusUnitLeadership(c *gc.C) {
 	u := s.Factory.MakeUnit(c, nil)
 	claimer, err := s.LeaseManager.Claimer("application-leadership", s.State.ModelUUID())
@@ -3,6 +3,14 @@
 
 package highavailability
 
+import "github.com/juju/juju/core/network"
+
 var (
 	NewHighAvailabilityAPI = newHighAvailabilityAPI
 )
+
+// SetAvailabilityZones replaces the source of availability zones used by
+// the given API to spread controllers.
+func SetAvailabilityZones(api *HighAvailabilityAPI, zones func() (network.AvailabilityZones, error)) {
+	api.availabilityZones = zones
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names/v5"
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/constraints"
+	corecontroller "github.com/juju/juju/core/controller"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/permission"
@@ -39,6 +41,11 @@ type HighAvailabilityAPI struct {
 	state      *state.State
 	resources  facade.Resources
 	authorizer facade.Authorizer
+
+	// availabilityZones returns the availability zones of the
+	// controller model's cloud, or an error satisfying
+	// errors.IsNotSupported if the cloud has none.
+	availabilityZones func() (network.AvailabilityZones, error)
 }
 
 var _ HighAvailability = (*HighAvailabilityAPI)(nil)
@@ -115,12 +122,116 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 		return params.ControllersChanges{}, errors.Trace(err)
 	}
 
+	// Unless told where to put them, spread new controllers across the
+	// availability zones so that the loss of a zone leaves a quorum.
+	var (
+		members  map[string]int
+		zones    []string
+		warnings []string
+	)
+	if len(spec.Placement) == 0 {
+		members, zones, warnings, err = api.spreadControllers(st, controllerIds, spec.Constraints)
+		if err != nil {
+			return params.ControllersChanges{}, errors.Trace(err)
+		}
+	}
+
 	// Might be nicer to pass the spec itself to this method.
-	changes, err := st.EnableHA(spec.NumControllers, spec.Constraints, referenceMachine.Base(), spec.Placement)
+	changes, err := st.EnableHAInZones(
+		spec.NumControllers, spec.Constraints, referenceMachine.Base(), spec.Placement, zones)
 	if err != nil {
 		return params.ControllersChanges{}, err
 	}
-	return controllersChanges(changes), nil
+	result := controllersChanges(changes)
+	if len(zones) > 0 {
+		for _, zone := range changes.Zones {
+			members[zone]++
+		}
+		warnings = append(warnings, zoneWarnings(members)...)
+	}
+	for _, warning := range warnings {
+		logger.Warningf("%s", warning)
+	}
+	result.Warnings = warnings
+	return result, nil
+}
+
+// spreadControllers returns the number of voting controllers in each
+// availability zone, along with the zones in which to place any new
+// controllers, in order, so as to spread them as evenly as possible.
+// The remaining usable zones follow, as fallbacks should a zone fail.
+// No zones are returned if the cloud does not support them. If the zones
+// can't be retrieved, for instance because the environ can't be opened,
+// a warning is returned instead, and the controllers are not spread.
+func (api *HighAvailabilityAPI) spreadControllers(
+	st *state.State, controllerIds []string, cons constraints.Value,
+) (map[string]int, []string, []string, error) {
+	zones, err := api.availabilityZones()
+	if errors.IsNotSupported(err) {
+		return nil, nil, nil, nil
+	} else if err != nil {
+		return nil, nil, []string{fmt.Sprintf(
+			"not spreading controllers across availability zones: %v", err,
+		)}, nil
+	}
+	// Respect any zones the controllers are constrained to.
+	if cons.HasZones() {
+		allowed := set.NewStrings(*cons.Zones...)
+		zones = slices.DeleteFunc(slices.Clone(zones), func(zone network.AvailabilityZone) bool {
+			return !allowed.Contains(zone.Name())
+		})
+	}
+
+	members := make(map[string]int)
+	for _, id := range controllerIds {
+		node, err := st.ControllerNode(id)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		if !node.WantsVote() {
+			continue
+		}
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, nil, nil, errors.Annotatef(err, "reading controller id %v", id)
+		}
+		// Controllers not yet provisioned are counted in an unknown zone.
+		var zone string
+		hc, err := m.HardwareCharacteristics()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, nil, nil, errors.Trace(err)
+		} else if err == nil && hc.AvailabilityZone != nil {
+			zone = *hc.AvailabilityZone
+		}
+		members[zone]++
+	}
+	// Placements are only consumed for as many controllers as are added,
+	// so provide enough for the largest possible controller. Any usable
+	// zones left out follow, so the controllers may fall back to them.
+	spread := zones.Spread(members, corecontroller.MaxPeers)
+	for _, zone := range zones {
+		if zone.Available() && !slices.Contains(spread, zone.Name()) {
+			spread = append(spread, zone.Name())
+		}
+	}
+	return members, spread, nil, nil
+}
+
+// zoneWarnings returns a warning if the controllers, numbering as given in
+// each availability zone, would lose quorum should a single zone fail.
+func zoneWarnings(members map[string]int) []string {
+	if network.SurvivesZoneLoss(members) {
+		return nil
+	}
+	var total int
+	for _, n := range members {
+		total += n
+	}
+	return []string{fmt.Sprintf(
+		"%d controllers are spread across only %d availability zone(s); "+
+			"the loss of a single zone could cost the controller its quorum",
+		total, len(members),
+	)}
 }
 
 // getReferenceController looks up the ideal controller to use as a reference for Constraints and Release
@@ -264,7 +375,21 @@ func controllersChanges(change state.ControllersChanges) params.ControllersChang
 		Maintained: machineIdsToTags(change.Maintained...),
 		Removed:    machineIdsToTags(change.Removed...),
 		Converted:  machineIdsToTags(change.Converted...),
+		Zones:      machineIdsToTagZones(change.Zones),
+	}
+}
+
+// machineIdsToTagZones returns a copy of the input map of machine IDs to
+// zones, keyed by machine tag string instead.
+func machineIdsToTagZones(zones map[string]string) map[string]string {
+	if len(zones) == 0 {
+		return nil
 	}
+	result := make(map[string]string, len(zones))
+	for id, zone := range zones {
+		result[names.NewMachineTag(id).String()] = zone
+	}
+	return result
 }
 
 // machineIdsToTags returns a slice of machine tag strings created from the
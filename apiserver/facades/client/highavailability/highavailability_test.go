@@ -18,6 +18,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc/params"
@@ -65,6 +66,9 @@ func (s *clientSuite) SetUpTest(c *gc.C) {
 		Auth_:      s.authorizer,
 	})
 	c.Assert(err, jc.ErrorIsNil)
+	// Spreading controllers across zones is exercised by tests
+	// that provide their own zones.
+	s.setAvailabilityZones(nil)
 
 	_, err = s.State.AddMachines(state.MachineTemplate{
 		Base:        state.UbuntuBase("12.10"),
@@ -95,6 +99,26 @@ func (s *clientSuite) setMachineAddresses(c *gc.C, machineId string) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+// setAvailabilityZones makes the API use the given zones to spread
+// controllers, or treat zones as unsupported if there are none.
+func (s *clientSuite) setAvailabilityZones(zones network.AvailabilityZones) {
+	highavailability.SetAvailabilityZones(s.haServer, func() (network.AvailabilityZones, error) {
+		if zones == nil {
+			return nil, errors.NotSupportedf("availability zones")
+		}
+		return zones, nil
+	})
+}
+
+func (s *clientSuite) setControllerZone(c *gc.C, machineId, zone string) {
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned(instance.Id("i-"+machineId), "", "fake-nonce", &instance.HardwareCharacteristics{
+		AvailabilityZone: &zone,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *clientSuite) enableHA(
 	c *gc.C, numControllers int, cons constraints.Value, placement []string,
 ) (params.ControllersChanges, error) {
@@ -299,6 +323,100 @@ func (s *clientSuite) TestEnableHAControllerConfigConstraints(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestEnableHASpreadsAcrossZones(c *gc.C) {
+	s.setAvailabilityZones(network.AvailabilityZones{
+		&fakeZone{name: "zone1", available: true},
+		&fakeZone{name: "zone2"},
+		&fakeZone{name: "zone3", available: true},
+		&fakeZone{name: "zone4", available: true},
+	})
+	s.setControllerZone(c, "0", "zone3")
+
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	c.Assert(enableHAResult.Zones, gc.DeepEquals, map[string]string{
+		"machine-1": "zone1",
+		"machine-2": "zone4",
+	})
+	c.Assert(enableHAResult.Warnings, gc.HasLen, 0)
+
+	// Each machine prefers its zone, but may fall back to the others.
+	for id, zones := range map[string][]string{
+		"1": {"zone1", "zone4", "zone3"},
+		"2": {"zone4", "zone1", "zone3"},
+	} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		cons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		expectedCons := controllerCons
+		expectedCons.Zones = &zones
+		c.Check(cons, gc.DeepEquals, expectedCons)
+	}
+}
+
+func (s *clientSuite) TestEnableHAWithoutZonesWarns(c *gc.C) {
+	highavailability.SetAvailabilityZones(s.haServer, func() (network.AvailabilityZones, error) {
+		return nil, errors.New("opening environment: boom")
+	})
+
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	c.Assert(enableHAResult.Zones, gc.HasLen, 0)
+	c.Assert(enableHAResult.Warnings, gc.DeepEquals, []string{
+		"not spreading controllers across availability zones: opening environment: boom",
+	})
+}
+
+func (s *clientSuite) TestEnableHASpreadsAcrossConstrainedZones(c *gc.C) {
+	s.setAvailabilityZones(network.AvailabilityZones{
+		&fakeZone{name: "zone1", available: true},
+		&fakeZone{name: "zone2", available: true},
+		&fakeZone{name: "zone3", available: true},
+	})
+
+	enableHAResult, err := s.enableHA(c, 3, constraints.MustParse("zones=zone2,zone3"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Zones, gc.DeepEquals, map[string]string{
+		"machine-1": "zone2",
+		"machine-2": "zone3",
+	})
+}
+
+func (s *clientSuite) TestEnableHAWarnsForInsufficientZones(c *gc.C) {
+	s.setAvailabilityZones(network.AvailabilityZones{
+		&fakeZone{name: "zone1", available: true},
+		&fakeZone{name: "zone2"},
+	})
+	s.setControllerZone(c, "0", "zone1")
+
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	c.Assert(enableHAResult.Zones, gc.DeepEquals, map[string]string{
+		"machine-1": "zone1",
+		"machine-2": "zone1",
+	})
+	c.Assert(enableHAResult.Warnings, gc.DeepEquals, []string{
+		"3 controllers are spread across only 1 availability zone(s); " +
+			"the loss of a single zone could cost the controller its quorum",
+	})
+}
+
+func (s *clientSuite) TestEnableHAPlacementIgnoresZones(c *gc.C) {
+	s.setAvailabilityZones(network.AvailabilityZones{
+		&fakeZone{name: "zone1", available: true},
+	})
+
+	enableHAResult, err := s.enableHA(c, 3, emptyCons, []string{"valid"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableHAResult.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	c.Assert(enableHAResult.Zones, gc.HasLen, 0)
+	c.Assert(enableHAResult.Warnings, gc.HasLen, 0)
+}
+
 func (s *clientSuite) TestBlockMakeHA(c *gc.C) {
 	// Block all changes.
 	s.BlockAllChanges(c, "TestBlockEnableHA")
@@ -597,3 +715,16 @@ func (s *clientSuite) TestHighAvailabilityCAASFails(c *gc.C) {
 	})
 	c.Assert(err, gc.ErrorMatches, "high availability on kubernetes controllers not supported")
 }
+
+type fakeZone struct {
+	name      string
+	available bool
+}
+
+func (z *fakeZone) Name() string {
+	return z.name
+}
+
+func (z *fakeZone) Available() bool {
+	return z.available
+}
@@ -10,7 +10,12 @@ import (
 
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 // Register is called to expose a package of facades onto a given registry.
@@ -37,9 +42,22 @@ func newHighAvailabilityAPI(ctx facade.Context) (*HighAvailabilityAPI, error) {
 		return nil, errors.NotSupportedf("high availability on kubernetes controllers")
 	}
 
+	availabilityZones := func() (network.AvailabilityZones, error) {
+		env, err := stateenvirons.GetNewEnvironFunc(environs.New)(model)
+		if err != nil {
+			return nil, errors.Annotate(err, "opening environment")
+		}
+		zonedEnv, ok := env.(providercommon.ZonedEnviron)
+		if !ok {
+			return nil, errors.NotSupportedf("availability zones")
+		}
+		return zonedEnv.AvailabilityZones(context.CallContext(st))
+	}
+
 	return &HighAvailabilityAPI{
-		state:      st,
-		resources:  ctx.Resources(),
-		authorizer: authorizer,
+		state:             st,
+		resources:         ctx.Resources(),
+		authorizer:        authorizer,
+		availabilityZones: availabilityZones,
 	}, nil
 }
@@ -22,3 +22,5 @@ func NewTestCharmhubSeriesValidator(client CharmhubClient) charmhubSeriesValidat
 		client: client,
 	}
 }
+
+var MachineUserData = machineUserData
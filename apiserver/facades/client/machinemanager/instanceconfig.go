@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/names/v5"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
@@ -31,15 +32,6 @@ type InstanceConfigBackend interface {
 // is exposed for testing purposes.
 // TODO(rog) fix environs/manual tests so they do not need to call this, or move this elsewhere.
 func InstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineId, nonce, dataDir string) (*instancecfg.InstanceConfig, error) {
-	model, err := st.Model()
-	if err != nil {
-		return nil, errors.Annotate(err, "getting state model")
-	}
-	modelConfig, err := model.Config()
-	if err != nil {
-		return nil, errors.Annotate(err, "getting model config")
-	}
-
 	// Get the machine so we can get its series and arch.
 	// If the Arch is not set in hardware-characteristics,
 	// an error is returned.
@@ -54,6 +46,71 @@ func InstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineI
 	if hc.Arch == nil {
 		return nil, fmt.Errorf("arch is not set for %q", machine.Tag())
 	}
+	base, err := corebase.ParseBase(machine.Base().OS, machine.Base().Channel)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting machine base")
+	}
+	return instanceConfig(ctrlSt, st, instanceConfigParams{
+		machineId: machineId,
+		nonce:     nonce,
+		dataDir:   dataDir,
+		base:      base,
+		arch:      *hc.Arch,
+		setupAuthentication: func(apiInfo *api.Info) (*api.Info, error) {
+			apiInfo, err := authentication.SetupAuthentication(machine, apiInfo)
+			return apiInfo, errors.Annotate(err, "setting up machine authentication")
+		},
+	})
+}
+
+// Placeholders used in previewed instance configs in place of the
+// secrets and values only known when the instance is provisioned.
+const (
+	previewPassword = "<password>"
+	previewNonce    = "<nonce>"
+)
+
+// previewInstanceConfig returns the instance config for a machine with
+// the given ID, base and architecture, without changing the machine. The
+// machine's password and nonce are replaced with placeholders.
+func previewInstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineId string, base corebase.Base, arch string) (*instancecfg.InstanceConfig, error) {
+	return instanceConfig(ctrlSt, st, instanceConfigParams{
+		machineId: machineId,
+		nonce:     previewNonce,
+		base:      base,
+		arch:      arch,
+		setupAuthentication: func(apiInfo *api.Info) (*api.Info, error) {
+			apiInfoCopy := *apiInfo
+			apiInfoCopy.Tag = names.NewMachineTag(machineId)
+			apiInfoCopy.Password = previewPassword
+			return &apiInfoCopy, nil
+		},
+	})
+}
+
+// instanceConfigParams holds the machine specific parameters of an
+// instance config.
+type instanceConfigParams struct {
+	machineId string
+	nonce     string
+	dataDir   string
+	base      corebase.Base
+	arch      string
+
+	// setupAuthentication returns the API info with which the machine
+	// agent connects to the controller.
+	setupAuthentication func(*api.Info) (*api.Info, error)
+}
+
+func instanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, args instanceConfigParams) (*instancecfg.InstanceConfig, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting state model")
+	}
+	modelConfig, err := model.Config()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model config")
+	}
 
 	// Find the appropriate tools information.
 	agentVersion, ok := modelConfig.AgentVersion()
@@ -68,8 +125,8 @@ func InstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineI
 	toolsFinder := common.NewToolsFinder(configGetter, st, urlGetter, newEnviron)
 	toolsList, err := toolsFinder.FindAgents(common.FindAgentsParams{
 		Number: agentVersion,
-		OSType: machine.Base().OS,
-		Arch:   *hc.Arch,
+		OSType: args.base.OS,
+		Arch:   args.arch,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "finding agent binaries")
@@ -98,17 +155,13 @@ func InstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineI
 		ModelTag: model.ModelTag(),
 	}
 
-	apiInfo, err = authentication.SetupAuthentication(machine, apiInfo)
+	apiInfo, err = args.setupAuthentication(apiInfo)
 	if err != nil {
-		return nil, errors.Annotate(err, "setting up machine authentication")
+		return nil, errors.Trace(err)
 	}
 
-	base, err := corebase.ParseBase(machine.Base().OS, machine.Base().Channel)
-	if err != nil {
-		return nil, errors.Annotate(err, "getting machine base")
-	}
-	icfg, err := instancecfg.NewInstanceConfig(ctrlSt.ControllerTag(), machineId, nonce, modelConfig.ImageStream(),
-		base, apiInfo,
+	icfg, err := instancecfg.NewInstanceConfig(ctrlSt.ControllerTag(), args.machineId, args.nonce, modelConfig.ImageStream(),
+		args.base, apiInfo,
 	)
 	if err != nil {
 		return nil, errors.Annotate(err, "initializing instance config")
@@ -119,8 +172,8 @@ func InstanceConfig(ctrlSt ControllerBackend, st InstanceConfigBackend, machineI
 		icfg.ControllerConfig[k] = v
 	}
 
-	if dataDir != "" {
-		icfg.DataDir = dataDir
+	if args.dataDir != "" {
+		icfg.DataDir = args.dataDir
 	}
 	if err := icfg.SetTools(toolsList); err != nil {
 		return nil, errors.Trace(err)
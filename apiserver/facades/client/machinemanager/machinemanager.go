@@ -82,6 +82,10 @@ type MachineManagerAPI struct {
 }
 
 type MachineManagerV9 struct {
+	*MachineManagerV10
+}
+
+type MachineManagerV10 struct {
 	*MachineManagerAPI
 }
 
@@ -93,13 +97,25 @@ func NewFacadeV9(ctx facade.Context) (*MachineManagerV9, error) {
 		return nil, err
 	}
 	return &MachineManagerV9{
-		MachineManagerAPI: api,
+		MachineManagerV10: api,
 	}, nil
 }
 
 // NewFacadeV10 create a new server-side MachineManager API facade. This
 // is used for facade registration.
-func NewFacadeV10(ctx facade.Context) (*MachineManagerAPI, error) {
+func NewFacadeV10(ctx facade.Context) (*MachineManagerV10, error) {
+	api, err := NewFacadeV11(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &MachineManagerV10{
+		MachineManagerAPI: api,
+	}, nil
+}
+
+// NewFacadeV11 create a new server-side MachineManager API facade. This
+// is used for facade registration.
+func NewFacadeV11(ctx facade.Context) (*MachineManagerAPI, error) {
 	st := ctx.State()
 	model, err := st.Model()
 	if err != nil {
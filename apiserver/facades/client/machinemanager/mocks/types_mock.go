@@ -190,6 +190,21 @@ func (mr *MockBackendMockRecorder) Model() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Model", reflect.TypeOf((*MockBackend)(nil).Model))
 }
 
+// Sequences mocks base method.
+func (m *MockBackend) Sequences() (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sequences")
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sequences indicates an expected call of Sequences.
+func (mr *MockBackendMockRecorder) Sequences() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sequences", reflect.TypeOf((*MockBackend)(nil).Sequences))
+}
+
 // ToolsStorage mocks base method.
 func (m *MockBackend) ToolsStorage() (binarystorage.StorageCloser, error) {
 	m.ctrl.T.Helper()
@@ -44,6 +44,7 @@ type Backend interface {
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
 	ToolsStorage() (binarystorage.StorageCloser, error)
+	Sequences() (map[string]int, error)
 }
 
 type BackendState interface {
@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v5"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/os/ostype"
+	"github.com/juju/juju/environs"
+	environscloudspec "github.com/juju/juju/environs/cloudspec"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// MachineUserData returns the userdata a provider would receive when
+// starting an instance for each of the machines, without changing them.
+// An argument without a machine tag previews the userdata of a new
+// machine, as add-machine would add it with the base and constraints.
+func (mm *MachineManagerAPI) MachineUserData(args params.MachineUserDataParams) (params.MachineUserDataResults, error) {
+	return machineUserData(mm, environs.GetEnviron, args)
+}
+
+// MachineUserData isn't on the v10 API.
+func (*MachineManagerV10) MachineUserData(_, _ struct{}) {}
+
+func machineUserData(mm *MachineManagerAPI, getEnviron environGetFunc, args params.MachineUserDataParams) (params.MachineUserDataResults, error) {
+	if err := mm.authorizer.CanWrite(); err != nil {
+		return params.MachineUserDataResults{}, err
+	}
+	model, err := mm.st.Model()
+	if err != nil {
+		return params.MachineUserDataResults{}, errors.Trace(err)
+	}
+	if model.Type() == state.ModelTypeCAAS {
+		return params.MachineUserDataResults{}, errors.NotSupportedf("userdata on kubernetes models")
+	}
+	ctrlSt, err := mm.pool.SystemState()
+	if err != nil {
+		return params.MachineUserDataResults{}, errors.Trace(err)
+	}
+
+	backend := common.EnvironConfigGetterFuncs{
+		CloudSpecFunc: func() (environscloudspec.CloudSpec, error) {
+			return stateenvirons.CloudSpecForModel(model)
+		},
+		ModelConfigFunc: model.Config,
+	}
+	env, err := getEnviron(backend, environs.New)
+	if err != nil {
+		return params.MachineUserDataResults{}, errors.Trace(err)
+	}
+	var renderer renderers.ProviderRenderer = cloudConfigRenderer{}
+	if userDataRenderer, ok := env.(environs.UserDataRenderer); ok {
+		renderer = userDataRenderer.UserDataRenderer()
+	}
+
+	results := make([]params.MachineUserDataResult, len(args.Machines))
+	for i, arg := range args.Machines {
+		icfg, err := mm.previewInstanceConfig(ctrlSt, model, arg)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(errors.Annotate(err, "getting instance config"))
+			continue
+		}
+		results[i].UserData, err = providerinit.ComposeUserData(icfg, nil, renderer)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(errors.Annotate(err, "composing userdata"))
+			continue
+		}
+		icfg.CloudInitUserData = nil
+		results[i].BaseUserData, err = providerinit.ComposeUserData(icfg, nil, renderer)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(errors.Annotate(err, "composing userdata"))
+		}
+	}
+	return params.MachineUserDataResults{Results: results}, nil
+}
+
+// previewInstanceConfig returns the instance config of the machine
+// identified by the argument, or of the machine which would be added
+// for it.
+func (mm *MachineManagerAPI) previewInstanceConfig(
+	ctrlSt ControllerBackend, model Model, arg params.MachineUserDataParam,
+) (*instancecfg.InstanceConfig, error) {
+	if arg.MachineTag != "" {
+		tag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machine, err := mm.st.Machine(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		base, err := corebase.ParseBase(machine.Base().OS, machine.Base().Channel)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting machine base")
+		}
+		hc, err := machine.HardwareCharacteristics()
+		if err != nil && !errors.Is(err, errors.NotFound) {
+			return nil, errors.Annotate(err, "getting machine hardware characteristics")
+		}
+		machineArch := previewArch(arg.Constraints)
+		if hc != nil && hc.Arch != nil {
+			machineArch = *hc.Arch
+		}
+		return previewInstanceConfig(ctrlSt, mm.st, tag.Id(), base, machineArch)
+	}
+
+	var base corebase.Base
+	if arg.Base == nil {
+		conf, err := model.Config()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		base = config.PreferredBase(conf)
+	} else {
+		var err error
+		base, err = corebase.ParseBase(arg.Base.Name, arg.Base.Channel)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	// Preview the machine with the ID it would be given if it was
+	// added now.
+	sequences, err := mm.st.Sequences()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId := strconv.Itoa(sequences["machine"])
+	return previewInstanceConfig(ctrlSt, mm.st, machineId, base, previewArch(arg.Constraints))
+}
+
+// previewArch returns the architecture of a machine which isn't
+// provisioned yet.
+func previewArch(cons constraints.Value) string {
+	if cons.HasArch() {
+		return *cons.Arch
+	}
+	return arch.DefaultArchitecture
+}
+
+// cloudConfigRenderer renders userdata as cloud-init reads it, for
+// providers which don't encode it.
+type cloudConfigRenderer struct{}
+
+// Render implements renderers.ProviderRenderer.
+func (cloudConfigRenderer) Render(cfg cloudinit.CloudConfig, _ ostype.OSType) ([]byte, error) {
+	return renderers.RenderYAML(cfg)
+}
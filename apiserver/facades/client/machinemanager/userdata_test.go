@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/facades/client/machinemanager/mocks"
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type userDataSuite struct {
+	ProvisioningMachineManagerSuite
+}
+
+var _ = gc.Suite(&userDataSuite{})
+
+func (s *userDataSuite) expectUserData(ctrl *gomock.Controller) func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+	s.model.EXPECT().Type().Return(state.ModelTypeIAAS)
+	s.model.EXPECT().Config().Return(config.New(config.UseDefaults, dummy.SampleConfig().Merge(coretesting.Attrs{
+		"agent-version":      "2.6.6",
+		"cloudinit-userdata": "packages: [jq]\n",
+	}))).AnyTimes()
+
+	storageCloser := s.expectProvisioningStorageCloser(ctrl)
+	s.st.EXPECT().ToolsStorage().Return(storageCloser, nil)
+
+	s.ctrlSt.EXPECT().APIHostPortsForAgents().Return([]network.SpaceHostPorts{{{
+		SpaceAddress: network.NewSpaceAddress("0.2.4.6", network.WithScope(network.ScopeCloudLocal)),
+		NetPort:      1,
+	}}}, nil)
+
+	env := mocks.NewMockEnviron(ctrl)
+	return func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return env, nil
+	}
+}
+
+func (s *userDataSuite) TestMachineUserData(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	getEnviron := s.expectUserData(ctrl)
+	// The machine's password must not be changed by a preview, so
+	// SetPassword isn't expected.
+	machine0 := s.expectProvisioningMachine(ctrl, nil)
+	s.st.EXPECT().Machine("0").Return(machine0, nil)
+
+	result, err := machinemanager.MachineUserData(s.api, getEnviron, params.MachineUserDataParams{
+		Machines: []params.MachineUserDataParam{{MachineTag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+
+	userData := string(result.Results[0].UserData)
+	c.Check(userData, jc.Contains, "machine-0")
+	c.Check(userData, jc.Contains, "oldpassword: <password>")
+	c.Check(userData, jc.Contains, "- jq")
+	c.Check(string(result.Results[0].BaseUserData), gc.Not(jc.Contains), "- jq")
+	c.Check(cloudinit.ValidateUserData(result.Results[0].UserData), jc.ErrorIsNil)
+}
+
+func (s *userDataSuite) TestMachineUserDataNewMachine(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	getEnviron := s.expectUserData(ctrl)
+	s.st.EXPECT().Sequences().Return(map[string]int{"machine": 3}, nil)
+
+	result, err := machinemanager.MachineUserData(s.api, getEnviron, params.MachineUserDataParams{
+		Machines: []params.MachineUserDataParam{{
+			Base:        &params.Base{Name: "ubuntu", Channel: "22.04"},
+			Constraints: constraints.MustParse("arch=amd64"),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Check(string(result.Results[0].UserData), jc.Contains, "machine-3")
+}
+
+func (s *userDataSuite) TestMachineUserDataCAAS(c *gc.C) {
+	ctrl := s.setup(c)
+	defer ctrl.Finish()
+
+	s.model.EXPECT().Type().Return(state.ModelTypeCAAS)
+
+	_, err := machinemanager.MachineUserData(s.api, nil, params.MachineUserDataParams{
+		Machines: []params.MachineUserDataParam{{MachineTag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
@@ -126,6 +126,20 @@ func (mr *MockStateMockRecorder) AbortCurrentUpgrade() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortCurrentUpgrade", reflect.TypeOf((*MockState)(nil).AbortCurrentUpgrade))
 }
 
+// AbortUpgradeRollout mocks base method.
+func (m *MockState) AbortUpgradeRollout() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortUpgradeRollout")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortUpgradeRollout indicates an expected call of AbortUpgradeRollout.
+func (mr *MockStateMockRecorder) AbortUpgradeRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortUpgradeRollout", reflect.TypeOf((*MockState)(nil).AbortUpgradeRollout))
+}
+
 // AllCharmURLs mocks base method.
 func (m *MockState) AllCharmURLs() ([]*string, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +249,20 @@ func (mr *MockStateMockRecorder) MongoCurrentStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MongoCurrentStatus", reflect.TypeOf((*MockState)(nil).MongoCurrentStatus))
 }
 
+// PauseUpgradeRollout mocks base method.
+func (m *MockState) PauseUpgradeRollout() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseUpgradeRollout")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseUpgradeRollout indicates an expected call of PauseUpgradeRollout.
+func (mr *MockStateMockRecorder) PauseUpgradeRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseUpgradeRollout", reflect.TypeOf((*MockState)(nil).PauseUpgradeRollout))
+}
+
 // Release mocks base method.
 func (m *MockState) Release() bool {
 	m.ctrl.T.Helper()
@@ -249,6 +277,20 @@ func (mr *MockStateMockRecorder) Release() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockState)(nil).Release))
 }
 
+// ResumeUpgradeRollout mocks base method.
+func (m *MockState) ResumeUpgradeRollout() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeUpgradeRollout")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeUpgradeRollout indicates an expected call of ResumeUpgradeRollout.
+func (mr *MockStateMockRecorder) ResumeUpgradeRollout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeUpgradeRollout", reflect.TypeOf((*MockState)(nil).ResumeUpgradeRollout))
+}
+
 // SetModelAgentVersion mocks base method.
 func (m *MockState) SetModelAgentVersion(arg0 version.Number, arg1 *string, arg2 bool) error {
 	m.ctrl.T.Helper()
@@ -263,6 +305,21 @@ func (mr *MockStateMockRecorder) SetModelAgentVersion(arg0, arg1, arg2 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetModelAgentVersion", reflect.TypeOf((*MockState)(nil).SetModelAgentVersion), arg0, arg1, arg2)
 }
 
+// StartUpgradeRollout mocks base method.
+func (m *MockState) StartUpgradeRollout(arg0 state.UpgradeRolloutArgs) (*state.UpgradeRollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartUpgradeRollout", arg0)
+	ret0, _ := ret[0].(*state.UpgradeRollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartUpgradeRollout indicates an expected call of StartUpgradeRollout.
+func (mr *MockStateMockRecorder) StartUpgradeRollout(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUpgradeRollout", reflect.TypeOf((*MockState)(nil).StartUpgradeRollout), arg0)
+}
+
 // MockModel is a mock of Model interface.
 type MockModel struct {
 	ctrl     *gomock.Controller
@@ -22,11 +22,23 @@ import (
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("ModelUpgrader", 1, func(ctx facade.Context) (facade.Facade, error) {
 		return newFacadeV1(ctx)
+	}, reflect.TypeOf((*ModelUpgraderAPIV1)(nil)))
+	registry.MustRegister("ModelUpgrader", 2, func(ctx facade.Context) (facade.Facade, error) {
+		return newFacadeV2(ctx) // adds staged upgrades
 	}, reflect.TypeOf((*ModelUpgraderAPI)(nil)))
 }
 
 // newFacadeV1 is used for API registration.
-func newFacadeV1(ctx facade.Context) (*ModelUpgraderAPI, error) {
+func newFacadeV1(ctx facade.Context) (*ModelUpgraderAPIV1, error) {
+	api, err := newFacadeV2(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ModelUpgraderAPIV1{ModelUpgraderAPI: api}, nil
+}
+
+// newFacadeV2 is used for API registration.
+func newFacadeV2(ctx facade.Context) (*ModelUpgraderAPI, error) {
 	st := ctx.State()
 	pool := ctx.StatePool()
 	auth := ctx.Auth()
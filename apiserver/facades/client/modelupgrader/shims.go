@@ -32,6 +32,10 @@ type State interface {
 	MongoCurrentStatus() (*replicaset.Status, error)
 	SetModelAgentVersion(newVersion version.Number, stream *string, ignoreAgentVersions bool) error
 	AbortCurrentUpgrade() error
+	StartUpgradeRollout(args state.UpgradeRolloutArgs) (*state.UpgradeRollout, error)
+	PauseUpgradeRollout() error
+	ResumeUpgradeRollout() error
+	AbortUpgradeRollout() error
 	ControllerConfig() (controller.Config, error)
 	AllCharmURLs() ([]*string, error)
 }
@@ -45,6 +45,21 @@ type ModelUpgraderAPI struct {
 	environscloudspecGetter func(names.ModelTag) (environscloudspec.CloudSpec, error)
 }
 
+// ModelUpgraderAPIV1 is version 1 of the ModelUpgrader API, without
+// staged upgrades.
+type ModelUpgraderAPIV1 struct {
+	*ModelUpgraderAPI
+}
+
+// PauseUpgradeRollout isn't on the v1 API.
+func (*ModelUpgraderAPIV1) PauseUpgradeRollout(_, _ struct{}) {}
+
+// ResumeUpgradeRollout isn't on the v1 API.
+func (*ModelUpgraderAPIV1) ResumeUpgradeRollout(_, _ struct{}) {}
+
+// AbortUpgradeRollout isn't on the v1 API.
+func (*ModelUpgraderAPIV1) AbortUpgradeRollout(_, _ struct{}) {}
+
 // NewModelUpgraderAPI creates a new api server endpoint for managing
 // models.
 func NewModelUpgraderAPI(
@@ -229,10 +244,27 @@ func (m *ModelUpgraderAPI) UpgradeModel(arg params.UpgradeModelParams) (result p
 		result.Error = apiservererrors.ServerError(err)
 		return result, nil
 	}
+	if arg.Stages != nil {
+		if err := validateStagedUpgrade(model, arg); err != nil {
+			result.Error = apiservererrors.ServerError(err)
+			return result, nil
+		}
+	}
 	if arg.DryRun {
 		return result, nil
 	}
 
+	if arg.Stages != nil {
+		_, err := st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+			ToVersion:           targetVersion,
+			Canaries:            arg.Stages.Canaries,
+			BatchSize:           arg.Stages.BatchSize,
+			ErrorThreshold:      arg.Stages.ErrorThreshold,
+			IgnoreAgentVersions: arg.IgnoreAgentVersions,
+		})
+		return result, errors.Trace(err)
+	}
+
 	var agentStream *string
 	if arg.AgentStream != "" {
 		agentStream = &arg.AgentStream
@@ -243,6 +275,63 @@ func (m *ModelUpgraderAPI) UpgradeModel(arg params.UpgradeModelParams) (result p
 	return result, nil
 }
 
+// validateStagedUpgrade checks that the model's machines can be upgraded
+// in stages.
+func validateStagedUpgrade(model Model, arg params.UpgradeModelParams) error {
+	if model.IsControllerModel() {
+		return errors.NotSupportedf("staged upgrade of the controller model")
+	}
+	if model.Type() != state.ModelTypeIAAS {
+		return errors.NotSupportedf("staged upgrade of a %s model", model.Type())
+	}
+	if arg.AgentStream != "" {
+		// The agent stream is only changed once all machines are
+		// upgraded, so agent binaries couldn't be found for the
+		// machines released before then.
+		return errors.NotSupportedf("changing the agent stream in a staged upgrade")
+	}
+	return errors.Trace(state.UpgradeRolloutArgs{
+		BatchSize:      arg.Stages.BatchSize,
+		ErrorThreshold: arg.Stages.ErrorThreshold,
+	}.Validate())
+}
+
+// PauseUpgradeRollout stops a staged model upgrade upgrading more
+// machines until it is resumed.
+func (m *ModelUpgraderAPI) PauseUpgradeRollout(arg params.ModelParam) error {
+	return m.changeUpgradeRollout(arg, State.PauseUpgradeRollout)
+}
+
+// ResumeUpgradeRollout resumes a paused or halted staged model upgrade.
+func (m *ModelUpgraderAPI) ResumeUpgradeRollout(arg params.ModelParam) error {
+	return m.changeUpgradeRollout(arg, State.ResumeUpgradeRollout)
+}
+
+// AbortUpgradeRollout abandons a staged model upgrade. Machines already
+// upgraded keep running the new version.
+func (m *ModelUpgraderAPI) AbortUpgradeRollout(arg params.ModelParam) error {
+	return m.changeUpgradeRollout(arg, State.AbortUpgradeRollout)
+}
+
+func (m *ModelUpgraderAPI) changeUpgradeRollout(arg params.ModelParam, change func(State) error) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := m.canUpgrade(modelTag); err != nil {
+		return errors.Trace(err)
+	}
+	if err := m.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	st, err := m.statePool.Get(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	return errors.Trace(change(st))
+}
+
 func preCheckEnvironForUpgradeModel(
 	ctx context.ProviderCallContext, env environs.BootstrapEnviron,
 	controllerModel bool, currentVersion, targetVersion version.Number,
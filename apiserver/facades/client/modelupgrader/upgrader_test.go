@@ -548,7 +548,7 @@ cannot upgrade to "3.9.99" due to issues with these models:
 - LXD version has to be at least "5.0.0", but current version is only "4.0.0"`[1:])
 }
 
-func (s *modelUpgradeSuite) assertUpgradeModelJuju3(c *gc.C, ctrlModelVers string, dryRun bool) {
+func (s *modelUpgradeSuite) assertUpgradeModelJuju3(c *gc.C, ctrlModelVers string, dryRun bool, stages *params.UpgradeStages) {
 	ctrl, api := s.getModelUpgraderAPI(c)
 	defer ctrl.Finish()
 
@@ -603,9 +603,20 @@ func (s *modelUpgradeSuite) assertUpgradeModelJuju3(c *gc.C, ctrlModelVers strin
 	serverFactory.EXPECT().RemoteServer(s.cloudSpec).Return(server, nil)
 	server.EXPECT().ServerVersion().Return("5.2")
 
-	if !dryRun {
+	if stages != nil {
+		model.EXPECT().Type().Return(state.ModelTypeIAAS)
+	}
+	if !dryRun && stages == nil {
 		st.EXPECT().SetModelAgentVersion(version.MustParse("3.9.99"), nil, false).Return(nil)
 	}
+	if !dryRun && stages != nil {
+		st.EXPECT().StartUpgradeRollout(state.UpgradeRolloutArgs{
+			ToVersion:      version.MustParse("3.9.99"),
+			Canaries:       stages.Canaries,
+			BatchSize:      stages.BatchSize,
+			ErrorThreshold: stages.ErrorThreshold,
+		}).Return(nil, nil)
+	}
 
 	result, err := api.UpgradeModel(
 		params.UpgradeModelParams{
@@ -613,6 +624,7 @@ func (s *modelUpgradeSuite) assertUpgradeModelJuju3(c *gc.C, ctrlModelVers strin
 			TargetVersion: version.MustParse("3.9.99"),
 			AgentStream:   agentStream,
 			DryRun:        dryRun,
+			Stages:        stages,
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -622,15 +634,27 @@ func (s *modelUpgradeSuite) assertUpgradeModelJuju3(c *gc.C, ctrlModelVers strin
 }
 
 func (s *modelUpgradeSuite) TestUpgradeModelJuju3(c *gc.C) {
-	s.assertUpgradeModelJuju3(c, "3.10.0", false)
+	s.assertUpgradeModelJuju3(c, "3.10.0", false, nil)
 }
 
 func (s *modelUpgradeSuite) TestUpgradeModelJuju3SameAsController(c *gc.C) {
-	s.assertUpgradeModelJuju3(c, "3.9.99", false)
+	s.assertUpgradeModelJuju3(c, "3.9.99", false, nil)
 }
 
 func (s *modelUpgradeSuite) TestUpgradeModelJuju3DryRun(c *gc.C) {
-	s.assertUpgradeModelJuju3(c, "3.10.0", true)
+	s.assertUpgradeModelJuju3(c, "3.10.0", true, nil)
+}
+
+func (s *modelUpgradeSuite) TestUpgradeModelJuju3Staged(c *gc.C) {
+	s.assertUpgradeModelJuju3(c, "3.10.0", false, &params.UpgradeStages{
+		Canaries:       []string{"0", "mysql/1"},
+		BatchSize:      3,
+		ErrorThreshold: 10,
+	})
+}
+
+func (s *modelUpgradeSuite) TestUpgradeModelJuju3StagedDryRun(c *gc.C) {
+	s.assertUpgradeModelJuju3(c, "3.10.0", true, &params.UpgradeStages{BatchSize: 1})
 }
 
 func (s *modelUpgradeSuite) TestUpgradeModelJuju3Failed(c *gc.C) {
@@ -757,6 +781,40 @@ func (s *modelUpgradeSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *modelUpgradeSuite) TestPauseUpgradeRollout(c *gc.C) {
+	ctrl, api := s.getModelUpgraderAPI(c)
+	defer ctrl.Finish()
+
+	modelUUID := coretesting.ModelTag.Id()
+	st := mocks.NewMockState(ctrl)
+	st.EXPECT().Release()
+
+	gomock.InOrder(
+		s.blockChecker.EXPECT().ChangeAllowed().Return(nil),
+		s.statePool.EXPECT().Get(modelUUID).Return(st, nil),
+		st.EXPECT().PauseUpgradeRollout().Return(nil),
+	)
+	err := api.PauseUpgradeRollout(params.ModelParam{ModelTag: coretesting.ModelTag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelUpgradeSuite) TestAbortUpgradeRolloutNotStarted(c *gc.C) {
+	ctrl, api := s.getModelUpgraderAPI(c)
+	defer ctrl.Finish()
+
+	modelUUID := coretesting.ModelTag.Id()
+	st := mocks.NewMockState(ctrl)
+	st.EXPECT().Release()
+
+	gomock.InOrder(
+		s.blockChecker.EXPECT().ChangeAllowed().Return(nil),
+		s.statePool.EXPECT().Get(modelUUID).Return(st, nil),
+		st.EXPECT().AbortUpgradeRollout().Return(errors.NotFoundf("staged model upgrade")),
+	)
+	err := api.AbortUpgradeRollout(params.ModelParam{ModelTag: coretesting.ModelTag.String()})
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+}
+
 func (s *modelUpgradeSuite) TestFindToolsIAAS(c *gc.C) {
 	ctrl, api := s.getModelUpgraderAPI(c)
 	defer ctrl.Finish()
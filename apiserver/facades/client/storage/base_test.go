@@ -108,6 +108,8 @@ const (
 	destroyStorageInstanceCall              = "destroyStorageInstance"
 	releaseStorageInstanceCall              = "releaseStorageInstance"
 	addExistingFilesystemCall               = "addExistingFilesystem"
+	setVolumeInfoCall                       = "setVolumeInfo"
+	setFilesystemInfoCall                   = "setFilesystemInfo"
 )
 
 func (s *baseStorageSuite) constructState() *mockState {
@@ -316,6 +318,14 @@ func (s *baseStorageSuite) constructStorageAccessor() *mockStorageAccessor {
 			s.stub.AddCall(addExistingFilesystemCall, f, v, storageName)
 			return s.storageTag, s.stub.NextErr()
 		},
+		setVolumeInfo: func(tag names.VolumeTag, info state.VolumeInfo) error {
+			s.stub.AddCall(setVolumeInfoCall, tag, info)
+			return s.stub.NextErr()
+		},
+		setFilesystemInfo: func(tag names.FilesystemTag, info state.FilesystemInfo) error {
+			s.stub.AddCall(setFilesystemInfoCall, tag, info)
+			return s.stub.NextErr()
+		},
 	}
 }
 
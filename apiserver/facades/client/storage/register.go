@@ -22,10 +22,22 @@ import (
 // Register is called to expose a package of facades onto a given registry.
 func Register(registry facade.FacadeRegistry) {
 	registry.MustRegister("Storage", 6, func(ctx facade.Context) (facade.Facade, error) {
-		return newStorageAPI(ctx) // modify Remove to support force and maxWait; add DetachStorage to support force and maxWait.
+		return newStorageAPIv6(ctx) // modify Remove to support force and maxWait; add DetachStorage to support force and maxWait.
+	}, reflect.TypeOf((*StorageAPIv6)(nil)))
+	registry.MustRegister("Storage", 7, func(ctx facade.Context) (facade.Facade, error) {
+		return newStorageAPI(ctx) // add Resize, CreateSnapshot, ListSnapshots, ExportSnapshot and RestoreSnapshot.
 	}, reflect.TypeOf((*StorageAPI)(nil)))
 }
 
+// newStorageAPIv6 returns a new v6 storage API facade.
+func newStorageAPIv6(ctx facade.Context) (*StorageAPIv6, error) {
+	api, err := newStorageAPI(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &StorageAPIv6{api}, nil
+}
+
 // newStorageAPI returns a new storage API facade.
 func newStorageAPI(ctx facade.Context) (*StorageAPI, error) {
 	st := ctx.State()
@@ -75,6 +75,9 @@ type storageVolume interface {
 	// Volume is required for volume functionality.
 	Volume(tag names.VolumeTag) (state.Volume, error)
 
+	// SetVolumeInfo records the provisioned details of a volume.
+	SetVolumeInfo(tag names.VolumeTag, info state.VolumeInfo) error
+
 	// AddExistingFilesystem imports an existing filesystem into the model.
 	AddExistingFilesystem(f state.FilesystemInfo, v *state.VolumeInfo, storageName string) (names.StorageTag, error)
 }
@@ -94,6 +97,9 @@ type storageFile interface {
 	// Filesystem is required for filesystem functionality.
 	Filesystem(tag names.FilesystemTag) (state.Filesystem, error)
 
+	// SetFilesystemInfo records the provisioned details of a filesystem.
+	SetFilesystemInfo(tag names.FilesystemTag, info state.FilesystemInfo) error
+
 	// AddExistingFilesystem imports an existing filesystem into the model.
 	AddExistingFilesystem(f state.FilesystemInfo, v *state.VolumeInfo, storageName string) (names.StorageTag, error)
 }
@@ -27,7 +27,7 @@ import (
 
 type storageMetadataFunc func() (poolmanager.PoolManager, storage.ProviderRegistry, error)
 
-// StorageAPI implements the latest version (v6) of the Storage API.
+// StorageAPI implements the latest version (v7) of the Storage API.
 type StorageAPI struct {
 	backend         backend
 	storageAccess   storageAccess
@@ -37,6 +37,12 @@ type StorageAPI struct {
 	modelType       state.ModelType
 }
 
+// StorageAPIv6 implements v6 of the Storage API, which doesn't
+// support resizing or snapshots.
+type StorageAPIv6 struct {
+	*StorageAPI
+}
+
 func NewStorageAPI(
 	backend backend,
 	modelType state.ModelType,
@@ -739,17 +745,8 @@ func (a *StorageAPI) importStorage(arg params.ImportStorageParams) (*params.Impo
 		return nil, errors.Trace(err)
 	}
 
-	cfg, err := pm.Get(arg.Pool)
-	if errors.IsNotFound(err) {
-		cfg, err = storage.NewConfig(
-			arg.Pool,
-			storage.ProviderType(arg.Pool),
-			map[string]interface{}{},
-		)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-	} else if err != nil {
+	cfg, err := poolConfig(pm, arg.Pool)
+	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	provider, err := registry.StorageProvider(cfg.Provider())
@@ -759,6 +756,20 @@ func (a *StorageAPI) importStorage(arg params.ImportStorageParams) (*params.Impo
 	return a.importFilesystem(arg, provider, cfg)
 }
 
+// poolConfig returns the configuration of the named storage pool. If
+// there is no such pool, the name is taken to be a provider type.
+func poolConfig(pm poolmanager.PoolManager, pool string) (*storage.Config, error) {
+	cfg, err := pm.Get(pool)
+	if errors.IsNotFound(err) {
+		cfg, err = storage.NewConfig(
+			pool,
+			storage.ProviderType(pool),
+			map[string]interface{}{},
+		)
+	}
+	return cfg, errors.Trace(err)
+}
+
 func (a *StorageAPI) importFilesystem(
 	arg params.ImportStorageParams,
 	provider storage.Provider,
@@ -827,6 +838,307 @@ func (a *StorageAPI) importFilesystem(
 	}, nil
 }
 
+// Resize grows the volumes backing the specified storage instances.
+func (a *StorageAPI) Resize(args params.ResizeStorageParams) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Storage))
+	for i, arg := range args.Storage {
+		if err := a.resizeStorage(arg); err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *StorageAPI) resizeStorage(arg params.ResizeStorageParam) error {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if arg.Size == 0 {
+		return errors.NotValidf("storage size 0")
+	}
+	volumeId, source, cfg, err := a.storageVolumeSource(tag, "resizing")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resizer, ok := source.(storage.VolumeResizer)
+	if !ok {
+		return errors.NotSupportedf("resizing volume with storage provider %q", cfg.Provider())
+	}
+	if err := resizer.ResizeVolume(a.callContext, volumeId, arg.Size); err != nil {
+		return errors.Annotate(err, "resizing volume")
+	}
+	return errors.Annotate(a.recordStorageSize(tag, arg.Size), "recording resized storage")
+}
+
+// recordStorageSize updates the sizes recorded in the model for the volume
+// backing the storage instance, and any filesystem on it, so that they match
+// the size the volume was resized to.
+func (a *StorageAPI) recordStorageSize(tag names.StorageTag, size uint64) error {
+	volume, err := a.storageAccess.StorageInstanceVolume(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	volumeInfo, err := volume.Info()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if volumeInfo.Size < size {
+		volumeInfo.Size = size
+		if err := a.storageAccess.SetVolumeInfo(volume.VolumeTag(), volumeInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	filesystem, err := a.storageAccess.StorageInstanceFilesystem(tag)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	filesystemInfo, err := filesystem.Info()
+	if errors.IsNotProvisioned(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if filesystemInfo.Size >= size {
+		return nil
+	}
+	filesystemInfo.Size = size
+	return errors.Trace(a.storageAccess.SetFilesystemInfo(filesystem.FilesystemTag(), filesystemInfo))
+}
+
+// CreateSnapshot takes snapshots of the volumes backing the specified
+// storage instances.
+func (a *StorageAPI) CreateSnapshot(args params.StorageSnapshotParams) (params.StorageSnapshotResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.StorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.StorageSnapshotResult, len(args.Snapshots))
+	for i, arg := range args.Snapshots {
+		snapshot, err := a.createSnapshot(arg)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results[i].Result = snapshot
+	}
+	return params.StorageSnapshotResults{Results: results}, nil
+}
+
+func (a *StorageAPI) createSnapshot(arg params.StorageSnapshotParam) (*params.StorageSnapshot, error) {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if arg.Name == "" {
+		return nil, errors.NotValidf("empty snapshot name")
+	}
+	volumeId, source, cfg, err := a.storageVolumeSource(tag, "snapshotting")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshotting volume with storage provider %q", cfg.Provider())
+	}
+	snapshot, err := snapshotter.CreateVolumeSnapshot(a.callContext, volumeId, arg.Name)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating volume snapshot")
+	}
+	result := storageSnapshotFromVolumeSnapshot(tag, snapshot)
+	return &result, nil
+}
+
+// ListSnapshots returns the snapshots taken of the volumes backing the
+// specified storage instances.
+func (a *StorageAPI) ListSnapshots(args params.Entities) (params.StorageSnapshotsResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.StorageSnapshotsResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.StorageSnapshotsResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		snapshots, err := a.listSnapshots(arg.Tag)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results[i].Result = snapshots
+	}
+	return params.StorageSnapshotsResults{Results: results}, nil
+}
+
+func (a *StorageAPI) listSnapshots(storageTag string) ([]params.StorageSnapshot, error) {
+	tag, err := names.ParseStorageTag(storageTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeId, source, cfg, err := a.storageVolumeSource(tag, "listing snapshots of")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshotting volume with storage provider %q", cfg.Provider())
+	}
+	snapshots, err := snapshotter.ListVolumeSnapshots(a.callContext, volumeId)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing volume snapshots")
+	}
+	result := make([]params.StorageSnapshot, len(snapshots))
+	for i, snapshot := range snapshots {
+		result[i] = storageSnapshotFromVolumeSnapshot(tag, snapshot)
+	}
+	return result, nil
+}
+
+// ExportSnapshot creates new volumes from snapshots of the volumes
+// backing the specified storage instances. The new volumes are not
+// managed by Juju.
+func (a *StorageAPI) ExportSnapshot(args params.StorageSnapshotParams) (params.ExportStorageSnapshotResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ExportStorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ExportStorageSnapshotResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ExportStorageSnapshotResult, len(args.Snapshots))
+	for i, arg := range args.Snapshots {
+		volumeId, err := a.exportSnapshot(arg)
+		if err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results[i].VolumeId = volumeId
+	}
+	return params.ExportStorageSnapshotResults{Results: results}, nil
+}
+
+func (a *StorageAPI) exportSnapshot(arg params.StorageSnapshotParam) (string, error) {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	volumeId, source, cfg, err := a.storageVolumeSource(tag, "exporting snapshots of")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return "", errors.NotSupportedf("snapshotting volume with storage provider %q", cfg.Provider())
+	}
+	exported, err := snapshotter.ExportVolumeSnapshot(a.callContext, volumeId, arg.Name)
+	return exported, errors.Annotate(err, "exporting volume snapshot")
+}
+
+// RestoreSnapshot replaces the contents of the volumes backing the
+// specified storage instances with the named snapshots of them.
+func (a *StorageAPI) RestoreSnapshot(args params.StorageSnapshotParams) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Snapshots))
+	for i, arg := range args.Snapshots {
+		if err := a.restoreSnapshot(arg); err != nil {
+			results[i].Error = apiservererrors.ServerError(err)
+		}
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+func (a *StorageAPI) restoreSnapshot(arg params.StorageSnapshotParam) error {
+	tag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	volumeId, source, cfg, err := a.storageVolumeSource(tag, "restoring snapshots of")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return errors.NotSupportedf("snapshotting volume with storage provider %q", cfg.Provider())
+	}
+	err = snapshotter.RestoreVolumeSnapshot(a.callContext, volumeId, arg.Name)
+	return errors.Annotate(err, "restoring volume snapshot")
+}
+
+// storageVolumeSource returns the provider ID of the volume backing the
+// specified storage instance, along with the volume source and pool
+// configuration that manage it. The operation is used to describe
+// storage which isn't backed by a volume.
+func (a *StorageAPI) storageVolumeSource(
+	tag names.StorageTag, operation string,
+) (string, storage.VolumeSource, *storage.Config, error) {
+	volume, err := a.storageAccess.StorageInstanceVolume(tag)
+	if errors.IsNotFound(err) {
+		return "", nil, nil, errors.NotSupportedf("%s storage %q not backed by a volume", operation, tag.Id())
+	} else if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	info, err := volume.Info()
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+
+	pm, registry, err := a.storageMetadata()
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	cfg, err := poolConfig(pm, info.Pool)
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	provider, err := registry.StorageProvider(cfg.Provider())
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	source, err := provider.VolumeSource(cfg)
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	return info.VolumeId, source, cfg, nil
+}
+
+func storageSnapshotFromVolumeSnapshot(tag names.StorageTag, snapshot storage.VolumeSnapshot) params.StorageSnapshot {
+	result := params.StorageSnapshot{
+		StorageTag: tag.String(),
+		Name:       snapshot.Name,
+		Size:       snapshot.Size,
+		Ready:      snapshot.Ready,
+	}
+	if !snapshot.Created.IsZero() {
+		created := snapshot.Created
+		result.Created = &created
+	}
+	return result
+}
+
 // RemovePool deletes the named pool
 func (a *StorageAPI) RemovePool(p params.StoragePoolDeleteArgs) (params.ErrorResults, error) {
 	results := params.ErrorResults{
@@ -880,3 +1192,15 @@ func (a *StorageAPI) unitAssignedMachine(tag names.UnitTag) (names.MachineTag, e
 	}
 	return names.NewMachineTag(mid), nil
 }
+
+// Resize isn't on the v6 API.
+func (*StorageAPIv6) Resize(_ struct{}) {}
+
+// CreateSnapshot isn't on the v6 API.
+func (*StorageAPIv6) CreateSnapshot(_ struct{}) {}
+
+// ListSnapshots isn't on the v6 API.
+func (*StorageAPIv6) ListSnapshots(_ struct{}) {}
+
+// ExportSnapshot isn't on the v6 API.
+func (*StorageAPIv6) ExportSnapshot(_ struct{}) {}
@@ -0,0 +1,246 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/dummy"
+)
+
+type storageVolumeOpsSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageVolumeOpsSuite{})
+
+func (s *storageVolumeOpsSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.volume.info = &state.VolumeInfo{VolumeId: "vol-1", Pool: "radiance", Size: 1024}
+}
+
+func (s *storageVolumeOpsSuite) registerVolumeSource(source storage.VolumeSource) {
+	s.registry.Providers["radiance"] = &dummy.StorageProvider{
+		StorageScope: storage.ScopeEnviron,
+		IsDynamic:    true,
+		SupportsFunc: func(kind storage.StorageKind) bool {
+			return kind == storage.StorageKindBlock
+		},
+		VolumeSourceFunc: func(*storage.Config) (storage.VolumeSource, error) {
+			return source, nil
+		},
+	}
+}
+
+func (s *storageVolumeOpsSuite) TestResize(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.Resize(params.ResizeStorageParams{Storage: []params.ResizeStorageParam{{
+		StorageTag: "storage-data-0",
+		Size:       2048,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	source.CheckCalls(c, []testing.StubCall{
+		{"ResizeVolume", []interface{}{s.callContext, "vol-1", uint64(2048)}},
+	})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, storageInstanceVolumeCall,
+		storageInstanceVolumeCall, setVolumeInfoCall, storageInstanceFilesystemCall)
+	s.stub.CheckCall(c, 3, setVolumeInfoCall, s.volumeTag, state.VolumeInfo{
+		VolumeId: "vol-1", Pool: "radiance", Size: 2048,
+	})
+}
+
+func (s *storageVolumeOpsSuite) TestResizeRecordsFilesystemSize(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+	s.filesystem.info = &state.FilesystemInfo{FilesystemId: "fs-1", Pool: "radiance", Size: 1024}
+
+	results, err := s.api.Resize(params.ResizeStorageParams{Storage: []params.ResizeStorageParam{{
+		StorageTag: "storage-data-0",
+		Size:       2048,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}})
+	s.stub.CheckCall(c, 5, setFilesystemInfoCall, s.filesystemTag, state.FilesystemInfo{
+		FilesystemId: "fs-1", Pool: "radiance", Size: 2048,
+	})
+}
+
+func (s *storageVolumeOpsSuite) TestResizeNotSupported(c *gc.C) {
+	source := &dummy.VolumeSource{}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.Resize(params.ResizeStorageParams{Storage: []params.ResizeStorageParam{{
+		StorageTag: "storage-data-0",
+		Size:       2048,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{
+		Error: &params.Error{
+			Message: `resizing volume with storage provider "radiance" not supported`,
+			Code:    "not supported",
+		},
+	}})
+	source.CheckNoCalls(c)
+}
+
+func (s *storageVolumeOpsSuite) TestResizeValidationErrors(c *gc.C) {
+	results, err := s.api.Resize(params.ResizeStorageParams{Storage: []params.ResizeStorageParam{{
+		StorageTag: "storage-data-0",
+	}, {
+		StorageTag: "storage-data-1",
+		Size:       2048,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: `storage size 0 not valid`, Code: "not valid"}},
+		{Error: &params.Error{Message: `resizing storage "data/1" not backed by a volume not supported`, Code: "not supported"}},
+	})
+}
+
+func (s *storageVolumeOpsSuite) TestCreateSnapshot(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.CreateSnapshot(params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}, {
+		StorageTag: "storage-data-0",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageSnapshotResult{{
+		Result: &params.StorageSnapshot{
+			StorageTag: "storage-data-0",
+			Name:       "snap-1",
+		},
+	}, {
+		Error: &params.Error{Message: `empty snapshot name not valid`, Code: "not valid"},
+	}})
+	source.CheckCalls(c, []testing.StubCall{
+		{"CreateVolumeSnapshot", []interface{}{s.callContext, "vol-1", "snap-1"}},
+	})
+}
+
+func (s *storageVolumeOpsSuite) TestListSnapshots(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.ListSnapshots(params.Entities{Entities: []params.Entity{{Tag: "storage-data-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageSnapshotsResult{{
+		Result: []params.StorageSnapshot{{
+			StorageTag: "storage-data-0",
+			Name:       "snap-1",
+			Size:       1024,
+			Ready:      true,
+			Created:    &created,
+		}},
+	}})
+	source.CheckCallNames(c, "ListVolumeSnapshots")
+}
+
+func (s *storageVolumeOpsSuite) TestListSnapshotsNotSupported(c *gc.C) {
+	s.registerVolumeSource(&dummy.VolumeSource{})
+
+	results, err := s.api.ListSnapshots(params.Entities{Entities: []params.Entity{{Tag: "storage-data-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.StorageSnapshotsResult{{
+		Error: &params.Error{
+			Message: `snapshotting volume with storage provider "radiance" not supported`,
+			Code:    "not supported",
+		},
+	}})
+}
+
+func (s *storageVolumeOpsSuite) TestExportSnapshot(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.ExportSnapshot(params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ExportStorageSnapshotResult{{
+		VolumeId: "vol-1-snap-1",
+	}})
+	source.CheckCalls(c, []testing.StubCall{
+		{"ExportVolumeSnapshot", []interface{}{s.callContext, "vol-1", "snap-1"}},
+	})
+}
+
+func (s *storageVolumeOpsSuite) TestRestoreSnapshot(c *gc.C) {
+	source := volumeSnapshotter{&dummy.VolumeSource{}}
+	s.registerVolumeSource(source)
+
+	results, err := s.api.RestoreSnapshot(params.StorageSnapshotParams{Snapshots: []params.StorageSnapshotParam{{
+		StorageTag: "storage-data-0",
+		Name:       "snap-1",
+	}, {
+		StorageTag: "storage-data-1",
+		Name:       "snap-1",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.ErrorResult{{}, {
+		Error: &params.Error{
+			Message: `restoring snapshots of storage "data/1" not backed by a volume not supported`,
+			Code:    "not supported",
+		},
+	}})
+	source.CheckCalls(c, []testing.StubCall{
+		{"RestoreVolumeSnapshot", []interface{}{s.callContext, "vol-1", "snap-1"}},
+	})
+}
+
+type volumeSnapshotter struct {
+	*dummy.VolumeSource
+}
+
+// ResizeVolume is part of the storage.VolumeResizer interface.
+func (v volumeSnapshotter) ResizeVolume(ctx context.ProviderCallContext, volumeId string, size uint64) error {
+	v.MethodCall(v, "ResizeVolume", ctx, volumeId, size)
+	return v.NextErr()
+}
+
+// CreateVolumeSnapshot is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) CreateVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) (storage.VolumeSnapshot, error) {
+	v.MethodCall(v, "CreateVolumeSnapshot", ctx, volumeId, name)
+	return storage.VolumeSnapshot{Name: name, VolumeId: volumeId}, v.NextErr()
+}
+
+// ListVolumeSnapshots is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) ListVolumeSnapshots(ctx context.ProviderCallContext, volumeId string) ([]storage.VolumeSnapshot, error) {
+	v.MethodCall(v, "ListVolumeSnapshots", ctx, volumeId)
+	return []storage.VolumeSnapshot{{
+		Name:     "snap-1",
+		VolumeId: volumeId,
+		Size:     1024,
+		Ready:    true,
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}, v.NextErr()
+}
+
+// ExportVolumeSnapshot is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) ExportVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) (string, error) {
+	v.MethodCall(v, "ExportVolumeSnapshot", ctx, volumeId, name)
+	return volumeId + "-" + name, v.NextErr()
+}
+
+// RestoreVolumeSnapshot is part of the storage.VolumeSnapshotter interface.
+func (v volumeSnapshotter) RestoreVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) error {
+	v.MethodCall(v, "RestoreVolumeSnapshot", ctx, volumeId, name)
+	return v.NextErr()
+}
@@ -230,6 +230,7 @@ type mockApplication struct {
 	unitsWatcher         *statetesting.MockStringsWatcher
 	unitsChanges         chan []string
 	watcher              *statetesting.MockNotifyWatcher
+	configWatcher        *statetesting.MockNotifyWatcher
 	charmPending         bool
 	provisioningState    *state.ApplicationProvisioningState
 }
@@ -363,6 +364,11 @@ func (a *mockApplication) Watch() state.NotifyWatcher {
 	return a.watcher
 }
 
+func (a *mockApplication) WatchApplicationConfig() state.NotifyWatcher {
+	a.MethodCall(a, "WatchApplicationConfig")
+	return a.configWatcher
+}
+
 func (a *mockApplication) SetProvisioningState(ps state.ApplicationProvisioningState) error {
 	a.MethodCall(a, "SetProvisioningState", ps)
 	err := a.NextErr()
@@ -498,6 +504,7 @@ type mockStorage struct {
 	storageFilesystems map[names.StorageTag]names.FilesystemTag
 	storageVolumes     map[names.StorageTag]names.VolumeTag
 	storageAttachments map[names.UnitTag]names.StorageTag
+	filesystemSizes    map[names.FilesystemTag]uint64
 	backingVolume      names.VolumeTag
 }
 
@@ -539,7 +546,8 @@ func (m *mockStorage) Filesystem(fsTag names.FilesystemTag) (state.Filesystem, e
 }
 
 func (m *mockStorage) StorageInstanceFilesystem(tag names.StorageTag) (state.Filesystem, error) {
-	return &mockFilesystem{Stub: &m.Stub, tag: m.storageFilesystems[tag], volTag: m.backingVolume}, nil
+	fsTag := m.storageFilesystems[tag]
+	return &mockFilesystem{Stub: &m.Stub, tag: fsTag, volTag: m.backingVolume, size: m.filesystemSizes[fsTag]}, nil
 }
 
 func (m *mockStorage) UnitStorageAttachments(unit names.UnitTag) ([]state.StorageAttachment, error) {
@@ -616,6 +624,7 @@ type mockFilesystem struct {
 	state.Filesystem
 	tag    names.FilesystemTag
 	volTag names.VolumeTag
+	size   uint64
 }
 
 func (f *mockFilesystem) Tag() names.Tag {
@@ -639,7 +648,10 @@ func (f *mockFilesystem) SetStatus(statusInfo status.StatusInfo) error {
 }
 
 func (f *mockFilesystem) Info() (state.FilesystemInfo, error) {
-	return state.FilesystemInfo{}, errors.NotProvisionedf("filesystem")
+	if f.size == 0 {
+		return state.FilesystemInfo{}, errors.NotProvisionedf("filesystem")
+	}
+	return state.FilesystemInfo{Size: f.size}, nil
 }
 
 type mockVolume struct {
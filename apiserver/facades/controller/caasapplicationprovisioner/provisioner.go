@@ -104,7 +104,7 @@ func NewStateCAASApplicationProvisionerAPI(ctx facade.Context) (*APIGroup, error
 	}
 
 	newResourceOpener := func(appName string) (resources.Opener, error) {
-		return resource.NewResourceOpenerForApplication(st, appName)
+		return resource.NewResourceOpenerForApplication(st, ctx.CharmhubCache(), appName)
 	}
 
 	systemState, err := ctx.StatePool().SystemState()
@@ -238,11 +238,12 @@ func (a *API) watchProvisioningInfo(appName names.ApplicationTag) (params.Notify
 	}
 
 	appWatcher := app.Watch()
+	appConfigWatcher := app.WatchApplicationConfig()
 	controllerConfigWatcher := a.ctrlSt.WatchControllerConfig()
 	controllerAPIHostPortsWatcher := a.ctrlSt.WatchAPIHostPortsForAgents()
 	modelConfigWatcher := model.WatchForModelConfigChanges()
 
-	multiWatcher := common.NewMultiNotifyWatcher(appWatcher, controllerConfigWatcher, controllerAPIHostPortsWatcher, modelConfigWatcher)
+	multiWatcher := common.NewMultiNotifyWatcher(appWatcher, appConfigWatcher, controllerConfigWatcher, controllerAPIHostPortsWatcher, modelConfigWatcher)
 
 	if _, ok := <-multiWatcher.Changes(); ok {
 		result.NotifyWatcherId = a.resources.Register(multiWatcher)
@@ -371,6 +372,9 @@ func (a *API) provisioningInfo(appName names.ApplicationTag) (*params.CAASApplic
 		CharmModifiedVersion: app.CharmModifiedVersion(),
 		CharmURL:             *charmURL,
 		Trust:                appConfig.GetBool(application.TrustConfigOptionName, false),
+		Autoscaling:          appConfig.GetString(application.AutoscalingConfigOptionName, ""),
+		MaxUnavailable:       appConfig.GetString(application.MaxUnavailableConfigOptionName, ""),
+		SpreadZones:          appConfig.GetBool(application.SpreadZonesConfigOptionName, false),
 		Scale:                app.GetScale(),
 	}, nil
 }
@@ -550,6 +554,13 @@ func (a *API) applicationFilesystemParams(
 		return nil, errors.Trace(err)
 	}
 
+	var recordedSizes map[string]uint64
+	if len(storageConstraints) > 0 {
+		if recordedSizes, err = a.recordedFilesystemSizes(app); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	var allFilesystemParams []params.KubernetesFilesystemParams
 	// To always guarantee the same order, sort by names.
 	var sNames []string
@@ -559,6 +570,12 @@ func (a *API) applicationFilesystemParams(
 	sort.Strings(sNames)
 	for _, name := range sNames {
 		cons := storageConstraints[name]
+		// Storage resized after deployment is recorded against the
+		// filesystems, not the constraints. Use the larger size so the
+		// volume claim template matches the resized claims.
+		if size := recordedSizes[name]; size > cons.Size {
+			cons.Size = size
+		}
 		fsParams, err := filesystemParams(
 			app, cons, name,
 			controllerConfig.ControllerUUID(),
@@ -588,6 +605,48 @@ func (a *API) applicationFilesystemParams(
 	return allFilesystemParams, nil
 }
 
+// recordedFilesystemSizes returns the largest provisioned filesystem size
+// recorded for each of the application's named storage, across its units.
+func (a *API) recordedFilesystemSizes(app Application) (map[string]uint64, error) {
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sizes := make(map[string]uint64)
+	for _, unit := range units {
+		unitTag, ok := unit.Tag().(names.UnitTag)
+		if !ok {
+			continue
+		}
+		attachments, err := a.storage.UnitStorageAttachments(unitTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, sa := range attachments {
+			storageName, err := names.StorageName(sa.StorageInstance().Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			fs, err := a.storage.StorageInstanceFilesystem(sa.StorageInstance())
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			info, err := fs.Info()
+			if errors.IsNotProvisioned(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if info.Size > sizes[storageName] {
+				sizes[storageName] = info.Size
+			}
+		}
+	}
+	return sizes, nil
+}
+
 func filesystemParams(
 	app Application,
 	cons state.StorageConstraints,
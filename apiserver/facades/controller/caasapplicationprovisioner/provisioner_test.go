@@ -63,6 +63,7 @@ func (s *CAASApplicationProvisionerSuite) SetUpTest(c *gc.C) {
 		storageFilesystems: make(map[names.StorageTag]names.FilesystemTag),
 		storageVolumes:     make(map[names.StorageTag]names.VolumeTag),
 		storageAttachments: make(map[names.UnitTag]names.StorageTag),
+		filesystemSizes:    make(map[names.FilesystemTag]uint64),
 		backingVolume:      names.NewVolumeTag("66"),
 	}
 	s.storagePoolManager = &mockStoragePoolManager{}
@@ -120,6 +121,41 @@ func (s *CAASApplicationProvisionerSuite) TestProvisioningInfo(c *gc.C) {
 	})
 }
 
+func (s *CAASApplicationProvisionerSuite) TestProvisioningInfoResizedStorage(c *gc.C) {
+	s.st.app = &mockApplication{
+		tag:  names.NewApplicationTag("gitlab"),
+		life: state.Alive,
+		charm: &mockCharm{
+			meta: &charm.Meta{
+				Storage: map[string]charm.Storage{
+					"data": {Name: "data", Type: charm.StorageFilesystem, Location: "/data"},
+				},
+			},
+			url: "ch:gitlab",
+		},
+		units: []*mockUnit{
+			{tag: names.NewUnitTag("gitlab/0")},
+			{tag: names.NewUnitTag("gitlab/1")},
+		},
+		storageConstraints: map[string]state.StorageConstraints{
+			"data": {Pool: "k8s-pool", Size: 100, Count: 1},
+		},
+	}
+	s.storage.storageAttachments[names.NewUnitTag("gitlab/0")] = names.NewStorageTag("data/0")
+	s.storage.storageAttachments[names.NewUnitTag("gitlab/1")] = names.NewStorageTag("data/1")
+	s.storage.storageFilesystems[names.NewStorageTag("data/0")] = names.NewFilesystemTag("gitlab/0/0")
+	s.storage.storageFilesystems[names.NewStorageTag("data/1")] = names.NewFilesystemTag("gitlab/1/0")
+	s.storage.filesystemSizes[names.NewFilesystemTag("gitlab/0/0")] = 100
+	s.storage.filesystemSizes[names.NewFilesystemTag("gitlab/1/0")] = 2048
+
+	result, err := s.api.ProvisioningInfo(params.Entities{Entities: []params.Entity{{"application-gitlab"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Filesystems, gc.HasLen, 1)
+	c.Assert(result.Results[0].Filesystems[0].StorageName, gc.Equals, "data")
+	c.Assert(result.Results[0].Filesystems[0].Size, gc.Equals, uint64(2048))
+}
+
 func (s *CAASApplicationProvisionerSuite) TestProvisioningInfoPendingCharmError(c *gc.C) {
 	s.st.app = &mockApplication{
 		life:         state.Alive,
@@ -136,6 +172,7 @@ func (s *CAASApplicationProvisionerSuite) TestProvisioningInfoPendingCharmError(
 
 func (s *CAASApplicationProvisionerSuite) TestWatchProvisioningInfo(c *gc.C) {
 	appChanged := make(chan struct{}, 1)
+	appConfigChanged := make(chan struct{}, 1)
 	portsChanged := make(chan struct{}, 1)
 	modelConfigChanged := make(chan struct{}, 1)
 	controllerConfigChanged := make(chan struct{}, 1)
@@ -148,9 +185,11 @@ func (s *CAASApplicationProvisionerSuite) TestWatchProvisioningInfo(c *gc.C) {
 			meta: &charm.Meta{},
 			url:  "cs:gitlab",
 		},
-		watcher: statetesting.NewMockNotifyWatcher(appChanged),
+		watcher:       statetesting.NewMockNotifyWatcher(appChanged),
+		configWatcher: statetesting.NewMockNotifyWatcher(appConfigChanged),
 	}
 	appChanged <- struct{}{}
+	appConfigChanged <- struct{}{}
 	portsChanged <- struct{}{}
 	modelConfigChanged <- struct{}{}
 	controllerConfigChanged <- struct{}{}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
 
@@ -137,14 +138,23 @@ func (f *Facade) WatchApplications() (params.StringsWatchResult, error) {
 // FacadeSidecar provides access to the CAASFirewaller API facade for sidecar applications.
 type FacadeSidecar struct {
 	*Facade
+	*common.ModelWatcher
 
-	accessModel common.GetAuthFunc
+	accessModel       common.GetAuthFunc
+	accessApplication common.GetAuthFunc
+}
+
+// FacadeSidecarV1 provides v1 of the CAASFirewallerSidecar API facade,
+// which doesn't support relations or model config.
+type FacadeSidecarV1 struct {
+	*FacadeSidecar
 }
 
 func newFacadeSidecar(
 	resources facade.Resources,
 	authorizer facade.Authorizer,
 	st CAASFirewallerState,
+	model state.ModelAccessor,
 	commonCharmsAPI *charmscommon.CharmInfoAPI,
 	appCharmInfoAPI *charmscommon.ApplicationCharmInfoAPI,
 ) (*FacadeSidecar, error) {
@@ -154,7 +164,9 @@ func newFacadeSidecar(
 	accessApplication := common.AuthFuncForTagKind(names.ApplicationTagKind)
 
 	return &FacadeSidecar{
-		accessModel: common.AuthFuncForTagKind(names.ModelTagKind),
+		ModelWatcher:      common.NewModelWatcher(model, resources, authorizer),
+		accessModel:       common.AuthFuncForTagKind(names.ModelTagKind),
+		accessApplication: accessApplication,
 		Facade: &Facade{
 			LifeGetter: common.NewLifeGetter(
 				st, common.AuthAny(
@@ -266,3 +278,129 @@ func (f *FacadeSidecar) applicationOpenedPortsForEndpoint(endpointName string, p
 	}
 	return o
 }
+
+// WatchApplicationRelations returns a new StringsWatcher for each given
+// application tag, which notifies of changes to the lifecycles of
+// relations involving the application.
+func (f *FacadeSidecar) WatchApplicationRelations(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canWatch, err := f.accessApplication()
+	if err != nil {
+		return params.StringsWatchResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil || !canWatch(tag) {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		watcherID, initial, err := f.watchOneApplicationRelations(tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].StringsWatcherId = watcherID
+		result.Results[i].Changes = initial
+	}
+	return result, nil
+}
+
+func (f *FacadeSidecar) watchOneApplicationRelations(tag names.ApplicationTag) (string, []string, error) {
+	app, err := f.state.Application(tag.Id())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	watch := app.WatchRelations()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return f.resources.Register(watch), changes, nil
+	}
+	return "", nil, watcher.EnsureErr(watch)
+}
+
+// ApplicationRelatedEndpoints returns, for each given application tag,
+// the application's endpoints which are in alive, unsuspended relations
+// with other applications, along with the related application endpoints.
+// Peer relations are not included.
+func (f *FacadeSidecar) ApplicationRelatedEndpoints(args params.Entities) (params.ApplicationRelatedEndpointsResults, error) {
+	result := params.ApplicationRelatedEndpointsResults{
+		Results: make([]params.ApplicationRelatedEndpointsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		endpoints, err := f.applicationRelatedEndpoints(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Endpoints = endpoints
+	}
+	return result, nil
+}
+
+func (f *FacadeSidecar) applicationRelatedEndpoints(tagString string) ([]params.ApplicationRelatedEndpoint, error) {
+	tag, err := names.ParseApplicationTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := f.state.Application(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations, err := app.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.ApplicationRelatedEndpoint
+	for _, rel := range relations {
+		if rel.Life() != state.Alive || rel.Suspended() {
+			continue
+		}
+		local, err := rel.Endpoint(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		related, err := rel.RelatedEndpoints(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		_, crossModel, err := rel.RemoteApplication()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, ep := range related {
+			if ep.ApplicationName == tag.Id() {
+				continue
+			}
+			result = append(result, params.ApplicationRelatedEndpoint{
+				Endpoint:            local.Name,
+				RelatedApplication:  ep.ApplicationName,
+				RelatedEndpointName: ep.Name,
+				CrossModel:          crossModel,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Endpoint != result[j].Endpoint {
+			return result[i].Endpoint < result[j].Endpoint
+		}
+		return result[i].RelatedApplication < result[j].RelatedApplication
+	})
+	return result, nil
+}
+
+// ModelConfig isn't on the v1 API.
+func (*FacadeSidecarV1) ModelConfig(_ struct{}) {}
+
+// WatchForModelConfigChanges isn't on the v1 API.
+func (*FacadeSidecarV1) WatchForModelConfigChanges(_ struct{}) {}
+
+// WatchApplicationRelations isn't on the v1 API.
+func (*FacadeSidecarV1) WatchApplicationRelations(_ struct{}) {}
+
+// ApplicationRelatedEndpoints isn't on the v1 API.
+func (*FacadeSidecarV1) ApplicationRelatedEndpoints(_ struct{}) {}
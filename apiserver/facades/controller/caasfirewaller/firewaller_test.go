@@ -73,6 +73,8 @@ type firewallerSidecarSuite struct {
 	facade facadeSidecar
 }
 
+var sidecarModel = &mockModel{}
+
 var _ = gc.Suite(&firewallerSidecarSuite{
 	firewallerBaseSuite: firewallerBaseSuite{
 		newFunc: func(c *gc.C, resources facade.Resources,
@@ -88,6 +90,7 @@ var _ = gc.Suite(&firewallerSidecarSuite{
 				resources,
 				authorizer,
 				st,
+				sidecarModel,
 				commonCharmsAPI,
 				appCharmInfoAPI,
 			)
@@ -169,6 +172,108 @@ func (s *firewallerSidecarSuite) TestGetApplicationOpenedPorts(c *gc.C) {
 	})
 }
 
+func (s *firewallerSidecarSuite) TestWatchApplicationRelations(c *gc.C) {
+	relationsChanges := make(chan []string, 1)
+	s.st.application.relationsWatcher = statetesting.NewMockStringsWatcher(relationsChanges)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, s.st.application.relationsWatcher) })
+	relationsChanges <- []string{"gitlab:db mysql:server"}
+
+	results, err := s.facade.WatchApplicationRelations(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].StringsWatcherId, gc.Equals, "1")
+	c.Assert(results.Results[0].Changes, jc.DeepEquals, []string{"gitlab:db mysql:server"})
+	c.Assert(results.Results[1].Error, jc.DeepEquals, &params.Error{
+		Message: "permission denied",
+		Code:    "unauthorized access",
+	})
+	c.Assert(s.resources.Get("1"), gc.Equals, s.st.application.relationsWatcher)
+}
+
+func (s *firewallerSidecarSuite) TestApplicationRelatedEndpoints(c *gc.C) {
+	s.st.application.relations = []caasfirewaller.Relation{
+		&mockRelation{
+			life: state.Alive,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "db", Role: charm.RoleRequirer}},
+				{ApplicationName: "mysql", Relation: charm.Relation{Name: "server", Role: charm.RoleProvider}},
+			},
+		},
+		&mockRelation{
+			life: state.Alive,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "website", Role: charm.RoleProvider}},
+				{ApplicationName: "haproxy", Relation: charm.Relation{Name: "reverseproxy", Role: charm.RoleRequirer}},
+			},
+		},
+		&mockRelation{
+			life:       state.Alive,
+			crossModel: true,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "website", Role: charm.RoleProvider}},
+				{ApplicationName: "remote-proxy", Relation: charm.Relation{Name: "reverseproxy", Role: charm.RoleRequirer}},
+			},
+		},
+		&mockRelation{
+			life:      state.Alive,
+			suspended: true,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "cache", Role: charm.RoleRequirer}},
+				{ApplicationName: "redis", Relation: charm.Relation{Name: "cache", Role: charm.RoleProvider}},
+			},
+		},
+		&mockRelation{
+			life: state.Dying,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "db", Role: charm.RoleRequirer}},
+				{ApplicationName: "postgresql", Relation: charm.Relation{Name: "db", Role: charm.RoleProvider}},
+			},
+		},
+		&mockRelation{
+			life: state.Alive,
+			endpoints: []state.Endpoint{
+				{ApplicationName: "gitlab", Relation: charm.Relation{Name: "cluster", Role: charm.RolePeer}},
+			},
+		},
+	}
+
+	results, err := s.facade.ApplicationRelatedEndpoints(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "application-gitlab"},
+			{Tag: "unit-gitlab-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ApplicationRelatedEndpointsResults{
+		Results: []params.ApplicationRelatedEndpointsResult{{
+			Endpoints: []params.ApplicationRelatedEndpoint{{
+				Endpoint:            "db",
+				RelatedApplication:  "mysql",
+				RelatedEndpointName: "server",
+			}, {
+				Endpoint:            "website",
+				RelatedApplication:  "haproxy",
+				RelatedEndpointName: "reverseproxy",
+			}, {
+				Endpoint:            "website",
+				RelatedApplication:  "remote-proxy",
+				RelatedEndpointName: "reverseproxy",
+				CrossModel:          true,
+			}},
+		}, {
+			Error: &params.Error{
+				Message: `"unit-gitlab-0" is not a valid application tag`,
+			},
+		}},
+	})
+}
+
 type facadeCommon interface {
 	IsExposed(args params.Entities) (params.BoolResults, error)
 	ApplicationsConfig(args params.Entities) (params.ApplicationGetConfigResults, error)
@@ -182,6 +287,8 @@ type facadeSidecar interface {
 	facadeCommon
 	WatchOpenedPorts(args params.Entities) (params.StringsWatchResults, error)
 	GetOpenedPorts(arg params.Entity) (params.ApplicationOpenedPortsResults, error)
+	WatchApplicationRelations(args params.Entities) (params.StringsWatchResults, error)
+	ApplicationRelatedEndpoints(args params.Entities) (params.ApplicationRelatedEndpointsResults, error)
 }
 
 func (s *firewallerBaseSuite) SetUpTest(c *gc.C) {
@@ -5,6 +5,7 @@ package caasfirewaller_test
 
 import (
 	"github.com/juju/charm/v12"
+	"github.com/juju/errors"
 	"github.com/juju/names/v5"
 	"github.com/juju/testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/caasfirewaller"
 	"github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/network"
+	environsconfig "github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -73,8 +75,10 @@ type mockApplication struct {
 	exposed      bool
 	watcher      state.NotifyWatcher
 
-	charm         mockCharm
-	appPortRanges network.GroupedPortRanges
+	charm            mockCharm
+	appPortRanges    network.GroupedPortRanges
+	relations        []caasfirewaller.Relation
+	relationsWatcher *statetesting.MockStringsWatcher
 }
 
 func (a *mockApplication) Life() state.Life {
@@ -102,11 +106,75 @@ func (a *mockApplication) OpenedPortRanges() (network.GroupedPortRanges, error)
 	return a.appPortRanges, nil
 }
 
+func (a *mockApplication) WatchRelations() state.StringsWatcher {
+	a.MethodCall(a, "WatchRelations")
+	return a.relationsWatcher
+}
+
+func (a *mockApplication) Relations() ([]caasfirewaller.Relation, error) {
+	a.MethodCall(a, "Relations")
+	return a.relations, a.NextErr()
+}
+
 func (a *mockApplication) Charm() (charmscommon.Charm, bool, error) {
 	a.MethodCall(a, "Charm")
 	return &a.charm, false, nil
 }
 
+type mockRelation struct {
+	life       state.Life
+	suspended  bool
+	crossModel bool
+	endpoints  []state.Endpoint
+}
+
+func (r *mockRelation) Life() state.Life {
+	return r.life
+}
+
+func (r *mockRelation) Suspended() bool {
+	return r.suspended
+}
+
+func (r *mockRelation) Endpoint(applicationName string) (state.Endpoint, error) {
+	for _, ep := range r.endpoints {
+		if ep.ApplicationName == applicationName {
+			return ep, nil
+		}
+	}
+	return state.Endpoint{}, errors.NotFoundf("endpoint for %q", applicationName)
+}
+
+func (r *mockRelation) RemoteApplication() (*state.RemoteApplication, bool, error) {
+	return nil, r.crossModel, nil
+}
+
+func (r *mockRelation) RelatedEndpoints(applicationName string) ([]state.Endpoint, error) {
+	var result []state.Endpoint
+	for _, ep := range r.endpoints {
+		if ep.ApplicationName != applicationName || ep.Role == charm.RolePeer {
+			result = append(result, ep)
+		}
+	}
+	return result, nil
+}
+
+type mockModel struct {
+	testing.Stub
+	config        *environsconfig.Config
+	configWatcher *statetesting.MockNotifyWatcher
+}
+
+func (m *mockModel) ModelConfig() (*environsconfig.Config, error) {
+	m.MethodCall(m, "ModelConfig")
+	return m.config, m.NextErr()
+}
+
+func (m *mockModel) WatchForModelConfigChanges() state.NotifyWatcher {
+	m.MethodCall(m, "WatchForModelConfigChanges")
+	return m.configWatcher
+}
+
 type mockCharm struct {
 	testing.Stub
 	charmscommon.Charm // Override only the methods the tests use
@@ -19,6 +19,9 @@ func Register(registry facade.FacadeRegistry) {
 	}, reflect.TypeOf((*Facade)(nil)))
 
 	registry.MustRegister("CAASFirewallerSidecar", 1, func(ctx facade.Context) (facade.Facade, error) {
+		return newStateFacadeSidecarV1(ctx)
+	}, reflect.TypeOf((*FacadeSidecarV1)(nil)))
+	registry.MustRegister("CAASFirewallerSidecar", 2, func(ctx facade.Context) (facade.Facade, error) {
 		return newStateFacadeSidecar(ctx)
 	}, reflect.TypeOf((*FacadeSidecar)(nil)))
 }
@@ -47,6 +50,15 @@ func newStateFacadeLegacy(ctx facade.Context) (*Facade, error) {
 	)
 }
 
+// newStateFacadeSidecarV1 provides the signature required for facade registration.
+func newStateFacadeSidecarV1(ctx facade.Context) (*FacadeSidecarV1, error) {
+	api, err := newStateFacadeSidecar(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FacadeSidecarV1{api}, nil
+}
+
 // newStateFacadeSidecar provides the signature required for facade registration.
 func newStateFacadeSidecar(ctx facade.Context) (*FacadeSidecar, error) {
 	authorizer := ctx.Auth()
@@ -61,10 +73,15 @@ func newStateFacadeSidecar(ctx facade.Context) (*FacadeSidecar, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return newFacadeSidecar(
 		resources,
 		authorizer,
 		&stateShim{ctx.State()},
+		model,
 		commonCharmsAPI,
 		appCharmInfoAPI,
 	)
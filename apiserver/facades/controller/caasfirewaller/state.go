@@ -30,6 +30,18 @@ type Application interface {
 	Watch() state.NotifyWatcher
 	Charm() (ch charmscommon.Charm, force bool, err error)
 	OpenedPortRanges() (network.GroupedPortRanges, error)
+	WatchRelations() state.StringsWatcher
+	Relations() ([]Relation, error)
+}
+
+// Relation provides the subset of relation state
+// required by the CAAS operator facade.
+type Relation interface {
+	Life() state.Life
+	Suspended() bool
+	Endpoint(applicationName string) (state.Endpoint, error)
+	RelatedEndpoints(applicationName string) ([]state.Endpoint, error)
+	RemoteApplication() (*state.RemoteApplication, bool, error)
 }
 
 type stateShim struct {
@@ -59,3 +71,15 @@ func (a *applicationShim) OpenedPortRanges() (network.GroupedPortRanges, error)
 	}
 	return pg.ByEndpoint(), nil
 }
+
+func (a *applicationShim) Relations() ([]Relation, error) {
+	rels, err := a.Application.Relations()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Relation, len(rels))
+	for i, rel := range rels {
+		result[i] = rel
+	}
+	return result, nil
+}
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	facade "github.com/juju/juju/apiserver/facade"
+	charmhub "github.com/juju/juju/charmhub"
 	cache "github.com/juju/juju/core/cache"
 	database "github.com/juju/juju/core/database"
 	leadership "github.com/juju/juju/core/leadership"
@@ -281,6 +282,20 @@ func (mr *MockContextMockRecorder) Controller() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Controller", reflect.TypeOf((*MockContext)(nil).Controller))
 }
 
+// CharmhubCache mocks base method.
+func (m *MockContext) CharmhubCache() *charmhub.Cache {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CharmhubCache")
+	ret0, _ := ret[0].(*charmhub.Cache)
+	return ret0
+}
+
+// CharmhubCache indicates an expected call of CharmhubCache.
+func (mr *MockContextMockRecorder) CharmhubCache() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharmhubCache", reflect.TypeOf((*MockContext)(nil).CharmhubCache))
+}
+
 // ControllerDB mocks base method.
 func (m *MockContext) ControllerDB() (database.TrackedDB, error) {
 	m.ctrl.T.Helper()
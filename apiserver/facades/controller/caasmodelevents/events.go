@@ -0,0 +1,77 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v5"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+)
+
+// API is the facade used by the caasevents worker to record events
+// reported by the cloud against the units in a model.
+type API struct {
+	state CAASModelEventsState
+}
+
+// NewAPI returns a new model events facade.
+func NewAPI(authorizer facade.Authorizer, st CAASModelEventsState) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{state: st}, nil
+}
+
+// RecordUnitEvents adds the supplied events to the status history of
+// their units. The units' status is left unchanged; each event is
+// recorded with the unit's current workload status so that the history
+// reads naturally alongside the unit's own status changes.
+func (a *API) RecordUnitEvents(args params.CAASUnitEvents) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Events)),
+	}
+	for i, event := range args.Events {
+		err := a.recordUnitEvent(event)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (a *API) recordUnitEvent(event params.CAASUnitEvent) error {
+	tag, err := names.ParseUnitTag(event.UnitTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if event.Reason == "" {
+		return errors.NotValidf("event for unit %q without a reason", tag.Id())
+	}
+	unit, err := a.state.Unit(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := unit.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data := map[string]interface{}{
+		"type":   event.Type,
+		"reason": event.Reason,
+	}
+	if event.Count > 1 {
+		data["count"] = event.Count
+	}
+	since := event.Time
+	return errors.Trace(unit.RecordStatusHistory(status.StatusInfo{
+		Status:  current.Status,
+		Message: fmt.Sprintf("%s: %s", event.Reason, event.Message),
+		Data:    data,
+		Since:   &since,
+	}))
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents_test
+
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/caasmodelevents"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ModelEventsSuite struct {
+	coretesting.BaseSuite
+
+	authorizer *apiservertesting.FakeAuthorizer
+	state      *mockState
+	unit       *mockUnit
+	api        *caasmodelevents.API
+}
+
+var _ = gc.Suite(&ModelEventsSuite{})
+
+func (s *ModelEventsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag:        names.NewModelTag("model-deadbeef-0bad-400d-8000-4b1d0d06f00d"),
+		Controller: true,
+	}
+	s.unit = &mockUnit{status: status.StatusInfo{Status: status.Active, Message: "ready"}}
+	s.state = &mockState{units: map[string]*mockUnit{"mariadb/0": s.unit}}
+
+	api, err := caasmodelevents.NewAPI(s.authorizer, s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *ModelEventsSuite) TestPermission(c *gc.C) {
+	s.authorizer.Controller = false
+	_, err := caasmodelevents.NewAPI(s.authorizer, s.state)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ModelEventsSuite) TestRecordUnitEvents(c *gc.C) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	results, err := s.api.RecordUnitEvents(params.CAASUnitEvents{Events: []params.CAASUnitEvent{{
+		UnitTag: "unit-mariadb-0",
+		Type:    "Warning",
+		Reason:  "BackOff",
+		Message: "Back-off restarting failed container",
+		Count:   3,
+		Time:    when,
+	}, {
+		UnitTag: "unit-mariadb-1",
+		Type:    "Warning",
+		Reason:  "OOMKilling",
+		Time:    when,
+	}, {
+		UnitTag: "unit-mariadb-0",
+		Type:    "Warning",
+	}, {
+		UnitTag: "application-mariadb",
+		Reason:  "BackOff",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: `unit "mariadb/1" not found`, Code: params.CodeNotFound}},
+		{Error: &params.Error{Message: `event for unit "mariadb/0" without a reason not valid`, Code: params.CodeNotValid}},
+		{Error: &params.Error{Message: `"application-mariadb" is not a valid unit tag`}},
+	}})
+
+	s.state.CheckCallNames(c, "Unit", "Unit")
+	c.Assert(s.unit.history, jc.DeepEquals, []status.StatusInfo{{
+		Status:  status.Active,
+		Message: "BackOff: Back-off restarting failed container",
+		Data: map[string]interface{}{
+			"type":   "Warning",
+			"reason": "BackOff",
+			"count":  int32(3),
+		},
+		Since: &when,
+	}})
+}
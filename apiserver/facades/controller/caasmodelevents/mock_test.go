@@ -0,0 +1,49 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/apiserver/facades/controller/caasmodelevents"
+	"github.com/juju/juju/core/status"
+)
+
+type mockState struct {
+	testing.Stub
+	units map[string]*mockUnit
+}
+
+func (st *mockState) Unit(name string) (caasmodelevents.Unit, error) {
+	st.MethodCall(st, "Unit", name)
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	unit, ok := st.units[name]
+	if !ok {
+		return nil, errors.NotFoundf("unit %q", name)
+	}
+	return unit, nil
+}
+
+type mockUnit struct {
+	testing.Stub
+	status  status.StatusInfo
+	history []status.StatusInfo
+}
+
+func (u *mockUnit) Status() (status.StatusInfo, error) {
+	u.MethodCall(u, "Status")
+	return u.status, u.NextErr()
+}
+
+func (u *mockUnit) RecordStatusHistory(info status.StatusInfo) error {
+	u.MethodCall(u, "RecordStatusHistory", info)
+	if err := u.NextErr(); err != nil {
+		return err
+	}
+	u.history = append(u.history, info)
+	return nil
+}
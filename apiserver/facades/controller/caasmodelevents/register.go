@@ -0,0 +1,23 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents
+
+import (
+	"reflect"
+
+	"github.com/juju/juju/apiserver/facade"
+)
+
+// Register is called to expose a package of facades onto a given registry.
+func Register(registry facade.FacadeRegistry) {
+	registry.MustRegister("CAASModelEvents", 1, func(ctx facade.Context) (facade.Facade, error) {
+		return newAPIFromContext(ctx)
+	}, reflect.TypeOf((*API)(nil)))
+}
+
+// newAPIFromContext creates a new model events facade from the supplied
+// context.
+func newAPIFromContext(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.Auth(), stateShim{ctx.State()})
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasmodelevents
+
+import (
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+// CAASModelEventsState provides the subset of model state required by the
+// model events facade.
+type CAASModelEventsState interface {
+	Unit(name string) (Unit, error)
+}
+
+// Unit provides the subset of unit state required by the model events
+// facade.
+type Unit interface {
+	Status() (status.StatusInfo, error)
+	RecordStatusHistory(status.StatusInfo) error
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) Unit(name string) (Unit, error) {
+	return s.State.Unit(name)
+}
@@ -15,6 +15,7 @@ import (
 
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facades/client/charms/services"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state/watcher"
 )
@@ -31,6 +32,7 @@ type CharmDownloaderAPI struct {
 	modelBackend       ModelBackend
 	clock              clock.Clock
 	charmhubHTTPClient http.HTTPClient
+	charmhubCache      *charmhub.Cache
 
 	newStorage    func(modelUUID string) services.Storage
 	newDownloader func(services.CharmDownloaderConfig) (Downloader, error)
@@ -48,6 +50,7 @@ func newAPI(
 	modelBackend ModelBackend,
 	clk clock.Clock,
 	httpClient http.HTTPClient,
+	charmhubCache *charmhub.Cache,
 	newStorage func(string) services.Storage,
 	newDownloader func(services.CharmDownloaderConfig) (Downloader, error),
 ) *CharmDownloaderAPI {
@@ -58,6 +61,7 @@ func newAPI(
 		modelBackend:       modelBackend,
 		clock:              clk,
 		charmhubHTTPClient: httpClient,
+		charmhubCache:      charmhubCache,
 		newStorage:         newStorage,
 		newDownloader:      newDownloader,
 	}
@@ -156,6 +160,7 @@ func (a *CharmDownloaderAPI) getDownloader() (Downloader, error) {
 	downloader, err := a.newDownloader(services.CharmDownloaderConfig{
 		Logger:             logger,
 		CharmhubHTTPClient: a.charmhubHTTPClient,
+		CharmhubCache:      a.charmhubCache,
 		StorageFactory:     a.newStorage,
 		StateBackend:       a.stateBackend,
 		ModelBackend:       a.modelBackend,
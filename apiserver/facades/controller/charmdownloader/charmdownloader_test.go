@@ -256,6 +256,7 @@ func (s *charmDownloaderSuite) setupMocks(c *gc.C) *gomock.Controller {
 		s.clk,
 		http.DefaultClient,
 		nil,
+		nil,
 		func(services.CharmDownloaderConfig) (charmdownloader.Downloader, error) {
 			return s.downloader, nil
 		},
@@ -39,6 +39,7 @@ func newFacadeV1(ctx facade.Context) (*CharmDownloaderAPI, error) {
 		modelBackend,
 		clock.WallClock,
 		ctx.HTTPClient(facade.CharmhubHTTPClient),
+		ctx.CharmhubCache(),
 		func(modelUUID string) services.Storage {
 			return storage.NewStorage(modelUUID, rawState.MongoSession())
 		},
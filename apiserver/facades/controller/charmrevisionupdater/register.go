@@ -27,7 +27,7 @@ func newCharmRevisionUpdaterAPI(ctx facade.Context) (*CharmRevisionUpdaterAPI, e
 	}
 	newCharmhubClient := func(st State) (CharmhubRefreshClient, error) {
 		httpClient := ctx.HTTPClient(facade.CharmhubHTTPClient)
-		return common.CharmhubClient(charmhubClientStateShim{state: st}, httpClient, logger)
+		return common.CharmhubClient(charmhubClientStateShim{state: st}, httpClient, ctx.CharmhubCache(), logger)
 	}
 	return NewCharmRevisionUpdaterAPIState(
 		StateShim{State: ctx.State()},
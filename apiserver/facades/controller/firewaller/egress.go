@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/names/v5"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state/watcher"
+)
+
+// GetApplicationEgressRules returns the egress rules set in the
+// application config of each given application, or an empty string
+// when none are set.
+func (f *FirewallerAPI) GetApplicationEgressRules(args params.Entities) (params.StringResults, error) {
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		app, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		config, err := app.ApplicationConfig()
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = config.GetString(application.EgressConfigOptionName, "")
+	}
+	return result, nil
+}
+
+// WatchApplicationEgressRules returns a NotifyWatcher for each given
+// application, notifying of potential changes to its egress rules.
+func (f *FirewallerAPI) WatchApplicationEgressRules(args params.Entities) (params.NotifyWatchResults, error) {
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		app, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		watch := app.WatchApplicationConfig()
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = f.resources.Register(watch)
+		} else {
+			result.Results[i].Error = apiservererrors.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// GetApplicationEgressRules isn't on the V7 API.
+func (*FirewallerAPIV7) GetApplicationEgressRules(_, _ struct{}) {}
+
+// WatchApplicationEgressRules isn't on the V7 API.
+func (*FirewallerAPIV7) WatchApplicationEgressRules(_, _ struct{}) {}
@@ -5,4 +5,5 @@ package firewaller
 
 var (
 	NewFirewallerAPIV7 = newFirewallerAPIV7
+	NewFirewallerAPIV8 = newFirewallerAPIV8
 )
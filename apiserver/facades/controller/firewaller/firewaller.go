@@ -4,6 +4,8 @@
 package firewaller
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
 	"strconv"
 
@@ -50,7 +52,12 @@ type FirewallerAPI struct {
 	appEndpointBindings map[string]map[string]string
 }
 
-// NewStateFirewallerAPI creates a new server-side FirewallerAPIV7 facade.
+// FirewallerAPIV7 provides access to the Firewaller API facade version 7.
+type FirewallerAPIV7 struct {
+	*FirewallerAPI
+}
+
+// NewStateFirewallerAPI creates a new server-side FirewallerAPIV8 facade.
 func NewStateFirewallerAPI(
 	st State,
 	resources facade.Resources,
@@ -399,6 +406,41 @@ func (f *FirewallerAPI) SetRelationsStatus(args params.SetStatus) (params.ErrorR
 	return result, nil
 }
 
+// RecordFirewallDrift isn't on the V7 API.
+func (*FirewallerAPIV7) RecordFirewallDrift(_ struct{}) {}
+
+// RecordFirewallDrift records the result of a firewall drift check in
+// the model's status history.
+func (f *FirewallerAPI) RecordFirewallDrift(arg params.FirewallDriftReport) (params.ErrorResult, error) {
+	report, err := json.Marshal(arg)
+	if err != nil {
+		return params.ErrorResult{}, errors.Trace(err)
+	}
+	err = f.st.AddModelStatusHistory(firewallDriftMessage(arg), map[string]interface{}{
+		params.FirewallDriftDataKey: string(report),
+	})
+	return params.ErrorResult{Error: apiservererrors.ServerError(err)}, nil
+}
+
+// firewallDriftMessage summarises a firewall drift report as a
+// status message.
+func firewallDriftMessage(report params.FirewallDriftReport) string {
+	if len(report.Drift) == 0 {
+		return "firewall drift: none found"
+	}
+	var missing, unexpected int
+	for _, drift := range report.Drift {
+		missing += len(drift.Missing)
+		unexpected += len(drift.Unexpected)
+	}
+	msg := fmt.Sprintf("firewall drift: %d missing and %d unexpected rules on %d firewalls",
+		missing, unexpected, len(report.Drift))
+	if report.Corrected {
+		msg += " (corrected)"
+	}
+	return msg
+}
+
 // AreManuallyProvisioned returns whether each given entity is
 // manually provisioned or not. Only machine tags are accepted.
 func (f *FirewallerAPI) AreManuallyProvisioned(args params.Entities) (params.BoolResults, error) {
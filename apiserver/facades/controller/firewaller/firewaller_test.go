@@ -10,14 +10,17 @@ import (
 	jc "github.com/juju/testing/checkers"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/cloudspec"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/facades/controller/firewaller"
 	"github.com/juju/juju/apiserver/facades/controller/firewaller/mocks"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
@@ -324,3 +327,65 @@ func (s *firewallerSuite) TestWatchSubnets(c *gc.C) {
 	wc := statetesting.NewStringsWatcherC(c, resource.(state.StringsWatcher))
 	wc.AssertNoChange()
 }
+
+func (s *firewallerSuite) setEgressRules(c *gc.C, rules string) {
+	schema := environschema.Fields{
+		application.EgressConfigOptionName: environschema.Attr{Type: environschema.Tstring},
+	}
+	err := s.application.UpdateApplicationConfig(config.ConfigAttributes{
+		application.EgressConfigOptionName: rules,
+	}, nil, schema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *firewallerSuite) TestGetApplicationEgressRules(c *gc.C) {
+	defer s.ctrl.Finish()
+
+	s.setEgressRules(c, "443/tcp;53/udp to 10.0.0.2/32")
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	result, err := s.firewaller.GetApplicationEgressRules(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "443/tcp;53/udp to 10.0.0.2/32"},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`application "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *firewallerSuite) TestWatchApplicationEgressRules(c *gc.C) {
+	defer s.ctrl.Finish()
+
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+		{Tag: s.machines[0].Tag().String()},
+	}}
+	result, err := s.firewaller.WatchApplicationEgressRules(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewNotifyWatcherC(c, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	s.setEgressRules(c, "443/tcp")
+	wc.AssertOneChange()
+}
@@ -4,6 +4,9 @@
 package firewaller_test
 
 import (
+	"encoding/json"
+
+	"github.com/juju/errors"
 	"github.com/juju/names/v5"
 	jc "github.com/juju/testing/checkers"
 	"go.uber.org/mock/gomock"
@@ -357,3 +360,52 @@ func (s *FirewallerSuite) TestAllSpaceInfos(c *gc.C) {
 	gotSpaceInfos := params.ToNetworkSpaceInfos(res)
 	c.Assert(gotSpaceInfos, gc.DeepEquals, spaceInfos[0:1], gc.Commentf("expected to get back a filtered list of the space infos"))
 }
+
+func (s *FirewallerSuite) TestRecordFirewallDrift(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	report := params.FirewallDriftReport{
+		Mode:      "correct",
+		Corrected: true,
+		Drift: []params.FirewallDrift{{
+			Target: "machine-0",
+			Missing: []params.IngressRule{{
+				PortRange:   params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				SourceCIDRs: []string{"0.0.0.0/0"},
+			}},
+			Unexpected: []params.IngressRule{{
+				PortRange:   params.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"},
+				SourceCIDRs: []string{"10.0.0.0/8"},
+			}, {
+				PortRange:   params.PortRange{FromPort: 8080, ToPort: 8080, Protocol: "tcp"},
+				SourceCIDRs: []string{"0.0.0.0/0"},
+			}},
+		}},
+	}
+	s.st.EXPECT().AddModelStatusHistory(
+		"firewall drift: 1 missing and 2 unexpected rules on 1 firewalls (corrected)",
+		gomock.Any(),
+	).DoAndReturn(func(_ string, data map[string]interface{}) error {
+		var recorded params.FirewallDriftReport
+		err := json.Unmarshal([]byte(data[params.FirewallDriftDataKey].(string)), &recorded)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(recorded, jc.DeepEquals, report)
+		return nil
+	})
+
+	result, err := s.api.RecordFirewallDrift(report)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+}
+
+func (s *FirewallerSuite) TestRecordFirewallDriftNone(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.st.EXPECT().AddModelStatusHistory("firewall drift: none found", map[string]interface{}{
+		params.FirewallDriftDataKey: `{"mode":"report"}`,
+	}).Return(errors.New("boom"))
+
+	result, err := s.api.RecordFirewallDrift(params.FirewallDriftReport{Mode: "report"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/apiserver/common/firewall"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 )
@@ -28,6 +29,7 @@ type State interface {
 	FindEntity(tag names.Tag) (state.Entity, error)
 	AllEndpointBindings() (map[string]map[string]string, error)
 	SpaceInfos() (network.SpaceInfos, error)
+	AddModelStatusHistory(message string, data map[string]interface{}) error
 }
 
 // ControllerConfigAPI provides the subset of common.ControllerConfigAPI
@@ -95,3 +97,21 @@ func (st stateShim) AllEndpointBindings() (map[string]map[string]string, error)
 func (st stateShim) SpaceInfos() (network.SpaceInfos, error) {
 	return st.st.AllSpaceInfos()
 }
+
+// AddModelStatusHistory records an entry in the model's status history,
+// keeping the model's current status value.
+func (st stateShim) AddModelStatusHistory(message string, data map[string]interface{}) error {
+	model, err := st.st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := model.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return model.AddStatusHistory(status.StatusInfo{
+		Status:  current.Status,
+		Message: message,
+		Data:    data,
+	})
+}
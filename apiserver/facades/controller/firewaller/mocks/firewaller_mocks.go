@@ -47,6 +47,20 @@ func (m *MockState) EXPECT() *MockStateMockRecorder {
 	return m.recorder
 }
 
+// AddModelStatusHistory mocks base method.
+func (m *MockState) AddModelStatusHistory(arg0 string, arg1 map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModelStatusHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModelStatusHistory indicates an expected call of AddModelStatusHistory.
+func (mr *MockStateMockRecorder) AddModelStatusHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModelStatusHistory", reflect.TypeOf((*MockState)(nil).AddModelStatusHistory), arg0, arg1)
+}
+
 // AllEndpointBindings mocks base method.
 func (m *MockState) AllEndpointBindings() (map[string]map[string]string, error) {
 	m.ctrl.T.Helper()
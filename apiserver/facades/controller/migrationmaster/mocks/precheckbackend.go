@@ -271,3 +271,18 @@ func (mr *MockPrecheckBackendMockRecorder) NeedsCleanup() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsCleanup", reflect.TypeOf((*MockPrecheckBackend)(nil).NeedsCleanup))
 }
+
+// UpgradeRolloutActive mocks base method.
+func (m *MockPrecheckBackend) UpgradeRolloutActive() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeRolloutActive")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRolloutActive indicates an expected call of UpgradeRolloutActive.
+func (mr *MockPrecheckBackendMockRecorder) UpgradeRolloutActive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRolloutActive", reflect.TypeOf((*MockPrecheckBackend)(nil).UpgradeRolloutActive))
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout
+
+import (
+	"reflect"
+
+	"github.com/juju/juju/apiserver/facade"
+)
+
+// Register is called to expose a package of facades onto a given registry.
+func Register(registry facade.FacadeRegistry) {
+	registry.MustRegister("UpgradeRollout", 1, func(ctx facade.Context) (facade.Facade, error) {
+		return newAPI(ctx)
+	}, reflect.TypeOf((*API)(nil)))
+}
+
+// newAPI creates a new instance of the UpgradeRollout API.
+func newAPI(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+}
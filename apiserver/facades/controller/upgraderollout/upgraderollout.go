@@ -0,0 +1,63 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgraderollout implements the API used by the upgraderollout
+// worker, which moves staged model upgrades on.
+package upgraderollout
+
+import (
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// State provides the state methods used by the API.
+type State interface {
+	AdvanceUpgradeRollout() (bool, error)
+	WatchUpgradeRollout() state.NotifyWatcher
+}
+
+// API implements the API used by the upgraderollout worker.
+type API struct {
+	st        State
+	resources facade.Resources
+}
+
+// NewAPI returns a new API for the given state. Only controllers may
+// use it.
+func NewAPI(st State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{
+		st:        st,
+		resources: resources,
+	}, nil
+}
+
+// AdvanceUpgradeRollout moves the model's staged upgrade on, halting it,
+// upgrading the next batch of machines or completing it as its progress
+// requires. The result reports whether the upgrade is still running, and
+// so needs advancing again.
+func (api *API) AdvanceUpgradeRollout() params.BoolResult {
+	running, err := api.st.AdvanceUpgradeRollout()
+	return params.BoolResult{
+		Result: running,
+		Error:  apiservererrors.ServerError(err),
+	}
+}
+
+// WatchUpgradeRollout watches for changes to the model's staged upgrade.
+func (api *API) WatchUpgradeRollout() (params.NotifyWatchResult, error) {
+	watch := api.st.WatchUpgradeRollout()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: apiservererrors.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
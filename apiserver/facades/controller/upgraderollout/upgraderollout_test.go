@@ -0,0 +1,87 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/controller/upgraderollout"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type upgradeRolloutSuite struct {
+	coretesting.BaseSuite
+
+	st        *mockState
+	resources *common.Resources
+	api       *upgraderollout.API
+}
+
+var _ = gc.Suite(&upgradeRolloutSuite{})
+
+func (s *upgradeRolloutSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &mockState{Stub: &testing.Stub{}}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.api, err = upgraderollout.NewAPI(s.st, s.resources, apiservertesting.FakeAuthorizer{Controller: true})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgradeRolloutSuite) TestNewAPIRequiresController(c *gc.C) {
+	api, err := upgraderollout.NewAPI(s.st, s.resources, apiservertesting.FakeAuthorizer{})
+	c.Assert(api, gc.IsNil)
+	c.Assert(apiservererrors.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *upgradeRolloutSuite) TestAdvanceUpgradeRollout(c *gc.C) {
+	s.st.running = true
+	result := s.api.AdvanceUpgradeRollout()
+	c.Assert(result, jc.DeepEquals, params.BoolResult{Result: true})
+	s.st.CheckCallNames(c, "AdvanceUpgradeRollout")
+}
+
+func (s *upgradeRolloutSuite) TestAdvanceUpgradeRolloutNotRunning(c *gc.C) {
+	result := s.api.AdvanceUpgradeRollout()
+	c.Assert(result, jc.DeepEquals, params.BoolResult{})
+}
+
+func (s *upgradeRolloutSuite) TestAdvanceUpgradeRolloutFailure(c *gc.C) {
+	s.st.SetErrors(errors.New("boom"))
+	result := s.api.AdvanceUpgradeRollout()
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
+
+func (s *upgradeRolloutSuite) TestWatchUpgradeRollout(c *gc.C) {
+	result, err := s.api.WatchUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(s.resources.Get(result.NotifyWatcherId), gc.NotNil)
+	s.st.CheckCallNames(c, "WatchUpgradeRollout")
+}
+
+type mockState struct {
+	*testing.Stub
+	running bool
+}
+
+func (st *mockState) AdvanceUpgradeRollout() (bool, error) {
+	st.MethodCall(st, "AdvanceUpgradeRollout")
+	return st.running, st.NextErr()
+}
+
+func (st *mockState) WatchUpgradeRollout() state.NotifyWatcher {
+	st.MethodCall(st, "WatchUpgradeRollout")
+	return apiservertesting.NewFakeNotifyWatcher()
+}
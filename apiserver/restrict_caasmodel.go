@@ -94,6 +94,7 @@ var caasModelFacadeNames = set.NewStrings(
 	"CAASOperatorUpgrader",
 	"CAASUnitProvisioner",
 	"CAASModelConfigManager",
+	"CAASModelEvents",
 
 	// For sidecar applications.
 	"CAASApplication",
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
 	"github.com/juju/juju/core/leadership"
@@ -613,6 +614,11 @@ func (ctx *facadeContext) HTTPClient(purpose facade.HTTPClientPurpose) facade.HT
 	}
 }
 
+// CharmhubCache is part of the facade.Context interface.
+func (ctx *facadeContext) CharmhubCache() *charmhub.Cache {
+	return ctx.r.shared.charmhubCache
+}
+
 // ControllerDB returns a TrackedDB reference for the controller database.
 func (ctx *facadeContext) ControllerDB() (coredatabase.TrackedDB, error) {
 	db, err := ctx.r.shared.dbGetter.GetDB(coredatabase.ControllerNS)
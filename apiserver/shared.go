@@ -12,6 +12,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/charmhub"
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
@@ -47,6 +48,7 @@ type sharedServerContext struct {
 	logger              loggo.Logger
 	cancel              <-chan struct{}
 	charmhubHTTPClient  facade.HTTPClient
+	charmhubCache       *charmhub.Cache
 	dbGetter            coredatabase.DBGetter
 
 	configMutex      sync.RWMutex
@@ -66,6 +68,7 @@ type sharedServerConfig struct {
 	controllerConfig    jujucontroller.Config
 	logger              loggo.Logger
 	charmhubHTTPClient  facade.HTTPClient
+	charmhubCache       *charmhub.Cache
 	dbGetter            coredatabase.DBGetter
 }
 
@@ -111,6 +114,7 @@ func newSharedServerContext(config sharedServerConfig) (*sharedServerContext, er
 		logger:              config.logger,
 		controllerConfig:    config.controllerConfig,
 		charmhubHTTPClient:  config.charmhubHTTPClient,
+		charmhubCache:       config.charmhubCache,
 		dbGetter:            config.dbGetter,
 	}
 	ctx.features = config.controllerConfig.Features()
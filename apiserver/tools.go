@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,6 +33,7 @@ import (
 	"github.com/juju/juju/state/binarystorage"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/tools"
+	"github.com/juju/juju/tools/delta"
 )
 
 // toolsReadCloser wraps the ReadCloser for the tools blob
@@ -58,22 +60,38 @@ func (t *toolsReadCloser) Close() error {
 	return err
 }
 
+// deltaFromParam is the query parameter with which an agent requests a
+// delta from the agent binaries it runs, rather than the full agent
+// binaries.
+const deltaFromParam = "delta-from"
+
 // toolsHandler handles tool upload through HTTPS in the API server.
 type toolsUploadHandler struct {
 	ctxt          httpContext
 	stateAuthFunc func(*http.Request) (*state.PooledState, error)
+
+	// verifySignatures is set if uploaded agent binaries are checked
+	// against the signature uploaded with them. Binaries uploaded by a
+	// migration were already accepted by the source controller.
+	verifySignatures bool
 }
 
 // toolsHandler handles tool download through HTTPS in the API server.
 type toolsDownloadHandler struct {
 	ctxt       httpContext
 	fetchMutex *kmutex.Kmutex
+
+	// generatingDelta is held while a delta is generated. Generating a
+	// delta holds both versions of the agent binaries in memory, so only
+	// one is generated at a time.
+	generatingDelta chan struct{}
 }
 
 func newToolsDownloadHandler(httpCtxt httpContext) *toolsDownloadHandler {
 	return &toolsDownloadHandler{
-		ctxt:       httpCtxt,
-		fetchMutex: kmutex.New(),
+		ctxt:            httpCtxt,
+		fetchMutex:      kmutex.New(),
+		generatingDelta: make(chan struct{}, 1),
 	}
 }
 
@@ -98,7 +116,17 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			return
 		}
 		defer reader.Close()
-		if err := h.sendTools(w, reader, size); err != nil {
+		contentType := "application/x-tar-gz"
+		if r.URL.Query().Get(deltaFromParam) != "" {
+			deltaReader, deltaSize, err := h.getDeltaForRequest(r, st.State)
+			if err == nil {
+				defer deltaReader.Close()
+				reader, size, contentType = deltaReader, deltaSize, delta.ContentType
+			} else if !errors.Is(err, errors.NotFound) {
+				logger.Warningf("GET(%s) sending full agent binaries, delta failed: %v", r.URL, err)
+			}
+		}
+		if err := h.sendTools(w, reader, size, contentType); err != nil {
 			logger.Errorf("%v", err)
 		}
 	default:
@@ -324,11 +352,26 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(
 	if respSha256 != exactTools.SHA256 {
 		return errors.Errorf("hash mismatch for %s", exactTools.URL)
 	}
+	controllerConfig, err := systemState.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verifier, err := envtools.NewSignatureVerifier(controllerConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := verifier.Verify(exactTools.URL, data); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		return errors.Trace(err)
+	}
 
 	md := binarystorage.Metadata{
-		Version: storageVers.String(),
-		Size:    exactTools.Size,
-		SHA256:  exactTools.SHA256,
+		Version:       storageVers.String(),
+		Size:          exactTools.Size,
+		SHA256:        exactTools.SHA256,
+		ArchiveSHA256: hashArchive(data),
 	}
 	if err := storage.Add(data, md); err != nil {
 		return errors.Annotate(err, "error caching agent binaries")
@@ -337,11 +380,134 @@ func (h *toolsDownloadHandler) fetchAndCacheTools(
 	return nil
 }
 
-// sendTools streams the tools tarball to the client.
-func (h *toolsDownloadHandler) sendTools(w http.ResponseWriter, reader io.ReadCloser, size int64) error {
+// getDeltaForRequest returns the delta from the agent binaries named by
+// the request's delta-from parameter to those requested, with its size.
+// The delta is generated and cached in the controller's delta storage on
+// first use. An error satisfying errors.IsNotFound is returned when
+// either version isn't in tools storage, when no useful delta exists, or
+// when another delta is being generated.
+func (h *toolsDownloadHandler) getDeltaForRequest(r *http.Request, st *state.State) (_ io.ReadCloser, _ int64, err error) {
+	query := r.URL.Query()
+	from, err := version.ParseBinary(query.Get(deltaFromParam))
+	if err != nil {
+		return nil, 0, errors.Annotate(err, "error parsing delta version")
+	}
+	to, err := version.ParseBinary(query.Get(":version"))
+	if err != nil {
+		return nil, 0, errors.Annotate(err, "error parsing version")
+	}
+	logger.Debugf("request for agent binaries delta: %s to %s", from, to)
+
+	storage, err := st.ToolsStorage()
+	if err != nil {
+		return nil, 0, errors.Annotate(err, "error getting storage for agent binaries")
+	}
+	defer func() { _ = storage.Close() }()
+	fromMetadata, err := storage.Metadata(from.String())
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	toMetadata, err := storage.Metadata(to.String())
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if toMetadata.ArchiveSHA256 == "" {
+		// The rebuilt archive can't be checked.
+		return nil, 0, errors.NotFoundf("delta to agent binaries %s without archive SHA-256", to)
+	}
+
+	systemState, err := h.ctxt.statePool().SystemState()
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	deltaStorage, err := systemState.ToolsDeltaStorage()
+	if err != nil {
+		return nil, 0, errors.Annotate(err, "error getting storage for agent binaries deltas")
+	}
+	defer func() {
+		if err != nil {
+			_ = deltaStorage.Close()
+		}
+	}()
+
+	deltaVersion := binarystorage.DeltaVersion(fromMetadata, toMetadata)
+	locker := h.fetchMutex.Locker(deltaVersion)
+	locker.Lock()
+	defer locker.Unlock()
+
+	md, reader, err := deltaStorage.Open(deltaVersion)
+	if errors.Is(err, errors.NotFound) {
+		select {
+		case h.generatingDelta <- struct{}{}:
+			err = generateDelta(storage, deltaStorage, fromMetadata, toMetadata)
+			<-h.generatingDelta
+		default:
+			// Agents requesting this delta wait for it on the lock
+			// above; those requesting another get the full agent
+			// binaries rather than holding more in memory.
+			return nil, 0, errors.NotFoundf("delta from %s to %s while generating another", from, to)
+		}
+		if err == nil {
+			md, reader, err = deltaStorage.Open(deltaVersion)
+		}
+	}
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	if md.Size == 0 {
+		// An empty delta records that there's no useful delta.
+		_ = reader.Close()
+		return nil, 0, errors.NotFoundf("delta from %s to %s", from, to)
+	}
+	return &toolsReadCloser{f: reader, st: deltaStorage}, md.Size, nil
+}
+
+// generateDelta generates the delta between the agent binaries with the
+// given metadata, and stores it in deltaStorage. When there's no useful
+// delta, an empty one is stored so it isn't generated again.
+func generateDelta(
+	storage binarystorage.Storage,
+	deltaStorage binarystorage.Storage,
+	from, to binarystorage.Metadata,
+) error {
+	_, base, err := storage.Open(from.Version)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = base.Close() }()
+	_, target, err := storage.Open(to.Version)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = target.Close() }()
+
+	logger.Infof("generating agent binaries delta from %s to %s", from.Version, to.Version)
+	data, err := delta.Generate(base, target, to.SHA256, to.ArchiveSHA256)
+	if errors.Is(err, errors.NotSupported) {
+		logger.Infof("not using agent binaries delta from %s to %s: %v", from.Version, to.Version, err)
+		data = nil
+	} else if err != nil {
+		return errors.Annotate(err, "error generating agent binaries delta")
+	}
+
+	md := binarystorage.Metadata{
+		Version: binarystorage.DeltaVersion(from, to),
+		Size:    int64(len(data)),
+	}
+	if len(data) > 0 {
+		md.SHA256 = fmt.Sprintf("%x", sha256.Sum256(data))
+	}
+	if err := deltaStorage.Add(bytes.NewReader(data), md); err != nil {
+		return errors.Annotate(err, "error caching agent binaries delta")
+	}
+	return nil
+}
+
+// sendTools streams the tools tarball, or a delta, to the client.
+func (h *toolsDownloadHandler) sendTools(w http.ResponseWriter, reader io.ReadCloser, size int64, contentType string) error {
 	logger.Tracef("sending %d bytes", size)
 
-	w.Header().Set("Content-Type", "application/x-tar-gz")
+	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
 
 	if _, err := io.Copy(w, reader); err != nil {
@@ -370,10 +536,17 @@ func (h *toolsUploadHandler) processPost(r *http.Request, st *state.State) (*too
 		return nil, errors.BadRequestf("expected Content-Type: application/x-tar-gz, got: %v", contentType)
 	}
 
+	var signature []byte
+	if header := r.Header.Get(params.AgentBinarySignatureHeader); header != "" {
+		if signature, err = base64.StdEncoding.DecodeString(header); err != nil {
+			return nil, errors.NewBadRequest(err, "invalid agent binary signature")
+		}
+	}
+
 	logger.Debugf("request to upload agent binaries: %s", toolsVersion)
 	toolsVersions := []version.Binary{toolsVersion}
 	serverRoot := h.getServerRoot(r, query, st)
-	return h.handleUpload(r.Body, toolsVersions, serverRoot, st)
+	return h.handleUpload(r.Body, signature, toolsVersions, serverRoot, st)
 }
 
 func (h *toolsUploadHandler) getServerRoot(r *http.Request, query url.Values, st *state.State) string {
@@ -382,7 +555,10 @@ func (h *toolsUploadHandler) getServerRoot(r *http.Request, query url.Values, st
 }
 
 // handleUpload uploads the tools data from the reader to env storage as the specified version.
-func (h *toolsUploadHandler) handleUpload(r io.Reader, toolsVersions []version.Binary, serverRoot string, st *state.State) (*tools.Tools, error) {
+// If the handler verifies signatures, the data is checked against the signature uploaded with it.
+func (h *toolsUploadHandler) handleUpload(
+	r io.Reader, signature []byte, toolsVersions []version.Binary, serverRoot string, st *state.State,
+) (*tools.Tools, error) {
 	// Check if changes are allowed and the command may proceed.
 	blockChecker := common.NewBlockChecker(st)
 	if err := blockChecker.ChangeAllowed(); err != nil {
@@ -406,13 +582,21 @@ func (h *toolsUploadHandler) handleUpload(r io.Reader, toolsVersions []version.B
 	}
 
 	// TODO(wallyworld): check integrity of tools tarball.
+	if h.verifySignatures {
+		if err := verifyUploadedTools(st, toolsVersions[0], data, signature); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	archiveSHA256 := hashArchive(data)
 
 	// Store tools and metadata in tools storage.
 	for _, v := range toolsVersions {
 		metadata := binarystorage.Metadata{
-			Version: v.String(),
-			Size:    size,
-			SHA256:  sha256,
+			Version:       v.String(),
+			Size:          size,
+			SHA256:        sha256,
+			ArchiveSHA256: archiveSHA256,
 		}
 		logger.Debugf("uploading agent binaries %+v to storage", metadata)
 		if err := storage.Add(data, metadata); err != nil {
@@ -429,8 +613,42 @@ func (h *toolsUploadHandler) handleUpload(r io.Reader, toolsVersions []version.B
 	return tools, nil
 }
 
+// verifyUploadedTools checks the uploaded agent binary data against its
+// signature, leaving the data ready to be read again.
+func verifyUploadedTools(st *state.State, vers version.Binary, data io.ReadSeeker, signature []byte) error {
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verifier, err := envtools.NewSignatureVerifier(controllerConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := verifier.VerifySignature(vers.String(), data, signature); err != nil {
+		return errors.NewBadRequest(err, "")
+	}
+	_, err = data.Seek(0, io.SeekStart)
+	return errors.Trace(err)
+}
+
+// hashArchive returns the SHA-256 of the uncompressed archive of the
+// agent binaries, against which an archive rebuilt from a delta is
+// checked, leaving the data ready to be read again. An empty string is
+// returned if the data isn't a tarball, so no delta is generated.
+func hashArchive(data io.ReadSeeker) string {
+	sum, err := delta.ArchiveSHA256(data)
+	if err != nil {
+		logger.Warningf("cannot hash agent binaries archive: %v", err)
+	}
+	if _, err := data.Seek(0, io.SeekStart); err != nil {
+		logger.Warningf("cannot hash agent binaries archive: %v", err)
+		return ""
+	}
+	return sum
+}
+
 type cleanupCloser struct {
-	io.ReadCloser
+	io.ReadSeekCloser
 	cleanup func()
 }
 
@@ -438,10 +656,10 @@ func (c *cleanupCloser) Close() error {
 	if c.cleanup != nil {
 		c.cleanup()
 	}
-	return c.ReadCloser.Close()
+	return c.ReadSeekCloser.Close()
 }
 
-func tmpCacheAndHash(r io.Reader) (data io.ReadCloser, sha256hex string, size int64, err error) {
+func tmpCacheAndHash(r io.Reader) (data io.ReadSeekCloser, sha256hex string, size int64, err error) {
 	tmpFile, err := os.CreateTemp("", "jujutools*")
 	tmpFilename := tmpFile.Name()
 	cleanup := func() {
@@ -4,11 +4,15 @@
 package apiserver_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -24,6 +28,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
@@ -36,6 +41,7 @@ import (
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/tools/delta"
 )
 
 type baseToolsSuite struct {
@@ -256,6 +262,49 @@ func (s *toolsSuite) TestUpload(c *gc.C) {
 	c.Assert(uploadedData, gc.DeepEquals, toolsContent)
 	allMetadata := s.getToolsMetadataFromStorage(c, s.State)
 	c.Assert(allMetadata, jc.DeepEquals, []binarystorage.Metadata{metadata})
+	archiveSHA256, err := delta.ArchiveSHA256(bytes.NewReader(toolsContent))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.ArchiveSHA256, gc.Equals, archiveSHA256)
+}
+
+func (s *toolsSuite) TestUploadRequiresSignature(c *gc.C) {
+	expectedTools, v, toolsContent := s.setupToolsForUpload(c)
+	vers := v.String()
+
+	signer, allowedSigner := toolstesting.NewAgentBinarySigner(c)
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.AgentBinarySigners:         allowedSigner,
+		controller.RequireSignedAgentBinaries: true,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	upload := func(signature []byte) *http.Response {
+		headers := make(map[string]string)
+		if signature != nil {
+			headers[params.AgentBinarySignatureHeader] = base64.StdEncoding.EncodeToString(signature)
+		}
+		return s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+			Method:       "POST",
+			URL:          s.toolsURI("?binaryVersion=" + vers),
+			ContentType:  "application/x-tar-gz",
+			Body:         bytes.NewReader(toolsContent),
+			ExtraHeaders: headers,
+		})
+	}
+
+	resp := upload(nil)
+	s.assertJSONErrorResponse(c, resp, http.StatusBadRequest,
+		"require-signed-agent-binaries requires signed agent binaries: signature for "+vers+" not found")
+	resp = upload(toolstesting.SignAgentBinary(c, signer, []byte("other data")))
+	s.assertJSONErrorResponse(c, resp, http.StatusBadRequest,
+		"verifying signature of "+vers+": invalid signature: .*")
+	c.Assert(s.getToolsMetadataFromStorage(c, s.State), gc.HasLen, 0)
+
+	resp = upload(toolstesting.SignAgentBinary(c, signer, toolsContent))
+	expectedTools[0].URL = s.toolsURL("").String() + "/" + vers
+	s.assertUploadResponse(c, resp, expectedTools[0])
+	_, uploadedData := s.getToolsFromStorage(c, s.State, vers)
+	c.Assert(uploadedData, gc.DeepEquals, toolsContent)
 }
 
 func (s *toolsSuite) TestMigrateTools(c *gc.C) {
@@ -430,6 +479,89 @@ func (s *toolsSuite) TestDownloadTopLevelPath(c *gc.C) {
 	s.testDownload(c, tools, "")
 }
 
+func (s *toolsSuite) deltaRequest(c *gc.C, vers, from version.Binary) *http.Response {
+	url := s.toolsURL("delta-from=" + from.String())
+	url.Path = fmt.Sprintf("/model/%s/tools/%s", s.State.ModelUUID(), vers)
+	return apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{Method: "GET", URL: url.String()})
+}
+
+// storeTarball stores agent binaries with a jujud of the given content,
+// recording the SHA-256 of their archive if archiveSHA256 is set.
+func (s *toolsSuite) storeTarball(c *gc.C, vers version.Binary, content []byte, archiveSHA256 bool) *coretools.Tools {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "jujud", Mode: 0755, Size: int64(len(content))})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = tw.Write(content)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(zw.Close(), jc.ErrorIsNil)
+	metadata := binarystorage.Metadata{
+		Version: vers.String(),
+		Size:    int64(buf.Len()),
+		SHA256:  fmt.Sprintf("%x", sha256.Sum256(buf.Bytes())),
+	}
+	if archiveSHA256 {
+		var err error
+		metadata.ArchiveSHA256, err = delta.ArchiveSHA256(bytes.NewReader(buf.Bytes()))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return s.storeFakeTools(c, s.State, buf.String(), metadata)
+}
+
+func (s *toolsSuite) TestDownloadDelta(c *gc.C) {
+	content := make([]byte, 256*1024)
+	_, _ = rand.New(rand.NewSource(0)).Read(content)
+	from := version.MustParseBinary("2.9.98-ubuntu-amd64")
+	to := version.MustParseBinary("2.9.99-ubuntu-amd64")
+	s.storeTarball(c, from, content, true)
+	newContent := append(append([]byte(nil), content...), "changed"...)
+	tools := s.storeTarball(c, to, newContent, true)
+
+	for i := 0; i < 2; i++ {
+		// The second request is served the cached delta.
+		resp := s.deltaRequest(c, to, from)
+		body := apitesting.AssertResponse(c, resp, http.StatusOK, delta.ContentType)
+		c.Assert(len(body) < int(tools.Size)/2, jc.IsTrue)
+
+		dir := c.MkDir()
+		err := os.WriteFile(filepath.Join(dir, "jujud"), content, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		var rebuilt bytes.Buffer
+		err = delta.Apply(&rebuilt, bytes.NewReader(body), dir)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(fmt.Sprintf("%x", sha256.Sum256(rebuilt.Bytes())), gc.Equals, tools.SHA256)
+	}
+}
+
+func (s *toolsSuite) TestDownloadDeltaWithoutArchiveSHA256(c *gc.C) {
+	content := make([]byte, 256*1024)
+	_, _ = rand.New(rand.NewSource(0)).Read(content)
+	from := version.MustParseBinary("2.9.98-ubuntu-amd64")
+	to := version.MustParseBinary("2.9.99-ubuntu-amd64")
+	s.storeTarball(c, from, content, true)
+	tools := s.storeTarball(c, to, append(content, "changed"...), false)
+
+	// The rebuilt archive couldn't be checked, so the full agent
+	// binaries are sent.
+	resp := s.deltaRequest(c, to, from)
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/x-tar-gz")
+	c.Assert(int64(len(body)), gc.Equals, tools.Size)
+}
+
+func (s *toolsSuite) TestDownloadDeltaFallback(c *gc.C) {
+	tools := s.storeFakeTools(c, s.State, "abc", binarystorage.Metadata{
+		Version: testing.CurrentVersion().String(),
+		Size:    3,
+		SHA256:  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	})
+	// The base version isn't stored, so the full agent binaries are sent.
+	resp := s.deltaRequest(c, tools.Version, version.MustParseBinary("2.9.98-ubuntu-amd64"))
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/x-tar-gz")
+	c.Assert(string(body), gc.Equals, "abc")
+}
+
 func (s *toolsSuite) TestDownloadMissingConcurrent(c *gc.C) {
 	closer, testStorage, _ := envtesting.CreateLocalTestStorage(c)
 	defer closer.Close()
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/devices"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/resources"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/storage"
@@ -34,6 +35,18 @@ type Application interface {
 	// defined.
 	Scale(int) error
 
+	// Autoscale applies the autoscaling policy to the Application, so the
+	// substrate measures the load on its units. The substrate doesn't
+	// change the number of units itself; Juju applies the scale returned
+	// by AutoscaleRecommendation so units are removed cleanly. A nil
+	// policy removes any autoscaling.
+	Autoscale(*AutoscalingPolicy) error
+
+	// AutoscaleRecommendation returns the number of units recommended by
+	// the Application's autoscaling policy, within the policy's bounds.
+	// It returns a NotFound error if the Application isn't autoscaled.
+	AutoscaleRecommendation() (int, error)
+
 	// Trust sets up the role on the application's service account to
 	// give full access to the cluster.
 	Trust(bool) error
@@ -49,6 +62,7 @@ type Application interface {
 	Service() (*Service, error)
 
 	ServiceInterface
+	NetworkPolicyInterface
 }
 
 // ServicePort represents service ports mapping from service to units.
@@ -73,6 +87,33 @@ type ServiceInterface interface {
 	UpdatePorts(ports []ServicePort, updateContainerPorts bool) error
 }
 
+// NetworkPolicyIngress describes another application which is
+// allowed to connect to an application's units.
+type NetworkPolicyIngress struct {
+	// Application is the name of the application allowed to connect.
+	// If it is empty, connections are allowed from anywhere, as needed
+	// when the application is exposed or has cross model relations.
+	Application string
+
+	// Ports, if not empty, restricts the connections allowed from
+	// Application to these port ranges.
+	Ports []network.PortRange
+}
+
+// NetworkPolicyInterface provides the API to manage an application's
+// network policy.
+type NetworkPolicyInterface interface {
+	// EnsureNetworkPolicy creates or updates a network policy which only
+	// allows connections to the application's units from the specified
+	// ingress, the application's own units, the model operator and the
+	// controller.
+	EnsureNetworkPolicy(ingress []NetworkPolicyIngress) error
+
+	// RemoveNetworkPolicy removes the application's network policy,
+	// if there is one.
+	RemoveNetworkPolicy() error
+}
+
 // ApplicationState represents the application state.
 type ApplicationState struct {
 	DesiredReplicas int
@@ -135,6 +176,15 @@ type ApplicationConfig struct {
 	// Trust is set to true to give the application cloud access.
 	Trust bool
 
+	// MaxUnavailable, if set, is the non-zero number ("1") or percentage ("25%")
+	// of units which may be voluntarily disrupted at once, such as when
+	// a node is drained.
+	MaxUnavailable string
+
+	// SpreadZones is set to true to spread the units across
+	// availability zones.
+	SpreadZones bool
+
 	// InitialScale is used to provide the initial desired scale of the application.
 	// After the application is created, InitialScale has no effect.
 	InitialScale int
@@ -0,0 +1,124 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AutoscalingPolicy describes how the number of units of an
+// application is scaled by the substrate.
+type AutoscalingPolicy struct {
+	// MinUnits is the lower bound on the number of units.
+	MinUnits int
+
+	// MaxUnits is the upper bound on the number of units.
+	MaxUnits int
+
+	// CPUPercent, if set, is the target average CPU utilisation
+	// of the units, as a percentage of the requested CPU.
+	CPUPercent int
+
+	// MemoryPercent, if set, is the target average memory utilisation
+	// of the units, as a percentage of the requested memory.
+	MemoryPercent int
+
+	// Metric, if set, is the name of a custom per unit metric to scale on.
+	Metric string
+
+	// MetricTarget is the target average value of Metric.
+	MetricTarget string
+}
+
+// ParseAutoscalingPolicy parses an autoscaling policy of the form
+// "min=2,max=10,cpu=70,memory=80,metric=requests-per-second:100".
+// Only max and at least one of cpu, memory or metric are required;
+// min defaults to 1.
+func ParseAutoscalingPolicy(in string) (*AutoscalingPolicy, error) {
+	policy := &AutoscalingPolicy{MinUnits: 1}
+	for _, field := range strings.Split(in, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, errors.NotValidf("autoscaling field %q", field)
+		}
+		var err error
+		switch key {
+		case "min":
+			policy.MinUnits, err = parsePositiveInt(key, value)
+		case "max":
+			policy.MaxUnits, err = parsePositiveInt(key, value)
+		case "cpu":
+			policy.CPUPercent, err = parsePositiveInt(key, value)
+		case "memory":
+			policy.MemoryPercent, err = parsePositiveInt(key, value)
+		case "metric":
+			name, target, ok := strings.Cut(value, ":")
+			if !ok || name == "" {
+				return nil, errors.NotValidf("autoscaling metric %q, expected <name>:<target>", value)
+			}
+			if _, err := resource.ParseQuantity(target); err != nil {
+				return nil, errors.NotValidf("autoscaling metric %q target %q", name, target)
+			}
+			policy.Metric, policy.MetricTarget = name, target
+		default:
+			return nil, errors.NotValidf("autoscaling field %q", key)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return policy, nil
+}
+
+func parsePositiveInt(key, value string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		return 0, errors.NotValidf("autoscaling %s %q", key, value)
+	}
+	return i, nil
+}
+
+// Validate returns an error if the policy is not valid.
+func (p *AutoscalingPolicy) Validate() error {
+	if p.MinUnits < 1 {
+		return errors.NotValidf("autoscaling min %d", p.MinUnits)
+	}
+	if p.MaxUnits < p.MinUnits {
+		return errors.NotValidf("autoscaling max %d less than min %d", p.MaxUnits, p.MinUnits)
+	}
+	if p.CPUPercent == 0 && p.MemoryPercent == 0 && p.Metric == "" {
+		return errors.NotValidf("autoscaling policy without a cpu, memory or metric target")
+	}
+	return nil
+}
+
+// String returns the policy in the form accepted by ParseAutoscalingPolicy.
+func (p *AutoscalingPolicy) String() string {
+	fields := []string{
+		fmt.Sprintf("min=%d", p.MinUnits),
+		fmt.Sprintf("max=%d", p.MaxUnits),
+	}
+	if p.CPUPercent > 0 {
+		fields = append(fields, fmt.Sprintf("cpu=%d", p.CPUPercent))
+	}
+	if p.MemoryPercent > 0 {
+		fields = append(fields, fmt.Sprintf("memory=%d", p.MemoryPercent))
+	}
+	if p.Metric != "" {
+		fields = append(fields, fmt.Sprintf("metric=%s:%s", p.Metric, p.MetricTarget))
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/testing"
+)
+
+type AutoscalingSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&AutoscalingSuite{})
+
+func (s *AutoscalingSuite) TestParseAutoscalingPolicy(c *gc.C) {
+	for i, t := range []struct {
+		in       string
+		expected caas.AutoscalingPolicy
+		str      string
+	}{{
+		in:       "max=5,cpu=70",
+		expected: caas.AutoscalingPolicy{MinUnits: 1, MaxUnits: 5, CPUPercent: 70},
+		str:      "min=1,max=5,cpu=70",
+	}, {
+		in:       "min=2, max=10, cpu=70, memory=80",
+		expected: caas.AutoscalingPolicy{MinUnits: 2, MaxUnits: 10, CPUPercent: 70, MemoryPercent: 80},
+		str:      "min=2,max=10,cpu=70,memory=80",
+	}, {
+		in: "min=3,max=3,metric=requests-per-second:500m",
+		expected: caas.AutoscalingPolicy{
+			MinUnits: 3, MaxUnits: 3, Metric: "requests-per-second", MetricTarget: "500m",
+		},
+		str: "min=3,max=3,metric=requests-per-second:500m",
+	}} {
+		c.Logf("test %d: %s", i, t.in)
+		policy, err := caas.ParseAutoscalingPolicy(t.in)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(*policy, jc.DeepEquals, t.expected)
+		c.Assert(policy.String(), gc.Equals, t.str)
+	}
+}
+
+func (s *AutoscalingSuite) TestParseAutoscalingPolicyInvalid(c *gc.C) {
+	for i, t := range []struct {
+		in  string
+		err string
+	}{{
+		in:  "",
+		err: `autoscaling max 0 less than min 1 not valid`,
+	}, {
+		in:  "max=5",
+		err: `autoscaling policy without a cpu, memory or metric target not valid`,
+	}, {
+		in:  "min=5,max=2,cpu=50",
+		err: `autoscaling max 2 less than min 5 not valid`,
+	}, {
+		in:  "min=0,max=2,cpu=50",
+		err: `autoscaling min "0" not valid`,
+	}, {
+		in:  "max=two,cpu=50",
+		err: `autoscaling max "two" not valid`,
+	}, {
+		in:  "max=2,gpu=50",
+		err: `autoscaling field "gpu" not valid`,
+	}, {
+		in:  "max=2,cpu",
+		err: `autoscaling field "cpu" not valid`,
+	}, {
+		in:  "max=2,metric=rps",
+		err: `autoscaling metric "rps", expected <name>:<target> not valid`,
+	}, {
+		in:  "max=2,metric=rps:lots",
+		err: `autoscaling metric "rps" target "lots" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, t.in)
+		_, err := caas.ParseAutoscalingPolicy(t.in)
+		c.Assert(err, gc.ErrorMatches, t.err)
+	}
+}
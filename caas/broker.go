@@ -229,6 +229,10 @@ type Broker interface {
 
 	// ProxyManager provides methods for managing application proxy connections.
 	ProxyManager
+
+	// EventWatcher provides an API for watching the events recorded
+	// against the model's workloads.
+	EventWatcher
 }
 
 // ApplicationBroker provides an API for accessing the broker interface for
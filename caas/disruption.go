@@ -0,0 +1,32 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ParseMaxUnavailable parses the number of units of an application which
+// may be disrupted at once, either a count like "1" or a percentage of the
+// application's units like "25%". Zero is rejected, as it would block the
+// voluntary disruptions needed to drain nodes and roll out upgrades.
+func ParseMaxUnavailable(in string) (intstr.IntOrString, error) {
+	in = strings.TrimSpace(in)
+	if percent, ok := strings.CutSuffix(in, "%"); ok {
+		p, err := strconv.Atoi(percent)
+		if err != nil || p < 1 || p > 100 {
+			return intstr.IntOrString{}, errors.NotValidf("max unavailable %q", in)
+		}
+		return intstr.FromString(in), nil
+	}
+	n, err := strconv.Atoi(in)
+	if err != nil || n < 1 {
+		return intstr.IntOrString{}, errors.NotValidf("max unavailable %q", in)
+	}
+	return intstr.FromInt(n), nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/testing"
+)
+
+type DisruptionSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&DisruptionSuite{})
+
+func (s *DisruptionSuite) TestParseMaxUnavailable(c *gc.C) {
+	for i, t := range []struct {
+		in       string
+		expected intstr.IntOrString
+	}{
+		{in: "1", expected: intstr.FromInt(1)},
+		{in: "2", expected: intstr.FromInt(2)},
+		{in: "25%", expected: intstr.FromString("25%")},
+		{in: " 100% ", expected: intstr.FromString("100%")},
+	} {
+		c.Logf("test %d: %q", i, t.in)
+		value, err := caas.ParseMaxUnavailable(t.in)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(value, jc.DeepEquals, t.expected)
+	}
+}
+
+func (s *DisruptionSuite) TestParseMaxUnavailableInvalid(c *gc.C) {
+	for i, in := range []string{"", "0", "0%", "-1", "one", "101%", "%", "1.5"} {
+		c.Logf("test %d: %q", i, in)
+		_, err := caas.ParseMaxUnavailable(in)
+		c.Assert(err, gc.ErrorMatches, `max unavailable ".*" not valid`)
+	}
+}
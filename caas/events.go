@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+import (
+	"time"
+
+	"github.com/juju/juju/core/watcher"
+)
+
+const (
+	// EventTypeNormal is the type of events reporting routine activity,
+	// such as a container being started.
+	EventTypeNormal = "Normal"
+
+	// EventTypeWarning is the type of events reporting a problem, such
+	// as a pod which can't be scheduled or an image which can't be pulled.
+	EventTypeWarning = "Warning"
+)
+
+// Event is an event recorded by the container orchestration layer
+// against one of the Juju managed objects in a model.
+type Event struct {
+	// UID uniquely identifies the event.
+	UID string
+
+	// ApplicationName is the name of the application the event relates to.
+	ApplicationName string
+
+	// UnitName is the name of the unit the event relates to, if any.
+	UnitName string
+
+	// ObjectKind is the kind of the object the event was recorded against.
+	ObjectKind string
+
+	// ObjectName is the name of the object the event was recorded against.
+	ObjectName string
+
+	// Type is either EventTypeNormal or EventTypeWarning.
+	Type string
+
+	// Reason is a short, machine readable reason for the event.
+	Reason string
+
+	// Message is a human readable description of the event.
+	Message string
+
+	// Count is the number of times the event has occurred.
+	Count int32
+
+	// Time is when the event most recently occurred.
+	Time time.Time
+}
+
+// IsWarning returns true if the event reports a problem.
+func (e Event) IsWarning() bool {
+	return e.Type == EventTypeWarning
+}
+
+// EventWatcher provides an API for watching the events recorded
+// against the Juju managed objects in a model.
+type EventWatcher interface {
+	// WatchEvents returns a watcher which notifies when events are
+	// recorded against the model's Juju managed objects.
+	WatchEvents() (watcher.NotifyWatcher, error)
+
+	// Events returns the events currently recorded against the model's
+	// Juju managed objects. It reads from the cache kept up to date by
+	// the watcher returned by WatchEvents, which must still be running.
+	Events() ([]Event, error)
+}
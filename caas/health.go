@@ -0,0 +1,33 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caas
+
+// ClusterHealth describes the result of checking the health of the API
+// endpoints a broker can use to reach its cluster.
+type ClusterHealth struct {
+	// Endpoint is the API endpoint the broker is using.
+	Endpoint string
+
+	// Unhealthy maps each endpoint which failed its health check to the
+	// reason it failed.
+	Unhealthy map[string]string
+}
+
+// ClusterHealthChecker is implemented by brokers which can fail over
+// between several API endpoints for the same cluster.
+type ClusterHealthChecker interface {
+	// CheckClusterHealth checks the cluster's API endpoints in priority
+	// order and switches the broker to the first healthy one. An error
+	// satisfying errors.Unauthorized is returned if no endpoint accepts
+	// the cloud credential; any other error means no endpoint is healthy.
+	CheckClusterHealth() (ClusterHealth, error)
+
+	// ClusterEndpoint returns the API endpoint the broker is using.
+	ClusterEndpoint() string
+
+	// HasFailoverEndpoints reports whether the broker has any API
+	// endpoints to fail over to. Cluster health only needs checking
+	// if it does.
+	HasFailoverEndpoints() bool
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/featureflag"
 	"github.com/juju/loggo"
+	"github.com/juju/retry"
 	"github.com/juju/version/v2"
 	"github.com/kr/pretty"
 	appsv1 "k8s.io/api/apps/v1"
@@ -182,6 +183,10 @@ func (a *app) Ensure(config caas.ApplicationConfig) (err error) {
 		return errors.Annotatef(err, "ensuring the default service %q", a.name)
 	}
 
+	if err := a.applyDisruptionBudget(applier, config); err != nil {
+		return errors.Annotatef(err, "applying pod disruption budget")
+	}
+
 	// Set up the parameters for creating charm storage (if required).
 	podSpec, err := a.ApplicationPodSpec(config)
 	if err != nil {
@@ -317,6 +322,16 @@ func (a *app) Ensure(config caas.ApplicationConfig) (err error) {
 			return errors.Trace(err)
 		}
 
+		if exists && volumeClaimTemplatesGrown(ss.Spec.VolumeClaimTemplates, statefulset.Spec.VolumeClaimTemplates) {
+			// Volume claim templates can't be updated, so the stateful set
+			// is deleted without its pods and created again below. The new
+			// stateful set adopts the existing pods and claims.
+			logger.Infof("recreating stateful set %q to resize its volume claim templates", a.name)
+			if err := a.orphanStatefulSet(ss); err != nil {
+				return errors.Trace(err)
+			}
+			statefulset.Spec.Replicas = ss.Spec.Replicas
+		}
 		applier.Apply(&statefulset)
 	case caas.DeploymentStateless:
 		exists := true
@@ -405,7 +420,13 @@ func (a *app) Ensure(config caas.ApplicationConfig) (err error) {
 		return errors.NotSupportedf("unknown deployment type")
 	}
 
-	return applier.Run(context.Background(), a.client, false)
+	if err := applier.Run(context.Background(), a.client, false); err != nil {
+		return errors.Trace(err)
+	}
+	if !config.SpreadZones {
+		return errors.Trace(a.clearTopologySpreadConstraints(context.Background()))
+	}
+	return nil
 }
 
 func (a *app) applyServiceAccountAndSecrets(applier resources.Applier, config caas.ApplicationConfig) error {
@@ -862,6 +883,65 @@ func (a *app) getStatefulSet() (*resources.StatefulSet, error) {
 	return ss, nil
 }
 
+// orphanStatefulSet deletes the stateful set, leaving its pods and claims
+// in place, and waits for the deletion to complete.
+func (a *app) orphanStatefulSet(ss *resources.StatefulSet) error {
+	api := a.client.AppsV1().StatefulSets(a.namespace)
+	orphan := metav1.DeletePropagationOrphan
+	err := api.Delete(context.TODO(), ss.Name, metav1.DeleteOptions{
+		PropagationPolicy: &orphan,
+		Preconditions:     &metav1.Preconditions{UID: &ss.UID},
+	})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "deleting stateful set %q", ss.Name)
+	}
+
+	errStillExists := errors.Errorf("stateful set %q still exists", ss.Name)
+	err = retry.Call(retry.CallArgs{
+		Func: func() error {
+			_, err := api.Get(context.TODO(), ss.Name, metav1.GetOptions{})
+			if k8serrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			return errStillExists
+		},
+		IsFatalError: func(err error) bool {
+			return err != errStillExists
+		},
+		Attempts: 30,
+		Delay:    time.Second,
+		Clock:    a.clock,
+	})
+	if err != nil {
+		return errors.Annotatef(retry.LastError(err), "waiting for stateful set %q to be deleted", ss.Name)
+	}
+	return nil
+}
+
+// volumeClaimTemplatesGrown reports whether any of the desired volume claim
+// templates requests more storage than the existing template of that name.
+func volumeClaimTemplatesGrown(existing, desired []corev1.PersistentVolumeClaim) bool {
+	current := make(map[string]resource.Quantity)
+	for _, pvc := range existing {
+		current[pvc.Name] = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	for _, pvc := range desired {
+		size, ok := current[pvc.Name]
+		if !ok {
+			continue
+		}
+		requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if requested.Cmp(size) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *app) getDeployment() (*resources.Deployment, error) {
 	ss := resources.NewDeployment(a.name, a.namespace, nil)
 	if err := ss.Get(context.Background(), a.client); err != nil {
@@ -1010,6 +1090,9 @@ func (a *app) Delete() error {
 	applier.Delete(resources.NewClusterRoleBinding(a.qualifiedClusterName(), nil))
 	applier.Delete(resources.NewClusterRole(a.qualifiedClusterName(), nil))
 	applier.Delete(resources.NewServiceAccount(a.serviceAccountName(), a.namespace, nil))
+	applier.Delete(resources.NewNetworkPolicy(a.name, a.namespace, nil))
+	applier.Delete(resources.NewHorizontalPodAutoscaler(a.name, a.namespace, nil))
+	applier.Delete(resources.NewPodDisruptionBudget(a.name, a.namespace, nil))
 
 	// Cleanup lists of resources.
 	cleanup := []resources.Resource(nil)
@@ -1793,6 +1876,7 @@ func (a *app) ApplicationPodSpec(config caas.ApplicationConfig) (*corev1.PodSpec
 	if err != nil {
 		return nil, errors.Annotate(err, "processing constraints")
 	}
+	spec.TopologySpreadConstraints = a.topologySpreadConstraints(config)
 
 	if requireSecurityContext {
 		// Rootless charms are any charm after juju 3.5 that declare
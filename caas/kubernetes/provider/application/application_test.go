@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
 
@@ -539,6 +540,82 @@ func (s *applicationSuite) TestEnsureStateful(c *gc.C) {
 	s.assertDelete(c, app)
 }
 
+func (s *applicationSuite) existingStatefulSet(c *gc.C, size string) {
+	_, err := s.client.AppsV1().StatefulSets("test").Create(context.TODO(), &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gitlab",
+			Namespace:   "test",
+			UID:         "gitlab-uid",
+			Annotations: map[string]string{"app.juju.is/uuid": "appuuid"},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(2),
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "gitlab-database-appuuid"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: k8sresource.MustParse(size),
+						},
+					},
+				},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.ClearActions()
+}
+
+func (s *applicationSuite) statefulSetDeletes() []metav1.DeleteOptions {
+	var deletes []metav1.DeleteOptions
+	for _, action := range s.client.Actions() {
+		if del, ok := action.(k8stesting.DeleteAction); ok && del.GetResource().Resource == "statefulsets" {
+			deletes = append(deletes, del.GetDeleteOptions())
+		}
+	}
+	return deletes
+}
+
+func (s *applicationSuite) TestEnsureStatefulResizedStorage(c *gc.C) {
+	s.existingStatefulSet(c, "50Mi")
+
+	app, _ := s.getApp(c, caas.DeploymentStateful, false)
+	s.assertEnsure(
+		c, app, false, constraints.Value{}, true, false, "", func() {
+			// The stateful set is recreated, without deleting its pods, with
+			// the resized claim template and the existing scale.
+			orphan := metav1.DeletePropagationOrphan
+			uid := types.UID("gitlab-uid")
+			c.Assert(s.statefulSetDeletes(), gc.DeepEquals, []metav1.DeleteOptions{{
+				PropagationPolicy: &orphan,
+				Preconditions:     &metav1.Preconditions{UID: &uid},
+			}})
+
+			ss, err := s.client.AppsV1().StatefulSets("test").Get(context.TODO(), "gitlab", metav1.GetOptions{})
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(ss.Spec.Replicas, gc.DeepEquals, pointer.Int32Ptr(2))
+			c.Assert(ss.Spec.VolumeClaimTemplates, gc.HasLen, 1)
+			size := ss.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+			c.Assert(size.String(), gc.Equals, "100Mi")
+		},
+	)
+}
+
+func (s *applicationSuite) TestEnsureStatefulUnchangedStorage(c *gc.C) {
+	s.existingStatefulSet(c, "100Mi")
+
+	app, _ := s.getApp(c, caas.DeploymentStateful, false)
+	s.assertEnsure(
+		c, app, false, constraints.Value{}, true, false, "", func() {
+			c.Assert(s.statefulSetDeletes(), gc.HasLen, 0)
+
+			ss, err := s.client.AppsV1().StatefulSets("test").Get(context.TODO(), "gitlab", metav1.GetOptions{})
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(ss.Spec.Replicas, gc.DeepEquals, pointer.Int32Ptr(2))
+		},
+	)
+}
+
 func (s *applicationSuite) TestEnsureStatefulRootless35(c *gc.C) {
 	app, _ := s.getApp(c, caas.DeploymentStateful, false)
 	s.assertEnsure(
@@ -1157,6 +1234,9 @@ func (s *applicationSuite) TestDeleteStateful(c *gc.C) {
 		s.applier.EXPECT().Delete(resources.NewClusterRoleBinding("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewClusterRole("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewServiceAccount("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewNetworkPolicy("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewHorizontalPodAutoscaler("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewPodDisruptionBudget("gitlab", "test", nil)),
 		s.applier.EXPECT().Run(context.Background(), s.client, false).Return(nil),
 	)
 	c.Assert(app.Delete(), jc.ErrorIsNil)
@@ -1175,6 +1255,9 @@ func (s *applicationSuite) TestDeleteStateless(c *gc.C) {
 		s.applier.EXPECT().Delete(resources.NewClusterRoleBinding("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewClusterRole("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewServiceAccount("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewNetworkPolicy("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewHorizontalPodAutoscaler("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewPodDisruptionBudget("gitlab", "test", nil)),
 		s.applier.EXPECT().Run(context.Background(), s.client, false).Return(nil),
 	)
 	c.Assert(app.Delete(), jc.ErrorIsNil)
@@ -1193,6 +1276,9 @@ func (s *applicationSuite) TestDeleteDaemon(c *gc.C) {
 		s.applier.EXPECT().Delete(resources.NewClusterRoleBinding("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewClusterRole("test-gitlab", nil)),
 		s.applier.EXPECT().Delete(resources.NewServiceAccount("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewNetworkPolicy("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewHorizontalPodAutoscaler("gitlab", "test", nil)),
+		s.applier.EXPECT().Delete(resources.NewPodDisruptionBudget("gitlab", "test", nil)),
 		s.applier.EXPECT().Run(context.Background(), s.client, false).Return(nil),
 	)
 	c.Assert(app.Delete(), jc.ErrorIsNil)
@@ -0,0 +1,197 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"context"
+	"math"
+
+	"github.com/juju/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider/resources"
+)
+
+// autoscaleTolerance is the relative difference between the current and
+// target metric values within which the scale isn't changed. It matches
+// the default tolerance of the kubernetes autoscaler.
+const autoscaleTolerance = 0.1
+
+// Autoscale creates or updates a horizontal pod autoscaler for the
+// application's workload from the policy. A nil policy removes it.
+//
+// The autoscaler is only used to measure the workload; scaling up and
+// down are disabled, since scaling the workload directly would remove
+// pods before Juju tears down their units. Juju applies the scale
+// returned by AutoscaleRecommendation instead.
+func (a *app) Autoscale(policy *caas.AutoscalingPolicy) error {
+	if policy == nil {
+		applier := a.newApplier()
+		applier.Delete(resources.NewHorizontalPodAutoscaler(a.name, a.namespace, nil))
+		return errors.Trace(applier.Run(context.Background(), a.client, false))
+	}
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	var target autoscalingv2.CrossVersionObjectReference
+	switch a.deploymentType {
+	case caas.DeploymentStateful:
+		target = autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       a.name,
+		}
+	case caas.DeploymentStateless:
+		target = autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       a.name,
+		}
+	default:
+		return errors.NotSupportedf("autoscaling %s application", a.deploymentType)
+	}
+
+	var metrics []autoscalingv2.MetricSpec
+	if policy.CPUPercent > 0 {
+		metrics = append(metrics, resourceUtilisationMetric(corev1.ResourceCPU, policy.CPUPercent))
+	}
+	if policy.MemoryPercent > 0 {
+		metrics = append(metrics, resourceUtilisationMetric(corev1.ResourceMemory, policy.MemoryPercent))
+	}
+	if policy.Metric != "" {
+		value, err := resource.ParseQuantity(policy.MetricTarget)
+		if err != nil {
+			return errors.NotValidf("autoscaling metric %q target %q", policy.Metric, policy.MetricTarget)
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: policy.Metric},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		})
+	}
+
+	hpa := resources.NewHorizontalPodAutoscaler(a.name, a.namespace, &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: a.labels(),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: target,
+			MinReplicas:    pointer.Int32(int32(policy.MinUnits)),
+			MaxReplicas:    int32(policy.MaxUnits),
+			Metrics:        metrics,
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleUp: &autoscalingv2.HPAScalingRules{
+					SelectPolicy: &disabledPolicy,
+				},
+				ScaleDown: &autoscalingv2.HPAScalingRules{
+					SelectPolicy: &disabledPolicy,
+				},
+			},
+		},
+	})
+	applier := a.newApplier()
+	applier.Apply(hpa)
+	return errors.Trace(applier.Run(context.Background(), a.client, false))
+}
+
+var disabledPolicy = autoscalingv2.DisabledPolicySelect
+
+// AutoscaleRecommendation returns the number of units the application's
+// horizontal pod autoscaler would scale to, computed from the metrics it
+// last observed in the same way as the autoscaler itself.
+func (a *app) AutoscaleRecommendation() (int, error) {
+	hpa := resources.NewHorizontalPodAutoscaler(a.name, a.namespace, nil)
+	if err := hpa.Get(context.Background(), a.client); err != nil {
+		return 0, errors.Trace(err)
+	}
+	current := int(hpa.Status.CurrentReplicas)
+	recommended, measured := 0, false
+	for _, metric := range hpa.Status.CurrentMetrics {
+		ratio, ok := metricUsageRatio(hpa.Spec.Metrics, metric)
+		if !ok {
+			continue
+		}
+		measured = true
+		scale := current
+		if math.Abs(ratio-1) > autoscaleTolerance {
+			scale = int(math.Ceil(ratio * float64(current)))
+		}
+		if scale > recommended {
+			recommended = scale
+		}
+	}
+	if !measured {
+		// Without usable metrics, only keep the scale within bounds.
+		recommended = current
+	}
+
+	minReplicas := 1
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = int(*hpa.Spec.MinReplicas)
+	}
+	if recommended < minReplicas {
+		recommended = minReplicas
+	}
+	if maxReplicas := int(hpa.Spec.MaxReplicas); recommended > maxReplicas {
+		recommended = maxReplicas
+	}
+	return recommended, nil
+}
+
+// metricUsageRatio returns the ratio of the observed metric value to the
+// target of the matching metric spec.
+func metricUsageRatio(specs []autoscalingv2.MetricSpec, status autoscalingv2.MetricStatus) (float64, bool) {
+	for _, spec := range specs {
+		if spec.Type != status.Type {
+			continue
+		}
+		switch spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if spec.Resource == nil || status.Resource == nil || spec.Resource.Name != status.Resource.Name {
+				continue
+			}
+			target := spec.Resource.Target.AverageUtilization
+			observed := status.Resource.Current.AverageUtilization
+			if target == nil || observed == nil || *target == 0 {
+				return 0, false
+			}
+			return float64(*observed) / float64(*target), true
+		case autoscalingv2.PodsMetricSourceType:
+			if spec.Pods == nil || status.Pods == nil || spec.Pods.Metric.Name != status.Pods.Metric.Name {
+				continue
+			}
+			target := spec.Pods.Target.AverageValue
+			observed := status.Pods.Current.AverageValue
+			if target == nil || observed == nil || target.MilliValue() == 0 {
+				return 0, false
+			}
+			return float64(observed.MilliValue()) / float64(target.MilliValue()), true
+		}
+	}
+	return 0, false
+}
+
+func resourceUtilisationMetric(name corev1.ResourceName, percent int) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: pointer.Int32(int32(percent)),
+			},
+		},
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/juju/juju/caas"
+)
+
+func (s *applicationSuite) TestAutoscale(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.Autoscale(&caas.AutoscalingPolicy{
+		MinUnits:      2,
+		MaxUnits:      10,
+		CPUPercent:    70,
+		MemoryPercent: 80,
+		Metric:        "requests-per-second",
+		MetricTarget:  "100",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	hpa, err := s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hpa.Labels, jc.DeepEquals, map[string]string{
+		"app.kubernetes.io/managed-by": "juju",
+		"app.kubernetes.io/name":       "gitlab",
+	})
+	target := resource.MustParse("100")
+	disabled := autoscalingv2.DisabledPolicySelect
+	c.Assert(hpa.Spec, jc.DeepEquals, autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       "gitlab",
+		},
+		MinReplicas: pointer.Int32(2),
+		MaxReplicas: 10,
+		Metrics: []autoscalingv2.MetricSpec{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: pointer.Int32(70),
+				},
+			},
+		}, {
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceMemory,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: pointer.Int32(80),
+				},
+			},
+		}, {
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "requests-per-second"},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &target,
+				},
+			},
+		}},
+		// Juju scales the application, the autoscaler only measures it.
+		Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+			ScaleUp:   &autoscalingv2.HPAScalingRules{SelectPolicy: &disabled},
+			ScaleDown: &autoscalingv2.HPAScalingRules{SelectPolicy: &disabled},
+		},
+	})
+
+	// Changing the policy replaces the metrics.
+	err = app.Autoscale(&caas.AutoscalingPolicy{
+		MinUnits:   1,
+		MaxUnits:   3,
+		CPUPercent: 50,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	hpa, err = s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hpa.Spec.MinReplicas, jc.DeepEquals, pointer.Int32(1))
+	c.Assert(hpa.Spec.MaxReplicas, gc.Equals, int32(3))
+	c.Assert(hpa.Spec.Metrics, gc.HasLen, 1)
+	c.Assert(hpa.Spec.Metrics[0].Resource.Name, gc.Equals, corev1.ResourceCPU)
+
+	// A nil policy removes the autoscaler.
+	err = app.Autoscale(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *applicationSuite) TestAutoscaleRecommendation(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	_, err := app.AutoscaleRecommendation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = app.Autoscale(&caas.AutoscalingPolicy{
+		MinUnits:     2,
+		MaxUnits:     10,
+		CPUPercent:   50,
+		Metric:       "requests-per-second",
+		MetricTarget: "100",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	setStatus := func(replicas int32, cpu int32, requests string) {
+		hpas := s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace)
+		hpa, err := hpas.Get(context.Background(), s.appName, metav1.GetOptions{})
+		c.Assert(err, jc.ErrorIsNil)
+		value := resource.MustParse(requests)
+		hpa.Status = autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: replicas,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: pointer.Int32(cpu)},
+				},
+			}, {
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricStatus{
+					Metric:  autoscalingv2.MetricIdentifier{Name: "requests-per-second"},
+					Current: autoscalingv2.MetricValueStatus{AverageValue: &value},
+				},
+			}},
+		}
+		_, err = hpas.UpdateStatus(context.Background(), hpa, metav1.UpdateOptions{})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The most demanding metric decides the scale.
+	setStatus(4, 75, "50")
+	scale, err := app.AutoscaleRecommendation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scale, gc.Equals, 6)
+
+	// Within the tolerance the scale is kept.
+	setStatus(4, 52, "95")
+	scale, err = app.AutoscaleRecommendation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scale, gc.Equals, 4)
+
+	// Metrics the autoscaler can't compare with a target keep the scale.
+	hpas := s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace)
+	hpa, err := hpas.Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	hpa.Status = autoscalingv2.HorizontalPodAutoscalerStatus{
+		CurrentReplicas: 5,
+		CurrentMetrics: []autoscalingv2.MetricStatus{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name: corev1.ResourceCPU,
+			},
+		}},
+	}
+	_, err = hpas.UpdateStatus(context.Background(), hpa, metav1.UpdateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	scale, err = app.AutoscaleRecommendation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scale, gc.Equals, 5)
+
+	// The scale is kept within the policy's bounds.
+	setStatus(4, 10, "10")
+	scale, err = app.AutoscaleRecommendation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scale, gc.Equals, 2)
+
+	setStatus(8, 500, "10")
+	scale, err = app.AutoscaleRecommendation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scale, gc.Equals, 10)
+}
+
+func (s *applicationSuite) TestAutoscaleStateless(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateless, false)
+	defer ctrl.Finish()
+
+	err := app.Autoscale(&caas.AutoscalingPolicy{MinUnits: 1, MaxUnits: 3, CPUPercent: 50})
+	c.Assert(err, jc.ErrorIsNil)
+	hpa, err := s.client.AutoscalingV2().HorizontalPodAutoscalers(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hpa.Spec.ScaleTargetRef, jc.DeepEquals, autoscalingv2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       "gitlab",
+	})
+}
+
+func (s *applicationSuite) TestAutoscaleDaemonNotSupported(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentDaemon, false)
+	defer ctrl.Finish()
+
+	err := app.Autoscale(&caas.AutoscalingPolicy{MinUnits: 1, MaxUnits: 3, CPUPercent: 50})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *applicationSuite) TestAutoscaleInvalidPolicy(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.Autoscale(&caas.AutoscalingPolicy{MinUnits: 3, MaxUnits: 1, CPUPercent: 50})
+	c.Assert(err, gc.ErrorMatches, "autoscaling max 1 less than min 3 not valid")
+}
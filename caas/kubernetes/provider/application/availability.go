@@ -0,0 +1,152 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider/resources"
+)
+
+// maxUnavailableAnnotation records the max unavailable limit configured
+// for the application on its pod disruption budget, so the budget can be
+// updated as the application scales.
+const maxUnavailableAnnotation = "app.juju.is/max-unavailable"
+
+// applyDisruptionBudget adds the application's pod disruption budget to the
+// applier, or removes it when the application has no max unavailable limit.
+func (a *app) applyDisruptionBudget(applier resources.Applier, config caas.ApplicationConfig) error {
+	if config.MaxUnavailable == "" || a.deploymentType == caas.DeploymentDaemon {
+		applier.Delete(resources.NewPodDisruptionBudget(a.name, a.namespace, nil))
+		return nil
+	}
+	scale, err := a.currentScale(context.Background())
+	if errors.Is(err, errors.NotFound) {
+		// The workload is created with the initial scale.
+		scale = config.InitialScale
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	pdb, err := a.disruptionBudget(config.MaxUnavailable, scale)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	applier.Apply(pdb)
+	return nil
+}
+
+// scaleDisruptionBudget updates any pod disruption budget of the
+// application for its new scale.
+func (a *app) scaleDisruptionBudget(ctx context.Context, scale int) error {
+	existing := resources.NewPodDisruptionBudget(a.name, a.namespace, nil)
+	err := existing.Get(ctx, a.client)
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	limit, ok := existing.Annotations[maxUnavailableAnnotation]
+	if !ok {
+		return nil
+	}
+	pdb, err := a.disruptionBudget(limit, scale)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(pdb.Apply(ctx, a.client))
+}
+
+// disruptionBudget returns the pod disruption budget limiting the
+// application to maxUnavailable disrupted units. The limit is only applied
+// while there is more than one unit, so a single unit application never
+// blocks a node drain.
+func (a *app) disruptionBudget(maxUnavailable string, scale int) (*resources.PodDisruptionBudget, error) {
+	limit, err := caas.ParseMaxUnavailable(maxUnavailable)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if scale < 2 {
+		limit = intstr.FromString("100%")
+	}
+	return resources.NewPodDisruptionBudget(a.name, a.namespace, &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: a.labels(),
+			Annotations: map[string]string{
+				maxUnavailableAnnotation: maxUnavailable,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &limit,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: a.selectorLabels(),
+			},
+		},
+	}), nil
+}
+
+// topologySpreadConstraints returns the constraints spreading the
+// application's units across zones. Spreading is best effort so units
+// are still scheduled on clusters with a single zone or unlabelled nodes.
+func (a *app) topologySpreadConstraints(config caas.ApplicationConfig) []corev1.TopologySpreadConstraint {
+	if !config.SpreadZones || a.deploymentType == caas.DeploymentDaemon {
+		return nil
+	}
+	return []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       corev1.LabelTopologyZone,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: a.selectorLabels(),
+		},
+	}}
+}
+
+// clearTopologySpreadConstraints removes any topology spread constraints
+// left on the workload from when zone spreading was enabled. A strategic
+// merge patch retains fields which are omitted, so they have to be
+// removed explicitly.
+func (a *app) clearTopologySpreadConstraints(ctx context.Context) error {
+	var (
+		constraints []corev1.TopologySpreadConstraint
+		patcher     func(data []byte) error
+	)
+	patchOpts := metav1.PatchOptions{FieldManager: resources.JujuFieldManager}
+	switch a.deploymentType {
+	case caas.DeploymentStateful:
+		ss, err := a.getStatefulSet()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		constraints = ss.Spec.Template.Spec.TopologySpreadConstraints
+		patcher = func(data []byte) error {
+			_, err := a.client.AppsV1().StatefulSets(a.namespace).Patch(ctx, a.name, types.MergePatchType, data, patchOpts)
+			return err
+		}
+	case caas.DeploymentStateless:
+		d, err := a.getDeployment()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		constraints = d.Spec.Template.Spec.TopologySpreadConstraints
+		patcher = func(data []byte) error {
+			_, err := a.client.AppsV1().Deployments(a.namespace).Patch(ctx, a.name, types.MergePatchType, data, patchOpts)
+			return err
+		}
+	default:
+		return nil
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+	err := patcher([]byte(`{"spec":{"template":{"spec":{"topologySpreadConstraints":null}}}}`))
+	return errors.Annotatef(err, "removing topology spread constraints from %q", a.name)
+}
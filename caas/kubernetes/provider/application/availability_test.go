@@ -0,0 +1,134 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"context"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/juju/juju/caas"
+	coreresources "github.com/juju/juju/core/resources"
+)
+
+func availabilityAppConfig(scale int, maxUnavailable string, spreadZones bool) caas.ApplicationConfig {
+	return caas.ApplicationConfig{
+		AgentVersion:       version.MustParse(defaultAgentVersion),
+		AgentImagePath:     "operator/image-path:1.1.1",
+		CharmBaseImagePath: "ubuntu@22.04",
+		Containers: map[string]caas.ContainerConfig{
+			"gitlab": {
+				Name: "gitlab",
+				Image: coreresources.DockerImageDetails{
+					RegistryPath: "docker.io/library/gitlab:latest",
+				},
+			},
+		},
+		InitialScale:   scale,
+		MaxUnavailable: maxUnavailable,
+		SpreadZones:    spreadZones,
+	}
+}
+
+func (s *applicationSuite) TestEnsurePodDisruptionBudget(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.Ensure(availabilityAppConfig(3, "1", false))
+	c.Assert(err, jc.ErrorIsNil)
+
+	pdb, err := s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdb.Labels, jc.DeepEquals, map[string]string{
+		"app.kubernetes.io/managed-by": "juju",
+		"app.kubernetes.io/name":       "gitlab",
+	})
+	c.Assert(pdb.Annotations, jc.DeepEquals, map[string]string{
+		"app.juju.is/max-unavailable": "1",
+	})
+	maxUnavailable := intstr.FromInt(1)
+	c.Assert(pdb.Spec, jc.DeepEquals, policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app.kubernetes.io/name": "gitlab"},
+		},
+	})
+
+	// Changing the limit updates the budget.
+	err = app.Ensure(availabilityAppConfig(3, "25%", false))
+	c.Assert(err, jc.ErrorIsNil)
+	pdb, err = s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdb.Spec.MaxUnavailable, jc.DeepEquals, &intstr.IntOrString{Type: intstr.String, StrVal: "25%"})
+
+	// Scaling to a single unit lifts the limit, so the unit never blocks
+	// a node drain.
+	err = app.Scale(1)
+	c.Assert(err, jc.ErrorIsNil)
+	pdb, err = s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdb.Spec.MaxUnavailable, jc.DeepEquals, &intstr.IntOrString{Type: intstr.String, StrVal: "100%"})
+
+	// The workload's current scale is used rather than the initial one.
+	err = app.Ensure(availabilityAppConfig(3, "25%", false))
+	c.Assert(err, jc.ErrorIsNil)
+	pdb, err = s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdb.Spec.MaxUnavailable, jc.DeepEquals, &intstr.IntOrString{Type: intstr.String, StrVal: "100%"})
+
+	// Scaling out again restores the limit.
+	err = app.Scale(2)
+	c.Assert(err, jc.ErrorIsNil)
+	pdb, err = s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdb.Spec.MaxUnavailable, jc.DeepEquals, &intstr.IntOrString{Type: intstr.String, StrVal: "25%"})
+
+	// As does unsetting the limit.
+	err = app.Ensure(availabilityAppConfig(3, "1", false))
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.Ensure(availabilityAppConfig(3, "", false))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.PolicyV1().PodDisruptionBudgets(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *applicationSuite) TestEnsurePodDisruptionBudgetInvalid(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.Ensure(availabilityAppConfig(3, "lots", false))
+	c.Assert(err, gc.ErrorMatches, `applying pod disruption budget: max unavailable "lots" not valid`)
+}
+
+func (s *applicationSuite) TestEnsureSpreadZones(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateless, false)
+	defer ctrl.Finish()
+
+	err := app.Ensure(availabilityAppConfig(3, "", true))
+	c.Assert(err, jc.ErrorIsNil)
+
+	d, err := s.client.AppsV1().Deployments(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(d.Spec.Template.Spec.TopologySpreadConstraints, jc.DeepEquals, []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app.kubernetes.io/name": "gitlab"},
+		},
+	}})
+
+	err = app.Ensure(availabilityAppConfig(3, "", false))
+	c.Assert(err, jc.ErrorIsNil)
+	d, err = s.client.AppsV1().Deployments(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(d.Spec.Template.Spec.TopologySpreadConstraints, gc.HasLen, 0)
+}
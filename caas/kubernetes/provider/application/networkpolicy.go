@@ -0,0 +1,125 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider/constants"
+	"github.com/juju/juju/caas/kubernetes/provider/resources"
+	"github.com/juju/juju/caas/kubernetes/provider/utils"
+	"github.com/juju/juju/core/network"
+)
+
+const (
+	// modelOperatorName and modelOperatorTarget match the labels the
+	// provider puts on the model operator pods.
+	modelOperatorName   = "modeloperator"
+	modelOperatorTarget = "model"
+
+	// controllerModelName is the name of the controller model. The
+	// namespace running the controller pods is labelled with it.
+	controllerModelName = "controller"
+)
+
+// EnsureNetworkPolicy creates or updates a network policy which only
+// allows connections to the application's pods from the specified
+// ingress, the application's own pods, the model operator and the
+// controller. Ingress without an application allows connections from
+// anywhere.
+func (a *app) EnsureNetworkPolicy(ingress []caas.NetworkPolicyIngress) error {
+	rules := []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{{
+			PodSelector: &metav1.LabelSelector{MatchLabels: a.selectorLabels()},
+		}},
+	}, {
+		From: []networkingv1.NetworkPolicyPeer{{
+			PodSelector: &metav1.LabelSelector{MatchLabels: a.modelOperatorSelectorLabels()},
+		}},
+	}, {
+		From: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: utils.LabelsForModel(controllerModelName, false),
+			},
+		}},
+	}}
+	for _, in := range ingress {
+		var rule networkingv1.NetworkPolicyIngressRule
+		if in.Application != "" {
+			rule.From = []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: utils.SelectorLabelsForApp(in.Application, a.legacyLabels),
+				},
+			}}
+		}
+		for _, p := range in.Ports {
+			port, err := convertNetworkPolicyPort(p)
+			if err != nil {
+				return errors.Annotatef(err, "network policy ingress from %q", in.Application)
+			}
+			rule.Ports = append(rule.Ports, port)
+		}
+		rules = append(rules, rule)
+	}
+
+	policy := resources.NewNetworkPolicy(a.name, a.namespace, &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: a.labels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: a.selectorLabels()},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	})
+	applier := a.newApplier()
+	applier.Apply(policy)
+	return errors.Trace(applier.Run(context.Background(), a.client, false))
+}
+
+// RemoveNetworkPolicy removes the application's network policy,
+// if there is one.
+func (a *app) RemoveNetworkPolicy() error {
+	applier := a.newApplier()
+	applier.Delete(resources.NewNetworkPolicy(a.name, a.namespace, nil))
+	return errors.Trace(applier.Run(context.Background(), a.client, false))
+}
+
+func (a *app) modelOperatorSelectorLabels() map[string]string {
+	if a.legacyLabels {
+		return utils.LabelForKeyValue(constants.LegacyLabelModelOperator, modelOperatorName)
+	}
+	return utils.LabelsForOperator(modelOperatorName, modelOperatorTarget, false)
+}
+
+func convertNetworkPolicyPort(in network.PortRange) (out networkingv1.NetworkPolicyPort, err error) {
+	var protocol corev1.Protocol
+	switch in.Protocol {
+	case "TCP", "tcp":
+		protocol = corev1.ProtocolTCP
+	case "UDP", "udp":
+		protocol = corev1.ProtocolUDP
+	case "SCTP", "sctp":
+		protocol = corev1.ProtocolSCTP
+	default:
+		return out, errors.NotValidf("protocol %q", in.Protocol)
+	}
+	port := intstr.FromInt(in.FromPort)
+	out = networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &port,
+	}
+	if in.ToPort > in.FromPort {
+		endPort := int32(in.ToPort)
+		out.EndPort = &endPort
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"context"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/network"
+)
+
+func (s *applicationSuite) TestEnsureNetworkPolicy(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+		Application: "mysql",
+	}, {
+		Application: "haproxy",
+		Ports: []network.PortRange{
+			network.MustParsePortRange("80/tcp"),
+			network.MustParsePortRange("8000-8080/tcp"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	policy, err := s.client.NetworkingV1().NetworkPolicies(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Labels, jc.DeepEquals, map[string]string{
+		"app.kubernetes.io/managed-by": "juju",
+		"app.kubernetes.io/name":       "gitlab",
+	})
+
+	tcp := corev1.ProtocolTCP
+	port80 := intstr.FromInt(80)
+	port8000 := intstr.FromInt(8000)
+	endPort := int32(8080)
+	c.Assert(policy.Spec, jc.DeepEquals, networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"app.kubernetes.io/name": "gitlab"},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "gitlab"},
+				},
+			}},
+		}, {
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"operator.juju.is/name":   "modeloperator",
+						"operator.juju.is/target": "model",
+					},
+				},
+			}},
+		}, {
+			From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"model.juju.is/name": "controller"},
+				},
+			}},
+		}, {
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "mysql"},
+				},
+			}},
+		}, {
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/name": "haproxy"},
+				},
+			}},
+			Ports: []networkingv1.NetworkPolicyPort{{
+				Protocol: &tcp,
+				Port:     &port80,
+			}, {
+				Protocol: &tcp,
+				Port:     &port8000,
+				EndPort:  &endPort,
+			}},
+		}},
+	})
+
+	// Removing a relation removes the corresponding rule.
+	err = app.EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+		Application: "haproxy",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = s.client.NetworkingV1().NetworkPolicies(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Spec.Ingress, gc.HasLen, 4)
+	c.Assert(policy.Spec.Ingress[3].From[0].PodSelector.MatchLabels, jc.DeepEquals, map[string]string{
+		"app.kubernetes.io/name": "haproxy",
+	})
+	c.Assert(policy.Spec.Ingress[3].Ports, gc.HasLen, 0)
+}
+
+func (s *applicationSuite) TestEnsureNetworkPolicyFromAnywhere(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	// Exposed applications and cross model relations need ingress
+	// from outside the model.
+	err := app.EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+		Ports: []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	policy, err := s.client.NetworkingV1().NetworkPolicies(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Spec.Ingress, gc.HasLen, 4)
+
+	tcp := corev1.ProtocolTCP
+	port443 := intstr.FromInt(443)
+	c.Assert(policy.Spec.Ingress[3], jc.DeepEquals, networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{
+			Protocol: &tcp,
+			Port:     &port443,
+		}},
+	})
+}
+
+func (s *applicationSuite) TestEnsureNetworkPolicyInvalidProtocol(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+		Application: "mysql",
+		Ports:       []network.PortRange{{FromPort: -1, ToPort: -1, Protocol: "icmp"}},
+	}})
+	c.Assert(err, gc.ErrorMatches, `network policy ingress from "mysql": protocol "icmp" not valid`)
+}
+
+func (s *applicationSuite) TestRemoveNetworkPolicy(c *gc.C) {
+	app, ctrl := s.getApp(c, caas.DeploymentStateful, false)
+	defer ctrl.Finish()
+
+	err := app.EnsureNetworkPolicy(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.NetworkingV1().NetworkPolicies(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.RemoveNetworkPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.NetworkingV1().NetworkPolicies(s.namespace).Get(context.Background(), s.appName, metav1.GetOptions{})
+	c.Assert(k8serrors.IsNotFound(err), jc.IsTrue)
+
+	// Removing a policy which doesn't exist is not an error.
+	err = app.RemoveNetworkPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+}
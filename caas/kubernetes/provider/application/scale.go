@@ -19,17 +19,19 @@ import (
 // be >= 0. Application units will be removed or added to meet the scale
 // defined.
 func (a *app) Scale(scaleTo int) error {
+	ctx := context.Background()
+	var err error
 	switch a.deploymentType {
 	case caas.DeploymentStateful:
-		return scale.PatchReplicasToScale(
-			context.Background(),
+		err = scale.PatchReplicasToScale(
+			ctx,
 			a.name,
 			int32(scaleTo),
 			scale.StatefulSetScalePatcher(a.client.AppsV1().StatefulSets(a.namespace)),
 		)
 	case caas.DeploymentStateless:
-		return scale.PatchReplicasToScale(
-			context.Background(),
+		err = scale.PatchReplicasToScale(
+			ctx,
 			a.name,
 			int32(scaleTo),
 			scale.DeploymentScalePatcher(a.client.AppsV1().Deployments(a.namespace)),
@@ -39,6 +41,10 @@ func (a *app) Scale(scaleTo int) error {
 			"application %q deployment type %q cannot be scaled",
 			a.name, a.deploymentType)
 	}
+	if err != nil {
+		return err
+	}
+	return errors.Annotatef(a.scaleDisruptionBudget(ctx, scaleTo), "scaling pod disruption budget for %q", a.name)
 }
 
 // currentScale returns the current scale in use for the applications. i.e how
@@ -57,6 +63,18 @@ func (a *app) currentScale(ctx context.Context) (int, error) {
 
 		return int(*ss.Spec.Replicas), nil
 
+	case caas.DeploymentStateless:
+		d, err := a.client.AppsV1().Deployments(a.namespace).Get(ctx, a.name, meta.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			err = errors.WithType(err, errors.NotFound)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("fetching scale for application %q deployment: %w",
+				a.name, err)
+		}
+
+		return int(*d.Spec.Replicas), nil
+
 	default:
 		return 0, fmt.Errorf("application %q deployment type %q is not supported for fetching scale",
 			a.name, a.deploymentType)
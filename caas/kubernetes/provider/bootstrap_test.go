@@ -70,9 +70,11 @@ func (s *bootstrapSuite) SetUpTest(c *gc.C) {
 	s.namespace = controllerName
 
 	cfg, err := config.New(config.UseDefaults, coretesting.FakeConfig().Merge(coretesting.Attrs{
-		config.NameKey:                  "controller-1",
-		k8sconstants.OperatorStorageKey: "",
-		k8sconstants.WorkloadStorageKey: "",
+		config.NameKey:                    "controller-1",
+		k8sconstants.OperatorStorageKey:   "",
+		k8sconstants.WorkloadStorageKey:   "",
+		k8sconstants.NetworkPoliciesKey:   false,
+		k8sconstants.FailoverEndpointsKey: "",
 	}))
 	c.Assert(err, jc.ErrorIsNil)
 	s.cfg = cfg
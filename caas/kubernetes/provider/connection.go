@@ -6,10 +6,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
 
+	"github.com/juju/juju/caas"
 	k8sconstants "github.com/juju/juju/caas/kubernetes/provider/constants"
 	k8sproxy "github.com/juju/juju/caas/kubernetes/provider/proxy"
 	"github.com/juju/juju/proxy"
@@ -87,3 +94,138 @@ func (k *kubernetesClient) ConnectionProxyInfo() (proxy.Proxier, error) {
 	}
 	return p, nil
 }
+
+// endpointHealthCheckTimeout is how long to wait for an API endpoint to
+// respond before it's considered unhealthy.
+const endpointHealthCheckTimeout = 10 * time.Second
+
+// CheckClusterHealth is part of the caas.ClusterHealthChecker interface.
+// The cloud endpoint is always tried first so that the broker fails back
+// to it once it recovers.
+func (k *kubernetesClient) CheckClusterHealth() (caas.ClusterHealth, error) {
+	k.lock.Lock()
+	k8sRestConfig := rest.CopyConfig(k.k8sCfgUnlocked)
+	k8sRestConfig.Host = k.cloudEndpointUnlocked
+	cfg := k.envCfgUnlocked
+	k.lock.Unlock()
+
+	brokerCfg, err := providerInstance.newConfig(cfg)
+	if err != nil {
+		return caas.ClusterHealth{}, errors.Trace(err)
+	}
+	health, err := checkClusterEndpoints(brokerCfg, k8sRestConfig, k.namespace, k.newClient)
+	if err != nil {
+		return health, errors.Trace(err)
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if current := k.k8sCfgUnlocked.Host; current != health.Endpoint {
+		logger.Warningf("switching cluster API endpoint from %q to %q", current, health.Endpoint)
+		k8sRestConfig.Host = health.Endpoint
+		if err := k.setRestConfigUnlocked(k8sRestConfig); err != nil {
+			return health, errors.Annotatef(err, "switching to cluster API endpoint %q", health.Endpoint)
+		}
+	}
+	return health, nil
+}
+
+// ClusterEndpoint is part of the caas.ClusterHealthChecker interface.
+func (k *kubernetesClient) ClusterEndpoint() string {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return k.k8sCfgUnlocked.Host
+}
+
+// HasFailoverEndpoints is part of the caas.ClusterHealthChecker interface.
+func (k *kubernetesClient) HasFailoverEndpoints() bool {
+	k.lock.Lock()
+	cfg := k.envCfgUnlocked
+	k.lock.Unlock()
+
+	brokerCfg, err := providerInstance.newConfig(cfg)
+	if err != nil {
+		logger.Warningf("cannot read failover endpoints: %v", err)
+		return false
+	}
+	return len(brokerCfg.failoverEndpoints()) > 0
+}
+
+// selectClusterEndpoint returns the rest config for the first healthy
+// cluster API endpoint. The config is returned unchanged if there are no
+// failover endpoints, or if none of the endpoints are healthy, in which
+// case the broker's own requests report the failure.
+func selectClusterEndpoint(
+	cfg *brokerConfig, k8sRestConfig *rest.Config, namespace string, newClient NewK8sClientFunc,
+) *rest.Config {
+	if len(cfg.failoverEndpoints()) == 0 {
+		return k8sRestConfig
+	}
+	health, err := checkClusterEndpoints(cfg, k8sRestConfig, namespace, newClient)
+	if err != nil {
+		logger.Warningf("%v", err)
+		return k8sRestConfig
+	}
+	if health.Endpoint != k8sRestConfig.Host {
+		logger.Infof("cluster API endpoint %q is unhealthy, using %q", k8sRestConfig.Host, health.Endpoint)
+	}
+	selected := rest.CopyConfig(k8sRestConfig)
+	selected.Host = health.Endpoint
+	return selected
+}
+
+// checkClusterEndpoints checks the cloud endpoint in the rest config and
+// then each failover endpoint, stopping at the first healthy one.
+func checkClusterEndpoints(
+	cfg *brokerConfig, k8sRestConfig *rest.Config, namespace string, newClient NewK8sClientFunc,
+) (caas.ClusterHealth, error) {
+	endpoints := append([]string{k8sRestConfig.Host}, cfg.failoverEndpoints()...)
+	health := caas.ClusterHealth{Unhealthy: make(map[string]string)}
+	checked := set.NewStrings()
+	unauthorized := 0
+	for _, endpoint := range endpoints {
+		if checked.Contains(endpoint) {
+			continue
+		}
+		checked.Add(endpoint)
+
+		endpointConfig := rest.CopyConfig(k8sRestConfig)
+		endpointConfig.Host = endpoint
+		endpointConfig.Timeout = endpointHealthCheckTimeout
+		err := checkClusterEndpoint(endpointConfig, namespace, newClient)
+		if err == nil {
+			health.Endpoint = endpoint
+			return health, nil
+		}
+		if k8serrors.IsUnauthorized(err) {
+			unauthorized++
+		}
+		health.Unhealthy[endpoint] = err.Error()
+	}
+	if unauthorized == checked.Size() {
+		return health, errors.Unauthorizedf("cloud credential rejected by all cluster API endpoints")
+	}
+	var failures []string
+	for _, endpoint := range checked.SortedValues() {
+		failures = append(failures, fmt.Sprintf("%s: %s", endpoint, health.Unhealthy[endpoint]))
+	}
+	return health, errors.Errorf("no healthy cluster API endpoint (%s)", strings.Join(failures, "; "))
+}
+
+// checkClusterEndpoint makes an authenticated request to the endpoint in
+// the rest config.
+func checkClusterEndpoint(k8sRestConfig *rest.Config, namespace string, newClient NewK8sClientFunc) error {
+	client, _, _, err := newClient(k8sRestConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if namespace == "" {
+		_, err = client.Discovery().ServerVersion()
+		return err
+	}
+	_, err = client.CoreV1().Namespaces().Get(context.TODO(), namespace, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
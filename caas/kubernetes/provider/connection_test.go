@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/juju/juju/caas"
+	k8sconstants "github.com/juju/juju/caas/kubernetes/provider/constants"
+)
+
+var _ = gc.Suite(&failoverSuite{})
+
+type failoverSuite struct {
+	fakeClientSuite
+
+	// endpointErrors holds the error returned by requests to each
+	// unhealthy endpoint.
+	endpointErrors map[string]error
+}
+
+func (s *failoverSuite) SetUpTest(c *gc.C) {
+	s.fakeClientSuite.SetUpTest(c)
+
+	var err error
+	s.cfg, err = s.cfg.Apply(map[string]interface{}{
+		k8sconstants.FailoverEndpointsKey: "https://backup-1, https://backup-2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.endpointErrors = make(map[string]error)
+}
+
+func (s *failoverSuite) newClient(cfg *rest.Config) (kubernetes.Interface, apiextensionsclientset.Interface, dynamic.Interface, error) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "namespaces", func(k8stesting.Action) (bool, k8sruntime.Object, error) {
+		err, unhealthy := s.endpointErrors[cfg.Host]
+		return unhealthy, nil, err
+	})
+	return clientset, s.mockApiextensionsClient, s.mockDynamicClient, nil
+}
+
+func (s *failoverSuite) setupFailoverBroker(c *gc.C) {
+	_, newK8sRestFunc := s.setupK8sRestClient(c, s.getNamespace())
+	s.setupBroker(c, s.newClient, newK8sRestFunc, func() (string, error) { return "appuuid", nil }, nil)
+}
+
+func (s *failoverSuite) TestBrokerUsesHealthyEndpoint(c *gc.C) {
+	s.endpointErrors["some-host"] = errors.New("connection refused")
+	s.setupFailoverBroker(c)
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "https://backup-1")
+}
+
+func (s *failoverSuite) TestCheckClusterHealthFailsOver(c *gc.C) {
+	s.setupFailoverBroker(c)
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "some-host")
+
+	s.endpointErrors["some-host"] = errors.New("connection refused")
+	s.endpointErrors["https://backup-1"] = errors.New("i/o timeout")
+	health, err := s.broker.CheckClusterHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, caas.ClusterHealth{
+		Endpoint: "https://backup-2",
+		Unhealthy: map[string]string{
+			"some-host":        "connection refused",
+			"https://backup-1": "i/o timeout",
+		},
+	})
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "https://backup-2")
+
+	// Once the cloud endpoint recovers, the broker fails back to it.
+	delete(s.endpointErrors, "some-host")
+	health, err = s.broker.CheckClusterHealth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Endpoint, gc.Equals, "some-host")
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "some-host")
+}
+
+func (s *failoverSuite) TestCheckClusterHealthNoHealthyEndpoint(c *gc.C) {
+	s.setupFailoverBroker(c)
+
+	s.endpointErrors["some-host"] = errors.New("connection refused")
+	s.endpointErrors["https://backup-1"] = errors.New("connection refused")
+	s.endpointErrors["https://backup-2"] = errors.New("connection refused")
+	_, err := s.broker.CheckClusterHealth()
+	c.Assert(err, gc.ErrorMatches, `no healthy cluster API endpoint \(https://backup-1: connection refused; https://backup-2: connection refused; some-host: connection refused\)`)
+	c.Assert(err, gc.Not(jc.ErrorIs), errors.Unauthorized)
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "some-host")
+}
+
+func (s *failoverSuite) TestCheckClusterHealthUnauthorized(c *gc.C) {
+	s.setupFailoverBroker(c)
+
+	unauthorized := k8serrors.NewUnauthorized("bad token")
+	s.endpointErrors["some-host"] = unauthorized
+	s.endpointErrors["https://backup-1"] = unauthorized
+	s.endpointErrors["https://backup-2"] = unauthorized
+	_, err := s.broker.CheckClusterHealth()
+	c.Assert(err, jc.ErrorIs, errors.Unauthorized)
+}
+
+func (s *failoverSuite) TestRemovingFailoverEndpointsSwitchesBack(c *gc.C) {
+	s.endpointErrors["some-host"] = errors.New("connection refused")
+	s.setupFailoverBroker(c)
+	c.Assert(s.broker.HasFailoverEndpoints(), jc.IsTrue)
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "https://backup-1")
+
+	cfg, err := s.cfg.Apply(map[string]interface{}{
+		k8sconstants.FailoverEndpointsKey: "",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.broker.HasFailoverEndpoints(), jc.IsFalse)
+	c.Assert(s.broker.ClusterEndpoint(), gc.Equals, "some-host")
+}
@@ -89,3 +89,14 @@ func DeletePropagationBackground() *metav1.DeletionPropagation {
 	v := metav1.DeletePropagationBackground
 	return &v
 }
+
+const (
+	// NetworkPoliciesKey is the model config attribute used to enable
+	// generating network policies for applications from their relations.
+	NetworkPoliciesKey = "network-policies"
+
+	// FailoverEndpointsKey is the model config attribute listing the
+	// API endpoints to fail over to, in priority order, when the cloud
+	// endpoint is unhealthy.
+	FailoverEndpointsKey = "failover-endpoints"
+)
@@ -22,6 +22,10 @@ const (
 	StorageProvisioner = "storage-provisioner"
 	StorageMedium      = "storage-medium"
 	StorageMode        = "storage-mode"
+
+	// StorageSnapshotClass is the name of the volume snapshot class
+	// used when taking snapshots of volumes in the pool.
+	StorageSnapshotClass = "snapshot-class"
 )
 
 const (
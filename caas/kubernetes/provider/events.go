@@ -5,13 +5,26 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/juju/errors"
 	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 
+	"github.com/juju/juju/caas"
 	"github.com/juju/juju/caas/kubernetes/provider/resources"
+	"github.com/juju/juju/caas/kubernetes/provider/utils"
 	"github.com/juju/juju/core/watcher"
 )
 
@@ -66,3 +79,198 @@ func (k *kubernetesClient) watchEvents(objName string, objKind string) (watcher.
 	)
 	return k.newWatcher(factory.Core().V1().Events().Informer(), objName, k.clock)
 }
+
+// eventCache holds the informer caches used to read the model's events and
+// the objects they were recorded against, without calling the API server.
+type eventCache struct {
+	events       corelisters.EventNamespaceLister
+	pods         corelisters.PodNamespaceLister
+	claims       corelisters.PersistentVolumeClaimNamespaceLister
+	statefulSets appslisters.StatefulSetNamespaceLister
+	deployments  appslisters.DeploymentNamespaceLister
+	daemonSets   appslisters.DaemonSetNamespaceLister
+
+	synced []cache.InformerSynced
+	stop   chan struct{}
+}
+
+// WatchEvents is part of the caas.EventWatcher interface.
+// It notifies when any event is recorded in the model's namespace, and keeps
+// the cache read by Events up to date until the watcher is stopped.
+func (k *kubernetesClient) WatchEvents() (watcher.NotifyWatcher, error) {
+	if k.namespace == "" {
+		return nil, errNoNamespace
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(k.client(), 0,
+		informers.WithNamespace(k.namespace),
+	)
+	coreInformers := factory.Core().V1()
+	appsInformers := factory.Apps().V1()
+	c := &eventCache{
+		pods:         coreInformers.Pods().Lister().Pods(k.namespace),
+		claims:       coreInformers.PersistentVolumeClaims().Lister().PersistentVolumeClaims(k.namespace),
+		statefulSets: appsInformers.StatefulSets().Lister().StatefulSets(k.namespace),
+		deployments:  appsInformers.Deployments().Lister().Deployments(k.namespace),
+		daemonSets:   appsInformers.DaemonSets().Lister().DaemonSets(k.namespace),
+		stop:         make(chan struct{}),
+	}
+	c.synced = []cache.InformerSynced{
+		coreInformers.Pods().Informer().HasSynced,
+		coreInformers.PersistentVolumeClaims().Informer().HasSynced,
+		appsInformers.StatefulSets().Informer().HasSynced,
+		appsInformers.Deployments().Informer().HasSynced,
+		appsInformers.DaemonSets().Informer().HasSynced,
+	}
+	// The event informer is run by the watcher, so it is only added to the
+	// factory after the informers for the other objects have been started.
+	factory.Start(c.stop)
+	eventInformer := coreInformers.Events()
+	c.events = eventInformer.Lister().Events(k.namespace)
+	c.synced = append(c.synced, eventInformer.Informer().HasSynced)
+
+	w, err := k.newWatcher(eventInformer.Informer(), k.namespace, k.clock)
+	if err != nil {
+		close(c.stop)
+		return nil, errors.Trace(err)
+	}
+	k.lock.Lock()
+	k.eventCacheUnlocked = c
+	k.lock.Unlock()
+	go func() {
+		_ = w.Wait()
+		k.lock.Lock()
+		if k.eventCacheUnlocked == c {
+			k.eventCacheUnlocked = nil
+		}
+		k.lock.Unlock()
+		close(c.stop)
+	}()
+	return w, nil
+}
+
+// Events is part of the caas.EventWatcher interface.
+// Only events recorded against objects owned by Juju are returned, mapped
+// to the application and, for pods, the unit they belong to.
+func (k *kubernetesClient) Events() ([]caas.Event, error) {
+	k.lock.Lock()
+	c := k.eventCacheUnlocked
+	k.lock.Unlock()
+	if c == nil {
+		return nil, errors.New("events are not being watched")
+	}
+	if !cache.WaitForCacheSync(c.stop, c.synced...) {
+		return nil, errors.New("events watcher stopped")
+	}
+	events, err := c.events.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Annotate(err, "listing events")
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
+	var result []caas.Event
+	for _, event := range events {
+		obj := event.InvolvedObject
+		objMeta, err := c.objectMeta(obj)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if objMeta == nil || !isK8sObjectOwnedByJuju(*objMeta) {
+			continue
+		}
+		appName := utils.AppNameFromLabels(labels.Set(objMeta.Labels))
+		if appName == "" {
+			continue
+		}
+		result = append(result, caas.Event{
+			UID:             string(event.UID),
+			ApplicationName: appName,
+			UnitName:        k.eventUnitName(appName, obj.Kind, objMeta),
+			ObjectKind:      obj.Kind,
+			ObjectName:      obj.Name,
+			Type:            event.Type,
+			Reason:          event.Reason,
+			Message:         strings.TrimSpace(event.Message),
+			Count:           eventCount(*event),
+			Time:            eventTime(*event),
+		})
+	}
+	return result, nil
+}
+
+// objectMeta returns the metadata of the object an event was recorded
+// against, or nil if the object is of a kind Juju doesn't manage or no
+// longer exists.
+func (c *eventCache) objectMeta(obj core.ObjectReference) (*metav1.ObjectMeta, error) {
+	var (
+		object metav1.Object
+		err    error
+	)
+	switch obj.Kind {
+	case "Pod":
+		object, err = c.pods.Get(obj.Name)
+	case "PersistentVolumeClaim":
+		object, err = c.claims.Get(obj.Name)
+	case "StatefulSet":
+		object, err = c.statefulSets.Get(obj.Name)
+	case "Deployment":
+		object, err = c.deployments.Get(obj.Name)
+	case "DaemonSet":
+		object, err = c.daemonSets.Get(obj.Name)
+	default:
+		return nil, nil
+	}
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting %s %q", strings.ToLower(obj.Kind), obj.Name)
+	}
+	return &metav1.ObjectMeta{
+		Name:        object.GetName(),
+		Labels:      object.GetLabels(),
+		Annotations: object.GetAnnotations(),
+	}, nil
+}
+
+// eventUnitName returns the name of the unit a pod belongs to, either
+// from the unit annotation or, for pods which haven't been annotated yet,
+// from the ordinal of a stateful set pod.
+func (k *kubernetesClient) eventUnitName(appName, kind string, objMeta *metav1.ObjectMeta) string {
+	if kind != "Pod" {
+		return ""
+	}
+	if unitName := objMeta.Annotations[utils.AnnotationUnitKey(k.IsLegacyLabels())]; unitName != "" {
+		return unitName
+	}
+	ordinal, ok := strings.CutPrefix(objMeta.Name, appName+"-")
+	if !ok {
+		return ""
+	}
+	if _, err := strconv.Atoi(ordinal); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", appName, ordinal)
+}
+
+func eventCount(event core.Event) int32 {
+	if event.Series != nil && event.Series.Count > event.Count {
+		return event.Series.Count
+	}
+	if event.Count == 0 {
+		return 1
+	}
+	return event.Count
+}
+
+func eventTime(event core.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"context"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3/workertest"
+	gc "gopkg.in/check.v1"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/juju/juju/caas"
+	k8swatcher "github.com/juju/juju/caas/kubernetes/provider/watcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&eventsSuite{})
+
+type eventsSuite struct {
+	fakeClientSuite
+}
+
+func (s *eventsSuite) SetUpTest(c *gc.C) {
+	s.fakeClientSuite.SetUpTest(c)
+	s.k8sWatcherFn = k8swatcher.NewKubernetesNotifyWatcher
+}
+
+func (s *eventsSuite) createEvent(c *gc.C, name, kind, objName, eventType, reason string, when time.Time) {
+	_, err := s.mockEvents.Create(context.Background(), &core.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: s.namespace,
+			UID:       types.UID("uid-" + name),
+		},
+		InvolvedObject: core.ObjectReference{Kind: kind, Name: objName},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " happened ",
+		Count:          2,
+		LastTimestamp:  v1.NewTime(when),
+	}, v1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *eventsSuite) TestEvents(c *gc.C) {
+	jujuLabels := map[string]string{
+		"app.kubernetes.io/managed-by": "juju",
+		"app.kubernetes.io/name":       "mariadb",
+	}
+	for _, pod := range []*core.Pod{{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "mariadb-0",
+			Labels:      jujuLabels,
+			Annotations: map[string]string{"unit.juju.is/id": "mariadb/5"},
+		},
+	}, {
+		ObjectMeta: v1.ObjectMeta{Name: "mariadb-1", Labels: jujuLabels},
+	}, {
+		ObjectMeta: v1.ObjectMeta{Name: "other-0", Labels: map[string]string{"app.kubernetes.io/name": "other"}},
+	}} {
+		_, err := s.mockPods.Create(context.Background(), pod, v1.CreateOptions{})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.mockStatefulSets.Create(context.Background(), &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{Name: "mariadb", Labels: jujuLabels},
+	}, v1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.createEvent(c, "ev-1", "Pod", "mariadb-0", "Warning", "BackOff", now)
+	s.createEvent(c, "ev-2", "Pod", "mariadb-1", "Warning", "OOMKilling", now)
+	s.createEvent(c, "ev-3", "StatefulSet", "mariadb", "Normal", "SuccessfulCreate", now)
+	s.createEvent(c, "ev-4", "Pod", "other-0", "Warning", "FailedScheduling", now)
+	s.createEvent(c, "ev-5", "Pod", "gone-0", "Warning", "FailedScheduling", now)
+	s.createEvent(c, "ev-6", "Node", "node-1", "Warning", "NodeNotReady", now)
+
+	w, err := s.broker.WatchEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	events, err := s.broker.Events()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []caas.Event{{
+		UID:             "uid-ev-1",
+		ApplicationName: "mariadb",
+		UnitName:        "mariadb/5",
+		ObjectKind:      "Pod",
+		ObjectName:      "mariadb-0",
+		Type:            caas.EventTypeWarning,
+		Reason:          "BackOff",
+		Message:         "BackOff happened",
+		Count:           2,
+		Time:            now,
+	}, {
+		UID:             "uid-ev-2",
+		ApplicationName: "mariadb",
+		UnitName:        "mariadb/1",
+		ObjectKind:      "Pod",
+		ObjectName:      "mariadb-1",
+		Type:            caas.EventTypeWarning,
+		Reason:          "OOMKilling",
+		Message:         "OOMKilling happened",
+		Count:           2,
+		Time:            now,
+	}, {
+		UID:             "uid-ev-3",
+		ApplicationName: "mariadb",
+		ObjectKind:      "StatefulSet",
+		ObjectName:      "mariadb",
+		Type:            caas.EventTypeNormal,
+		Reason:          "SuccessfulCreate",
+		Message:         "SuccessfulCreate happened",
+		Count:           2,
+		Time:            now,
+	}})
+}
+
+func (s *eventsSuite) TestEventsNotWatched(c *gc.C) {
+	_, err := s.broker.Events()
+	c.Assert(err, gc.ErrorMatches, "events are not being watched")
+
+	w, err := s.broker.WatchEvents()
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+
+	// The cache is dropped once the watcher has stopped.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if _, err = s.broker.Events(); err != nil && err.Error() == "events are not being watched" {
+			break
+		}
+	}
+	c.Assert(err, gc.ErrorMatches, "events are not being watched")
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/juju/clock"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	apps "k8s.io/api/apps/v1"
@@ -14,6 +15,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/juju/juju/caas"
@@ -38,6 +40,7 @@ var (
 	ToYaml                    = toYaml
 	Indent                    = indent
 	ProcessSecretData         = processSecretData
+	VolumeSnapshotsGVR        = volumeSnapshotsGVR
 
 	CompileK8sCloudCheckers                    = compileK8sCloudCheckers
 	CompileLifecycleApplicationRemovalSelector = compileLifecycleApplicationRemovalSelector
@@ -179,8 +182,13 @@ func (k *kubernetesClient) DeleteNamespaceModelTeardown(ctx context.Context, wg
 	k.deleteNamespaceModelTeardown(ctx, wg, errChan)
 }
 
-func StorageProvider(k8sClient kubernetes.Interface, namespace string) storage.Provider {
-	return &storageProvider{&kubernetesClient{clientUnlocked: k8sClient, namespace: namespace}}
+func StorageProvider(k8sClient kubernetes.Interface, dynamicClient dynamic.Interface, namespace string) storage.Provider {
+	return &storageProvider{client: &kubernetesClient{
+		clientUnlocked:        k8sClient,
+		dynamicClientUnlocked: dynamicClient,
+		namespace:             namespace,
+		clock:                 clock.WallClock,
+	}}
 }
 
 func GetCloudProviderFromNodeMeta(node core.Node) (string, string) {
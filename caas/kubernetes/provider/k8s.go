@@ -118,6 +118,10 @@ type kubernetesClient struct {
 	apiextensionsClientUnlocked apiextensionsclientset.Interface
 	dynamicClientUnlocked       dynamic.Interface
 
+	// cloudEndpointUnlocked is the API endpoint from the cloud spec. It
+	// takes priority over any failover endpoints.
+	cloudEndpointUnlocked string
+
 	newClient     NewK8sClientFunc
 	newRestClient k8sspecs.NewK8sRestClientFunc
 
@@ -131,6 +135,10 @@ type kubernetesClient struct {
 	// informerFactoryUnlocked informer factory setup for tracking this model
 	informerFactoryUnlocked informers.SharedInformerFactory
 
+	// eventCacheUnlocked is the cache read by Events, kept up to date
+	// while the watcher returned by WatchEvents is running.
+	eventCacheUnlocked *eventCache
+
 	// isLegacyLabels describes if this client should use and implement legacy
 	// labels or new ones
 	isLegacyLabels bool
@@ -178,11 +186,13 @@ func newK8sBroker(
 	randomPrefix utils.RandomPrefixFunc,
 	clock jujuclock.Clock,
 ) (*kubernetesClient, error) {
-	k8sClient, apiextensionsClient, dynamicClient, err := newClient(k8sRestConfig)
+	newCfg, err := providerInstance.newConfig(cfg)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	newCfg, err := providerInstance.newConfig(cfg)
+	cloudEndpoint := k8sRestConfig.Host
+	k8sRestConfig = selectClusterEndpoint(newCfg, k8sRestConfig, namespace, newClient)
+	k8sClient, apiextensionsClient, dynamicClient, err := newClient(k8sRestConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -208,6 +218,7 @@ func newK8sBroker(
 		dynamicClientUnlocked:       dynamicClient,
 		envCfgUnlocked:              newCfg.Config,
 		k8sCfgUnlocked:              k8sRestConfig,
+		cloudEndpointUnlocked:       cloudEndpoint,
 		informerFactoryUnlocked: informers.NewSharedInformerFactoryWithOptions(
 			k8sClient,
 			InformerResyncPeriod,
@@ -363,7 +374,15 @@ func (k *kubernetesClient) SetConfig(cfg *config.Config) error {
 		return errors.Trace(err)
 	}
 	k.envCfgUnlocked = newCfg.Config
-	return nil
+
+	// Without failover endpoints the broker must use the cloud endpoint.
+	if len(newCfg.failoverEndpoints()) > 0 || k.k8sCfgUnlocked.Host == k.cloudEndpointUnlocked {
+		return nil
+	}
+	logger.Infof("failover endpoints removed, switching back to cluster API endpoint %q", k.cloudEndpointUnlocked)
+	k8sRestConfig := rest.CopyConfig(k.k8sCfgUnlocked)
+	k8sRestConfig.Host = k.cloudEndpointUnlocked
+	return errors.Annotate(k.setRestConfigUnlocked(k8sRestConfig), "switching to cloud endpoint")
 }
 
 // SetCloudSpec is specified in the environs.Environ interface.
@@ -379,10 +398,24 @@ func (k *kubernetesClient) SetCloudSpec(_ context.Context, spec environscloudspe
 		return errors.Annotate(err, "cannot set cloud spec")
 	}
 
-	k.clientUnlocked, k.apiextensionsClientUnlocked, k.dynamicClientUnlocked, err = k.newClient(k8sRestConfig)
-	if err != nil {
+	if err := k.setRestConfigUnlocked(k8sRestConfig); err != nil {
 		return errors.Annotate(err, "cannot set cloud spec")
 	}
+	k.cloudEndpointUnlocked = k8sRestConfig.Host
+	return nil
+}
+
+// setRestConfigUnlocked replaces the clients and informer factory used by
+// the broker with ones for the specified rest config. The caller must hold
+// the lock.
+func (k *kubernetesClient) setRestConfigUnlocked(k8sRestConfig *rest.Config) error {
+	client, apiextensionsClient, dynamicClient, err := k.newClient(k8sRestConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	k.clientUnlocked = client
+	k.apiextensionsClientUnlocked = apiextensionsClient
+	k.dynamicClientUnlocked = dynamicClient
 	k.k8sCfgUnlocked = rest.CopyConfig(k8sRestConfig)
 
 	k.informerFactoryUnlocked = informers.NewSharedInformerFactoryWithOptions(
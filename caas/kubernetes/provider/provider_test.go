@@ -27,10 +27,12 @@ func fakeConfig(c *gc.C, attrs ...coretesting.Attrs) *config.Config {
 
 func fakeConfigAttrs(attrs ...coretesting.Attrs) coretesting.Attrs {
 	merged := coretesting.FakeConfig().Merge(coretesting.Attrs{
-		"type":             "kubernetes",
-		"uuid":             utils.MustNewUUID().String(),
-		"operator-storage": "",
-		"workload-storage": "",
+		"type":               "kubernetes",
+		"uuid":               utils.MustNewUUID().String(),
+		"operator-storage":   "",
+		"workload-storage":   "",
+		"network-policies":   false,
+		"failover-endpoints": "",
 	})
 	for _, attrs := range attrs {
 		merged = merged.Merge(attrs)
@@ -130,3 +132,13 @@ func (s *providerSuite) TestValidate(c *gc.C) {
 	validAttrs := validCfg.AllAttrs()
 	c.Assert(config.AllAttrs(), gc.DeepEquals, validAttrs)
 }
+
+func (s *providerSuite) TestValidateFailoverEndpoints(c *gc.C) {
+	config := fakeConfig(c, coretesting.Attrs{"failover-endpoints": "https://10.0.0.2:6443, https://10.0.0.3:6443"})
+	_, err := s.provider.Validate(config, nil)
+	c.Check(err, jc.ErrorIsNil)
+
+	config = fakeConfig(c, coretesting.Attrs{"failover-endpoints": "https://10.0.0.2:6443,not-a-url"})
+	_, err = s.provider.Validate(config, nil)
+	c.Check(err, gc.ErrorMatches, `invalid k8s provider config: failover endpoint "not-a-url" not valid`)
+}
@@ -5,8 +5,11 @@ package provider
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/juju/charm/v12"
+	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version/v2"
 	"gopkg.in/juju/environschema.v1"
@@ -43,6 +46,17 @@ var configSchema = environschema.Fields{
 		Group:       environschema.AccountGroup,
 		Immutable:   true,
 	},
+	k8sconstants.NetworkPoliciesKey: {
+		Description: "Whether to generate network policies which only allow traffic between related applications.",
+		Type:        environschema.Tbool,
+	},
+	k8sconstants.FailoverEndpointsKey: {
+		Description: "A comma separated list of API endpoints, in priority order, to fail over to when " +
+			"the cloud endpoint is unhealthy. The failover endpoints are reached with the cloud's credential and " +
+			"CA certificates, so they must serve the same cluster and accept them; separate credentials or CA " +
+			"certificates per endpoint are not supported.",
+		Type: environschema.Tstring,
+	},
 }
 
 var providerConfigFields = func() schema.Fields {
@@ -54,8 +68,10 @@ var providerConfigFields = func() schema.Fields {
 }()
 
 var providerConfigDefaults = schema.Defaults{
-	k8sconstants.WorkloadStorageKey: "",
-	k8sconstants.OperatorStorageKey: "",
+	k8sconstants.WorkloadStorageKey:   "",
+	k8sconstants.OperatorStorageKey:   "",
+	k8sconstants.NetworkPoliciesKey:   false,
+	k8sconstants.FailoverEndpointsKey: "",
 }
 
 type brokerConfig struct {
@@ -111,5 +127,23 @@ func validateConfig(cfg, old *config.Config) (*brokerConfig, error) {
 	}
 
 	bcfg := &brokerConfig{cfg, validated}
+	for _, endpoint := range bcfg.failoverEndpoints() {
+		if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+			return nil, errors.NotValidf("failover endpoint %q", endpoint)
+		}
+	}
 	return bcfg, nil
 }
+
+// failoverEndpoints returns the API endpoints to fail over to, in
+// priority order.
+func (c *brokerConfig) failoverEndpoints() []string {
+	value, _ := c.attrs[k8sconstants.FailoverEndpointsKey].(string)
+	var endpoints []string
+	for _, endpoint := range strings.Split(value, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/juju/juju/caas/kubernetes/provider/resources"
+)
+
+type horizontalPodAutoscalerSuite struct {
+	resourceSuite
+}
+
+var _ = gc.Suite(&horizontalPodAutoscalerSuite{})
+
+func (s *horizontalPodAutoscalerSuite) TestApply(c *gc.C) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hpa1",
+			Namespace: "test",
+		},
+	}
+	// Create.
+	hpaResource := resources.NewHorizontalPodAutoscaler("hpa1", "test", hpa)
+	c.Assert(hpaResource.Apply(context.TODO(), s.client), jc.ErrorIsNil)
+	result, err := s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Get(context.TODO(), "hpa1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(result.GetAnnotations()), gc.Equals, 0)
+
+	// Update.
+	hpa.SetAnnotations(map[string]string{"a": "b"})
+	hpaResource = resources.NewHorizontalPodAutoscaler("hpa1", "test", hpa)
+	c.Assert(hpaResource.Apply(context.TODO(), s.client), jc.ErrorIsNil)
+
+	result, err = s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Get(context.TODO(), "hpa1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.GetName(), gc.Equals, `hpa1`)
+	c.Assert(result.GetNamespace(), gc.Equals, `test`)
+	c.Assert(result.GetAnnotations(), gc.DeepEquals, map[string]string{"a": "b"})
+}
+
+func (s *horizontalPodAutoscalerSuite) TestGet(c *gc.C) {
+	template := autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hpa1",
+			Namespace: "test",
+		},
+	}
+	hpa1 := template
+	hpa1.SetAnnotations(map[string]string{"a": "b"})
+	_, err := s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Create(context.TODO(), &hpa1, metav1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	hpaResource := resources.NewHorizontalPodAutoscaler("hpa1", "test", &template)
+	c.Assert(len(hpaResource.GetAnnotations()), gc.Equals, 0)
+	err = hpaResource.Get(context.TODO(), s.client)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hpaResource.GetName(), gc.Equals, `hpa1`)
+	c.Assert(hpaResource.GetNamespace(), gc.Equals, `test`)
+	c.Assert(hpaResource.GetAnnotations(), gc.DeepEquals, map[string]string{"a": "b"})
+}
+
+func (s *horizontalPodAutoscalerSuite) TestDelete(c *gc.C) {
+	hpa := autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hpa1",
+			Namespace: "test",
+		},
+	}
+	_, err := s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Create(context.TODO(), &hpa, metav1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Get(context.TODO(), "hpa1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.GetName(), gc.Equals, `hpa1`)
+
+	hpaResource := resources.NewHorizontalPodAutoscaler("hpa1", "test", &hpa)
+	err = hpaResource.Delete(context.TODO(), s.client)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = hpaResource.Get(context.TODO(), s.client)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.client.AutoscalingV2().HorizontalPodAutoscalers("test").Get(context.TODO(), "hpa1", metav1.GetOptions{})
+	c.Assert(err, jc.Satisfies, k8serrors.IsNotFound)
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/juju/juju/caas/kubernetes/provider/resources"
+)
+
+type podDisruptionBudgetSuite struct {
+	resourceSuite
+}
+
+var _ = gc.Suite(&podDisruptionBudgetSuite{})
+
+func (s *podDisruptionBudgetSuite) TestApply(c *gc.C) {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pdb1",
+			Namespace: "test",
+		},
+	}
+	// Create.
+	pdbResource := resources.NewPodDisruptionBudget("pdb1", "test", pdb)
+	c.Assert(pdbResource.Apply(context.TODO(), s.client), jc.ErrorIsNil)
+	result, err := s.client.PolicyV1().PodDisruptionBudgets("test").Get(context.TODO(), "pdb1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(result.GetAnnotations()), gc.Equals, 0)
+
+	// Update.
+	pdb.SetAnnotations(map[string]string{"a": "b"})
+	pdbResource = resources.NewPodDisruptionBudget("pdb1", "test", pdb)
+	c.Assert(pdbResource.Apply(context.TODO(), s.client), jc.ErrorIsNil)
+
+	result, err = s.client.PolicyV1().PodDisruptionBudgets("test").Get(context.TODO(), "pdb1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.GetName(), gc.Equals, `pdb1`)
+	c.Assert(result.GetNamespace(), gc.Equals, `test`)
+	c.Assert(result.GetAnnotations(), gc.DeepEquals, map[string]string{"a": "b"})
+}
+
+func (s *podDisruptionBudgetSuite) TestGet(c *gc.C) {
+	template := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pdb1",
+			Namespace: "test",
+		},
+	}
+	pdb1 := template
+	pdb1.SetAnnotations(map[string]string{"a": "b"})
+	_, err := s.client.PolicyV1().PodDisruptionBudgets("test").Create(context.TODO(), &pdb1, metav1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pdbResource := resources.NewPodDisruptionBudget("pdb1", "test", &template)
+	c.Assert(len(pdbResource.GetAnnotations()), gc.Equals, 0)
+	err = pdbResource.Get(context.TODO(), s.client)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pdbResource.GetName(), gc.Equals, `pdb1`)
+	c.Assert(pdbResource.GetNamespace(), gc.Equals, `test`)
+	c.Assert(pdbResource.GetAnnotations(), gc.DeepEquals, map[string]string{"a": "b"})
+}
+
+func (s *podDisruptionBudgetSuite) TestDelete(c *gc.C) {
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pdb1",
+			Namespace: "test",
+		},
+	}
+	_, err := s.client.PolicyV1().PodDisruptionBudgets("test").Create(context.TODO(), &pdb, metav1.CreateOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.client.PolicyV1().PodDisruptionBudgets("test").Get(context.TODO(), "pdb1", metav1.GetOptions{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.GetName(), gc.Equals, `pdb1`)
+
+	pdbResource := resources.NewPodDisruptionBudget("pdb1", "test", &pdb)
+	err = pdbResource.Delete(context.TODO(), s.client)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = pdbResource.Get(context.TODO(), s.client)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.client.PolicyV1().PodDisruptionBudgets("test").Get(context.TODO(), "pdb1", metav1.GetOptions{})
+	c.Assert(err, jc.Satisfies, k8serrors.IsNotFound)
+}
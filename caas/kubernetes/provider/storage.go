@@ -5,13 +5,16 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 
 	"github.com/juju/errors"
 	core "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/juju/juju/caas/kubernetes/provider/constants"
 	"github.com/juju/juju/caas/kubernetes/provider/storage"
@@ -89,8 +92,13 @@ func (g *storageProvider) DefaultPools() []*jujustorage.Config {
 
 // VolumeSource is defined on the jujustorage.Provider interface.
 func (g *storageProvider) VolumeSource(cfg *jujustorage.Config) (jujustorage.VolumeSource, error) {
+	var snapshotClass string
+	if cfg != nil {
+		snapshotClass, _ = cfg.Attrs()[constants.StorageSnapshotClass].(string)
+	}
 	return &volumeSource{
-		client: g.client,
+		client:        g.client,
+		snapshotClass: snapshotClass,
 	}, nil
 }
 
@@ -100,10 +108,15 @@ func (g *storageProvider) FilesystemSource(providerConfig *jujustorage.Config) (
 }
 
 type volumeSource struct {
-	client *kubernetesClient
+	client        *kubernetesClient
+	snapshotClass string
 }
 
-var _ jujustorage.VolumeSource = (*volumeSource)(nil)
+var (
+	_ jujustorage.VolumeSource      = (*volumeSource)(nil)
+	_ jujustorage.VolumeResizer     = (*volumeSource)(nil)
+	_ jujustorage.VolumeSnapshotter = (*volumeSource)(nil)
+)
 
 // CreateVolumes is specified on the jujustorage.VolumeSource interface.
 func (v *volumeSource) CreateVolumes(ctx jujucontext.ProviderCallContext, params []jujustorage.VolumeParams) (_ []jujustorage.CreateVolumesResult, err error) {
@@ -207,6 +220,86 @@ func (v *volumeSource) DetachVolumes(ctx jujucontext.ProviderCallContext, attach
 	return make([]error, len(attachParams)), nil
 }
 
+// ResizeVolume is specified on the jujustorage.VolumeResizer interface.
+// The volume claim is grown to the requested size, which is only
+// allowed if its storage class permits volume expansion. The stateful
+// set's claim template is left to the application provisioner, which
+// picks up the size recorded for the storage.
+func (v *volumeSource) ResizeVolume(ctx jujucontext.ProviderCallContext, volumeId string, size uint64) error {
+	claim, err := v.volumeClaim(volumeId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	requested := resource.NewQuantity(int64(size)*1024*1024, resource.BinarySI)
+	current := claim.Spec.Resources.Requests[core.ResourceStorage]
+	switch requested.Cmp(current) {
+	case -1:
+		return errors.NotValidf("shrinking volume %q from %s to %s", volumeId, current.String(), requested.String())
+	case 0:
+		return nil
+	}
+
+	storageClassName := ""
+	if claim.Spec.StorageClassName != nil {
+		storageClassName = *claim.Spec.StorageClassName
+	}
+	if storageClassName == "" {
+		return errors.NotSupportedf("resizing volume %q without a storage class", volumeId)
+	}
+	storageClass, err := v.client.client().StorageV1().StorageClasses().Get(context.TODO(), storageClassName, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("storage class %q", storageClassName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return errors.NotSupportedf("resizing volume %q with storage class %q", volumeId, storageClassName)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					string(core.ResourceStorage): requested.String(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("resizing volume claim %s for volume %q to %s", claim.Name, volumeId, requested.String())
+	_, err = v.client.client().CoreV1().PersistentVolumeClaims(claim.Namespace).Patch(
+		context.TODO(), claim.Name, types.MergePatchType, patch, v1.PatchOptions{},
+	)
+	if err != nil {
+		return errors.Annotatef(err, "resizing volume claim %v", claim.Name)
+	}
+	return nil
+}
+
+// volumeClaim returns the persistent volume claim bound to the
+// persistent volume with the specified name.
+func (v *volumeSource) volumeClaim(volumeId string) (*core.PersistentVolumeClaim, error) {
+	vol, err := v.client.client().CoreV1().PersistentVolumes().Get(context.TODO(), volumeId, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, errors.NotFoundf("volume %q", volumeId)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting volume %v", volumeId)
+	}
+	claimRef := vol.Spec.ClaimRef
+	if claimRef == nil {
+		return nil, errors.NotFoundf("claim for volume %q", volumeId)
+	}
+	claim, err := v.client.client().CoreV1().PersistentVolumeClaims(claimRef.Namespace).Get(context.TODO(), claimRef.Name, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, errors.NotFoundf("volume claim %q", claimRef.Name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "getting volume claim %v", claimRef.Name)
+	}
+	return claim, nil
+}
+
 func foreachVolume(volumeIds []string, f func(string) error) []error {
 	results := make([]error, len(volumeIds))
 	var wg sync.WaitGroup
@@ -47,15 +47,17 @@ func ParseVolumeParams(name string, size resource.Quantity, storageAttr map[stri
 }
 
 var storageConfigFields = schema.Fields{
-	k8sconstants.StorageClass:       schema.String(),
-	k8sconstants.StorageProvisioner: schema.String(),
+	k8sconstants.StorageClass:         schema.String(),
+	k8sconstants.StorageProvisioner:   schema.String(),
+	k8sconstants.StorageSnapshotClass: schema.String(),
 }
 
 var storageConfigChecker = schema.FieldMap(
 	storageConfigFields,
 	schema.Defaults{
-		k8sconstants.StorageClass:       schema.Omit,
-		k8sconstants.StorageProvisioner: schema.Omit,
+		k8sconstants.StorageClass:         schema.Omit,
+		k8sconstants.StorageProvisioner:   schema.Omit,
+		k8sconstants.StorageSnapshotClass: schema.Omit,
 	},
 )
 
@@ -4,12 +4,18 @@
 package provider_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
+	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/caas/kubernetes/provider/constants"
@@ -24,7 +30,7 @@ type storageSuite struct {
 }
 
 func (s *storageSuite) k8sProvider(c *gc.C, ctrl *gomock.Controller) storage.Provider {
-	return provider.StorageProvider(s.k8sClient, s.getNamespace())
+	return provider.StorageProvider(s.k8sClient, s.mockDynamicClient, s.getNamespace())
 }
 
 func (s *storageSuite) TestValidateConfig(c *gc.C) {
@@ -198,3 +204,413 @@ func (s *storageSuite) TestValidateStorageProvider(c *gc.C) {
 		}
 	}
 }
+
+func (s *storageSuite) volumeClaimExpectations(claim *core.PersistentVolumeClaim) []any {
+	return []any{
+		s.mockPersistentVolumes.EXPECT().Get(gomock.Any(), "vol-1", v1.GetOptions{}).
+			Return(&core.PersistentVolume{
+				Spec: core.PersistentVolumeSpec{
+					ClaimRef: &core.ObjectReference{Namespace: "test", Name: "vol-1-pvc"},
+				}}, nil),
+		s.mockPersistentVolumeClaims.EXPECT().Get(gomock.Any(), "vol-1-pvc", v1.GetOptions{}).
+			Return(claim, nil),
+	}
+}
+
+func (s *storageSuite) volumeClaim(size string) *core.PersistentVolumeClaim {
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "vol-1-pvc", Namespace: "test"},
+		Spec: core.PersistentVolumeClaimSpec{
+			StorageClassName: pointer.StringPtr("test-workload-storage"),
+			AccessModes:      []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+			Resources: core.VolumeResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+func (s *storageSuite) volumeSnapshot(ready bool) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.storage.k8s.io/v1",
+		"kind":       "VolumeSnapshot",
+		"metadata": map[string]interface{}{
+			"name":      "snap-1",
+			"namespace": "test",
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": "vol-1-pvc",
+			},
+		},
+		"status": map[string]interface{}{
+			"readyToUse":  ready,
+			"restoreSize": "1Gi",
+		},
+	}}
+}
+
+func (s *storageSuite) TestResizeVolume(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockStorageClass.EXPECT().Get(gomock.Any(), "test-workload-storage", v1.GetOptions{}).
+			Return(&storagev1.StorageClass{AllowVolumeExpansion: pointer.BoolPtr(true)}, nil),
+		s.mockPersistentVolumeClaims.EXPECT().Patch(
+			gomock.Any(), "vol-1-pvc", types.MergePatchType,
+			[]byte(`{"spec":{"resources":{"requests":{"storage":"2Gi"}}}}`), v1.PatchOptions{},
+		).Return(nil, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeResizer).ResizeVolume(&context.CloudCallContext{}, "vol-1", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestResizeVolumeNotAllowedByStorageClass(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockStorageClass.EXPECT().Get(gomock.Any(), "test-workload-storage", v1.GetOptions{}).
+			Return(&storagev1.StorageClass{}, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeResizer).ResizeVolume(&context.CloudCallContext{}, "vol-1", 2048)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `resizing volume "vol-1" with storage class "test-workload-storage" not supported`)
+}
+
+func (s *storageSuite) TestResizeVolumeShrink(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(s.volumeClaimExpectations(s.volumeClaim("2Gi"))...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeResizer).ResizeVolume(&context.CloudCallContext{}, "vol-1", 1024)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `shrinking volume "vol-1" from 2Gi to 1Gi not valid`)
+}
+
+func (s *storageSuite) TestCreateVolumeSnapshot(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.storage.k8s.io/v1",
+		"kind":       "VolumeSnapshot",
+		"metadata": map[string]interface{}{
+			"name":      "snap-1",
+			"namespace": "test",
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": "vol-1-pvc",
+			},
+			"volumeSnapshotClassName": "csi-snapclass",
+		},
+	}}
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Create(gomock.Any(), snapshot, v1.CreateOptions{}).Return(snapshot, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	cfg, err := storage.NewConfig("snapshots", constants.StorageProviderType, map[string]interface{}{
+		"snapshot-class": "csi-snapclass",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	vs, err := p.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := vs.(storage.VolumeSnapshotter).CreateVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, storage.VolumeSnapshot{
+		Name:     "snap-1",
+		VolumeId: "vol-1",
+	})
+}
+
+func (s *storageSuite) TestCreateVolumeSnapshotNotSupported(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Create(gomock.Any(), gomock.Any(), v1.CreateOptions{}).Return(nil, s.k8sNotFoundError()),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = vs.(storage.VolumeSnapshotter).CreateVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageSuite) TestListVolumeSnapshots(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	other := s.volumeSnapshot(true)
+	other.SetName("snap-2")
+	_ = unstructured.SetNestedField(other.Object, "vol-2-pvc", "spec", "source", "persistentVolumeClaimName")
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().List(gomock.Any(), v1.ListOptions{}).Return(&unstructured.UnstructuredList{
+			Items: []unstructured.Unstructured{*s.volumeSnapshot(true), *other},
+		}, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := vs.(storage.VolumeSnapshotter).ListVolumeSnapshots(&context.CloudCallContext{}, "vol-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []storage.VolumeSnapshot{{
+		Name:     "snap-1",
+		VolumeId: "vol-1",
+		Size:     1024,
+		Ready:    true,
+	}})
+}
+
+func (s *storageSuite) TestExportVolumeSnapshot(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	claim := s.volumeClaim("1Gi")
+	calls := s.volumeClaimExpectations(claim)
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Get(gomock.Any(), "snap-1", v1.GetOptions{}).Return(s.volumeSnapshot(true), nil),
+		s.mockPersistentVolumeClaims.EXPECT().Create(gomock.Any(), &core.PersistentVolumeClaim{
+			ObjectMeta: v1.ObjectMeta{Name: "vol-1-pvc-snap-1", Namespace: "test"},
+			Spec: core.PersistentVolumeClaimSpec{
+				StorageClassName: claim.Spec.StorageClassName,
+				AccessModes:      claim.Spec.AccessModes,
+				Resources:        claim.Spec.Resources,
+				DataSource: &core.TypedLocalObjectReference{
+					APIGroup: pointer.StringPtr("snapshot.storage.k8s.io"),
+					Kind:     "VolumeSnapshot",
+					Name:     "snap-1",
+				},
+			},
+		}, v1.CreateOptions{}).Return(nil, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	name, err := vs.(storage.VolumeSnapshotter).ExportVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(name, gc.Equals, "vol-1-pvc-snap-1")
+}
+
+func (s *storageSuite) TestExportVolumeSnapshotNotReady(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Get(gomock.Any(), "snap-1", v1.GetOptions{}).Return(s.volumeSnapshot(false), nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = vs.(storage.VolumeSnapshotter).ExportVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, gc.ErrorMatches, `exporting volume snapshot "snap-1" before it is ready not valid`)
+}
+
+func (s *storageSuite) restoreFixtures() (*core.PersistentVolumeClaim, *core.Pod, *apps.StatefulSet) {
+	claim := s.volumeClaim("1Gi")
+	claim.UID = "claim-uid"
+	claim.Labels = map[string]string{"storage.juju.is/name": "database"}
+	pod := &core.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "app-0",
+			Namespace: "test",
+			UID:       "pod-uid",
+			OwnerReferences: []v1.OwnerReference{{
+				Kind:       "StatefulSet",
+				Name:       "app",
+				UID:        "app-uid",
+				Controller: pointer.BoolPtr(true),
+			}},
+		},
+		Spec: core.PodSpec{
+			Volumes: []core.Volume{{
+				Name: "database",
+				VolumeSource: core.VolumeSource{
+					PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "vol-1-pvc"},
+				},
+			}},
+		},
+	}
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "app",
+			Namespace:       "test",
+			UID:             "app-uid",
+			ResourceVersion: "42",
+			Annotations:     map[string]string{"app.juju.is/uuid": "appuuid"},
+		},
+		Spec:   apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(2)},
+		Status: apps.StatefulSetStatus{Replicas: 2},
+	}
+	return claim, pod, statefulSet
+}
+
+func (s *storageSuite) restoreExpectations(
+	claim *core.PersistentVolumeClaim, pod *core.Pod, statefulSet *apps.StatefulSet,
+) []any {
+	orphan := v1.DeletePropagationOrphan
+	otherPod := core.Pod{ObjectMeta: v1.ObjectMeta{Name: "other-0"}}
+	calls := s.volumeClaimExpectations(claim)
+	return append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Get(gomock.Any(), "snap-1", v1.GetOptions{}).Return(s.volumeSnapshot(true), nil),
+		s.mockPods.EXPECT().List(gomock.Any(), v1.ListOptions{}).
+			Return(&core.PodList{Items: []core.Pod{otherPod, *pod}}, nil),
+		s.mockStatefulSets.EXPECT().Get(gomock.Any(), "app", v1.GetOptions{}).Return(statefulSet, nil),
+		s.mockStatefulSets.EXPECT().Delete(gomock.Any(), "app", v1.DeleteOptions{
+			PropagationPolicy: &orphan,
+			Preconditions:     &v1.Preconditions{UID: &statefulSet.UID},
+		}).Return(nil),
+		s.mockStatefulSets.EXPECT().Get(gomock.Any(), "app", v1.GetOptions{}).Return(nil, s.k8sNotFoundError()),
+		s.mockPods.EXPECT().Delete(gomock.Any(), "app-0", v1.DeleteOptions{
+			Preconditions: &v1.Preconditions{UID: &pod.UID},
+		}).Return(nil),
+		s.mockPersistentVolumeClaims.EXPECT().Delete(gomock.Any(), "vol-1-pvc", v1.DeleteOptions{
+			Preconditions: &v1.Preconditions{UID: &claim.UID},
+		}).Return(nil),
+		s.mockPersistentVolumeClaims.EXPECT().Get(gomock.Any(), "vol-1-pvc", v1.GetOptions{}).
+			Return(nil, s.k8sNotFoundError()),
+	)
+}
+
+func (s *storageSuite) restoredClaim(claim *core.PersistentVolumeClaim) *core.PersistentVolumeClaim {
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "vol-1-pvc",
+			Namespace: "test",
+			Labels:    claim.Labels,
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			StorageClassName: claim.Spec.StorageClassName,
+			AccessModes:      claim.Spec.AccessModes,
+			Resources:        claim.Spec.Resources,
+			DataSource: &core.TypedLocalObjectReference{
+				APIGroup: pointer.StringPtr("snapshot.storage.k8s.io"),
+				Kind:     "VolumeSnapshot",
+				Name:     "snap-1",
+			},
+		},
+	}
+}
+
+func (s *storageSuite) recreatedStatefulSet() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "test",
+			Annotations: map[string]string{"app.juju.is/uuid": "appuuid"},
+		},
+		Spec: apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(2)},
+	}
+}
+
+func (s *storageSuite) TestRestoreVolumeSnapshot(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	claim, pod, statefulSet := s.restoreFixtures()
+	calls := s.restoreExpectations(claim, pod, statefulSet)
+	calls = append(calls,
+		s.mockPersistentVolumeClaims.EXPECT().Create(gomock.Any(), s.restoredClaim(claim), v1.CreateOptions{}).
+			Return(nil, nil),
+		s.mockStatefulSets.EXPECT().Create(gomock.Any(), s.recreatedStatefulSet(), v1.CreateOptions{}).
+			Return(nil, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeSnapshotter).RestoreVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestRestoreVolumeSnapshotRecreatesStatefulSetOnError(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	claim, pod, statefulSet := s.restoreFixtures()
+	calls := s.restoreExpectations(claim, pod, statefulSet)
+	calls = append(calls,
+		s.mockPersistentVolumeClaims.EXPECT().Create(gomock.Any(), s.restoredClaim(claim), v1.CreateOptions{}).
+			Return(nil, errors.New("boom")),
+		s.mockStatefulSets.EXPECT().Create(gomock.Any(), s.recreatedStatefulSet(), v1.CreateOptions{}).
+			Return(nil, nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeSnapshotter).RestoreVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, gc.ErrorMatches, `creating volume claim vol-1-pvc: boom`)
+}
+
+func (s *storageSuite) TestRestoreVolumeSnapshotNotReady(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	calls := s.volumeClaimExpectations(s.volumeClaim("1Gi"))
+	calls = append(calls,
+		s.mockDynamicClient.EXPECT().Resource(provider.VolumeSnapshotsGVR).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Get(gomock.Any(), "snap-1", v1.GetOptions{}).Return(s.volumeSnapshot(false), nil),
+	)
+	gomock.InOrder(calls...)
+
+	p := s.k8sProvider(c, ctrl)
+	vs, err := p.VolumeSource(&storage.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = vs.(storage.VolumeSnapshotter).RestoreVolumeSnapshot(&context.CloudCallContext{}, "vol-1", "snap-1")
+	c.Assert(err, gc.ErrorMatches, `restoring volume snapshot "snap-1" before it is ready not valid`)
+}
@@ -152,3 +152,14 @@ func StorageNameFromLabels(labels labels.Set) string {
 	}
 	return labels[constants.LegacyLabelStorageName]
 }
+
+// AppNameFromLabels returns the juju application name used in the provided
+// label set. First checks for the key LabelKubernetesAppName and then
+// defaults over to the key LegacyLabelKubernetesAppName. If neither key
+// exists an empty string is returned.
+func AppNameFromLabels(labels labels.Set) string {
+	if labels[constants.LabelKubernetesAppName] != "" {
+		return labels[constants.LabelKubernetesAppName]
+	}
+	return labels[constants.LegacyLabelKubernetesAppName]
+}
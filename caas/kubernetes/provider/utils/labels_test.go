@@ -333,3 +333,27 @@ func (l *LabelSuite) TestStorageNameFromLabels(c *gc.C) {
 		c.Assert(utils.StorageNameFromLabels(test.Labels), gc.Equals, test.Expected)
 	}
 }
+
+func (l *LabelSuite) TestAppNameFromLabels(c *gc.C) {
+	tests := []struct {
+		Labels   labels.Set
+		Expected string
+	}{
+		{
+			Labels:   labels.Set{constants.LabelKubernetesAppName: "test1"},
+			Expected: "test1",
+		},
+		{
+			Labels:   labels.Set{constants.LegacyLabelKubernetesAppName: "test2"},
+			Expected: "test2",
+		},
+		{
+			Labels:   labels.Set{"foo": "bar"},
+			Expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		c.Assert(utils.AppNameFromLabels(test.Labels), gc.Equals, test.Expected)
+	}
+}
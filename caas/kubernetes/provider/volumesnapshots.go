@@ -0,0 +1,373 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	jujucontext "github.com/juju/juju/environs/context"
+	jujustorage "github.com/juju/juju/storage"
+)
+
+const (
+	volumeSnapshotGroup = "snapshot.storage.k8s.io"
+	volumeSnapshotKind  = "VolumeSnapshot"
+)
+
+// volumeSnapshotsGVR identifies the CSI VolumeSnapshot resource.
+var volumeSnapshotsGVR = schema.GroupVersionResource{
+	Group:    volumeSnapshotGroup,
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+func (v *volumeSource) volumeSnapshots(namespace string) dynamic.ResourceInterface {
+	return v.client.dynamicClient().Resource(volumeSnapshotsGVR).Namespace(namespace)
+}
+
+// snapshotsNotSupported converts the error returned when the
+// VolumeSnapshot resource isn't served by the cluster.
+func snapshotsNotSupported(err error) error {
+	return errors.NewNotSupported(err, "volume snapshots are not available on this cluster")
+}
+
+// CreateVolumeSnapshot is specified on the jujustorage.VolumeSnapshotter interface.
+func (v *volumeSource) CreateVolumeSnapshot(
+	ctx jujucontext.ProviderCallContext, volumeId, name string,
+) (jujustorage.VolumeSnapshot, error) {
+	claim, err := v.volumeClaim(volumeId)
+	if err != nil {
+		return jujustorage.VolumeSnapshot{}, errors.Trace(err)
+	}
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": claim.Name,
+		},
+	}
+	if v.snapshotClass != "" {
+		spec["volumeSnapshotClassName"] = v.snapshotClass
+	}
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotsGVR.GroupVersion().String(),
+		"kind":       volumeSnapshotKind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": claim.Namespace,
+		},
+		"spec": spec,
+	}}
+	logger.Infof("creating volume snapshot %s of volume claim %s", name, claim.Name)
+	out, err := v.volumeSnapshots(claim.Namespace).Create(context.TODO(), snapshot, v1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		return jujustorage.VolumeSnapshot{}, errors.AlreadyExistsf("volume snapshot %q", name)
+	} else if k8serrors.IsNotFound(err) {
+		return jujustorage.VolumeSnapshot{}, snapshotsNotSupported(err)
+	} else if err != nil {
+		return jujustorage.VolumeSnapshot{}, errors.Annotatef(err, "creating volume snapshot %v", name)
+	}
+	return volumeSnapshotInfo(volumeId, out), nil
+}
+
+// ListVolumeSnapshots is specified on the jujustorage.VolumeSnapshotter interface.
+func (v *volumeSource) ListVolumeSnapshots(
+	ctx jujucontext.ProviderCallContext, volumeId string,
+) ([]jujustorage.VolumeSnapshot, error) {
+	claim, err := v.volumeClaim(volumeId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	snapshots, err := v.volumeSnapshots(claim.Namespace).List(context.TODO(), v1.ListOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, snapshotsNotSupported(err)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "listing volume snapshots")
+	}
+	var result []jujustorage.VolumeSnapshot
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if snapshotClaimName(snapshot) != claim.Name {
+			continue
+		}
+		result = append(result, volumeSnapshotInfo(volumeId, snapshot))
+	}
+	return result, nil
+}
+
+// ExportVolumeSnapshot is specified on the jujustorage.VolumeSnapshotter interface.
+// A new volume claim, named after the original claim and the snapshot, is
+// provisioned from the snapshot; the name of the new claim is returned.
+// Juju doesn't manage the new claim.
+func (v *volumeSource) ExportVolumeSnapshot(
+	ctx jujucontext.ProviderCallContext, volumeId, name string,
+) (string, error) {
+	claim, err := v.volumeClaim(volumeId)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := v.checkVolumeSnapshotReady(claim, volumeId, name, "exporting"); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	exported := claimFromSnapshot(claim, name)
+	exported.Name = fmt.Sprintf("%s-%s", claim.Name, name)
+	logger.Infof("exporting volume snapshot %s to volume claim %s", name, exported.Name)
+	_, err = v.client.client().CoreV1().PersistentVolumeClaims(claim.Namespace).Create(context.TODO(), exported, v1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		return "", errors.AlreadyExistsf("volume claim %q", exported.Name)
+	} else if err != nil {
+		return "", errors.Annotatef(err, "creating volume claim %v", exported.Name)
+	}
+	return exported.Name, nil
+}
+
+// RestoreVolumeSnapshot is specified on the jujustorage.VolumeSnapshotter interface.
+// The volume claim is replaced by a claim of the same name provisioned
+// from the snapshot, and the pods using it are restarted. The stateful
+// set owning the pods is deleted, leaving its pods in place, while the
+// claim is replaced so that it can't provision an empty claim in its
+// place; it is then created again and adopts the pods.
+func (v *volumeSource) RestoreVolumeSnapshot(
+	ctx jujucontext.ProviderCallContext, volumeId, name string,
+) (err error) {
+	claim, err := v.volumeClaim(volumeId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := v.checkVolumeSnapshotReady(claim, volumeId, name, "restoring"); err != nil {
+		return errors.Trace(err)
+	}
+	pods, err := v.claimPods(claim)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statefulSet, err := v.podsStatefulSet(claim.Namespace, pods)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if statefulSet != nil {
+		if err := v.orphanStatefulSet(statefulSet); err != nil {
+			return errors.Trace(err)
+		}
+		defer func() {
+			// The stateful set is created again even if the restore
+			// fails, so the application's pods are still managed.
+			if createErr := v.recreateStatefulSet(statefulSet); createErr != nil && err == nil {
+				err = errors.Trace(createErr)
+			}
+		}()
+	}
+
+	podsAPI := v.client.client().CoreV1().Pods(claim.Namespace)
+	for _, pod := range pods {
+		logger.Infof("deleting pod %s to restore volume claim %s", pod.Name, claim.Name)
+		err := podsAPI.Delete(context.TODO(), pod.Name, v1.DeleteOptions{
+			Preconditions: &v1.Preconditions{UID: &pod.UID},
+		})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Annotatef(err, "deleting pod %v", pod.Name)
+		}
+	}
+
+	claimsAPI := v.client.client().CoreV1().PersistentVolumeClaims(claim.Namespace)
+	logger.Infof("restoring volume snapshot %s to volume claim %s", name, claim.Name)
+	err = claimsAPI.Delete(context.TODO(), claim.Name, v1.DeleteOptions{
+		Preconditions: &v1.Preconditions{UID: &claim.UID},
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Annotatef(err, "deleting volume claim %v", claim.Name)
+	}
+	if err := v.waitUntilDeleted(fmt.Sprintf("volume claim %q", claim.Name), func() error {
+		_, err := claimsAPI.Get(context.TODO(), claim.Name, v1.GetOptions{})
+		return err
+	}); err != nil {
+		return errors.Trace(err)
+	}
+
+	restored := claimFromSnapshot(claim, name)
+	restored.Labels = claim.Labels
+	restored.Annotations = claim.Annotations
+	_, err = claimsAPI.Create(context.TODO(), restored, v1.CreateOptions{})
+	return errors.Annotatef(err, "creating volume claim %v", restored.Name)
+}
+
+// checkVolumeSnapshotReady returns an error if the named snapshot wasn't
+// taken of the claim or isn't ready to be used by the operation.
+func (v *volumeSource) checkVolumeSnapshotReady(claim *core.PersistentVolumeClaim, volumeId, name, operation string) error {
+	snapshot, err := v.volumeSnapshots(claim.Namespace).Get(context.TODO(), name, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("volume snapshot %q", name)
+	} else if err != nil {
+		return errors.Annotatef(err, "getting volume snapshot %v", name)
+	}
+	if snapshotClaimName(snapshot) != claim.Name {
+		return errors.NotFoundf("volume snapshot %q of volume %q", name, volumeId)
+	}
+	if info := volumeSnapshotInfo(volumeId, snapshot); !info.Ready {
+		return errors.NotValidf("%s volume snapshot %q before it is ready", operation, name)
+	}
+	return nil
+}
+
+// claimFromSnapshot returns a volume claim, with the same name and spec
+// as the specified claim, to be provisioned from the named snapshot.
+func claimFromSnapshot(claim *core.PersistentVolumeClaim, name string) *core.PersistentVolumeClaim {
+	apiGroup := volumeSnapshotGroup
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			StorageClassName: claim.Spec.StorageClassName,
+			AccessModes:      claim.Spec.AccessModes,
+			VolumeMode:       claim.Spec.VolumeMode,
+			Resources:        claim.Spec.Resources,
+			DataSource: &core.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     volumeSnapshotKind,
+				Name:     name,
+			},
+		},
+	}
+}
+
+// claimPods returns the pods with a volume backed by the claim.
+func (v *volumeSource) claimPods(claim *core.PersistentVolumeClaim) ([]core.Pod, error) {
+	pods, err := v.client.client().CoreV1().Pods(claim.Namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Annotate(err, "listing pods")
+	}
+	var result []core.Pod
+	for _, pod := range pods.Items {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == claim.Name {
+				result = append(result, pod)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// podsStatefulSet returns the stateful set controlling the pods, or nil if
+// none of them is controlled by one.
+func (v *volumeSource) podsStatefulSet(namespace string, pods []core.Pod) (*apps.StatefulSet, error) {
+	var owner *v1.OwnerReference
+	for _, pod := range pods {
+		ref := v1.GetControllerOf(&pod)
+		if ref == nil || ref.Kind != "StatefulSet" {
+			continue
+		}
+		if owner != nil && owner.UID != ref.UID {
+			return nil, errors.NotSupportedf("restoring a volume claim used by more than one stateful set")
+		}
+		owner = ref
+	}
+	if owner == nil {
+		return nil, nil
+	}
+	statefulSet, err := v.client.client().AppsV1().StatefulSets(namespace).Get(context.TODO(), owner.Name, v1.GetOptions{})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting stateful set %v", owner.Name)
+	}
+	if statefulSet.UID != owner.UID {
+		return nil, errors.NotFoundf("stateful set %q with UID %q", owner.Name, owner.UID)
+	}
+	return statefulSet, nil
+}
+
+// orphanStatefulSet deletes the stateful set, leaving its pods and claims
+// in place, and waits for the deletion to complete.
+func (v *volumeSource) orphanStatefulSet(statefulSet *apps.StatefulSet) error {
+	api := v.client.client().AppsV1().StatefulSets(statefulSet.Namespace)
+	orphan := v1.DeletePropagationOrphan
+	err := api.Delete(context.TODO(), statefulSet.Name, v1.DeleteOptions{
+		PropagationPolicy: &orphan,
+		Preconditions:     &v1.Preconditions{UID: &statefulSet.UID},
+	})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "deleting stateful set %v", statefulSet.Name)
+	}
+	return errors.Trace(v.waitUntilDeleted(fmt.Sprintf("stateful set %q", statefulSet.Name), func() error {
+		_, err := api.Get(context.TODO(), statefulSet.Name, v1.GetOptions{})
+		return err
+	}))
+}
+
+// recreateStatefulSet creates the deleted stateful set again.
+func (v *volumeSource) recreateStatefulSet(statefulSet *apps.StatefulSet) error {
+	recreated := &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        statefulSet.Name,
+			Namespace:   statefulSet.Namespace,
+			Labels:      statefulSet.Labels,
+			Annotations: statefulSet.Annotations,
+		},
+		Spec: statefulSet.Spec,
+	}
+	_, err := v.client.client().AppsV1().StatefulSets(statefulSet.Namespace).Create(context.TODO(), recreated, v1.CreateOptions{})
+	return errors.Annotatef(err, "recreating stateful set %v", statefulSet.Name)
+}
+
+// waitUntilDeleted waits for get to report that the resource is not found.
+func (v *volumeSource) waitUntilDeleted(what string, get func() error) error {
+	errStillExists := errors.Errorf("%s still exists", what)
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			err := get()
+			if k8serrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			return errStillExists
+		},
+		IsFatalError: func(err error) bool {
+			return err != errStillExists
+		},
+		Attempts: 60,
+		Delay:    time.Second,
+		Clock:    v.client.clock,
+	})
+	if err != nil {
+		return errors.Annotatef(retry.LastError(err), "waiting for %s to be deleted", what)
+	}
+	return nil
+}
+
+func snapshotClaimName(snapshot *unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	return name
+}
+
+func volumeSnapshotInfo(volumeId string, snapshot *unstructured.Unstructured) jujustorage.VolumeSnapshot {
+	info := jujustorage.VolumeSnapshot{
+		Name:     snapshot.GetName(),
+		VolumeId: volumeId,
+		Created:  snapshot.GetCreationTimestamp().Time,
+	}
+	info.Ready, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	if restoreSize, _, _ := unstructured.NestedString(snapshot.Object, "status", "restoreSize"); restoreSize != "" {
+		if size, err := resource.ParseQuantity(restoreSize); err == nil {
+			info.Size = uint64(size.Value() / (1024 * 1024))
+		}
+	}
+	return info
+}
@@ -57,6 +57,35 @@ func (mr *MockApplicationMockRecorder) ApplicationPodSpec(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationPodSpec", reflect.TypeOf((*MockApplication)(nil).ApplicationPodSpec), arg0)
 }
 
+// Autoscale mocks base method.
+func (m *MockApplication) Autoscale(arg0 *caas.AutoscalingPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Autoscale", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Autoscale indicates an expected call of Autoscale.
+func (mr *MockApplicationMockRecorder) Autoscale(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Autoscale", reflect.TypeOf((*MockApplication)(nil).Autoscale), arg0)
+}
+
+// AutoscaleRecommendation mocks base method.
+func (m *MockApplication) AutoscaleRecommendation() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutoscaleRecommendation")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutoscaleRecommendation indicates an expected call of AutoscaleRecommendation.
+func (mr *MockApplicationMockRecorder) AutoscaleRecommendation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoscaleRecommendation", reflect.TypeOf((*MockApplication)(nil).AutoscaleRecommendation))
+}
+
 // Delete mocks base method.
 func (m *MockApplication) Delete() error {
 	m.ctrl.T.Helper()
@@ -85,6 +114,20 @@ func (mr *MockApplicationMockRecorder) Ensure(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ensure", reflect.TypeOf((*MockApplication)(nil).Ensure), arg0)
 }
 
+// EnsureNetworkPolicy mocks base method.
+func (m *MockApplication) EnsureNetworkPolicy(arg0 []caas.NetworkPolicyIngress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureNetworkPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureNetworkPolicy indicates an expected call of EnsureNetworkPolicy.
+func (mr *MockApplicationMockRecorder) EnsureNetworkPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureNetworkPolicy", reflect.TypeOf((*MockApplication)(nil).EnsureNetworkPolicy), arg0)
+}
+
 // Exists mocks base method.
 func (m *MockApplication) Exists() (caas.DeploymentState, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +143,20 @@ func (mr *MockApplicationMockRecorder) Exists() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockApplication)(nil).Exists))
 }
 
+// RemoveNetworkPolicy mocks base method.
+func (m *MockApplication) RemoveNetworkPolicy() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetworkPolicy")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetworkPolicy indicates an expected call of RemoveNetworkPolicy.
+func (mr *MockApplicationMockRecorder) RemoveNetworkPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkPolicy", reflect.TypeOf((*MockApplication)(nil).RemoveNetworkPolicy))
+}
+
 // Scale mocks base method.
 func (m *MockApplication) Scale(arg0 int) error {
 	m.ctrl.T.Helper()
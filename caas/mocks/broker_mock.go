@@ -322,6 +322,21 @@ func (mr *MockBrokerMockRecorder) EnsureService(arg0, arg1, arg2, arg3, arg4 any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureService", reflect.TypeOf((*MockBroker)(nil).EnsureService), arg0, arg1, arg2, arg3, arg4)
 }
 
+// Events mocks base method.
+func (m *MockBroker) Events() ([]caas.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].([]caas.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockBrokerMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockBroker)(nil).Events))
+}
+
 // ExposeService mocks base method.
 func (m *MockBroker) ExposeService(arg0 string, arg1 map[string]string, arg2 config.ConfigAttributes) error {
 	m.ctrl.T.Helper()
@@ -658,6 +673,21 @@ func (mr *MockBrokerMockRecorder) WatchContainerStart(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchContainerStart", reflect.TypeOf((*MockBroker)(nil).WatchContainerStart), arg0, arg1)
 }
 
+// WatchEvents mocks base method.
+func (m *MockBroker) WatchEvents() (watcher.NotifyWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchEvents")
+	ret0, _ := ret[0].(watcher.NotifyWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchEvents indicates an expected call of WatchEvents.
+func (mr *MockBrokerMockRecorder) WatchEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchEvents", reflect.TypeOf((*MockBroker)(nil).WatchEvents))
+}
+
 // WatchOperator mocks base method.
 func (m *MockBroker) WatchOperator(arg0 string) (watcher.NotifyWatcher, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,364 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"container/list"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cacheMetricsNamespace   = "juju"
+	cacheSubsystemNamespace = "charmhub_cache"
+
+	// cacheTempPrefix is the prefix for partially written archives in the
+	// cache directory. Files with this prefix are never served and are
+	// removed when the cache is opened.
+	cacheTempPrefix = ".partial-"
+
+	// defaultCacheMaxResponses is the number of API responses held in
+	// memory if no other value is configured.
+	defaultCacheMaxResponses = 256
+)
+
+const (
+	cacheKindArchive  = "archive"
+	cacheKindResponse = "response"
+
+	cacheResultHit         = "hit"
+	cacheResultMiss        = "miss"
+	cacheResultRevalidated = "revalidated"
+	cacheResultEvicted     = "evicted"
+)
+
+// CacheConfig holds the configuration for a charmhub Cache.
+type CacheConfig struct {
+	// Dir is the directory in which downloaded charm and resource archives
+	// are stored, named by their SHA-384 hash. This field is required.
+	Dir string
+
+	// MaxSize is the maximum number of bytes of archives to keep on disk.
+	// Least recently used archives are evicted once the limit is exceeded.
+	// A zero value means no limit.
+	MaxSize int64
+
+	// MaxResponses is the maximum number of info and find API responses
+	// kept in memory. If zero, a default is used.
+	MaxResponses int
+
+	// Clock is used to determine the freshness of cached API responses.
+	// If nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Validate ensures that the config is correct.
+func (c CacheConfig) Validate() error {
+	if c.Dir == "" {
+		return errors.NotValidf("empty cache dir")
+	}
+	if c.MaxSize < 0 {
+		return errors.NotValidf("negative cache max size")
+	}
+	if c.MaxResponses < 0 {
+		return errors.NotValidf("negative cache max responses")
+	}
+	return nil
+}
+
+// Cache is a content-addressed, on-disk cache of charm and resource
+// archives, along with an in-memory cache of API responses. A single Cache
+// is safe for concurrent use and can be shared between many clients.
+type Cache struct {
+	dir          string
+	maxSize      int64
+	maxResponses int
+	clock        clock.Clock
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+
+	responseLRU     *list.List
+	responseEntries map[string]*list.Element
+
+	requests *prometheus.CounterVec
+}
+
+type archiveEntry struct {
+	hash string
+	size int64
+}
+
+// NewCache creates a new Cache from the supplied configuration. Any archives
+// already in the cache directory are indexed, oldest first, so that a
+// restarted controller can continue to use them.
+func NewCache(config CacheConfig) (*Cache, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(config.Dir, 0700); err != nil {
+		return nil, errors.Annotate(err, "creating cache dir")
+	}
+
+	maxResponses := config.MaxResponses
+	if maxResponses == 0 {
+		maxResponses = defaultCacheMaxResponses
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+
+	cache := &Cache{
+		dir:             config.Dir,
+		maxSize:         config.MaxSize,
+		maxResponses:    maxResponses,
+		clock:           clk,
+		lru:             list.New(),
+		entries:         make(map[string]*list.Element),
+		responseLRU:     list.New(),
+		responseEntries: make(map[string]*list.Element),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cacheMetricsNamespace,
+			Subsystem: cacheSubsystemNamespace,
+			Name:      "requests_total",
+			Help:      "Number of charmhub cache lookups by kind and result.",
+		}, []string{"kind", "result"}),
+	}
+	if err := cache.load(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cache, nil
+}
+
+// load indexes the archives found in the cache directory.
+func (c *Cache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return errors.Annotate(err, "reading cache dir")
+	}
+
+	type found struct {
+		hash string
+		info os.FileInfo
+	}
+	var archives []found
+	for _, entry := range dirEntries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(name, cacheTempPrefix) {
+			_ = os.Remove(filepath.Join(c.dir, name))
+			continue
+		}
+		if !isSHA384(name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		archives = append(archives, found{hash: name, info: info})
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].info.ModTime().Before(archives[j].info.ModTime())
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, archive := range archives {
+		c.entries[archive.hash] = c.lru.PushFront(&archiveEntry{
+			hash: archive.hash,
+			size: archive.info.Size(),
+		})
+		c.size += archive.info.Size()
+	}
+	c.evictLocked()
+	return nil
+}
+
+// Size returns the total size in bytes of the archives in the cache.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Has returns true if an archive with the given SHA-384 hash is cached.
+func (c *Cache) Has(sha384 string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[sha384]
+	return ok
+}
+
+// Open returns a reader for the archive with the given SHA-384 hash,
+// marking it as recently used. A NotFound error is returned if the archive
+// is not in the cache.
+func (c *Cache) Open(sha384 string) (io.ReadCloser, int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[sha384]
+	if !ok {
+		c.requests.WithLabelValues(cacheKindArchive, cacheResultMiss).Inc()
+		return nil, -1, errors.NotFoundf("cached archive %q", sha384)
+	}
+
+	f, err := os.Open(filepath.Join(c.dir, sha384))
+	if os.IsNotExist(err) {
+		// The file was removed out from under us, forget about it.
+		c.removeLocked(elem)
+		c.requests.WithLabelValues(cacheKindArchive, cacheResultMiss).Inc()
+		return nil, -1, errors.NotFoundf("cached archive %q", sha384)
+	} else if err != nil {
+		return nil, -1, errors.Trace(err)
+	}
+	c.lru.MoveToFront(elem)
+	c.requests.WithLabelValues(cacheKindArchive, cacheResultHit).Inc()
+	return f, elem.Value.(*archiveEntry).size, nil
+}
+
+// Writer returns a writer that stores an archive with the given SHA-384
+// hash. The archive is only added to the cache once Commit is called on the
+// writer, and only if the written content matches the hash.
+func (c *Cache) Writer(sha384 string) (*CacheWriter, error) {
+	if !isSHA384(sha384) {
+		return nil, errors.NotValidf("sha384 hash %q", sha384)
+	}
+	f, err := os.CreateTemp(c.dir, cacheTempPrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &CacheWriter{
+		cache:    c,
+		file:     f,
+		hash:     sha512.New384(),
+		expected: sha384,
+	}, nil
+}
+
+func (c *Cache) add(sha384, tmpPath string, size int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[sha384]; ok {
+		// Someone beat us to it, the content is identical.
+		c.lru.MoveToFront(elem)
+		return errors.Trace(os.Remove(tmpPath))
+	}
+	if err := os.Rename(tmpPath, filepath.Join(c.dir, sha384)); err != nil {
+		return errors.Trace(err)
+	}
+	c.entries[sha384] = c.lru.PushFront(&archiveEntry{
+		hash: sha384,
+		size: size,
+	})
+	c.size += size
+	c.evictLocked()
+	return nil
+}
+
+// evictLocked removes the least recently used archives until the cache is
+// within its size limit. The newest archive is never removed, even if it is
+// larger than the limit on its own.
+func (c *Cache) evictLocked() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for c.size > c.maxSize && c.lru.Len() > 1 {
+		c.removeLocked(c.lru.Back())
+		c.requests.WithLabelValues(cacheKindArchive, cacheResultEvicted).Inc()
+	}
+}
+
+func (c *Cache) removeLocked(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*archiveEntry)
+	delete(c.entries, entry.hash)
+	c.size -= entry.size
+	_ = os.Remove(filepath.Join(c.dir, entry.hash))
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *Cache) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *Cache) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+}
+
+// CacheWriter writes an archive into the cache, verifying its content
+// against the expected SHA-384 hash.
+type CacheWriter struct {
+	cache    *Cache
+	file     *os.File
+	hash     hash.Hash
+	expected string
+	size     int64
+	done     bool
+}
+
+// Write is part of the io.Writer interface.
+func (w *CacheWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	_, _ = w.hash.Write(p[:n])
+	return n, err
+}
+
+// Commit adds the written archive to the cache. An error satisfying
+// errors.NotValid is returned if the content does not match the expected
+// hash, in which case nothing is cached.
+func (w *CacheWriter) Commit() error {
+	if w.done {
+		return errors.Errorf("cache writer already closed")
+	}
+	w.done = true
+
+	tmpPath := w.file.Name()
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Trace(err)
+	}
+	if actual := hex.EncodeToString(w.hash.Sum(nil)); actual != w.expected {
+		_ = os.Remove(tmpPath)
+		return errors.NotValidf("archive sha384 hash %q, expected %q", actual, w.expected)
+	}
+	return errors.Trace(w.cache.add(w.expected, tmpPath, w.size))
+}
+
+// Abort discards the written content. It is safe to call Abort after
+// Commit.
+func (w *CacheWriter) Abort() {
+	if w.done {
+		return
+	}
+	w.done = true
+	_ = w.file.Close()
+	_ = os.Remove(w.file.Name())
+}
+
+func isSHA384(s string) bool {
+	if len(s) != sha512.Size384*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,344 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub/transport"
+)
+
+type CacheSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CacheSuite{})
+
+func (s *CacheSuite) TestValidate(c *gc.C) {
+	_, err := NewCache(CacheConfig{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = NewCache(CacheConfig{Dir: c.MkDir(), MaxSize: -1})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *CacheSuite) TestWriteAndOpen(c *gc.C) {
+	cache := s.newCache(c, 0)
+
+	hash := s.put(c, cache, "meshuggah")
+	c.Assert(cache.Has(hash), jc.IsTrue)
+	c.Assert(cache.Size(), gc.Equals, int64(len("meshuggah")))
+
+	r, size, err := cache.Open(hash)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	c.Assert(size, gc.Equals, int64(len("meshuggah")))
+
+	content, err := io.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, "meshuggah")
+}
+
+func (s *CacheSuite) TestOpenNotFound(c *gc.C) {
+	cache := s.newCache(c, 0)
+
+	_, _, err := cache.Open(sha384Of("meshuggah"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CacheSuite) TestCommitHashMismatch(c *gc.C) {
+	cache := s.newCache(c, 0)
+
+	hash := sha384Of("meshuggah")
+	w, err := cache.Writer(hash)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte("gojira"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Commit()
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(cache.Has(hash), jc.IsFalse)
+	c.Assert(s.files(c, cache), gc.HasLen, 0)
+}
+
+func (s *CacheSuite) TestWriterInvalidHash(c *gc.C) {
+	cache := s.newCache(c, 0)
+
+	_, err := cache.Writer("../../etc/passwd")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *CacheSuite) TestEvictsLeastRecentlyUsed(c *gc.C) {
+	cache := s.newCache(c, 10)
+
+	first := s.put(c, cache, "aaaa")
+	second := s.put(c, cache, "bbbb")
+
+	// Touch the first archive, so the second is now the oldest.
+	r, _, err := cache.Open(first)
+	c.Assert(err, jc.ErrorIsNil)
+	_ = r.Close()
+
+	third := s.put(c, cache, "cccc")
+
+	c.Assert(cache.Has(first), jc.IsTrue)
+	c.Assert(cache.Has(second), jc.IsFalse)
+	c.Assert(cache.Has(third), jc.IsTrue)
+	c.Assert(cache.Size(), gc.Equals, int64(8))
+	c.Assert(s.files(c, cache), gc.HasLen, 2)
+}
+
+func (s *CacheSuite) TestReloadExisting(c *gc.C) {
+	dir := c.MkDir()
+	cache, err := NewCache(CacheConfig{Dir: dir})
+	c.Assert(err, jc.ErrorIsNil)
+	hash := s.put(c, cache, "meshuggah")
+
+	err = os.WriteFile(filepath.Join(dir, cacheTempPrefix+"1234"), []byte("partial"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cache, err = NewCache(CacheConfig{Dir: dir})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cache.Has(hash), jc.IsTrue)
+	c.Assert(cache.Size(), gc.Equals, int64(len("meshuggah")))
+	c.Assert(s.files(c, cache), gc.HasLen, 1)
+}
+
+func (s *CacheSuite) TestDownloadPopulatesCache(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+	hash := sha384Of("archive")
+	archivePath := filepath.Join(c.MkDir(), "archive")
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("archive")),
+	}, nil)
+
+	client := newDownloadClient(httpClient, fileSystem{}, cache, &FakeLogger{})
+	serverURL := MustParseURL(c, "http://meshuggah.rocks")
+
+	err := client.Download(context.Background(), serverURL, archivePath, WithSHA384(hash))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cache.Has(hash), jc.IsTrue)
+
+	// The second download must not hit the server.
+	secondPath := filepath.Join(c.MkDir(), "archive")
+	err = client.Download(context.Background(), serverURL, secondPath, WithSHA384(hash))
+	c.Assert(err, jc.ErrorIsNil)
+
+	content, err := os.ReadFile(secondPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, "archive")
+}
+
+func (s *CacheSuite) TestDownloadHashMismatch(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+	hash := sha384Of("archive")
+	archivePath := filepath.Join(c.MkDir(), "archive")
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("corrupt")),
+	}, nil)
+
+	client := newDownloadClient(httpClient, fileSystem{}, cache, &FakeLogger{})
+	serverURL := MustParseURL(c, "http://meshuggah.rocks")
+
+	err := client.Download(context.Background(), serverURL, archivePath, WithSHA384(hash))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "http://meshuggah.rocks": archive sha384 hash .* not valid`)
+	c.Assert(cache.Has(hash), jc.IsFalse)
+}
+
+func (s *CacheSuite) TestDownloadResourcePopulatesCache(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+	hash := sha384Of("resource")
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("resource")),
+	}, nil)
+
+	client := newDownloadClient(httpClient, fileSystem{}, cache, &FakeLogger{})
+	serverURL := MustParseURL(c, "http://meshuggah.rocks")
+	ctx := context.WithValue(context.Background(), DownloadSHA384Key, hash)
+
+	for i := 0; i < 2; i++ {
+		r, err := client.DownloadResource(ctx, serverURL)
+		c.Assert(err, jc.ErrorIsNil)
+		content, err := io.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(r.Close(), jc.ErrorIsNil)
+		c.Assert(string(content), gc.Equals, "resource")
+	}
+	c.Assert(cache.Has(hash), jc.IsTrue)
+}
+
+func (s *CacheSuite) TestResponseCacheMaxAge(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	clock := testclock.NewClock(time.Now())
+	cache, err := NewCache(CacheConfig{Dir: c.MkDir(), Clock: clock})
+	c.Assert(err, jc.ErrorIsNil)
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		return s.jsonResponse(http.StatusOK, `{"name":"wordpress"}`, "max-age=60", ""), nil
+	}).Times(2)
+
+	client := newCachingHTTPClient(httpClient, cache, &FakeLogger{})
+
+	s.assertInfoName(c, client, "wordpress")
+	s.assertInfoName(c, client, "wordpress")
+
+	clock.Advance(time.Minute)
+	s.assertInfoName(c, client, "wordpress")
+}
+
+func (s *CacheSuite) TestResponseCacheRevalidate(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+
+	httpClient := NewMockHTTPClient(ctrl)
+	gomock.InOrder(
+		httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			c.Check(req.Header.Get("If-None-Match"), gc.Equals, "")
+			return s.jsonResponse(http.StatusOK, `{"name":"wordpress"}`, "no-cache", `"abc"`), nil
+		}),
+		httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			c.Check(req.Header.Get("If-None-Match"), gc.Equals, `"abc"`)
+			return s.jsonResponse(http.StatusNotModified, "", "no-cache", `"abc"`), nil
+		}),
+	)
+
+	client := newCachingHTTPClient(httpClient, cache, &FakeLogger{})
+
+	s.assertInfoName(c, client, "wordpress")
+	s.assertInfoName(c, client, "wordpress")
+}
+
+func (s *CacheSuite) TestResponseCacheNoStore(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		c.Check(req.Header.Get("If-None-Match"), gc.Equals, "")
+		return s.jsonResponse(http.StatusOK, `{"name":"wordpress"}`, "no-store", `"abc"`), nil
+	}).Times(2)
+
+	client := newCachingHTTPClient(httpClient, cache, &FakeLogger{})
+
+	s.assertInfoName(c, client, "wordpress")
+	s.assertInfoName(c, client, "wordpress")
+}
+
+func (s *CacheSuite) TestResponseCacheIgnoresPost(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	cache := s.newCache(c, 0)
+
+	httpClient := NewMockHTTPClient(ctrl)
+	httpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		return s.jsonResponse(http.StatusOK, `{}`, "max-age=60", `"abc"`), nil
+	}).Times(2)
+
+	client := newCachingHTTPClient(httpClient, cache, &FakeLogger{})
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("POST", "http://api.foo.bar/v2/charms/refresh", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = client.Do(req)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *CacheSuite) assertInfoName(c *gc.C, client HTTPClient, name string) {
+	restClient := newHTTPRESTClient(client)
+
+	var result transport.InfoResponse
+	_, err := restClient.Get(context.Background(), MustMakePath(c, "http://api.foo.bar/v2/charms/info/wordpress"), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Name, gc.Equals, name)
+}
+
+func (s *CacheSuite) jsonResponse(status int, body, cacheControl, etag string) *http.Response {
+	header := MakeContentTypeHeader("application/json")
+	if cacheControl != "" {
+		header.Set("Cache-Control", cacheControl)
+	}
+	if etag != "" {
+		header.Set("ETag", etag)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func (s *CacheSuite) newCache(c *gc.C, maxSize int64) *Cache {
+	cache, err := NewCache(CacheConfig{
+		Dir:     c.MkDir(),
+		MaxSize: maxSize,
+		Clock:   testclock.NewClock(time.Now()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return cache
+}
+
+func (s *CacheSuite) put(c *gc.C, cache *Cache, content string) string {
+	hash := sha384Of(content)
+	w, err := cache.Writer(hash)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(content))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Commit(), jc.ErrorIsNil)
+	return hash
+}
+
+func (s *CacheSuite) files(c *gc.C, cache *Cache) []string {
+	entries, err := os.ReadDir(cache.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func sha384Of(content string) string {
+	sum := sha512.Sum384([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
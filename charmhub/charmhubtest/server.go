@@ -0,0 +1,460 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmhubtest provides a Charmhub compatible HTTP server that serves
+// charms, bundles and resources from disk. It implements enough of the info,
+// find, refresh, resources and download endpoints for charmhub.Client, and
+// so a controller with its charmhub-url pointed at it, to work without
+// network access.
+package charmhubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/charm/v12"
+
+	"github.com/juju/juju/charmhub/transport"
+)
+
+const (
+	// downloadPath is the path blobs are served from, followed by their
+	// SHA-384 hash.
+	downloadPath = "/api/v1/download/"
+
+	jsonContentType = "application/json"
+)
+
+// risks holds the channel risks from most to least stable.
+var risks = []charm.Risk{charm.Stable, charm.Candidate, charm.Beta, charm.Edge}
+
+// Server is a Charmhub compatible HTTP handler serving a Store.
+type Server struct {
+	store *Store
+	mux   *http.ServeMux
+}
+
+// NewServer creates a new Server for the store. The store must not be
+// modified once it is being served.
+func NewServer(store *Store) *Server {
+	s := &Server{
+		store: store,
+		mux:   http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /v2/charms/info/{name}", s.serveInfo)
+	s.mux.HandleFunc("GET /v2/charms/find", s.serveFind)
+	s.mux.HandleFunc("POST /v2/charms/refresh", s.serveRefresh)
+	s.mux.HandleFunc("GET /v2/charms/resources/{name}/{resource}/revisions", s.serveResourceRevisions)
+	s.mux.HandleFunc("GET "+downloadPath+"{hash}", s.serveDownload)
+	return s
+}
+
+// NewTestServer starts a new httptest.Server serving the store. The URL of
+// the returned server can be used as charmhub.Config.URL or as the
+// charmhub-url model config. The caller must close the server.
+func NewTestServer(store *Store) *httptest.Server {
+	return httptest.NewServer(NewServer(store))
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(w, req)
+}
+
+func (s *Server) serveInfo(w http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("name")
+	ent, ok := s.store.lookup(name)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, transport.InfoResponse{
+			ErrorList: notFound(fmt.Sprintf("No charm or bundle with name %q.", name)),
+		})
+		return
+	}
+
+	resp := transport.InfoResponse{
+		Type:   ent.typ,
+		ID:     ent.id,
+		Name:   ent.name,
+		Entity: transport.Entity{Summary: ent.summary, Description: ent.desc},
+	}
+	requested := req.URL.Query().Get("channel")
+	var haveDefault bool
+	for _, rel := range ent.sortedReleases() {
+		rev := ent.revisions[rel.revision]
+		channelMap := transport.InfoChannelMap{
+			Channel: transportChannel(rel),
+			Revision: transport.InfoRevision{
+				ConfigYAML:   rev.configYAML,
+				CreatedAt:    rev.created.Format(time.RFC3339),
+				Download:     transport.Download{Size: int(rev.blob.size)},
+				MetadataYAML: rev.metadataYAML,
+				BundleYAML:   rev.bundleYAML,
+				Bases:        rev.bases,
+				Revision:     rev.revision,
+				Version:      rev.version,
+			},
+		}
+		resp.ChannelMap = append(resp.ChannelMap, channelMap)
+		if !haveDefault || (requested != "" && channelMap.Channel.Name == requested) {
+			resp.DefaultRelease = channelMap
+			haveDefault = true
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serveFind(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query().Get("q")
+
+	names := make([]string, 0, len(s.store.entities))
+	for name := range s.store.entities {
+		if strings.Contains(name, query) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	resp := transport.FindResponses{}
+	for _, name := range names {
+		ent := s.store.entities[name]
+		result := transport.FindResponse{
+			Type:   ent.typ,
+			ID:     ent.id,
+			Name:   ent.name,
+			Entity: transport.Entity{Summary: ent.summary, Description: ent.desc},
+		}
+		if releases := ent.sortedReleases(); len(releases) > 0 {
+			rev := ent.revisions[releases[0].revision]
+			result.DefaultRelease = transport.FindChannelMap{
+				Channel: transportChannel(releases[0]),
+				Revision: transport.FindRevision{
+					CreatedAt: rev.created.Format(time.RFC3339),
+					Download:  transport.Download{Size: int(rev.blob.size)},
+					Bases:     rev.bases,
+					Revision:  rev.revision,
+					Version:   rev.version,
+				},
+			}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serveRefresh(w http.ResponseWriter, req *http.Request) {
+	var refreshReq transport.RefreshRequest
+	if err := json.NewDecoder(req.Body).Decode(&refreshReq); err != nil {
+		writeJSON(w, http.StatusBadRequest, transport.RefreshResponses{
+			ErrorList: transport.APIErrors{{
+				Code:    transport.ErrorCodeBadArgument,
+				Message: err.Error(),
+			}},
+		})
+		return
+	}
+
+	contexts := make(map[string]transport.RefreshRequestContext)
+	for _, ctx := range refreshReq.Context {
+		contexts[ctx.InstanceKey] = ctx
+	}
+
+	baseURL := requestBaseURL(req)
+	resp := transport.RefreshResponses{
+		Results: make([]transport.RefreshResponse, 0, len(refreshReq.Actions)),
+	}
+	for _, action := range refreshReq.Actions {
+		resp.Results = append(resp.Results, s.refresh(baseURL, action, contexts[action.InstanceKey]))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// refresh resolves a single refresh action.
+func (s *Server) refresh(baseURL string, action transport.RefreshRequestAction, ctx transport.RefreshRequestContext) transport.RefreshResponse {
+	resp := transport.RefreshResponse{
+		InstanceKey: action.InstanceKey,
+		Result:      action.Action,
+	}
+
+	var nameOrID string
+	switch {
+	case action.Name != nil:
+		nameOrID = *action.Name
+	case action.ID != nil:
+		nameOrID = *action.ID
+	default:
+		nameOrID = ctx.ID
+	}
+	ent, ok := s.store.lookup(nameOrID)
+	if !ok {
+		resp.Error = &transport.APIError{
+			Code:    transport.ErrorCodeNotFound,
+			Message: fmt.Sprintf("No charm or bundle with name or id %q.", nameOrID),
+		}
+		return resp
+	}
+	resp.ID, resp.Name = ent.id, ent.name
+
+	base := ctx.Base
+	if action.Base != nil {
+		base = *action.Base
+	}
+
+	var rev *revision
+	if action.Revision != nil {
+		if rev, ok = ent.revisions[*action.Revision]; !ok {
+			resp.Error = &transport.APIError{
+				Code:    transport.ErrorCodeRevisionNotFound,
+				Message: fmt.Sprintf("No revision %d of %q.", *action.Revision, ent.name),
+			}
+			return resp
+		}
+	} else {
+		channelName := ctx.TrackingChannel
+		if action.Channel != nil {
+			channelName = *action.Channel
+		}
+		if channelName == "" {
+			channelName = DefaultChannel
+		}
+		channel, err := parseChannel(channelName)
+		if err != nil {
+			resp.Error = &transport.APIError{
+				Code:    transport.ErrorCodeInvalidChannel,
+				Message: err.Error(),
+			}
+			return resp
+		}
+
+		rel, ok := ent.resolve(channel, base)
+		if !ok {
+			resp.Error = &transport.APIError{
+				Code:    transport.ErrorCodeRevisionNotFound,
+				Message: fmt.Sprintf("No revision of %q in channel %q for the requested base.", ent.name, channel),
+				Extra:   transport.APIErrorExtra{Releases: ent.suggestions()},
+			}
+			return resp
+		}
+		rev = ent.revisions[rel.revision]
+		resp.EffectiveChannel = rel.channel.String()
+		resp.ReleasedAt = rel.released
+	}
+
+	resp.Entity = transport.RefreshEntity{
+		Type:         ent.typ,
+		Download:     transportDownload(baseURL, rev.blob),
+		ID:           ent.id,
+		Name:         ent.name,
+		Bases:        rev.bases,
+		Revision:     rev.revision,
+		Summary:      ent.summary,
+		Version:      rev.version,
+		CreatedAt:    rev.created,
+		MetadataYAML: rev.metadataYAML,
+		ConfigYAML:   rev.configYAML,
+		Resources:    []transport.ResourceRevision{},
+	}
+	requested := make(map[string]int)
+	for _, res := range action.ResourceRevisions {
+		requested[res.Name] = res.Revision
+	}
+	for _, res := range rev.resources {
+		if revNum, ok := requested[res.name]; ok {
+			if other, ok := ent.resource(res.name, revNum); ok {
+				res = other
+			}
+		}
+		resp.Entity.Resources = append(resp.Entity.Resources, transportResource(baseURL, res))
+	}
+	return resp
+}
+
+func (s *Server) serveResourceRevisions(w http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("name")
+	ent, ok := s.store.lookup(name)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, transport.APIErrors{{
+			Code:    transport.ErrorCodeNotFound,
+			Message: fmt.Sprintf("No charm with name %q.", name),
+		}})
+		return
+	}
+
+	baseURL := requestBaseURL(req)
+	resourceName := req.PathValue("resource")
+	seen := make(map[int]bool)
+	resp := transport.ResourcesResponse{}
+	for _, rev := range ent.revisions {
+		for _, res := range rev.resources {
+			if res.name != resourceName || seen[res.revision] {
+				continue
+			}
+			seen[res.revision] = true
+			resp.Revisions = append(resp.Revisions, transportResource(baseURL, res))
+		}
+	}
+	sort.Slice(resp.Revisions, func(i, j int) bool {
+		return resp.Revisions[i].Revision > resp.Revisions[j].Revision
+	})
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, req *http.Request) {
+	b, ok := s.store.blobs[req.PathValue("hash")]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	content, err := b.open()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, req, "", time.Time{}, content)
+}
+
+// resolve returns the release for the channel and base. If the channel has
+// nothing released for the base, less risky channels on the same track are
+// tried, in the same way as Charmhub.
+func (e *entity) resolve(channel charm.Channel, base transport.Base) (release, bool) {
+	for i := len(risks) - 1; i >= 0; i-- {
+		if risks[i] != channel.Risk && !riskAbove(risks[i], channel.Risk) {
+			continue
+		}
+		for _, rel := range e.releases {
+			if rel.channel.Track == channel.Track && rel.channel.Risk == risks[i] && rel.channel.Branch == channel.Branch && baseMatches(base, rel.base) {
+				return rel, true
+			}
+		}
+	}
+	return release{}, false
+}
+
+// riskAbove returns true if risk a is more stable than risk b.
+func riskAbove(a, b charm.Risk) bool {
+	var ia, ib int
+	for i, r := range risks {
+		if r == a {
+			ia = i
+		}
+		if r == b {
+			ib = i
+		}
+	}
+	return ia < ib
+}
+
+// suggestions returns every release of the entity, for use in
+// revision-not-found errors.
+func (e *entity) suggestions() []transport.Release {
+	var releases []transport.Release
+	for _, rel := range e.sortedReleases() {
+		releases = append(releases, transport.Release{
+			Base:    rel.base,
+			Channel: rel.channel.String(),
+		})
+	}
+	return releases
+}
+
+// resource returns the resource with the given name and revision from any
+// revision of the entity.
+func (e *entity) resource(name string, revNum int) (resource, bool) {
+	for _, rev := range e.revisions {
+		for _, res := range rev.resources {
+			if res.name == name && res.revision == revNum {
+				return res, true
+			}
+		}
+	}
+	return resource{}, false
+}
+
+// sortedReleases returns the releases ordered by track, then from most to
+// least stable risk.
+func (e *entity) sortedReleases() []release {
+	releases := append([]release(nil), e.releases...)
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if a.channel.Track != b.channel.Track {
+			return a.channel.Track == "latest" || (b.channel.Track != "latest" && a.channel.Track < b.channel.Track)
+		}
+		return riskAbove(a.channel.Risk, b.channel.Risk)
+	})
+	return releases
+}
+
+// baseMatches returns true if a release for the have base satisfies a
+// request for the want base. Empty fields in want match anything.
+func baseMatches(want, have transport.Base) bool {
+	if want.Architecture != "" && have.Architecture != "all" && want.Architecture != have.Architecture {
+		return false
+	}
+	if want.Name != "" && want.Name != have.Name {
+		return false
+	}
+	trimRisk := func(s string) string {
+		return strings.TrimSuffix(s, "/stable")
+	}
+	return want.Channel == "" || trimRisk(want.Channel) == trimRisk(have.Channel)
+}
+
+func transportChannel(rel release) transport.Channel {
+	return transport.Channel{
+		Name:       rel.channel.String(),
+		Base:       rel.base,
+		ReleasedAt: rel.released.Format(time.RFC3339),
+		Risk:       string(rel.channel.Risk),
+		Track:      rel.channel.Track,
+	}
+}
+
+func transportDownload(baseURL string, b blob) transport.Download {
+	return transport.Download{
+		HashSHA256: b.sha256,
+		HashSHA384: b.sha384,
+		Size:       int(b.size),
+		URL:        baseURL + downloadPath + b.sha384,
+	}
+}
+
+func transportResource(baseURL string, res resource) transport.ResourceRevision {
+	return transport.ResourceRevision{
+		Download:    transportDownload(baseURL, res.blob),
+		Description: res.description,
+		Name:        res.name,
+		Filename:    res.filename,
+		Revision:    res.revision,
+		Type:        res.typ,
+	}
+}
+
+// requestBaseURL returns the URL clients used to reach the server, so that
+// download URLs resolve back to it.
+func requestBaseURL(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, req.Host)
+}
+
+func notFound(msg string) transport.APIErrors {
+	return transport.APIErrors{{
+		Code:    transport.ErrorCodeNotFound,
+		Message: msg,
+	}}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
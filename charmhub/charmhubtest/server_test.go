@@ -0,0 +1,224 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhubtest_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/charmhubtest"
+	"github.com/juju/juju/charmhub/mirror"
+	"github.com/juju/juju/charmhub/transport"
+	"github.com/juju/juju/testcharms/repo"
+)
+
+func Test(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type serverSuite struct {
+	jujutesting.IsolationSuite
+
+	server *httptest.Server
+	client *charmhub.Client
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+const manifestYAML = `
+bases:
+- name: ubuntu
+  channel: "22.04"
+  architectures: [amd64]
+`
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	dir := c.MkDir()
+	charmRepo := repo.NewRepo("../../testcharms/charm-repo", "quantal")
+
+	// An archive, as built by charmcraft.
+	archive := charmRepo.CharmArchivePath(c.MkDir(), "dummy")
+	err := os.Rename(archive, filepath.Join(dir, "dummy.charm"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A charm directory with a resource.
+	charmDir := charmRepo.ClonedDirPath(dir, "dummy-resource")
+	err = os.WriteFile(filepath.Join(charmDir, "manifest.yaml"), []byte(manifestYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(filepath.Join(dir, "dummy-resource.resources"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(dir, "dummy-resource.resources", "dummy"), []byte("resource content"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	store, err := charmhubtest.LoadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.server = charmhubtest.NewTestServer(store)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	s.client, err = charmhub.NewClient(charmhub.Config{
+		URL:    s.server.URL,
+		Logger: loggo.GetLogger("juju.charmhub.charmhubtest"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serverSuite) TestInfo(c *gc.C) {
+	info, err := s.client.Info(context.Background(), "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Type, gc.Equals, transport.CharmType)
+	c.Check(info.Name, gc.Equals, "dummy")
+	c.Check(info.Entity.Summary, gc.Equals, "That's a dummy charm.")
+	c.Check(info.DefaultRelease.Channel.Name, gc.Equals, "latest/stable")
+	c.Check(info.DefaultRelease.Revision.MetadataYAML, gc.Matches, "(?s)name: dummy.*")
+	c.Check(info.ChannelMap, gc.HasLen, 3)
+}
+
+func (s *serverSuite) TestInfoDefaultsToFirstReleaseOfRevisionZero(c *gc.C) {
+	dir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(dir, mirror.BlobDir), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(mirror.BlobPath(dir, "deadbeef"), []byte("charm"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	base := transport.Base{Architecture: "amd64", Name: "ubuntu", Channel: "22.04"}
+	entity := transport.RefreshEntity{
+		Type:     transport.CharmType,
+		Name:     "zero",
+		Download: transport.Download{HashSHA384: "deadbeef"},
+		Revision: 0,
+	}
+	store := charmhubtest.NewStore()
+	err = store.AddMirror(dir, mirror.Manifest{Entries: []mirror.Entry{
+		{Channel: "edge", Base: base, Entity: entity},
+		{Channel: "stable", Base: base, Entity: entity},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	server := charmhubtest.NewTestServer(store)
+	defer server.Close()
+	client, err := charmhub.NewClient(charmhub.Config{
+		URL:    server.URL,
+		Logger: loggo.GetLogger("juju.charmhub.charmhubtest"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := client.Info(context.Background(), "zero")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.ChannelMap, gc.HasLen, 2)
+	c.Check(info.DefaultRelease.Channel.Name, gc.Equals, "latest/stable")
+	c.Check(info.DefaultRelease.Revision.Revision, gc.Equals, 0)
+}
+
+func (s *serverSuite) TestInfoNotFound(c *gc.C) {
+	_, err := s.client.Info(context.Background(), "missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestFind(c *gc.C) {
+	results, err := s.client.Find(context.Background(), "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0].Name, gc.Equals, "dummy")
+	c.Check(results[1].Name, gc.Equals, "dummy-resource")
+}
+
+func (s *serverSuite) TestRefreshAndDownload(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("dummy-resource", "stable", charmhub.RefreshBase{
+		Architecture: "amd64",
+		Name:         "ubuntu",
+		Channel:      "22.04",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.client.Refresh(context.Background(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Check(results[0].EffectiveChannel, gc.Equals, "latest/stable")
+
+	entity := results[0].Entity
+	c.Check(entity.Name, gc.Equals, "dummy-resource")
+	c.Assert(entity.Resources, gc.HasLen, 1)
+
+	downloadURL, err := url.Parse(entity.Download.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := s.client.DownloadAndRead(context.Background(), downloadURL, filepath.Join(c.MkDir(), "charm"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archive.Meta().Name, gc.Equals, "dummy-resource")
+
+	resourceURL, err := url.Parse(entity.Resources[0].Download.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	r, err := s.client.DownloadResource(context.Background(), resourceURL)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(content), gc.Equals, "resource content")
+}
+
+func (s *serverSuite) TestRefreshRiskFallback(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("dummy", "edge", charmhub.RefreshBase{
+		Architecture: "amd64",
+		Name:         "ubuntu",
+		Channel:      "20.04",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.client.Refresh(context.Background(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Check(results[0].EffectiveChannel, gc.Equals, "latest/stable")
+}
+
+func (s *serverSuite) TestRefreshUnsupportedBase(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("dummy-resource", "stable", charmhub.RefreshBase{
+		Architecture: "amd64",
+		Name:         "ubuntu",
+		Channel:      "20.04",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.client.Refresh(context.Background(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.NotNil)
+	c.Check(results[0].Error.Code, gc.Equals, transport.ErrorCodeRevisionNotFound)
+	c.Check(results[0].Error.Extra.Releases, jc.DeepEquals, []transport.Release{{
+		Base:    transport.Base{Architecture: "amd64", Name: "ubuntu", Channel: "22.04"},
+		Channel: "latest/stable",
+	}})
+}
+
+func (s *serverSuite) TestRefreshByRevision(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromRevision("dummy", 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.client.Refresh(context.Background(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Check(results[0].Entity.Revision, gc.Equals, 1)
+}
+
+func (s *serverSuite) TestListResourceRevisions(c *gc.C) {
+	revisions, err := s.client.ListResourceRevisions(context.Background(), "dummy-resource", "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisions, gc.HasLen, 1)
+	c.Check(revisions[0].Name, gc.Equals, "dummy")
+	c.Check(revisions[0].Filename, gc.Equals, "dummy-resource.zip")
+}
@@ -0,0 +1,428 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhubtest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charmhub/mirror"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+// DefaultChannel is the channel charms read from archives or directories
+// are released to.
+const DefaultChannel = "latest/stable"
+
+// blob is the content of a charm, bundle or resource, held either on disk
+// or in memory.
+type blob struct {
+	path   string
+	data   []byte
+	sha256 string
+	sha384 string
+	size   int64
+}
+
+func (b blob) open() (io.ReadSeekCloser, error) {
+	if b.path == "" {
+		return nopSeekCloser{bytes.NewReader(b.data)}, nil
+	}
+	f, err := os.Open(b.path)
+	return f, errors.Trace(err)
+}
+
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+// resource is a single resource revision attached to a charm revision.
+type resource struct {
+	name        string
+	typ         string
+	filename    string
+	description string
+	revision    int
+	blob        blob
+}
+
+// revision is a single revision of a charm or bundle.
+type revision struct {
+	revision     int
+	version      string
+	created      time.Time
+	bases        []transport.Base
+	metadataYAML string
+	configYAML   string
+	bundleYAML   string
+	blob         blob
+	resources    []resource
+}
+
+// release places a revision in a channel for a base.
+type release struct {
+	channel  charm.Channel
+	base     transport.Base
+	revision int
+	released time.Time
+}
+
+// entity is a charm or bundle with all its revisions and releases.
+type entity struct {
+	id        string
+	name      string
+	typ       transport.Type
+	summary   string
+	desc      string
+	revisions map[int]*revision
+	releases  []release
+}
+
+// Store holds the charms and bundles served by a Server. A Store must be
+// fully populated before it is served.
+type Store struct {
+	entities map[string]*entity
+	blobs    map[string]blob
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{
+		entities: make(map[string]*entity),
+		blobs:    make(map[string]blob),
+	}
+}
+
+// LoadDir creates a Store from a directory. If the directory is a charm
+// mirror directory, as written by the charm-mirror import command, every
+// entry of its manifest is loaded. Otherwise every charm archive (*.charm)
+// and charm directory within it is added to DefaultChannel. Resources for
+// a charm can be supplied as files in a "<charm>.resources" directory next
+// to it, named after the resource.
+func LoadDir(dir string) (*Store, error) {
+	store := NewStore()
+	if _, err := os.Stat(filepath.Join(dir, mirror.ManifestFile)); err == nil {
+		manifest, err := mirror.ReadManifest(dir)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := store.AddMirror(dir, manifest); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return store, nil
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, dirEntry := range dirEntries {
+		path := filepath.Join(dir, dirEntry.Name())
+		switch {
+		case dirEntry.IsDir() && strings.HasSuffix(dirEntry.Name(), ".resources"):
+			continue
+		case dirEntry.IsDir():
+			if _, err := os.Stat(filepath.Join(path, "metadata.yaml")); err != nil {
+				continue
+			}
+		case !strings.HasSuffix(dirEntry.Name(), ".charm"):
+			continue
+		}
+		if err := store.AddCharm(path); err != nil {
+			return nil, errors.Annotatef(err, "adding %q", dirEntry.Name())
+		}
+	}
+	return store, nil
+}
+
+// AddCharm adds the charm archive or directory at path to the store,
+// releasing it to DefaultChannel for every base in its manifest.
+func (s *Store) AddCharm(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var (
+		ch      charm.Charm
+		content blob
+		files   func(string) string
+	)
+	if info.IsDir() {
+		dir, err := charm.ReadCharmDir(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var buf bytes.Buffer
+		if err := dir.ArchiveTo(&buf); err != nil {
+			return errors.Trace(err)
+		}
+		ch, content = dir, newMemoryBlob(buf.Bytes())
+		files = func(name string) string {
+			data, _ := os.ReadFile(filepath.Join(path, name))
+			return string(data)
+		}
+	} else {
+		archive, err := charm.ReadCharmArchive(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if content, err = newFileBlob(path); err != nil {
+			return errors.Trace(err)
+		}
+		ch = archive
+		files = func(name string) string {
+			return readZipFile(path, name)
+		}
+	}
+
+	meta := ch.Meta()
+	rev := &revision{
+		revision:     ch.Revision(),
+		created:      info.ModTime().UTC(),
+		metadataYAML: files("metadata.yaml"),
+		configYAML:   files("config.yaml"),
+		blob:         content,
+	}
+	if versioned, ok := ch.(interface{ Version() string }); ok {
+		rev.version = versioned.Version()
+	}
+	if manifest := ch.Manifest(); manifest != nil {
+		for _, base := range manifest.Bases {
+			for _, arch := range base.Architectures {
+				rev.bases = append(rev.bases, transport.Base{
+					Architecture: arch,
+					Name:         base.Name,
+					Channel:      base.Channel.Track,
+				})
+			}
+		}
+	}
+
+	resourceDir := strings.TrimSuffix(path, ".charm") + ".resources"
+	names := make([]string, 0, len(meta.Resources))
+	for name := range meta.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resMeta := meta.Resources[name]
+		resBlob, err := newFileBlob(filepath.Join(resourceDir, name))
+		if os.IsNotExist(errors.Cause(err)) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		rev.resources = append(rev.resources, resource{
+			name:        name,
+			typ:         resMeta.Type.String(),
+			filename:    resMeta.Path,
+			description: resMeta.Description,
+			revision:    1,
+			blob:        resBlob,
+		})
+	}
+
+	ent := s.entity(meta.Name, transport.CharmType)
+	ent.summary, ent.desc = meta.Summary, meta.Description
+	channel, _ := parseChannel(DefaultChannel)
+	s.addRevision(ent, rev)
+	for _, base := range rev.bases {
+		s.release(ent, channel, base, rev.revision, rev.created)
+	}
+	return nil
+}
+
+// AddMirror adds every entry of a charm mirror manifest to the store.
+// Blobs are read from the mirror directory.
+func (s *Store) AddMirror(dir string, manifest mirror.Manifest) error {
+	for _, entry := range manifest.Entries {
+		src := entry.Entity
+		ent := s.entity(src.Name, src.Type)
+		ent.summary = src.Summary
+
+		content, err := newFileBlob(mirror.BlobPath(dir, src.Download.HashSHA384))
+		if err != nil {
+			return errors.Annotatef(err, "reading %q", src.Name)
+		}
+		rev := &revision{
+			revision:     src.Revision,
+			version:      src.Version,
+			created:      src.CreatedAt,
+			bases:        src.Bases,
+			metadataYAML: src.MetadataYAML,
+			configYAML:   src.ConfigYAML,
+			blob:         content,
+		}
+		if len(rev.bases) == 0 && entry.Base.Name != "" {
+			rev.bases = []transport.Base{entry.Base}
+		}
+		if src.Type == transport.BundleType {
+			rev.bundleYAML = readZipFile(content.path, "bundle.yaml")
+		}
+		for _, res := range src.Resources {
+			resBlob, err := newFileBlob(mirror.BlobPath(dir, res.Download.HashSHA384))
+			if err != nil {
+				return errors.Annotatef(err, "reading resource %q of %q", res.Name, src.Name)
+			}
+			rev.resources = append(rev.resources, resource{
+				name:        res.Name,
+				typ:         res.Type,
+				filename:    res.Filename,
+				description: res.Description,
+				revision:    res.Revision,
+				blob:        resBlob,
+			})
+		}
+		s.addRevision(ent, rev)
+
+		if entry.Channel == "" {
+			continue
+		}
+		channel, err := parseChannel(entry.Channel)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.release(ent, channel, entry.Base, rev.revision, manifest.Created)
+	}
+	return nil
+}
+
+// parseChannel parses and normalises a channel. As in Charmhub, a channel
+// without a track is on the "latest" track.
+func parseChannel(s string) (charm.Channel, error) {
+	channel, err := charm.ParseChannelNormalize(s)
+	if err != nil {
+		return charm.Channel{}, errors.Trace(err)
+	}
+	if channel.Track == "" {
+		channel.Track = "latest"
+	}
+	return channel, nil
+}
+
+func (s *Store) entity(name string, typ transport.Type) *entity {
+	if ent, ok := s.entities[name]; ok {
+		return ent
+	}
+	sum := sha256.Sum256([]byte(name))
+	ent := &entity{
+		id:        hex.EncodeToString(sum[:])[:32],
+		name:      name,
+		typ:       typ,
+		revisions: make(map[int]*revision),
+	}
+	s.entities[name] = ent
+	return ent
+}
+
+func (s *Store) addRevision(ent *entity, rev *revision) {
+	ent.revisions[rev.revision] = rev
+	s.blobs[rev.blob.sha384] = rev.blob
+	for _, res := range rev.resources {
+		s.blobs[res.blob.sha384] = res.blob
+	}
+}
+
+// release places the revision in the channel for the base, replacing any
+// revision already released there.
+func (s *Store) release(ent *entity, channel charm.Channel, base transport.Base, rev int, released time.Time) {
+	for i, rel := range ent.releases {
+		if rel.channel == channel && rel.base == base {
+			ent.releases[i].revision = rev
+			ent.releases[i].released = released
+			return
+		}
+	}
+	ent.releases = append(ent.releases, release{
+		channel:  channel,
+		base:     base,
+		revision: rev,
+		released: released,
+	})
+}
+
+// lookup returns the entity with the given name or id.
+func (s *Store) lookup(nameOrID string) (*entity, bool) {
+	if ent, ok := s.entities[nameOrID]; ok {
+		return ent, true
+	}
+	for _, ent := range s.entities {
+		if ent.id == nameOrID {
+			return ent, true
+		}
+	}
+	return nil, false
+}
+
+func newFileBlob(path string) (blob, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return blob{}, errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	sha256Hash, sha384Hash := sha256.New(), sha512.New384()
+	size, err := io.Copy(io.MultiWriter(sha256Hash, sha384Hash), f)
+	if err != nil {
+		return blob{}, errors.Trace(err)
+	}
+	return blob{
+		path:   path,
+		sha256: hex.EncodeToString(sha256Hash.Sum(nil)),
+		sha384: hex.EncodeToString(sha384Hash.Sum(nil)),
+		size:   size,
+	}, nil
+}
+
+func newMemoryBlob(data []byte) blob {
+	sha256Sum, sha384Sum := sha256.Sum256(data), sha512.Sum384(data)
+	return blob{
+		data:   data,
+		sha256: hex.EncodeToString(sha256Sum[:]),
+		sha384: hex.EncodeToString(sha384Sum[:]),
+		size:   int64(len(data)),
+	}
+}
+
+// readZipFile returns the content of the named file in the zip archive at
+// path, or an empty string if it can't be read.
+func readZipFile(path, name string) string {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = r.Close() }()
+
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return ""
+		}
+		defer func() { _ = rc.Close() }()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	return ""
+}
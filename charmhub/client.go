@@ -77,6 +77,10 @@ type Config struct {
 	// FileSystem represents the file system operations for downloading.
 	// If nil, use the real OS file system.
 	FileSystem FileSystem
+
+	// Cache holds downloaded archives and info/find responses. The same
+	// cache can be shared between clients. If nil, nothing is cached.
+	Cache *Cache
 }
 
 // basePath returns the base configuration path for speaking to the server API.
@@ -151,7 +155,12 @@ func NewClient(config Config) (*Client, error) {
 
 	logger.Tracef("NewClient to %q", url)
 
-	apiRequester := newAPIRequester(httpClient, logger)
+	restHTTPClient := httpClient
+	if config.Cache != nil {
+		restHTTPClient = newCachingHTTPClient(httpClient, config.Cache, logger)
+	}
+
+	apiRequester := newAPIRequester(restHTTPClient, logger)
 	apiRequestLogger := newAPIRequesterLogger(apiRequester, logger)
 	restClient := newHTTPRESTClient(apiRequestLogger)
 
@@ -163,7 +172,7 @@ func NewClient(config Config) (*Client, error) {
 		// download client doesn't require a path here, as the download could
 		// be from any server in theory. That information is found from the
 		// refresh response.
-		downloadClient:  newDownloadClient(httpClient, fs, logger),
+		downloadClient:  newDownloadClient(httpClient, fs, config.Cache, logger),
 		resourcesClient: newResourcesClient(resourcesPath, restClient, logger),
 		logger:          logger,
 	}, nil
@@ -34,6 +34,7 @@ type DownloadOption func(*downloadOptions)
 
 type downloadOptions struct {
 	progressBar ProgressBar
+	sha384      string
 }
 
 // WithProgressBar sets the channel on the option.
@@ -43,6 +44,15 @@ func WithProgressBar(pb ProgressBar) DownloadOption {
 	}
 }
 
+// WithSHA384 sets the expected SHA-384 hash of the download. If the client
+// has a cache, the hash is used to serve the archive from the cache and to
+// store it there once downloaded and verified.
+func WithSHA384(hash string) DownloadOption {
+	return func(options *downloadOptions) {
+		options.sha384 = hash
+	}
+}
+
 // Create a downloadOptions instance with default values.
 func newDownloadOptions() *downloadOptions {
 	return &downloadOptions{}
@@ -52,14 +62,17 @@ func newDownloadOptions() *downloadOptions {
 type downloadClient struct {
 	httpClient HTTPClient
 	fileSystem FileSystem
+	cache      *Cache
 	logger     Logger
 }
 
-// newDownloadClient creates a downloadClient for requesting
-func newDownloadClient(httpClient HTTPClient, fileSystem FileSystem, logger Logger) *downloadClient {
+// newDownloadClient creates a downloadClient for requesting. The cache may be
+// nil, in which case every download is fetched from the server.
+func newDownloadClient(httpClient HTTPClient, fileSystem FileSystem, cache *Cache, logger Logger) *downloadClient {
 	return &downloadClient{
 		httpClient: httpClient,
 		fileSystem: fileSystem,
+		cache:      cache,
 		logger:     logger,
 	}
 }
@@ -71,6 +84,10 @@ const (
 	// DownloadNameKey defines a name of a download, so the progress bar can
 	// show it.
 	DownloadNameKey downloadKey = "download-name-key"
+
+	// DownloadSHA384Key defines the expected SHA-384 hash of a resource
+	// download, so that it can be served from and stored in the cache.
+	DownloadSHA384Key downloadKey = "download-sha384-key"
 )
 
 // ProgressBar defines a progress bar type for giving feedback to the user about
@@ -106,15 +123,19 @@ func (c *downloadClient) Download(ctx context.Context, resourceURL *url.URL, arc
 		_ = f.Close()
 	}()
 
-	r, err := c.downloadFromURL(ctx, resourceURL)
+	body, size, cacheWriter, err := c.open(ctx, resourceURL, opts.sha384)
 	if err != nil {
 		return errors.Annotatef(err, "cannot retrieve %q", resourceURL)
 	}
 	defer func() {
-		_ = r.Body.Close()
+		_ = body.Close()
 	}()
 
 	var writer io.Writer = f
+	if cacheWriter != nil {
+		defer cacheWriter.Abort()
+		writer = io.MultiWriter(f, cacheWriter)
+	}
 	if opts.progressBar != nil {
 		// Progress bar has this nifty feature where you can supply a name. In
 		// this case we can supply one to help with UI feedback.
@@ -128,20 +149,57 @@ func (c *downloadClient) Download(ctx context.Context, resourceURL *url.URL, arc
 		// TODO (stickupkid): Would be good to verify the size, but
 		// unfortunately we don't have the information to hand. That information
 		// is further up the stack.
-		downloadSize := float64(r.ContentLength)
+		downloadSize := float64(size)
 		opts.progressBar.Start(name, downloadSize)
 		defer opts.progressBar.Finished()
 
-		writer = io.MultiWriter(f, opts.progressBar)
+		writer = io.MultiWriter(writer, opts.progressBar)
 	}
 
-	if _, err := io.Copy(writer, r.Body); err != nil {
+	if _, err := io.Copy(writer, body); err != nil {
 		return errors.Trace(err)
 	}
 
+	if cacheWriter != nil {
+		// The caller told us what to expect, so a mismatch means the
+		// archive is corrupt.
+		if err := cacheWriter.Commit(); err != nil {
+			return errors.Annotatef(err, "cannot retrieve %q", resourceURL)
+		}
+	}
 	return nil
 }
 
+// open returns a reader for the download, either from the cache or from
+// the server. If the download is to be cached, a CacheWriter is returned
+// which the content should be copied to.
+func (c *downloadClient) open(ctx context.Context, resourceURL *url.URL, sha384 string) (io.ReadCloser, int64, *CacheWriter, error) {
+	if c.cache != nil && sha384 != "" {
+		r, size, err := c.cache.Open(sha384)
+		if err == nil {
+			c.logger.Tracef("download of %s served from cache", resourceURL.String())
+			return r, size, nil, nil
+		} else if !errors.Is(err, errors.NotFound) {
+			return nil, -1, nil, errors.Trace(err)
+		}
+	}
+
+	resp, err := c.downloadFromURL(ctx, resourceURL)
+	if err != nil {
+		return nil, -1, nil, errors.Trace(err)
+	}
+	if c.cache == nil || sha384 == "" {
+		return resp.Body, resp.ContentLength, nil, nil
+	}
+
+	cacheWriter, err := c.cache.Writer(sha384)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, -1, nil, errors.Trace(err)
+	}
+	return resp.Body, resp.ContentLength, cacheWriter, nil
+}
+
 // DownloadAndRead returns a charm archive retrieved from the given URL.
 func (c *downloadClient) DownloadAndRead(ctx context.Context, resourceURL *url.URL, archivePath string, options ...DownloadOption) (*charm.CharmArchive, error) {
 	err := c.Download(ctx, resourceURL, archivePath, options...)
@@ -163,12 +221,62 @@ func (c *downloadClient) DownloadAndReadBundle(ctx context.Context, resourceURL
 }
 
 // DownloadResource returns an io.ReadCloser to read the Resource from.
+// If the context carries a DownloadSHA384Key and the client has a cache,
+// the resource is served from the cache when possible, and otherwise added
+// to the cache once it has been read in full and verified.
 func (c *downloadClient) DownloadResource(ctx context.Context, resourceURL *url.URL) (r io.ReadCloser, err error) {
-	resp, err := c.downloadFromURL(ctx, resourceURL)
+	var sha384 string
+	if v, ok := ctx.Value(DownloadSHA384Key).(string); ok {
+		sha384 = v
+	}
+	body, _, cacheWriter, err := c.open(ctx, resourceURL, sha384)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return resp.Body, nil
+	if cacheWriter == nil {
+		return body, nil
+	}
+	return &cachingReadCloser{
+		ReadCloser: body,
+		writer:     cacheWriter,
+		logger:     c.logger,
+	}, nil
+}
+
+// cachingReadCloser copies everything read into the cache, committing the
+// cache entry only if the reader reached the end of the content.
+type cachingReadCloser struct {
+	io.ReadCloser
+	writer *CacheWriter
+	logger Logger
+	eof    bool
+	failed bool
+}
+
+// Read is part of the io.Reader interface.
+func (r *cachingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 && !r.failed {
+		if _, werr := r.writer.Write(p[:n]); werr != nil {
+			r.failed = true
+		}
+	}
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// Close is part of the io.Closer interface.
+func (r *cachingReadCloser) Close() error {
+	if r.eof && !r.failed {
+		if err := r.writer.Commit(); err != nil {
+			r.logger.Errorf("unable to cache resource: %v", err)
+		}
+	} else {
+		r.writer.Abort()
+	}
+	return r.ReadCloser.Close()
 }
 
 func (c *downloadClient) downloadFromURL(ctx context.Context, resourceURL *url.URL) (resp *http.Response, err error) {
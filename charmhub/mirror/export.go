@@ -0,0 +1,416 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/transport"
+	corebase "github.com/juju/juju/core/base"
+)
+
+// Client is the subset of the charmhub client used to export charms.
+type Client interface {
+	URL() string
+	Refresh(context.Context, charmhub.RefreshConfig) ([]transport.RefreshResponse, error)
+	Download(ctx context.Context, resourceURL *url.URL, archivePath string, options ...charmhub.DownloadOption) error
+}
+
+// Logger is the interface used by the exporter to report progress.
+type Logger interface {
+	Infof(string, ...interface{})
+	Debugf(string, ...interface{})
+}
+
+// Request identifies a charm or bundle to export.
+type Request struct {
+	// Name is the name of the charm or bundle in Charmhub.
+	Name string
+
+	// Channel is the channel to resolve the charm from. It is ignored if
+	// Revision is set.
+	Channel string
+
+	// Revision is the revision to export, or -1 to use the channel.
+	Revision int
+
+	// Base is the base to resolve the charm for.
+	Base charmhub.RefreshBase
+}
+
+// ExporterConfig holds the dependencies of an Exporter.
+type ExporterConfig struct {
+	Client Client
+	Logger Logger
+	Clock  clock.Clock
+}
+
+// Validate ensures that the config is correct.
+func (c ExporterConfig) Validate() error {
+	if c.Client == nil {
+		return errors.NotValidf("nil client")
+	}
+	if c.Logger == nil {
+		return errors.NotValidf("nil logger")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil clock")
+	}
+	return nil
+}
+
+// Exporter resolves charms and bundles through Charmhub and writes them,
+// together with their resources, into a signed mirror archive.
+type Exporter struct {
+	client Client
+	logger Logger
+	clock  clock.Clock
+}
+
+// NewExporter creates a new Exporter.
+func NewExporter(config ExporterConfig) (*Exporter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Exporter{
+		client: config.Client,
+		logger: config.Logger,
+		clock:  config.Clock,
+	}, nil
+}
+
+// Export resolves the requests, downloads every charm, bundle and resource
+// and writes a mirror archive signed by the signer to w. Charms referenced
+// by exported bundles are exported too.
+func (e *Exporter) Export(ctx context.Context, requests []Request, signer ssh.Signer, w io.Writer) (Manifest, error) {
+	if signer == nil {
+		return Manifest{}, errors.NotValidf("nil signer")
+	}
+
+	workDir, err := os.MkdirTemp("", "juju-charm-mirror")
+	if err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	manifest := Manifest{
+		Version: ManifestVersion,
+		Created: e.clock.Now().UTC(),
+		Source:  e.client.URL(),
+		Signer:  ssh.FingerprintSHA256(signer.PublicKey()),
+	}
+
+	seen := make(map[string]bool)
+	for len(requests) > 0 {
+		req := requests[0]
+		requests = requests[1:]
+
+		entry, err := e.resolve(ctx, req)
+		if err != nil {
+			return Manifest{}, errors.Annotatef(err, "resolving %q", req.Name)
+		}
+		if seen[entry.Key()] {
+			continue
+		}
+		seen[entry.Key()] = true
+
+		e.logger.Infof("Exporting %s %q revision %d", entry.Entity.Type, entry.Entity.Name, entry.Entity.Revision)
+		blobPath, err := e.download(ctx, workDir, entry.Entity.Name, entry.Entity.Download)
+		if err != nil {
+			return Manifest{}, errors.Trace(err)
+		}
+		for _, res := range entry.Entity.Resources {
+			e.logger.Debugf("Exporting resource %q revision %d of %q", res.Name, res.Revision, entry.Entity.Name)
+			if _, err := e.download(ctx, workDir, res.Name, res.Download); err != nil {
+				return Manifest{}, errors.Trace(err)
+			}
+		}
+
+		if entry.Entity.Type == transport.BundleType {
+			bundleRequests, err := bundleCharms(blobPath, req)
+			if err != nil {
+				return Manifest{}, errors.Annotatef(err, "reading bundle %q", entry.Entity.Name)
+			}
+			requests = append(requests, bundleRequests...)
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	if err := writeArchive(w, workDir, manifest, signer); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	return manifest, nil
+}
+
+// resolve calls the Charmhub refresh API to locate a single request.
+func (e *Exporter) resolve(ctx context.Context, req Request) (Entry, error) {
+	var (
+		cfg charmhub.RefreshConfig
+		err error
+	)
+	if req.Revision >= 0 {
+		cfg, err = charmhub.InstallOneFromRevision(req.Name, req.Revision)
+	} else {
+		cfg, err = charmhub.InstallOneFromChannel(req.Name, req.Channel, req.Base)
+	}
+	if err != nil {
+		return Entry{}, errors.Trace(err)
+	}
+
+	results, err := e.client.Refresh(ctx, cfg)
+	if err != nil {
+		return Entry{}, errors.Trace(err)
+	}
+	if len(results) == 0 {
+		return Entry{}, errors.NotFoundf(req.Name)
+	}
+	result := results[0]
+	if result.Error != nil {
+		return Entry{}, errors.Errorf("%s: %s", result.Error.Code, result.Error.Message)
+	}
+
+	entry := Entry{
+		Base: transport.Base{
+			Architecture: req.Base.Architecture,
+			Name:         req.Base.Name,
+			Channel:      req.Base.Channel,
+		},
+		Entity: result.Entity,
+	}
+	if req.Revision < 0 {
+		entry.Channel = result.EffectiveChannel
+		if entry.Channel == "" {
+			entry.Channel = req.Channel
+		}
+	}
+	return entry, nil
+}
+
+// download fetches a single blob into the work dir, named by its SHA-384
+// hash, and verifies it against both hashes reported by Charmhub.
+func (e *Exporter) download(ctx context.Context, workDir, name string, download transport.Download) (string, error) {
+	if !isSHA384(download.HashSHA384) {
+		return "", errors.NotValidf("%q sha384 hash %q", name, download.HashSHA384)
+	}
+	blobPath := filepath.Join(workDir, download.HashSHA384)
+	if _, err := os.Stat(blobPath); err == nil {
+		return blobPath, nil
+	}
+
+	downloadURL, err := url.Parse(download.URL)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ctx = context.WithValue(ctx, charmhub.DownloadNameKey, name)
+	if err := e.client.Download(ctx, downloadURL, blobPath, charmhub.WithSHA384(download.HashSHA384)); err != nil {
+		return "", errors.Annotatef(err, "downloading %q", name)
+	}
+
+	sha256Hash, sha384Hash, err := hashFile(blobPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if sha384Hash != download.HashSHA384 || (download.HashSHA256 != "" && sha256Hash != download.HashSHA256) {
+		return "", errors.Errorf("checksum of download failed for %q", name)
+	}
+	return blobPath, nil
+}
+
+// bundleCharms returns the requests for the Charmhub charms referenced by
+// the bundle archive at path.
+func bundleCharms(path string, parent Request) ([]Request, error) {
+	bundle, err := charm.ReadBundleArchive(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data := bundle.Data()
+
+	defaultBase := parent.Base
+	if data.DefaultBase != "" {
+		if defaultBase, err = refreshBase(data.DefaultBase, parent.Base.Architecture); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	names := make([]string, 0, len(data.Applications))
+	for name := range data.Applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var requests []Request
+	for _, name := range names {
+		app := data.Applications[name]
+		if app == nil || app.Charm == "" || strings.HasPrefix(app.Charm, ".") || strings.HasPrefix(app.Charm, "/") {
+			// Local charms can't be mirrored.
+			continue
+		}
+		curl, err := charm.ParseURL(app.Charm)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !charm.CharmHub.Matches(curl.Schema) {
+			continue
+		}
+
+		req := Request{
+			Name:     curl.Name,
+			Channel:  app.Channel,
+			Revision: -1,
+			Base:     defaultBase,
+		}
+		if req.Channel == "" {
+			req.Channel = parent.Channel
+		}
+		if app.Revision != nil {
+			req.Revision = *app.Revision
+		}
+		if app.Base != "" {
+			if req.Base, err = refreshBase(app.Base, parent.Base.Architecture); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+func refreshBase(s, arch string) (charmhub.RefreshBase, error) {
+	base, err := corebase.ParseBaseFromString(s)
+	if err != nil {
+		return charmhub.RefreshBase{}, errors.Trace(err)
+	}
+	return charmhub.RefreshBase{
+		Name:         base.OS,
+		Channel:      base.Channel.Track,
+		Architecture: arch,
+	}, nil
+}
+
+// writeArchive writes the signed manifest followed by every blob in the
+// work dir.
+func writeArchive(w io.Writer, workDir string, manifest Manifest, signer ssh.Signer) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	signature, err := signManifest(signer, data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	modTime := manifest.Created
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{name: ManifestFile, data: data},
+		{name: SignatureFile, data: signature},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0644,
+			Size:    int64(len(file.data)),
+			ModTime: modTime,
+		}); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	blobs := manifest.Blobs()
+	hashes := make([]string, 0, len(blobs))
+	for sha384 := range blobs {
+		hashes = append(hashes, sha384)
+	}
+	sort.Strings(hashes)
+	for _, sha384 := range hashes {
+		if err := writeBlob(tw, filepath.Join(workDir, sha384), path.Join(BlobDir, sha384), blobs[sha384], modTime); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(gzw.Close())
+}
+
+func writeBlob(tw *tar.Writer, source, name string, size int64, modTime time.Time) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if size > 0 && info.Size() != size {
+		return errors.Errorf("blob %q has size %d, expected %d", path.Base(name), info.Size(), size)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: modTime,
+	}); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = io.Copy(tw, f)
+	return errors.Trace(err)
+}
+
+func hashFile(path string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	sha256Hash, sha384Hash := sha256.New(), sha512.New384()
+	if _, err := io.Copy(io.MultiWriter(sha256Hash, sha384Hash), f); err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return hexSum(sha256Hash), hexSum(sha384Hash), nil
+}
+
+func hexSum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isSHA384(s string) bool {
+	if len(s) != sha512.Size384*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
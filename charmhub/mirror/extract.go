@@ -0,0 +1,148 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// Extract verifies the mirror archive read from r against the trusted key
+// and unpacks it into the mirror directory. Entries already in the
+// directory are kept, so successive archives can be imported into the same
+// directory. Nothing is written to the directory until the manifest
+// signature has been verified, and every blob is checked against its hash
+// before it is made visible.
+func Extract(r io.Reader, dir string, key ssh.PublicKey) (Manifest, error) {
+	if key == nil {
+		return Manifest{}, errors.NotValidf("nil trusted key")
+	}
+
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, errors.Annotate(err, "reading mirror archive")
+	}
+	defer func() { _ = gzr.Close() }()
+	tr := tar.NewReader(gzr)
+
+	data, err := readFile(tr, ManifestFile)
+	if err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	signature, err := readFile(tr, SignatureFile)
+	if err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	if err := verifyManifest(key, data, signature); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, errors.Annotate(err, "parsing mirror manifest")
+	}
+	if err := manifest.Validate(); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, BlobDir), 0755); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+
+	pending := manifest.Blobs()
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Manifest{}, errors.Annotate(err, "reading mirror archive")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		blobDir, sha384 := path.Split(hdr.Name)
+		if path.Clean(blobDir) != BlobDir {
+			return Manifest{}, errors.NotValidf("mirror archive entry %q", hdr.Name)
+		}
+		if _, ok := pending[sha384]; !ok {
+			return Manifest{}, errors.NotValidf("blob %q not in manifest", sha384)
+		}
+		if err := extractBlob(tr, dir, sha384); err != nil {
+			return Manifest{}, errors.Trace(err)
+		}
+		delete(pending, sha384)
+	}
+	for sha384 := range pending {
+		return Manifest{}, errors.NotFoundf("blob %q in mirror archive", sha384)
+	}
+
+	existing, err := ReadManifest(dir)
+	if err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	existing.Merge(manifest)
+	if err := writeManifest(dir, existing); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	return manifest, nil
+}
+
+func readFile(tr *tar.Reader, name string) ([]byte, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %q from mirror archive", name)
+	}
+	if hdr.Name != name {
+		return nil, errors.NotValidf("mirror archive entry %q, expected %q", hdr.Name, name)
+	}
+	data, err := io.ReadAll(tr)
+	return data, errors.Trace(err)
+}
+
+func extractBlob(r io.Reader, dir, sha384 string) error {
+	target := BlobPath(dir, sha384)
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".partial-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha512.New384()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), r); err != nil {
+		return errors.Trace(err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != sha384 {
+		return errors.NotValidf("blob %q with hash %q", sha384, actual)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp.Name(), target))
+}
+
+func writeManifest(dir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp, filepath.Join(dir, ManifestFile)))
+}
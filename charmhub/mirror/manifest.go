@@ -0,0 +1,189 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package mirror reads and writes offline charm mirror archives.
+//
+// A mirror archive is a gzipped tarball holding a signed manifest followed by
+// the charm, bundle and resource blobs it refers to. Every blob is named by
+// its SHA-384 hash, so the manifest signature covers the whole archive.
+// Archives are created with an Exporter, which resolves charms through the
+// Charmhub refresh API, and unpacked into a mirror directory with Extract.
+// NewHandler serves a mirror directory to controllers as a Charmhub
+// endpoint.
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/charmhub/transport"
+)
+
+const (
+	// ManifestVersion is the version of the manifest format written by
+	// this package.
+	ManifestVersion = 1
+
+	// ManifestFile is the name of the manifest in an archive or a mirror
+	// directory.
+	ManifestFile = "manifest.json"
+
+	// SignatureFile is the name of the detached manifest signature in an
+	// archive.
+	SignatureFile = "manifest.json.sig"
+
+	// BlobDir is the directory holding the content-addressed blobs in an
+	// archive or a mirror directory.
+	BlobDir = "blobs"
+)
+
+// Manifest describes the contents of a mirror archive or directory.
+type Manifest struct {
+	// Version is the manifest format version.
+	Version int `json:"version"`
+
+	// Created is the time the archive was exported.
+	Created time.Time `json:"created"`
+
+	// Source is the Charmhub URL the charms were resolved against.
+	Source string `json:"source"`
+
+	// Signer is the fingerprint of the key used to sign the manifest.
+	Signer string `json:"signer,omitempty"`
+
+	// Entries holds one entry for each resolved charm or bundle.
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a charm or bundle revision resolved from a channel.
+type Entry struct {
+	// Channel is the channel the entity was resolved from. It is empty if
+	// the entity was requested by revision.
+	Channel string `json:"channel,omitempty"`
+
+	// Base is the base the entity was resolved for.
+	Base transport.Base `json:"base"`
+
+	// Entity is the refresh entity as returned by Charmhub. The download
+	// and resource hashes identify the blobs in the archive.
+	Entity transport.RefreshEntity `json:"entity"`
+}
+
+// Key returns a key that uniquely identifies the entry within a manifest.
+func (e Entry) Key() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%d",
+		e.Entity.Name, e.Channel, e.Base.Architecture, e.Base.Name, e.Base.Channel, e.Entity.Revision)
+}
+
+// Blobs returns the SHA-384 hashes of all blobs referenced by the manifest.
+func (m Manifest) Blobs() map[string]int64 {
+	blobs := make(map[string]int64)
+	for _, entry := range m.Entries {
+		blobs[entry.Entity.Download.HashSHA384] = int64(entry.Entity.Download.Size)
+		for _, res := range entry.Entity.Resources {
+			blobs[res.Download.HashSHA384] = int64(res.Download.Size)
+		}
+	}
+	return blobs
+}
+
+// Merge adds the entries of other to the manifest, replacing any entries
+// with the same key.
+func (m *Manifest) Merge(other Manifest) {
+	entries := make(map[string]Entry)
+	for _, entry := range m.Entries {
+		entries[entry.Key()] = entry
+	}
+	for _, entry := range other.Entries {
+		entries[entry.Key()] = entry
+	}
+
+	m.Entries = make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		m.Entries = append(m.Entries, entry)
+	}
+	sort.Slice(m.Entries, func(i, j int) bool {
+		return m.Entries[i].Key() < m.Entries[j].Key()
+	})
+	if other.Created.After(m.Created) {
+		m.Created = other.Created
+		m.Source = other.Source
+		m.Signer = other.Signer
+	}
+	m.Version = ManifestVersion
+}
+
+// Validate ensures that the manifest can be read by this package.
+func (m Manifest) Validate() error {
+	if m.Version != ManifestVersion {
+		return errors.NotSupportedf("manifest version %d", m.Version)
+	}
+	for _, entry := range m.Entries {
+		if entry.Entity.Name == "" {
+			return errors.NotValidf("entry with empty name")
+		}
+		if !isSHA384(entry.Entity.Download.HashSHA384) {
+			return errors.NotValidf("%q hash %q", entry.Entity.Name, entry.Entity.Download.HashSHA384)
+		}
+		for _, res := range entry.Entity.Resources {
+			if !isSHA384(res.Download.HashSHA384) {
+				return errors.NotValidf("%q resource %q hash %q", entry.Entity.Name, res.Name, res.Download.HashSHA384)
+			}
+		}
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest of a mirror directory. An empty manifest
+// is returned if the directory has no manifest yet.
+func ReadManifest(dir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return Manifest{Version: ManifestVersion}, nil
+	} else if err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, errors.Annotate(err, "parsing mirror manifest")
+	}
+	if err := manifest.Validate(); err != nil {
+		return Manifest{}, errors.Trace(err)
+	}
+	return manifest, nil
+}
+
+// BlobPath returns the path of the blob with the given SHA-384 hash within
+// a mirror directory.
+func BlobPath(dir, sha384 string) string {
+	return filepath.Join(dir, BlobDir, sha384)
+}
+
+// signManifest returns the wire format of a signature over the manifest.
+func signManifest(signer ssh.Signer, data []byte) ([]byte, error) {
+	sig, err := signer.Sign(nil, data)
+	if err != nil {
+		return nil, errors.Annotate(err, "signing manifest")
+	}
+	return ssh.Marshal(sig), nil
+}
+
+// verifyManifest checks the signature over the manifest against the
+// trusted key.
+func verifyManifest(key ssh.PublicKey, data, signature []byte) error {
+	var sig ssh.Signature
+	if err := ssh.Unmarshal(signature, &sig); err != nil {
+		return errors.Annotate(err, "parsing manifest signature")
+	}
+	if err := key.Verify(data, &sig); err != nil {
+		return errors.Unauthorizedf("manifest signature not valid for key %s", ssh.FingerprintSHA256(key))
+	}
+	return nil
+}
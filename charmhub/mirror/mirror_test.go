@@ -0,0 +1,305 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+type MirrorSuite struct {
+	testing.IsolationSuite
+
+	client *fakeClient
+	signer ssh.Signer
+}
+
+var _ = gc.Suite(&MirrorSuite{})
+
+func (s *MirrorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.client = &fakeClient{
+		entities: make(map[string]transport.RefreshEntity),
+		blobs:    make(map[string][]byte),
+	}
+	s.signer = newSigner(c)
+}
+
+func (s *MirrorSuite) TestExportAndExtract(c *gc.C) {
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), map[string][]byte{
+		"snap": []byte("postgresql snap"),
+	})
+
+	var buf bytes.Buffer
+	manifest, err := s.newExporter(c).Export(context.Background(), []Request{{
+		Name:     "postgresql",
+		Channel:  "14/stable",
+		Revision: -1,
+		Base:     charmhub.RefreshBase{Name: "ubuntu", Channel: "22.04", Architecture: "amd64"},
+	}}, s.signer, &buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(manifest.Entries, gc.HasLen, 1)
+	c.Check(manifest.Entries[0].Channel, gc.Equals, "14/stable")
+	c.Check(manifest.Entries[0].Entity.Revision, gc.Equals, 42)
+	c.Check(manifest.Signer, gc.Equals, ssh.FingerprintSHA256(s.signer.PublicKey()))
+
+	dir := c.MkDir()
+	extracted, err := Extract(bytes.NewReader(buf.Bytes()), dir, s.signer.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(extracted.Entries, gc.HasLen, 1)
+
+	onDisk, err := ReadManifest(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(onDisk.Entries, jc.DeepEquals, extracted.Entries)
+
+	entity := onDisk.Entries[0].Entity
+	data, err := os.ReadFile(BlobPath(dir, entity.Download.HashSHA384))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "postgresql charm")
+
+	data, err = os.ReadFile(BlobPath(dir, entity.Resources[0].Download.HashSHA384))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "postgresql snap")
+}
+
+func (s *MirrorSuite) TestExportBundle(c *gc.C) {
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), nil)
+	s.client.addCharm("wordpress", 7, []byte("wordpress charm"), nil)
+	s.client.addBundle("blog", 3, s.bundleArchive(c, `
+applications:
+  db:
+    charm: postgresql
+    channel: 14/stable
+  wp:
+    charm: wordpress
+  local:
+    charm: ./local
+`))
+
+	var buf bytes.Buffer
+	manifest, err := s.newExporter(c).Export(context.Background(), []Request{{
+		Name:     "blog",
+		Channel:  "stable",
+		Revision: -1,
+		Base:     charmhub.RefreshBase{Name: "ubuntu", Channel: "22.04", Architecture: "amd64"},
+	}}, s.signer, &buf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var names []string
+	for _, entry := range manifest.Entries {
+		names = append(names, fmt.Sprintf("%s@%s", entry.Entity.Name, entry.Channel))
+	}
+	c.Check(names, jc.DeepEquals, []string{"blog@stable", "postgresql@14/stable", "wordpress@stable"})
+}
+
+func (s *MirrorSuite) TestExportChecksumMismatch(c *gc.C) {
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), nil)
+	s.client.blobs["postgresql"] = []byte("tampered")
+
+	var buf bytes.Buffer
+	_, err := s.newExporter(c).Export(context.Background(), []Request{{
+		Name:     "postgresql",
+		Revision: 42,
+	}}, s.signer, &buf)
+	c.Assert(err, gc.ErrorMatches, `checksum of download failed for "postgresql"`)
+}
+
+func (s *MirrorSuite) TestExtractUntrustedKey(c *gc.C) {
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), nil)
+
+	var buf bytes.Buffer
+	_, err := s.newExporter(c).Export(context.Background(), []Request{{
+		Name:     "postgresql",
+		Revision: 42,
+	}}, s.signer, &buf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir := c.MkDir()
+	_, err = Extract(bytes.NewReader(buf.Bytes()), dir, newSigner(c).PublicKey())
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	_, err = os.Stat(filepath.Join(dir, ManifestFile))
+	c.Assert(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *MirrorSuite) TestExtractMerges(c *gc.C) {
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), nil)
+	s.client.addCharm("wordpress", 7, []byte("wordpress charm"), nil)
+
+	dir := c.MkDir()
+	for _, name := range []string{"postgresql", "wordpress"} {
+		var buf bytes.Buffer
+		_, err := s.newExporter(c).Export(context.Background(), []Request{{
+			Name:     name,
+			Revision: -1,
+			Channel:  "stable",
+			Base:     charmhub.RefreshBase{Name: "ubuntu", Channel: "22.04", Architecture: "amd64"},
+		}}, s.signer, &buf)
+		c.Assert(err, jc.ErrorIsNil)
+
+		_, err = Extract(bytes.NewReader(buf.Bytes()), dir, s.signer.PublicKey())
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	manifest, err := ReadManifest(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(manifest.Entries, gc.HasLen, 2)
+	c.Check(manifest.Entries[0].Entity.Name, gc.Equals, "postgresql")
+	c.Check(manifest.Entries[1].Entity.Name, gc.Equals, "wordpress")
+}
+
+func (s *MirrorSuite) newExporter(c *gc.C) *Exporter {
+	return newExporter(c, s.client)
+}
+
+func newExporter(c *gc.C, client *fakeClient) *Exporter {
+	exporter, err := NewExporter(ExporterConfig{
+		Client: client,
+		Logger: noopLogger{},
+		Clock:  testclock.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return exporter
+}
+
+func (s *MirrorSuite) bundleArchive(c *gc.C, bundleYAML string) []byte {
+	dir := c.MkDir()
+	err := os.WriteFile(filepath.Join(dir, "bundle.yaml"), []byte(bundleYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(dir, "README.md"), []byte("blog"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bundleDir, err := charm.ReadBundleDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = bundleDir.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func newSigner(c *gc.C) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Debugf(string, ...interface{}) {}
+
+// fakeClient serves refresh and download requests from memory. Download
+// URLs are of the form "https://store/<name>".
+type fakeClient struct {
+	entities map[string]transport.RefreshEntity
+	blobs    map[string][]byte
+}
+
+func (f *fakeClient) URL() string {
+	return "https://api.charmhub.io/v2/charms"
+}
+
+func (f *fakeClient) addCharm(name string, revision int, content []byte, resources map[string][]byte) {
+	entity := transport.RefreshEntity{
+		Type:     transport.CharmType,
+		ID:       name + "-id",
+		Name:     name,
+		Revision: revision,
+		Download: f.addBlob(name, content),
+	}
+	for resName, resContent := range resources {
+		entity.Resources = append(entity.Resources, transport.ResourceRevision{
+			Name:     resName,
+			Type:     "file",
+			Revision: 1,
+			Download: f.addBlob(name+"-"+resName, resContent),
+		})
+	}
+	f.entities[name] = entity
+}
+
+func (f *fakeClient) addBundle(name string, revision int, content []byte) {
+	f.entities[name] = transport.RefreshEntity{
+		Type:     transport.BundleType,
+		ID:       name + "-id",
+		Name:     name,
+		Revision: revision,
+		Download: f.addBlob(name, content),
+	}
+}
+
+func (f *fakeClient) addBlob(name string, content []byte) transport.Download {
+	f.blobs[name] = content
+	sha256Sum := sha256.Sum256(content)
+	sha384Sum := sha512.Sum384(content)
+	return transport.Download{
+		HashSHA256: hex.EncodeToString(sha256Sum[:]),
+		HashSHA384: hex.EncodeToString(sha384Sum[:]),
+		Size:       len(content),
+		URL:        "https://store/" + name,
+	}
+}
+
+func (f *fakeClient) Refresh(_ context.Context, cfg charmhub.RefreshConfig) ([]transport.RefreshResponse, error) {
+	req, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	var results []transport.RefreshResponse
+	for _, action := range req.Actions {
+		name := ""
+		if action.Name != nil {
+			name = *action.Name
+		}
+		entity, ok := f.entities[name]
+		if !ok {
+			results = append(results, transport.RefreshResponse{
+				Error: &transport.APIError{Code: transport.ErrorCodeNotFound, Message: name},
+			})
+			continue
+		}
+		var channel string
+		if action.Channel != nil {
+			channel = *action.Channel
+		}
+		results = append(results, transport.RefreshResponse{
+			Entity:           entity,
+			EffectiveChannel: channel,
+			Name:             name,
+		})
+	}
+	return results, nil
+}
+
+func (f *fakeClient) Download(_ context.Context, resourceURL *url.URL, archivePath string, _ ...charmhub.DownloadOption) error {
+	content, ok := f.blobs[filepath.Base(resourceURL.Path)]
+	if !ok {
+		return errors.NotFoundf("archive")
+	}
+	return os.WriteFile(archivePath, content, 0644)
+}
@@ -0,0 +1,321 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charmhub/transport"
+)
+
+const (
+	// apiPrefix is the path under which Charmhub serves the charm API,
+	// relative to a charmhub-url.
+	apiPrefix = "/v2/charms/"
+
+	// downloadPrefix is the path under which the mirror serves blobs.
+	downloadPrefix = "/download/"
+
+	// notAvailable is sent by the Charmhub client for base fields that
+	// are not known.
+	notAvailable = "NA"
+)
+
+// NewHandler returns an http.Handler that serves a mirror directory over
+// the parts of the Charmhub API used by the controller: the refresh and
+// resource revision endpoints, and downloads of the blobs they refer to.
+// A model whose charmhub-url points at the handler deploys and refreshes
+// charms from the mirror with their Charmhub origin intact.
+//
+// The manifest is read on every request, so archives imported into the
+// directory are served without restarting the handler.
+func NewHandler(dir string) http.Handler {
+	return &handler{dir: dir}
+}
+
+type handler struct {
+	dir string
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == apiPrefix+"refresh" && r.Method == http.MethodPost:
+		h.serveRefresh(w, r)
+	case strings.HasPrefix(r.URL.Path, apiPrefix+"resources/") && r.Method == http.MethodGet:
+		h.serveResourceRevisions(w, r)
+	case strings.HasPrefix(r.URL.Path, downloadPrefix) && r.Method == http.MethodGet:
+		h.serveBlob(w, r)
+	default:
+		writeError(w, http.StatusNotFound, transport.ErrorCodeNotFound, "not served by the charm mirror")
+	}
+}
+
+func (h *handler) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	var req transport.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, transport.ErrorCodeBadArgument, err.Error())
+		return
+	}
+	manifest, err := ReadManifest(h.dir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, transport.ErrorCodeAPIError, err.Error())
+		return
+	}
+
+	contexts := make(map[string]transport.RefreshRequestContext)
+	for _, ctx := range req.Context {
+		contexts[ctx.InstanceKey] = ctx
+	}
+	baseURL := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		baseURL.Scheme = "https"
+	}
+
+	var resp transport.RefreshResponses
+	for _, action := range req.Actions {
+		resp.Results = append(resp.Results, refreshResult(manifest, action, contexts[action.InstanceKey], baseURL))
+	}
+	writeJSON(w, resp)
+}
+
+// refreshResult resolves a single refresh action against the manifest in
+// the same way Charmhub would: by revision if one is given, otherwise by
+// the requested or tracked channel and base.
+func refreshResult(
+	manifest Manifest, action transport.RefreshRequestAction, ctx transport.RefreshRequestContext, baseURL *url.URL,
+) transport.RefreshResponse {
+	result := transport.RefreshResponse{
+		InstanceKey: action.InstanceKey,
+		Result:      action.Action,
+	}
+
+	var (
+		name, id, channel string
+		base              transport.Base
+	)
+	if action.Name != nil {
+		name = *action.Name
+	}
+	if action.ID != nil {
+		id = *action.ID
+	}
+	if action.Channel != nil {
+		channel = *action.Channel
+	}
+	if action.Base != nil {
+		base = *action.Base
+	}
+	if action.Action == "refresh" {
+		id = ctx.ID
+		channel = ctx.TrackingChannel
+		base = ctx.Base
+	}
+
+	var candidates []Entry
+	for _, entry := range manifest.Entries {
+		if (name != "" && entry.Entity.Name == name) || (id != "" && entry.Entity.ID == id) {
+			candidates = append(candidates, entry)
+		}
+	}
+	if len(candidates) == 0 {
+		return errorResult(result, transport.ErrorCodeNotFound, "%q not found in the charm mirror", name+id)
+	}
+
+	entry, ok := Entry{}, false
+	if action.Revision != nil {
+		entry, ok = matchRevision(candidates, *action.Revision)
+	} else {
+		entry, ok = matchChannel(candidates, channel, base)
+	}
+	if !ok {
+		return errorResult(result, transport.ErrorCodeRevisionNotFound,
+			"no revision of %q in the charm mirror matches the request", candidates[0].Entity.Name)
+	}
+
+	result.ID = entry.Entity.ID
+	result.Name = entry.Entity.Name
+	result.EffectiveChannel = entry.Channel
+	result.ReleasedAt = entry.Entity.CreatedAt
+	result.Entity = entry.Entity
+	result.Entity.Download.URL = blobURL(baseURL, entry.Entity.Download.HashSHA384)
+	result.Entity.Resources = make([]transport.ResourceRevision, len(entry.Entity.Resources))
+	for i, res := range entry.Entity.Resources {
+		res.Download.URL = blobURL(baseURL, res.Download.HashSHA384)
+		result.Entity.Resources[i] = res
+	}
+	return result
+}
+
+func errorResult(result transport.RefreshResponse, code transport.APIErrorCode, format string, args ...interface{}) transport.RefreshResponse {
+	result.Result = "error"
+	result.Error = &transport.APIError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+	return result
+}
+
+func matchRevision(candidates []Entry, revision int) (Entry, bool) {
+	for _, entry := range candidates {
+		if entry.Entity.Revision == revision {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// matchChannel returns the newest revision released to the channel for
+// the base. As with Charmhub, a risk with no release falls back to the
+// next less risky one in the same track.
+func matchChannel(candidates []Entry, channel string, base transport.Base) (Entry, bool) {
+	requested, err := normaliseChannel(channel)
+	if err != nil {
+		return Entry{}, false
+	}
+	var risks []charm.Risk
+	for _, risk := range charm.Risks {
+		risks = append(risks, risk)
+		if risk == requested.Risk {
+			break
+		}
+	}
+
+	for i := len(risks) - 1; i >= 0; i-- {
+		want := requested
+		want.Risk = risks[i]
+
+		var (
+			match Entry
+			found bool
+		)
+		for _, entry := range candidates {
+			released, err := normaliseChannel(entry.Channel)
+			if err != nil || released != want || !matchBase(entry.Base, base) {
+				continue
+			}
+			if !found || entry.Entity.Revision > match.Entity.Revision {
+				match, found = entry, true
+			}
+		}
+		if found {
+			return match, true
+		}
+	}
+	return Entry{}, false
+}
+
+// normaliseChannel returns the channel with the default risk filled in
+// and the "latest" track removed, so that "stable" and "latest/stable"
+// compare equal.
+func normaliseChannel(channel string) (charm.Channel, error) {
+	if channel == "" {
+		return charm.Channel{Risk: charm.Stable}, nil
+	}
+	ch, err := charm.ParseChannelNormalize(channel)
+	if err != nil {
+		return charm.Channel{}, errors.Trace(err)
+	}
+	if ch.Track == "latest" {
+		ch.Track = ""
+	}
+	return ch, nil
+}
+
+// matchBase reports whether an entry resolved for the released base can
+// serve a request for the requested one. Fields that either side leaves
+// unspecified match anything.
+func matchBase(released, requested transport.Base) bool {
+	return matchBaseField(released.Architecture, requested.Architecture) &&
+		matchBaseField(released.Name, requested.Name) &&
+		matchBaseField(released.Channel, requested.Channel)
+}
+
+func matchBaseField(released, requested string) bool {
+	wildcard := func(s string) bool {
+		return s == "" || s == notAvailable || s == "all"
+	}
+	return wildcard(released) || wildcard(requested) || released == requested
+}
+
+// serveResourceRevisions lists the revisions of a charm's resource held in
+// the mirror. The path is resources/<charm>/<resource>/revisions.
+func (h *handler) serveResourceRevisions(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix+"resources/"), "/")
+	if len(parts) != 3 || parts[2] != "revisions" {
+		writeError(w, http.StatusNotFound, transport.ErrorCodeNotFound, "not served by the charm mirror")
+		return
+	}
+	charmName, resourceName := parts[0], parts[1]
+
+	manifest, err := ReadManifest(h.dir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, transport.ErrorCodeAPIError, err.Error())
+		return
+	}
+	baseURL := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		baseURL.Scheme = "https"
+	}
+
+	seen := make(map[int]bool)
+	resp := transport.ResourcesResponse{Revisions: []transport.ResourceRevision{}}
+	for _, entry := range manifest.Entries {
+		if entry.Entity.Name != charmName {
+			continue
+		}
+		for _, res := range entry.Entity.Resources {
+			if res.Name != resourceName || seen[res.Revision] {
+				continue
+			}
+			seen[res.Revision] = true
+			res.Download.URL = blobURL(baseURL, res.Download.HashSHA384)
+			resp.Revisions = append(resp.Revisions, res)
+		}
+	}
+	if len(seen) == 0 {
+		writeError(w, http.StatusNotFound, transport.ErrorCodeResourceNotFound, "resource not found in the charm mirror")
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// serveBlob serves a charm, bundle or resource by its SHA-384 hash.
+func (h *handler) serveBlob(w http.ResponseWriter, r *http.Request) {
+	sha384 := strings.TrimPrefix(r.URL.Path, downloadPrefix)
+	if !isSHA384(sha384) {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, BlobPath(h.dir, sha384))
+}
+
+func blobURL(baseURL *url.URL, sha384 string) string {
+	u := *baseURL
+	u.Path = path.Join(downloadPrefix, sha384)
+	return u.String()
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code transport.APIErrorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		ErrorList transport.APIErrors `json:"error-list"`
+	}{
+		ErrorList: transport.APIErrors{{Code: code, Message: message}},
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+type ServerSuite struct {
+	testing.IsolationSuite
+
+	client *fakeClient
+	signer ssh.Signer
+	dir    string
+	server *httptest.Server
+	ch     *charmhub.Client
+}
+
+var _ = gc.Suite(&ServerSuite{})
+
+var jammy = charmhub.RefreshBase{Name: "ubuntu", Channel: "22.04", Architecture: "amd64"}
+
+func (s *ServerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.client = &fakeClient{
+		entities: make(map[string]transport.RefreshEntity),
+		blobs:    make(map[string][]byte),
+	}
+	s.signer = newSigner(c)
+	s.dir = c.MkDir()
+	s.server = httptest.NewServer(NewHandler(s.dir))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	var err error
+	s.ch, err = charmhub.NewClient(charmhub.Config{
+		URL:    s.server.URL,
+		Logger: loggo.GetLogger("test"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.addCharm("postgresql", 42, []byte("postgresql charm"), map[string][]byte{
+		"snap": []byte("postgresql snap"),
+	})
+	s.importChannel(c, "postgresql", "14/stable")
+}
+
+// importChannel exports the charm from the channel and extracts the
+// archive into the mirror directory.
+func (s *ServerSuite) importChannel(c *gc.C, name, channel string) {
+	var buf bytes.Buffer
+	_, err := newExporter(c, s.client).Export(context.Background(), []Request{{
+		Name:     name,
+		Channel:  channel,
+		Revision: -1,
+		Base:     jammy,
+	}}, s.signer, &buf)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = Extract(bytes.NewReader(buf.Bytes()), s.dir, s.signer.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ServerSuite) refresh(c *gc.C, cfg charmhub.RefreshConfig) transport.RefreshResponse {
+	results, err := s.ch.Refresh(context.Background(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	return results[0]
+}
+
+func (s *ServerSuite) TestInstallFromChannel(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("postgresql", "14/stable", jammy)
+	c.Assert(err, jc.ErrorIsNil)
+	result := s.refresh(c, cfg)
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Entity.Revision, gc.Equals, 42)
+	c.Check(result.Entity.ID, gc.Equals, "postgresql-id")
+	c.Check(result.EffectiveChannel, gc.Equals, "14/stable")
+
+	// The download URL points back at the mirror, and the archive matches
+	// the hash Charmhub reported.
+	c.Check(strings.HasPrefix(result.Entity.Download.URL, s.server.URL+"/download/"), jc.IsTrue)
+	downloadURL, err := url.Parse(result.Entity.Download.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(c.MkDir(), "postgresql.charm")
+	err = s.ch.Download(context.Background(), downloadURL, path, charmhub.WithSHA384(result.Entity.Download.HashSHA384))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := os.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "postgresql charm")
+}
+
+func (s *ServerSuite) TestInstallFromLessRiskyChannel(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("postgresql", "14/edge", jammy)
+	c.Assert(err, jc.ErrorIsNil)
+	result := s.refresh(c, cfg)
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Entity.Revision, gc.Equals, 42)
+	c.Check(result.EffectiveChannel, gc.Equals, "14/stable")
+}
+
+func (s *ServerSuite) TestInstallFromRevision(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromRevision("postgresql", 42)
+	c.Assert(err, jc.ErrorIsNil)
+	result := s.refresh(c, cfg)
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Entity.Revision, gc.Equals, 42)
+
+	cfg, err = charmhub.InstallOneFromRevision("postgresql", 41)
+	c.Assert(err, jc.ErrorIsNil)
+	result = s.refresh(c, cfg)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Code, gc.Equals, transport.ErrorCodeRevisionNotFound)
+}
+
+func (s *ServerSuite) TestInstallNotFound(c *gc.C) {
+	cfg, err := charmhub.InstallOneFromChannel("mysql", "stable", jammy)
+	c.Assert(err, jc.ErrorIsNil)
+	result := s.refresh(c, cfg)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Code, gc.Equals, transport.ErrorCodeNotFound)
+
+	cfg, err = charmhub.InstallOneFromChannel("postgresql", "15/stable", jammy)
+	c.Assert(err, jc.ErrorIsNil)
+	result = s.refresh(c, cfg)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Code, gc.Equals, transport.ErrorCodeRevisionNotFound)
+}
+
+func (s *ServerSuite) TestRefreshFollowsImports(c *gc.C) {
+	s.client.addCharm("postgresql", 43, []byte("postgresql charm 43"), nil)
+	s.importChannel(c, "postgresql", "14/stable")
+
+	cfg, err := charmhub.RefreshOne("instance", "postgresql-id", 42, "14/stable", jammy)
+	c.Assert(err, jc.ErrorIsNil)
+	result := s.refresh(c, cfg)
+	c.Assert(result.Error, gc.IsNil)
+	c.Check(result.Entity.Revision, gc.Equals, 43)
+	c.Check(result.InstanceKey, gc.Equals, "instance")
+}
+
+func (s *ServerSuite) TestListResourceRevisions(c *gc.C) {
+	revisions, err := s.ch.ListResourceRevisions(context.Background(), "postgresql", "snap")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisions, gc.HasLen, 1)
+	c.Check(revisions[0].Revision, gc.Equals, 1)
+	c.Check(strings.HasPrefix(revisions[0].Download.URL, s.server.URL+"/download/"), jc.IsTrue)
+}
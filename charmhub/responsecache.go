@@ -0,0 +1,203 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// cachedResponse holds a GET response body along with the validators needed
+// to revalidate it with the server.
+type cachedResponse struct {
+	key     string
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// cacheControl holds the parts of a Cache-Control header we care about.
+type cacheControl struct {
+	noStore bool
+	noCache bool
+	maxAge  time.Duration
+}
+
+func parseCacheControl(header string) cacheControl {
+	var cc cacheControl
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			cc.noStore = true
+		case directive == "no-cache":
+			cc.noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds > 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return cc
+}
+
+// cachingHTTPClient is an HTTPClient that serves GET requests from the
+// response cache, revalidating stale entries with If-None-Match.
+type cachingHTTPClient struct {
+	httpClient HTTPClient
+	cache      *Cache
+	logger     Logger
+}
+
+// newCachingHTTPClient creates a new HTTPClient that caches info and find
+// responses in the given cache.
+func newCachingHTTPClient(httpClient HTTPClient, cache *Cache, logger Logger) *cachingHTTPClient {
+	return &cachingHTTPClient{
+		httpClient: httpClient,
+		cache:      cache,
+		logger:     logger,
+	}
+}
+
+// Do performs the *http.Request and returns an *http.Response or an error.
+// Only GET requests are cached, everything else is passed straight through.
+func (c *cachingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.httpClient.Do(req)
+	}
+
+	key := req.URL.String()
+	entry, found, fresh := c.cache.lookupResponse(key)
+	if found && fresh {
+		c.logger.Tracef("response cache hit for %s", key)
+		c.cache.requests.WithLabelValues(cacheKindResponse, cacheResultHit).Inc()
+		return entry.response(req), nil
+	}
+	if found && entry.etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified && found {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		c.logger.Tracef("response cache revalidated %s", key)
+		c.cache.requests.WithLabelValues(cacheKindResponse, cacheResultRevalidated).Inc()
+		c.cache.refreshResponse(key, resp.Header)
+		return entry.response(req), nil
+	}
+
+	c.cache.requests.WithLabelValues(cacheKindResponse, cacheResultMiss).Inc()
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	etag := resp.Header.Get("ETag")
+	if cc.noStore || (etag == "" && (cc.noCache || cc.maxAge == 0)) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.cache.storeResponse(&cachedResponse{
+		key:    key,
+		header: resp.Header.Clone(),
+		body:   body,
+		etag:   etag,
+	}, cc)
+	return resp, nil
+}
+
+// response creates a new *http.Response from the cached entry.
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// lookupResponse returns a copy of the cached response for the key, if
+// any, and whether it is still fresh.
+func (c *Cache) lookupResponse(key string) (cachedResponse, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.responseEntries[key]
+	if !ok {
+		return cachedResponse{}, false, false
+	}
+	c.responseLRU.MoveToFront(elem)
+	entry := elem.Value.(*cachedResponse)
+	return *entry, true, c.clock.Now().Before(entry.expires)
+}
+
+// storeResponse adds the response to the cache, evicting the least recently
+// used response if the cache is full.
+func (c *Cache) storeResponse(entry *cachedResponse, cc cacheControl) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !cc.noCache {
+		entry.expires = c.clock.Now().Add(cc.maxAge)
+	}
+	if elem, ok := c.responseEntries[entry.key]; ok {
+		elem.Value = entry
+		c.responseLRU.MoveToFront(elem)
+		return
+	}
+	c.responseEntries[entry.key] = c.responseLRU.PushFront(entry)
+	for c.responseLRU.Len() > c.maxResponses {
+		oldest := c.responseLRU.Remove(c.responseLRU.Back()).(*cachedResponse)
+		delete(c.responseEntries, oldest.key)
+		c.requests.WithLabelValues(cacheKindResponse, cacheResultEvicted).Inc()
+	}
+}
+
+// refreshResponse updates the freshness of a response after a successful
+// revalidation.
+func (c *Cache) refreshResponse(key string, header http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.responseEntries[key]
+	if !ok {
+		return
+	}
+	entry := elem.Value.(*cachedResponse)
+	cc := parseCacheControl(header.Get("Cache-Control"))
+	if cc.noCache {
+		entry.expires = time.Time{}
+	} else {
+		entry.expires = c.clock.Now().Add(cc.maxAge)
+	}
+	if etag := header.Get("ETag"); etag != "" {
+		entry.etag = etag
+	}
+}
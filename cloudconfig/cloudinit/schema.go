@@ -0,0 +1,125 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/gojsonschema"
+	"github.com/juju/utils/v3"
+	"gopkg.in/yaml.v2"
+)
+
+// cloudConfigSchema is the JSON schema for the cloud-config modules
+// rendered by Juju, and those most commonly set in the model's
+// cloudinit-userdata.
+//
+//go:embed cloud-config-schema.json
+var cloudConfigSchema []byte
+
+// cloudConfigHeader starts userdata in the cloud-config format.
+const cloudConfigHeader = "#cloud-config"
+
+// SchemaError is returned when userdata does not conform to the
+// cloud-init schema. It holds every violation found.
+type SchemaError struct {
+	Violations []string
+}
+
+// Error implements error.
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("userdata does not conform to the cloud-init schema:\n  %s",
+		strings.Join(e.Violations, "\n  "))
+}
+
+// DecodeUserData reverses the gzip and base64 encoding applied by the
+// provider renderers, returning userdata as cloud-init reads it.
+func DecodeUserData(userData []byte) ([]byte, error) {
+	// Renderers apply at most gzip followed by base64.
+	for i := 0; i < 2; i++ {
+		switch {
+		case bytes.HasPrefix(userData, []byte{0x1f, 0x8b}):
+			unzipped, err := utils.Gunzip(userData)
+			if err != nil {
+				return nil, errors.Annotate(err, "decompressing userdata")
+			}
+			userData = unzipped
+		case bytes.HasPrefix(userData, []byte("#")):
+			return userData, nil
+		default:
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(userData)))
+			if err != nil {
+				return nil, errors.NotValidf("userdata encoding")
+			}
+			userData = decoded
+		}
+	}
+	return userData, nil
+}
+
+// ValidateUserData checks userdata, as rendered by a provider renderer,
+// against the cloud-init schema bundled with Juju. A *SchemaError is
+// returned listing any violations. Userdata which is not in the
+// cloud-config format, such as a script, can't be validated and results
+// in a NotSupported error.
+func ValidateUserData(userData []byte) error {
+	decoded, err := DecodeUserData(userData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !bytes.HasPrefix(decoded, []byte(cloudConfigHeader+"\n")) {
+		return errors.NotSupportedf("validating userdata which is not cloud-config")
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(decoded, &doc); err != nil {
+		return &SchemaError{Violations: []string{err.Error()}}
+	}
+	if doc == nil {
+		// An empty cloud-config is valid.
+		return nil
+	}
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewStringLoader(string(cloudConfigSchema)),
+		gojsonschema.NewGoLoader(yamlToJSON(doc)),
+	)
+	if err != nil {
+		return errors.Annotate(err, "validating userdata")
+	}
+	if result.Valid() {
+		return nil
+	}
+	violations := make([]string, len(result.Errors()))
+	for i, resultErr := range result.Errors() {
+		field := strings.TrimPrefix(resultErr.Context.String(), "(root).")
+		violations[i] = fmt.Sprintf("%s: %s", field, resultErr.Description)
+	}
+	sort.Strings(violations)
+	return &SchemaError{Violations: violations}
+}
+
+// yamlToJSON converts the maps produced by the YAML decoder to the
+// string keyed maps expected by the schema validator.
+func yamlToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = yamlToJSON(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = yamlToJSON(item)
+		}
+		return items
+	}
+	return value
+}
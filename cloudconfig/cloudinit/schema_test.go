@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/packaging/v3"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/v3"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SchemaSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&SchemaSuite{})
+
+func (s *SchemaSuite) renderedConfig(c *gc.C) []byte {
+	cfg, err := cloudinit.New("ubuntu")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg.AddUser(&cloudinit.User{
+		Name:              "ubuntu",
+		Groups:            []string{"adm", "sudo"},
+		Shell:             "/bin/bash",
+		Sudo:              "ALL=(ALL) NOPASSWD:ALL",
+		SSHAuthorizedKeys: "ssh-ed25519 AAAA juju",
+	})
+	cfg.SetSystemUpdate(true)
+	cfg.SetSystemUpgrade(false)
+	cfg.AddPackage("curl")
+	cfg.AddPackageSource(packaging.PackageSource{Name: "juju", URL: "ppa:juju/stable"})
+	cfg.SetPackageProxy("http://proxy.example.com:3128")
+	cfg.AddBootCmd("echo boot")
+	cfg.AddRunCmd("echo run")
+	cfg.AddRunTextFile("/etc/juju.conf", "juju", 0644)
+	cfg.AddMount("/dev/sdb", "/srv", "ext4", "defaults", "0", "2")
+	cfg.SetOutput(cloudinit.OutAll, "| tee -a /var/log/cloud-init-output.log", "")
+	cfg.SetDisableRoot(true)
+	cfg.SetFinalMessage("done")
+	cfg.ManageEtcHosts(true)
+	err = cfg.SetSSHKeys(cloudinit.SSHKeys{{
+		Private:            "private",
+		Public:             "public",
+		PublicKeyAlgorithm: "ssh-ed25519",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	userData, err := cfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	return userData
+}
+
+func (s *SchemaSuite) TestValidateRenderedUserData(c *gc.C) {
+	userData := s.renderedConfig(c)
+	c.Assert(cloudinit.ValidateUserData(userData), jc.ErrorIsNil)
+
+	// Encoded userdata, as sent to providers, is also validated.
+	encoded := renderers.ToBase64(utils.Gzip(userData))
+	c.Assert(cloudinit.ValidateUserData(encoded), jc.ErrorIsNil)
+}
+
+func (s *SchemaSuite) TestValidateUserDataViolations(c *gc.C) {
+	err := cloudinit.ValidateUserData([]byte(`#cloud-config
+package_update: "yes"
+runcmd: []
+write_files:
+- content: juju
+users:
+- name: ubuntu
+  sudo: ALL=(ALL) NOPASSWD:ALL
+  lock-passwd: true
+unknown_module: accepted
+`))
+	c.Assert(err, gc.FitsTypeOf, &cloudinit.SchemaError{})
+	c.Check(err.(*cloudinit.SchemaError).Violations, jc.DeepEquals, []string{
+		"package_update: must be of type boolean",
+		"runcmd: array must have at least 1 items",
+		`users.0: additional property "lock-passwd" is not allowed`,
+		"users.0: must validate one and only one schema (oneOf)",
+		"users: must validate one and only one schema (oneOf)",
+		`write_files.0: "path" property is missing and required`,
+	})
+	c.Check(err, gc.ErrorMatches, `(?s)userdata does not conform to the cloud-init schema:\n  package_update: .*`)
+}
+
+func (s *SchemaSuite) TestValidateUserDataNotYAML(c *gc.C) {
+	err := cloudinit.ValidateUserData([]byte("#cloud-config\nruncmd: [\n"))
+	c.Assert(err, gc.FitsTypeOf, &cloudinit.SchemaError{})
+}
+
+func (s *SchemaSuite) TestValidateUserDataScript(c *gc.C) {
+	err := cloudinit.ValidateUserData([]byte("#!/bin/bash\necho hello\n"))
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
+
+func (s *SchemaSuite) TestDecodeUserData(c *gc.C) {
+	userData := []byte("#cloud-config\nruncmd: [ls]\n")
+	for i, encoded := range [][]byte{
+		userData,
+		utils.Gzip(userData),
+		renderers.ToBase64(userData),
+		renderers.ToBase64(utils.Gzip(userData)),
+	} {
+		c.Logf("test %d", i)
+		decoded, err := cloudinit.DecodeUserData(encoded)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(decoded), gc.Equals, string(userData))
+	}
+
+	_, err := cloudinit.DecodeUserData([]byte("not userdata!"))
+	c.Assert(err, gc.ErrorMatches, "userdata encoding not valid")
+}
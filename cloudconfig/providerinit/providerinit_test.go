@@ -193,6 +193,7 @@ func (*CloudInitSuite) testUserData(c *gc.C, base corebase.Base, bootstrap bool)
 	cloudcfg.AddRunCmd(script2)
 	result, err := providerinit.ComposeUserData(cfg, cloudcfg, &openstack.OpenstackRenderer{})
 	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudinit.ValidateUserData(result), jc.ErrorIsNil)
 
 	unzipped, err := utils.Gunzip(result)
 	c.Assert(err, jc.ErrorIsNil)
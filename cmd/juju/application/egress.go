@@ -0,0 +1,267 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/client/application"
+	appfacade "github.com/juju/juju/apiserver/facades/client/application"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+const (
+	setEgressSummary = `Restricts the outbound traffic of an application's machines.`
+	setEgressDetails = `
+Each rule is a port range, optionally followed by "to" and a comma
+separated list of destination CIDRs. A rule without destinations allows
+traffic to anywhere on that port range.
+
+Once rules are set, the machines of the application may only send
+traffic matched by one of them. The rules replace any previously set.
+Use --reset to remove all rules and allow all outbound traffic again.
+
+Rules are enforced by the firewaller using the provider's per-machine
+firewall, which is currently only supported on AWS with the "instance"
+firewall-mode. On other clouds, including GCE and OpenStack, and with
+the "global" firewall-mode the rules are stored but not enforced, and
+a warning is logged; no host firewall is used as a fallback.
+Applications placed on the same machine share its firewall, so the
+machine allows the outbound traffic of all their rules.
+`
+	setEgressExamples = `
+    juju set-egress postgresql "443/tcp" "53/udp to 10.0.0.2/32"
+    juju set-egress postgresql "5432/tcp to 10.0.0.0/8,192.168.0.0/16"
+    juju set-egress postgresql --reset
+`
+
+	showEgressSummary = `Shows the outbound traffic rules of an application.`
+	showEgressDetails = `
+Shows the rules set with set-egress. An application without rules may
+send traffic anywhere.
+`
+	showEgressExamples = `
+    juju show-egress postgresql
+    juju show-egress postgresql --format yaml
+`
+)
+
+// NewSetEgressCommand returns a command which sets the egress rules of
+// an application.
+func NewSetEgressCommand() cmd.Command {
+	return modelcmd.Wrap(&setEgressCommand{})
+}
+
+type setEgressCommand struct {
+	modelcmd.ModelCommandBase
+	api ApplicationAPI
+
+	applicationName string
+	rules           firewall.EgressRules
+	reset           bool
+}
+
+// Info is part of the cmd.Command interface.
+func (c *setEgressCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "set-egress",
+		Args:     "<application name> [<rule> ...]",
+		Purpose:  setEgressSummary,
+		Doc:      setEgressDetails,
+		Examples: setEgressExamples,
+		SeeAlso: []string{
+			"show-egress",
+			"expose",
+		},
+	})
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *setEgressCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.reset, "reset", false, "Remove all rules, allowing all outbound traffic")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *setEgressCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	c.applicationName = args[0]
+	args = args[1:]
+	if c.reset {
+		if len(args) > 0 {
+			return errors.New("cannot specify rules with --reset")
+		}
+		return nil
+	}
+	if len(args) == 0 {
+		return errors.New("no rules specified")
+	}
+	for _, arg := range args {
+		rule, err := firewall.ParseEgressRule(arg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.rules = append(c.rules, rule)
+	}
+	c.rules.Sort()
+	return nil
+}
+
+// Run is part of the cmd.Command interface.
+func (c *setEgressCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if c.reset {
+		err = client.UnsetApplicationConfig("", c.applicationName, []string{appfacade.EgressConfigOptionName})
+	} else {
+		err = client.SetConfig("", c.applicationName, "",
+			map[string]string{appfacade.EgressConfigOptionName: c.rules.String()},
+		)
+	}
+	return errors.Trace(block.ProcessBlockedError(err, block.BlockChange))
+}
+
+func (c *setEgressCommand) getAPI() (ApplicationAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// NewShowEgressCommand returns a command which shows the egress rules of
+// an application.
+func NewShowEgressCommand() cmd.Command {
+	return modelcmd.Wrap(&showEgressCommand{})
+}
+
+type showEgressCommand struct {
+	modelcmd.ModelCommandBase
+	api ApplicationAPI
+	out cmd.Output
+
+	applicationName string
+}
+
+// Info is part of the cmd.Command interface.
+func (c *showEgressCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show-egress",
+		Args:     "<application name>",
+		Purpose:  showEgressSummary,
+		Doc:      showEgressDetails,
+		Examples: showEgressExamples,
+		SeeAlso: []string{
+			"set-egress",
+		},
+	})
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *showEgressCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatEgressTabular,
+	})
+}
+
+// Init is part of the cmd.Command interface.
+func (c *showEgressCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	c.applicationName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// egressRule is the serialised form of a firewall.EgressRule.
+type egressRule struct {
+	PortRange    string   `yaml:"port-range" json:"port-range"`
+	Destinations []string `yaml:"destinations,omitempty" json:"destinations,omitempty"`
+}
+
+// Run is part of the cmd.Command interface.
+func (c *showEgressCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	results, err := client.Get("", c.applicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var value string
+	if info, ok := results.ApplicationConfig[appfacade.EgressConfigOptionName].(map[string]interface{}); ok {
+		value, _ = info["value"].(string)
+	}
+	rules, err := firewall.ParseEgressRules(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	out := make([]egressRule, len(rules))
+	for i, rule := range rules {
+		out[i] = egressRule{
+			PortRange:    rule.PortRange.String(),
+			Destinations: rule.DestinationCIDRs.SortedValues(),
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+func (c *showEgressCommand) getAPI() (ApplicationAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+func formatEgressTabular(writer io.Writer, value interface{}) error {
+	rules, ok := value.([]egressRule)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", rules, value)
+	}
+	if len(rules) == 0 {
+		_, err := fmt.Fprintln(writer, "No egress rules; all outbound traffic is allowed.")
+		return err
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Port range", "Destinations")
+	for _, rule := range rules {
+		destinations := strings.Join(rule.Destinations, ",")
+		if destinations == "" {
+			destinations = "anywhere"
+		}
+		w.Println(rule.PortRange, destinations)
+	}
+	return tw.Flush()
+}
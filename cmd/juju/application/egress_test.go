@@ -0,0 +1,151 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application/mocks"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/rpc/params"
+)
+
+type EgressSuite struct {
+	applicationAPI *mocks.MockApplicationAPI
+	store          *jujuclient.MemStore
+}
+
+func (s *EgressSuite) SetUpTest(c *gc.C) {
+	s.store = jujuclienttesting.MinimalStore()
+}
+
+var _ = gc.Suite(&EgressSuite{})
+
+func (s *EgressSuite) setupMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.applicationAPI = mocks.NewMockApplicationAPI(ctrl)
+	return ctrl
+}
+
+func (s *EgressSuite) runSetEgress(c *gc.C, args ...string) error {
+	setEgressCmd := modelcmd.Wrap(&setEgressCommand{api: s.applicationAPI})
+	setEgressCmd.SetClientStore(s.store)
+	_, err := cmdtesting.RunCommand(c, setEgressCmd, args...)
+	return err
+}
+
+func (s *EgressSuite) runShowEgress(c *gc.C, args ...string) (string, error) {
+	showEgressCmd := modelcmd.Wrap(&showEgressCommand{api: s.applicationAPI})
+	showEgressCmd.SetClientStore(s.store)
+	ctx, err := cmdtesting.RunCommand(c, showEgressCmd, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *EgressSuite) TestSetEgress(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.applicationAPI.EXPECT().SetConfig("", "postgresql", "", map[string]string{
+		"egress": "443/tcp;53/udp to 10.0.0.2/32,10.0.0.3/32",
+	})
+	s.applicationAPI.EXPECT().Close()
+
+	err := s.runSetEgress(c, "postgresql", "53/udp to 10.0.0.3/32,10.0.0.2/32", "443/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EgressSuite) TestSetEgressReset(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.applicationAPI.EXPECT().UnsetApplicationConfig("", "postgresql", []string{"egress"})
+	s.applicationAPI.EXPECT().Close()
+
+	err := s.runSetEgress(c, "postgresql", "--reset")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EgressSuite) TestSetEgressInitErrors(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	err := s.runSetEgress(c)
+	c.Assert(err, gc.ErrorMatches, "no application name specified")
+
+	err = s.runSetEgress(c, "postgresql")
+	c.Assert(err, gc.ErrorMatches, "no rules specified")
+
+	err = s.runSetEgress(c, "postgresql", "--reset", "443/tcp")
+	c.Assert(err, gc.ErrorMatches, "cannot specify rules with --reset")
+
+	err = s.runSetEgress(c, "postgresql", "443/tcp to 10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `parsing egress rule "443/tcp to 10.0.0.0": invalid CIDR address: 10.0.0.0`)
+}
+
+func (s *EgressSuite) TestShowEgress(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.applicationAPI.EXPECT().Get("", "postgresql").Return(&params.ApplicationGetResults{
+		ApplicationConfig: map[string]interface{}{
+			"egress": map[string]interface{}{
+				"source": "user",
+				"value":  "443/tcp;5432/tcp to 10.0.0.0/8,192.168.0.0/16",
+			},
+		},
+	}, nil)
+	s.applicationAPI.EXPECT().Close()
+
+	out, err := s.runShowEgress(c, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Port range  Destinations
+443/tcp     anywhere
+5432/tcp    10.0.0.0/8,192.168.0.0/16
+`[1:])
+}
+
+func (s *EgressSuite) TestShowEgressYAML(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.applicationAPI.EXPECT().Get("", "postgresql").Return(&params.ApplicationGetResults{
+		ApplicationConfig: map[string]interface{}{
+			"egress": map[string]interface{}{
+				"source": "user",
+				"value":  "443/tcp;53/udp to 10.0.0.2/32",
+			},
+		},
+	}, nil)
+	s.applicationAPI.EXPECT().Close()
+
+	out, err := s.runShowEgress(c, "postgresql", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+- port-range: 443/tcp
+- port-range: 53/udp
+  destinations:
+  - 10.0.0.2/32
+`[1:])
+}
+
+func (s *EgressSuite) TestShowEgressUnset(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	s.applicationAPI.EXPECT().Get("", "postgresql").Return(&params.ApplicationGetResults{
+		ApplicationConfig: map[string]interface{}{
+			"egress": map[string]interface{}{
+				"source": "unset",
+			},
+		},
+	}, nil)
+	s.applicationAPI.EXPECT().Close()
+
+	out, err := s.runShowEgress(c, "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "No egress rules; all outbound traffic is allowed.\n")
+}
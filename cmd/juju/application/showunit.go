@@ -14,7 +14,9 @@ import (
 
 	"github.com/juju/juju/api/client/application"
 	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/rpc/params"
 )
 
 const showUnitDoc = `
@@ -44,6 +46,10 @@ To show only the relation data for a specific endpoint:
 To show only the relation data for a specific related unit:
 
     juju show-unit mysql/0 --related-unit wordpress/2
+
+To show the timing of the most recent hooks and operations run by a unit:
+
+    juju show-unit mysql/0 --hook-history
 `
 
 // NewShowUnitCommand returns a command that displays unit info.
@@ -63,6 +69,7 @@ type showUnitCommand struct {
 	endpoint    string
 	relatedUnit string
 	appOnly     bool
+	hookHistory bool
 
 	newAPIFunc func() (UnitsInfoAPI, error)
 }
@@ -115,12 +122,14 @@ func (c *showUnitCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.endpoint, "endpoint", "", "only show relation data for the specified endpoint")
 	f.StringVar(&c.relatedUnit, "related-unit", "", "only show relation data for the specified unit")
 	f.BoolVar(&c.appOnly, "app", false, "only show application relation data")
+	f.BoolVar(&c.hookHistory, "hook-history", false, "show the timing of recent hooks and operations run by the unit")
 }
 
 // UnitsInfoAPI defines the API methods that show-unit command uses.
 type UnitsInfoAPI interface {
 	Close() error
 	UnitsInfo([]names.UnitTag) ([]application.UnitInfo, error)
+	UnitsHookHistory([]names.UnitTag) ([]params.UnitHookHistoryResult, error)
 }
 
 func (c *showUnitCommand) newUnitAPI() (UnitsInfoAPI, error) {
@@ -166,13 +175,46 @@ func (c *showUnitCommand) Run(ctx *cmd.Context) error {
 		return errors.New(strings.Join(errorStrings, "\n"))
 	}
 
-	output, err := c.formatUnitInfos(valid)
+	var histories map[string][]params.HookSpan
+	if c.hookHistory {
+		histories = c.hookHistories(ctx, client, valid)
+	}
+
+	output, err := c.formatUnitInfos(valid, histories)
 	if err != nil {
 		return err
 	}
 	return c.out.Write(ctx, output)
 }
 
+// hookHistories returns the hook history of each unit, keyed by unit
+// tag. The history is only diagnostic, so units whose history can't be
+// read are shown without it, with a warning.
+func (c *showUnitCommand) hookHistories(ctx *cmd.Context, client UnitsInfoAPI, units []application.UnitInfo) map[string][]params.HookSpan {
+	tags := make([]names.UnitTag, 0, len(units))
+	for _, unit := range units {
+		tag, err := names.ParseUnitTag(unit.Tag)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	results, err := client.UnitsHookHistory(tags)
+	if err != nil {
+		ctx.Warningf("cannot show hook history: %v", err)
+		return nil
+	}
+	histories := make(map[string][]params.HookSpan)
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Warningf("cannot show hook history for %s: %v", tags[i].Id(), result.Error)
+			continue
+		}
+		histories[tags[i].String()] = result.History
+	}
+	return histories
+}
+
 func (c *showUnitCommand) getUnitTags() ([]names.UnitTag, error) {
 	tags := make([]names.UnitTag, len(c.units))
 	for i, one := range c.units {
@@ -184,13 +226,13 @@ func (c *showUnitCommand) getUnitTags() ([]names.UnitTag, error) {
 	return tags, nil
 }
 
-func (c *showUnitCommand) formatUnitInfos(all []application.UnitInfo) (map[string]UnitInfo, error) {
+func (c *showUnitCommand) formatUnitInfos(all []application.UnitInfo, histories map[string][]params.HookSpan) (map[string]UnitInfo, error) {
 	if len(all) == 0 {
 		return nil, nil
 	}
 	output := make(map[string]UnitInfo)
 	for _, one := range all {
-		tag, info, err := c.createUnitInfo(one)
+		tag, info, err := c.createUnitInfo(one, histories[one.Tag])
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -224,13 +266,27 @@ type UnitInfo struct {
 	Leader          bool           `yaml:"leader" json:"leader"`
 	Life            string         `yaml:"life,omitempty" json:"life,omitempty"`
 	RelationData    []RelationData `yaml:"relation-info,omitempty" json:"relation-info,omitempty"`
+	HookHistory     []HookSpan     `yaml:"hook-history,omitempty" json:"hook-history,omitempty"`
 
 	// The following are for CAAS models.
 	ProviderId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
 	Address    string `yaml:"address,omitempty" json:"address,omitempty"`
 }
 
-func (c *showUnitCommand) createUnitInfo(details application.UnitInfo) (names.UnitTag, UnitInfo, error) {
+// HookSpan defines the serialization behaviour of a single operation
+// recorded in a unit's hook history.
+type HookSpan struct {
+	Operation string `yaml:"operation" json:"operation"`
+	Queued    string `yaml:"queued" json:"queued"`
+	LockWait  string `yaml:"lock-wait" json:"lock-wait"`
+	Prepare   string `yaml:"prepare" json:"prepare"`
+	Execute   string `yaml:"execute" json:"execute"`
+	Commit    string `yaml:"commit" json:"commit"`
+	Skipped   bool   `yaml:"skipped,omitempty" json:"skipped,omitempty"`
+	Error     string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func (c *showUnitCommand) createUnitInfo(details application.UnitInfo, history []params.HookSpan) (names.UnitTag, UnitInfo, error) {
 	tag, err := names.ParseUnitTag(details.Tag)
 	if err != nil {
 		return names.UnitTag{}, UnitInfo{}, errors.Trace(err)
@@ -247,6 +303,18 @@ func (c *showUnitCommand) createUnitInfo(details application.UnitInfo) (names.Un
 		ProviderId:      details.ProviderId,
 		Address:         details.Address,
 	}
+	for _, span := range history {
+		info.HookHistory = append(info.HookHistory, HookSpan{
+			Operation: span.Operation,
+			Queued:    common.FormatTime(&span.Queued, true),
+			LockWait:  span.LockWait.String(),
+			Prepare:   span.Prepare.String(),
+			Execute:   span.Execute.String(),
+			Commit:    span.Commit.String(),
+			Skipped:   span.Skipped,
+			Error:     span.Error,
+		})
+	}
 	for _, rdparams := range details.RelationData {
 		if c.endpoint != "" && rdparams.Endpoint != c.endpoint {
 			continue
@@ -6,6 +6,7 @@ package application_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd/v3"
 	"github.com/juju/cmd/v3/cmdtesting"
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/juju/cmd/juju/application"
 	"github.com/juju/juju/jujuclient"
 	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 	jujutesting "github.com/juju/juju/testing"
 )
@@ -309,6 +311,114 @@ wordpress/0:
 	})
 }
 
+func (s *ShowUnitSuite) TestShowHookHistory(c *gc.C) {
+	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
+		info := s.createTestUnitInfo("wordpress", "")
+		info.RelationData = nil
+		return []apiapplication.UnitInfo{info}, nil
+	}
+	historyCalls := 0
+	s.mockAPI.unitsHookHistoryFunc = func(tags []names.UnitTag) ([]params.UnitHookHistoryResult, error) {
+		historyCalls++
+		c.Check(tags, jc.DeepEquals, []names.UnitTag{names.NewUnitTag("wordpress/0")})
+		return []params.UnitHookHistoryResult{{History: []params.HookSpan{{
+			Operation: "run install hook",
+			Queued:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			LockWait:  2 * time.Second,
+			Prepare:   10 * time.Millisecond,
+			Execute:   3 * time.Second,
+			Commit:    5 * time.Millisecond,
+		}, {
+			Operation: "run config-changed hook",
+			Queued:    time.Date(2024, 5, 1, 10, 0, 6, 0, time.UTC),
+			Execute:   time.Second,
+			Error:     "hook failed",
+		}}}}, nil
+	}
+	s.assertRunShow(c, showUnitTest{
+		args: []string{"wordpress/0", "--hook-history"},
+		stdout: `
+wordpress/0:
+  workload-version: "666"
+  machine: "0"
+  opened-ports:
+  - 100-102/ip
+  public-address: 10.0.0.1
+  charm: charm-wordpress
+  leader: true
+  life: alive
+  hook-history:
+  - operation: run install hook
+    queued: 2024-05-01 10:00:00Z
+    lock-wait: 2s
+    prepare: 10ms
+    execute: 3s
+    commit: 5ms
+  - operation: run config-changed hook
+    queued: 2024-05-01 10:00:06Z
+    lock-wait: 0s
+    prepare: 0s
+    execute: 1s
+    commit: 0s
+    error: hook failed
+  provider-id: provider-id
+  address: 192.168.1.1
+`[1:],
+	})
+
+	c.Assert(historyCalls, gc.Equals, 1)
+
+	// Without the flag the history is not fetched.
+	s.assertRunShow(c, showUnitTest{
+		args: []string{"wordpress/0"},
+		stdout: `
+wordpress/0:
+  workload-version: "666"
+  machine: "0"
+  opened-ports:
+  - 100-102/ip
+  public-address: 10.0.0.1
+  charm: charm-wordpress
+  leader: true
+  life: alive
+  provider-id: provider-id
+  address: 192.168.1.1
+`[1:],
+	})
+	c.Assert(historyCalls, gc.Equals, 1)
+}
+
+func (s *ShowUnitSuite) TestShowHookHistoryError(c *gc.C) {
+	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
+		info := s.createTestUnitInfo("wordpress", "")
+		info.RelationData = nil
+		return []apiapplication.UnitInfo{info}, nil
+	}
+	s.mockAPI.unitsHookHistoryFunc = func(tags []names.UnitTag) ([]params.UnitHookHistoryResult, error) {
+		return []params.UnitHookHistoryResult{{
+			Error: &params.Error{Message: "reading hook history for wordpress/0: unexpected end of JSON input"},
+		}}, nil
+	}
+	// The unit is still shown, without its history.
+	s.assertRunShow(c, showUnitTest{
+		args: []string{"wordpress/0", "--hook-history"},
+		stdout: `
+wordpress/0:
+  workload-version: "666"
+  machine: "0"
+  opened-ports:
+  - 100-102/ip
+  public-address: 10.0.0.1
+  charm: charm-wordpress
+  leader: true
+  life: alive
+  provider-id: provider-id
+  address: 192.168.1.1
+`[1:],
+	})
+	c.Assert(c.GetTestLog(), jc.Contains, "cannot show hook history for wordpress/0: reading hook history for wordpress/0: unexpected end of JSON input")
+}
+
 func (s *ShowUnitSuite) TestShowJSON(c *gc.C) {
 	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
 		return []apiapplication.UnitInfo{
@@ -395,7 +505,8 @@ wordpress/0:
 }
 
 type mockShowUnitAPI struct {
-	unitsInfoFunc func([]names.UnitTag) ([]apiapplication.UnitInfo, error)
+	unitsInfoFunc        func([]names.UnitTag) ([]apiapplication.UnitInfo, error)
+	unitsHookHistoryFunc func([]names.UnitTag) ([]params.UnitHookHistoryResult, error)
 }
 
 func (s mockShowUnitAPI) Close() error {
@@ -405,3 +516,7 @@ func (s mockShowUnitAPI) Close() error {
 func (s mockShowUnitAPI) UnitsInfo(tags []names.UnitTag) ([]apiapplication.UnitInfo, error) {
 	return s.unitsInfoFunc(tags)
 }
+
+func (s mockShowUnitAPI) UnitsHookHistory(tags []names.UnitTag) ([]params.UnitHookHistoryResult, error) {
+	return s.unitsHookHistoryFunc(tags)
+}
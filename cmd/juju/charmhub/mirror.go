@@ -0,0 +1,431 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/clock"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/mirror"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	corecharm "github.com/juju/juju/core/charm"
+	"github.com/juju/juju/version"
+)
+
+const charmMirrorDoc = `
+Export charms, bundles and their resources from CharmHub into a signed,
+self-describing archive, and import such archives on hosts that cannot
+reach CharmHub.
+
+The archive records the channel, base and revision every charm was
+resolved from. A mirror directory built from archives can be served as
+a CharmHub endpoint for models whose charmhub-url points at it, so that
+charms are deployed and refreshed from the channels that were exported.
+`
+
+const charmMirrorExamples = `
+    juju charm-mirror export --key ~/.ssh/id_ed25519 -o mirror.tar.gz postgresql wordpress
+    juju charm-mirror import --trusted-key ~/.ssh/id_ed25519.pub mirror.tar.gz /srv/charm-mirror
+    juju charm-mirror serve /srv/charm-mirror
+`
+
+// NewCharmMirrorCommand creates the charm-mirror supercommand and registers
+// the subcommands that it supports.
+func NewCharmMirrorCommand() cmd.Command {
+	charmMirror := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "charm-mirror",
+		UsagePrefix: "juju",
+		Doc:         charmMirrorDoc,
+		Purpose:     "Export, import and serve CharmHub artifacts for offline use.",
+		Examples:    charmMirrorExamples,
+	})
+	charmMirror.Register(newMirrorExportCommand())
+	charmMirror.Register(newMirrorImportCommand())
+	charmMirror.Register(newMirrorServeCommand())
+	return charmMirror
+}
+
+const mirrorExportDoc = `
+Resolve the given charms and bundles through CharmHub, download them along
+with their resources, and write them into a mirror archive signed with the
+given SSH private key. Charms referenced by exported bundles are exported
+too.
+
+The key must not be protected by a passphrase.
+`
+
+func newMirrorExportCommand() *mirrorExportCommand {
+	return &mirrorExportCommand{
+		charmHubCommand: newCharmHubCommand(),
+	}
+}
+
+// mirrorExportCommand supplies the "charm-mirror export" CLI command.
+type mirrorExportCommand struct {
+	*charmHubCommand
+
+	channel    string
+	revision   int
+	keyPath    string
+	outputPath string
+	names      []string
+}
+
+// Info returns help related info about the command, it implements
+// part of the cmd.Command interface.
+func (c *mirrorExportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export",
+		Args:    "[options] <charm or bundle>...",
+		Purpose: "Export charms and bundles from CharmHub into a mirror archive.",
+		Doc:     mirrorExportDoc,
+		SeeAlso: []string{
+			"download",
+		},
+	})
+}
+
+// SetFlags defines flags which can be used with the export command.
+// It implements part of the cmd.Command interface.
+func (c *mirrorExportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.charmHubCommand.SetFlags(f)
+
+	f.StringVar(&c.arch, "arch", arch.DefaultArchitecture, fmt.Sprintf("specify an arch <%s>", c.archArgumentList()))
+	f.StringVar(&c.base, "base", "", "specify a base")
+	f.StringVar(&c.channel, "channel", "", "specify a channel to use instead of the default release")
+	f.IntVar(&c.revision, "revision", -1, "specify a revision to export, only valid for a single charm")
+	f.StringVar(&c.keyPath, "key", "", "path to the SSH private key used to sign the archive")
+	f.StringVar(&c.outputPath, "o", "charm-mirror.tar.gz", "path of the archive to write")
+	f.StringVar(&c.outputPath, "output", "charm-mirror.tar.gz", "")
+}
+
+// Init initializes the export command, including validating the provided
+// flags. It implements part of the cmd.Command interface.
+func (c *mirrorExportCommand) Init(args []string) error {
+	if err := c.charmHubCommand.Init(args); err != nil {
+		return errors.Trace(err)
+	}
+	if c.arch == ArchAll {
+		return errors.Errorf("--arch must name a single architecture")
+	}
+	if len(args) == 0 {
+		return errors.Errorf("expected at least one charm or bundle name")
+	}
+	if c.revision != -1 && len(args) > 1 {
+		return errors.Errorf("--revision can only be used with a single charm or bundle")
+	}
+	if c.revision != -1 && c.channel != "" {
+		return errors.Errorf("--revision cannot be specified together with --channel")
+	}
+	if c.keyPath == "" {
+		return errors.Errorf("--key is required to sign the archive")
+	}
+	for _, arg := range args {
+		curl, err := charm.ParseURL(arg)
+		if err != nil {
+			return errors.NotValidf("charm or bundle name, %q, is", arg)
+		}
+		if !charm.CharmHub.Matches(curl.Schema) {
+			return errors.Errorf("%q is not a Charmhub charm", arg)
+		}
+		c.names = append(c.names, curl.Name)
+	}
+	return nil
+}
+
+// Run is the business logic of the export command. It implements the meaty
+// part of the cmd.Command interface.
+func (c *mirrorExportCommand) Run(cmdContext *cmd.Context) error {
+	signer, err := c.readSigner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	base := version.DefaultSupportedLTSBase()
+	if c.base != "" {
+		if base, err = corebase.ParseBaseFromString(c.base); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	channel := c.channel
+	if channel == "" {
+		channel = corecharm.DefaultChannelString
+	}
+	normChannel, err := charm.ParseChannelNormalize(channel)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.CharmHubClientFunc(charmhub.Config{
+		URL:    c.charmHubURL,
+		Logger: downloadLogger{Context: cmdContext},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	exporter, err := mirror.NewExporter(mirror.ExporterConfig{
+		Client: client,
+		Logger: mirrorLogger{Context: cmdContext},
+		Clock:  clock.WallClock,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	requests := make([]mirror.Request, len(c.names))
+	for i, name := range c.names {
+		requests[i] = mirror.Request{
+			Name:     name,
+			Channel:  normChannel.String(),
+			Revision: c.revision,
+			Base: charmhub.RefreshBase{
+				Name:         base.OS,
+				Channel:      base.Channel.Track,
+				Architecture: c.arch,
+			},
+		}
+	}
+
+	f, err := c.Filesystem().Create(c.outputPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	manifest, err := exporter.Export(context.Background(), requests, signer, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = c.Filesystem().RemoveAll(c.outputPath)
+		return errors.Trace(err)
+	}
+
+	cmdContext.Infof("Exported %d charms and bundles to %s, signed by %s", len(manifest.Entries), c.outputPath, manifest.Signer)
+	return nil
+}
+
+func (c *mirrorExportCommand) readSigner() (ssh.Signer, error) {
+	data, err := os.ReadFile(c.keyPath)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading signing key")
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing signing key %q", c.keyPath)
+	}
+	return signer, nil
+}
+
+const mirrorImportDoc = `
+Verify a mirror archive against a trusted SSH public key and unpack it into
+a mirror directory. Importing several archives into the same directory
+merges them, so that a newer export of a channel is served in place of an
+older one.
+
+Nothing is written unless the archive signature is valid, and every charm
+and resource is checked against the hash recorded in the signed manifest.
+
+Serve the directory with "juju charm-mirror serve" to deploy from it.
+`
+
+const mirrorImportExamples = `
+    juju charm-mirror import --trusted-key ~/.ssh/id_ed25519.pub mirror.tar.gz /srv/charm-mirror
+`
+
+func newMirrorImportCommand() *mirrorImportCommand {
+	return &mirrorImportCommand{}
+}
+
+// mirrorImportCommand supplies the "charm-mirror import" CLI command.
+type mirrorImportCommand struct {
+	cmd.CommandBase
+
+	trustedKeyPath string
+	archivePath    string
+	dir            string
+}
+
+// Info returns help related info about the command, it implements
+// part of the cmd.Command interface.
+func (c *mirrorImportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "import",
+		Args:     "[options] <archive> <directory>",
+		Purpose:  "Verify a mirror archive and unpack it into a mirror directory.",
+		Doc:      mirrorImportDoc,
+		Examples: mirrorImportExamples,
+		SeeAlso: []string{
+			"charm-mirror serve",
+		},
+	})
+}
+
+// SetFlags defines flags which can be used with the import command.
+// It implements part of the cmd.Command interface.
+func (c *mirrorImportCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.trustedKeyPath, "trusted-key", "", "path to the SSH public key the archive must be signed with")
+}
+
+// Init initializes the import command, including validating the provided
+// flags. It implements part of the cmd.Command interface.
+func (c *mirrorImportCommand) Init(args []string) error {
+	if len(args) != 2 {
+		return errors.Errorf("expected an archive and a directory")
+	}
+	if c.trustedKeyPath == "" {
+		return errors.Errorf("--trusted-key is required to verify the archive")
+	}
+	c.archivePath, c.dir = args[0], args[1]
+	return nil
+}
+
+// Run is the business logic of the import command. It implements the meaty
+// part of the cmd.Command interface.
+func (c *mirrorImportCommand) Run(cmdContext *cmd.Context) error {
+	data, err := os.ReadFile(c.trustedKeyPath)
+	if err != nil {
+		return errors.Annotate(err, "reading trusted key")
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return errors.Annotatef(err, "parsing trusted key %q", c.trustedKeyPath)
+	}
+
+	f, err := os.Open(c.archivePath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	manifest, err := mirror.Extract(f, c.dir, key)
+	if err != nil {
+		return errors.Annotatef(err, "importing %q", c.archivePath)
+	}
+	cmdContext.Infof("Imported %d charms and bundles into %s", len(manifest.Entries), c.dir)
+	for _, entry := range manifest.Entries {
+		channel := entry.Channel
+		if channel == "" {
+			channel = "-"
+		}
+		cmdContext.Verbosef("  %s revision %d, channel %s", entry.Entity.Name, entry.Entity.Revision, channel)
+	}
+	return nil
+}
+
+const mirrorServeDoc = `
+Serve a mirror directory over the parts of the CharmHub API that controllers
+use to resolve, download and refresh charms and their resources.
+
+Set the model's charmhub-url to the address of the mirror when adding the
+model. Charms are then deployed from the mirror with their CharmHub channel,
+revision and origin, and "juju refresh" picks up newer revisions as
+archives are imported into the directory. Resources are fetched from the
+mirror like any other CharmHub resource.
+
+The mirror answers refresh requests from the channels and bases recorded in
+the imported archives; requests for anything else fail as they would for a
+charm missing from CharmHub. Searching and "juju info" are not served.
+`
+
+const mirrorServeExamples = `
+    juju charm-mirror serve --address :8080 /srv/charm-mirror
+    juju add-model offline --config charmhub-url=http://mirror.internal:8080
+    juju deploy postgresql --channel 14/stable
+`
+
+func newMirrorServeCommand() *mirrorServeCommand {
+	return &mirrorServeCommand{}
+}
+
+// mirrorServeCommand supplies the "charm-mirror serve" CLI command.
+type mirrorServeCommand struct {
+	cmd.CommandBase
+
+	address string
+	dir     string
+}
+
+// Info returns help related info about the command, it implements
+// part of the cmd.Command interface.
+func (c *mirrorServeCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "serve",
+		Args:     "[options] <directory>",
+		Purpose:  "Serve a mirror directory as a CharmHub endpoint.",
+		Doc:      mirrorServeDoc,
+		Examples: mirrorServeExamples,
+		SeeAlso: []string{
+			"charm-mirror import",
+			"add-model",
+		},
+	})
+}
+
+// SetFlags defines flags which can be used with the serve command.
+// It implements part of the cmd.Command interface.
+func (c *mirrorServeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.address, "address", ":8080", "address to listen on")
+}
+
+// Init initializes the serve command, including validating the provided
+// flags. It implements part of the cmd.Command interface.
+func (c *mirrorServeCommand) Init(args []string) error {
+	if len(args) != 1 {
+		return errors.Errorf("expected a mirror directory")
+	}
+	c.dir = args[0]
+	return nil
+}
+
+// Run is the business logic of the serve command. It implements the meaty
+// part of the cmd.Command interface.
+func (c *mirrorServeCommand) Run(cmdContext *cmd.Context) error {
+	if _, err := mirror.ReadManifest(c.dir); err != nil {
+		return errors.Annotatef(err, "reading mirror %q", c.dir)
+	}
+	listener, err := net.Listen("tcp", c.address)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	server := &http.Server{Handler: mirror.NewHandler(c.dir)}
+
+	interrupted := make(chan os.Signal, 1)
+	cmdContext.InterruptNotify(interrupted)
+	defer cmdContext.StopInterruptNotify(interrupted)
+	go func() {
+		<-interrupted
+		_ = server.Close()
+	}()
+
+	cmdContext.Infof("Serving %s on http://%s", c.dir, listener.Addr())
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+type mirrorLogger struct {
+	Context *cmd.Context
+}
+
+func (l mirrorLogger) Infof(msg string, args ...interface{}) {
+	l.Context.Infof(msg, args...)
+}
+
+func (l mirrorLogger) Debugf(msg string, args ...interface{}) {
+	l.Context.Verbosef(msg, args...)
+}
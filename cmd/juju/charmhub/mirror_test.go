@@ -0,0 +1,345 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/charm/v12"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/mirror"
+	"github.com/juju/juju/charmhub/transport"
+	"github.com/juju/juju/cmd/juju/charmhub/mocks"
+	"github.com/juju/juju/testing"
+)
+
+type mirrorSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+
+	charmHubAPI *mocks.MockCharmHubClient
+}
+
+var _ = gc.Suite(&mirrorSuite{})
+
+func (s *mirrorSuite) TestExportInitNoArgs(c *gc.C) {
+	command := newMirrorExportCommand()
+	err := cmdtesting.InitCommand(command, []string{"--key", "id_ed25519"})
+	c.Assert(err, gc.ErrorMatches, "expected at least one charm or bundle name")
+}
+
+func (s *mirrorSuite) TestExportInitNoKey(c *gc.C) {
+	command := newMirrorExportCommand()
+	err := cmdtesting.InitCommand(command, []string{"postgresql"})
+	c.Assert(err, gc.ErrorMatches, "--key is required to sign the archive")
+}
+
+func (s *mirrorSuite) TestExportInitRevisionMultipleCharms(c *gc.C) {
+	command := newMirrorExportCommand()
+	err := cmdtesting.InitCommand(command, []string{"--key", "id_ed25519", "--revision", "1", "postgresql", "wordpress"})
+	c.Assert(err, gc.ErrorMatches, "--revision can only be used with a single charm or bundle")
+}
+
+func (s *mirrorSuite) TestExportInitAllArches(c *gc.C) {
+	command := newMirrorExportCommand()
+	err := cmdtesting.InitCommand(command, []string{"--key", "id_ed25519", "--arch", "all", "postgresql"})
+	c.Assert(err, gc.ErrorMatches, "--arch must name a single architecture")
+}
+
+func (s *mirrorSuite) TestImportInitNoTrustedKey(c *gc.C) {
+	command := newMirrorImportCommand()
+	err := cmdtesting.InitCommand(command, []string{"mirror.tar.gz", "dir"})
+	c.Assert(err, gc.ErrorMatches, "--trusted-key is required to verify the archive")
+}
+
+func (s *mirrorSuite) TestServeInitNoDirectory(c *gc.C) {
+	command := newMirrorServeCommand()
+	err := cmdtesting.InitCommand(command, []string{})
+	c.Assert(err, gc.ErrorMatches, "expected a mirror directory")
+}
+
+func (s *mirrorSuite) TestServeInvalidMirror(c *gc.C) {
+	dir := c.MkDir()
+	err := os.WriteFile(filepath.Join(dir, mirror.ManifestFile), []byte(`{"version": 99}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cmdtesting.RunCommand(c, newMirrorServeCommand(), dir)
+	c.Assert(err, gc.ErrorMatches, `reading mirror ".*": manifest version 99 not supported`)
+}
+
+func (s *mirrorSuite) TestExportAndImport(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	dir := c.MkDir()
+	keyPath, pubKeyPath := s.writeKeys(c, dir)
+
+	content := s.charmArchive(c, dir, "postgresql")
+	sha256Sum := sha256.Sum256(content)
+	sha384Sum := sha512.Sum384(content)
+	entity := transport.RefreshEntity{
+		Type:     transport.CharmType,
+		ID:       "postgresql-id",
+		Name:     "postgresql",
+		Revision: 42,
+		Download: transport.Download{
+			HashSHA256: hex.EncodeToString(sha256Sum[:]),
+			HashSHA384: hex.EncodeToString(sha384Sum[:]),
+			Size:       len(content),
+			URL:        "https://example.org/postgresql",
+		},
+	}
+
+	s.charmHubAPI.EXPECT().URL().Return("https://api.charmhub.io/v2/charms")
+	s.charmHubAPI.EXPECT().Refresh(gomock.Any(), gomock.Any()).Return([]transport.RefreshResponse{{
+		Entity:           entity,
+		EffectiveChannel: "14/stable",
+	}}, nil)
+	s.charmHubAPI.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *url.URL, path string, _ ...charmhub.DownloadOption) error {
+			return os.WriteFile(path, content, 0644)
+		},
+	)
+
+	archivePath := filepath.Join(dir, "mirror.tar.gz")
+	export := newMirrorExportCommand()
+	export.CharmHubClientFunc = func(charmhub.Config) (CharmHubClient, error) {
+		return s.charmHubAPI, nil
+	}
+	_, err := cmdtesting.RunCommand(c, export, "--key", keyPath, "-o", archivePath, "--channel", "14/stable", "--base", "ubuntu@22.04", "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	mirrorDir := filepath.Join(dir, "mirror")
+	ctx, err := cmdtesting.RunCommand(c, newMirrorImportCommand(),
+		"--trusted-key", pubKeyPath, archivePath, mirrorDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, fmt.Sprintf("Imported 1 charms and bundles into %s\n", mirrorDir))
+
+	manifest, err := mirror.ReadManifest(mirrorDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(manifest.Entries, gc.HasLen, 1)
+	c.Check(manifest.Entries[0].Channel, gc.Equals, "14/stable")
+	c.Check(manifest.Entries[0].Base, jc.DeepEquals, transport.Base{
+		Architecture: "amd64",
+		Name:         "ubuntu",
+		Channel:      "22.04",
+	})
+}
+
+func (s *mirrorSuite) TestImportResources(c *gc.C) {
+	dir := c.MkDir()
+	keyPath, pubKeyPath := s.writeKeys(c, dir)
+	content := s.charmArchive(c, dir, "postgresql")
+	resource := []byte("wal-e snapshot")
+
+	signer := s.readSigner(c, keyPath)
+	archivePath := filepath.Join(dir, "mirror.tar.gz")
+	charmHash := s.writeArchive(c, archivePath, signer, content, resource)
+
+	mirrorDir := filepath.Join(dir, "mirror")
+	_, err := cmdtesting.RunCommand(c, newMirrorImportCommand(),
+		"--trusted-key", pubKeyPath, archivePath, mirrorDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both channels are recorded, so either can be served.
+	manifest, err := mirror.ReadManifest(mirrorDir)
+	c.Assert(err, jc.ErrorIsNil)
+	var channels []string
+	for _, entry := range manifest.Entries {
+		channels = append(channels, entry.Channel)
+	}
+	c.Check(channels, jc.SameContents, []string{"14/stable", "14/candidate"})
+
+	data, err := os.ReadFile(mirror.BlobPath(mirrorDir, charmHash))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, content)
+
+	resourceSum := sha512.Sum384(resource)
+	data, err = os.ReadFile(mirror.BlobPath(mirrorDir, hex.EncodeToString(resourceSum[:])))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, resource)
+}
+
+func (s *mirrorSuite) TestImportUntrustedKey(c *gc.C) {
+	dir := c.MkDir()
+	keyPath, _ := s.writeKeys(c, dir)
+	content := s.charmArchive(c, dir, "postgresql")
+
+	archivePath := filepath.Join(dir, "mirror.tar.gz")
+	s.writeArchive(c, archivePath, s.readSigner(c, keyPath), content, nil)
+
+	_, otherPubKeyPath := s.writeKeys(c, c.MkDir())
+	mirrorDir := filepath.Join(dir, "mirror")
+	_, err := cmdtesting.RunCommand(c, newMirrorImportCommand(),
+		"--trusted-key", otherPubKeyPath, archivePath, mirrorDir)
+	c.Assert(err, gc.ErrorMatches, `importing ".*": .*`)
+
+	_, err = os.Stat(filepath.Join(mirrorDir, mirror.ManifestFile))
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (s *mirrorSuite) setUpMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.charmHubAPI = mocks.NewMockCharmHubClient(ctrl)
+	return ctrl
+}
+
+func (s *mirrorSuite) writeKeys(c *gc.C, dir string) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	c.Assert(err, jc.ErrorIsNil)
+	keyPath := filepath.Join(dir, "id_ed25519")
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	c.Assert(err, jc.ErrorIsNil)
+	pubKeyPath := filepath.Join(dir, "id_ed25519.pub")
+	err = os.WriteFile(pubKeyPath, ssh.MarshalAuthorizedKey(sshPub), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return keyPath, pubKeyPath
+}
+
+// charmArchive returns the content of a minimal charm archive with the
+// given name.
+func (s *mirrorSuite) charmArchive(c *gc.C, dir, name string) []byte {
+	charmDir := filepath.Join(dir, name)
+	err := os.MkdirAll(charmDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(charmDir, "metadata.yaml"), []byte(fmt.Sprintf(`
+name: %s
+summary: test charm
+description: test charm
+`, name)), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(charmDir, "manifest.yaml"), []byte(`
+bases:
+  - name: ubuntu
+    channel: "22.04"
+    architectures: [amd64]
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch, err := charm.ReadCharmDir(charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	err = ch.ArchiveTo(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *mirrorSuite) readSigner(c *gc.C, keyPath string) ssh.Signer {
+	data, err := os.ReadFile(keyPath)
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := ssh.ParsePrivateKey(data)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+// writeArchive exports a mirror archive holding revision 42 of the
+// postgresql charm, resolved from two channels, and returns the SHA-384
+// hash of the charm. If resource is not nil, it is exported as the
+// charm's "wal-e" resource.
+func (s *mirrorSuite) writeArchive(c *gc.C, path string, signer ssh.Signer, content, resource []byte) string {
+	blobs := map[string][]byte{"https://example.org/postgresql": content}
+	entity := transport.RefreshEntity{
+		Type:     transport.CharmType,
+		ID:       "postgresql-id",
+		Name:     "postgresql",
+		Revision: 42,
+		Download: blobDownload("https://example.org/postgresql", content),
+	}
+	if resource != nil {
+		blobs["https://example.org/wal-e"] = resource
+		entity.Resources = []transport.ResourceRevision{{
+			Name:     "wal-e",
+			Type:     "file",
+			Revision: 1,
+			Download: blobDownload("https://example.org/wal-e", resource),
+		}}
+	}
+
+	exporter, err := mirror.NewExporter(mirror.ExporterConfig{
+		Client: fakeMirrorClient{entity: entity, blobs: blobs},
+		Logger: loggo.GetLogger("test"),
+		Clock:  testclock.NewClock(time.Now()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { _ = f.Close() }()
+	base := charmhub.RefreshBase{Name: "ubuntu", Channel: "22.04", Architecture: "amd64"}
+	_, err = exporter.Export(context.Background(), []mirror.Request{
+		{Name: "postgresql", Channel: "14/stable", Revision: -1, Base: base},
+		{Name: "postgresql", Channel: "14/candidate", Revision: -1, Base: base},
+	}, signer, f)
+	c.Assert(err, jc.ErrorIsNil)
+	return entity.Download.HashSHA384
+}
+
+func blobDownload(url string, content []byte) transport.Download {
+	sha256Sum := sha256.Sum256(content)
+	sha384Sum := sha512.Sum384(content)
+	return transport.Download{
+		HashSHA256: hex.EncodeToString(sha256Sum[:]),
+		HashSHA384: hex.EncodeToString(sha384Sum[:]),
+		Size:       len(content),
+		URL:        url,
+	}
+}
+
+// fakeMirrorClient resolves every refresh request to the same entity.
+type fakeMirrorClient struct {
+	entity transport.RefreshEntity
+	blobs  map[string][]byte
+}
+
+func (f fakeMirrorClient) URL() string {
+	return "https://api.charmhub.io/v2/charms"
+}
+
+func (f fakeMirrorClient) Refresh(_ context.Context, cfg charmhub.RefreshConfig) ([]transport.RefreshResponse, error) {
+	req, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]transport.RefreshResponse, len(req.Actions))
+	for i, action := range req.Actions {
+		channel := ""
+		if action.Channel != nil {
+			channel = *action.Channel
+		}
+		responses[i] = transport.RefreshResponse{
+			Entity:           f.entity,
+			EffectiveChannel: channel,
+			InstanceKey:      action.InstanceKey,
+		}
+	}
+	return responses, nil
+}
+
+func (f fakeMirrorClient) Download(_ context.Context, resourceURL *url.URL, path string, _ ...charmhub.DownloadOption) error {
+	return os.WriteFile(path, f.blobs[resourceURL.String()], 0644)
+}
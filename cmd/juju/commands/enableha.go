@@ -12,6 +12,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/names/v5"
+	"github.com/juju/naturalsort"
 
 	"github.com/juju/juju/api/client/highavailability"
 	jujucmd "github.com/juju/juju/cmd"
@@ -72,6 +73,12 @@ that the specified number of controller machines are used to make up the
 controller.
 
 An odd number of controllers is required.
+
+Unless placement directives are given with --to, new controller machines
+are spread across the availability zones of the cloud, so that the loss of
+a single zone leaves a majority of the controllers running. Any zones
+constraint limits the zones used. A warning is shown if there are too few
+zones for the controllers to survive a zone outage.
 `
 
 const enableHAExamples = `
@@ -134,6 +141,22 @@ func formatSimple(writer io.Writer, value interface{}) error {
 		}
 	}
 
+	if len(enableHAResult.Zones) > 0 {
+		ids := make([]string, 0, len(enableHAResult.Zones))
+		for id := range enableHAResult.Zones {
+			ids = append(ids, id)
+		}
+		naturalsort.Sort(ids)
+		placements := make([]string, len(ids))
+		for i, id := range ids {
+			placements[i] = fmt.Sprintf("%s in %s", id, enableHAResult.Zones[id])
+		}
+		_, err := fmt.Fprintf(writer, "placing machines in zones: %s\n", strings.Join(placements, ", "))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -193,6 +216,9 @@ type availabilityInfo struct {
 	Removed    []string `json:"removed,omitempty" yaml:"removed,flow,omitempty"`
 	Added      []string `json:"added,omitempty" yaml:"added,flow,omitempty"`
 	Converted  []string `json:"converted,omitempty" yaml:"converted,flow,omitempty"`
+
+	// Zones maps the ids of added machines to their availability zones.
+	Zones map[string]string `json:"zones,omitempty" yaml:"zones,omitempty"`
 }
 
 // MakeHAClient defines the methods
@@ -235,12 +261,24 @@ func (c *enableHACommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
+	for _, warning := range enableHAResult.Warnings {
+		ctx.Warningf("%s", warning)
+	}
+
 	result := availabilityInfo{
 		Added:      machineTagsToIds(enableHAResult.Added...),
 		Removed:    machineTagsToIds(enableHAResult.Removed...),
 		Maintained: machineTagsToIds(enableHAResult.Maintained...),
 		Converted:  machineTagsToIds(enableHAResult.Converted...),
 	}
+	if len(enableHAResult.Zones) > 0 {
+		result.Zones = make(map[string]string, len(enableHAResult.Zones))
+		for rawTag, zone := range enableHAResult.Zones {
+			for _, id := range machineTagsToIds(rawTag) {
+				result.Zones[id] = zone
+			}
+		}
+	}
 	return c.out.Write(ctx, result)
 }
 
@@ -171,6 +171,39 @@ func (s *EnableHASuite) TestEnableHAWithFive(c *gc.C) {
 	c.Assert(len(s.fake.placement), gc.Equals, 0)
 }
 
+func (s *EnableHASuite) TestEnableHAWithZones(c *gc.C) {
+	s.fake.result.Zones = map[string]string{
+		"machine-1":  "zone1",
+		"machine-2":  "zone1",
+		"machine-10": "zone2",
+	}
+	s.fake.result.Warnings = []string{"too few availability zones"}
+
+	ctx, err := s.runEnableHA(c, "-n", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+maintaining machines: 0
+adding machines: 1, 2
+placing machines in zones: 1 in zone1, 2 in zone1, 10 in zone2
+`[1:])
+	c.Check(c.GetTestLog(), jc.Contains, "WARNING cmd too few availability zones")
+}
+
+func (s *EnableHASuite) TestEnableHAWithZonesFormatYaml(c *gc.C) {
+	s.fake.result.Zones = map[string]string{
+		"machine-1": "zone1",
+		"machine-2": "zone2",
+	}
+
+	ctx, err := s.runEnableHA(c, "-n", "3", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var result availabilityInfo
+	err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Zones, gc.DeepEquals, map[string]string{"1": "zone1", "2": "zone2"})
+}
+
 func (s *EnableHASuite) TestEnableHAWithConstraints(c *gc.C) {
 	ctx, err := s.runEnableHA(c, "--constraints", "mem=4G", "-n", "3")
 	c.Assert(err, jc.ErrorIsNil)
@@ -270,9 +303,11 @@ func (s *EnableHASuite) TestEnableHAEndToEnd(c *gc.C) {
 	ctx, err := cmdtesting.RunCommand(c, newEnableHACommand(), "-n", "3")
 	c.Assert(err, jc.ErrorIsNil)
 
+	// The new machines are spread across the available dummy provider zones.
 	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
 maintaining machines: 0
 adding machines: 1, 2
+placing machines in zones: 1 in zone1, 2 in zone3
 `[1:])
 }
 
@@ -374,6 +374,7 @@ func registerCommands(r commandRegistry) {
 	// Firewall rule commands.
 	r.Register(firewall.NewSetFirewallRuleCommand())
 	r.Register(firewall.NewListFirewallRulesCommand())
+	r.Register(firewall.NewFirewallDriftCommand())
 
 	// Destruction commands.
 	r.Register(application.NewRemoveRelationCommand())
@@ -400,6 +401,9 @@ func registerCommands(r commandRegistry) {
 	r.Register(model.NewModelSetConstraintsCommand())
 	r.Register(newSyncAgentBinaryCommand())
 	r.Register(newUpgradeModelCommand())
+	r.Register(newPauseModelUpgradeCommand())
+	r.Register(newResumeModelUpgradeCommand())
+	r.Register(newAbortModelUpgradeCommand())
 	r.Register(newUpgradeControllerCommand())
 	r.Register(application.NewRefreshCommand())
 	r.Register(application.NewSetApplicationBaseCommand())
@@ -435,6 +439,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewShowMachineUserDataCommand())
 	r.Register(machine.NewUpgradeMachineCommand())
 
 	// Manage model
@@ -506,6 +511,11 @@ func registerCommands(r commandRegistry) {
 	r.Register(storage.NewDetachStorageCommandWithAPI())
 	r.Register(storage.NewAttachStorageCommandWithAPI())
 	r.Register(storage.NewImportFilesystemCommand(storage.NewStorageImporter, nil))
+	r.Register(storage.NewResizeCommand())
+	r.Register(storage.NewCreateSnapshotCommand())
+	r.Register(storage.NewListSnapshotsCommand())
+	r.Register(storage.NewExportSnapshotCommand())
+	r.Register(storage.NewRestoreSnapshotCommand())
 
 	// Manage spaces
 	r.Register(space.NewAddCommand())
@@ -564,6 +574,10 @@ func registerCommands(r commandRegistry) {
 	// Manage Application Credential Access
 	r.Register(application.NewTrustCommand())
 
+	// Manage Application Egress
+	r.Register(application.NewSetEgressCommand())
+	r.Register(application.NewShowEgressCommand())
+
 	// Juju Dashboard commands.
 	r.Register(dashboard.NewDashboardCommand())
 
@@ -576,6 +590,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(charmhub.NewInfoCommand())
 	r.Register(charmhub.NewFindCommand())
 	r.Register(charmhub.NewDownloadCommand())
+	r.Register(charmhub.NewCharmMirrorCommand())
 
 	// Secrets.
 	r.Register(secrets.NewListSecretsCommand())
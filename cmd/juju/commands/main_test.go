@@ -306,6 +306,7 @@ func assertNoArgs(c *gc.C, argChan <-chan []string) {
 }
 
 var commandNames = []string{
+	"abort-model-upgrade",
 	"actions",
 	"add-cloud",
 	"add-credential",
@@ -328,6 +329,7 @@ var commandNames = []string{
 	"bootstrap",
 	"cancel-task",
 	"change-user-password",
+	"charm-mirror",
 	"charm-resources",
 	"clouds",
 	"collect-metrics",
@@ -337,6 +339,7 @@ var commandNames = []string{
 	"controllers",
 	"create-backup",
 	"create-storage-pool",
+	"create-storage-snapshot",
 	"credentials",
 	"dashboard",
 	"debug-code",
@@ -362,9 +365,11 @@ var commandNames = []string{
 	"enable-user",
 	"exec",
 	"export-bundle",
+	"export-storage-snapshot",
 	"expose",
 	"find",
 	"find-offers",
+	"firewall-drift",
 	"firewall-rules",
 	"constraints",
 	"model-constraints",
@@ -399,6 +404,7 @@ var commandNames = []string{
 	"list-ssh-keys",
 	"list-storage",
 	"list-storage-pools",
+	"list-storage-snapshots",
 	"list-subnets",
 	"list-users",
 	"login",
@@ -414,6 +420,7 @@ var commandNames = []string{
 	"offer",
 	"offers",
 	"operations",
+	"pause-model-upgrade",
 	"payloads",
 	"refresh",
 	"regions",
@@ -437,9 +444,12 @@ var commandNames = []string{
 	"remove-unit",
 	"remove-user",
 	"rename-space",
+	"resize-storage",
 	"resolved",
 	"resolve",
 	"resources",
+	"restore-storage-snapshot",
+	"resume-model-upgrade",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
@@ -455,6 +465,7 @@ var commandNames = []string{
 	"set-constraints",
 	"set-default-credentials",
 	"set-default-region",
+	"set-egress",
 	"set-firewall-rule",
 	"set-meter-status",
 	"set-model-constraints",
@@ -464,7 +475,9 @@ var commandNames = []string{
 	"show-controller",
 	"show-credential",
 	"show-credentials",
+	"show-egress",
 	"show-machine",
+	"show-machine-userdata",
 	"show-model",
 	"show-offer",
 	"show-operation",
@@ -482,6 +495,7 @@ var commandNames = []string{
 	"status",
 	"storage",
 	"storage-pools",
+	"storage-snapshots",
 	"subnets",
 	"suspend-relation",
 	"switch",
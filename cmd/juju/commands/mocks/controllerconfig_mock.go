@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/cmd/juju/commands (interfaces: ControllerConfigAPI)
+//
+// Generated by this command:
+//
+//	mockgen -package mocks -destination mocks/controllerconfig_mock.go github.com/juju/juju/cmd/juju/commands ControllerConfigAPI
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	controller "github.com/juju/juju/controller"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockControllerConfigAPI is a mock of ControllerConfigAPI interface.
+type MockControllerConfigAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockControllerConfigAPIMockRecorder
+}
+
+// MockControllerConfigAPIMockRecorder is the mock recorder for MockControllerConfigAPI.
+type MockControllerConfigAPIMockRecorder struct {
+	mock *MockControllerConfigAPI
+}
+
+// NewMockControllerConfigAPI creates a new mock instance.
+func NewMockControllerConfigAPI(ctrl *gomock.Controller) *MockControllerConfigAPI {
+	mock := &MockControllerConfigAPI{ctrl: ctrl}
+	mock.recorder = &MockControllerConfigAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockControllerConfigAPI) EXPECT() *MockControllerConfigAPIMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockControllerConfigAPI) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockControllerConfigAPIMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockControllerConfigAPI)(nil).Close))
+}
+
+// ControllerConfig mocks base method.
+func (m *MockControllerConfigAPI) ControllerConfig() (controller.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ControllerConfig")
+	ret0, _ := ret[0].(controller.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ControllerConfig indicates an expected call of ControllerConfig.
+func (mr *MockControllerConfigAPIMockRecorder) ControllerConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControllerConfig", reflect.TypeOf((*MockControllerConfigAPI)(nil).ControllerConfig))
+}
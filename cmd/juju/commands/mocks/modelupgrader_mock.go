@@ -13,6 +13,7 @@ import (
 	io "io"
 	reflect "reflect"
 
+	params "github.com/juju/juju/rpc/params"
 	tools "github.com/juju/juju/tools"
 	version "github.com/juju/version/v2"
 	gomock "go.uber.org/mock/gomock"
@@ -55,6 +56,20 @@ func (mr *MockModelUpgraderAPIMockRecorder) AbortModelUpgrade(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortModelUpgrade", reflect.TypeOf((*MockModelUpgraderAPI)(nil).AbortModelUpgrade), arg0)
 }
 
+// AbortUpgradeRollout mocks base method.
+func (m *MockModelUpgraderAPI) AbortUpgradeRollout(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortUpgradeRollout", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortUpgradeRollout indicates an expected call of AbortUpgradeRollout.
+func (mr *MockModelUpgraderAPIMockRecorder) AbortUpgradeRollout(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortUpgradeRollout", reflect.TypeOf((*MockModelUpgraderAPI)(nil).AbortUpgradeRollout), arg0)
+}
+
 // Close mocks base method.
 func (m *MockModelUpgraderAPI) Close() error {
 	m.ctrl.T.Helper()
@@ -69,6 +84,49 @@ func (mr *MockModelUpgraderAPIMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockModelUpgraderAPI)(nil).Close))
 }
 
+// PauseUpgradeRollout mocks base method.
+func (m *MockModelUpgraderAPI) PauseUpgradeRollout(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseUpgradeRollout", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseUpgradeRollout indicates an expected call of PauseUpgradeRollout.
+func (mr *MockModelUpgraderAPIMockRecorder) PauseUpgradeRollout(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseUpgradeRollout", reflect.TypeOf((*MockModelUpgraderAPI)(nil).PauseUpgradeRollout), arg0)
+}
+
+// ResumeUpgradeRollout mocks base method.
+func (m *MockModelUpgraderAPI) ResumeUpgradeRollout(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeUpgradeRollout", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeUpgradeRollout indicates an expected call of ResumeUpgradeRollout.
+func (mr *MockModelUpgraderAPIMockRecorder) ResumeUpgradeRollout(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeUpgradeRollout", reflect.TypeOf((*MockModelUpgraderAPI)(nil).ResumeUpgradeRollout), arg0)
+}
+
+// StageModelUpgrade mocks base method.
+func (m *MockModelUpgraderAPI) StageModelUpgrade(arg0 string, arg1 version.Number, arg2 params.UpgradeStages, arg3, arg4 bool) (version.Number, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StageModelUpgrade", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(version.Number)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StageModelUpgrade indicates an expected call of StageModelUpgrade.
+func (mr *MockModelUpgraderAPIMockRecorder) StageModelUpgrade(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageModelUpgrade", reflect.TypeOf((*MockModelUpgraderAPI)(nil).StageModelUpgrade), arg0, arg1, arg2, arg3, arg4)
+}
+
 // UpgradeModel mocks base method.
 func (m *MockModelUpgraderAPI) UpgradeModel(arg0 string, arg1 version.Number, arg2 string, arg3, arg4 bool) (version.Number, error) {
 	m.ctrl.T.Helper()
@@ -55,17 +55,17 @@ func (mr *MockSyncToolAPIMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSyncToolAPI)(nil).Close))
 }
 
-// UploadTools mocks base method.
-func (m *MockSyncToolAPI) UploadTools(arg0 io.ReadSeeker, arg1 version.Binary) (tools.List, error) {
+// UploadSignedTools mocks base method.
+func (m *MockSyncToolAPI) UploadSignedTools(arg0 io.ReadSeeker, arg1 version.Binary, arg2 []byte) (tools.List, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadTools", arg0, arg1)
+	ret := m.ctrl.Call(m, "UploadSignedTools", arg0, arg1, arg2)
 	ret0, _ := ret[0].(tools.List)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UploadTools indicates an expected call of UploadTools.
-func (mr *MockSyncToolAPIMockRecorder) UploadTools(arg0, arg1 any) *gomock.Call {
+// UploadSignedTools indicates an expected call of UploadSignedTools.
+func (mr *MockSyncToolAPIMockRecorder) UploadSignedTools(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadTools", reflect.TypeOf((*MockSyncToolAPI)(nil).UploadTools), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSignedTools", reflect.TypeOf((*MockSyncToolAPI)(nil).UploadSignedTools), arg0, arg1, arg2)
 }
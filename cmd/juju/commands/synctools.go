@@ -14,10 +14,12 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/version/v2"
 
+	apicontroller "github.com/juju/juju/api/controller/controller"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/constants"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/sync"
 	envtools "github.com/juju/juju/environs/tools"
@@ -43,6 +45,7 @@ type syncAgentBinaryCommand struct {
 	stream        string
 	localDir      string
 	syncToolAPI   SyncToolAPI
+	controllerAPI ControllerConfigAPI
 }
 
 var _ cmd.Command = (*syncAgentBinaryCommand)(nil)
@@ -98,7 +101,7 @@ func (c *syncAgentBinaryCommand) Init(args []string) error {
 // SyncToolAPI provides an interface with a subset of the
 // modelupgrader.Client API. This exists to enable mocking.
 type SyncToolAPI interface {
-	UploadTools(r io.ReadSeeker, v version.Binary) (coretools.List, error)
+	UploadSignedTools(r io.ReadSeeker, v version.Binary, signature []byte) (coretools.List, error)
 	Close() error
 }
 
@@ -109,6 +112,39 @@ func (c *syncAgentBinaryCommand) getSyncToolAPI() (SyncToolAPI, error) {
 	return c.NewModelUpgraderAPIClient()
 }
 
+// ControllerConfigAPI provides an interface with a subset of the
+// controller.Client API. This exists to enable mocking.
+type ControllerConfigAPI interface {
+	ControllerConfig() (controller.Config, error)
+	Close() error
+}
+
+func (c *syncAgentBinaryCommand) getControllerConfigAPI() (ControllerConfigAPI, error) {
+	if c.controllerAPI != nil {
+		return c.controllerAPI, nil
+	}
+	root, err := c.NewControllerAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apicontroller.NewClient(root), nil
+}
+
+// signatureVerifier returns a verifier for the agent binaries synced to
+// the controller, trusting the signers configured on the controller.
+func (c *syncAgentBinaryCommand) signatureVerifier() (*envtools.SignatureVerifier, error) {
+	api, err := c.getControllerConfigAPI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer api.Close()
+	cfg, err := api.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller config")
+	}
+	return envtools.NewSignatureVerifier(cfg)
+}
+
 func (c *syncAgentBinaryCommand) Run(ctx *cmd.Context) (resultErr error) {
 	// Register writer for output on screen.
 	writer := loggo.NewMinimumLevelWriter(
@@ -155,6 +191,9 @@ func (c *syncAgentBinaryCommand) Run(ctx *cmd.Context) (resultErr error) {
 		defer api.Close()
 		adapter := syncToolAPIAdapter{api}
 		sctx.TargetToolsUploader = adapter
+		if sctx.SignatureVerifier, err = c.signatureVerifier(); err != nil {
+			return err
+		}
 	}
 	return block.ProcessBlockedError(syncTools(sctx), block.BlockChange)
 }
@@ -166,7 +205,7 @@ type syncToolAPIAdapter struct {
 	SyncToolAPI
 }
 
-func (s syncToolAPIAdapter) UploadTools(toolsDir, stream string, tools *coretools.Tools, data []byte) error {
-	_, err := s.SyncToolAPI.UploadTools(bytes.NewReader(data), tools.Version)
+func (s syncToolAPIAdapter) UploadTools(toolsDir, stream string, tools *coretools.Tools, data, signature []byte) error {
+	_, err := s.SyncToolAPI.UploadSignedTools(bytes.NewReader(data), tools.Version, signature)
 	return err
 }
@@ -18,8 +18,10 @@ import (
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/cmd/juju/commands/mocks"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/sync"
 	envtools "github.com/juju/juju/environs/tools"
+	toolstesting "github.com/juju/juju/environs/tools/testing"
 	"github.com/juju/juju/jujuclient"
 	coretesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -27,8 +29,9 @@ import (
 
 type syncToolSuite struct {
 	coretesting.FakeJujuXDGDataHomeSuite
-	fakeSyncToolAPI *mocks.MockSyncToolAPI
-	store           *jujuclient.MemStore
+	fakeSyncToolAPI   *mocks.MockSyncToolAPI
+	fakeControllerAPI *mocks.MockControllerConfigAPI
+	store             *jujuclient.MemStore
 }
 
 var _ = gc.Suite(&syncToolSuite{})
@@ -53,8 +56,12 @@ func (s *syncToolSuite) Reset(c *gc.C) {
 func (s *syncToolSuite) getSyncAgentBinariesCommand(c *gc.C, args ...string) (*gomock.Controller, func() (*cmd.Context, error)) {
 	ctrl := gomock.NewController(c)
 	s.fakeSyncToolAPI = mocks.NewMockSyncToolAPI(ctrl)
+	s.fakeControllerAPI = mocks.NewMockControllerConfigAPI(ctrl)
 
-	syncToolCMD := &syncAgentBinaryCommand{syncToolAPI: s.fakeSyncToolAPI}
+	syncToolCMD := &syncAgentBinaryCommand{
+		syncToolAPI:   s.fakeSyncToolAPI,
+		controllerAPI: s.fakeControllerAPI,
+	}
 	syncToolCMD.SetClientStore(s.store)
 	return ctrl, func() (*cmd.Context, error) {
 		return cmdtesting.RunCommand(c, modelcmd.Wrap(syncToolCMD), args...)
@@ -99,6 +106,8 @@ func (s *syncToolSuite) TestSyncToolsCommand(c *gc.C) {
 		defer ctrl.Finish()
 
 		s.fakeSyncToolAPI.EXPECT().Close()
+		s.fakeControllerAPI.EXPECT().ControllerConfig().Return(coretesting.FakeControllerConfig(), nil)
+		s.fakeControllerAPI.EXPECT().Close()
 
 		called := false
 		syncTools = func(sctx *sync.SyncContext) error {
@@ -127,6 +136,32 @@ func (s *syncToolSuite) TestSyncToolsCommand(c *gc.C) {
 	}
 }
 
+func (s *syncToolSuite) TestSyncToolsCommandVerifiesSignatures(c *gc.C) {
+	ctrl, run := s.getSyncAgentBinariesCommand(c, "--agent-version", "2.9.99", "-m", "test-target")
+	defer ctrl.Finish()
+
+	_, signers := toolstesting.NewAgentBinarySigner(c)
+	cfg := coretesting.FakeControllerConfig()
+	cfg[controller.AgentBinarySigners] = signers
+	cfg[controller.RequireSignedAgentBinaries] = true
+	s.fakeSyncToolAPI.EXPECT().Close()
+	s.fakeControllerAPI.EXPECT().ControllerConfig().Return(cfg, nil)
+	s.fakeControllerAPI.EXPECT().Close()
+
+	called := false
+	syncTools = func(sctx *sync.SyncContext) error {
+		c.Assert(sctx.SignatureVerifier, gc.NotNil)
+		// Unsigned binaries are rejected, as signatures are required.
+		err := sctx.SignatureVerifier.Verify("", bytes.NewReader([]byte("agent binary")))
+		c.Assert(err, jc.ErrorIs, errors.NotFound)
+		called = true
+		return nil
+	}
+	_, err := run()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *syncToolSuite) TestSyncToolsCommandTargetDirectory(c *gc.C) {
 	dir := c.MkDir()
 	ctrl, run := s.getSyncAgentBinariesCommand(
@@ -140,6 +175,7 @@ func (s *syncToolSuite) TestSyncToolsCommandTargetDirectory(c *gc.C) {
 		c.Assert(sctx.Stream, gc.Equals, "proposed")
 		c.Assert(sctx.Source, gc.Equals, "")
 		c.Assert(sctx.TargetToolsUploader, gc.FitsTypeOf, sync.StorageToolsUploader{})
+		c.Assert(sctx.SignatureVerifier, gc.IsNil)
 		uploader := sctx.TargetToolsUploader.(sync.StorageToolsUploader)
 		c.Assert(uploader.WriteMirrors, gc.Equals, envtools.DoNotWriteMirrors)
 		url, err := uploader.Storage.URL("")
@@ -181,10 +217,10 @@ func (s *syncToolSuite) TestAPIAdapterUploadTools(c *gc.C) {
 
 	current := coretesting.CurrentVersion()
 	uploadToolsErr := errors.New("uh oh")
-	fakeAPI.EXPECT().UploadTools(bytes.NewReader([]byte("abc")), current).Return(nil, uploadToolsErr)
+	fakeAPI.EXPECT().UploadSignedTools(bytes.NewReader([]byte("abc")), current, []byte("sig")).Return(nil, uploadToolsErr)
 
 	a := syncToolAPIAdapter{fakeAPI}
-	err := a.UploadTools("released", "released", &coretools.Tools{Version: current}, []byte("abc"))
+	err := a.UploadTools("released", "released", &coretools.Tools{Version: current}, []byte("abc"), []byte("sig"))
 	c.Assert(err, gc.Equals, uploadToolsErr)
 }
 
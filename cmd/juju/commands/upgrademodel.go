@@ -52,12 +52,24 @@ If a failed upgrade has been resolved, '--reset-previous-upgrade' can be
 used to allow the upgrade to proceed.
 Backups are recommended prior to upgrading.
 
+With '--staged', machines are not all upgraded at once. The machines given
+by '--canary' (or hosting the units given) are upgraded first, then the
+remaining machines '--batch-size' at a time. Each batch must be running
+the new version with idle agents and no errors before the next batch is
+started. If more than '--error-threshold' percent of the upgraded machines
+report errors, the upgrade halts. The progress of a staged upgrade is
+shown by ` + "`juju status`" + `, and it can be controlled with the
+` + "`juju pause-model-upgrade`" + `, ` + "`juju resume-model-upgrade`" + ` and
+` + "`juju abort-model-upgrade`" + ` commands. Machines already upgraded keep
+the new version if the upgrade is aborted.
+
 `
 
 const usageUpgradeJujuExamples = `
     juju upgrade-model --dry-run
     juju upgrade-model --agent-version 2.0.1
     juju upgrade-model --agent-stream proposed
+    juju upgrade-model --staged --canary 0,mysql/1 --batch-size 5 --error-threshold 10
 `
 
 const upgradeModelMessage = "upgrade to this version by running\n    juju upgrade-model"
@@ -82,6 +94,14 @@ type upgradeModelCommand struct {
 	// version without waiting for all agents to be at the right version.
 	IgnoreAgentVersions bool
 
+	// Staged, Canaries, BatchSize and ErrorThreshold describe a staged
+	// upgrade, which upgrades the model's machines a batch at a time.
+	Staged         bool
+	canary         string
+	Canaries       []string
+	BatchSize      int
+	ErrorThreshold int
+
 	// model config API for the current model
 	modelConfigAPI   ModelConfigAPI
 	modelUpgraderAPI ModelUpgraderAPI
@@ -97,6 +117,9 @@ func (c *upgradeModelCommand) Info() *cmd.Info {
 		Examples: usageUpgradeJujuExamples,
 		SeeAlso: []string{
 			"sync-agent-binary",
+			"pause-model-upgrade",
+			"resume-model-upgrade",
+			"abort-model-upgrade",
 		},
 	})
 }
@@ -113,6 +136,11 @@ func (c *upgradeModelCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.IgnoreAgentVersions, "ignore-agent-versions", false,
 		"Don't check if all agents have already reached the current version")
 	f.DurationVar(&c.timeout, "timeout", 10*time.Minute, "Timeout before upgrade is aborted")
+	f.BoolVar(&c.Staged, "staged", false, "Upgrade machines in batches, halting on errors")
+	f.StringVar(&c.canary, "canary", "", "Comma separated machines or units to upgrade first in a staged upgrade")
+	f.IntVar(&c.BatchSize, "batch-size", 1, "Number of machines to upgrade at a time in a staged upgrade")
+	f.IntVar(&c.ErrorThreshold, "error-threshold", 0,
+		"Percentage of upgraded machines allowed to report errors before a staged upgrade halts")
 }
 
 func (c *upgradeModelCommand) Init(args []string) error {
@@ -123,9 +151,42 @@ func (c *upgradeModelCommand) Init(args []string) error {
 		}
 		c.Version = vers
 	}
+	if err := c.initStages(); err != nil {
+		return errors.Trace(err)
+	}
 	return cmd.CheckEmpty(args)
 }
 
+func (c *upgradeModelCommand) initStages() error {
+	if !c.Staged {
+		if c.canary != "" || c.BatchSize != 1 || c.ErrorThreshold != 0 {
+			return errors.New("--canary, --batch-size and --error-threshold require --staged")
+		}
+		return nil
+	}
+	if c.AgentStream != "" {
+		return errors.New("--agent-stream cannot be used with --staged")
+	}
+	if c.BatchSize < 1 {
+		return errors.NotValidf("batch size %d", c.BatchSize)
+	}
+	if c.ErrorThreshold < 0 || c.ErrorThreshold > 100 {
+		return errors.NotValidf("error threshold %d%%", c.ErrorThreshold)
+	}
+	c.Canaries = nil
+	for _, canary := range strings.Split(c.canary, ",") {
+		canary = strings.TrimSpace(canary)
+		if canary == "" {
+			continue
+		}
+		if !names.IsValidMachine(canary) && !names.IsValidUnit(canary) {
+			return errors.NotValidf("canary %q", canary)
+		}
+		c.Canaries = append(c.Canaries, canary)
+	}
+	return nil
+}
+
 const (
 	errUpToDate errors.ConstError = "no upgrades available"
 )
@@ -142,6 +203,12 @@ type ModelUpgraderAPI interface {
 		modelUUID string, targetVersion version.Number, stream string, ignoreAgentVersions, druRun bool,
 	) (version.Number, error)
 	AbortModelUpgrade(modelUUID string) error
+	StageModelUpgrade(
+		modelUUID string, targetVersion version.Number, stages params.UpgradeStages, ignoreAgentVersions, dryRun bool,
+	) (version.Number, error)
+	PauseUpgradeRollout(modelUUID string) error
+	ResumeUpgradeRollout(modelUUID string) error
+	AbortUpgradeRollout(modelUUID string) error
 	UploadTools(r io.ReadSeeker, vers version.Binary) (coretools.List, error)
 
 	Close() error
@@ -191,6 +258,8 @@ func (c *upgradeModelCommand) upgradeModel(ctx *cmd.Context, fetchTimeout time.D
 			fmt.Fprintf(ctx.Stderr, "best version:\n    %v\n", targetVersion)
 			if c.DryRun {
 				fmt.Fprintf(ctx.Stderr, "%s\n", upgradeModelMessage)
+			} else if c.Staged {
+				fmt.Fprintf(ctx.Stdout, "started staged upgrade to %s\n", targetVersion)
 			} else {
 				fmt.Fprintf(ctx.Stdout, "started upgrade to %s\n", targetVersion)
 			}
@@ -284,9 +353,20 @@ func (c *upgradeModelCommand) notifyControllerUpgrade(
 			return chosenVersion, block.ProcessBlockedError(err, block.BlockChange)
 		}
 	}
-	if chosenVersion, err = modelUpgrader.UpgradeModel(
-		modelTag.Id(), targetVersion, c.AgentStream, c.IgnoreAgentVersions, dryRun,
-	); err != nil {
+	if c.Staged {
+		chosenVersion, err = modelUpgrader.StageModelUpgrade(
+			modelTag.Id(), targetVersion, params.UpgradeStages{
+				Canaries:       c.Canaries,
+				BatchSize:      c.BatchSize,
+				ErrorThreshold: c.ErrorThreshold,
+			}, c.IgnoreAgentVersions, dryRun,
+		)
+	} else {
+		chosenVersion, err = modelUpgrader.UpgradeModel(
+			modelTag.Id(), targetVersion, c.AgentStream, c.IgnoreAgentVersions, dryRun,
+		)
+	}
+	if err != nil {
 		if params.IsCodeUpgradeInProgress(err) {
 			return chosenVersion, errors.Errorf("%s\n\n"+
 				"Please wait for the upgrade to complete or if there was a problem with\n"+
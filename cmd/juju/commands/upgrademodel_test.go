@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/environs/sync"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/rpc/params"
 	coretesting "github.com/juju/juju/testing"
 	jujuversion "github.com/juju/juju/version"
 )
@@ -528,6 +529,78 @@ started upgrade to 3.9.99
 `[1:])
 }
 
+func (s *upgradeModelSuite) TestUpgradeModelStaged(c *gc.C) {
+	s.reset(c)
+
+	ctrl, cmd := s.upgradeModelCommand(c, false)
+	defer ctrl.Finish()
+
+	agentVersion := coretesting.FakeVersionNumber
+	cfg := coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"agent-version": agentVersion.String(),
+	})
+	controllerCfg := coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"agent-version": agentVersion.String(),
+		"uuid":          testControllerModelUUID,
+	})
+
+	gomock.InOrder(
+		s.modelConfigAPI.EXPECT().ModelGet().Return(cfg, nil),
+		s.controllerModelConfigAPI.EXPECT().ModelGet().Return(controllerCfg, nil),
+		s.modelUpgrader.EXPECT().StageModelUpgrade(
+			coretesting.ModelTag.Id(), version.Zero,
+			params.UpgradeStages{
+				Canaries:       []string{"0", "mysql/1"},
+				BatchSize:      5,
+				ErrorThreshold: 10,
+			}, false, false,
+		).Return(version.MustParse("3.9.99"), nil),
+	)
+
+	ctx, err := cmdtesting.RunCommand(c, cmd,
+		"--staged", "--canary", "0, mysql/1", "--batch-size", "5", "--error-threshold", "10",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+best version:
+    3.9.99
+`[1:])
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+started staged upgrade to 3.9.99
+`[1:])
+}
+
+func (s *upgradeModelSuite) TestUpgradeModelStagedInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--canary", "0"},
+		err:  "--canary, --batch-size and --error-threshold require --staged",
+	}, {
+		args: []string{"--batch-size", "3"},
+		err:  "--canary, --batch-size and --error-threshold require --staged",
+	}, {
+		args: []string{"--staged", "--agent-stream", "proposed"},
+		err:  "--agent-stream cannot be used with --staged",
+	}, {
+		args: []string{"--staged", "--batch-size", "0"},
+		err:  "batch size 0 not valid",
+	}, {
+		args: []string{"--staged", "--error-threshold", "101"},
+		err:  "error threshold 101% not valid",
+	}, {
+		args: []string{"--staged", "--canary", "0,mysql"},
+		err:  `canary "mysql" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		ctrl, cmd := s.upgradeModelCommand(c, false)
+		_, err := cmdtesting.RunCommand(c, cmd, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+		ctrl.Finish()
+	}
+}
+
 func (s *upgradeModelSuite) assertResetPreviousUpgrade(c *gc.C, answer string, expectUpgrade bool, args ...string) {
 	s.reset(c)
 
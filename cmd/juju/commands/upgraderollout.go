@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const usagePauseModelUpgradeDetails = `
+Pauses a staged model upgrade started with ` + "`juju upgrade-model --staged`" + `.
+Machines already released keep upgrading, but no further batches are
+started until the upgrade is resumed.`
+
+const usageResumeModelUpgradeDetails = `
+Resumes a paused staged model upgrade, or one which halted because too
+many upgraded machines reported errors. A halted upgrade should only be
+resumed once the errors have been resolved.`
+
+const usageAbortModelUpgradeDetails = `
+Aborts a staged model upgrade. No further machines are upgraded; machines
+which have already been upgraded keep running the new version. The model
+can be upgraded again with ` + "`juju upgrade-model`" + `.`
+
+func newPauseModelUpgradeCommand() cmd.Command {
+	return modelcmd.Wrap(&upgradeRolloutCommand{
+		info: cmd.Info{
+			Name:     "pause-model-upgrade",
+			Purpose:  "Pauses a staged model upgrade.",
+			Doc:      usagePauseModelUpgradeDetails,
+			Examples: "    juju pause-model-upgrade\n",
+		},
+		change:  ModelUpgraderAPI.PauseUpgradeRollout,
+		message: "paused staged upgrade",
+	})
+}
+
+func newResumeModelUpgradeCommand() cmd.Command {
+	return modelcmd.Wrap(&upgradeRolloutCommand{
+		info: cmd.Info{
+			Name:     "resume-model-upgrade",
+			Purpose:  "Resumes a paused or halted staged model upgrade.",
+			Doc:      usageResumeModelUpgradeDetails,
+			Examples: "    juju resume-model-upgrade\n",
+		},
+		change:  ModelUpgraderAPI.ResumeUpgradeRollout,
+		message: "resumed staged upgrade",
+	})
+}
+
+func newAbortModelUpgradeCommand() cmd.Command {
+	return modelcmd.Wrap(&upgradeRolloutCommand{
+		info: cmd.Info{
+			Name:     "abort-model-upgrade",
+			Purpose:  "Aborts a staged model upgrade.",
+			Doc:      usageAbortModelUpgradeDetails,
+			Examples: "    juju abort-model-upgrade\n",
+		},
+		change:  ModelUpgraderAPI.AbortUpgradeRollout,
+		message: "aborted staged upgrade",
+	})
+}
+
+// upgradeRolloutCommand changes the state of a model's staged upgrade.
+type upgradeRolloutCommand struct {
+	modelcmd.ModelCommandBase
+
+	info    cmd.Info
+	change  func(ModelUpgraderAPI, string) error
+	message string
+
+	modelUpgraderAPI ModelUpgraderAPI
+}
+
+// Info implements cmd.Command.
+func (c *upgradeRolloutCommand) Info() *cmd.Info {
+	info := c.info
+	info.SeeAlso = []string{"upgrade-model", "status"}
+	return jujucmd.Info(&info)
+}
+
+// Init implements cmd.Command.
+func (c *upgradeRolloutCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *upgradeRolloutCommand) getModelUpgraderAPI() (ModelUpgraderAPI, error) {
+	if c.modelUpgraderAPI != nil {
+		return c.modelUpgraderAPI, nil
+	}
+	return c.NewModelUpgraderAPIClient()
+}
+
+// Run implements cmd.Command.
+func (c *upgradeRolloutCommand) Run(ctx *cmd.Context) error {
+	_, details, err := c.ModelCommandBase.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelUpgrader, err := c.getModelUpgraderAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer modelUpgrader.Close()
+
+	if err := c.change(modelUpgrader, details.ModelUUID); err != nil {
+		if errors.Is(err, errors.NotFound) {
+			return errors.New("no staged upgrade in progress")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintln(ctx.Stdout, c.message)
+	return nil
+}
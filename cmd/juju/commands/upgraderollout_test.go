@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/commands/mocks"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type upgradeRolloutSuite struct {
+	testing.IsolationSuite
+
+	modelUpgrader *mocks.MockModelUpgraderAPI
+}
+
+var _ = gc.Suite(&upgradeRolloutSuite{})
+
+func (s *upgradeRolloutSuite) newCommand(c *gc.C, newCommand func() cmd.Command) (*gomock.Controller, cmd.Command) {
+	ctrl := gomock.NewController(c)
+	s.modelUpgrader = mocks.NewMockModelUpgraderAPI(ctrl)
+	s.modelUpgrader.EXPECT().Close().AnyTimes()
+
+	store := jujuclienttesting.MinimalStore()
+	store.Models["arthur"].Models["king/sword"] = jujuclient.ModelDetails{
+		ModelUUID: coretesting.ModelTag.Id(),
+		ModelType: model.IAAS,
+	}
+
+	command := newCommand()
+	inner := modelcmd.InnerCommand(command).(*upgradeRolloutCommand)
+	inner.modelUpgraderAPI = s.modelUpgrader
+	inner.SetClientStore(store)
+	return ctrl, command
+}
+
+func (s *upgradeRolloutSuite) TestPause(c *gc.C) {
+	ctrl, command := s.newCommand(c, newPauseModelUpgradeCommand)
+	defer ctrl.Finish()
+
+	s.modelUpgrader.EXPECT().PauseUpgradeRollout(coretesting.ModelTag.Id()).Return(nil)
+
+	ctx, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "paused staged upgrade\n")
+}
+
+func (s *upgradeRolloutSuite) TestResume(c *gc.C) {
+	ctrl, command := s.newCommand(c, newResumeModelUpgradeCommand)
+	defer ctrl.Finish()
+
+	s.modelUpgrader.EXPECT().ResumeUpgradeRollout(coretesting.ModelTag.Id()).Return(nil)
+
+	ctx, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "resumed staged upgrade\n")
+}
+
+func (s *upgradeRolloutSuite) TestAbortNotFound(c *gc.C) {
+	ctrl, command := s.newCommand(c, newAbortModelUpgradeCommand)
+	defer ctrl.Finish()
+
+	s.modelUpgrader.EXPECT().AbortUpgradeRollout(coretesting.ModelTag.Id()).Return(
+		errors.NotFoundf("staged model upgrade"),
+	)
+
+	_, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, gc.ErrorMatches, "no staged upgrade in progress")
+}
+
+func (s *upgradeRolloutSuite) TestTooManyArgs(c *gc.C) {
+	ctrl, command := s.newCommand(c, newAbortModelUpgradeCommand)
+	defer ctrl.Finish()
+
+	_, err := cmdtesting.RunCommand(c, command, "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/names/v5"
+	"github.com/juju/naturalsort"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/client/modelconfig"
@@ -18,6 +19,7 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/bootstrap"
@@ -316,6 +318,10 @@ type ShowControllerDetails struct {
 	// Nodes is a collection of all k8s pods forming the controller cluster.
 	Nodes map[string]MachineDetails `yaml:"controller-nodes,omitempty" json:"controller-nodes,omitempty"`
 
+	// ZonePlacement describes how the controller machines are spread
+	// across availability zones.
+	ZonePlacement *ZonePlacementDetails `yaml:"zone-placement,omitempty" json:"zone-placement,omitempty"`
+
 	// Models is a collection of all models for this controller.
 	Models map[string]ModelDetails `yaml:"models,omitempty" json:"models,omitempty"`
 
@@ -383,6 +389,20 @@ type MachineDetails struct {
 
 	// HAPrimary is set to true for a primary controller machine in HA.
 	HAPrimary bool `yaml:"ha-primary,omitempty" json:"ha-primary,omitempty"`
+
+	// AvailabilityZone holds the availability zone of the machine.
+	AvailabilityZone string `yaml:"availability-zone,omitempty" json:"availability-zone,omitempty"`
+}
+
+// ZonePlacementDetails holds details of the placement of controller
+// machines across availability zones.
+type ZonePlacementDetails struct {
+	// Zones maps availability zones to the controller machines in them.
+	Zones map[string][]string `yaml:"zones" json:"zones"`
+
+	// SurvivesZoneLoss is true if a majority of the controller machines
+	// remain after the loss of any single availability zone.
+	SurvivesZoneLoss bool `yaml:"survives-zone-loss" json:"survives-zone-loss"`
 }
 
 // ModelDetails holds details of a model to show.
@@ -555,6 +575,11 @@ func (c *showControllerCommand) convertMachinesForShow(
 		}
 		numControllers++
 	}
+	var (
+		zoneMachines = make(map[string][]string)
+		zoneCounts   = make(map[string]int)
+		zonesKnown   bool
+	)
 	for _, m := range controllerModel.Machines {
 		if !m.WantsVote {
 			// Skip non controller machines.
@@ -571,8 +596,28 @@ func (c *showControllerCommand) convertMachinesForShow(
 				details.HAPrimary = *m.HAPrimary
 			}
 		}
+		zone := "(unknown)"
+		if m.Hardware != nil && m.Hardware.AvailabilityZone != nil {
+			details.AvailabilityZone = *m.Hardware.AvailabilityZone
+			zone = details.AvailabilityZone
+			zonesKnown = true
+		}
+		zoneMachines[zone] = append(zoneMachines[zone], m.Id)
+		zoneCounts[zone]++
 		nodes[m.Id] = details
 	}
+
+	// Zone placement is only of interest when there is more
+	// than one controller and the cloud reports zones.
+	if numControllers > 1 && zonesKnown {
+		for _, ids := range zoneMachines {
+			naturalsort.Sort(ids)
+		}
+		controller.ZonePlacement = &ZonePlacementDetails{
+			Zones:            zoneMachines,
+			SurvivesZoneLoss: network.SurvivesZoneLoss(zoneCounts),
+		}
+	}
 }
 
 func haStatus(hasVote bool, wantsVote bool, statusStr string) string {
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api/base"
 	apicontroller "github.com/juju/juju/api/controller/controller"
 	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/jujuclient"
@@ -594,6 +595,75 @@ aws-test:
 	s.assertShowController(c, "aws-test")
 }
 
+func (s *ShowControllerSuite) TestShowControllerZonePlacement(c *gc.C) {
+	_ = s.createTestClientStore(c)
+	s.expectedOutput = `
+aws-test:
+  details:
+    controller-uuid: this-is-the-aws-test-uuid
+    api-endpoints: [this-is-aws-test-of-many-api-endpoints]
+    cloud: aws
+    region: us-east-1
+    agent-version: 999.99.99
+    agent-git-commit: badf00d0badf00d0badf00d0badf00d0badf00d0
+    controller-model-version: 999.99.99
+    mongo-version: 3.5.12
+    ca-cert: this-is-aws-test-ca-cert
+  controller-machines:
+    "0":
+      instance-id: id-0
+      ha-status: ha-pending
+      availability-zone: us-east-1a
+    "1":
+      instance-id: id-1
+      ha-status: down, lost connection
+      availability-zone: us-east-1b
+    "2":
+      instance-id: id-2
+      ha-status: ha-enabled
+      availability-zone: us-east-1a
+  zone-placement:
+    zones:
+      us-east-1a:
+      - "0"
+      - "2"
+      us-east-1b:
+      - "1"
+    survives-zone-loss: false
+  models:
+    controller:
+      model-uuid: ghi
+      machine-count: 2
+      core-count: 4
+  current-model: admin/controller
+  account:
+    user: admin
+    access: superuser
+`[1:]
+
+	for i, zone := range []string{"us-east-1a", "us-east-1b", "us-east-1a"} {
+		s.fakeController.machines["ghi"][i].Hardware = &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		}
+	}
+
+	s.assertShowController(c, "aws-test")
+}
+
+func (s *ShowControllerSuite) TestShowControllerZonePlacementSurvivesZoneLoss(c *gc.C) {
+	_ = s.createTestClientStore(c)
+	for i, zone := range []string{"us-east-1a", "us-east-1b", "us-east-1c"} {
+		s.fakeController.machines["ghi"][i].Hardware = &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		}
+	}
+
+	context, err := s.runShowController(c, "aws-test", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), jc.Contains,
+		`"zone-placement":{"zones":{"us-east-1a":["0"],"us-east-1b":["1"],"us-east-1c":["2"]},"survives-zone-loss":true}`)
+}
+
 func (s *ShowControllerSuite) TestShowControllerPrimaryModelStatusFail(c *gc.C) {
 	_ = s.createTestClientStore(c)
 	s.expectedOutput = `
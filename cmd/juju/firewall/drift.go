@@ -0,0 +1,227 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v5"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/rpc/params"
+)
+
+var firewallDriftHelpSummary = `
+Shows the last firewall drift report for a model.`[1:]
+
+var firewallDriftHelpDetails = `
+The firewaller periodically compares the firewall rules applied by the
+cloud with those Juju expects, when the model config setting
+"` + config.FirewallDriftCheckIntervalKey + `" is set. Rules found on the cloud which Juju
+did not expect, for example those added out-of-band, are shown as
+unexpected; rules Juju expected but which were not found are shown as
+missing.
+
+Depending on the "` + config.FirewallDriftModeKey + `" model config setting, drift
+is either only reported, or also corrected.
+
+This command shows the result of the most recent check.
+`
+
+const firewallDriftHelpExamples = `
+    juju firewall-drift
+    juju firewall-drift --format yaml
+`
+
+// firewallDriftHistorySize is the number of model status history
+// entries searched for the latest drift report.
+const firewallDriftHistorySize = 100
+
+// NewFirewallDriftCommand returns a command to show the last firewall
+// drift report.
+func NewFirewallDriftCommand() cmd.Command {
+	cmd := &firewallDriftCommand{}
+	cmd.newAPIFunc = func() (FirewallDriftAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type firewallDriftCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+	out cmd.Output
+
+	isoTime    bool
+	newAPIFunc func() (FirewallDriftAPI, error)
+}
+
+// FirewallDriftAPI defines the API methods that the firewall drift
+// command uses.
+type FirewallDriftAPI interface {
+	Close() error
+	StatusHistory(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error)
+}
+
+// Info implements cmd.Command.
+func (c *firewallDriftCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "firewall-drift",
+		Purpose:  firewallDriftHelpSummary,
+		Doc:      firewallDriftHelpDetails,
+		Examples: firewallDriftHelpExamples,
+		SeeAlso: []string{
+			"model-config",
+			"show-status-log",
+		},
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *firewallDriftCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatFirewallDriftTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *firewallDriftCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// firewallDriftReport is the serialised form of a firewall drift report.
+type firewallDriftReport struct {
+	Checked   string          `yaml:"checked" json:"checked"`
+	Mode      string          `yaml:"mode" json:"mode"`
+	Corrected bool            `yaml:"corrected" json:"corrected"`
+	Drift     []firewallDrift `yaml:"drift,omitempty" json:"drift,omitempty"`
+}
+
+type firewallDrift struct {
+	Firewall   string   `yaml:"firewall" json:"firewall"`
+	Missing    []string `yaml:"missing,omitempty" json:"missing,omitempty"`
+	Unexpected []string `yaml:"unexpected,omitempty" json:"unexpected,omitempty"`
+}
+
+// Run implements cmd.Command.
+func (c *firewallDriftCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	history, err := client.StatusHistory(
+		status.KindModel,
+		names.NewModelTag(details.ModelUUID),
+		status.StatusHistoryFilter{Size: firewallDriftHistorySize},
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Find the most recent entry holding a drift report.
+	var latest *status.DetailedStatus
+	for i, h := range history {
+		if _, ok := h.Data[params.FirewallDriftDataKey]; !ok || h.Since == nil {
+			continue
+		}
+		if latest == nil || h.Since.After(*latest.Since) {
+			latest = &history[i]
+		}
+	}
+	if latest == nil {
+		return errors.Errorf("no firewall drift report available; drift checks are enabled with %q",
+			config.FirewallDriftCheckIntervalKey)
+	}
+
+	data, _ := latest.Data[params.FirewallDriftDataKey].(string)
+	var report params.FirewallDriftReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return errors.Annotate(err, "cannot parse firewall drift report")
+	}
+
+	out := firewallDriftReport{
+		Checked:   common.FormatTime(latest.Since, c.isoTime),
+		Mode:      report.Mode,
+		Corrected: report.Corrected,
+	}
+	for _, drift := range report.Drift {
+		out.Drift = append(out.Drift, firewallDrift{
+			Firewall:   drift.Target,
+			Missing:    ingressRuleStrings(drift.Missing),
+			Unexpected: ingressRuleStrings(drift.Unexpected),
+		})
+	}
+	return c.out.Write(ctx, out)
+}
+
+func ingressRuleStrings(rules []params.IngressRule) []string {
+	var result []string
+	for _, rule := range rules {
+		result = append(result, firewall.NewIngressRule(rule.PortRange.NetworkPortRange(), rule.SourceCIDRs...).String())
+	}
+	return result
+}
+
+func formatFirewallDriftTabular(writer io.Writer, value interface{}) error {
+	report, ok := value.(firewallDriftReport)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", report, value)
+	}
+	if len(report.Drift) == 0 {
+		_, err := fmt.Fprintf(writer, "No firewall drift found at %s.\n", report.Checked)
+		return err
+	}
+	action := "reported"
+	if report.Corrected {
+		action = "corrected"
+	}
+	if _, err := fmt.Fprintf(writer, "Firewall drift found at %s (%s).\n\n", report.Checked, action); err != nil {
+		return err
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Firewall", "Missing", "Unexpected")
+	for _, drift := range report.Drift {
+		rows := len(drift.Missing)
+		if len(drift.Unexpected) > rows {
+			rows = len(drift.Unexpected)
+		}
+		for i := 0; i < rows; i++ {
+			var target, missing, unexpected string
+			if i == 0 {
+				target = drift.Firewall
+			}
+			if i < len(drift.Missing) {
+				missing = drift.Missing[i]
+			}
+			if i < len(drift.Unexpected) {
+				unexpected = drift.Unexpected[i]
+			}
+			w.Println(target, missing, unexpected)
+		}
+	}
+	return tw.Flush()
+}
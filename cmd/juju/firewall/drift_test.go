@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/names/v5"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing"
+)
+
+type DriftSuite struct {
+	testing.BaseSuite
+
+	mockAPI *mockDriftAPI
+}
+
+var _ = gc.Suite(&DriftSuite{})
+
+func (s *DriftSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	older := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	s.mockAPI = &mockDriftAPI{
+		history: status.History{{
+			Status: status.Available,
+			Info:   "firewall drift: 1 missing and 2 unexpected rules on 2 firewalls",
+			Data: map[string]interface{}{
+				params.FirewallDriftDataKey: `{"mode":"report","drift":[` +
+					`{"target":"global","unexpected":[{"port-range":{"from-port":22,"to-port":22,"protocol":"tcp"},"source-cidrs":["10.0.0.0/8"]}]},` +
+					`{"target":"machine-0","missing":[{"port-range":{"from-port":80,"to-port":80,"protocol":"tcp"},"source-cidrs":["0.0.0.0/0"]}],` +
+					`"unexpected":[{"port-range":{"from-port":53,"to-port":53,"protocol":"udp"},"source-cidrs":["0.0.0.0/0"]}]}]}`,
+			},
+			Since: &newer,
+		}, {
+			Status: status.Available,
+			Info:   "firewall drift: none found",
+			Data: map[string]interface{}{
+				params.FirewallDriftDataKey: `{"mode":"report"}`,
+			},
+			Since: &older,
+		}, {
+			Status: status.Available,
+			Since:  &newer,
+		}},
+	}
+}
+
+func (s *DriftSuite) TestDriftTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, firewall.NewFirewallDriftCommandForTest(s.mockAPI), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Firewall drift found at 2024-03-01 11:00:00Z (reported).
+
+Firewall   Missing  Unexpected
+global              22/tcp from 10.0.0.0/8
+machine-0  80/tcp   53/udp
+`[1:])
+	c.Assert(s.mockAPI.kind, gc.Equals, status.KindModel)
+	c.Assert(s.mockAPI.tag.Kind(), gc.Equals, names.ModelTagKind)
+}
+
+func (s *DriftSuite) TestDriftYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, firewall.NewFirewallDriftCommandForTest(s.mockAPI), "--utc", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+checked: 2024-03-01 11:00:00Z
+mode: report
+corrected: false
+drift:
+- firewall: global
+  unexpected:
+  - 22/tcp from 10.0.0.0/8
+- firewall: machine-0
+  missing:
+  - 80/tcp
+  unexpected:
+  - 53/udp
+`[1:])
+}
+
+func (s *DriftSuite) TestNoDrift(c *gc.C) {
+	s.mockAPI.history = s.mockAPI.history[1:]
+	ctx, err := cmdtesting.RunCommand(c, firewall.NewFirewallDriftCommandForTest(s.mockAPI), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No firewall drift found at 2024-03-01 10:00:00Z.\n")
+}
+
+func (s *DriftSuite) TestNoReport(c *gc.C) {
+	s.mockAPI.history = s.mockAPI.history[2:]
+	_, err := cmdtesting.RunCommand(c, firewall.NewFirewallDriftCommandForTest(s.mockAPI))
+	c.Assert(err, gc.ErrorMatches, `no firewall drift report available; drift checks are enabled with "firewall-drift-check-interval"`)
+}
+
+type mockDriftAPI struct {
+	history status.History
+	kind    status.HistoryKind
+	tag     names.Tag
+}
+
+func (s *mockDriftAPI) Close() error {
+	return nil
+}
+
+func (s *mockDriftAPI) StatusHistory(kind status.HistoryKind, tag names.Tag, filter status.StatusHistoryFilter) (status.History, error) {
+	s.kind = kind
+	s.tag = tag
+	return s.history, nil
+}
@@ -33,3 +33,15 @@ func NewSetRulesCommandForTest(
 	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
 	return modelcmd.Wrap(aCmd)
 }
+
+func NewFirewallDriftCommandForTest(
+	api FirewallDriftAPI,
+) cmd.Command {
+	aCmd := &firewallDriftCommand{
+		newAPIFunc: func() (FirewallDriftAPI, error) {
+			return api, nil
+		},
+	}
+	aCmd.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(aCmd)
+}
@@ -75,6 +75,28 @@ Manual provisioning is the process of installing Juju on an existing machine
 and bringing it under Juju's management. The Juju controller must be able to
 access the new machine over the network.
 
+To enlist several computers at once, list them in a YAML file and pass it
+to the --inventory option. Each entry gives the host's address and,
+optionally, the user, private-key and public-key to connect with, the
+base it is expected to run, and the tags and spaces to record as its
+constraints. Values which aren't specified default to those given by
+--base, --private-key and --public-key. For example:
+
+    hosts:
+      - host: 10.10.0.3
+        user: ubuntu
+        base: ubuntu@22.04
+        tags: [rack1]
+      - host: 10.10.0.4
+        private-key: ~/.ssh/rack2
+        spaces: [storage]
+
+Hosts are provisioned in parallel, up to the number given by --concurrency,
+and a table of results is written once they have all finished. Hosts which
+are already enlisted are skipped, so an inventory can be applied again after
+fixing any failures. Provisioning isn't interactive: each host must accept
+the SSH key and allow the user to use sudo without a password.
+
 
 Container creation
 
@@ -141,6 +163,10 @@ Allocate a machine specifying a public key to set in the list of authorized keys
 
 	juju add-machine ssh:user@10.10.0.3 --public-key /tmp/id_rsa.pub --private-key /tmp/id_rsa
 	
+Allocate the machines listed in an inventory file, eight at a time:
+
+	juju add-machine --inventory hosts.yaml --concurrency 8
+	
 Allocate a machine to the model. Note: specific to MAAS.
 
 	juju add-machine host.internal
@@ -178,12 +204,17 @@ type addCommand struct {
 	// PublicKey is the path for a file containing a public key required
 	// by the server
 	PublicKey string
+	// Inventory is the path for a file listing hosts to be manually
+	// provisioned.
+	Inventory string
+	// Concurrency is the number of inventory hosts provisioned at once.
+	Concurrency int
 }
 
 func (c *addCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:     "add-machine",
-		Args:     "[<container-type>[:<machine-id>] | ssh:[<user>@]<host> | <placement>] | <private-key> | <public-key>",
+		Args:     "[<container-type>[:<machine-id>] | ssh:[<user>@]<host> | <placement>] | <private-key> | <public-key> | --inventory <file>",
 		Purpose:  "Provision a new machine or assign one to the model.",
 		Doc:      addMachineDoc,
 		Examples: addMachineExamples,
@@ -204,6 +235,8 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(disksFlag{&c.Disks}, "disks", "Storage constraints for disks to attach to the machine(s)")
 	f.StringVar(&c.PrivateKey, "private-key", "", "Path to the private key to use during the connection")
 	f.StringVar(&c.PublicKey, "public-key", "", "Path to the public key to add to the remote authorized keys")
+	f.StringVar(&c.Inventory, "inventory", "", "Path to a YAML file listing hosts to provision manually")
+	f.IntVar(&c.Concurrency, "concurrency", defaultInventoryConcurrency, "The number of inventory hosts to provision at once")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -217,6 +250,9 @@ func (c *addCommand) Init(args []string) error {
 	if err != nil {
 		return err
 	}
+	if c.Inventory != "" {
+		return c.initInventory(placement)
+	}
 	c.Placement, err = instance.ParsePlacement(placement)
 	if err == instance.ErrPlacementScopeMissing {
 		placement = "model-uuid" + ":" + placement
@@ -333,6 +369,10 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.Inventory != "" {
+		return c.enlistInventory(machineManager, cfg, ctx)
+	}
+
 	if c.Placement != nil {
 		err := c.tryManualProvision(machineManager, cfg, ctx)
 		if err != errNonManualScope {
@@ -253,6 +253,7 @@ type fakeAddMachineAPI struct {
 	addError         error
 	addModelGetError error
 	providerType     string
+	script           string
 }
 
 func (f *fakeAddMachineAPI) Close() error {
@@ -291,6 +292,9 @@ func (f *fakeAddMachineAPI) DestroyMachinesWithParams(force, keep, dryRun bool,
 }
 
 func (f *fakeAddMachineAPI) ProvisioningScript(params.ProvisioningScriptParams) (script string, err error) {
+	if f.script != "" {
+		return f.script, nil
+	}
 	return "", errors.NotImplementedf("ProvisioningScript")
 }
 
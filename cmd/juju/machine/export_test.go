@@ -75,6 +75,14 @@ func NewUpgradeMachineCommandForTest(statusAPI StatusAPI, upgradeAPI UpgradeMach
 	return modelcmd.Wrap(command)
 }
 
+// NewShowMachineUserDataCommandForTest returns a show machine userdata
+// command with the specified api.
+func NewShowMachineUserDataCommandForTest(api MachineUserDataAPI) cmd.Command {
+	command := &showMachineUserDataCommand{api: api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -0,0 +1,250 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/output"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/rpc/params"
+)
+
+// defaultInventoryConcurrency is the number of hosts in an inventory
+// which are provisioned at the same time, unless overridden.
+const defaultInventoryConcurrency = 4
+
+// inventory describes the hosts to enlist as manual machines.
+type inventory struct {
+	Hosts []inventoryHost `yaml:"hosts"`
+}
+
+// inventoryHost describes a host to enlist. The command's --base,
+// --private-key and --public-key options are used for any values
+// which aren't specified.
+type inventoryHost struct {
+	Host       string   `yaml:"host"`
+	User       string   `yaml:"user,omitempty"`
+	PrivateKey string   `yaml:"private-key,omitempty"`
+	PublicKey  string   `yaml:"public-key,omitempty"`
+	Base       string   `yaml:"base,omitempty"`
+	Tags       []string `yaml:"tags,omitempty"`
+	Spaces     []string `yaml:"spaces,omitempty"`
+}
+
+// initInventory validates the options given with --inventory.
+func (c *addCommand) initInventory(placement string) error {
+	if placement != "" {
+		return errors.New("cannot specify a placement directive with --inventory")
+	}
+	if c.NumMachines != 1 {
+		return errors.New("cannot use -n with --inventory")
+	}
+	if len(c.ConstraintsStr) > 0 {
+		return errors.New("cannot use --constraints with --inventory, specify tags and spaces in the inventory")
+	}
+	if len(c.Disks) > 0 {
+		return errors.New("cannot use --disks with --inventory")
+	}
+	if c.Concurrency < 1 {
+		return errors.Errorf("--concurrency must be at least 1, got %d", c.Concurrency)
+	}
+	return nil
+}
+
+// readInventory reads and validates the inventory file at path.
+func readInventory(path string) (*inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading inventory")
+	}
+	var inv inventory
+	if err := yaml.UnmarshalStrict(data, &inv); err != nil {
+		return nil, errors.Annotatef(err, "parsing inventory %q", path)
+	}
+	if len(inv.Hosts) == 0 {
+		return nil, errors.NotValidf("inventory %q with no hosts", path)
+	}
+	seen := set.NewStrings()
+	for i, host := range inv.Hosts {
+		if host.Host == "" {
+			return nil, errors.NotValidf("inventory host %d with no address", i+1)
+		}
+		if seen.Contains(host.Host) {
+			return nil, errors.NotValidf("inventory with duplicate host %q", host.Host)
+		}
+		seen.Add(host.Host)
+		if host.Base != "" {
+			if _, err := corebase.ParseBaseFromString(host.Base); err != nil {
+				return nil, errors.Annotatef(err, "host %q", host.Host)
+			}
+		}
+	}
+	return &inv, nil
+}
+
+// constraints returns the constraints to record against the host's machine.
+func (h inventoryHost) constraints() constraints.Value {
+	var cons constraints.Value
+	if len(h.Tags) > 0 {
+		tags := h.Tags
+		cons.Tags = &tags
+	}
+	if len(h.Spaces) > 0 {
+		spaces := h.Spaces
+		cons.Spaces = &spaces
+	}
+	return cons
+}
+
+const (
+	enlistResultEnlisted = "enlisted"
+	enlistResultSkipped  = "skipped"
+	enlistResultFailed   = "failed"
+)
+
+// enlistResult records the outcome of enlisting a single host.
+type enlistResult struct {
+	Host    string
+	Machine string
+	Result  string
+	Message string
+}
+
+// enlistInventory provisions each host in the inventory as a manual
+// machine, running at most c.Concurrency provisioners at a time. Hosts
+// which already run a machine agent are skipped. A table of results is
+// written to stdout.
+func (c *addCommand) enlistInventory(client manual.ProvisioningClientAPI, cfg *config.Config, ctx *cmd.Context) error {
+	inv, err := readInventory(ctx.AbsPath(c.Inventory))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Read the authorized keys for each public key up front, so that
+	// any problem with them is reported before provisioning starts.
+	authKeys := make(map[string]string)
+	for _, host := range inv.Hosts {
+		publicKey := c.inventoryValue(host.PublicKey, c.PublicKey)
+		if _, ok := authKeys[publicKey]; ok {
+			continue
+		}
+		if authKeys[publicKey], err = common.ReadAuthorizedKeys(ctx, publicKey); err != nil {
+			return errors.Annotatef(err, "cannot read authorized-keys for host %q", host.Host)
+		}
+	}
+
+	results := make([]enlistResult, len(inv.Hosts))
+	sem := make(chan struct{}, c.Concurrency)
+	var wg sync.WaitGroup
+	for i, host := range inv.Hosts {
+		wg.Add(1)
+		go func(i int, host inventoryHost) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = c.enlistHost(client, cfg, host, authKeys[c.inventoryValue(host.PublicKey, c.PublicKey)])
+		}(i, host)
+	}
+	wg.Wait()
+
+	if err := writeEnlistResults(ctx.Stdout, results); err != nil {
+		return errors.Trace(err)
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Result == enlistResultFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to enlist %d of %d hosts", failed, len(results))
+	}
+	return nil
+}
+
+// enlistHost provisions a single host from the inventory. Provisioning
+// isn't interactive, so hosts must accept the SSH key and allow
+// passwordless sudo. Progress output is logged rather than written to
+// the terminal, where the output for several hosts would be interleaved.
+func (c *addCommand) enlistHost(
+	client manual.ProvisioningClientAPI, cfg *config.Config, host inventoryHost, authKeys string,
+) enlistResult {
+	result := enlistResult{Host: host.Host}
+
+	var base corebase.Base
+	if baseStr := c.inventoryValue(host.Base, c.Base); baseStr != "" {
+		var err error
+		if base, err = corebase.ParseBaseFromString(baseStr); err != nil {
+			result.Result = enlistResultFailed
+			result.Message = err.Error()
+			return result
+		}
+	}
+
+	var progress bytes.Buffer
+	machineId, err := sshProvisioner(manual.ProvisionMachineArgs{
+		Host:           host.Host,
+		User:           host.User,
+		Client:         client,
+		Stdin:          strings.NewReader(""),
+		Stdout:         &progress,
+		Stderr:         &progress,
+		AuthorizedKeys: authKeys,
+		PrivateKey:     c.inventoryValue(host.PrivateKey, c.PrivateKey),
+		Base:           base,
+		Constraints:    host.constraints(),
+		UpdateBehavior: &params.UpdateBehavior{
+			EnableOSRefreshUpdate: cfg.EnableOSRefreshUpdate(),
+			EnableOSUpgrade:       cfg.EnableOSUpgrade(),
+		},
+	})
+	if progress.Len() > 0 {
+		logger.Debugf("provisioning %s:\n%s", host.Host, progress.String())
+	}
+	switch {
+	case errors.Is(err, manual.ErrProvisioned):
+		result.Result = enlistResultSkipped
+		result.Message = "already enlisted"
+	case err != nil:
+		result.Result = enlistResultFailed
+		result.Message = err.Error()
+	default:
+		result.Machine = machineId
+		result.Result = enlistResultEnlisted
+	}
+	return result
+}
+
+// inventoryValue returns the value from the inventory, or the value of
+// the corresponding command line option if it isn't set.
+func (c *addCommand) inventoryValue(value, option string) string {
+	if value != "" {
+		return value
+	}
+	return option
+}
+
+func writeEnlistResults(writer io.Writer, results []enlistResult) error {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("Host", "Machine", "Result", "Message")
+	for _, result := range results {
+		w.Println(result.Host, result.Machine, result.Result, result.Message)
+	}
+	return tw.Flush()
+}
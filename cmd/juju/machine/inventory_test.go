@@ -0,0 +1,231 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/machine"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs/manual"
+	sshtesting "github.com/juju/juju/environs/manual/sshprovisioner/testing"
+	"github.com/juju/juju/testing"
+)
+
+type InventorySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fakeAddMachine *fakeAddMachineAPI
+}
+
+var _ = gc.Suite(&InventorySuite{})
+
+func (s *InventorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fakeAddMachine = &fakeAddMachineAPI{}
+}
+
+func (s *InventorySuite) writeInventory(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "hosts.yaml")
+	err := os.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *InventorySuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	add, _ := machine.NewAddCommandForTest(s.fakeAddMachine, s.fakeAddMachine)
+	return cmdtesting.RunCommand(c, add, args...)
+}
+
+func (s *InventorySuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		args:        []string{"--inventory", "hosts.yaml", "ssh:10.0.0.1"},
+		errorString: "cannot specify a placement directive with --inventory",
+	}, {
+		args:        []string{"--inventory", "hosts.yaml", "-n", "2"},
+		errorString: "cannot use -n with --inventory",
+	}, {
+		args:        []string{"--inventory", "hosts.yaml", "--constraints", "mem=8G"},
+		errorString: "cannot use --constraints with --inventory, specify tags and spaces in the inventory",
+	}, {
+		args:        []string{"--inventory", "hosts.yaml", "--disks", "1G"},
+		errorString: "cannot use --disks with --inventory",
+	}, {
+		args:        []string{"--inventory", "hosts.yaml", "--concurrency", "0"},
+		errorString: "--concurrency must be at least 1, got 0",
+	}} {
+		c.Logf("test %d", i)
+		wrappedCommand, _ := machine.NewAddCommandForTest(s.fakeAddMachine, s.fakeAddMachine)
+		err := cmdtesting.InitCommand(wrappedCommand, test.args)
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+}
+
+func (s *InventorySuite) TestInvalidInventory(c *gc.C) {
+	for i, test := range []struct {
+		content     string
+		errorString string
+	}{{
+		content:     "hosts: []",
+		errorString: `inventory ".*" with no hosts not valid`,
+	}, {
+		content:     "hosts:\n- user: ubuntu",
+		errorString: `inventory host 1 with no address not valid`,
+	}, {
+		content:     "hosts:\n- host: 10.0.0.1\n- host: 10.0.0.1",
+		errorString: `inventory with duplicate host "10.0.0.1" not valid`,
+	}, {
+		content:     "hosts:\n- host: 10.0.0.1\n  base: foo",
+		errorString: `host "10.0.0.1": .*`,
+	}, {
+		content:     "hosts:\n- host: 10.0.0.1\n  password: secret",
+		errorString: `(?s)parsing inventory .*field password not found.*`,
+	}} {
+		c.Logf("test %d", i)
+		s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+			c.Fatalf("unexpected provisioning of %q", args.Host)
+			return "", nil
+		})
+		_, err := s.run(c, "--inventory", s.writeInventory(c, test.content))
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+}
+
+func (s *InventorySuite) TestEnlist(c *gc.C) {
+	var (
+		mu   sync.Mutex
+		args = make(map[string]manual.ProvisionMachineArgs)
+	)
+	s.PatchValue(machine.SSHProvisioner, func(a manual.ProvisionMachineArgs) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		args[a.Host] = a
+		switch a.Host {
+		case "10.0.0.2":
+			return "", manual.ErrProvisioned
+		case "10.0.0.3":
+			return "", errors.New("connection refused")
+		}
+		return "7", nil
+	})
+	path := s.writeInventory(c, `
+hosts:
+  - host: 10.0.0.1
+    user: admin
+    private-key: /keys/rack1
+    base: ubuntu@20.04
+    tags: [rack1, fast]
+    spaces: [storage]
+  - host: 10.0.0.2
+  - host: 10.0.0.3
+`[1:])
+	ctx, err := s.run(c, "--inventory", path, "--base", "ubuntu@22.04", "--private-key", "/keys/default")
+	c.Assert(err, gc.ErrorMatches, "failed to enlist 1 of 3 hosts")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Host      Machine  Result    Message
+10.0.0.1  7        enlisted  
+10.0.0.2           skipped   already enlisted
+10.0.0.3           failed    connection refused
+`[1:])
+
+	c.Assert(args, gc.HasLen, 3)
+	first := args["10.0.0.1"]
+	c.Check(first.User, gc.Equals, "admin")
+	c.Check(first.PrivateKey, gc.Equals, "/keys/rack1")
+	c.Check(first.Base, jc.DeepEquals, corebase.MustParseBaseFromString("ubuntu@20.04"))
+	c.Check(first.Constraints, jc.DeepEquals, constraints.MustParse("tags=rack1,fast spaces=storage"))
+	second := args["10.0.0.2"]
+	c.Check(second.User, gc.Equals, "")
+	c.Check(second.PrivateKey, gc.Equals, "/keys/default")
+	c.Check(second.Base, jc.DeepEquals, corebase.MustParseBaseFromString("ubuntu@22.04"))
+	c.Check(second.Constraints, jc.DeepEquals, constraints.Value{})
+}
+
+func (s *InventorySuite) TestConcurrencyLimit(c *gc.C) {
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+		provisioned       []string
+	)
+	s.PatchValue(machine.SSHProvisioner, func(a manual.ProvisionMachineArgs) (string, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(testing.ShortWait)
+		mu.Lock()
+		inFlight--
+		provisioned = append(provisioned, a.Host)
+		mu.Unlock()
+		return "0", nil
+	})
+	path := s.writeInventory(c, `
+hosts:
+  - host: 10.0.0.1
+  - host: 10.0.0.2
+  - host: 10.0.0.3
+  - host: 10.0.0.4
+  - host: 10.0.0.5
+`[1:])
+	_, err := s.run(c, "--inventory", path, "--concurrency", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(provisioned, jc.SameContents, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"})
+	c.Check(maxSeen <= 2, jc.IsTrue, gc.Commentf("%d hosts provisioned at once", maxSeen))
+}
+
+func (s *InventorySuite) TestEnlistWithFakeSSH(c *gc.C) {
+	base := corebase.MustParseBaseFromString("ubuntu@22.04")
+	s.fakeAddMachine.script = "echo provisioned"
+	path := s.writeInventory(c, `
+hosts:
+  - host: 10.0.0.1
+    user: ubuntu
+    tags: [rack1]
+`[1:])
+
+	restore := sshtesting.FakeSSH{
+		Base:           base,
+		InitUbuntuUser: true,
+	}.Install(c)
+	ctx, err := s.run(c, "--inventory", path, "--base", base.String())
+	restore.Restore()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Host      Machine  Result    Message
+10.0.0.1  0        enlisted  
+`[1:])
+	c.Assert(s.fakeAddMachine.args, gc.HasLen, 1)
+	c.Check(s.fakeAddMachine.args[0].Constraints, jc.DeepEquals, constraints.MustParse("tags=rack1"))
+
+	// Applying the inventory again skips the host, which now has a
+	// machine agent.
+	restore = sshtesting.FakeSSH{
+		Provisioned:        true,
+		InitUbuntuUser:     true,
+		SkipDetection:      true,
+		SkipProvisionAgent: true,
+	}.Install(c)
+	ctx, err = s.run(c, "--inventory", path)
+	restore.Restore()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Host      Machine  Result   Message
+10.0.0.1           skipped  already enlisted
+`[1:])
+	c.Check(s.fakeAddMachine.args, gc.HasLen, 1)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/cmd/juju/machine (interfaces: MachineUserDataAPI)
+//
+// Generated by this command:
+//
+//	mockgen -package mocks -destination mocks/machineuserdata_api_mock.go github.com/juju/juju/cmd/juju/machine MachineUserDataAPI
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	params "github.com/juju/juju/rpc/params"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMachineUserDataAPI is a mock of MachineUserDataAPI interface.
+type MockMachineUserDataAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockMachineUserDataAPIMockRecorder
+}
+
+// MockMachineUserDataAPIMockRecorder is the mock recorder for MockMachineUserDataAPI.
+type MockMachineUserDataAPIMockRecorder struct {
+	mock *MockMachineUserDataAPI
+}
+
+// NewMockMachineUserDataAPI creates a new mock instance.
+func NewMockMachineUserDataAPI(ctrl *gomock.Controller) *MockMachineUserDataAPI {
+	mock := &MockMachineUserDataAPI{ctrl: ctrl}
+	mock.recorder = &MockMachineUserDataAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMachineUserDataAPI) EXPECT() *MockMachineUserDataAPIMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockMachineUserDataAPI) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockMachineUserDataAPIMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMachineUserDataAPI)(nil).Close))
+}
+
+// MachineUserData mocks base method.
+func (m *MockMachineUserDataAPI) MachineUserData(arg0 ...params.MachineUserDataParam) ([]params.MachineUserDataResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MachineUserData", varargs...)
+	ret0, _ := ret[0].([]params.MachineUserDataResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MachineUserData indicates an expected call of MachineUserData.
+func (mr *MockMachineUserDataAPIMockRecorder) MachineUserData(arg0 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MachineUserData", reflect.TypeOf((*MockMachineUserDataAPI)(nil).MachineUserData), arg0...)
+}
@@ -14,6 +14,7 @@ import (
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/status_api_mock.go github.com/juju/juju/cmd/juju/machine StatusAPI
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/removemachine_api_mock.go github.com/juju/juju/cmd/juju/machine RemoveMachineAPI
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/modelconfig_api_mock.go github.com/juju/juju/cmd/juju/machine ModelConfigAPI
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/machineuserdata_api_mock.go github.com/juju/juju/cmd/juju/machine MachineUserDataAPI
 
 // None of the tests in this package require mongo.
 
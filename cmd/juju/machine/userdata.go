@@ -0,0 +1,227 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v5"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/juju/juju/api/client/machinemanager"
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/rpc/params"
+)
+
+// NewShowMachineUserDataCommand returns a command which shows the
+// userdata a provider would receive for a machine.
+func NewShowMachineUserDataCommand() cmd.Command {
+	return modelcmd.Wrap(&showMachineUserDataCommand{})
+}
+
+// MachineUserDataAPI previews the userdata of machines.
+type MachineUserDataAPI interface {
+	MachineUserData(...params.MachineUserDataParam) ([]params.MachineUserDataResult, error)
+	Close() error
+}
+
+// showMachineUserDataCommand shows the userdata of an existing or new
+// machine.
+type showMachineUserDataCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	api MachineUserDataAPI
+
+	machineId      string
+	base           string
+	constraintsStr common.ConstraintsFlag
+	diff           bool
+	raw            bool
+}
+
+const showMachineUserDataDoc = `
+Show the userdata Juju passes to the provider when starting an instance
+for a machine, without starting one or changing the machine.
+
+The userdata includes the model's cloudinit-userdata. With --diff, only
+the changes cloudinit-userdata makes to the userdata Juju generates are
+shown, as a unified diff.
+
+Without a machine, the userdata of the machine add-machine would add is
+shown, using the --base and --constraints options.
+
+Userdata in the cloud-config format is checked against the cloud-init
+schema, and the command fails listing any violations. The agent password
+and nonce are shown as placeholders. Network configuration which some
+providers add when starting an instance is not included.
+
+Userdata which the provider compresses or encodes is shown decoded,
+unless --raw is specified.
+`
+
+const showMachineUserDataExamples = `
+    juju show-machine-userdata 0
+    juju show-machine-userdata 0 --diff
+    juju show-machine-userdata --base ubuntu@22.04 --constraints arch=arm64
+    juju show-machine-userdata 0 --raw > userdata.bin
+`
+
+// Info implements cmd.Command.
+func (c *showMachineUserDataCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "show-machine-userdata",
+		Args:     "[<machine>]",
+		Purpose:  "Show the userdata passed to the provider for a machine.",
+		Doc:      showMachineUserDataDoc,
+		Examples: showMachineUserDataExamples,
+		SeeAlso: []string{
+			"add-machine",
+			"model-config",
+			"show-machine",
+		},
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *showMachineUserDataCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.base, "base", "", "The operating system base of the new machine")
+	f.Var(&c.constraintsStr, "constraints", "Constraints of the new machine")
+	f.BoolVar(&c.diff, "diff", false, "Show the changes made by the model's cloudinit-userdata")
+	f.BoolVar(&c.raw, "raw", false, "Show the userdata as encoded for the provider")
+}
+
+// Init implements cmd.Command.
+func (c *showMachineUserDataCommand) Init(args []string) error {
+	machineId, err := cmd.ZeroOrOneArgs(args)
+	if err != nil {
+		return err
+	}
+	if machineId != "" {
+		if !names.IsValidMachine(machineId) {
+			return errors.NotValidf("machine ID %q", machineId)
+		}
+		if c.base != "" || len(c.constraintsStr) > 0 {
+			return errors.New("--base and --constraints apply only to a new machine")
+		}
+	}
+	if c.diff && c.raw {
+		return errors.New("--diff and --raw cannot be specified together")
+	}
+	c.machineId = machineId
+	return nil
+}
+
+func (c *showMachineUserDataCommand) getAPI() (MachineUserDataAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *showMachineUserDataCommand) Run(ctx *cmd.Context) error {
+	var arg params.MachineUserDataParam
+	if c.machineId != "" {
+		arg.MachineTag = names.NewMachineTag(c.machineId).String()
+	}
+	if c.base != "" {
+		base, err := corebase.ParseBaseFromString(c.base)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		arg.Base = &params.Base{Name: base.OS, Channel: base.Channel.String()}
+	}
+	cons, err := common.ParseConstraints(ctx, strings.Join(c.constraintsStr, " "))
+	if err != nil {
+		return err
+	}
+	arg.Constraints = cons
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	results, err := client.MachineUserData(arg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+
+	userData, err := cloudinit.DecodeUserData(result.UserData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case c.raw:
+		_, err = ctx.Stdout.Write(result.UserData)
+	case c.diff:
+		err = c.writeDiff(ctx, result.BaseUserData, userData)
+	default:
+		_, err = ctx.Stdout.Write(userData)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = cloudinit.ValidateUserData(userData)
+	if errors.Is(err, errors.NotSupported) {
+		ctx.Infof("userdata is not cloud-config, not validating it")
+		return nil
+	}
+	if _, ok := err.(*cloudinit.SchemaError); ok {
+		// The violations are reported as they are.
+		return err
+	}
+	return errors.Trace(err)
+}
+
+// writeDiff writes the differences between the userdata without and
+// with the model's cloudinit-userdata.
+func (c *showMachineUserDataCommand) writeDiff(ctx *cmd.Context, baseUserData, userData []byte) error {
+	baseUserData, err := cloudinit.DecodeUserData(baseUserData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(baseUserData),
+		B:        splitLines(userData),
+		FromFile: "generated",
+		ToFile:   "with cloudinit-userdata",
+		Context:  3,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if diff == "" {
+		ctx.Infof("cloudinit-userdata does not change the userdata")
+		return nil
+	}
+	_, err = fmt.Fprint(ctx.Stdout, diff)
+	return errors.Trace(err)
+}
+
+// splitLines splits text into lines for diffing. Unlike
+// difflib.SplitLines, a trailing newline doesn't add an empty line.
+func splitLines(text []byte) []string {
+	return difflib.SplitLines(strings.TrimSuffix(string(text), "\n"))
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/v3"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/machine/mocks"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/rpc/params"
+)
+
+type ShowMachineUserDataSuite struct {
+	testing.IsolationSuite
+
+	api *mocks.MockMachineUserDataAPI
+}
+
+var _ = gc.Suite(&ShowMachineUserDataSuite{})
+
+const (
+	baseUserData = "#cloud-config\npackage_update: true\nruncmd:\n- install-agent\n"
+	userData     = "#cloud-config\npackage_update: true\npackages:\n- jq\nruncmd:\n- install-agent\n"
+)
+
+func (s *ShowMachineUserDataSuite) setup(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.api = mocks.NewMockMachineUserDataAPI(ctrl)
+	s.api.EXPECT().Close().Return(nil).AnyTimes()
+	return ctrl
+}
+
+func (s *ShowMachineUserDataSuite) expectUserData(arg params.MachineUserDataParam, userData, baseUserData []byte) {
+	s.api.EXPECT().MachineUserData(arg).Return([]params.MachineUserDataResult{{
+		UserData:     userData,
+		BaseUserData: baseUserData,
+	}}, nil)
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserData(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	encoded := renderers.ToBase64(utils.Gzip([]byte(userData)))
+	s.expectUserData(params.MachineUserDataParam{MachineTag: "machine-0"}, encoded, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, userData)
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserDataRaw(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	encoded := renderers.ToBase64(utils.Gzip([]byte(userData)))
+	s.expectUserData(params.MachineUserDataParam{MachineTag: "machine-0"}, encoded, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api), "0", "--raw")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, string(encoded))
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserDataDiff(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectUserData(params.MachineUserDataParam{MachineTag: "machine-0"}, []byte(userData), []byte(baseUserData))
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api), "0", "--diff")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+--- generated
++++ with cloudinit-userdata
+@@ -1,4 +1,6 @@
+ #cloud-config
+ package_update: true
++packages:
++- jq
+ runcmd:
+ - install-agent
+`[1:])
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserDataNewMachine(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectUserData(params.MachineUserDataParam{
+		Base:        &params.Base{Name: "ubuntu", Channel: "22.04/stable"},
+		Constraints: constraints.MustParse("arch=arm64"),
+	}, []byte(userData), nil)
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api),
+		"--base", "ubuntu@22.04", "--constraints", "arch=arm64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, userData)
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserDataInvalid(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectUserData(params.MachineUserDataParam{MachineTag: "machine-0"},
+		[]byte("#cloud-config\npackage_update: yes please\n"), nil)
+
+	_, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api), "0")
+	c.Assert(err, gc.ErrorMatches, `
+userdata does not conform to the cloud-init schema:
+  package_update: must be of type boolean`[1:])
+}
+
+func (s *ShowMachineUserDataSuite) TestShowMachineUserDataScript(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.expectUserData(params.MachineUserDataParam{MachineTag: "machine-0"}, []byte("#!/bin/bash\n"), nil)
+
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowMachineUserDataCommandForTest(s.api), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "#!/bin/bash\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "userdata is not cloud-config, not validating it\n")
+}
+
+func (s *ShowMachineUserDataSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"0", "--base", "ubuntu@22.04"},
+		err:  "--base and --constraints apply only to a new machine",
+	}, {
+		args: []string{"0", "--diff", "--raw"},
+		err:  "--diff and --raw cannot be specified together",
+	}, {
+		args: []string{"foo"},
+		err:  `machine ID "foo" not valid`,
+	}, {
+		args: []string{"0", "1"},
+		err:  `unrecognized args: \["1"\]`,
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(machine.NewShowMachineUserDataCommandForTest(nil), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
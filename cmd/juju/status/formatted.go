@@ -47,6 +47,24 @@ type modelStatus struct {
 	Status           statusInfoContents `json:"model-status,omitempty" yaml:"model-status,omitempty"`
 	MeterStatus      *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 	SLA              string             `json:"sla,omitempty" yaml:"sla,omitempty"`
+
+	ClusterEndpoint           string   `json:"cluster-endpoint,omitempty" yaml:"cluster-endpoint,omitempty"`
+	UnhealthyClusterEndpoints []string `json:"unhealthy-cluster-endpoints,omitempty" yaml:"unhealthy-cluster-endpoints,omitempty"`
+
+	UpgradeRollout *upgradeRollout `json:"upgrade-rollout,omitempty" yaml:"upgrade-rollout,omitempty"`
+
+	UnenforcedEgress []string `json:"unenforced-egress,omitempty" yaml:"unenforced-egress,omitempty"`
+}
+
+type upgradeRollout struct {
+	From     string   `json:"from-version" yaml:"from-version"`
+	To       string   `json:"to-version" yaml:"to-version"`
+	Status   string   `json:"status" yaml:"status"`
+	Message  string   `json:"message,omitempty" yaml:"message,omitempty"`
+	Released int      `json:"released" yaml:"released"`
+	Machines int      `json:"machines" yaml:"machines"`
+	Batch    []string `json:"batch,omitempty" yaml:"batch,omitempty"`
+	Failed   []string `json:"failed,omitempty" yaml:"failed,omitempty"`
 }
 
 type controllerStatus struct {
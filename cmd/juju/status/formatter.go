@@ -88,6 +88,7 @@ func (sf *statusFormatter) Format() (formattedStatus, error) {
 			AvailableVersion: sf.status.Model.AvailableVersion,
 			Status:           sf.getStatusInfoContents(sf.status.Model.ModelStatus),
 			SLA:              sf.status.Model.SLA,
+			UnenforcedEgress: sf.status.Model.UnenforcedEgress,
 		},
 		Machines:           make(map[string]machineStatus),
 		Applications:       make(map[string]applicationStatus),
@@ -96,12 +97,25 @@ func (sf *statusFormatter) Format() (formattedStatus, error) {
 		Relations:          make([]relationStatus, len(sf.relations)),
 		Branches:           make(map[string]branchStatus),
 	}
+	out.Model.ClusterEndpoint, out.Model.UnhealthyClusterEndpoints = clusterEndpoints(sf.status.Model.ModelStatus)
 	if sf.status.Model.MeterStatus.Color != "" {
 		out.Model.MeterStatus = &meterStatus{
 			Color:   sf.status.Model.MeterStatus.Color,
 			Message: sf.status.Model.MeterStatus.Message,
 		}
 	}
+	if r := sf.status.Model.UpgradeRollout; r != nil {
+		out.Model.UpgradeRollout = &upgradeRollout{
+			From:     r.FromVersion,
+			To:       r.ToVersion,
+			Status:   r.Status,
+			Message:  r.Message,
+			Released: r.Released,
+			Machines: r.Machines,
+			Batch:    r.Batch,
+			Failed:   r.Failed,
+		}
+	}
 	if sf.status.ControllerTimestamp != nil {
 		out.Controller = &controllerStatus{
 			Timestamp: common.FormatTimeAsTimestamp(sf.status.ControllerTimestamp, sf.isoTime),
@@ -506,6 +520,22 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 	return out
 }
 
+// clusterEndpoints returns the cluster API endpoint recorded in the model
+// status, along with any endpoints which failed their health check.
+func clusterEndpoints(modelStatus params.DetailedStatus) (string, []string) {
+	endpoint, _ := modelStatus.Data[status.ClusterEndpointKey].(string)
+	var unhealthy []string
+	switch values := modelStatus.Data[status.UnhealthyClusterEndpointsKey].(type) {
+	case []string:
+		unhealthy = values
+	case []interface{}:
+		for _, value := range values {
+			unhealthy = append(unhealthy, fmt.Sprint(value))
+		}
+	}
+	return endpoint, unhealthy
+}
+
 func (sf *statusFormatter) getStatusInfoContents(inst params.DetailedStatus) statusInfoContents {
 	// TODO(perrito66) add status validation.
 	info := statusInfoContents{
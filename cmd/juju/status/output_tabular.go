@@ -586,6 +586,17 @@ func getModelMessage(model modelStatus) string {
 	switch {
 	case model.Status.Message != "":
 		return model.Status.Message
+	case model.UpgradeRollout != nil:
+		r := model.UpgradeRollout
+		message := fmt.Sprintf("upgrading to %s: %d/%d machines %s", r.To, r.Released, r.Machines, r.Status)
+		if r.Message != "" {
+			message += ": " + r.Message
+		}
+		return message
+	case len(model.UnenforcedEgress) > 0:
+		return "egress rules not enforced by cloud: " + strings.Join(model.UnenforcedEgress, ", ")
+	case model.ClusterEndpoint != "" && len(model.UnhealthyClusterEndpoints) > 0:
+		return "using failover endpoint " + model.ClusterEndpoint
 	case model.AvailableVersion != "":
 		return "upgrade available: " + model.AvailableVersion
 	default:
@@ -5473,6 +5473,85 @@ foo/1  active    running     10.0.0.1  80/TCP
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularCAASModelClusterFailover(c *gc.C) {
+	clusterEndpoint, unhealthy := clusterEndpoints(params.DetailedStatus{
+		Status: "available",
+		Data: map[string]interface{}{
+			"cluster-endpoint":            "https://10.0.0.2:6443",
+			"unhealthy-cluster-endpoints": []interface{}{"https://10.0.0.1:6443: connection refused"},
+		},
+	})
+	c.Assert(clusterEndpoint, gc.Equals, "https://10.0.0.2:6443")
+	c.Assert(unhealthy, jc.DeepEquals, []string{"https://10.0.0.1:6443: connection refused"})
+
+	status := formattedStatus{
+		Model: modelStatus{
+			Name:                      "k8s",
+			Type:                      "caas",
+			Controller:                "kontroll",
+			Cloud:                     "microk8s",
+			Version:                   "3.6.0",
+			ClusterEndpoint:           clusterEndpoint,
+			UnhealthyClusterEndpoints: unhealthy,
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model  Controller  Cloud/Region  Version  Notes
+k8s    kontroll    microk8s      3.6.0    using failover endpoint https://10.0.0.2:6443
+`[1:])
+}
+
+func (s *StatusSuite) TestFormatTabularUnenforcedEgress(c *gc.C) {
+	status := formattedStatus{
+		Model: modelStatus{
+			Name:             "default",
+			Type:             "iaas",
+			Controller:       "kontroll",
+			Cloud:            "localhost",
+			Version:          "3.6.0",
+			UnenforcedEgress: []string{"mysql", "wordpress"},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model    Controller  Cloud/Region  Version  Notes
+default  kontroll    localhost     3.6.0    egress rules not enforced by cloud: mysql, wordpress
+`[1:])
+}
+
+func (s *StatusSuite) TestFormatTabularUpgradeRollout(c *gc.C) {
+	status := formattedStatus{
+		Model: modelStatus{
+			Name:       "default",
+			Type:       "iaas",
+			Controller: "kontroll",
+			Cloud:      "dummy",
+			Version:    "3.6.0",
+			UpgradeRollout: &upgradeRollout{
+				From:     "3.6.0",
+				To:       "3.6.1",
+				Status:   "halted",
+				Message:  "1 of 3 upgraded machines reported errors",
+				Released: 3,
+				Machines: 10,
+				Failed:   []string{"2"},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := FormatTabular(out, false, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model    Controller  Cloud/Region  Version  Notes
+default  kontroll    dummy         3.6.0    upgrading to 3.6.1: 3/10 machines halted: 1 of 3 upgraded machines reported errors
+`[1:])
+}
+
 func (s *StatusSuite) TestFormatTabularCAASModelTruncatedVersion(c *gc.C) {
 	status := formattedStatus{
 		Model: modelStatus{
@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/names/v5"
+	"github.com/juju/utils/v3"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/rpc/params"
+)
+
+// NewResizeCommand returns a command used to resize storage.
+func NewResizeCommand() cmd.Command {
+	cmd := &resizeCommand{}
+	cmd.newAPIFunc = func() (StorageResizeAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	resizeCommandDoc = `
+Grows the volume backing a storage instance to the specified size.
+
+Volumes can only be grown, not shrunk, and only storage providers which
+support online expansion can resize a volume. On Kubernetes models the
+storage class used to provision the volume must allow volume expansion.
+
+The size is a positive number followed by a size suffix. Valid suffixes
+include M, G, T, and P. Resizing may complete some time after the command
+returns; 'juju show-storage' reports the size once it has changed.
+`
+	resizeCommandExamples = `
+Grow the pgdata/0 storage instance to 20GiB:
+
+    juju resize-storage pgdata/0 20G
+
+`
+	resizeCommandArgs = `<storage-id> <size>`
+)
+
+// resizeCommand grows the volume backing a storage instance.
+type resizeCommand struct {
+	StorageCommandBase
+	newAPIFunc func() (StorageResizeAPI, error)
+
+	storageId string
+	size      uint64
+}
+
+// Init implements Command.Init.
+func (c *resizeCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("resize-storage requires a storage ID and a size")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	size, err := utils.ParseSize(args[1])
+	if err != nil {
+		return errors.Annotatef(err, "cannot parse size %q", args[1])
+	}
+	if size == 0 {
+		return errors.NotValidf("storage size 0")
+	}
+	c.storageId = args[0]
+	c.size = size
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Info implements Command.Info.
+func (c *resizeCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "resize-storage",
+		Purpose:  "Grows the volume backing a storage instance.",
+		Doc:      resizeCommandDoc,
+		Args:     resizeCommandArgs,
+		Examples: resizeCommandExamples,
+		SeeAlso: []string{
+			"create-storage-snapshot",
+			"show-storage",
+			"storage",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *resizeCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.Resize(c.storageId, c.size); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "resize storage")
+		}
+		return errors.Annotatef(err, "resizing storage %s", c.storageId)
+	}
+	ctx.Infof("resizing storage %s to %s", c.storageId, humanize.IBytes(c.size*humanize.MiByte))
+	return nil
+}
+
+// StorageResizeAPI defines the API methods that the resize-storage
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	Resize(storageId string, size uint64) error
+}
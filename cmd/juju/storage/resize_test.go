@@ -0,0 +1,102 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/rpc/params"
+)
+
+type resizeSuite struct {
+	SubStorageSuite
+	mockAPI *mockResizeAPI
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.mockAPI = &mockResizeAPI{}
+}
+
+func (s *resizeSuite) runResize(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, storage.NewResizeCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *resizeSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args        []string
+		expectedErr string
+	}{{
+		args:        nil,
+		expectedErr: "resize-storage requires a storage ID and a size",
+	}, {
+		args:        []string{"data/0"},
+		expectedErr: "resize-storage requires a storage ID and a size",
+	}, {
+		args:        []string{"data", "10G"},
+		expectedErr: `storage ID "data" not valid`,
+	}, {
+		args:        []string{"data/0", "lots"},
+		expectedErr: `cannot parse size "lots": .*`,
+	}, {
+		args:        []string{"data/0", "0"},
+		expectedErr: `storage size 0 not valid`,
+	}, {
+		args:        []string{"data/0", "10G", "extra"},
+		expectedErr: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, err := s.runResize(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.expectedErr)
+	}
+}
+
+func (s *resizeSuite) TestResize(c *gc.C) {
+	ctx, err := s.runResize(c, "data/0", "10G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageId, gc.Equals, "data/0")
+	c.Assert(s.mockAPI.size, gc.Equals, uint64(10*1024))
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "resizing storage data/0 to 10 GiB\n")
+}
+
+func (s *resizeSuite) TestResizeError(c *gc.C) {
+	s.mockAPI.err = errors.NotSupportedf(`resizing volume with storage provider "loop"`)
+	_, err := s.runResize(c, "data/0", "10G")
+	c.Assert(err, gc.ErrorMatches, `resizing storage data/0: resizing volume with storage provider "loop" not supported`)
+}
+
+func (s *resizeSuite) TestUnauthorizedMentionsJujuGrant(c *gc.C) {
+	s.mockAPI.err = &params.Error{
+		Message: "permission denied",
+		Code:    params.CodeUnauthorized,
+	}
+	ctx, _ := s.runResize(c, "data/0", "10G")
+	errString := strings.Replace(cmdtesting.Stderr(ctx), "\n", " ", -1)
+	c.Assert(errString, gc.Matches, `.*juju grant.*`)
+}
+
+type mockResizeAPI struct {
+	storageId string
+	size      uint64
+	err       error
+}
+
+func (m *mockResizeAPI) Close() error {
+	return nil
+}
+
+func (m *mockResizeAPI) Resize(storageId string, size uint64) error {
+	m.storageId = storageId
+	m.size = size
+	return m.err
+}
@@ -0,0 +1,436 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v5"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/rpc/params"
+)
+
+// StorageSnapshotAPI defines the API methods that the storage snapshot
+// commands use.
+type StorageSnapshotAPI interface {
+	Close() error
+	CreateSnapshot(storageId, name string) (params.StorageSnapshot, error)
+	ListSnapshots(storageIds []string) ([]params.StorageSnapshotsResult, error)
+	ExportSnapshot(storageId, name string) (string, error)
+	RestoreSnapshot(storageId, name string) error
+}
+
+// SnapshotInfo defines the serialization behaviour of a storage snapshot.
+type SnapshotInfo struct {
+	Storage string     `yaml:"storage" json:"storage"`
+	Size    uint64     `yaml:"size,omitempty" json:"size,omitempty"`
+	Ready   bool       `yaml:"ready" json:"ready"`
+	Created *time.Time `yaml:"created,omitempty" json:"created,omitempty"`
+}
+
+// snapshotStorageArgs checks the storage ID and snapshot name
+// arguments shared by the snapshot commands.
+func snapshotStorageArgs(command string, args []string) (string, string, error) {
+	if len(args) < 2 {
+		return "", "", errors.Errorf("%s requires a storage ID and a snapshot name", command)
+	}
+	if !names.IsValidStorage(args[0]) {
+		return "", "", errors.NotValidf("storage ID %q", args[0])
+	}
+	if err := cmd.CheckEmpty(args[2:]); err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return args[0], args[1], nil
+}
+
+// NewCreateSnapshotCommand returns a command used to snapshot storage.
+func NewCreateSnapshotCommand() cmd.Command {
+	cmd := &createSnapshotCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	createSnapshotCommandDoc = `
+Takes a point in time snapshot of the volume backing a storage instance.
+
+Only storage providers which support snapshots can take them. On Kubernetes
+models this uses the CSI VolumeSnapshot API, which requires the snapshot
+controller to be installed in the cluster. The "snapshot-class" attribute
+of the storage pool selects the volume snapshot class to use; if it isn't
+set, the cluster default is used.
+
+Snapshots may take some time before they can be exported; use
+'juju storage-snapshots' to see when a snapshot is ready.
+`
+	createSnapshotCommandExamples = `
+Snapshot the pgdata/0 storage instance:
+
+    juju create-storage-snapshot pgdata/0 before-upgrade
+
+`
+	snapshotCommandArgs = `<storage-id> <snapshot-name>`
+)
+
+// createSnapshotCommand snapshots the volume backing a storage instance.
+type createSnapshotCommand struct {
+	StorageCommandBase
+	newAPIFunc func() (StorageSnapshotAPI, error)
+
+	storageId string
+	name      string
+}
+
+// Init implements Command.Init.
+func (c *createSnapshotCommand) Init(args []string) (err error) {
+	c.storageId, c.name, err = snapshotStorageArgs("create-storage-snapshot", args)
+	return err
+}
+
+// Info implements Command.Info.
+func (c *createSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "create-storage-snapshot",
+		Purpose:  "Takes a snapshot of a storage instance.",
+		Doc:      createSnapshotCommandDoc,
+		Args:     snapshotCommandArgs,
+		Examples: createSnapshotCommandExamples,
+		SeeAlso: []string{
+			"storage-snapshots",
+			"export-storage-snapshot",
+			"restore-storage-snapshot",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *createSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	snapshot, err := api.CreateSnapshot(c.storageId, c.name)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "snapshot storage")
+		}
+		return errors.Annotatef(err, "creating snapshot of storage %s", c.storageId)
+	}
+	ctx.Infof("created snapshot %s of storage %s", snapshot.Name, c.storageId)
+	return nil
+}
+
+// NewListSnapshotsCommand returns a command used to list storage snapshots.
+func NewListSnapshotsCommand() cmd.Command {
+	cmd := &listSnapshotsCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	listSnapshotsCommandDoc = `
+Lists the snapshots taken of the volumes backing the specified storage
+instances.
+`
+	listSnapshotsCommandExamples = `
+    juju storage-snapshots pgdata/0
+    juju storage-snapshots pgdata/0 pgdata/1 --format yaml
+
+`
+)
+
+// listSnapshotsCommand lists the snapshots of storage instances.
+type listSnapshotsCommand struct {
+	StorageCommandBase
+	newAPIFunc func() (StorageSnapshotAPI, error)
+	out        cmd.Output
+
+	storageIds []string
+}
+
+// Init implements Command.Init.
+func (c *listSnapshotsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage-snapshots requires at least one storage ID")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage ID %q", id)
+		}
+	}
+	c.storageIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *listSnapshotsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "storage-snapshots",
+		Purpose:  "Lists snapshots of storage instances.",
+		Doc:      listSnapshotsCommandDoc,
+		Args:     "<storage-id> ...",
+		Aliases:  []string{"list-storage-snapshots"},
+		Examples: listSnapshotsCommandExamples,
+		SeeAlso: []string{
+			"create-storage-snapshot",
+			"export-storage-snapshot",
+			"restore-storage-snapshot",
+		},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listSnapshotsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSnapshotListTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *listSnapshotsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.ListSnapshots(c.storageIds)
+	if err != nil {
+		return err
+	}
+	snapshots := make(map[string]SnapshotInfo)
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Warningf("cannot list snapshots of storage %s: %v", c.storageIds[i], result.Error)
+			continue
+		}
+		for _, one := range result.Result {
+			snapshots[one.Name] = SnapshotInfo{
+				Storage: c.storageIds[i],
+				Size:    one.Size,
+				Ready:   one.Ready,
+				Created: one.Created,
+			}
+		}
+	}
+	if len(snapshots) == 0 {
+		ctx.Infof("No storage snapshots to display.")
+		return nil
+	}
+	return c.out.Write(ctx, snapshots)
+}
+
+// formatSnapshotListTabular writes a tabular summary of storage snapshots.
+func formatSnapshotListTabular(writer io.Writer, value interface{}) error {
+	snapshots, ok := value.(map[string]SnapshotInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", snapshots, value)
+	}
+	tw := output.TabWriter(writer)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("Snapshot", "Storage", "Size", "Ready", "Created")
+	for _, name := range sortedSnapshotNames(snapshots) {
+		snapshot := snapshots[name]
+		size, created := "", ""
+		if snapshot.Size > 0 {
+			size = humanize.IBytes(snapshot.Size * humanize.MiByte)
+		}
+		if snapshot.Created != nil {
+			created = snapshot.Created.UTC().Format(time.RFC3339)
+		}
+		print(name, snapshot.Storage, size, fmt.Sprint(snapshot.Ready), created)
+	}
+	return tw.Flush()
+}
+
+func sortedSnapshotNames(snapshots map[string]SnapshotInfo) []string {
+	result := make([]string, 0, len(snapshots))
+	for name := range snapshots {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// NewExportSnapshotCommand returns a command used to export storage
+// snapshots to new volumes.
+func NewExportSnapshotCommand() cmd.Command {
+	cmd := &exportSnapshotCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	exportSnapshotCommandDoc = `
+Creates a new volume from a snapshot of the volume backing a storage
+instance. The storage instance itself is left untouched.
+
+The new volume is not managed by Juju: it is not a storage instance, so
+it cannot be attached to units with 'juju attach-storage', and it is not
+removed along with the model. It is up to you to use and remove it.
+
+On Kubernetes models a new persistent volume claim, named after the
+original claim and the snapshot, is provisioned from the snapshot in the
+model's namespace. The name of the new claim is printed once it has been
+requested.
+`
+	exportSnapshotCommandExamples = `
+    juju export-storage-snapshot pgdata/0 before-upgrade
+
+`
+)
+
+// exportSnapshotCommand exports a snapshot of a storage instance to a new
+// volume outside of Juju.
+type exportSnapshotCommand struct {
+	StorageCommandBase
+	newAPIFunc func() (StorageSnapshotAPI, error)
+
+	storageId string
+	name      string
+}
+
+// Init implements Command.Init.
+func (c *exportSnapshotCommand) Init(args []string) (err error) {
+	c.storageId, c.name, err = snapshotStorageArgs("export-storage-snapshot", args)
+	return err
+}
+
+// Info implements Command.Info.
+func (c *exportSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "export-storage-snapshot",
+		Purpose:  "Exports a storage snapshot to a new volume not managed by Juju.",
+		Doc:      exportSnapshotCommandDoc,
+		Args:     snapshotCommandArgs,
+		Examples: exportSnapshotCommandExamples,
+		SeeAlso: []string{
+			"create-storage-snapshot",
+			"storage-snapshots",
+			"restore-storage-snapshot",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *exportSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	volumeId, err := api.ExportSnapshot(c.storageId, c.name)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "export storage snapshots")
+		}
+		return errors.Annotatef(err, "exporting snapshot %s of storage %s", c.name, c.storageId)
+	}
+	fmt.Fprintln(ctx.Stdout, volumeId)
+	return nil
+}
+
+// NewRestoreSnapshotCommand returns a command used to restore storage
+// from a snapshot.
+func NewRestoreSnapshotCommand() cmd.Command {
+	cmd := &restoreSnapshotCommand{}
+	cmd.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return cmd.NewStorageAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+const (
+	restoreSnapshotCommandDoc = `
+Replaces the contents of the volume backing a storage instance with a
+snapshot of it. The storage instance stays attached to the same unit.
+Everything written to the storage since the snapshot was taken is lost.
+
+The snapshot must be ready, as reported by 'juju storage-snapshots'.
+
+On Kubernetes models the unit's persistent volume claim is replaced by a
+claim of the same name provisioned from the snapshot, and the unit's pod
+is restarted to use it. The stateful set managing the application's pods
+is recreated while this happens; the application's other pods keep
+running.
+`
+	restoreSnapshotCommandExamples = `
+    juju restore-storage-snapshot pgdata/0 before-upgrade
+
+`
+)
+
+// restoreSnapshotCommand restores a storage instance from a snapshot.
+type restoreSnapshotCommand struct {
+	StorageCommandBase
+	newAPIFunc func() (StorageSnapshotAPI, error)
+
+	storageId string
+	name      string
+}
+
+// Init implements Command.Init.
+func (c *restoreSnapshotCommand) Init(args []string) (err error) {
+	c.storageId, c.name, err = snapshotStorageArgs("restore-storage-snapshot", args)
+	return err
+}
+
+// Info implements Command.Info.
+func (c *restoreSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "restore-storage-snapshot",
+		Purpose:  "Restores a storage instance from a snapshot.",
+		Doc:      restoreSnapshotCommandDoc,
+		Args:     snapshotCommandArgs,
+		Examples: restoreSnapshotCommandExamples,
+		SeeAlso: []string{
+			"create-storage-snapshot",
+			"storage-snapshots",
+			"export-storage-snapshot",
+		},
+	})
+}
+
+// Run implements Command.Run.
+func (c *restoreSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.RestoreSnapshot(c.storageId, c.name); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "restore storage snapshots")
+		}
+		return errors.Annotatef(err, "restoring snapshot %s of storage %s", c.name, c.storageId)
+	}
+	return nil
+}
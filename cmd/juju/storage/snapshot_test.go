@@ -0,0 +1,162 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/rpc/params"
+)
+
+type snapshotSuite struct {
+	SubStorageSuite
+	mockAPI *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.mockAPI = &mockSnapshotAPI{
+		snapshots: map[string][]params.StorageSnapshot{
+			"data/0": {{
+				StorageTag: "storage-data-0",
+				Name:       "snap-1",
+				Size:       1024,
+				Ready:      true,
+				Created:    &created,
+			}, {
+				StorageTag: "storage-data-0",
+				Name:       "snap-2",
+			}},
+		},
+	}
+}
+
+func (s *snapshotSuite) TestCreateSnapshotInitErrors(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewCreateSnapshotCommandForTest(s.mockAPI, s.store), "data/0")
+	c.Assert(err, gc.ErrorMatches, "create-storage-snapshot requires a storage ID and a snapshot name")
+	_, err = cmdtesting.RunCommand(c, storage.NewCreateSnapshotCommandForTest(s.mockAPI, s.store), "data", "snap-1")
+	c.Assert(err, gc.ErrorMatches, `storage ID "data" not valid`)
+}
+
+func (s *snapshotSuite) TestCreateSnapshot(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewCreateSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"CreateSnapshot data/0 snap-3"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "created snapshot snap-3 of storage data/0\n")
+}
+
+func (s *snapshotSuite) TestCreateSnapshotError(c *gc.C) {
+	s.mockAPI.err = errors.NotSupportedf("volume snapshots")
+	_, err := cmdtesting.RunCommand(c, storage.NewCreateSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-3")
+	c.Assert(err, gc.ErrorMatches, "creating snapshot of storage data/0: volume snapshots not supported")
+}
+
+func (s *snapshotSuite) TestListSnapshotsTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListSnapshotsCommandForTest(s.mockAPI, s.store), "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Snapshot  Storage  Size     Ready  Created
+snap-1    data/0   1.0 GiB  true   2024-01-02T03:04:05Z
+snap-2    data/0            false  
+`[1:])
+}
+
+func (s *snapshotSuite) TestListSnapshotsYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListSnapshotsCommandForTest(s.mockAPI, s.store), "data/0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+snap-1:
+  storage: data/0
+  size: 1024
+  ready: true
+  created: 2024-01-02T03:04:05Z
+snap-2:
+  storage: data/0
+  ready: false
+`[1:])
+}
+
+func (s *snapshotSuite) TestListSnapshotsNone(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListSnapshotsCommandForTest(s.mockAPI, s.store), "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No storage snapshots to display.\n")
+}
+
+func (s *snapshotSuite) TestListSnapshotsRequiresStorage(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewListSnapshotsCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, "storage-snapshots requires at least one storage ID")
+}
+
+func (s *snapshotSuite) TestExportSnapshot(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewExportSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"ExportSnapshot data/0 snap-1"})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "data-0-pvc-snap-1\n")
+}
+
+func (s *snapshotSuite) TestExportSnapshotError(c *gc.C) {
+	s.mockAPI.err = errors.NotFoundf(`volume snapshot "snap-9"`)
+	_, err := cmdtesting.RunCommand(c, storage.NewExportSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-9")
+	c.Assert(err, gc.ErrorMatches, `exporting snapshot snap-9 of storage data/0: volume snapshot "snap-9" not found`)
+}
+
+func (s *snapshotSuite) TestRestoreSnapshot(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewRestoreSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"RestoreSnapshot data/0 snap-1"})
+}
+
+func (s *snapshotSuite) TestRestoreSnapshotError(c *gc.C) {
+	s.mockAPI.err = errors.NotFoundf(`volume snapshot "snap-9"`)
+	_, err := cmdtesting.RunCommand(c, storage.NewRestoreSnapshotCommandForTest(s.mockAPI, s.store), "data/0", "snap-9")
+	c.Assert(err, gc.ErrorMatches, `restoring snapshot snap-9 of storage data/0: volume snapshot "snap-9" not found`)
+}
+
+func (s *snapshotSuite) TestRestoreSnapshotInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewRestoreSnapshotCommandForTest(s.mockAPI, s.store), "data/0")
+	c.Assert(err, gc.ErrorMatches, "restore-storage-snapshot requires a storage ID and a snapshot name")
+}
+
+type mockSnapshotAPI struct {
+	snapshots map[string][]params.StorageSnapshot
+	calls     []string
+	err       error
+}
+
+func (m *mockSnapshotAPI) Close() error {
+	return nil
+}
+
+func (m *mockSnapshotAPI) CreateSnapshot(storageId, name string) (params.StorageSnapshot, error) {
+	m.calls = append(m.calls, "CreateSnapshot "+storageId+" "+name)
+	return params.StorageSnapshot{Name: name}, m.err
+}
+
+func (m *mockSnapshotAPI) ListSnapshots(storageIds []string) ([]params.StorageSnapshotsResult, error) {
+	results := make([]params.StorageSnapshotsResult, len(storageIds))
+	for i, id := range storageIds {
+		results[i].Result = m.snapshots[id]
+	}
+	return results, m.err
+}
+
+func (m *mockSnapshotAPI) ExportSnapshot(storageId, name string) (string, error) {
+	m.calls = append(m.calls, "ExportSnapshot "+storageId+" "+name)
+	return "data-0-pvc-" + name, m.err
+}
+
+func (m *mockSnapshotAPI) RestoreSnapshot(storageId, name string) error {
+	m.calls = append(m.calls, "RestoreSnapshot "+storageId+" "+name)
+	return m.err
+}
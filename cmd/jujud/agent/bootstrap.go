@@ -49,6 +49,7 @@ import (
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/tools"
+	"github.com/juju/juju/tools/delta"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/worker/peergrouper"
 )
@@ -538,16 +539,34 @@ func (c *BootstrapCommand) populateTools(st *state.State) error {
 		return errors.Trace(err)
 	}
 
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verifier, err := envtools.NewSignatureVerifier(controllerConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := verifier.Verify(agentTools.URL, bytes.NewReader(data)); err != nil {
+		return errors.Trace(err)
+	}
+
 	toolStorage, err := st.ToolsStorage()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer func() { _ = toolStorage.Close() }()
 
+	archiveSHA256, err := delta.ArchiveSHA256(bytes.NewReader(data))
+	if err != nil {
+		// No deltas are generated to these agent binaries.
+		logger.Warningf("cannot hash agent binaries archive: %v", err)
+	}
 	metadata := binarystorage.Metadata{
-		Version: agentTools.Version.String(),
-		Size:    agentTools.Size,
-		SHA256:  agentTools.SHA256,
+		Version:       agentTools.Version.String(),
+		Size:          agentTools.Size,
+		SHA256:        agentTools.SHA256,
+		ArchiveSHA256: archiveSHA256,
 	}
 	logger.Debugf("Adding agent binary: %v", agentTools.Version)
 	if err := toolStorage.Add(bytes.NewReader(data), metadata); err != nil {
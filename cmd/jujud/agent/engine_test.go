@@ -52,7 +52,8 @@ var (
 		"status-history-pruner", // tertiary dependency: will be inactive because migration workers will be inactive
 		"storage-provisioner",   // tertiary dependency: will be inactive because migration workers will be inactive
 		"undertaker",
-		"unit-assigner", // tertiary dependency: will be inactive because migration workers will be inactive
+		"unit-assigner",   // tertiary dependency: will be inactive because migration workers will be inactive
+		"upgrade-rollout", // tertiary dependency: will be inactive because migration workers will be inactive
 		"secrets-pruner",
 		"user-secrets-drain-worker",
 	}
@@ -78,6 +79,7 @@ var (
 		"status-history-pruner",
 		"storage-provisioner",
 		"unit-assigner",
+		"upgrade-rollout",
 		"secrets-pruner",
 		"user-secrets-drain-worker",
 	}
@@ -130,6 +132,7 @@ var (
 		"logging-config-updater",
 		"lxd-container-provisioner",
 		"kvm-container-provisioner",
+		"nspawn-container-provisioner",
 		"machine-action-runner",
 		//"machine-setup", exits when done
 		"machiner",
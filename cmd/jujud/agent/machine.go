@@ -55,6 +55,7 @@ import (
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/container/broker"
 	"github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/container/nspawn"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/life"
@@ -903,6 +904,14 @@ func (a *MachineAgent) setupContainerSupport(st api.Connection, logger machine.L
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	supportsNspawn, err := nspawn.IsNspawnSupported()
+	if err != nil {
+		logger.Warningf("determining nspawn support: %v\nno nspawn containers possible", err)
+	}
+	if err == nil && supportsNspawn {
+		supportedContainers = append(supportedContainers, instance.NSPAWN)
+	}
 	logger.Debugf("Supported container types %q", supportedContainers)
 
 	if len(supportedContainers) == 0 {
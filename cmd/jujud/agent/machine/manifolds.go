@@ -661,6 +661,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
 			Hub:                               config.CentralHub,
 			Presence:                          config.PresenceRecorder,
+			GetControllerConfig:               apiserver.GetControllerConfig,
 			NewWorker:                         apiserver.NewWorker,
 			NewMetricsCollector:               apiserver.NewMetricsCollector,
 		})),
@@ -979,6 +980,14 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
 			ContainerType:                instance.LXD,
 		})),
+		nspawnContainerProvisioner: ifNotMigrating(provisioner.ContainerProvisioningManifold(provisioner.ContainerManifoldConfig{
+			AgentName:                    agentName,
+			APICallerName:                apiCallerName,
+			Logger:                       loggo.GetLogger("juju.worker.nspawnprovisioner"),
+			MachineLock:                  config.MachineLock,
+			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
+			ContainerType:                instance.NSPAWN,
+		})),
 		// isNotControllerFlagName is only used for the stateconverter,
 		isNotControllerFlagName: isControllerFlagManifold(false),
 		stateConverterName: ifNotController(ifNotMigrating(stateconverter.Manifold(stateconverter.ManifoldConfig{
@@ -1174,6 +1183,7 @@ const (
 	stateConverterName            = "state-converter"
 	lxdContainerProvisioner       = "lxd-container-provisioner"
 	kvmContainerProvisioner       = "kvm-container-provisioner"
+	nspawnContainerProvisioner    = "nspawn-container-provisioner"
 
 	secretBackendRotateName = "secret-backend-rotate"
 
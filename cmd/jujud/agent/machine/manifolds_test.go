@@ -104,6 +104,7 @@ func (s *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"model-cache-initialized-gate",
 			"model-worker-manager",
 			"multiwatcher",
+			"nspawn-container-provisioner",
 			"peer-grouper",
 			"presence",
 			"proxy-config-updater",
@@ -758,6 +759,18 @@ var expectedMachineManifoldsWithDependenciesIAAS = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"nspawn-container-provisioner": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"machine-action-runner": {
 		"agent",
 		"api-caller",
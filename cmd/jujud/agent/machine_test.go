@@ -54,6 +54,7 @@ import (
 	"github.com/juju/juju/cmd/jujud/agent/mocks"
 	"github.com/juju/juju/cmd/jujud/agent/model"
 	"github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/container/nspawn"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/arch"
 	"github.com/juju/juju/core/auditlog"
@@ -1141,9 +1142,11 @@ func (s *MachineSuite) TestMachineWorkers(c *gc.C) {
 	matcher := agenttest.NewWorkerMatcher(c, tracker, a.Tag().String(),
 		append(alwaysMachineWorkers, notMigratingMachineWorkers...))
 
-	// Indicate that this machine supports KVM containers rather than doing
-	// detection that may return true/false based on the machine running tests.
+	// Indicate that this machine supports KVM and nspawn containers rather
+	// than doing detection that may return true/false based on the machine
+	// running tests.
 	s.PatchValue(&kvm.IsKVMSupported, func() (bool, error) { return true, nil })
+	s.PatchValue(&nspawn.IsNspawnSupported, func() (bool, error) { return true, nil })
 
 	agenttest.WaitMatch(c, matcher.Check, coretesting.LongWait)
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/juju/juju/worker/caasapplicationprovisioner"
 	"github.com/juju/juju/worker/caasbroker"
 	"github.com/juju/juju/worker/caasenvironupgrader"
+	"github.com/juju/juju/worker/caasevents"
 	"github.com/juju/juju/worker/caasfirewaller"
 	"github.com/juju/juju/worker/caasfirewallersidecar"
 	"github.com/juju/juju/worker/caasmodelconfigmanager"
@@ -71,6 +72,7 @@ import (
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/undertaker"
 	"github.com/juju/juju/worker/unitassigner"
+	"github.com/juju/juju/worker/upgraderollout"
 )
 
 // ManifoldsConfig holds the dependencies and configuration options for a
@@ -481,6 +483,11 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewClient:     instancemutater.NewClient,
 			NewWorker:     instancemutater.NewEnvironWorker,
 		})),
+		upgradeRolloutName: ifNotMigrating(upgraderollout.Manifold(upgraderollout.ManifoldConfig{
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			Logger:        config.LoggingContext.GetLogger("juju.worker.upgraderollout"),
+		})),
 	}
 
 	result := commonManifolds(config)
@@ -513,6 +520,8 @@ func CAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName:          apiCallerName,
 			NewContainerBrokerFunc: config.NewContainerBrokerFunc,
 			Logger:                 config.LoggingContext.GetLogger("juju.worker.caas"),
+			NewCredentialAPI:       caasbroker.NewCredentialAPI,
+			Clock:                  config.Clock,
 		})),
 
 		caasFirewallerNameLegacy: ifNotMigrating(caasfirewaller.Manifold(
@@ -560,6 +569,15 @@ func CAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Clock:         config.Clock,
 		})),
 
+		caasEventsName: ifNotMigrating(caasevents.Manifold(caasevents.ManifoldConfig{
+			APICallerName: apiCallerName,
+			BrokerName:    caasBrokerTrackerName,
+			Logger:        config.LoggingContext.GetLogger("juju.worker.caasevents"),
+			NewWorker:     caasevents.NewWorker,
+			NewFacade:     caasevents.NewFacade,
+			Clock:         config.Clock,
+		})),
+
 		caasOperatorProvisionerName: ifNotMigrating(caasoperatorprovisioner.Manifold(
 			caasoperatorprovisioner.ManifoldConfig{
 				AgentName:     agentName,
@@ -736,11 +754,13 @@ const (
 	logForwarderName         = "log-forwarder"
 	loggingConfigUpdaterName = "logging-config-updater"
 	instanceMutaterName      = "instance-mutater"
+	upgradeRolloutName       = "upgrade-rollout"
 
 	caasFirewallerNameLegacy       = "caas-firewaller-legacy"
 	caasFirewallerNameSidecar      = "caas-firewaller-embedded"
 	caasModelOperatorName          = "caas-model-operator"
 	caasmodelconfigmanagerName     = "caas-model-config-manager"
+	caasEventsName                 = "caas-events"
 	caasOperatorProvisionerName    = "caas-operator-provisioner"
 	caasApplicationProvisionerName = "caas-application-provisioner"
 	caasUnitProvisionerName        = "caas-unit-provisioner"
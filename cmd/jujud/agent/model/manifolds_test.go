@@ -67,6 +67,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"storage-provisioner",
 		"undertaker",
 		"unit-assigner",
+		"upgrade-rollout",
 		"user-secrets-drain-worker",
 		"valid-credential-flag",
 	})
@@ -90,6 +91,7 @@ func (s *ManifoldsSuite) TestCAASNames(c *gc.C) {
 		"api-config-watcher",
 		"caas-application-provisioner",
 		"caas-broker-tracker",
+		"caas-events",
 		"caas-firewaller-embedded",
 		"caas-firewaller-legacy",
 		"caas-model-config-manager",
@@ -251,6 +253,17 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 
 	"caas-model-config-manager": {"agent", "api-caller", "caas-broker-tracker", "is-responsible-flag"},
 
+	"caas-events": {
+		"agent",
+		"api-caller",
+		"caas-broker-tracker",
+		"environ-upgrade-gate",
+		"environ-upgraded-flag",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"not-dead-flag"},
+
 	"caas-firewaller-legacy": {
 		"agent",
 		"api-caller",
@@ -732,5 +745,15 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"environ-upgraded-flag",
 		"not-dead-flag"},
 
+	"upgrade-rollout": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"environ-upgrade-gate",
+		"environ-upgraded-flag",
+		"not-dead-flag"},
+
 	"valid-credential-flag": {"agent", "api-caller"},
 }
@@ -33,7 +33,7 @@ var _ = gc.Suite(&NewRebootSuite{})
 
 func (s *NewRebootSuite) TestExecuteReboot(c *gc.C) {
 	defer s.setupMocks(c).Finish()
-	s.expectManagerIsInitialized(false, false)
+	s.expectManagerIsInitialized(false, false, false)
 	s.expectListServices()
 	s.expectStopDeployedUnits()
 	s.expectScheduleAction()
@@ -44,8 +44,8 @@ func (s *NewRebootSuite) TestExecuteReboot(c *gc.C) {
 
 func (s *NewRebootSuite) TestExecuteRebootWaitForContainers(c *gc.C) {
 	defer s.setupMocks(c).Finish()
-	s.expectManagerIsInitialized(true, false)
-	s.expectManagerIsInitialized(true, false)
+	s.expectManagerIsInitialized(true, false, false)
+	s.expectManagerIsInitialized(true, false, false)
 	s.expectListContainers()
 	s.expectListServices()
 	s.expectStopDeployedUnits()
@@ -75,9 +75,10 @@ func (s *NewRebootSuite) setupMocks(c *gc.C) *gomock.Controller {
 	return ctrl
 }
 
-func (s *NewRebootSuite) expectManagerIsInitialized(lxd, kvm bool) {
+func (s *NewRebootSuite) expectManagerIsInitialized(lxd, kvm, nspawn bool) {
 	s.containerManager.EXPECT().IsInitialized().Return(lxd)
 	s.containerManager.EXPECT().IsInitialized().Return(kvm)
+	s.containerManager.EXPECT().IsInitialized().Return(nspawn)
 }
 
 func (s *NewRebootSuite) expectListServices() {
@@ -53,6 +53,8 @@ func NewSuperCommand() cmd.Command {
 	metadatacmd.Register(newGenerateAgentsCommand())
 	metadatacmd.Register(newValidateToolsMetadataCommand())
 	metadatacmd.Register(newSignMetadataCommand())
+	metadatacmd.Register(newServeMetadataCommand())
+	metadatacmd.Register(newMirrorCommand())
 	metadatacmd.Register(newListImagesCommand())
 	metadatacmd.Register(newAddImageMetadataCommand())
 	metadatacmd.Register(newDeleteImageMetadataCommand())
@@ -34,6 +34,8 @@ var metadataCommandNames = []string{
 	"generate-image",
 	"images",
 	"list-images",
+	"mirror",
+	"serve",
 	"sign",
 	"validate-agent-binaries",
 	"validate-images",
@@ -0,0 +1,347 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	jujuhttp "github.com/juju/http/v2"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/v3"
+	"github.com/juju/version/v2"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	envtools "github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/juju/keys"
+	"github.com/juju/juju/juju/osenv"
+)
+
+func newMirrorCommand() cmd.Command {
+	return &mirrorCommand{}
+}
+
+const mirrorDoc = `
+mirror copies agent binaries, and their simplestreams metadata, from an
+upstream simplestreams source into a local directory, which can then be
+served to a cloud without access to the upstream source using "serve".
+
+The working directory is specified using the -d argument (defaults to
+$JUJU_DATA or if not defined $XDG_DATA_HOME/juju or if that is not defined
+~/.local/share/juju). Agent binaries and metadata are written to its "tools"
+subdirectory, in the same layout as used by the upstream source.
+
+Mirroring is incremental: agent binaries which have already been mirrored,
+and are unchanged upstream, are not downloaded again. The metadata for
+binaries mirrored previously, and for other streams, is kept.
+
+The agent binaries to mirror may be restricted to the specified
+architectures, operating systems and agent version. An agent version may be
+specified as <major>.<minor>, to mirror all agent binaries in that series,
+or as a full version number.
+
+Upstream metadata is verified using the Juju public signing key, unless a
+file containing another armored public key is specified with --public-key.
+
+Only agent binaries are mirrored. Cloud images are specific to each cloud,
+and must already be available in it, so they are not mirrored. Use
+"generate-image" to write image metadata for those images into the working
+directory, from where "serve" serves it alongside the agent binaries.
+
+Examples:
+
+# mirror all released agent binaries from the public streams:
+juju metadata mirror -d <workingdir>
+
+# mirror amd64 agent binaries for 3.5 from the "proposed" stream:
+juju metadata mirror -d <workingdir> --stream proposed --arch amd64 --agent-version 3.5
+
+# mirror agent binaries from another mirror:
+juju metadata mirror -d <workingdir> --source https://example.com/juju/tools
+
+See also:
+    generate-agent-binaries
+    generate-image
+    serve
+`
+
+// mirrorCommand is used to mirror agent binaries and their metadata
+// from an upstream simplestreams source.
+type mirrorCommand struct {
+	cmd.CommandBase
+	metadataDir   string
+	source        string
+	stream        string
+	arches        string
+	releases      string
+	agentVersion  string
+	publicKeyFile string
+
+	majorVersion int
+	minorVersion int
+	version      version.Number
+}
+
+func (c *mirrorCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "mirror",
+		Purpose: "mirror agent binaries and metadata from an upstream source",
+		Doc:     mirrorDoc,
+	})
+}
+
+func (c *mirrorCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.metadataDir, "d", "", "local directory in which to store agent binaries and metadata")
+	f.StringVar(&c.source, "source", "", "URL of the upstream agent binaries (defaults to the public streams)")
+	f.StringVar(&c.stream, "stream", envtools.ReleasedStream, "simplestreams stream to mirror")
+	f.StringVar(&c.arches, "arch", "", "comma separated architectures of the agent binaries to mirror (defaults to all)")
+	f.StringVar(&c.releases, "release", "", "comma separated operating systems of the agent binaries to mirror (defaults to all)")
+	f.StringVar(&c.agentVersion, "agent-version", "", "agent version, or <major>.<minor> series, to mirror (defaults to all)")
+	f.StringVar(&c.publicKeyFile, "public-key", "", "file containing the armored public key used to verify upstream metadata")
+}
+
+func (c *mirrorCommand) Init(args []string) error {
+	for _, a := range splitList(c.arches) {
+		if !arch.IsSupportedArch(a) {
+			return errors.NotValidf("architecture %q", a)
+		}
+	}
+	c.majorVersion, c.minorVersion = -1, -1
+	if c.agentVersion != "" {
+		if strings.Count(c.agentVersion, ".") == 1 {
+			var err error
+			if c.majorVersion, c.minorVersion, err = version.ParseMajorMinor(c.agentVersion); err != nil {
+				return errors.NotValidf("agent version %q", c.agentVersion)
+			}
+		} else {
+			var err error
+			if c.version, err = version.Parse(c.agentVersion); err != nil {
+				return errors.NotValidf("agent version %q", c.agentVersion)
+			}
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *mirrorCommand) Run(context *cmd.Context) error {
+	writer := loggo.NewMinimumLevelWriter(
+		cmd.NewCommandLogWriter("juju.plugins.metadata", context.Stdout, context.Stderr),
+		loggo.INFO)
+	_ = loggo.RegisterWriter("mirror", writer)
+	defer func() { _, _ = loggo.RemoveWriter("mirror") }()
+	if c.metadataDir == "" {
+		c.metadataDir = osenv.JujuXDGDataHomeDir()
+	} else {
+		c.metadataDir = context.AbsPath(c.metadataDir)
+	}
+
+	publicKey := keys.JujuPublicKey
+	if c.publicKeyFile != "" {
+		keyData, err := os.ReadFile(context.AbsPath(c.publicKeyFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		publicKey = string(keyData)
+	}
+	source := c.source
+	if source == "" {
+		source = envtools.DefaultBaseURL
+	}
+	source, err := envtools.ToolsURL(source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	cons, err := c.toolsConstraint()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ss := simplestreams.NewSimpleStreams(simplestreams.DefaultDataSourceFactory())
+	dataSource := ss.NewDataSource(simplestreams.Config{
+		Description:          "mirror source",
+		BaseURL:              source,
+		PublicSigningKey:     publicKey,
+		HostnameVerification: true,
+		Priority:             simplestreams.CUSTOM_CLOUD_DATA,
+	})
+
+	fmt.Fprintf(context.Stdout, "Finding agent binaries in %s for stream %s.\n", source, c.stream)
+	upstream, _, err := envtools.Fetch(ss, []simplestreams.DataSource{dataSource}, cons)
+	if err != nil {
+		return errors.Annotate(err, "reading upstream metadata")
+	}
+
+	stor, err := filestorage.NewFileStorageWriter(c.metadataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	existing, err := envtools.ReadAllMetadata(ss, stor)
+	if err != nil {
+		return errors.Annotate(err, "reading existing metadata")
+	}
+
+	m := &mirror{
+		dir:      c.metadataDir,
+		stor:     stor,
+		existing: existing[c.stream],
+	}
+	mirrored, err := m.mirror(upstream)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(context.Stdout, "Mirrored %d agent binaries, %d already up to date.\n",
+		len(mirrored), len(upstream)-len(mirrored))
+	if len(mirrored) == 0 {
+		return nil
+	}
+
+	merged, err := envtools.MergeMetadata(mirrored, withoutBinaries(existing[c.stream], mirrored))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	existing[c.stream] = merged
+	return errors.Trace(envtools.WriteMetadata(stor, existing, []string{c.stream}, envtools.DoNotWriteMirrors))
+}
+
+// toolsConstraint returns the constraint used to select the upstream
+// agent binaries to mirror.
+func (c *mirrorCommand) toolsConstraint() (*envtools.ToolsConstraint, error) {
+	params := simplestreams.LookupParams{
+		Stream:   c.stream,
+		Arches:   splitList(c.arches),
+		Releases: splitList(c.releases),
+	}
+	if len(params.Arches) == 0 {
+		params.Arches = arch.AllSupportedArches
+	}
+	if len(params.Releases) == 0 {
+		workloadOSTypes, err := corebase.AllWorkloadOSTypes()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		params.Releases = workloadOSTypes.SortedValues()
+	}
+	if c.version != version.Zero {
+		return envtools.NewVersionedToolsConstraint(c.version, params), nil
+	}
+	return envtools.NewGeneralToolsConstraint(c.majorVersion, c.minorVersion, params), nil
+}
+
+// mirror downloads agent binaries into a local simplestreams directory.
+type mirror struct {
+	dir      string
+	stor     storage.Storage
+	existing []*envtools.ToolsMetadata
+}
+
+// mirror downloads the upstream agent binaries which aren't already in
+// the local directory, and returns the metadata for those it downloaded.
+func (m *mirror) mirror(upstream []*envtools.ToolsMetadata) ([]*envtools.ToolsMetadata, error) {
+	var mirrored []*envtools.ToolsMetadata
+	for _, md := range upstream {
+		local := *md
+		// Binaries are stored at the same path as upstream, relative to
+		// the tools directory, but never outside of it.
+		local.Path = strings.TrimPrefix(path.Clean("/"+md.Path), "/")
+		local.FullPath = ""
+		if m.upToDate(&local) {
+			logger.Debugf("agent binary %q is up to date", local.Path)
+			continue
+		}
+		if err := m.download(md.FullPath, &local); err != nil {
+			return nil, errors.Annotatef(err, "mirroring agent binary %s", md.FullPath)
+		}
+		mirrored = append(mirrored, &local)
+	}
+	return mirrored, nil
+}
+
+// upToDate returns whether the agent binary described by the metadata
+// has already been mirrored.
+func (m *mirror) upToDate(md *envtools.ToolsMetadata) bool {
+	found := false
+	for _, existing := range m.existing {
+		if existing.Version == md.Version && existing.Release == md.Release && existing.Arch == md.Arch {
+			found = existing.Path == md.Path && existing.SHA256 == md.SHA256 && existing.Size == md.Size
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(m.dir, storage.BaseToolsPath, filepath.FromSlash(md.Path)))
+	return err == nil && info.Size() == md.Size
+}
+
+// download fetches the agent binary at the URL, verifies it against
+// the metadata, and stores it in the local directory.
+func (m *mirror) download(url string, md *envtools.ToolsMetadata) error {
+	logger.Infof("downloading %s", url)
+	resp, err := jujuhttp.NewClient().Get(context.TODO(), url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("cannot download %s: %s", url, resp.Status)
+	}
+
+	var buf bytes.Buffer
+	sha256, size, err := utils.ReadSHA256(io.TeeReader(resp.Body, &buf))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if md.SHA256 != "" && sha256 != md.SHA256 {
+		return errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, md.SHA256)
+	}
+	if md.Size != 0 && size != md.Size {
+		return errors.Errorf("size mismatch (%v/%v)", size, md.Size)
+	}
+	md.SHA256, md.Size = sha256, size
+	return errors.Trace(m.stor.Put(path.Join(storage.BaseToolsPath, md.Path), &buf, size))
+}
+
+// withoutBinaries returns the metadata in list for agent binaries other
+// than those in remove.
+func withoutBinaries(list, remove []*envtools.ToolsMetadata) []*envtools.ToolsMetadata {
+	removed := set.NewStrings()
+	for _, md := range remove {
+		removed.Add(md.Version + "-" + md.Release + "-" + md.Arch)
+	}
+	var result []*envtools.ToolsMetadata
+	for _, md := range list {
+		if !removed.Contains(md.Version + "-" + md.Release + "-" + md.Arch) {
+			result = append(result, md)
+		}
+	}
+	return result
+}
+
+// splitList splits a comma separated flag value.
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	toolstesting "github.com/juju/juju/environs/tools/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type MirrorSuite struct {
+	coretesting.BaseSuite
+	upstreamDir   string
+	upstreamURL   string
+	publicKeyFile string
+}
+
+var _ = gc.Suite(&MirrorSuite{})
+
+func (s *MirrorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	loggo.GetLogger("").SetLogLevel(loggo.INFO)
+
+	// The upstream source is served by the same handler as "serve".
+	s.upstreamDir = c.MkDir()
+	server := httptest.NewServer(newMetadataHandler(s.upstreamDir, "", ""))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	s.upstreamURL = server.URL + "/tools"
+
+	s.publicKeyFile = filepath.Join(c.MkDir(), "public.asc")
+	err := os.WriteFile(s.publicKeyFile, []byte(sstesting.SignedMetadataPublicKey), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MirrorSuite) runMirror(c *gc.C, dir string, args ...string) string {
+	ctx := cmdtesting.Context(c)
+	args = append([]string{"-d", dir, "--source", s.upstreamURL, "--public-key", s.publicKeyFile}, args...)
+	code := cmd.Main(newMirrorCommand(), ctx, args)
+	c.Assert(code, gc.Equals, 0, gc.Commentf("stderr: %s", cmdtesting.Stderr(ctx)))
+	return cmdtesting.Stdout(ctx)
+}
+
+func (s *MirrorSuite) assertMirrored(c *gc.C, dir string, expected ...string) {
+	metadata := toolstesting.ParseMetadataFromDir(c, dir, "released", false)
+	var versions []string
+	for _, md := range metadata {
+		versions = append(versions, md.Version+"-"+md.Release+"-"+md.Arch)
+		data, err := os.ReadFile(filepath.Join(dir, "tools", filepath.FromSlash(md.Path)))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(int64(len(data)), gc.Equals, md.Size)
+	}
+	c.Check(versions, jc.SameContents, expected)
+}
+
+func (s *MirrorSuite) TestMirror(c *gc.C) {
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.5.0-ubuntu-amd64",
+		"3.5.0-ubuntu-arm64",
+	})
+	dir := c.MkDir()
+
+	out := s.runMirror(c, dir)
+	c.Check(out, gc.Matches, `(?s)Finding agent binaries in .* for stream released\.
+.*Mirrored 2 agent binaries, 0 already up to date\.
+.*`)
+	s.assertMirrored(c, dir, "3.5.0-ubuntu-amd64", "3.5.0-ubuntu-arm64")
+}
+
+func (s *MirrorSuite) TestMirrorIncremental(c *gc.C) {
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.5.0-ubuntu-amd64",
+	})
+	dir := c.MkDir()
+	s.runMirror(c, dir)
+
+	out := s.runMirror(c, dir)
+	c.Check(out, gc.Matches, `(?s).*Mirrored 0 agent binaries, 1 already up to date\.
+`)
+
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.5.1-ubuntu-amd64",
+	})
+	out = s.runMirror(c, dir)
+	c.Check(out, gc.Matches, `(?s).*Mirrored 1 agent binaries, 1 already up to date\..*`)
+	s.assertMirrored(c, dir, "3.5.0-ubuntu-amd64", "3.5.1-ubuntu-amd64")
+}
+
+func (s *MirrorSuite) TestMirrorReplacesMissingBinary(c *gc.C) {
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.5.0-ubuntu-amd64",
+	})
+	dir := c.MkDir()
+	s.runMirror(c, dir)
+
+	err := os.Remove(filepath.Join(dir, "tools", "released", "juju-3.5.0-ubuntu-amd64.tgz"))
+	c.Assert(err, jc.ErrorIsNil)
+	out := s.runMirror(c, dir)
+	c.Check(out, gc.Matches, `(?s).*Mirrored 1 agent binaries, 0 already up to date\..*`)
+	s.assertMirrored(c, dir, "3.5.0-ubuntu-amd64")
+}
+
+func (s *MirrorSuite) TestMirrorFiltered(c *gc.C) {
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.4.2-ubuntu-amd64",
+		"3.5.0-ubuntu-amd64",
+		"3.5.0-ubuntu-arm64",
+		"3.5.1-ubuntu-amd64",
+	})
+
+	dir := c.MkDir()
+	s.runMirror(c, dir, "--arch", "amd64", "--agent-version", "3.5")
+	s.assertMirrored(c, dir, "3.5.0-ubuntu-amd64", "3.5.1-ubuntu-amd64")
+
+	dir = c.MkDir()
+	s.runMirror(c, dir, "--release", "ubuntu", "--agent-version", "3.5.0")
+	s.assertMirrored(c, dir, "3.5.0-ubuntu-amd64", "3.5.0-ubuntu-arm64")
+}
+
+func (s *MirrorSuite) TestMirrorWrongPublicKey(c *gc.C) {
+	toolstesting.MakeToolsWithCheckSum(c, s.upstreamDir, "released", []string{
+		"3.5.0-ubuntu-amd64",
+	})
+	// Only the signed metadata is available upstream.
+	for _, name := range []string{"index.json", "index2.json", "com.ubuntu.juju-released-agents.json"} {
+		err := os.Remove(filepath.Join(s.upstreamDir, "tools", "streams", "v1", name))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := os.WriteFile(s.publicKeyFile, []byte("not a key"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(newMirrorCommand(), ctx, []string{
+		"-d", c.MkDir(), "--source", s.upstreamURL, "--public-key", s.publicKeyFile,
+	})
+	c.Check(code, gc.Equals, 1)
+	c.Check(cmdtesting.Stderr(ctx), gc.Matches, `(?s).*ERROR reading upstream metadata: .*`)
+}
+
+func (s *MirrorSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--arch", "amd64,z80"},
+		err:  `architecture "z80" not valid`,
+	}, {
+		args: []string{"--agent-version", "3.x"},
+		err:  `agent version "3.x" not valid`,
+	}, {
+		args: []string{"--agent-version", "3.5.x"},
+		err:  `agent version "3.5.x" not valid`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--arch", "amd64, arm64", "--release", "ubuntu", "--agent-version", "3.5"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(newMirrorCommand(), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
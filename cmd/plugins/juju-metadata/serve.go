@@ -0,0 +1,272 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/loggo"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+func newServeMetadataCommand() cmd.Command {
+	return &serveMetadataCommand{}
+}
+
+const serveMetadataDoc = `
+serve makes a directory of simplestreams metadata, and the image or agent
+binaries it refers to, available over HTTP so that it can be used as the
+image-metadata-url or agent-metadata-url of a model in a cloud without
+access to the public streams.
+
+The directory is specified using the -d argument and is served as is, so the
+URL for agent metadata generated in <dir> with "generate-agent-binaries" is
+http://<address>/tools. Directory listings are not served.
+
+If --cert and --key are specified, the metadata is served over HTTPS using
+the certificate and private key in the specified files.
+
+If a keyring file containing an armored private key is specified with -k,
+requests for a signed .sjson file which doesn't exist in the directory are
+answered by signing the corresponding .json file with that key. This allows
+models which require signed metadata to use unsigned metadata generated
+locally, without having to re-run "sign" after each update.
+
+Examples:
+
+# serve agent and image metadata from <dir> on port 8080:
+juju metadata serve -d <dir>
+
+# serve metadata over HTTPS, signing the index and products on demand:
+juju metadata serve -d <dir> --address :443 --cert cert.pem --key key.pem -k private.asc
+
+See also:
+    generate-agent-binaries
+    generate-image
+    mirror
+    sign
+`
+
+// serveMetadataCommand is used to serve simplestreams metadata over HTTP.
+type serveMetadataCommand struct {
+	cmd.CommandBase
+	dir        string
+	address    string
+	certFile   string
+	keyFile    string
+	signingKey string
+	passphrase string
+}
+
+func (c *serveMetadataCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "serve",
+		Purpose: "serve simplestreams metadata over HTTP",
+		Doc:     serveMetadataDoc,
+	})
+}
+
+func (c *serveMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.dir, "d", "", "directory containing the metadata to serve")
+	f.StringVar(&c.address, "address", ":8080", "address on which to listen")
+	f.StringVar(&c.certFile, "cert", "", "file containing the TLS certificate to serve HTTPS")
+	f.StringVar(&c.keyFile, "key", "", "file containing the TLS private key to serve HTTPS")
+	f.StringVar(&c.signingKey, "k", "", "file containing the armored private key used to sign metadata on demand")
+	f.StringVar(&c.passphrase, "p", "", "passphrase used to decrypt the private signing key")
+}
+
+func (c *serveMetadataCommand) Init(args []string) error {
+	if c.dir == "" {
+		return errors.Errorf("directory must be specified")
+	}
+	if (c.certFile == "") != (c.keyFile == "") {
+		return errors.Errorf("--cert and --key must be specified together")
+	}
+	if c.passphrase != "" && c.signingKey == "" {
+		return errors.Errorf("passphrase specified without a signing key")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *serveMetadataCommand) Run(context *cmd.Context) error {
+	writer := loggo.NewMinimumLevelWriter(
+		cmd.NewCommandLogWriter("juju.plugins.metadata", context.Stdout, context.Stderr),
+		loggo.INFO)
+	_ = loggo.RegisterWriter("servemetadata", writer)
+	defer func() { _, _ = loggo.RemoveWriter("servemetadata") }()
+
+	dir := context.AbsPath(c.dir)
+	if info, err := os.Stat(dir); err != nil {
+		return errors.Trace(err)
+	} else if !info.IsDir() {
+		return errors.NotValidf("metadata directory %q", dir)
+	}
+	var key string
+	if c.signingKey != "" {
+		keyData, err := os.ReadFile(context.AbsPath(c.signingKey))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		key = string(keyData)
+	}
+
+	listener, err := net.Listen("tcp", c.address)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	server := &http.Server{
+		Handler:           newMetadataHandler(dir, key, c.passphrase),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	scheme := "http"
+	if c.certFile != "" {
+		scheme = "https"
+	}
+	fmt.Fprintf(context.Stdout, "Serving metadata in %s on %s://%s/\n", dir, scheme, listener.Addr())
+
+	served := make(chan error, 1)
+	go func() {
+		if c.certFile != "" {
+			served <- server.ServeTLS(listener, context.AbsPath(c.certFile), context.AbsPath(c.keyFile))
+		} else {
+			served <- server.Serve(listener)
+		}
+	}()
+
+	interrupted := make(chan os.Signal, 1)
+	context.InterruptNotify(interrupted)
+	defer context.StopInterruptNotify(interrupted)
+	select {
+	case err := <-served:
+		return errors.Trace(err)
+	case <-interrupted:
+		return errors.Trace(server.Close())
+	}
+}
+
+// metadataContentTypes maps the extensions of the files found in a
+// simplestreams directory to the content type with which they are
+// served. Files with other extensions are served as binary data.
+var metadataContentTypes = map[string]string{
+	simplestreams.UnsignedSuffix: "application/json",
+	// Signed metadata is an inline (clearsigned) OpenPGP message, which
+	// is text rather than JSON.
+	simplestreams.SignedSuffix: "text/plain; charset=utf-8",
+	".tgz":                     "application/gzip",
+	".gz":                      "application/gzip",
+	".xz":                      "application/x-xz",
+}
+
+func metadataContentType(name string) string {
+	if contentType, ok := metadataContentTypes[path.Ext(name)]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// metadataHandler serves the files in a directory of simplestreams
+// metadata. If a signing key is set, missing signed metadata files are
+// generated from the corresponding unsigned files.
+type metadataHandler struct {
+	dir        string
+	signingKey string
+	passphrase string
+}
+
+func newMetadataHandler(dir, signingKey, passphrase string) http.Handler {
+	return &metadataHandler{
+		dir:        dir,
+		signingKey: signingKey,
+		passphrase: passphrase,
+	}
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *metadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Cleaning the path as an absolute path removes any ".." elements,
+	// so the file is always inside the metadata directory.
+	name := path.Clean("/" + r.URL.Path)
+	filename := filepath.Join(h.dir, filepath.FromSlash(name))
+
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) && h.signingKey != "" && strings.HasSuffix(name, simplestreams.SignedSuffix) {
+		h.serveSigned(w, r, name, filename)
+		return
+	}
+	if err != nil {
+		h.serveError(w, name, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		h.serveError(w, name, err)
+		return
+	}
+	if info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	logger.Debugf("serving %q", name)
+	w.Header().Set("Content-Type", metadataContentType(name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// serveSigned serves the signed form of the unsigned metadata file
+// corresponding to the requested signed file.
+func (h *metadataHandler) serveSigned(w http.ResponseWriter, r *http.Request, name, filename string) {
+	unsignedFilename := strings.TrimSuffix(filename, simplestreams.SignedSuffix) + simplestreams.UnsignedSuffix
+	f, err := os.Open(unsignedFilename)
+	if err != nil {
+		h.serveError(w, name, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		h.serveError(w, name, err)
+		return
+	}
+	encoded, err := simplestreams.Encode(f, h.signingKey, h.passphrase)
+	if err != nil {
+		h.serveError(w, name, errors.Annotatef(err, "signing %q", unsignedFilename))
+		return
+	}
+	logger.Debugf("serving %q signed on demand", name)
+	w.Header().Set("Content-Type", metadataContentType(name))
+	http.ServeContent(w, r, name, info.ModTime(), bytes.NewReader(encoded))
+}
+
+func (h *metadataHandler) serveError(w http.ResponseWriter, name string, err error) {
+	switch {
+	case os.IsNotExist(err):
+		http.Error(w, "404 page not found", http.StatusNotFound)
+	case os.IsPermission(err):
+		http.Error(w, "403 forbidden", http.StatusForbidden)
+	default:
+		logger.Errorf("serving %q: %v", name, err)
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+	}
+}
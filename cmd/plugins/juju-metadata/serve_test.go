@@ -0,0 +1,151 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ServeMetadataSuite struct {
+	coretesting.BaseSuite
+	dir string
+}
+
+var _ = gc.Suite(&ServeMetadataSuite{})
+
+func (s *ServeMetadataSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	topLevel := c.MkDir()
+	s.dir = filepath.Join(topLevel, "metadata")
+	for name, content := range map[string]string{
+		"tools/streams/v1/index.json":                `{"format": "index:1.0"}`,
+		"tools/released/juju-3.5.0-ubuntu-amd64.tgz": "agent binary",
+		"images/streams/v1/index.sjson":              "signed index",
+		"../secret":                                  "secret",
+	} {
+		filename := filepath.Join(s.dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		err = os.WriteFile(filename, []byte(content), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *ServeMetadataSuite) get(c *gc.C, handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func (s *ServeMetadataSuite) TestServeContentTypes(c *gc.C) {
+	handler := newMetadataHandler(s.dir, "", "")
+	for path, expected := range map[string]struct {
+		contentType string
+		body        string
+	}{
+		"/tools/streams/v1/index.json":                {"application/json", `{"format": "index:1.0"}`},
+		"/tools/released/juju-3.5.0-ubuntu-amd64.tgz": {"application/gzip", "agent binary"},
+		"/images/streams/v1/index.sjson":              {"text/plain; charset=utf-8", "signed index"},
+	} {
+		c.Logf("GET %s", path)
+		resp := s.get(c, handler, "GET", path)
+		c.Check(resp.Code, gc.Equals, http.StatusOK)
+		c.Check(resp.Header().Get("Content-Type"), gc.Equals, expected.contentType)
+		c.Check(resp.Body.String(), gc.Equals, expected.body)
+	}
+}
+
+func (s *ServeMetadataSuite) TestServeHead(c *gc.C) {
+	resp := s.get(c, newMetadataHandler(s.dir, "", ""), "HEAD", "/tools/streams/v1/index.json")
+	c.Check(resp.Code, gc.Equals, http.StatusOK)
+	c.Check(resp.Header().Get("Content-Length"), gc.Equals, "23")
+	c.Check(resp.Body.Len(), gc.Equals, 0)
+}
+
+func (s *ServeMetadataSuite) TestServeNotFound(c *gc.C) {
+	handler := newMetadataHandler(s.dir, "", "")
+	for _, path := range []string{
+		"/tools/streams/v1/missing.json",
+		"/tools/streams/v1/index.sjson",
+		"/tools/streams",
+		"/../secret",
+		"/tools/../../secret",
+	} {
+		c.Logf("GET %s", path)
+		resp := s.get(c, handler, "GET", path)
+		c.Check(resp.Code, gc.Equals, http.StatusNotFound)
+	}
+}
+
+func (s *ServeMetadataSuite) TestServeMethodNotAllowed(c *gc.C) {
+	resp := s.get(c, newMetadataHandler(s.dir, "", ""), "PUT", "/tools/streams/v1/index.json")
+	c.Check(resp.Code, gc.Equals, http.StatusMethodNotAllowed)
+	c.Check(resp.Header().Get("Allow"), gc.Equals, "GET, HEAD")
+}
+
+func (s *ServeMetadataSuite) TestServeSignsOnDemand(c *gc.C) {
+	handler := newMetadataHandler(s.dir, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	resp := s.get(c, handler, "GET", "/tools/streams/v1/index.sjson")
+	c.Assert(resp.Code, gc.Equals, http.StatusOK)
+	c.Check(resp.Header().Get("Content-Type"), gc.Equals, "text/plain; charset=utf-8")
+
+	data, err := simplestreams.DecodeCheckSignature(resp.Body, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(strings.TrimSpace(string(data)), gc.Equals, `{"format": "index:1.0"}`)
+
+	// Existing signed files are served as is.
+	resp = s.get(c, handler, "GET", "/images/streams/v1/index.sjson")
+	c.Check(resp.Code, gc.Equals, http.StatusOK)
+	c.Check(resp.Body.String(), gc.Equals, "signed index")
+
+	// There's nothing to sign for a missing unsigned file.
+	resp = s.get(c, handler, "GET", "/tools/streams/v1/missing.sjson")
+	c.Check(resp.Code, gc.Equals, http.StatusNotFound)
+}
+
+func (s *ServeMetadataSuite) TestServeSignError(c *gc.C) {
+	resp := s.get(c, newMetadataHandler(s.dir, "not a key", ""), "GET", "/tools/streams/v1/index.sjson")
+	c.Check(resp.Code, gc.Equals, http.StatusInternalServerError)
+}
+
+func (s *ServeMetadataSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "directory must be specified",
+	}, {
+		args: []string{"-d", s.dir, "--cert", "cert.pem"},
+		err:  "--cert and --key must be specified together",
+	}, {
+		args: []string{"-d", s.dir, "-p", "secret"},
+		err:  "passphrase specified without a signing key",
+	}, {
+		args: []string{"-d", s.dir, "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"-d", s.dir, "--address", "127.0.0.1:0", "--cert", "cert.pem", "--key", "key.pem", "-k", "key.asc"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(newServeMetadataCommand(), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
@@ -94,6 +94,8 @@ func New(config Config) (environs.InstanceBroker, error) {
 		newBroker = NewKVMBroker
 	case instance.LXD:
 		newBroker = NewLXDBroker
+	case instance.NSPAWN:
+		newBroker = NewNspawnBroker
 	default:
 		return nil, errors.NotValidf("ContainerType %s", config.ContainerType)
 	}
@@ -0,0 +1,147 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package broker
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v5"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+var nspawnLogger = loggo.GetLogger("juju.container.broker.nspawn")
+
+// NewNspawnBroker creates a Broker that can be used to start systemd-nspawn
+// containers in a similar fashion to normal StartInstance requests.
+// prepareHost is a callback that will be called when a new container is about
+// to be started. It provides the intersection point where the host can update
+// itself to be ready for whatever changes are necessary to have a functioning
+// container. (such as bridging host devices.)
+// manager is the infrastructure to actually launch the container.
+func NewNspawnBroker(
+	prepareHost PrepareHostFunc,
+	api APICalls,
+	manager container.Manager,
+	agentConfig agent.Config,
+) (environs.InstanceBroker, error) {
+	return &nspawnBroker{
+		prepareHost: prepareHost,
+		manager:     manager,
+		api:         api,
+		agentConfig: agentConfig,
+	}, nil
+}
+
+type nspawnBroker struct {
+	prepareHost PrepareHostFunc
+	manager     container.Manager
+	api         APICalls
+	agentConfig agent.Config
+}
+
+// StartInstance is specified in the Broker interface.
+func (broker *nspawnBroker) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	containerMachineID := args.InstanceConfig.MachineId
+	nspawnLogger.Infof("starting nspawn container for containerMachineID: %s", containerMachineID)
+
+	config, err := broker.api.ContainerConfig()
+	if err != nil {
+		nspawnLogger.Errorf("failed to get container config: %v", err)
+		return nil, err
+	}
+
+	err = broker.prepareHost(names.NewMachineTag(containerMachineID), nspawnLogger, args.Abort)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	preparedInfo, err := prepareContainerInterfaceInfo(broker.api, containerMachineID, nspawnLogger)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	interfaces, err := finishNetworkConfig(preparedInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	net := container.BridgeNetworkConfig(0, interfaces)
+
+	// nspawn containers share the host's kernel, so we constrain the
+	// tools to host arch regardless of the constraints specified.
+	archTools, err := matchHostArchTools(args.Tools)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	args.InstanceConfig.MachineContainerType = instance.NSPAWN
+	if err := args.InstanceConfig.SetTools(archTools); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cloudInitUserData, err := combinedCloudInitData(
+		config.CloudInitUserData,
+		config.ContainerInheritProperties,
+		args.InstanceConfig.Base, nspawnLogger)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err := instancecfg.PopulateInstanceConfig(
+		args.InstanceConfig,
+		config.ProviderType,
+		config.AuthorizedKeys,
+		config.SSLHostnameVerification,
+		proxyConfigurationFromContainerCfg(config),
+		config.EnableOSRefreshUpdate,
+		config.EnableOSUpgrade,
+		cloudInitUserData,
+		nil,
+	); err != nil {
+		nspawnLogger.Errorf("failed to populate machine config: %v", err)
+		return nil, err
+	}
+
+	inst, hardware, err := broker.manager.CreateContainer(
+		ctx, args.InstanceConfig, args.Constraints, args.InstanceConfig.Base, net, nil, args.StatusCallback,
+	)
+	if err != nil {
+		nspawnLogger.Errorf("failed to start container: %v", err)
+		return nil, err
+	}
+	nspawnLogger.Infof("started nspawn container for containerMachineID: %s, %s, %s", containerMachineID, inst.Id(), hardware.String())
+	return &environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hardware,
+	}, nil
+}
+
+// StopInstances shuts down the given instances.
+func (broker *nspawnBroker) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
+	for _, id := range ids {
+		nspawnLogger.Infof("stopping nspawn container for instance: %s", id)
+		if err := broker.manager.DestroyContainer(id); err != nil {
+			nspawnLogger.Errorf("container did not stop: %v", err)
+			return err
+		}
+		releaseContainerAddresses(broker.api, id, broker.manager.Namespace(), nspawnLogger)
+	}
+	return nil
+}
+
+// AllInstances returns all containers.
+func (broker *nspawnBroker) AllInstances(ctx context.ProviderCallContext) (result []instances.Instance, err error) {
+	return broker.manager.ListContainers()
+}
+
+// AllRunningInstances only returns running containers.
+func (broker *nspawnBroker) AllRunningInstances(ctx context.ProviderCallContext) (result []instances.Instance, err error) {
+	return broker.manager.ListContainers()
+}
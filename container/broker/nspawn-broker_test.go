@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package broker_test
+
+import (
+	"github.com/juju/names/v5"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container/broker"
+	"github.com/juju/juju/core/arch"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type nspawnBrokerSuite struct {
+	coretesting.BaseSuite
+	agentConfig agent.Config
+	api         *fakeAPI
+	manager     *fakeContainerManager
+}
+
+var _ = gc.Suite(&nspawnBrokerSuite{})
+
+func (s *nspawnBrokerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	// To isolate the tests from the host's architecture, we override it here.
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	broker.PatchNewMachineInitReader(s, newBlankMachineInitReader)
+
+	var err error
+	s.agentConfig, err = agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			Paths:             agent.NewPathsWithDefaults(agent.Paths{DataDir: "/not/used/here"}),
+			Tag:               names.NewMachineTag("1"),
+			UpgradedToVersion: jujuversion.Current,
+			Password:          "dummy-secret",
+			Nonce:             "nonce",
+			APIAddresses:      []string{"10.0.0.1:1234"},
+			CACert:            coretesting.CACert,
+			Controller:        coretesting.ControllerTag,
+			Model:             coretesting.ModelTag,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = NewFakeAPI()
+	s.manager = &fakeContainerManager{}
+}
+
+func (s *nspawnBrokerSuite) newNspawnBroker(c *gc.C) environs.InstanceBroker {
+	broker, err := broker.NewNspawnBroker(s.api.PrepareHost, s.api, s.manager, s.agentConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	return broker
+}
+
+func (s *nspawnBrokerSuite) TestStartInstance(c *gc.C) {
+	broker := s.newNspawnBroker(c)
+
+	result, err := callStartInstance(c, s, broker, "1/nspawn/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("testinst"))
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "ContainerConfig",
+	}, {
+		FuncName: "PrepareHost",
+		Args:     []interface{}{names.NewMachineTag("1-nspawn-0")},
+	}, {
+		FuncName: "PrepareContainerInterfaceInfo",
+		Args:     []interface{}{names.NewMachineTag("1-nspawn-0")},
+	}})
+
+	s.manager.CheckCallNames(c, "CreateContainer")
+	call := s.manager.Calls()[0]
+	c.Assert(call.Args[0], gc.FitsTypeOf, &instancecfg.InstanceConfig{})
+	instanceConfig := call.Args[0].(*instancecfg.InstanceConfig)
+	c.Assert(instanceConfig.MachineContainerType, gc.Equals, instance.NSPAWN)
+	arch, err := instanceConfig.ToolsList().OneArch()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(arch, gc.Equals, "amd64")
+	assertCloudInitUserData(instanceConfig.CloudInitUserData, map[string]interface{}{
+		"packages":        []interface{}{"python-keystoneclient", "python-glanceclient"},
+		"preruncmd":       []interface{}{"mkdir /tmp/preruncmd", "mkdir /tmp/preruncmd2"},
+		"postruncmd":      []interface{}{"mkdir /tmp/postruncmd", "mkdir /tmp/postruncmd2"},
+		"package_upgrade": false,
+	}, c)
+}
+
+func (s *nspawnBrokerSuite) TestStartInstanceNoHostArchTools(c *gc.C) {
+	broker := s.newNspawnBroker(c)
+
+	_, err := broker.StartInstance(context.NewEmptyCloudCallContext(), environs.StartInstanceParams{
+		Tools: coretools.List{{
+			// non-host-arch tools should be filtered out by StartInstance
+			Version: version.MustParseBinary("2.3.4-ubuntu-arm64"),
+			URL:     "http://tools.testing.invalid/2.3.4-ubuntu-arm64.tgz",
+		}},
+		InstanceConfig: makeInstanceConfig(c, s, "1/nspawn/0"),
+	})
+	c.Assert(err, gc.ErrorMatches, `need agent binaries for arch amd64, only found arm64`)
+	s.manager.CheckNoCalls(c)
+}
+
+func (s *nspawnBrokerSuite) TestStopInstances(c *gc.C) {
+	broker := s.newNspawnBroker(c)
+
+	err := broker.StopInstances(context.NewEmptyCloudCallContext(), "juju-06f00d-1-nspawn-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.manager.CheckCall(c, 0, "DestroyContainer", instance.Id("juju-06f00d-1-nspawn-0"))
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "ReleaseContainerAddresses",
+		Args:     []interface{}{names.NewMachineTag("1/nspawn/0")},
+	}})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/container/nspawn"
 	"github.com/juju/juju/core/instance"
 )
 
@@ -22,6 +23,8 @@ var NewContainerManager = func(forType instance.ContainerType, conf container.Ma
 		return lxd.NewContainerManager(conf, lxd.NewLocalServer)
 	case instance.KVM:
 		return kvm.NewContainerManager(conf)
+	case instance.NSPAWN:
+		return nspawn.NewContainerManager(conf)
 	}
 	return nil, errors.Errorf("unknown container type: %q", forType)
 }
@@ -27,7 +27,7 @@ type kvmContainer struct {
 	started *bool
 
 	pathfinder pathfinderFunc
-	runCmd     runFunc
+	dial       dialFunc
 }
 
 var _ Container = (*kvmContainer)(nil)
@@ -138,8 +138,9 @@ func (c *kvmContainer) IsRunning() bool {
 	if c.started != nil {
 		return *c.started
 	}
-	machines, err := ListMachines(run)
+	machines, err := ListMachines(c.dial)
 	if err != nil {
+		logger.Warningf("cannot get state of %s: %v", c.name, err)
 		return false
 	}
 	c.started = isRunning(machines[c.name])
@@ -3,10 +3,14 @@
 
 package kvm
 
-import "github.com/juju/juju/environs/imagemetadata"
+import (
+	"github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs/imagemetadata"
+)
 
 type containerFactory struct {
 	fetcher imagemetadata.SimplestreamsFetcher
+	dial    dialFunc
 }
 
 var _ ContainerFactory = (*containerFactory)(nil)
@@ -16,12 +20,12 @@ func (factory *containerFactory) New(name string) Container {
 }
 
 func (factory *containerFactory) List() (result []Container, err error) {
-	machines, err := ListMachines(run)
+	machines, err := ListMachines(factory.dial)
 	if err != nil {
 		return nil, err
 	}
-	for hostname, status := range machines {
-		result = append(result, factory.new(hostname, isRunning(status)))
+	for hostname, state := range machines {
+		result = append(result, factory.new(hostname, isRunning(state)))
 	}
 	return result, nil
 }
@@ -32,10 +36,11 @@ func (factory *containerFactory) new(name string, started *bool) *kvmContainer {
 		factory: factory,
 		name:    name,
 		started: started,
+		dial:    factory.dial,
 	}
 }
 
-func isRunning(value string) *bool {
-	result := value == "running"
+func isRunning(state libvirt.DomainState) *bool {
+	result := state == libvirt.DomainRunning
 	return &result
 }
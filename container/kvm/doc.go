@@ -23,17 +23,17 @@ dependency on uvtool is to allow kvm to also work on arm64 and ppc64el.
 However, it is still only expected to work on ubuntu.
 
 When removing uvtool we (redir) performed a survey of the libvirt and qemu go
-package landscape. There are a number of cgo based libraries and two possibly
-promising ones once they are further developed:
-github.com/digitalocean/go-libvirt and github.com/digitalocean/go-qemu. Those
-packages are nascent and alpha at the time of this writing. They implement pure
-go interfaces to libvirt and qemu by way of libvirt/qemu's custom RPC protocol.
-While this would reduce the number of commands that require shelling out, it
-wouldn't provide a way to create and manage disk images. So unless someone
-implements qemu-utils and genisoimage in go, we'll always need to shell out for
-those calls. The wrapped commands exist, shockingly, in wrappedcmds.go with
-the exception of libvirt pool initialisation bits which are in
-initialization.go.
+package landscape. There are a number of cgo based libraries, and pure go
+implementations of libvirt's RPC protocol, such as
+github.com/digitalocean/go-libvirt. Rather than take a dependency for the
+handful of calls we make, juju/container/kvm/libvirt implements the parts of
+the protocol needed to define, start, stop, undefine and list domains, talking
+to the libvirt daemon over its unix socket. This replaces scraping the output
+of virsh. The RPC protocol doesn't provide a way to create and manage disk
+images, so unless someone implements qemu-utils and genisoimage in go, we'll
+always need to shell out for those calls. The wrapped commands exist,
+shockingly, in wrappedcmds.go with the exception of libvirt pool
+initialisation bits which are in initialization.go and still use virsh.
 
 After the provisioner initializes the kvm environment, we synchronise (fetch if
 we don't have one) an ubuntu qcow image for the appropriate series and
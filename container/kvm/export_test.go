@@ -5,7 +5,9 @@ package kvm
 
 import (
 	"strings"
+	"sync"
 
+	"github.com/juju/juju/container/kvm/libvirt"
 	"github.com/juju/juju/environs/instances"
 )
 
@@ -16,10 +18,10 @@ var KVMPath = &kvmPath
 
 // MakeCreateMachineParamsTestable adds test values to non exported values on
 // CreateMachineParams.
-func MakeCreateMachineParamsTestable(params *CreateMachineParams, pathfinder pathfinderFunc, runCmd runFunc, arch string) {
+func MakeCreateMachineParamsTestable(params *CreateMachineParams, pathfinder pathfinderFunc, runCmd runFunc, dial dialFunc, arch string) {
 	params.findPath = pathfinder
 	params.runCmd = runCmd
-	params.runCmdAsRoot = runCmd
+	params.dial = dial
 	params.arch = arch
 	return
 }
@@ -30,8 +32,8 @@ func NewEmptyKvmContainer() *kvmContainer {
 }
 
 // NewTestContainer returns a new container for testing.
-func NewTestContainer(name string, runCmd runFunc, pathfinder pathfinderFunc) *kvmContainer {
-	return &kvmContainer{name: name, runCmd: runCmd, pathfinder: pathfinder}
+func NewTestContainer(name string, dial dialFunc, pathfinder pathfinderFunc) *kvmContainer {
+	return &kvmContainer{name: name, dial: dial, pathfinder: pathfinder}
 }
 
 // ContainerFromInstance extracts the inner container from input instance,
@@ -67,3 +69,81 @@ func (s *runStub) Run(dir, cmd string, args ...string) (string, error) {
 func (s *runStub) Calls() []string {
 	return s.calls
 }
+
+// NewLibvirtStub returns a stub libvirt connection which reports the given
+// domains.
+func NewLibvirtStub(domains map[string]libvirt.DomainState) *libvirtStub {
+	return &libvirtStub{domains: domains, errors: make(map[string]error)}
+}
+
+type libvirtStub struct {
+	mu        sync.Mutex
+	domains   map[string]libvirt.DomainState
+	errors    map[string]error
+	calls     []string
+	domainXML string
+}
+
+// SetError sets the error returned by calls to the named method.
+func (s *libvirtStub) SetError(method string, err error) {
+	s.errors[method] = err
+}
+
+// Dial fakes connecting to libvirt.
+func (s *libvirtStub) Dial() (libvirtConn, error) {
+	if err := s.record("Dial", ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *libvirtStub) record(method, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, strings.TrimSpace(method+" "+name))
+	return s.errors[method]
+}
+
+func (s *libvirtStub) DefineDomain(domainXML string) (libvirt.DomainRef, error) {
+	s.domainXML = domainXML
+	return libvirt.DomainRef{Name: "defined", ID: -1}, s.record("DefineDomain", "")
+}
+
+func (s *libvirtStub) StartDomain(name string) error {
+	return s.record("StartDomain", name)
+}
+
+func (s *libvirtStub) DestroyDomain(name string) error {
+	return s.record("DestroyDomain", name)
+}
+
+func (s *libvirtStub) UndefineDomain(name string) error {
+	return s.record("UndefineDomain", name)
+}
+
+func (s *libvirtStub) SetAutostart(name string, autostart bool) error {
+	return s.record("SetAutostart", name)
+}
+
+func (s *libvirtStub) ListDomains() (map[string]libvirt.DomainState, error) {
+	if err := s.record("ListDomains", ""); err != nil {
+		return nil, err
+	}
+	return s.domains, nil
+}
+
+func (s *libvirtStub) Close() error {
+	return s.record("Close", "")
+}
+
+// Calls returns the calls made on a libvirtStub.
+func (s *libvirtStub) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// DomainXML returns the XML the last domain was defined with.
+func (s *libvirtStub) DomainXML() string {
+	return s.domainXML
+}
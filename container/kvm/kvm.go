@@ -144,7 +144,9 @@ type containerManager struct {
 	imageMetadataURL              string
 	imageStream                   string
 	imageMetadataDefaultsDisabled bool
-	imageMutex                    sync.Mutex
+
+	imageLocksMutex sync.Mutex
+	imageLocks      map[string]*sync.Mutex
 }
 
 var _ container.Manager = (*containerManager)(nil)
@@ -249,10 +251,12 @@ func (manager *containerManager) CreateContainer(
 	// Lock around finding an image.
 	// The provisioner works concurrently to create containers.
 	// If an image needs to be copied from a remote, we don't want many
-	// goroutines attempting to do it at once.
-	manager.imageMutex.Lock()
+	// goroutines attempting to do it at once. Containers which use
+	// different images don't need to wait for each other.
+	imageLock := manager.imageLock(startParams)
+	imageLock.Lock()
 	err = kvmContainer.EnsureCachedImage(startParams)
-	manager.imageMutex.Unlock()
+	imageLock.Unlock()
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "acquiring container image")
 	}
@@ -265,6 +269,23 @@ func (manager *containerManager) CreateContainer(
 	return &kvmInstance{kvmContainer, name}, &hardware, nil
 }
 
+// imageLock returns the lock held while caching the backing image used by
+// containers started with the given parameters.
+func (manager *containerManager) imageLock(params StartParams) *sync.Mutex {
+	manager.imageLocksMutex.Lock()
+	defer manager.imageLocksMutex.Unlock()
+	if manager.imageLocks == nil {
+		manager.imageLocks = make(map[string]*sync.Mutex)
+	}
+	key := backingFileName(params.Version, params.Arch)
+	lock, ok := manager.imageLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		manager.imageLocks[key] = lock
+	}
+	return lock
+}
+
 func (manager *containerManager) IsInitialized() bool {
 	requiredBinaries := []string{
 		"virsh",
@@ -0,0 +1,30 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package kvm
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+)
+
+type managerInternalSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&managerInternalSuite{})
+
+func (managerInternalSuite) TestImageLockPerImage(c *gc.C) {
+	manager := &containerManager{}
+	jammy := manager.imageLock(StartParams{Version: "22.04", Arch: "amd64"})
+	c.Check(manager.imageLock(StartParams{Version: "22.04", Arch: "amd64", Stream: "daily"}), gc.Equals, jammy)
+	c.Check(manager.imageLock(StartParams{Version: "20.04", Arch: "amd64"}), gc.Not(gc.Equals), jammy)
+	c.Check(manager.imageLock(StartParams{Version: "22.04", Arch: "arm64"}), gc.Not(gc.Equals), jammy)
+
+	// Holding the lock for one image doesn't block caching another.
+	jammy.Lock()
+	defer jammy.Unlock()
+	focal := manager.imageLock(StartParams{Version: "20.04", Arch: "amd64"})
+	c.Check(focal.TryLock(), gc.Equals, true)
+	focal.Unlock()
+}
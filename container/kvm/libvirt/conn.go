@@ -0,0 +1,452 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.container.kvm.libvirt")
+
+// Details of the remote protocol are at: https://libvirt.org/kbase/internals/rpc.html
+// and the procedures are defined in src/remote/remote_protocol.x in the
+// libvirt source.
+const (
+	remoteProgram        = 0x20008086
+	remoteProgramVersion = 1
+
+	procConnectOpen           = 1
+	procConnectClose          = 2
+	procDomainCreate          = 9
+	procDomainDefineXML       = 11
+	procDomainDestroy         = 12
+	procDomainLookupByName    = 23
+	procDomainSetAutostart    = 29
+	procDomainGetState        = 212
+	procDomainUndefineFlags   = 231
+	procConnectListAllDomains = 273
+
+	messageTypeCall  = 0
+	messageTypeReply = 1
+
+	messageStatusOK    = 0
+	messageStatusError = 1
+
+	// headerSize is the size of the length prefix and message header.
+	headerSize = 28
+
+	// maxMessageSize is the largest message libvirt will send.
+	maxMessageSize = 32 * 1024 * 1024
+
+	// undefineNVRAM is VIR_DOMAIN_UNDEFINE_NVRAM, which removes the
+	// domain's pflash drive along with its definition.
+	undefineNVRAM = 4
+
+	systemURI   = "qemu:///system"
+	dialTimeout = 10 * time.Second
+)
+
+// DefaultSockets are the paths of the sockets on which the system libvirt
+// daemon listens, in the order they are tried. Hosts using the modular
+// daemons only provide the virtqemud socket.
+var DefaultSockets = []string{
+	"/var/run/libvirt/libvirt-sock",
+	"/var/run/libvirt/virtqemud-sock",
+}
+
+// DomainState is the state of a libvirt guest domain.
+type DomainState int32
+
+const (
+	DomainNoState DomainState = iota
+	DomainRunning
+	DomainBlocked
+	DomainPaused
+	DomainShutdown
+	DomainShutoff
+	DomainCrashed
+	DomainPMSuspended
+)
+
+// String returns the state as it is shown by `virsh list`.
+func (s DomainState) String() string {
+	switch s {
+	case DomainRunning:
+		return "running"
+	case DomainBlocked:
+		return "idle"
+	case DomainPaused:
+		return "paused"
+	case DomainShutdown:
+		return "in shutdown"
+	case DomainShutoff:
+		return "shut off"
+	case DomainCrashed:
+		return "crashed"
+	case DomainPMSuspended:
+		return "pmsuspended"
+	}
+	return "no state"
+}
+
+// DomainRef identifies a libvirt guest domain.
+type DomainRef struct {
+	Name string
+	UUID [16]byte
+	// ID is the domain's ID while it is running, or -1.
+	ID int32
+}
+
+type reply struct {
+	status uint32
+	body   []byte
+	err    error
+}
+
+// Conn is a connection to a libvirt daemon using its RPC protocol. It is
+// safe for concurrent use; calls made at the same time are multiplexed over
+// the one connection.
+type Conn struct {
+	conn net.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	serial  uint32
+	pending map[uint32]chan reply
+	err     error
+
+	done chan struct{}
+}
+
+// Dial connects to the system libvirt daemon on the first of
+// DefaultSockets which accepts the connection.
+func Dial() (*Conn, error) {
+	var lastErr error
+	for _, socket := range DefaultSockets {
+		conn, err := DialSocket(socket)
+		if err == nil {
+			return conn, nil
+		}
+		logger.Debugf("cannot connect to libvirt on %q: %v", socket, err)
+		lastErr = err
+	}
+	return nil, errors.Annotate(lastErr, "connecting to libvirt")
+}
+
+// DialSocket connects to the system libvirt daemon on the given unix
+// socket.
+func DialSocket(socket string) (*Conn, error) {
+	netConn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conn := NewConn(netConn)
+	if err := conn.open(systemURI); err != nil {
+		_ = netConn.Close()
+		return nil, errors.Annotatef(err, "opening %q", systemURI)
+	}
+	return conn, nil
+}
+
+// NewConn returns a Conn which makes calls over netConn. The connection
+// must be opened before any other calls are made; Dial and DialSocket do
+// this.
+func NewConn(netConn net.Conn) *Conn {
+	c := &Conn{
+		conn:    netConn,
+		pending: make(map[uint32]chan reply),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Close closes the libvirt connection.
+func (c *Conn) Close() error {
+	if _, err := c.call(procConnectClose, nil); err != nil {
+		logger.Debugf("closing libvirt connection: %v", err)
+	}
+	err := c.conn.Close()
+	<-c.done
+	return errors.Trace(err)
+}
+
+// DefineDomain defines, but does not start, a persistent domain from the
+// given domain XML.
+func (c *Conn) DefineDomain(domainXML string) (DomainRef, error) {
+	var args xdrEncoder
+	args.string(domainXML)
+	body, err := c.call(procDomainDefineXML, args.bytes())
+	if err != nil {
+		return DomainRef{}, errors.Annotate(err, "defining domain")
+	}
+	dec := xdrDecoder{data: body}
+	dom := dec.domain()
+	return dom, errors.Trace(dec.err)
+}
+
+// LookupDomain returns the named domain. The error satisfies
+// errors.NotFound if there is no such domain.
+func (c *Conn) LookupDomain(name string) (DomainRef, error) {
+	var args xdrEncoder
+	args.string(name)
+	body, err := c.call(procDomainLookupByName, args.bytes())
+	if err != nil {
+		return DomainRef{}, errors.Annotatef(err, "looking up domain %q", name)
+	}
+	dec := xdrDecoder{data: body}
+	dom := dec.domain()
+	return dom, errors.Trace(dec.err)
+}
+
+// StartDomain starts the named domain.
+func (c *Conn) StartDomain(name string) error {
+	return c.domainCall(name, procDomainCreate, "starting", nil)
+}
+
+// DestroyDomain immediately stops the named domain. The domain remains
+// defined.
+func (c *Conn) DestroyDomain(name string) error {
+	return c.domainCall(name, procDomainDestroy, "destroying", nil)
+}
+
+// UndefineDomain removes the definition of the named domain, along with
+// its NVRAM. Its disk images are left in place.
+func (c *Conn) UndefineDomain(name string) error {
+	return c.domainCall(name, procDomainUndefineFlags, "undefining", func(args *xdrEncoder) {
+		args.uint32(undefineNVRAM)
+	})
+}
+
+// SetAutostart sets whether the named domain starts when the host boots.
+func (c *Conn) SetAutostart(name string, autostart bool) error {
+	return c.domainCall(name, procDomainSetAutostart, "setting autostart for", func(args *xdrEncoder) {
+		args.bool(autostart)
+	})
+}
+
+// DomainState returns the state of the named domain.
+func (c *Conn) DomainState(name string) (DomainState, error) {
+	dom, err := c.LookupDomain(name)
+	if err != nil {
+		return DomainNoState, errors.Trace(err)
+	}
+	return c.domainState(dom)
+}
+
+func (c *Conn) domainState(dom DomainRef) (DomainState, error) {
+	var args xdrEncoder
+	args.domain(dom)
+	args.uint32(0)
+	body, err := c.call(procDomainGetState, args.bytes())
+	if err != nil {
+		return DomainNoState, errors.Annotatef(err, "getting state of domain %q", dom.Name)
+	}
+	dec := xdrDecoder{data: body}
+	state := DomainState(dec.int32())
+	_ = dec.int32() // reason
+	return state, errors.Trace(dec.err)
+}
+
+// ListDomains returns the state of every domain, both running and
+// inactive, keyed by domain name.
+func (c *Conn) ListDomains() (map[string]DomainState, error) {
+	var args xdrEncoder
+	args.int32(1) // need_results
+	args.uint32(0)
+	body, err := c.call(procConnectListAllDomains, args.bytes())
+	if err != nil {
+		return nil, errors.Annotate(err, "listing domains")
+	}
+	dec := xdrDecoder{data: body}
+	n := dec.uint32()
+	if dec.err == nil && int(n) > len(dec.data)/4 {
+		return nil, errors.Errorf("listing domains: invalid domain count %d", n)
+	}
+	domains := make([]DomainRef, n)
+	for i := range domains {
+		domains[i] = dec.domain()
+	}
+	_ = dec.uint32() // ret
+	if dec.err != nil {
+		return nil, errors.Annotate(dec.err, "listing domains")
+	}
+
+	result := make(map[string]DomainState, len(domains))
+	for _, dom := range domains {
+		state, err := c.domainState(dom)
+		if errors.Is(err, errors.NotFound) {
+			// The domain was removed since it was listed.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[dom.Name] = state
+	}
+	return result, nil
+}
+
+func (c *Conn) open(uri string) error {
+	var args xdrEncoder
+	args.optionalString(uri)
+	args.uint32(0)
+	_, err := c.call(procConnectOpen, args.bytes())
+	return errors.Trace(err)
+}
+
+// domainCall looks up the named domain, then makes a call whose arguments
+// are the domain followed by any added by extraArgs.
+func (c *Conn) domainCall(name string, proc int32, action string, extraArgs func(*xdrEncoder)) error {
+	dom, err := c.LookupDomain(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var args xdrEncoder
+	args.domain(dom)
+	if extraArgs != nil {
+		extraArgs(&args)
+	}
+	_, err = c.call(proc, args.bytes())
+	return errors.Annotatef(err, "%s domain %q", action, name)
+}
+
+// call sends a call to libvirt and waits for the reply, returning the body
+// of the reply.
+func (c *Conn) call(proc int32, args []byte) ([]byte, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, errors.Trace(c.err)
+	}
+	c.serial++
+	serial := c.serial
+	replies := make(chan reply, 1)
+	c.pending[serial] = replies
+	c.mu.Unlock()
+
+	if err := c.send(proc, serial, args); err != nil {
+		c.mu.Lock()
+		delete(c.pending, serial)
+		c.mu.Unlock()
+		return nil, errors.Annotate(connectionError(err), "sending libvirt call")
+	}
+
+	r := <-replies
+	if r.err != nil {
+		return nil, errors.Trace(r.err)
+	}
+	if r.status != messageStatusOK {
+		return nil, decodeError(r.body)
+	}
+	return r.body, nil
+}
+
+func (c *Conn) send(proc int32, serial uint32, args []byte) error {
+	var msg xdrEncoder
+	msg.uint32(uint32(headerSize + len(args)))
+	msg.uint32(remoteProgram)
+	msg.uint32(remoteProgramVersion)
+	msg.int32(proc)
+	msg.uint32(messageTypeCall)
+	msg.uint32(serial)
+	msg.uint32(messageStatusOK)
+	msg.buf.Write(args)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(msg.bytes())
+	return errors.Trace(err)
+}
+
+// readLoop reads messages from libvirt, passing each reply to the call
+// waiting for it. Any other messages, such as events, are discarded. When
+// the connection fails or is closed, all waiting calls are failed.
+func (c *Conn) readLoop() {
+	defer close(c.done)
+	err := connectionError(c.readMessages())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+	for serial, replies := range c.pending {
+		replies <- reply{err: err}
+		delete(c.pending, serial)
+	}
+}
+
+func (c *Conn) readMessages() error {
+	var lengthBuf [4]byte
+	for {
+		if _, err := io.ReadFull(c.conn, lengthBuf[:]); err != nil {
+			return errors.Trace(err)
+		}
+		length := binary.BigEndian.Uint32(lengthBuf[:])
+		if length < headerSize || length > maxMessageSize {
+			return errors.Errorf("invalid libvirt message length %d", length)
+		}
+		msg := make([]byte, length-4)
+		if _, err := io.ReadFull(c.conn, msg); err != nil {
+			return errors.Trace(err)
+		}
+
+		dec := xdrDecoder{data: msg}
+		program := dec.uint32()
+		_ = dec.uint32() // version
+		_ = dec.int32()  // procedure
+		msgType := dec.uint32()
+		serial := dec.uint32()
+		status := dec.uint32()
+		if program != remoteProgram || msgType != messageTypeReply {
+			continue
+		}
+
+		c.mu.Lock()
+		replies, ok := c.pending[serial]
+		delete(c.pending, serial)
+		c.mu.Unlock()
+		if !ok {
+			logger.Debugf("discarding libvirt reply with unknown serial %d", serial)
+			continue
+		}
+		replies <- reply{status: status, body: dec.data}
+	}
+}
+
+// connectionError returns ErrConnectionClosed if err reports that the
+// connection has been closed by either end, or err otherwise.
+func connectionError(err error) error {
+	if errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrClosedPipe) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return ErrConnectionClosed
+	}
+	return err
+}
+
+// decodeError decodes the remote_error sent in an error reply. Only the
+// leading fields are used; the remainder describe the object the error
+// relates to, which we already know.
+func decodeError(body []byte) error {
+	dec := xdrDecoder{data: body}
+	libvirtErr := &Error{
+		Code:    ErrorCode(dec.int32()),
+		Domain:  dec.int32(),
+		Message: dec.optionalString(),
+	}
+	if dec.err != nil {
+		return errors.Annotate(dec.err, "decoding libvirt error")
+	}
+	return typedError(libvirtErr)
+}
@@ -0,0 +1,353 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+)
+
+type connSuite struct {
+	testing.IsolationSuite
+
+	daemon *fakeDaemon
+	conn   *Conn
+}
+
+var _ = gc.Suite(&connSuite{})
+
+func (s *connSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	client, server := net.Pipe()
+	s.daemon = newFakeDaemon(server)
+	s.conn = NewConn(client)
+	c.Assert(s.conn.open(systemURI), jc.ErrorIsNil)
+	c.Assert(s.daemon.uri, gc.Equals, systemURI)
+}
+
+func (s *connSuite) TearDownTest(c *gc.C) {
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	s.IsolationSuite.TearDownTest(c)
+}
+
+func (s *connSuite) TestDomainLifecycle(c *gc.C) {
+	dom, err := s.conn.DefineDomain("<domain type='kvm'><name>juju-06f00d-0</name></domain>")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dom.Name, gc.Equals, "juju-06f00d-0")
+	c.Assert(dom.ID, gc.Equals, int32(-1))
+
+	err = s.conn.StartDomain("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.conn.SetAutostart("juju-06f00d-0", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.daemon.domains["juju-06f00d-0"].autostart, jc.IsTrue)
+
+	_, err = s.conn.DefineDomain("<domain type='kvm'><name>juju-06f00d-1</name></domain>")
+	c.Assert(err, jc.ErrorIsNil)
+	domains, err := s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, jc.DeepEquals, map[string]DomainState{
+		"juju-06f00d-0": DomainRunning,
+		"juju-06f00d-1": DomainShutoff,
+	})
+
+	err = s.conn.DestroyDomain("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	state, err := s.conn.DomainState("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state, gc.Equals, DomainShutoff)
+	c.Assert(state.String(), gc.Equals, "shut off")
+
+	err = s.conn.UndefineDomain("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.daemon.undefineFlags, gc.Equals, uint32(undefineNVRAM))
+	domains, err = s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, jc.DeepEquals, map[string]DomainState{
+		"juju-06f00d-1": DomainShutoff,
+	})
+}
+
+func (s *connSuite) TestMissingDomainNotFound(c *gc.C) {
+	err := s.conn.StartDomain("juju-06f00d-9")
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+	c.Assert(err, gc.ErrorMatches, `looking up domain "juju-06f00d-9": Domain not found: no domain with matching name 'juju-06f00d-9'`)
+}
+
+func (s *connSuite) TestDestroyStoppedDomainInvalid(c *gc.C) {
+	_, err := s.conn.DefineDomain("<domain type='kvm'><name>juju-06f00d-0</name></domain>")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.conn.DestroyDomain("juju-06f00d-0")
+	c.Assert(err, gc.ErrorMatches, `destroying domain "juju-06f00d-0": Requested operation is not valid: domain is not running`)
+	c.Assert(IsOperationInvalid(err), jc.IsTrue)
+	c.Assert(err, gc.Not(jc.ErrorIs), errors.NotFound)
+}
+
+func (s *connSuite) TestConcurrentCalls(c *gc.C) {
+	// The fake daemon handles each call in its own goroutine, so replies
+	// are sent in whatever order the calls complete.
+	const count = 20
+	var wg sync.WaitGroup
+	errs := make([]error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("juju-06f00d-%d", i)
+			if _, err := s.conn.DefineDomain(fmt.Sprintf("<domain><name>%s</name></domain>", name)); err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = s.conn.StartDomain(name)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		c.Check(err, jc.ErrorIsNil)
+	}
+	domains, err := s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, gc.HasLen, count)
+	for name, state := range domains {
+		c.Check(state, gc.Equals, DomainRunning, gc.Commentf("domain %q", name))
+	}
+}
+
+func (s *connSuite) TestConnectionClosed(c *gc.C) {
+	s.daemon.hang = true
+	result := make(chan error, 1)
+	go func() {
+		_, err := s.conn.LookupDomain("juju-06f00d-0")
+		result <- err
+	}()
+	s.daemon.waitForCall(c)
+	_ = s.daemon.conn.Close()
+
+	err := <-result
+	c.Assert(err, jc.ErrorIs, ErrConnectionClosed)
+	_, err = s.conn.ListDomains()
+	c.Assert(err, jc.ErrorIs, ErrConnectionClosed)
+}
+
+type fakeDomain struct {
+	dom       DomainRef
+	running   bool
+	autostart bool
+}
+
+// fakeDaemon implements enough of the libvirt remote protocol to exercise
+// Conn.
+type fakeDaemon struct {
+	conn net.Conn
+
+	// hang causes calls to be read but never answered.
+	hang  bool
+	calls chan int32
+
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	uri           string
+	nextID        int32
+	domains       map[string]*fakeDomain
+	undefineFlags uint32
+}
+
+func newFakeDaemon(conn net.Conn) *fakeDaemon {
+	d := &fakeDaemon{
+		conn:    conn,
+		calls:   make(chan int32, 100),
+		domains: make(map[string]*fakeDomain),
+	}
+	go d.serve()
+	return d
+}
+
+func (d *fakeDaemon) waitForCall(c *gc.C) {
+	select {
+	case <-d.calls:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for call")
+	}
+}
+
+func (d *fakeDaemon) serve() {
+	var lengthBuf [4]byte
+	for {
+		if _, err := io.ReadFull(d.conn, lengthBuf[:]); err != nil {
+			return
+		}
+		msg := make([]byte, binary.BigEndian.Uint32(lengthBuf[:])-4)
+		if _, err := io.ReadFull(d.conn, msg); err != nil {
+			return
+		}
+		dec := xdrDecoder{data: msg}
+		_ = dec.uint32() // program
+		_ = dec.uint32() // version
+		proc := dec.int32()
+		_ = dec.uint32() // type
+		serial := dec.uint32()
+		_ = dec.uint32() // status
+		d.calls <- proc
+		if d.hang {
+			continue
+		}
+		go func(body []byte) {
+			reply, libvirtErr := d.handle(proc, &xdrDecoder{data: body})
+			d.reply(proc, serial, reply, libvirtErr)
+		}(dec.data)
+	}
+}
+
+func (d *fakeDaemon) reply(proc int32, serial uint32, body []byte, libvirtErr *Error) {
+	status := uint32(messageStatusOK)
+	if libvirtErr != nil {
+		status = messageStatusError
+		var errBody xdrEncoder
+		errBody.int32(int32(libvirtErr.Code))
+		errBody.int32(libvirtErr.Domain)
+		errBody.optionalString(libvirtErr.Message)
+		errBody.int32(2) // level
+		errBody.bool(false)
+		errBody.optionalString("")
+		errBody.optionalString("")
+		errBody.optionalString("")
+		errBody.int32(0)
+		errBody.int32(0)
+		errBody.bool(false)
+		body = errBody.bytes()
+	}
+	var msg xdrEncoder
+	msg.uint32(uint32(headerSize + len(body)))
+	msg.uint32(remoteProgram)
+	msg.uint32(remoteProgramVersion)
+	msg.int32(proc)
+	msg.uint32(messageTypeReply)
+	msg.uint32(serial)
+	msg.uint32(status)
+	msg.buf.Write(body)
+
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+	_, _ = d.conn.Write(msg.bytes())
+}
+
+func noDomain(name string) *Error {
+	return &Error{
+		Code:    ErrNoDomain,
+		Domain:  10,
+		Message: fmt.Sprintf("Domain not found: no domain with matching name '%s'", name),
+	}
+}
+
+func (d *fakeDaemon) handle(proc int32, args *xdrDecoder) ([]byte, *Error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result xdrEncoder
+	lookup := func() (*fakeDomain, *Error) {
+		dom := args.domain()
+		fake, ok := d.domains[dom.Name]
+		if !ok {
+			return nil, noDomain(dom.Name)
+		}
+		return fake, nil
+	}
+
+	switch proc {
+	case procConnectOpen:
+		d.uri = args.optionalString()
+	case procConnectClose:
+	case procDomainDefineXML:
+		var spec struct {
+			Name string `xml:"name"`
+		}
+		if err := xml.Unmarshal([]byte(args.string()), &spec); err != nil {
+			return nil, &Error{Code: 27, Message: err.Error()}
+		}
+		fake := &fakeDomain{dom: DomainRef{Name: spec.Name, ID: -1}}
+		fake.dom.UUID[0] = byte(len(d.domains) + 1)
+		d.domains[spec.Name] = fake
+		result.domain(fake.dom)
+	case procDomainLookupByName:
+		name := args.string()
+		fake, ok := d.domains[name]
+		if !ok {
+			return nil, noDomain(name)
+		}
+		result.domain(fake.dom)
+	case procDomainCreate:
+		fake, libvirtErr := lookup()
+		if libvirtErr != nil {
+			return nil, libvirtErr
+		}
+		d.nextID++
+		fake.running = true
+		fake.dom.ID = d.nextID
+	case procDomainDestroy:
+		fake, libvirtErr := lookup()
+		if libvirtErr != nil {
+			return nil, libvirtErr
+		}
+		if !fake.running {
+			return nil, &Error{
+				Code:    ErrOperationInvalid,
+				Domain:  10,
+				Message: "Requested operation is not valid: domain is not running",
+			}
+		}
+		fake.running = false
+		fake.dom.ID = -1
+	case procDomainUndefineFlags:
+		fake, libvirtErr := lookup()
+		if libvirtErr != nil {
+			return nil, libvirtErr
+		}
+		d.undefineFlags = args.uint32()
+		delete(d.domains, fake.dom.Name)
+	case procDomainSetAutostart:
+		fake, libvirtErr := lookup()
+		if libvirtErr != nil {
+			return nil, libvirtErr
+		}
+		fake.autostart = args.bool()
+	case procDomainGetState:
+		fake, libvirtErr := lookup()
+		if libvirtErr != nil {
+			return nil, libvirtErr
+		}
+		if fake.running {
+			result.int32(int32(DomainRunning))
+		} else {
+			result.int32(int32(DomainShutoff))
+		}
+		result.int32(1)
+	case procConnectListAllDomains:
+		result.uint32(uint32(len(d.domains)))
+		for _, fake := range d.domains {
+			result.domain(fake.dom)
+		}
+		result.uint32(uint32(len(d.domains)))
+	default:
+		return nil, &Error{Code: 3, Message: fmt.Sprintf("procedure %d not supported", proc)}
+	}
+	if args.err != nil {
+		return nil, &Error{Code: 1, Message: args.err.Error()}
+	}
+	return result.bytes(), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// ErrorCode is a libvirt virErrorNumber.
+type ErrorCode int32
+
+const (
+	// ErrNoDomain is returned by libvirt when a domain doesn't exist.
+	ErrNoDomain ErrorCode = 42
+
+	// ErrOperationInvalid is returned by libvirt when an operation isn't
+	// valid for the domain's current state, such as destroying a domain
+	// which isn't running.
+	ErrOperationInvalid ErrorCode = 55
+)
+
+// ErrConnectionClosed is returned for calls made on, or interrupted by, a
+// closed connection to libvirt.
+const ErrConnectionClosed = errors.ConstError("libvirt connection closed")
+
+// Error is an error reported by libvirt in reply to a call.
+type Error struct {
+	// Code identifies the error.
+	Code ErrorCode
+
+	// Domain is the libvirt subsystem which raised the error. It isn't
+	// related to guest domains.
+	Domain int32
+
+	// Message describes the error.
+	Message string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("libvirt error %d", e.Code)
+	}
+	return e.Message
+}
+
+// typedError returns err with the juju/errors type matching its code, so
+// that callers can check for missing domains with errors.Is(err,
+// errors.NotFound).
+func typedError(err *Error) error {
+	if err.Code == ErrNoDomain {
+		return errors.WithType(err, errors.NotFound)
+	}
+	return err
+}
+
+// IsOperationInvalid returns true if err is a libvirt error reporting that
+// an operation isn't valid in the domain's current state.
+func IsOperationInvalid(err error) bool {
+	var libvirtErr *Error
+	return errors.As(err, &libvirtErr) && libvirtErr.Code == ErrOperationInvalid
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/juju/errors"
+)
+
+// The libvirt remote protocol encodes messages using XDR (RFC 4506). We
+// only need the handful of XDR types used by the procedures called here,
+// so they are implemented directly rather than through a general purpose
+// reflection based encoder.
+
+// xdrEncoder appends XDR encoded values to a buffer.
+type xdrEncoder struct {
+	buf bytes.Buffer
+}
+
+func (e *xdrEncoder) uint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *xdrEncoder) int32(v int32) {
+	e.uint32(uint32(v))
+}
+
+func (e *xdrEncoder) bool(v bool) {
+	if v {
+		e.uint32(1)
+	} else {
+		e.uint32(0)
+	}
+}
+
+// opaque encodes fixed length opaque data, padded to a multiple of four
+// bytes.
+func (e *xdrEncoder) opaque(v []byte) {
+	e.buf.Write(v)
+	if pad := (4 - len(v)%4) % 4; pad > 0 {
+		e.buf.Write(make([]byte, pad))
+	}
+}
+
+func (e *xdrEncoder) string(v string) {
+	e.uint32(uint32(len(v)))
+	e.opaque([]byte(v))
+}
+
+// optionalString encodes a libvirt remote_string, which is a pointer to
+// a string; an empty string is sent as a nil pointer.
+func (e *xdrEncoder) optionalString(v string) {
+	e.bool(v != "")
+	if v != "" {
+		e.string(v)
+	}
+}
+
+func (e *xdrEncoder) domain(d DomainRef) {
+	e.string(d.Name)
+	e.opaque(d.UUID[:])
+	e.int32(d.ID)
+}
+
+func (e *xdrEncoder) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// xdrDecoder reads XDR encoded values from a message body.
+type xdrDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *xdrDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = errors.Errorf("truncated message: need %d bytes, have %d", n, len(d.data))
+		return nil
+	}
+	v := d.data[:n]
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *xdrDecoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *xdrDecoder) int32() int32 {
+	return int32(d.uint32())
+}
+
+func (d *xdrDecoder) bool() bool {
+	return d.uint32() != 0
+}
+
+func (d *xdrDecoder) opaque(n int) []byte {
+	b := d.next(n + (4-n%4)%4)
+	if b == nil {
+		return nil
+	}
+	return b[:n]
+}
+
+func (d *xdrDecoder) string() string {
+	n := d.uint32()
+	if d.err == nil && int(n) > len(d.data) {
+		d.err = errors.Errorf("truncated message: string of %d bytes", n)
+		return ""
+	}
+	return string(d.opaque(int(n)))
+}
+
+func (d *xdrDecoder) optionalString() string {
+	if !d.bool() {
+		return ""
+	}
+	return d.string()
+}
+
+func (d *xdrDecoder) domain() DomainRef {
+	dom := DomainRef{Name: d.string()}
+	copy(dom.UUID[:], d.opaque(len(dom.UUID)))
+	dom.ID = d.int32()
+	return dom
+}
@@ -6,23 +6,19 @@ package kvm
 // This file contains wrappers around the following executables:
 //   genisoimage
 //   qemu-img
-//   virsh
 // Those executables are found in the following packages:
 //   genisoimage
-//   libvirt-bin
 //   qemu-utils
 //
-// These executables provide Juju's interface to dealing with kvm containers.
-// They are the means by which we start, stop and list running containers on
-// the host
+// These executables create the disk images for kvm containers. The
+// containers themselves are defined, started, stopped and listed by calls
+// to libvirt over its RPC socket.
 
 import (
 	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/v3"
@@ -49,13 +45,29 @@ const (
 	nvramCode = "/usr/share/AAVMF/AAVMF_CODE.fd"
 )
 
-var (
-	// The regular expression for breaking up the results of 'virsh list'
-	// (?m) - specify that this is a multi-line regex
-	// first part is the opaque identifier we don't care about
-	// then the hostname, and lastly the status.
-	machineListPattern = regexp.MustCompile(`(?m)^\s+\d+\s+(?P<hostname>[-\w]+)\s+(?P<status>.+)\s*$`)
-)
+// libvirtConn describes the libvirt calls used to manage kvm containers.
+// It is implemented by *libvirt.Conn.
+type libvirtConn interface {
+	DefineDomain(domainXML string) (libvirt.DomainRef, error)
+	StartDomain(name string) error
+	DestroyDomain(name string) error
+	UndefineDomain(name string) error
+	SetAutostart(name string, autostart bool) error
+	ListDomains() (map[string]libvirt.DomainState, error)
+	Close() error
+}
+
+// dialFunc provides the signature for connecting to libvirt.
+type dialFunc func() (libvirtConn, error)
+
+// dialLibvirt connects to the system libvirt daemon over its RPC socket.
+func dialLibvirt() (libvirtConn, error) {
+	conn, err := libvirt.Dial()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return conn, nil
+}
 
 // CreateMachineParams Implements libvirt.domainParams.
 type CreateMachineParams struct {
@@ -71,9 +83,9 @@ type CreateMachineParams struct {
 	disks    []libvirt.DiskInfo
 	findPath pathfinderFunc
 
-	runCmd       runFunc
-	runCmdAsRoot runFunc
-	arch         string
+	runCmd runFunc
+	dial   dialFunc
+	arch   string
 }
 
 // Arch returns the architecture to be used.
@@ -191,24 +203,28 @@ func CreateMachine(params CreateMachineParams) error {
 	params.disks = append(params.disks, diskInfo{source: imgPath, driver: "qcow2"})
 	params.disks = append(params.disks, diskInfo{source: dsPath, driver: "raw"})
 
-	domainPath, err := writeDomainXML(templateDir, params)
+	domainPath, domainXML, err := writeDomainXML(templateDir, params)
 	if err != nil {
 		return errors.Annotatef(err, "failed to write domain xml for %q", params.Host())
 	}
 
-	out, err := params.runCmdAsRoot("", virsh, "define", domainPath)
+	conn, err := params.dial()
 	if err != nil {
-		return errors.Annotatef(err, "failed to define the domain for %q from %s:%s", params.Host(), domainPath, out)
+		return errors.Trace(err)
 	}
-	logger.Debugf("created domain: %s", out)
+	defer func() { _ = conn.Close() }()
 
-	out, err = params.runCmdAsRoot("", virsh, "start", params.Host())
+	dom, err := conn.DefineDomain(domainXML)
 	if err != nil {
-		return errors.Annotatef(err, "failed to start domain %q:%s", params.Host(), out)
+		return errors.Annotatef(err, "failed to define the domain for %q from %s", params.Host(), domainPath)
 	}
-	logger.Debugf("started domain: %s", out)
+	logger.Debugf("created domain %q", dom.Name)
 
-	return err
+	if err := conn.StartDomain(params.Host()); err != nil {
+		return errors.Annotatef(err, "failed to start domain %q", params.Host())
+	}
+	logger.Debugf("started domain %q", params.Host())
+	return nil
 }
 
 // Setup the default values for params.
@@ -219,40 +235,46 @@ func setDefaults(p *CreateMachineParams) {
 	if p.runCmd == nil {
 		p.runCmd = runAsLibvirt
 	}
-	if p.runCmdAsRoot == nil {
-		p.runCmdAsRoot = run
+	if p.dial == nil {
+		p.dial = dialLibvirt
 	}
 }
 
 // DestroyMachine destroys the virtual machine represented by the kvmContainer.
 func DestroyMachine(c *kvmContainer) error {
-	if c.runCmd == nil {
-		c.runCmd = run
+	if c.dial == nil {
+		c.dial = dialLibvirt
 	}
 	if c.pathfinder == nil {
 		c.pathfinder = paths.DataDir
 	}
 
-	// We don't return errors for virsh commands because it is possible that we
-	// didn't succeed in creating the domain. Additionally, we want all the
-	// commands to run. If any fail it is certainly because the thing we're
-	// trying to remove wasn't created. However, we still want to try removing
-	// all the parts. The exception here is getting the guestBase, if that
-	// fails we return the error because we cannot continue without it.
-
-	_, err := c.runCmd("", virsh, "destroy", c.Name())
+	conn, err := c.dial()
 	if err != nil {
-		logger.Infof("`%s destroy %s` failed: %q", virsh, c.Name(), err)
+		return errors.Trace(err)
 	}
+	defer func() { _ = conn.Close() }()
 
-	// The nvram flag here removes the pflash drive for us. There is also a
-	// `remove-all-storage` flag, but it is unclear if that would also remove
-	// the backing store which we don't want to do. So we remove those manually
-	// after undefining.
-	_, err = c.runCmd("", virsh, "undefine", "--nvram", c.Name())
-	if err != nil {
-		logger.Infof("`%s undefine --nvram %s` failed: %q", virsh, c.Name(), err)
+	// The domain may not exist, or not be running, if we didn't succeed in
+	// creating it. We still want to try removing all the parts, so those
+	// errors are expected here.
+	err = conn.DestroyDomain(c.Name())
+	if errors.Is(err, errors.NotFound) || libvirt.IsOperationInvalid(err) {
+		logger.Debugf("domain %q not running: %v", c.Name(), err)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to destroy domain %q", c.Name())
 	}
+
+	// Undefining removes the pflash drive for us, but not the disks, which
+	// we remove manually afterwards. The backing store must not be removed
+	// as other domains may be using it.
+	err = conn.UndefineDomain(c.Name())
+	if errors.Is(err, errors.NotFound) {
+		logger.Debugf("domain %q not defined: %v", c.Name(), err)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to undefine domain %q", c.Name())
+	}
+
 	guestBase, err := guestPath(c.pathfinder)
 	if err != nil {
 		return errors.Trace(err)
@@ -272,35 +294,32 @@ func DestroyMachine(c *kvmContainer) error {
 // AutostartMachine indicates that the virtual machines should automatically
 // restart when the host restarts.
 func AutostartMachine(c *kvmContainer) error {
-	if c.runCmd == nil {
-		c.runCmd = run
+	if c.dial == nil {
+		c.dial = dialLibvirt
 	}
-	_, err := c.runCmd("", virsh, "autostart", c.Name())
+	conn, err := c.dial()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	err = conn.SetAutostart(c.Name(), true)
 	return errors.Annotatef(err, "failed to autostart domain %q", c.Name())
 }
 
-// ListMachines returns a map of machine name to state, where state is one of:
-// running, idle, paused, shutdown, shut off, crashed, dying, pmsuspended.
-func ListMachines(runCmd runFunc) (map[string]string, error) {
-	if runCmd == nil {
-		runCmd = run
+// ListMachines returns a map of machine name to state.
+func ListMachines(dial dialFunc) (map[string]libvirt.DomainState, error) {
+	if dial == nil {
+		dial = dialLibvirt
 	}
-
-	output, err := runCmd("", virsh, "-q", "list", "--all")
+	conn, err := dial()
 	if err != nil {
-		return nil, err
-	}
-	// Split the output into lines.
-	// Regex matching is the easiest way to match the lines.
-	//   id hostname status
-	// separated by whitespace, with whitespace at the start too.
-	result := make(map[string]string)
-	for _, s := range machineListPattern.FindAllStringSubmatchIndex(output, -1) {
-		hostnameAndStatus := machineListPattern.ExpandString(nil, "$hostname $status", output, s)
-		parts := strings.SplitN(string(hostnameAndStatus), " ", 2)
-		result[parts[0]] = parts[1]
-	}
-	return result, nil
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	machines, err := conn.ListDomains()
+	return machines, errors.Trace(err)
 }
 
 // guestPath returns the path to the guest directory from the given
@@ -370,22 +389,23 @@ func writeDataSourceVolume(params CreateMachineParams) (string, error) {
 }
 
 // writeDomainXML writes out the configuration required to create a new guest
-// domain.
-func writeDomainXML(templateDir string, p CreateMachineParams) (string, error) {
+// domain, returning both its path and its contents. The file is not used to
+// define the domain, but is kept to help diagnose problems with it.
+func writeDomainXML(templateDir string, p CreateMachineParams) (string, string, error) {
 	domainPath := filepath.Join(templateDir, fmt.Sprintf("%s.xml", p.Host()))
 	dom, err := libvirt.NewDomain(p)
 	if err != nil {
-		return "", errors.Trace(err)
+		return "", "", errors.Trace(err)
 	}
 
 	ml, err := xml.MarshalIndent(&dom, "", "    ")
 	if err != nil {
-		return "", errors.Trace(err)
+		return "", "", errors.Trace(err)
 	}
 
 	f, err := os.Create(domainPath)
 	if err != nil {
-		return "", errors.Trace(err)
+		return "", "", errors.Trace(err)
 	}
 	defer func() {
 		err = f.Close()
@@ -396,10 +416,10 @@ func writeDomainXML(templateDir string, p CreateMachineParams) (string, error) {
 
 	_, err = f.Write(ml)
 	if err != nil {
-		return "", errors.Trace(err)
+		return "", "", errors.Trace(err)
 	}
 
-	return domainPath, nil
+	return domainPath, string(ml), nil
 }
 
 // writeMetadata writes out a metadata file with an UUID instance-id. The
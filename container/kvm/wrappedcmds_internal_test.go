@@ -49,9 +49,13 @@ func (libvirtInternalSuite) TestWriteDomainXMLSucceeds(c *gc.C) {
 		},
 	}
 
-	got, err := writeDomainXML(d, p)
+	got, domainXML, err := writeDomainXML(d, p)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(got, gc.Matches, `/tmp/check-.*/\d+/host00.xml`)
+	c.Assert(domainXML, jc.Contains, "<name>host00</name>")
+	b, err := os.ReadFile(got)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, domainXML)
 }
 
 func (libvirtInternalSuite) TestWriteDomainXMLMissingValidSystemDisk(c *gc.C) {
@@ -72,7 +76,7 @@ func (libvirtInternalSuite) TestWriteDomainXMLMissingValidSystemDisk(c *gc.C) {
 		},
 	}
 
-	got, err := writeDomainXML(d, p)
+	got, _, err := writeDomainXML(d, p)
 	c.Assert(err, gc.ErrorMatches, "missing system disk")
 	c.Assert(got, gc.Matches, "")
 }
@@ -92,7 +96,7 @@ func (libvirtInternalSuite) TestWriteDomainXMLMissingOneDisk(c *gc.C) {
 		},
 	}
 
-	got, err := writeDomainXML(d, p)
+	got, _, err := writeDomainXML(d, p)
 	c.Assert(err, gc.ErrorMatches, "got 1 disks, need at least 2")
 	c.Assert(got, gc.Matches, "")
 }
@@ -108,7 +112,7 @@ func (libvirtInternalSuite) TestWriteDomainXMLMissingBothDisk(c *gc.C) {
 		disks:    []libvirt.DiskInfo{},
 	}
 
-	got, err := writeDomainXML(d, p)
+	got, _, err := writeDomainXML(d, p)
 	c.Assert(err, gc.ErrorMatches, "got 0 disks, need at least 2")
 	c.Assert(got, gc.Matches, "")
 }
@@ -130,7 +134,7 @@ func (libvirtInternalSuite) TestWriteDomainXMLNoHostname(c *gc.C) {
 		},
 	}
 
-	got, err := writeDomainXML(d, p)
+	got, _, err := writeDomainXML(d, p)
 	c.Assert(err, gc.ErrorMatches, "missing required hostname")
 	c.Assert(got, gc.Matches, "")
 }
@@ -8,11 +8,13 @@ import (
 	"os"
 	"path/filepath"
 
+	jujuerrors "github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	. "github.com/juju/juju/container/kvm"
+	"github.com/juju/juju/container/kvm/libvirt"
 	"github.com/juju/juju/core/paths"
 	"github.com/juju/juju/environs/imagedownloads"
 	"github.com/juju/juju/environs/simplestreams"
@@ -108,14 +110,44 @@ func (s *commandWrapperSuite) TestCreateMachineSuccessOnFocal(c *gc.C) {
 		// On focal, the backing image format must be explicitly specified
 		// hence the '-F raw'
 		` qemu-img create -b \/tmp/juju-libvirtSuite-\d+\/kvm\/guests\/20.04-arm64-backing-file.qcow -F raw -f qcow2 \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00.qcow 8G`,
-		` virsh define \/tmp\/juju-libvirtSuite-\d+\/host00.xml`,
-		" virsh start host00",
 	}
 
 	assertCreateMachineSuccess(c, tmpDir, want)
 }
+
+func (s *commandWrapperSuite) TestCreateMachineStartFails(c *gc.C) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = os.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	libvirtStub := NewLibvirtStub(nil)
+	libvirtStub.SetError("StartDomain", errors.New("Boom"))
+	params := CreateMachineParams{
+		Hostname:     "host00",
+		Version:      "20.04",
+		UserDataFile: cloudInitPath,
+		RootDisk:     8,
+	}
+	pathfinder := func(_ paths.OS) string {
+		return tmpDir
+	}
+	MakeCreateMachineParamsTestable(&params, pathfinder, NewRunStub("success", nil).Run, libvirtStub.Dial, "arm64")
+	err = CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `failed to start domain "host00": Boom`)
+	c.Assert(libvirtStub.Calls(), jc.DeepEquals, []string{
+		"Dial",
+		"DefineDomain",
+		"StartDomain host00",
+		"Close",
+	})
+}
+
 func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
 	stub := NewRunStub("success", nil)
+	libvirtStub := NewLibvirtStub(nil)
 
 	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
 	c.Check(err, jc.ErrorIsNil)
@@ -145,14 +177,25 @@ func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
 		RootDisk:          8,
 	}
 
-	MakeCreateMachineParamsTestable(&params, pathfinder, stub.Run, "arm64")
+	MakeCreateMachineParamsTestable(&params, pathfinder, stub.Run, libvirtStub.Dial, "arm64")
 	err = CreateMachine(params)
 	c.Assert(err, jc.ErrorIsNil)
 
+	c.Assert(libvirtStub.Calls(), jc.DeepEquals, []string{
+		"Dial",
+		"DefineDomain",
+		"StartDomain host00",
+		"Close",
+	})
+	c.Assert(libvirtStub.DomainXML(), jc.Contains, "<name>host00</name>")
+	b, err := os.ReadFile(filepath.Join(tmpDir, "host00.xml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(b), gc.Equals, libvirtStub.DomainXML())
+
 	_, err = os.Stat(cloudInitPath)
 	c.Assert(os.IsNotExist(err), jc.IsTrue)
 
-	b, err := os.ReadFile(userDataPath)
+	b, err = os.ReadFile(userDataPath)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(b), jc.Contains, "#cloud-init")
 
@@ -167,10 +210,9 @@ func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
 }
 
 func (commandWrapperSuite) TestDestroyMachineSuccess(c *gc.C) {
-	tmpDir, err := os.MkdirTemp("", "juju-libvirtSuite-")
-	c.Check(err, jc.ErrorIsNil)
+	tmpDir := c.MkDir()
 	guestBase := filepath.Join(tmpDir, "kvm", "guests")
-	err = os.MkdirAll(guestBase, 0700)
+	err := os.MkdirAll(guestBase, 0700)
 	c.Check(err, jc.ErrorIsNil)
 
 	err = os.WriteFile(filepath.Join(guestBase, "aname.qcow"), []byte("diskcontents"), 0700)
@@ -182,70 +224,97 @@ func (commandWrapperSuite) TestDestroyMachineSuccess(c *gc.C) {
 		return tmpDir
 	}
 
-	stub := NewRunStub("success", nil)
-	container := NewTestContainer("aname", stub.Run, pathfinder)
+	stub := NewLibvirtStub(nil)
+	container := NewTestContainer("aname", stub.Dial, pathfinder)
 	err = DestroyMachine(container)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(stub.Calls(), jc.DeepEquals, []string{
-		" virsh destroy aname",
-		" virsh undefine --nvram aname",
+		"Dial",
+		"DestroyDomain aname",
+		"UndefineDomain aname",
+		"Close",
+	})
+	_, err = os.Stat(filepath.Join(guestBase, "aname.qcow"))
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+	_, err = os.Stat(filepath.Join(guestBase, "aname-ds.iso"))
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (commandWrapperSuite) TestDestroyMachineNotDefined(c *gc.C) {
+	// A domain which was never created, or never started, can still be
+	// cleaned up.
+	stub := NewLibvirtStub(nil)
+	stub.SetError("DestroyDomain", &libvirt.Error{Code: libvirt.ErrOperationInvalid, Message: "domain is not running"})
+	stub.SetError("UndefineDomain", jujuerrors.NotFoundf("domain %q", "aname"))
+	container := NewTestContainer("aname", stub.Dial, func(paths.OS) string { return c.MkDir() })
+	err := DestroyMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stub.Calls(), jc.DeepEquals, []string{
+		"Dial",
+		"DestroyDomain aname",
+		"UndefineDomain aname",
+		"Close",
 	})
 }
 
 func (commandWrapperSuite) TestDestroyMachineFails(c *gc.C) {
-	stub := NewRunStub("", errors.New("Boom"))
-	container := NewTestContainer("aname", stub.Run, nil)
+	stub := NewLibvirtStub(nil)
+	stub.SetError("DestroyDomain", errors.New("Boom"))
+	container := NewTestContainer("aname", stub.Dial, nil)
 	err := DestroyMachine(container)
+	c.Assert(err, gc.ErrorMatches, `failed to destroy domain "aname": Boom`)
 	c.Check(stub.Calls(), jc.DeepEquals, []string{
-		" virsh destroy aname",
-		" virsh undefine --nvram aname",
+		"Dial",
+		"DestroyDomain aname",
+		"Close",
 	})
-	log := c.GetTestLog()
-	c.Check(log, jc.Contains, "`virsh destroy aname` failed")
-	c.Check(log, jc.Contains, "`virsh undefine --nvram aname` failed")
-	c.Assert(err, jc.ErrorIsNil)
+}
 
+func (commandWrapperSuite) TestDestroyMachineCannotConnect(c *gc.C) {
+	stub := NewLibvirtStub(nil)
+	stub.SetError("Dial", errors.New("no socket"))
+	container := NewTestContainer("aname", stub.Dial, nil)
+	err := DestroyMachine(container)
+	c.Assert(err, gc.ErrorMatches, "no socket")
 }
 
 func (commandWrapperSuite) TestAutostartMachineSuccess(c *gc.C) {
-	stub := NewRunStub("success", nil)
-	container := NewTestContainer("aname", stub.Run, nil)
+	stub := NewLibvirtStub(nil)
+	container := NewTestContainer("aname", stub.Dial, nil)
 	err := AutostartMachine(container)
-	c.Assert(stub.Calls(), jc.DeepEquals, []string{" virsh autostart aname"})
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{"Dial", "SetAutostart aname", "Close"})
 	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (commandWrapperSuite) TestAutostartMachineFails(c *gc.C) {
-	stub := NewRunStub("", errors.New("Boom"))
-	container := NewTestContainer("aname", stub.Run, nil)
+	stub := NewLibvirtStub(nil)
+	stub.SetError("SetAutostart", errors.New("Boom"))
+	container := NewTestContainer("aname", stub.Dial, nil)
 	err := AutostartMachine(container)
-	c.Assert(stub.Calls(), jc.DeepEquals, []string{" virsh autostart aname"})
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{"Dial", "SetAutostart aname", "Close"})
 	c.Check(err, gc.ErrorMatches, `failed to autostart domain "aname": Boom`)
 }
 
 func (commandWrapperSuite) TestListMachinesSuccess(c *gc.C) {
-	output := `
- Id    Name                           State
-----------------------------------------------------
- 0     Domain-0                       running
- 2     ubuntu                         paused
-`[1:]
-	stub := NewRunStub(output, nil)
-	got, err := ListMachines(stub.Run)
+	stub := NewLibvirtStub(map[string]libvirt.DomainState{
+		"juju-06f00d-0": libvirt.DomainRunning,
+		"ubuntu":        libvirt.DomainPaused,
+	})
+	got, err := ListMachines(stub.Dial)
 
 	c.Check(err, jc.ErrorIsNil)
-	c.Check(stub.Calls(), jc.DeepEquals, []string{" virsh -q list --all"})
-	c.Assert(got, jc.DeepEquals, map[string]string{
-		"Domain-0": "running",
-		"ubuntu":   "paused",
+	c.Check(stub.Calls(), jc.DeepEquals, []string{"Dial", "ListDomains", "Close"})
+	c.Assert(got, jc.DeepEquals, map[string]libvirt.DomainState{
+		"juju-06f00d-0": libvirt.DomainRunning,
+		"ubuntu":        libvirt.DomainPaused,
 	})
-
 }
 
 func (commandWrapperSuite) TestListMachinesFails(c *gc.C) {
-	stub := NewRunStub("", errors.New("Boom"))
-	got, err := ListMachines(stub.Run)
+	stub := NewLibvirtStub(nil)
+	stub.SetError("ListDomains", errors.New("Boom"))
+	got, err := ListMachines(stub.Dial)
 	c.Check(err, gc.ErrorMatches, "Boom")
-	c.Check(stub.Calls(), jc.DeepEquals, []string{" virsh -q list --all"})
+	c.Check(stub.Calls(), jc.DeepEquals, []string{"Dial", "ListDomains", "Close"})
 	c.Assert(got, gc.IsNil)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/lxdprofile"
+	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -49,6 +50,10 @@ type containerManager struct {
 	imageMetadataDefaultsDisabled bool
 	imageMutex                    sync.Mutex
 
+	// preferIPv6 indicates that the default LXD bridge should be
+	// configured to allocate IPv6 addresses to containers.
+	preferIPv6 bool
+
 	serverInitMutex sync.Mutex
 	profileMutex    sync.Mutex
 }
@@ -85,6 +90,8 @@ func NewContainerManager(cfg container.ManagerConfig, newServer func() (*Server,
 	// for it when calling WarnAboutUnused() below.
 	_ = cfg.PopValue(config.LXDSnapChannel)
 
+	preferIPv6 := cfg.PopValue(config.PreferredAddressFamilyKey) == string(corenetwork.IPv6Address)
+
 	cfg.WarnAboutUnused()
 	return &containerManager{
 		newServer:                     newServer,
@@ -94,6 +101,7 @@ func NewContainerManager(cfg container.ManagerConfig, newServer func() (*Server,
 		imageMetadataURL:              imageMetaDataURL,
 		imageStream:                   imageStream,
 		imageMetadataDefaultsDisabled: imageMetadataDefaultsDisabled,
+		preferIPv6:                    preferIPv6,
 	}, nil
 }
 
@@ -245,7 +253,8 @@ func (m *containerManager) getContainerSpec(
 	logger.Debugf("configuring container %q with network devices: %v", name, nics)
 
 	// If the default LXD bridge was supplied in network config,
-	// but without a CIDR, attempt to ensure it is configured for IPv4.
+	// but without a CIDR, attempt to ensure it is configured for IPv4,
+	// and for IPv6 if that is the model's preferred address family.
 	// If there are others with incomplete info, log a warning.
 	if len(unknown) > 0 {
 		if len(unknown) == 1 && unknown[0] == network.DefaultLXDBridge && m.server.networkAPISupport {
@@ -256,6 +265,15 @@ func (m *containerManager) getContainerSpec(
 			if mod {
 				logger.Infof(`added "auto" IPv4 configuration to default LXD bridge`)
 			}
+			if m.preferIPv6 {
+				mod, err := m.server.EnsureIPv6(network.DefaultLXDBridge)
+				if err != nil {
+					return ContainerSpec{}, errors.Annotate(err, "ensuring default bridge IPv6 config")
+				}
+				if mod {
+					logger.Infof(`added "auto" IPv6 configuration to default LXD bridge`)
+				}
+			}
 		} else {
 			logger.Warningf("no CIDR was detected for the following networks: %v", unknown)
 		}
@@ -239,6 +239,65 @@ func (s *managerSuite) TestContainerCreateUpdateIPv4Network(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *managerSuite) TestContainerCreateUpdateIPv6Network(c *gc.C) {
+	ctrl := s.setupWithExtensions(c, "network")
+	defer ctrl.Finish()
+
+	s.patch()
+
+	cfg := getBaseConfig()
+	cfg[config.PreferredAddressFamilyKey] = "ipv6"
+	s.makeManagerForConfig(c, cfg)
+	iCfg := prepInstanceConfig(c)
+	hostName, err := s.manager.Namespace().Hostname(iCfg.MachineId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	exp := s.cSvr.EXPECT()
+
+	ipv4Net := func() *lxdapi.Network {
+		return &lxdapi.Network{NetworkPut: lxdapi.NetworkPut{Config: map[string]string{
+			"ipv4.address": "10.5.3.1/24",
+			"ipv6.address": "none",
+		}}}
+	}
+	req := lxdapi.NetworkPut{
+		Config: map[string]string{
+			"ipv4.address": "10.5.3.1/24",
+			"ipv6.address": "auto",
+			"ipv6.nat":     "true",
+		},
+	}
+	gomock.InOrder(
+		exp.GetNetwork(network.DefaultLXDBridge).Return(ipv4Net(), lxdtesting.ETag, nil),
+		exp.GetNetwork(network.DefaultLXDBridge).Return(ipv4Net(), lxdtesting.ETag, nil),
+		exp.UpdateNetwork(network.DefaultLXDBridge, req, lxdtesting.ETag).Return(nil),
+	)
+
+	s.expectCreateContainer(ctrl)
+	s.expectStartOp(ctrl)
+
+	exp.UpdateInstanceState(hostName, lxdapi.InstanceStatePut{Action: "start", Timeout: -1}, "").Return(s.startOp, nil)
+	inst := &lxdapi.Instance{
+		Name:        hostName,
+		Type:        "container",
+		InstancePut: lxdapi.InstancePut{Architecture: "amd64"},
+	}
+	exp.GetInstance(hostName).Return(inst, lxdtesting.ETag, nil)
+
+	// With IPv6 preferred, the default bridge is also updated with
+	// IPv6 config when it has none.
+	netConfig := container.BridgeNetworkConfig(1500, corenetwork.InterfaceInfos{{
+		InterfaceName:       "eth0",
+		InterfaceType:       corenetwork.EthernetDevice,
+		ConfigType:          corenetwork.ConfigDHCP,
+		ParentInterfaceName: network.DefaultLXDBridge,
+	}})
+	_, _, err = s.manager.CreateContainer(
+		stdcontext.Background(), iCfg, constraints.Value{}, corebase.MakeDefaultBase("ubuntu", "16.04"), netConfig, &container.StorageConfig{}, lxdtesting.NoOpCallback,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *managerSuite) TestCreateContainerCreateFailed(c *gc.C) {
 	ctrl := s.setup(c)
 	defer ctrl.Finish()
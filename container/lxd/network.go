@@ -85,6 +85,35 @@ func (s *Server) EnsureIPv4(netName string) (bool, error) {
 	return modified, nil
 }
 
+// EnsureIPv6 retrieves the network for the input name and checks its IPv6
+// configuration. If none is detected, it is set to "auto", so that
+// containers on the network are allocated IPv6 addresses as well as
+// any IPv4 ones. The boolean return indicates if modification was necessary.
+func (s *Server) EnsureIPv6(netName string) (bool, error) {
+	var modified bool
+
+	net, eTag, err := s.GetNetwork(netName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	cfg, ok := net.Config["ipv6.address"]
+	if !ok || cfg == "none" {
+		if net.Config == nil {
+			net.Config = make(device, 2)
+		}
+		net.Config["ipv6.address"] = "auto"
+		net.Config["ipv6.nat"] = "true"
+
+		if err := s.UpdateNetwork(netName, net.Writable(), eTag); err != nil {
+			return false, errors.Trace(err)
+		}
+		modified = true
+	}
+
+	return modified, nil
+}
+
 // GetNICsFromProfile returns all NIC devices in the profile with the input
 // name. All returned devices have a MAC address; generated if required.
 func (s *Server) GetNICsFromProfile(profileName string) (map[string]device, error) {
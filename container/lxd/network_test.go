@@ -102,6 +102,61 @@ func (s *networkSuite) TestEnsureIPv4Modified(c *gc.C) {
 	c.Check(mod, jc.IsTrue)
 }
 
+func (s *networkSuite) TestEnsureIPv6NoChange(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network")
+
+	net := &lxdapi.Network{
+		NetworkPut: lxdapi.NetworkPut{
+			Config: map[string]string{
+				"ipv6.address": "fd42:9b2b:7e65:1a5c::1/64",
+			},
+		},
+	}
+	cSvr.EXPECT().GetNetwork("some-net-name").Return(net, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mod, err := jujuSvr.EnsureIPv6("some-net-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mod, jc.IsFalse)
+}
+
+func (s *networkSuite) TestEnsureIPv6Modified(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "network")
+
+	net := &lxdapi.Network{
+		NetworkPut: lxdapi.NetworkPut{
+			Config: map[string]string{
+				"ipv4.address": "10.5.3.1/24",
+				"ipv6.address": "none",
+			},
+		},
+	}
+	req := lxdapi.NetworkPut{
+		Config: map[string]string{
+			"ipv4.address": "10.5.3.1/24",
+			"ipv6.address": "auto",
+			"ipv6.nat":     "true",
+		},
+	}
+	gomock.InOrder(
+		cSvr.EXPECT().GetNetwork(network.DefaultLXDBridge).Return(net, lxdtesting.ETag, nil),
+		cSvr.EXPECT().UpdateNetwork(network.DefaultLXDBridge, req, lxdtesting.ETag).Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	mod, err := jujuSvr.EnsureIPv6(network.DefaultLXDBridge)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mod, jc.IsTrue)
+}
+
 func (s *networkSuite) TestGetNICsFromProfile(c *gc.C) {
 	lxd.PatchGenerateVirtualMACAddress(s)
 
@@ -0,0 +1,71 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/canonical/lxd/shared/api"
+	"github.com/juju/errors"
+)
+
+// HasProject interrogates the known projects and returns a boolean
+// indicating whether a project with the input name exists.
+func (s *Server) HasProject(name string) (bool, error) {
+	projects, err := s.GetProjectNames()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, project := range projects {
+		if project == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateProjectWithConfig creates a new project with the input name,
+// description and config.
+func (s *Server) CreateProjectWithConfig(name, description string, cfg map[string]string) error {
+	req := api.ProjectsPost{
+		Name: name,
+		ProjectPut: api.ProjectPut{
+			Description: description,
+			Config:      cfg,
+		},
+	}
+	return errors.Trace(s.CreateProject(req))
+}
+
+// EmptyProject removes the images and all profiles other than "default"
+// from the project in use, so that the project can be deleted.
+// Instances and storage volumes are not removed; that is left to the
+// caller, which knows which of them it owns.
+func (s *Server) EmptyProject() error {
+	fingerprints, err := s.GetImageFingerprints()
+	if err != nil {
+		return errors.Annotate(err, "listing images")
+	}
+	for _, fingerprint := range fingerprints {
+		op, err := s.DeleteImage(fingerprint)
+		if err == nil {
+			err = op.Wait()
+		}
+		if err != nil {
+			return errors.Annotatef(err, "deleting image %q", fingerprint)
+		}
+	}
+
+	profiles, err := s.GetProfileNames()
+	if err != nil {
+		return errors.Annotate(err, "listing profiles")
+	}
+	for _, profile := range profiles {
+		if profile == "default" {
+			continue
+		}
+		if err := s.DeleteProfile(profile); err != nil {
+			return errors.Annotatef(err, "deleting profile %q", profile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"errors"
+
+	"github.com/canonical/lxd/shared/api"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+)
+
+type projectSuite struct {
+	lxdtesting.BaseSuite
+}
+
+var _ = gc.Suite(&projectSuite{})
+
+func (s *projectSuite) TestHasProject(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	cSvr.EXPECT().GetProjectNames().Return([]string{"default", "juju-06f00d-test"}, nil).Times(2)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	has, err := jujuSvr.HasProject("juju-06f00d-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsTrue)
+
+	has, err = jujuSvr.HasProject("unknown")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+}
+
+func (s *projectSuite) TestCreateProjectWithConfig(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	req := api.ProjectsPost{
+		Name: "juju-06f00d-test",
+		ProjectPut: api.ProjectPut{
+			Description: "Juju model test",
+			Config: map[string]string{
+				"features.images": "true",
+			},
+		},
+	}
+	cSvr.EXPECT().CreateProject(req).Return(nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujuSvr.CreateProjectWithConfig("juju-06f00d-test", "Juju model test", map[string]string{
+		"features.images": "true",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestEmptyProject(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	op := lxdtesting.NewMockOperation(ctrl)
+	gomock.InOrder(
+		cSvr.EXPECT().GetImageFingerprints().Return([]string{"abc", "def"}, nil),
+		cSvr.EXPECT().DeleteImage("abc").Return(op, nil),
+		op.EXPECT().Wait().Return(nil),
+		cSvr.EXPECT().DeleteImage("def").Return(op, nil),
+		op.EXPECT().Wait().Return(nil),
+		cSvr.EXPECT().GetProfileNames().Return([]string{"default", "juju-test"}, nil),
+		cSvr.EXPECT().DeleteProfile("juju-test").Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujuSvr.EmptyProject()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestEmptyProjectImageError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServer(ctrl)
+
+	op := lxdtesting.NewMockOperation(ctrl)
+	gomock.InOrder(
+		cSvr.EXPECT().GetImageFingerprints().Return([]string{"abc"}, nil),
+		cSvr.EXPECT().DeleteImage("abc").Return(op, nil),
+		op.EXPECT().Wait().Return(errors.New("boom")),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+	err = jujuSvr.EmptyProject()
+	c.Assert(err, gc.ErrorMatches, `deleting image "abc": boom`)
+}
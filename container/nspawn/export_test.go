@@ -0,0 +1,149 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/container"
+)
+
+// This file exports internal package implementations so that tests
+// can utilize them to mock behavior.
+
+var SystemdRuntimeDir = &systemdRuntimeDir
+
+type ImageParams = imageParams
+
+// NewTestContainerManager returns a manager which runs commands with
+// runCmd, keeps its files under dir and fetches images with fetchImage.
+func NewTestContainerManager(
+	conf container.ManagerConfig,
+	runCmd func(string, ...string) (string, error),
+	dir string,
+	fetchImage func(ImageParams, func(string)) (string, error),
+) (container.Manager, error) {
+	return newContainerManager(conf, newTestMachineCtl(runCmd, dir), fetchImage)
+}
+
+// NewTestContainerInitialiser returns an initialiser which runs commands
+// with runCmd and keeps its files under dir.
+func NewTestContainerInitialiser(
+	containerNetworkingMethod string,
+	runCmd func(string, ...string) (string, error),
+	dir string,
+	ensureDependencies func() error,
+) container.Initialiser {
+	return &containerInitialiser{
+		containerNetworkingMethod: containerNetworkingMethod,
+		machines:                  newTestMachineCtl(runCmd, dir),
+		ensureDependencies:        ensureDependencies,
+	}
+}
+
+func newTestMachineCtl(runCmd runFunc, dir string) *machineCtl {
+	return &machineCtl{
+		runCmd:      runCmd,
+		machinesDir: filepath.Join(dir, "machines"),
+		settingsDir: filepath.Join(dir, "nspawn"),
+		networkDir:  filepath.Join(dir, "network"),
+	}
+}
+
+// NewRunStub returns a stub to fake shelling out to machinectl and
+// systemctl. It keeps track of the images and running machines the
+// commands would have created.
+func NewRunStub() *RunStub {
+	return &RunStub{
+		images:  set.NewStrings(),
+		running: set.NewStrings(),
+		errors:  make(map[string]error),
+	}
+}
+
+type RunStub struct {
+	mu      sync.Mutex
+	images  set.Strings
+	running set.Strings
+	errors  map[string]error
+	calls   []string
+}
+
+// SetError sets the error returned when running the given subcommand.
+func (s *RunStub) SetError(subcommand string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[subcommand] = err
+}
+
+// AddImage fakes an existing image, which is running if running is true.
+func (s *RunStub) AddImage(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images.Add(name)
+	if running {
+		s.running.Add(name)
+	}
+}
+
+// Run fakes running commands, instead recording calls made for use in
+// testing.
+func (s *RunStub) Run(cmd string, args ...string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, strings.Join(append([]string{cmd}, args...), " "))
+	if len(args) == 0 {
+		return "", nil
+	}
+	if err := s.errors[args[0]]; err != nil {
+		return err.Error(), err
+	}
+	if cmd != machinectl {
+		return "", nil
+	}
+	switch args[0] {
+	case "list-images":
+		return listing(s.images), nil
+	case "list":
+		return listing(s.running), nil
+	case "import-tar":
+		s.images.Add(args[2])
+	case "clone":
+		s.images.Add(args[2])
+	case "remove":
+		s.images.Remove(args[1])
+	case "start":
+		s.running.Add(args[1])
+	case "terminate":
+		s.running.Remove(args[1])
+	}
+	return "", nil
+}
+
+func listing(names set.Strings) string {
+	var out string
+	for _, name := range names.SortedValues() {
+		out += fmt.Sprintf("%s container no -     -\n", name)
+	}
+	return out
+}
+
+// Calls returns the calls made on a RunStub.
+func (s *RunStub) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// ResetCalls forgets the calls made so far.
+func (s *RunStub) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+// RootImageType is the simplestreams file type of the root filesystem
+// tarballs that nspawn container images are imported from. These are
+// published alongside the disk images used by KVM containers.
+const RootImageType = "root.tar.xz"
+
+// imageParams identifies the cloud image a container is created from.
+type imageParams struct {
+	Arch             string
+	Version          string
+	Stream           string
+	ImageDownloadURL string
+}
+
+// templateName returns the name of the image from which containers
+// of the given version and architecture are cloned.
+func templateName(version, arch string) string {
+	return fmt.Sprintf("juju-nspawn-%s-%s", version, arch)
+}
+
+// fetchImageFunc downloads the root filesystem tarball described by the
+// parameters, returning the path of a local copy which the caller is
+// responsible for removing.
+type fetchImageFunc func(params imageParams, progress func(string)) (string, error)
+
+// simplestreamsImageFetcher returns a fetchImageFunc which locates images
+// in simplestreams metadata, by default that of the Ubuntu cloud images.
+func simplestreamsImageFetcher(fetcher imagemetadata.SimplestreamsFetcher) fetchImageFunc {
+	return func(params imageParams, progress func(string)) (string, error) {
+		var srcFunc func() simplestreams.DataSource
+		if params.ImageDownloadURL != "" {
+			srcFunc = func() simplestreams.DataSource {
+				return imagedownloads.NewDataSource(fetcher, params.ImageDownloadURL)
+			}
+		}
+		md, err := imagedownloads.One(fetcher, params.Arch, params.Version, params.Stream, RootImageType, srcFunc)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		dlURL, err := md.DownloadURL(params.ImageDownloadURL)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		progress(fmt.Sprintf("downloading %s", dlURL))
+		return download(dlURL.String(), md.SHA256)
+	}
+}
+
+// download fetches url into a temporary file, verifying its checksum.
+func download(url, sha256sum string) (_ string, err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.NotFoundf("got %d fetching image %q", resp.StatusCode, path.Base(url))
+	}
+
+	f, err := os.CreateTemp("", fmt.Sprintf("juju-nspawn-%s-", path.Base(url)))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = errors.Trace(closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result := fmt.Sprintf("%x", hash.Sum(nil)); result != sha256sum {
+		return "", errors.Errorf("hash sum mismatch for %s: %s != %s", path.Base(url), result, sha256sum)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container"
+	coreos "github.com/juju/juju/core/os"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/packaging"
+	"github.com/juju/juju/packaging/dependency"
+)
+
+type containerInitialiser struct {
+	containerNetworkingMethod string
+	machines                  *machineCtl
+	ensureDependencies        func() error
+}
+
+// containerInitialiser implements container.Initialiser.
+var _ container.Initialiser = (*containerInitialiser)(nil)
+
+// NewContainerInitialiser returns an instance used to perform the steps
+// required to allow a host machine to run a nspawn container.
+func NewContainerInitialiser(containerNetworkingMethod string) container.Initialiser {
+	return &containerInitialiser{
+		containerNetworkingMethod: containerNetworkingMethod,
+		machines:                  newMachineCtl(),
+		ensureDependencies:        ensureDependencies,
+	}
+}
+
+// Initialise is specified on the container.Initialiser interface.
+func (ci *containerInitialiser) Initialise() error {
+	if err := ci.ensureDependencies(); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Locally networked containers are attached to a bridge private
+	// to the host, which we have to provide.
+	if ci.containerNetworkingMethod == "local" {
+		if err := ci.machines.EnsureBridge(network.DefaultNspawnBridge); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func ensureDependencies() error {
+	hostBase, err := coreos.HostBase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(packaging.InstallDependency(dependency.Nspawn(), hostBase))
+}
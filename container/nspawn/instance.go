@@ -0,0 +1,55 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+type nspawnInstance struct {
+	id       string
+	machines *machineCtl
+}
+
+var _ instances.Instance = (*nspawnInstance)(nil)
+
+// Id implements instances.Instance.Id.
+func (inst *nspawnInstance) Id() instance.Id {
+	return instance.Id(inst.id)
+}
+
+// Status implements instances.Instance.Status.
+func (inst *nspawnInstance) Status(ctx context.ProviderCallContext) instance.Status {
+	running, err := inst.machines.RunningMachines()
+	if err != nil {
+		logger.Warningf("cannot get state of %s: %v", inst.id, err)
+	}
+	if running.Contains(inst.id) {
+		return instance.Status{
+			Status:  status.Running,
+			Message: "running",
+		}
+	}
+	return instance.Status{
+		Status:  status.Stopped,
+		Message: "stopped",
+	}
+}
+
+// Addresses implements instances.Instance.Addresses.
+// The addresses of a container are reported by its machine agent.
+func (inst *nspawnInstance) Addresses(ctx context.ProviderCallContext) (corenetwork.ProviderAddresses, error) {
+	return nil, nil
+}
+
+// Add a string representation of the id.
+func (inst *nspawnInstance) String() string {
+	return fmt.Sprintf("nspawn:%s", inst.id)
+}
@@ -0,0 +1,455 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package nspawn provides a container.Manager that runs machine containers
+// with systemd-nspawn, for hosts on which LXD is not available. Containers
+// are cloned from Ubuntu cloud image root filesystems imported into
+// systemd-machined, and configured on first boot by cloud-init.
+package nspawn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/containerinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+var logger = loggo.GetLogger("juju.container.nspawn")
+
+// hostInterfaceName is the name systemd-nspawn gives to the container
+// end of the virtual ethernet link it attaches to a bridge.
+const hostInterfaceName = "host0"
+
+// systemdRuntimeDir only exists on hosts booted with systemd.
+var systemdRuntimeDir = "/run/systemd/system"
+
+const (
+	systemdNspawn     = "systemd-nspawn"
+	systemdDetectVirt = "systemd-detect-virt"
+)
+
+// IsNspawnSupported reports whether the host can run nspawn containers.
+// It must have been booted with systemd, have the systemd-nspawn and
+// machinectl executables from the systemd-container package, and not
+// itself be a container.
+// It is a variable to allow us to override behaviour in the tests.
+var IsNspawnSupported = func() (bool, error) {
+	_, err := os.Stat(systemdRuntimeDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, name := range []string{systemdNspawn, machinectl} {
+		if _, err := exec.LookPath(name); err != nil {
+			logger.Debugf("%s executable not found", name)
+			return false, nil
+		}
+	}
+	inContainer, err := isContainer()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !inContainer, nil
+}
+
+// isContainer reports whether the host is itself a container, in which
+// nspawn containers can't be run. systemd-detect-virt prints "none" and
+// exits non-zero outside containers.
+func isContainer() (bool, error) {
+	output, err := run(systemdDetectVirt, "--container")
+	if strings.TrimSpace(output) == "none" {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Annotatef(err, "running %s: %s", systemdDetectVirt, strings.TrimSpace(output))
+	}
+	logger.Debugf("host is a %s container", strings.TrimSpace(output))
+	return true, nil
+}
+
+// NewContainerManager returns a manager object that can start and stop
+// nspawn containers.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	return newContainerManager(conf, newMachineCtl(),
+		simplestreamsImageFetcher(simplestreams.NewSimpleStreams(simplestreams.DefaultDataSourceFactory())))
+}
+
+func newContainerManager(conf container.ManagerConfig, machines *machineCtl, fetchImage fetchImageFunc) (*containerManager, error) {
+	modelUUID := conf.PopValue(container.ConfigModelUUID)
+	if modelUUID == "" {
+		return nil, errors.Errorf("model UUID is required")
+	}
+	namespace, err := instance.NewNamespace(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The log directory is not needed, as containers log to the journal.
+	_ = conf.PopValue(container.ConfigLogDir)
+
+	availabilityZone := conf.PopValue(container.ConfigAvailabilityZone)
+	if availabilityZone == "" {
+		logger.Infof("Availability zone will be empty for this container manager")
+	}
+
+	imageMetaDataURL := conf.PopValue(config.ContainerImageMetadataURLKey)
+	imageStream := conf.PopValue(config.ContainerImageStreamKey)
+	imageMetadataDefaultsDisabled := false
+	if conf.PopValue(config.ContainerImageMetadataDefaultsDisabledKey) == "true" {
+		imageMetadataDefaultsDisabled = true
+	}
+
+	conf.WarnAboutUnused()
+	return &containerManager{
+		namespace:                     namespace,
+		availabilityZone:              availabilityZone,
+		imageMetadataURL:              imageMetaDataURL,
+		imageStream:                   imageStream,
+		imageMetadataDefaultsDisabled: imageMetadataDefaultsDisabled,
+		machines:                      machines,
+		fetchImage:                    fetchImage,
+	}, nil
+}
+
+// containerManager handles all of the business logic at the juju specific
+// level. It makes sure that the container image is available, that the
+// cloud-init data is seeded into the container, and that the container is
+// attached to the right bridge.
+type containerManager struct {
+	namespace                     instance.Namespace
+	availabilityZone              string
+	imageMetadataURL              string
+	imageStream                   string
+	imageMetadataDefaultsDisabled bool
+
+	machines   *machineCtl
+	fetchImage fetchImageFunc
+
+	imageLocksMutex sync.Mutex
+	imageLocks      map[string]*sync.Mutex
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+// Namespace implements container.Manager.
+func (manager *containerManager) Namespace() instance.Namespace {
+	return manager.namespace
+}
+
+// CreateContainer implements container.Manager.
+func (manager *containerManager) CreateContainer(
+	_ context.Context,
+	instanceConfig *instancecfg.InstanceConfig,
+	cons constraints.Value,
+	base corebase.Base,
+	networkConfig *container.NetworkConfig,
+	_ *container.StorageConfig,
+	callback environs.StatusCallbackFunc,
+) (_ instances.Instance, _ *instance.HardwareCharacteristics, err error) {
+
+	name, err := manager.namespace.Hostname(instanceConfig.MachineId)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = callback(status.ProvisioningError, fmt.Sprintf("Creating container: %v", err), nil)
+		}
+	}()
+
+	// Set the MachineContainerHostname to match the name returned by
+	// machinectl list.
+	instanceConfig.MachineContainerHostname = name
+
+	bridge, networkConfig, err := hostNetworkConfig(networkConfig)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	// Create the cloud-init.
+	cloudConfig, err := cloudinit.New(instanceConfig.Base.OS)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	logger.Tracef("write cloud-init")
+	userData, err := containerinit.CloudInitUserData(cloudConfig, instanceConfig, networkConfig)
+	if err != nil {
+		err = errors.Annotate(err, "failed to generate cloud-init data")
+		logger.Errorf(err.Error())
+		return nil, nil, errors.Trace(err)
+	}
+
+	// The container directory holds a copy of the user data, to help
+	// diagnose problems with the container.
+	directory, err := container.NewDirectory(name)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to create container directory")
+	}
+	if err := os.WriteFile(filepath.Join(directory, "cloud-init"), userData, 0644); err != nil {
+		return nil, nil, errors.Annotate(err, "failed to write cloud-init data")
+	}
+
+	params := imageParams{
+		Arch:    arch.HostArch(),
+		Version: base.Channel.Track,
+		Stream:  manager.imageStream,
+	}
+
+	// Check whether a container image metadata URL was configured.
+	// Default to Ubuntu cloud images if configured stream is not "released".
+	imURL := manager.imageMetadataURL
+	if manager.imageMetadataURL == "" && manager.imageMetadataDefaultsDisabled {
+		return nil, nil, errors.Errorf("no image metadata source configured: default sources disabled")
+	}
+	if manager.imageMetadataURL == "" && manager.imageStream != imagemetadata.ReleasedStream {
+		imURL = imagemetadata.UbuntuCloudImagesURL
+		imURL, err = imagemetadata.ImageMetadataURL(imURL, manager.imageStream)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "generating image metadata source")
+		}
+	}
+	params.ImageDownloadURL = imURL
+
+	hardware := hardwareCharacteristics(params.Arch, cons, manager.availabilityZone)
+
+	_ = callback(status.Provisioning, "Creating container; it might take some time", nil)
+	logger.Tracef("create the container, constraints: %v", cons)
+
+	// Lock around finding an image.
+	// The provisioner works concurrently to create containers.
+	// If an image needs to be copied from a remote, we don't want many
+	// goroutines attempting to do it at once. Containers which use
+	// different images don't need to wait for each other.
+	template := templateName(params.Version, params.Arch)
+	imageLock := manager.imageLock(template)
+	imageLock.Lock()
+	err = manager.ensureImage(template, params, callback)
+	imageLock.Unlock()
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "acquiring container image")
+	}
+
+	if err := manager.start(name, template, bridge, userData, hardware, callback); err != nil {
+		return nil, nil, errors.Annotate(err, "nspawn container creation failed")
+	}
+	logger.Tracef("nspawn container created")
+	_ = callback(status.Running, "Container started", nil)
+	return &nspawnInstance{id: name, machines: manager.machines}, hardware, nil
+}
+
+// hostNetworkConfig returns the bridge that the container is to be
+// attached to, along with the network config to render into its
+// cloud-init data. systemd-nspawn can only bridge a single interface, and
+// always calls it host0 inside the container, so netplan has to match it
+// by that name rather than by the MAC address juju allocated.
+func hostNetworkConfig(networkConfig *container.NetworkConfig) (string, *container.NetworkConfig, error) {
+	if networkConfig == nil || len(networkConfig.Interfaces) == 0 {
+		return "", networkConfig, nil
+	}
+	if networkConfig.NetworkType != container.BridgeNetwork {
+		return "", nil, errors.NotSupportedf("network type %q for nspawn containers", networkConfig.NetworkType)
+	}
+	if n := len(networkConfig.Interfaces); n > 1 {
+		return "", nil, errors.NotSupportedf("%d network interfaces for nspawn containers", n)
+	}
+
+	iface := networkConfig.Interfaces[0]
+	iface.InterfaceName = hostInterfaceName
+	return iface.ParentInterfaceName, container.BridgeNetworkConfig(
+		networkConfig.MTU, corenetwork.InterfaceInfos{iface},
+	), nil
+}
+
+// hardwareCharacteristics returns the hardware of a container started with
+// the given constraints. Only memory and cores can be limited; anything
+// else in the constraints is ignored.
+func hardwareCharacteristics(arch string, cons constraints.Value, zone string) *instance.HardwareCharacteristics {
+	hardware := &instance.HardwareCharacteristics{
+		Arch:     &arch,
+		Mem:      cons.Mem,
+		CpuCores: cons.CpuCores,
+	}
+	if zone != "" {
+		hardware.AvailabilityZone = &zone
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %d being ignored as not supported", *cons.RootDisk)
+	}
+	if cons.CpuPower != nil {
+		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+	}
+	return hardware
+}
+
+// ensureImage imports the image from which containers are cloned, unless
+// it has already been imported.
+func (manager *containerManager) ensureImage(template string, params imageParams, callback environs.StatusCallbackFunc) error {
+	images, err := manager.machines.Images()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if images.Contains(template) {
+		logger.Debugf("image %q already imported", template)
+		return nil
+	}
+
+	logger.Debugf("fetch image for %s %s %s %s", params.Arch, params.Version, params.Stream, params.ImageDownloadURL)
+	path, err := manager.fetchImage(params, func(msg string) {
+		_ = callback(status.Provisioning, msg, nil)
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := os.Remove(path); err != nil {
+			logger.Warningf("failed to remove %q after use: %v", path, err)
+		}
+	}()
+
+	_ = callback(status.Provisioning, "Importing image", nil)
+	return errors.Trace(manager.machines.ImportImage(path, template))
+}
+
+// start clones the container from the template image, seeds its
+// cloud-init data, and boots it.
+func (manager *containerManager) start(
+	name, template, bridge string,
+	userData []byte,
+	hardware *instance.HardwareCharacteristics,
+	callback environs.StatusCallbackFunc,
+) error {
+	logger.Debugf("create the machine %s", name)
+	_ = callback(status.Provisioning, "Creating instance", nil)
+	if err := manager.machines.CloneImage(template, name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := manager.machines.WriteSeed(name, userData, cloudinit.CloudInitNetworkConfigDisabled); err != nil {
+		return errors.Trace(err)
+	}
+	if err := manager.machines.WriteSettings(name, bridge); err != nil {
+		return errors.Trace(err)
+	}
+	var memory, cores uint64
+	if hardware.Mem != nil {
+		memory = *hardware.Mem
+	}
+	if hardware.CpuCores != nil {
+		cores = *hardware.CpuCores
+	}
+	if err := manager.machines.SetResourceLimits(name, memory, cores); err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Debugf("set machine %s to autostart", name)
+	if err := manager.machines.Enable(name); err != nil {
+		return errors.Trace(err)
+	}
+	_ = callback(status.Provisioning, "Starting instance", nil)
+	return errors.Trace(manager.machines.Start(name))
+}
+
+// imageLock returns the lock held while importing the named image.
+func (manager *containerManager) imageLock(template string) *sync.Mutex {
+	manager.imageLocksMutex.Lock()
+	defer manager.imageLocksMutex.Unlock()
+	if manager.imageLocks == nil {
+		manager.imageLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := manager.imageLocks[template]
+	if !ok {
+		lock = &sync.Mutex{}
+		manager.imageLocks[template] = lock
+	}
+	return lock
+}
+
+// IsInitialized implements container.Manager.
+func (manager *containerManager) IsInitialized() bool {
+	requiredBinaries := []string{
+		"machinectl",
+		"systemd-nspawn",
+	}
+	for _, bin := range requiredBinaries {
+		if _, err := exec.LookPath(bin); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// DestroyContainer implements container.Manager.
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	running, err := manager.machines.RunningMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if running.Contains(name) {
+		if err := manager.machines.Terminate(name); err != nil {
+			logger.Errorf("failed to stop nspawn container: %v", err)
+			return err
+		}
+	}
+	if err := manager.machines.Disable(name); err != nil {
+		logger.Debugf("%v", err)
+	}
+
+	// The image may not exist if we didn't succeed in creating it.
+	images, err := manager.machines.Images()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if images.Contains(name) {
+		if err := manager.machines.RemoveImage(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := manager.machines.RemoveSettings(name); err != nil {
+		return errors.Trace(err)
+	}
+	return container.RemoveDirectory(name)
+}
+
+// ListContainers implements container.Manager, returning the running
+// containers belonging to this manager's namespace.
+func (manager *containerManager) ListContainers() (result []instances.Instance, err error) {
+	running, err := manager.machines.RunningMachines()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return nil, errors.Trace(err)
+	}
+	managerPrefix := manager.namespace.Prefix()
+	for _, name := range running.SortedValues() {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		result = append(result, &nspawnInstance{id: name, machines: manager.machines})
+	}
+	return result, nil
+}
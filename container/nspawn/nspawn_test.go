@@ -0,0 +1,341 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/nspawn"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/core/arch"
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/imagemetadata"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const template = "juju-nspawn-18.04-amd64"
+
+type NspawnSuite struct {
+	coretesting.BaseSuite
+
+	dir          string
+	containerDir string
+	removedDir   string
+	stub         *nspawn.RunStub
+	fetched      []nspawn.ImageParams
+	manager      container.Manager
+}
+
+var _ = gc.Suite(&NspawnSuite{})
+
+func (s *NspawnSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&arch.HostArch, func() string { return arch.AMD64 })
+	s.containerDir = c.MkDir()
+	s.PatchValue(&container.ContainerDir, s.containerDir)
+	s.removedDir = c.MkDir()
+	s.PatchValue(&container.RemovedContainerDir, s.removedDir)
+
+	s.dir = c.MkDir()
+	s.stub = nspawn.NewRunStub()
+	s.fetched = nil
+	s.manager = s.newManager(c, container.ManagerConfig{
+		container.ConfigModelUUID:      coretesting.ModelTag.Id(),
+		config.ContainerImageStreamKey: imagemetadata.ReleasedStream,
+	})
+}
+
+func (s *NspawnSuite) newManager(c *gc.C, conf container.ManagerConfig) container.Manager {
+	manager, err := nspawn.NewTestContainerManager(conf, s.stub.Run, s.dir, s.fetchImage)
+	c.Assert(err, jc.ErrorIsNil)
+	return manager
+}
+
+func (s *NspawnSuite) fetchImage(params nspawn.ImageParams, progress func(string)) (string, error) {
+	s.fetched = append(s.fetched, params)
+	path := filepath.Join(s.dir, "root.tar.xz")
+	return path, os.WriteFile(path, []byte("image"), 0644)
+}
+
+func (s *NspawnSuite) createContainer(c *gc.C, machineId string, cons constraints.Value, net *container.NetworkConfig) (instance.Id, error) {
+	instanceConfig, err := containertesting.MockMachineConfig(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _, err := s.manager.CreateContainer(
+		context.Background(), instanceConfig, cons, corebase.MakeDefaultBase("ubuntu", "18.04"), net, nil,
+		func(status.Status, string, map[string]interface{}) error { return nil },
+	)
+	if err != nil {
+		return "", err
+	}
+	return inst.Id(), nil
+}
+
+func bridgedNetwork(names ...string) *container.NetworkConfig {
+	var nics corenetwork.InterfaceInfos
+	for _, name := range names {
+		nics = append(nics, corenetwork.InterfaceInfo{
+			InterfaceName:       name,
+			ParentInterfaceName: "br-" + name,
+			InterfaceType:       corenetwork.EthernetDevice,
+			ConfigType:          corenetwork.ConfigDHCP,
+			MACAddress:          "00:16:3e:00:00:01",
+		})
+	}
+	return container.BridgeNetworkConfig(0, nics)
+}
+
+func (*NspawnSuite) TestManagerModelUUIDNeeded(c *gc.C) {
+	manager, err := nspawn.NewContainerManager(container.ManagerConfig{container.ConfigModelUUID: ""})
+	c.Assert(err, gc.ErrorMatches, "model UUID is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (s *NspawnSuite) TestListMatchesRunningContainersInNamespace(c *gc.C) {
+	s.stub.AddImage("juju-06f00d-match1", true)
+	s.stub.AddImage("juju-06f00d-match2", true)
+	s.stub.AddImage("juju-06f00d-stopped", false)
+	s.stub.AddImage("other", true)
+
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	c.Check(containers[0].Id(), gc.Equals, instance.Id("juju-06f00d-match1"))
+	c.Check(containers[1].Id(), gc.Equals, instance.Id("juju-06f00d-match2"))
+	c.Check(containers[0].Status(nil).Status, gc.Equals, status.Running)
+}
+
+func (s *NspawnSuite) TestCreateContainer(c *gc.C) {
+	id, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, bridgedNetwork("eth0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, instance.Id("juju-06f00d-1-nspawn-0"))
+
+	c.Check(s.fetched, jc.DeepEquals, []nspawn.ImageParams{{
+		Arch:    "amd64",
+		Version: "18.04",
+		Stream:  "released",
+	}})
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"machinectl list-images --no-legend --no-pager",
+		"machinectl import-tar " + filepath.Join(s.dir, "root.tar.xz") + " " + template,
+		"machinectl clone " + template + " juju-06f00d-1-nspawn-0",
+		"machinectl enable juju-06f00d-1-nspawn-0",
+		"machinectl start juju-06f00d-1-nspawn-0",
+	})
+	// The downloaded image is removed once imported.
+	c.Check(filepath.Join(s.dir, "root.tar.xz"), jc.DoesNotExist)
+
+	containertesting.AssertCloudInit(c, filepath.Join(s.containerDir, string(id), "cloud-init"))
+	userData := containertesting.AssertCloudInit(c,
+		filepath.Join(s.dir, "machines", string(id), "var/lib/cloud/seed/nocloud/user-data"))
+	// nspawn names the bridged interface host0 inside the container.
+	c.Check(string(userData), jc.Contains, "host0")
+	c.Check(string(userData), gc.Not(jc.Contains), "00:16:3e:00:00:01")
+
+	settings, err := os.ReadFile(filepath.Join(s.dir, "nspawn", string(id)+".nspawn"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(settings), gc.Equals, "[Exec]\nBoot=yes\n\n[Network]\nBridge=br-eth0\n")
+
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Check(containers[0].Id(), gc.Equals, id)
+}
+
+func (s *NspawnSuite) TestCreateContainerReusesImportedImage(c *gc.C) {
+	s.stub.AddImage(template, false)
+
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.fetched, gc.HasLen, 0)
+	c.Check(s.stub.Calls()[1], gc.Equals, "machinectl clone "+template+" juju-06f00d-1-nspawn-0")
+
+	settings, err := os.ReadFile(filepath.Join(s.dir, "nspawn", "juju-06f00d-1-nspawn-0.nspawn"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(settings), gc.Equals, "[Exec]\nBoot=yes\n")
+}
+
+func (s *NspawnSuite) TestCreateContainerResourceLimits(c *gc.C) {
+	s.stub.AddImage(template, false)
+
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.MustParse("mem=2G cores=2"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls()[2], gc.Equals,
+		"systemctl set-property systemd-nspawn@juju-06f00d-1-nspawn-0.service MemoryMax=2048M CPUQuota=200%")
+}
+
+func (s *NspawnSuite) TestCreateContainerMultipleInterfaces(c *gc.C) {
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, bridgedNetwork("eth0", "eth1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(s.stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *NspawnSuite) TestCreateContainerUsesDailySimpleStream(c *gc.C) {
+	s.manager = s.newManager(c, container.ManagerConfig{
+		container.ConfigModelUUID:      coretesting.ModelTag.Id(),
+		config.ContainerImageStreamKey: "daily",
+	})
+
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fetched, gc.HasLen, 1)
+	c.Check(s.fetched[0].ImageDownloadURL, gc.Equals, "http://cloud-images.ubuntu.com/daily")
+	c.Check(s.fetched[0].Stream, gc.Equals, "daily")
+}
+
+func (s *NspawnSuite) TestCreateContainerNoDefaultImageMetadata(c *gc.C) {
+	s.manager = s.newManager(c, container.ManagerConfig{
+		container.ConfigModelUUID:                        coretesting.ModelTag.Id(),
+		config.ContainerImageStreamKey:                   imagemetadata.ReleasedStream,
+		config.ContainerImageMetadataDefaultsDisabledKey: "true",
+	})
+
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, nil)
+	c.Assert(err, gc.ErrorMatches, `no image metadata source configured: default sources disabled`)
+}
+
+func (s *NspawnSuite) TestCreateContainerStartFails(c *gc.C) {
+	s.stub.AddImage(template, false)
+	s.stub.SetError("start", errors.New("boom"))
+
+	_, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, nil)
+	c.Assert(err, gc.ErrorMatches, `nspawn container creation failed: starting container "juju-06f00d-1-nspawn-0": boom`)
+}
+
+func (s *NspawnSuite) TestDestroyContainer(c *gc.C) {
+	id, err := s.createContainer(c, "1/nspawn/0", constraints.Value{}, bridgedNetwork("eth0"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.ResetCalls()
+
+	err = s.manager.DestroyContainer(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.stub.Calls(), jc.DeepEquals, []string{
+		"machinectl list --no-legend --no-pager",
+		"machinectl terminate juju-06f00d-1-nspawn-0",
+		"machinectl disable juju-06f00d-1-nspawn-0",
+		"machinectl list-images --no-legend --no-pager",
+		"machinectl remove juju-06f00d-1-nspawn-0",
+	})
+	c.Check(filepath.Join(s.dir, "nspawn", string(id)+".nspawn"), jc.DoesNotExist)
+	c.Check(filepath.Join(s.containerDir, string(id)), jc.DoesNotExist)
+	c.Check(filepath.Join(s.removedDir, string(id)), jc.IsDirectory)
+
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(containers, gc.HasLen, 0)
+}
+
+func (s *NspawnSuite) TestIsNspawnSupported(c *gc.C) {
+	s.PatchValue(nspawn.SystemdRuntimeDir, c.MkDir())
+	s.patchBinaries(c, "echo none; exit 1", "systemd-nspawn", "machinectl")
+	supported, err := nspawn.IsNspawnSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsTrue)
+
+	s.PatchValue(nspawn.SystemdRuntimeDir, filepath.Join(c.MkDir(), "missing"))
+	supported, err = nspawn.IsNspawnSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsFalse)
+}
+
+func (s *NspawnSuite) TestIsNspawnSupportedMissingBinary(c *gc.C) {
+	s.PatchValue(nspawn.SystemdRuntimeDir, c.MkDir())
+	s.patchBinaries(c, "echo none; exit 1", "systemd-nspawn")
+	supported, err := nspawn.IsNspawnSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsFalse)
+}
+
+func (s *NspawnSuite) TestIsNspawnSupportedInContainer(c *gc.C) {
+	s.PatchValue(nspawn.SystemdRuntimeDir, c.MkDir())
+	s.patchBinaries(c, "echo lxc", "systemd-nspawn", "machinectl")
+	supported, err := nspawn.IsNspawnSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(supported, jc.IsFalse)
+}
+
+func (s *NspawnSuite) TestIsNspawnSupportedDetectVirtFails(c *gc.C) {
+	s.PatchValue(nspawn.SystemdRuntimeDir, c.MkDir())
+	s.patchBinaries(c, "echo boom >&2; exit 2", "systemd-nspawn", "machinectl")
+	supported, err := nspawn.IsNspawnSupported()
+	c.Assert(err, gc.ErrorMatches, "running systemd-detect-virt: boom: exit status 2")
+	c.Check(supported, jc.IsFalse)
+}
+
+// patchBinaries makes the PATH hold only the named executables, and a
+// systemd-detect-virt running the given script.
+func (s *NspawnSuite) patchBinaries(c *gc.C, detectVirt string, names ...string) {
+	dir := c.MkDir()
+	for _, name := range names {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := os.WriteFile(filepath.Join(dir, "systemd-detect-virt"), []byte("#!/bin/sh\n"+detectVirt+"\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchEnvironment("PATH", dir)
+}
+
+type InitialiserSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&InitialiserSuite{})
+
+func (s *InitialiserSuite) TestInitialiseLocalNetworking(c *gc.C) {
+	dir := c.MkDir()
+	stub := nspawn.NewRunStub()
+	var installed bool
+	initialiser := nspawn.NewTestContainerInitialiser("local", stub.Run, dir, func() error {
+		installed = true
+		return nil
+	})
+
+	err := initialiser.Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(installed, jc.IsTrue)
+	c.Check(stub.Calls(), jc.DeepEquals, []string{"networkctl reload"})
+
+	netdev, err := os.ReadFile(filepath.Join(dir, "network", "80-juju-nspawnbr0.netdev"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(netdev), gc.Equals, "[NetDev]\nName=nspawnbr0\nKind=bridge\n")
+	network, err := os.ReadFile(filepath.Join(dir, "network", "80-juju-nspawnbr0.network"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(network), jc.Contains, "DHCPServer=yes\n")
+
+	// Once configured, networkd is left alone.
+	stub.ResetCalls()
+	err = initialiser.Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *InitialiserSuite) TestInitialiseProviderNetworking(c *gc.C) {
+	dir := c.MkDir()
+	stub := nspawn.NewRunStub()
+	initialiser := nspawn.NewTestContainerInitialiser("provider", stub.Run, dir, func() error { return nil })
+
+	err := initialiser.Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stub.Calls(), gc.HasLen, 0)
+	c.Check(filepath.Join(dir, "network"), jc.DoesNotExist)
+}
+
+func (s *InitialiserSuite) TestInitialiseDependenciesFail(c *gc.C) {
+	initialiser := nspawn.NewTestContainerInitialiser("local", nspawn.NewRunStub().Run, c.MkDir(), func() error {
+		return errors.New("no apt")
+	})
+	err := initialiser.Initialise()
+	c.Assert(err, gc.ErrorMatches, "no apt")
+}
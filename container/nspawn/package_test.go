@@ -0,0 +1,18 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn_test
+
+import (
+	"runtime"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("nspawn containers are only supported on linux")
+	}
+	gc.TestingT(t)
+}
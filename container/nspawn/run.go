@@ -0,0 +1,23 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+import (
+	"os/exec"
+)
+
+// runFunc provides the signature for running an external
+// command and returning the combined output.
+type runFunc func(string, ...string) (string, error)
+
+// run the command and return the combined output.
+func run(command string, args ...string) (string, error) {
+	logger.Debugf("%s %v", command, args)
+
+	out, err := exec.Command(command, args...).CombinedOutput()
+	output := string(out)
+
+	logger.Debugf("output: %v", output)
+	return output, err
+}
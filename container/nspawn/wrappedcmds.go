@@ -0,0 +1,251 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nspawn
+
+// This file contains wrappers around the following executables:
+//   machinectl
+//   systemctl
+//   networkctl
+// Those executables are found in the systemd-container and systemd
+// packages.
+//
+// Container images are imported into, and cloned by, systemd-machined.
+// The containers themselves run as instances of the systemd-nspawn@
+// template unit, configured by a .nspawn settings file.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+const (
+	machinectl = "machinectl"
+	systemctl  = "systemctl"
+	networkctl = "networkctl"
+
+	metadata      = "meta-data"
+	userdata      = "user-data"
+	networkconfig = "network-config"
+
+	// machinesDir is where systemd-machined keeps container images.
+	machinesDir = "/var/lib/machines"
+	// settingsDir is where systemd-nspawn looks for per-container
+	// .nspawn settings files.
+	settingsDir = "/etc/systemd/nspawn"
+	// networkDir is where systemd-networkd looks for its configuration.
+	networkDir = "/etc/systemd/network"
+
+	// seedDir is the NoCloud seed directory, relative to a container's
+	// root filesystem, from which cloud-init reads its data.
+	seedDir = "var/lib/cloud/seed/nocloud"
+)
+
+// machineCtl wraps the commands used to manage nspawn containers. The
+// directories are fields so that tests can redirect them.
+type machineCtl struct {
+	runCmd      runFunc
+	machinesDir string
+	settingsDir string
+	networkDir  string
+}
+
+func newMachineCtl() *machineCtl {
+	return &machineCtl{
+		runCmd:      run,
+		machinesDir: machinesDir,
+		settingsDir: settingsDir,
+		networkDir:  networkDir,
+	}
+}
+
+// Images returns the names of all images known to systemd-machined,
+// which includes every container whether or not it is running.
+func (m *machineCtl) Images() (set.Strings, error) {
+	out, err := m.runCmd(machinectl, "list-images", "--no-legend", "--no-pager")
+	if err != nil {
+		return nil, errors.Annotate(err, "listing images")
+	}
+	return firstFields(out), nil
+}
+
+// RunningMachines returns the names of the running containers.
+func (m *machineCtl) RunningMachines() (set.Strings, error) {
+	out, err := m.runCmd(machinectl, "list", "--no-legend", "--no-pager")
+	if err != nil {
+		return nil, errors.Annotate(err, "listing machines")
+	}
+	return firstFields(out), nil
+}
+
+// firstFields returns the first column of each line of tabular output.
+func firstFields(out string) set.Strings {
+	result := set.NewStrings()
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			result.Add(fields[0])
+		}
+	}
+	return result
+}
+
+// ImportImage imports the root filesystem tarball at path as a new image
+// with the given name.
+func (m *machineCtl) ImportImage(path, name string) error {
+	_, err := m.runCmd(machinectl, "import-tar", path, name)
+	return errors.Annotatef(err, "importing image %q", name)
+}
+
+// CloneImage creates a new image with the given name from the source
+// image.
+func (m *machineCtl) CloneImage(source, name string) error {
+	_, err := m.runCmd(machinectl, "clone", source, name)
+	return errors.Annotatef(err, "cloning image %q to %q", source, name)
+}
+
+// RemoveImage removes the named image and its root filesystem.
+func (m *machineCtl) RemoveImage(name string) error {
+	_, err := m.runCmd(machinectl, "remove", name)
+	return errors.Annotatef(err, "removing image %q", name)
+}
+
+// Start boots the named container.
+func (m *machineCtl) Start(name string) error {
+	_, err := m.runCmd(machinectl, "start", name)
+	return errors.Annotatef(err, "starting container %q", name)
+}
+
+// Enable configures the named container to start when the host boots.
+func (m *machineCtl) Enable(name string) error {
+	_, err := m.runCmd(machinectl, "enable", name)
+	return errors.Annotatef(err, "enabling container %q", name)
+}
+
+// Disable stops the named container from starting when the host boots.
+func (m *machineCtl) Disable(name string) error {
+	_, err := m.runCmd(machinectl, "disable", name)
+	return errors.Annotatef(err, "disabling container %q", name)
+}
+
+// Terminate kills the named container.
+func (m *machineCtl) Terminate(name string) error {
+	_, err := m.runCmd(machinectl, "terminate", name)
+	return errors.Annotatef(err, "terminating container %q", name)
+}
+
+// SetResourceLimits persistently limits the memory (in MB) and CPU cores
+// available to the named container. Zero values are left unlimited.
+func (m *machineCtl) SetResourceLimits(name string, memory, cores uint64) error {
+	var props []string
+	if memory > 0 {
+		props = append(props, fmt.Sprintf("MemoryMax=%dM", memory))
+	}
+	if cores > 0 {
+		props = append(props, fmt.Sprintf("CPUQuota=%d%%", cores*100))
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	args := append([]string{"set-property", serviceName(name)}, props...)
+	_, err := m.runCmd(systemctl, args...)
+	return errors.Annotatef(err, "limiting resources of container %q", name)
+}
+
+// serviceName returns the systemd unit that runs the named container.
+func serviceName(name string) string {
+	return fmt.Sprintf("systemd-nspawn@%s.service", name)
+}
+
+// WriteSettings writes the .nspawn settings file for the named container.
+// If bridge is not empty, the container's host0 interface is attached to
+// it.
+func (m *machineCtl) WriteSettings(name, bridge string) error {
+	settings := "[Exec]\nBoot=yes\n"
+	if bridge != "" {
+		settings += fmt.Sprintf("\n[Network]\nBridge=%s\n", bridge)
+	}
+	if err := os.MkdirAll(m.settingsDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	err := os.WriteFile(m.settingsPath(name), []byte(settings), 0644)
+	return errors.Annotatef(err, "writing settings for container %q", name)
+}
+
+// RemoveSettings removes the .nspawn settings file for the named container.
+func (m *machineCtl) RemoveSettings(name string) error {
+	err := os.Remove(m.settingsPath(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+func (m *machineCtl) settingsPath(name string) string {
+	return filepath.Join(m.settingsDir, name+".nspawn")
+}
+
+// WriteSeed writes the cloud-init NoCloud data into the root filesystem of
+// the named container, so that it is configured on first boot.
+func (m *machineCtl) WriteSeed(name string, userData []byte, networkConfig string) error {
+	dir := filepath.Join(m.machinesDir, name, seedDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Annotatef(err, "creating seed directory for container %q", name)
+	}
+	// instance-id is what cloud-init uses to determine whether this is
+	// the first boot, and thereby whether or not to run.
+	// See: http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
+	meta := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", name, name)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{metadata, []byte(meta)},
+		{userdata, userData},
+		{networkconfig, []byte(networkConfig)},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, 0600); err != nil {
+			return errors.Annotatef(err, "writing %s for container %q", f.name, name)
+		}
+	}
+	return nil
+}
+
+// EnsureBridge configures systemd-networkd to provide the named bridge,
+// giving it a private IPv4 subnet from which locally networked containers
+// are served addresses over DHCP and masqueraded to the outside world.
+func (m *machineCtl) EnsureBridge(name string) error {
+	netdev := fmt.Sprintf("[NetDev]\nName=%s\nKind=bridge\n", name)
+	// An all zero address asks networkd to allocate an unused subnet.
+	network := fmt.Sprintf(
+		"[Match]\nName=%s\n\n[Network]\nAddress=0.0.0.0/24\nDHCPServer=yes\nIPMasquerade=yes\nConfigureWithoutCarrier=yes\n",
+		name)
+
+	changed := false
+	for path, content := range map[string]string{
+		filepath.Join(m.networkDir, fmt.Sprintf("80-juju-%s.netdev", name)):  netdev,
+		filepath.Join(m.networkDir, fmt.Sprintf("80-juju-%s.network", name)): network,
+	} {
+		if existing, err := os.ReadFile(path); err == nil && string(existing) == content {
+			continue
+		}
+		if err := os.MkdirAll(m.networkDir, 0755); err != nil {
+			return errors.Trace(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return errors.Annotatef(err, "writing network configuration for bridge %q", name)
+		}
+		changed = true
+	}
+	if !changed {
+		logger.Debugf("bridge %q already configured", name)
+		return nil
+	}
+	_, err := m.runCmd(networkctl, "reload")
+	return errors.Annotatef(err, "configuring bridge %q", name)
+}
@@ -19,6 +19,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/pki"
+	"github.com/juju/juju/pki/ssh"
 )
 
 const (
@@ -271,6 +272,26 @@ const (
 	// Can be set to "legacy", "snapstore", "local" or "local-dangerous".
 	// Cannot be changed.
 	JujudControllerSnapSource = "jujud-controller-snap-source"
+
+	// AgentBinarySigners holds the keys trusted to sign agent binaries,
+	// in the allowed signers format used by "ssh-keygen -Y verify".
+	// Lines containing only a public key are also accepted.
+	AgentBinarySigners = "agent-binary-signers"
+
+	// RequireSignedAgentBinaries sets whether agent binaries fetched by
+	// the controller, or synced to it, must have a detached signature
+	// made by one of the agent-binary-signers.
+	RequireSignedAgentBinaries = "require-signed-agent-binaries"
+
+	// CharmhubCacheDir is the directory in which charm and resource
+	// archives fetched from charmhub are cached. Relative paths are
+	// taken relative to the controller agent's data dir.
+	CharmhubCacheDir = "charmhub-cache-dir"
+
+	// CharmhubCacheSize is the amount of charmhub archives, in MB, that
+	// the controller keeps on disk before evicting the least recently
+	// used.
+	CharmhubCacheSize = "charmhub-cache-size"
 )
 
 // Attribute Defaults
@@ -400,6 +421,18 @@ const (
 	// snap source, which is the snapstore.
 	// TODO(jujud-controller-snap): change this to "snapstore" once it is implemented.
 	DefaultJujudControllerSnapSource = "legacy"
+
+	// DefaultRequireSignedAgentBinaries is the default value for whether
+	// agent binaries must be signed.
+	DefaultRequireSignedAgentBinaries = false
+
+	// DefaultCharmhubCacheDir is the default directory, relative to the
+	// controller agent's data dir, for the charmhub cache.
+	DefaultCharmhubCacheDir = "charmhub-cache"
+
+	// DefaultCharmhubCacheSize is the default size in MB of the
+	// charmhub cache.
+	DefaultCharmhubCacheSize = 2048
 )
 
 var (
@@ -455,6 +488,10 @@ var (
 		QueryTracingEnabled,
 		QueryTracingThreshold,
 		JujudControllerSnapSource,
+		AgentBinarySigners,
+		RequireSignedAgentBinaries,
+		CharmhubCacheDir,
+		CharmhubCacheSize,
 	}
 
 	// For backwards compatibility, we must include "anything", "juju-apiserver"
@@ -473,6 +510,7 @@ var (
 	// config attributes that are allowed to be updated after the
 	// controller has been created.
 	AllowedUpdateConfigAttributes = set.NewStrings(
+		AgentBinarySigners,
 		AgentLogfileMaxBackups,
 		AgentLogfileMaxSize,
 		AgentRateLimitMax,
@@ -507,6 +545,7 @@ var (
 		PublicDNSAddress,
 		QueryTracingEnabled,
 		QueryTracingThreshold,
+		RequireSignedAgentBinaries,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -1013,6 +1052,32 @@ func (c Config) QueryTracingThreshold() time.Duration {
 	return c.durationOrDefault(QueryTracingThreshold, DefaultQueryTracingThreshold)
 }
 
+// AgentBinarySigners returns the keys trusted to sign agent binaries,
+// in the allowed signers format.
+func (c Config) AgentBinarySigners() string {
+	return c.asString(AgentBinarySigners)
+}
+
+// RequireSignedAgentBinaries returns whether agent binaries must be
+// signed by one of the agent binary signers.
+func (c Config) RequireSignedAgentBinaries() bool {
+	return c.boolOrDefault(RequireSignedAgentBinaries, DefaultRequireSignedAgentBinaries)
+}
+
+// CharmhubCacheDir returns the directory in which charmhub archives are
+// cached. A relative path is relative to the controller agent's data dir.
+func (c Config) CharmhubCacheDir() string {
+	if v := c.asString(CharmhubCacheDir); v != "" {
+		return v
+	}
+	return DefaultCharmhubCacheDir
+}
+
+// CharmhubCacheSizeMB returns the size in MB of the charmhub cache.
+func (c Config) CharmhubCacheSizeMB() int {
+	return c.sizeMBOrDefault(CharmhubCacheSize, DefaultCharmhubCacheSize)
+}
+
 // Validate ensures that config is a valid configuration.
 func Validate(c Config) error {
 	if v, ok := c[IdentityPublicKey].(string); ok {
@@ -1139,6 +1204,16 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[CharmhubCacheSize].(string); ok {
+		mb, err := utils.ParseSize(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s in configuration", CharmhubCacheSize)
+		}
+		if mb < 1 {
+			return errors.NotValidf("%s less than 1 MB", CharmhubCacheSize)
+		}
+	}
+
 	if v, ok := c[MaxTxnLogSize].(string); ok {
 		if _, err := utils.ParseSize(v); err != nil {
 			return errors.Annotate(err, "invalid max txn log size in configuration")
@@ -1268,6 +1343,17 @@ func Validate(c Config) error {
 		}
 	}
 
+	var signers ssh.AllowedSigners
+	if v, ok := c[AgentBinarySigners].(string); ok {
+		var err error
+		if signers, err = ssh.ParseAllowedSigners(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", AgentBinarySigners)
+		}
+	}
+	if c.RequireSignedAgentBinaries() && len(signers) == 0 {
+		return errors.Errorf("%s requires %s to be set", RequireSignedAgentBinaries, AgentBinarySigners)
+	}
+
 	return nil
 }
 
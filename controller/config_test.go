@@ -252,6 +252,12 @@ var newConfigTests = []struct {
 		controller.ModelLogfileMaxSize: "0",
 	},
 	expectError: `model-logfile-max-size less than 1 MB not valid`,
+}, {
+	about: "charmhub-cache-size not valid",
+	config: controller.Config{
+		controller.CharmhubCacheSize: "0",
+	},
+	expectError: `charmhub-cache-size less than 1 MB not valid`,
 }, {
 	about: "agent-ratelimit-max non-int",
 	config: controller.Config{
@@ -384,6 +390,24 @@ var newConfigTests = []struct {
 		controller.JujudControllerSnapSource: "latest/stable",
 	},
 	expectError: `jujud-controller-snap-source value "latest/stable" must be one of legacy, snapstore, local or local-dangerous.`,
+}, {
+	about: "valid agent binary signers",
+	config: controller.Config{
+		controller.AgentBinarySigners:         `juju@example.com namespaces="juju-agent-binary" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAICVi7TLYdWEBAJDA9ZLWav8VSZ86YyM52moGTDsyK7cB`,
+		controller.RequireSignedAgentBinaries: true,
+	},
+}, {
+	about: "invalid agent binary signers",
+	config: controller.Config{
+		controller.AgentBinarySigners: "juju@example.com",
+	},
+	expectError: `invalid agent-binary-signers: line 1: allowed signer without key not valid`,
+}, {
+	about: "signed agent binaries required without signers",
+	config: controller.Config{
+		controller.RequireSignedAgentBinaries: true,
+	},
+	expectError: `require-signed-agent-binaries requires agent-binary-signers to be set`,
 }, {
 	about: "empty controller name",
 	config: controller.Config{
@@ -789,6 +813,8 @@ func (s *ConfigSuite) TestDefaults(c *gc.C) {
 	c.Assert(cfg.ControllerResourceDownloadLimit(), gc.Equals, controller.DefaultControllerResourceDownloadLimit)
 	c.Assert(cfg.QueryTracingEnabled(), gc.Equals, controller.DefaultQueryTracingEnabled)
 	c.Assert(cfg.QueryTracingThreshold(), gc.Equals, controller.DefaultQueryTracingThreshold)
+	c.Assert(cfg.CharmhubCacheDir(), gc.Equals, controller.DefaultCharmhubCacheDir)
+	c.Assert(cfg.CharmhubCacheSizeMB(), gc.Equals, controller.DefaultCharmhubCacheSize)
 }
 
 func (s *ConfigSuite) TestAgentLogfile(c *gc.C) {
@@ -830,6 +856,20 @@ func (s *ConfigSuite) TestModelLogfile(c *gc.C) {
 	c.Assert(cfg.ModelLogfileMaxSizeMB(), gc.Equals, 25)
 }
 
+func (s *ConfigSuite) TestCharmhubCache(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"charmhub-cache-dir":  "/var/cache/charmhub",
+			"charmhub-cache-size": "4G",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.CharmhubCacheDir(), gc.Equals, "/var/cache/charmhub")
+	c.Assert(cfg.CharmhubCacheSizeMB(), gc.Equals, 4096)
+}
+
 func (s *ConfigSuite) TestModelLogfileBackupErr(c *gc.C) {
 	_, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
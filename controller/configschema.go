@@ -59,6 +59,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	QueryTracingEnabled:              schema.Bool(),
 	QueryTracingThreshold:            schema.TimeDuration(),
 	JujudControllerSnapSource:        schema.String(),
+	AgentBinarySigners:               schema.String(),
+	RequireSignedAgentBinaries:       schema.Bool(),
+	CharmhubCacheDir:                 schema.String(),
+	CharmhubCacheSize:                schema.String(),
 }, schema.Defaults{
 	AgentRateLimitMax:                schema.Omit,
 	AgentRateLimitRate:               schema.Omit,
@@ -107,6 +111,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	QueryTracingEnabled:              DefaultQueryTracingEnabled,
 	QueryTracingThreshold:            DefaultQueryTracingThreshold,
 	JujudControllerSnapSource:        DefaultJujudControllerSnapSource,
+	AgentBinarySigners:               schema.Omit,
+	RequireSignedAgentBinaries:       DefaultRequireSignedAgentBinaries,
+	CharmhubCacheDir:                 DefaultCharmhubCacheDir,
+	CharmhubCacheSize:                fmt.Sprintf("%vM", DefaultCharmhubCacheSize),
 })
 
 // ConfigSchema holds information on all the fields defined by
@@ -312,4 +320,22 @@ will be output if tracing is enabled.`,
 		Type:        environschema.Tstring,
 		Description: `The source for the jujud-controller snap.`,
 	},
+	AgentBinarySigners: {
+		Type: environschema.Tstring,
+		Description: `The keys trusted to sign agent binaries, in the allowed_signers format
+used by "ssh-keygen -Y verify". Lines containing only a public key are also
+accepted. Signatures are expected next to each agent binary, with a ".sig" suffix.`,
+	},
+	RequireSignedAgentBinaries: {
+		Type:        environschema.Tbool,
+		Description: `Whether agent binaries must be signed by one of the agent-binary-signers`,
+	},
+	CharmhubCacheDir: {
+		Type:        environschema.Tstring,
+		Description: `The directory in which charms and resources fetched from charmhub are cached, relative to the agent data dir unless absolute`,
+	},
+	CharmhubCacheSize: {
+		Type:        environschema.Tstring,
+		Description: `The maximum size of the charmhub cache (in MB)`,
+	},
 }
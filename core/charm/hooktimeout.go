@@ -0,0 +1,46 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// HookTimeoutDefault is the hook timeout key for the timeout of
+	// hooks that have no timeout of their own.
+	HookTimeoutDefault = "default"
+
+	// HookTimeoutKillGrace is the hook timeout key for the time between
+	// sending SIGTERM and SIGKILL to a hook that has timed out.
+	HookTimeoutKillGrace = "kill-grace"
+)
+
+// ParseHookTimeouts parses hook timeouts written as comma separated
+// key=duration pairs, such as "default=1h,kill-grace=1m,config-changed=10m".
+// Keys other than HookTimeoutDefault and HookTimeoutKillGrace are hook
+// names.
+func ParseHookTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, errors.NotValidf("hook timeout %q", part)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, errors.NotValidf("hook timeout %s %q", key, value)
+		}
+		timeouts[key] = d
+	}
+	return timeouts, nil
+}
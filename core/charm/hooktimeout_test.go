@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type hookTimeoutSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&hookTimeoutSuite{})
+
+func (s *hookTimeoutSuite) TestParseHookTimeouts(c *gc.C) {
+	timeouts, err := ParseHookTimeouts("default=1h, kill-grace=30s,config-changed=10m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeouts, jc.DeepEquals, map[string]time.Duration{
+		HookTimeoutDefault:   time.Hour,
+		HookTimeoutKillGrace: 30 * time.Second,
+		"config-changed":     10 * time.Minute,
+	})
+}
+
+func (s *hookTimeoutSuite) TestParseHookTimeoutsEmpty(c *gc.C) {
+	timeouts, err := ParseHookTimeouts("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeouts, gc.HasLen, 0)
+}
+
+func (s *hookTimeoutSuite) TestParseHookTimeoutsInvalid(c *gc.C) {
+	for _, value := range []string{"default", "=1h", "default=soon", "install=-1s"} {
+		_, err := ParseHookTimeouts(value)
+		c.Check(err, jc.Satisfies, errors.IsNotValid, gc.Commentf("value %q", value))
+	}
+}
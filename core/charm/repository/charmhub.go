@@ -358,12 +358,17 @@ func (c *CharmHubRepository) DownloadCharm(charmName string, requestedOrigin cor
 
 	// Resolve charm URL to a link to the charm blob and keep track of the
 	// actual resolved origin which may be different from the requested one.
-	resURL, actualOrigin, err := c.GetDownloadURL(charmName, requestedOrigin)
+	resURL, actualOrigin, sha384, err := c.downloadURL(charmName, requestedOrigin)
 	if err != nil {
 		return nil, corecharm.Origin{}, errors.Trace(err)
 	}
 
-	charmArchive, err := c.client.DownloadAndRead(context.TODO(), resURL, archivePath)
+	// The SHA-384 hash lets the client serve the archive from its cache.
+	var options []charmhub.DownloadOption
+	if sha384 != "" {
+		options = append(options, charmhub.WithSHA384(sha384))
+	}
+	charmArchive, err := c.client.DownloadAndRead(context.TODO(), resURL, archivePath, options...)
 	if err != nil {
 		return nil, corecharm.Origin{}, errors.Trace(err)
 	}
@@ -379,12 +384,19 @@ func (c *CharmHubRepository) DownloadCharm(charmName string, requestedOrigin cor
 func (c *CharmHubRepository) GetDownloadURL(charmName string, requestedOrigin corecharm.Origin) (*url.URL, corecharm.Origin, error) {
 	c.logger.Tracef("GetDownloadURL %q, origin: %q", charmName, requestedOrigin)
 
+	durl, origin, _, err := c.downloadURL(charmName, requestedOrigin)
+	return durl, origin, errors.Trace(err)
+}
+
+// downloadURL returns the download URL and updated origin as for
+// GetDownloadURL, along with the SHA-384 hash of the archive.
+func (c *CharmHubRepository) downloadURL(charmName string, requestedOrigin corecharm.Origin) (*url.URL, corecharm.Origin, string, error) {
 	refreshRes, err := c.refreshOne(charmName, requestedOrigin)
 	if err != nil {
-		return nil, corecharm.Origin{}, errors.Trace(err)
+		return nil, corecharm.Origin{}, "", errors.Trace(err)
 	}
 	if refreshRes.Error != nil {
-		return nil, corecharm.Origin{}, errors.Errorf("%s: %s", refreshRes.Error.Code, refreshRes.Error.Message)
+		return nil, corecharm.Origin{}, "", errors.Errorf("%s: %s", refreshRes.Error.Code, refreshRes.Error.Message)
 	}
 
 	resOrigin := requestedOrigin
@@ -397,10 +409,13 @@ func (c *CharmHubRepository) GetDownloadURL(charmName string, requestedOrigin co
 
 	durl, err := url.Parse(refreshRes.Entity.Download.URL)
 	if err != nil {
-		return nil, corecharm.Origin{}, errors.Trace(err)
+		return nil, corecharm.Origin{}, "", errors.Trace(err)
 	}
 	outputOrigin, err := sanitiseCharmOrigin(resOrigin, requestedOrigin)
-	return durl, outputOrigin, errors.Trace(err)
+	if err != nil {
+		return nil, corecharm.Origin{}, "", errors.Trace(err)
+	}
+	return durl, outputOrigin, refreshRes.Entity.Download.HashSHA384, nil
 }
 
 // ListResources returns the resources for a given charm and origin.
@@ -408,7 +408,8 @@ func (s *charmHubRepositorySuite) TestDownloadCharm(c *gc.C) {
 	resolvedArchive := new(charm.CharmArchive)
 
 	s.expectCharmRefreshInstallOneFromChannel(c)
-	s.client.EXPECT().DownloadAndRead(context.TODO(), resolvedURL, "/tmp/foo").Return(resolvedArchive, nil)
+	// The archive's SHA-384 hash is passed so it can be served from cache.
+	s.client.EXPECT().DownloadAndRead(context.TODO(), resolvedURL, "/tmp/foo", gomock.Any()).Return(resolvedArchive, nil)
 
 	gotArchive, gotOrigin, err := s.newClient().DownloadCharm("wordpress", requestedOrigin, "/tmp/foo")
 	c.Assert(err, jc.ErrorIsNil)
@@ -12,15 +12,17 @@ type ContainerType string
 
 // Known container types.
 const (
-	NONE ContainerType = "none"
-	LXD  ContainerType = "lxd"
-	KVM  ContainerType = "kvm"
+	NONE   ContainerType = "none"
+	LXD    ContainerType = "lxd"
+	KVM    ContainerType = "kvm"
+	NSPAWN ContainerType = "nspawn"
 )
 
 // ContainerTypes is used to validate add-machine arguments.
 var ContainerTypes = []ContainerType{
 	LXD,
 	KVM,
+	NSPAWN,
 }
 
 // ParseContainerTypeOrNone converts the specified string into a supported
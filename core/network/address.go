@@ -799,6 +799,31 @@ func ScopeMatchCloudLocal(addr Address) ScopeMatch {
 	return invalidScope
 }
 
+// PreferAddressType returns a scope matching function that ranks addresses
+// as the input function does, except that IPv6 addresses are ranked ahead
+// of IPv4 addresses and host names with the same scope when IPv6 is the
+// preferred type. The scope matchers in this package already prefer IPv4,
+// so the input function is returned unchanged for any other type.
+func PreferAddressType(matcher ScopeMatchFunc, preferred AddressType) ScopeMatchFunc {
+	if preferred != IPv6Address {
+		return matcher
+	}
+	return func(addr Address) ScopeMatch {
+		match := matcher(addr)
+		if match == invalidScope {
+			return match
+		}
+
+		// Each level of the scope match hierarchy is a pair of the
+		// preferred type followed by any other type.
+		level := (match - exactScopeIPv4) / 2 * 2
+		if addr.AddressType() == IPv6Address {
+			return exactScopeIPv4 + level
+		}
+		return exactScope + level
+	}
+}
+
 // MergedAddresses provides a single list of addresses without duplicates
 // suitable for returning as an address list for a machine.
 // TODO (cherylj) Add explicit unit tests - tracked with bug #1544158
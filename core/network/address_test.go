@@ -814,6 +814,47 @@ func (*AddressSuite) TestSortAddresses(c *gc.C) {
 	})
 }
 
+func (*AddressSuite) TestPreferAddressTypeIPv6(c *gc.C) {
+	addrs := network.SpaceAddresses{
+		network.NewSpaceAddress("8.8.8.8", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("example.com", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("2001:db8::1", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("172.16.0.2", network.WithScope(network.ScopeCloudLocal)),
+		network.NewSpaceAddress("fc00::1", network.WithScope(network.ScopeCloudLocal)),
+	}
+
+	addr, ok := addrs.OneMatchingScope(network.PreferAddressType(network.ScopeMatchPublic, network.IPv6Address))
+	c.Assert(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "2001:db8::1")
+
+	addr, ok = addrs.OneMatchingScope(network.PreferAddressType(network.ScopeMatchCloudLocal, network.IPv6Address))
+	c.Assert(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "fc00::1")
+
+	// A better scope match still wins over the preferred type.
+	addr, ok = addrs[:2].OneMatchingScope(network.PreferAddressType(network.ScopeMatchPublic, network.IPv6Address))
+	c.Assert(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "8.8.8.8")
+	addr, ok = addrs[3:4].OneMatchingScope(network.PreferAddressType(network.ScopeMatchPublic, network.IPv6Address))
+	c.Assert(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "172.16.0.2")
+
+	// Addresses not matching the scope at all are still excluded.
+	_, ok = network.NewSpaceAddresses("::1").OneMatchingScope(
+		network.PreferAddressType(network.ScopeMatchCloudLocal, network.IPv6Address))
+	c.Check(ok, jc.IsFalse)
+}
+
+func (*AddressSuite) TestPreferAddressTypeIPv4(c *gc.C) {
+	addrs := network.SpaceAddresses{
+		network.NewSpaceAddress("2001:db8::1", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("8.8.8.8", network.WithScope(network.ScopePublic)),
+	}
+	addr, ok := addrs.OneMatchingScope(network.PreferAddressType(network.ScopeMatchPublic, network.IPv4Address))
+	c.Assert(ok, jc.IsTrue)
+	c.Check(addr.Value, gc.Equals, "8.8.8.8")
+}
+
 func (*AddressSuite) TestExactScopeMatch(c *gc.C) {
 	var addr network.Address
 
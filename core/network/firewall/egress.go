@@ -0,0 +1,175 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/network"
+)
+
+// EgressRule represents a rule for allowing traffic to a set of destination
+// CIDRs on a particular port range.
+type EgressRule struct {
+	// The destination port range for the outgoing traffic.
+	PortRange network.PortRange
+
+	// A set of CIDRs that outgoing traffic may reach. An implicit
+	// 0.0.0.0/0 and ::/0 CIDR is assumed if no CIDRs are specified.
+	DestinationCIDRs set.Strings
+}
+
+// NewEgressRule creates a new EgressRule for allowing access to portRange
+// on the list of destinationCIDRs. If no destinationCIDRs are specified,
+// the rule will implicitly apply to all networks.
+func NewEgressRule(portRange network.PortRange, destinationCIDRs ...string) EgressRule {
+	return EgressRule{
+		PortRange:        portRange,
+		DestinationCIDRs: set.NewStrings(destinationCIDRs...),
+	}
+}
+
+// ParseEgressRule parses a rule of the form "<port range> [to <cidr>,...]",
+// for example "443/tcp to 10.0.0.0/8,192.168.0.0/16", as produced by
+// EgressRule.String.
+func ParseEgressRule(s string) (EgressRule, error) {
+	portRangeStr, cidrs, hasCIDRs := strings.Cut(strings.TrimSpace(s), " to ")
+	portRange, err := network.ParsePortRange(strings.TrimSpace(portRangeStr))
+	if err != nil {
+		return EgressRule{}, errors.Annotatef(err, "parsing egress rule %q", s)
+	}
+	rule := NewEgressRule(portRange)
+	if hasCIDRs {
+		for _, cidr := range strings.Split(cidrs, ",") {
+			rule.DestinationCIDRs.Add(strings.TrimSpace(cidr))
+		}
+	}
+	if err := rule.Validate(); err != nil {
+		return EgressRule{}, errors.Annotatef(err, "parsing egress rule %q", s)
+	}
+	return rule, nil
+}
+
+// Validate ensures that the egress rule contains valid destination
+// parameters.
+func (r EgressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Annotatef(err, "invalid destination for egress rule")
+	}
+
+	for dstCIDR := range r.DestinationCIDRs {
+		if _, _, err := net.ParseCIDR(dstCIDR); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
+
+// String is the string representation of EgressRule.
+func (r EgressRule) String() string {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprint(&buf, r.PortRange.String())
+
+	dst := strings.Join(r.DestinationCIDRs.SortedValues(), ",")
+	if dst != "" && dst != AllNetworksIPV4CIDR && dst != AllNetworksIPV6CIDR {
+		_, _ = fmt.Fprintf(&buf, " to %s", dst)
+	}
+	return buf.String()
+}
+
+// EgressRules represents a collection of EgressRule instances.
+type EgressRules []EgressRule
+
+// ParseEgressRules parses a list of rules, each in the form accepted by
+// ParseEgressRule, separated by semicolons.
+func ParseEgressRules(s string) (EgressRules, error) {
+	var rules EgressRules
+	for _, ruleStr := range strings.Split(s, ";") {
+		if strings.TrimSpace(ruleStr) == "" {
+			continue
+		}
+		rule, err := ParseEgressRule(ruleStr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, rule)
+	}
+	rules.Sort()
+	return rules, nil
+}
+
+// String returns the rules in the form accepted by ParseEgressRules.
+func (rules EgressRules) String() string {
+	ruleStrs := make([]string, len(rules))
+	for i, rule := range rules {
+		ruleStrs[i] = rule.String()
+	}
+	return strings.Join(ruleStrs, ";")
+}
+
+// Sort the rule list by port range and then by destination CIDRs.
+func (rules EgressRules) Sort() {
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].asIngress().LessThan(rules[j].asIngress())
+	})
+}
+
+// Validate the list of egress rules.
+func (rules EgressRules) Validate() error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EqualTo returns true if this rule list is equal to the provided rule list.
+func (rules EgressRules) EqualTo(other EgressRules) bool {
+	return rules.asIngress().EqualTo(other.asIngress())
+}
+
+// Diff returns a list of EgressRules to open and/or close so that this
+// set of egress rules matches the target.
+func (rules EgressRules) Diff(target EgressRules) (toOpen, toClose EgressRules) {
+	// Rules in both directions are a port range and a set of CIDRs, so
+	// the ingress rule diff is reused with destinations in place of
+	// sources.
+	ingressOpen, ingressClose := rules.asIngress().Diff(target.asIngress())
+	return egressFromIngress(ingressOpen), egressFromIngress(ingressClose)
+}
+
+func (r EgressRule) asIngress() IngressRule {
+	return IngressRule{PortRange: r.PortRange, SourceCIDRs: r.DestinationCIDRs}
+}
+
+func (rules EgressRules) asIngress() IngressRules {
+	if rules == nil {
+		return nil
+	}
+	result := make(IngressRules, len(rules))
+	for i, rule := range rules {
+		result[i] = rule.asIngress()
+	}
+	return result
+}
+
+func egressFromIngress(rules IngressRules) EgressRules {
+	if rules == nil {
+		return nil
+	}
+	result := make(EgressRules, len(rules))
+	for i, rule := range rules {
+		result[i] = EgressRule{PortRange: rule.PortRange, DestinationCIDRs: rule.SourceCIDRs}
+	}
+	return result
+}
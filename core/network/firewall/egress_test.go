@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+)
+
+var _ = gc.Suite(&EgressRuleSuite{})
+
+type EgressRuleSuite struct {
+	testing.IsolationSuite
+}
+
+func (EgressRuleSuite) TestRuleFormatting(c *gc.C) {
+	pr := network.MustParsePortRange("443/tcp")
+	c.Assert(NewEgressRule(pr).String(), gc.Equals, "443/tcp")
+	c.Assert(NewEgressRule(pr, "0.0.0.0/0").String(), gc.Equals, "443/tcp")
+	c.Assert(NewEgressRule(pr, "192.168.0.0/16", "10.0.0.0/8").String(), gc.Equals, "443/tcp to 10.0.0.0/8,192.168.0.0/16")
+}
+
+func (EgressRuleSuite) TestParseEgressRules(c *gc.C) {
+	rules, err := ParseEgressRules("5432/tcp to 10.0.0.0/8; 443/tcp ;53/udp to 10.0.0.2/32,10.0.0.3/32")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, EgressRules{
+		NewEgressRule(network.MustParsePortRange("443/tcp")),
+		NewEgressRule(network.MustParsePortRange("5432/tcp"), "10.0.0.0/8"),
+		NewEgressRule(network.MustParsePortRange("53/udp"), "10.0.0.2/32", "10.0.0.3/32"),
+	})
+	c.Assert(rules.String(), gc.Equals, "443/tcp;5432/tcp to 10.0.0.0/8;53/udp to 10.0.0.2/32,10.0.0.3/32")
+
+	reparsed, err := ParseEgressRules(rules.String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reparsed.EqualTo(rules), jc.IsTrue)
+}
+
+func (EgressRuleSuite) TestParseEgressRulesEmpty(c *gc.C) {
+	rules, err := ParseEgressRules("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (EgressRuleSuite) TestParseEgressRuleInvalid(c *gc.C) {
+	_, err := ParseEgressRule("443/tcp to 10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `parsing egress rule "443/tcp to 10.0.0.0": invalid CIDR address: 10.0.0.0`)
+
+	_, err = ParseEgressRule("https to 10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `parsing egress rule "https to 10.0.0.0/8": .*`)
+}
+
+func (EgressRuleSuite) TestDiff(c *gc.C) {
+	current := EgressRules{
+		NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+		NewEgressRule(network.MustParsePortRange("80/tcp"), "10.0.0.0/8"),
+	}
+	target := EgressRules{
+		NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8", "192.168.0.0/16"),
+		NewEgressRule(network.MustParsePortRange("53/udp")),
+	}
+
+	toOpen, toClose := current.Diff(target)
+	c.Assert(toOpen, jc.DeepEquals, EgressRules{
+		NewEgressRule(network.MustParsePortRange("443/tcp"), "192.168.0.0/16"),
+		NewEgressRule(network.MustParsePortRange("53/udp"), AllNetworksIPV4CIDR, AllNetworksIPV6CIDR),
+	})
+	c.Assert(toClose, jc.DeepEquals, EgressRules{
+		NewEgressRule(network.MustParsePortRange("80/tcp"), "10.0.0.0/8"),
+	})
+}
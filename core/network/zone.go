@@ -34,3 +34,50 @@ func (a AvailabilityZones) Validate(zoneName string) error {
 	}
 	return errors.NotValidf("availability zone %q", zoneName)
 }
+
+// Spread returns the names of the available zones in which to place count
+// new members of a group, given the number of members already in each zone.
+// Each new member goes to the least populated available zone, with ties
+// broken by the order of the receiver.
+// Nil is returned if there are no available zones.
+func (a AvailabilityZones) Spread(members map[string]int, count int) []string {
+	var available []string
+	for _, az := range a {
+		if az.Available() {
+			available = append(available, az.Name())
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	placed := make(map[string]int, len(available))
+	for _, name := range available {
+		placed[name] = members[name]
+	}
+	result := make([]string, count)
+	for i := range result {
+		least := available[0]
+		for _, name := range available[1:] {
+			if placed[name] < placed[least] {
+				least = name
+			}
+		}
+		placed[least]++
+		result[i] = least
+	}
+	return result
+}
+
+// SurvivesZoneLoss reports whether a group with the given number of members
+// in each zone keeps a majority of its members after losing any single zone.
+func SurvivesZoneLoss(members map[string]int) bool {
+	var total, largest int
+	for _, n := range members {
+		total += n
+		if n > largest {
+			largest = n
+		}
+	}
+	return total-largest > total/2
+}
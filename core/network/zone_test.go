@@ -33,6 +33,28 @@ func (s *zoneSuite) TestAvailabilityZones(c *gc.C) {
 	c.Assert(s.zones.Validate("zone3"), jc.Satisfies, errors.IsNotValid)
 }
 
+func (s *zoneSuite) TestSpread(c *gc.C) {
+	zones := AvailabilityZones{
+		&az{name: "zone1", available: true},
+		&az{name: "zone2"},
+		&az{name: "zone3", available: true},
+		&az{name: "zone4", available: true},
+	}
+	c.Check(zones.Spread(nil, 4), jc.DeepEquals, []string{"zone1", "zone3", "zone4", "zone1"})
+	c.Check(zones.Spread(map[string]int{"zone1": 1, "zone3": 2}, 3), jc.DeepEquals, []string{"zone4", "zone1", "zone4"})
+	c.Check(s.zones.Spread(map[string]int{"zone1": 1}, 2), jc.DeepEquals, []string{"zone1", "zone1"})
+	c.Check(AvailabilityZones{&az{name: "zone2"}}.Spread(nil, 2), gc.IsNil)
+}
+
+func (s *zoneSuite) TestSurvivesZoneLoss(c *gc.C) {
+	c.Check(SurvivesZoneLoss(nil), jc.IsFalse)
+	c.Check(SurvivesZoneLoss(map[string]int{"zone1": 1}), jc.IsFalse)
+	c.Check(SurvivesZoneLoss(map[string]int{"zone1": 2, "zone2": 1}), jc.IsFalse)
+	c.Check(SurvivesZoneLoss(map[string]int{"zone1": 1, "zone2": 1, "zone3": 1}), jc.IsTrue)
+	c.Check(SurvivesZoneLoss(map[string]int{"zone1": 2, "zone2": 2, "zone3": 1}), jc.IsTrue)
+	c.Check(SurvivesZoneLoss(map[string]int{"zone1": 3, "zone2": 2}), jc.IsFalse)
+}
+
 type az struct {
 	name      string
 	available bool
@@ -240,6 +240,16 @@ const (
 	MessageInstallingCharm   = "installing charm software"
 )
 
+const (
+	// ClusterEndpointKey is the model status data key recording the
+	// cluster API endpoint a model's workers are using.
+	ClusterEndpointKey = "cluster-endpoint"
+
+	// UnhealthyClusterEndpointsKey is the model status data key recording
+	// the cluster API endpoints which failed their health check.
+	UnhealthyClusterEndpointsKey = "unhealthy-cluster-endpoints"
+)
+
 // KnownModificationStatus returns true if the status has a known value for
 // a modification of an instance.
 func (s Status) KnownModificationStatus() bool {
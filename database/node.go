@@ -248,6 +248,7 @@ func (m *NodeManager) WithPreferredCloudLocalAddressOption(source corenetwork.Co
 		if nic.Type() == corenetwork.LoopbackDevice ||
 			name == network.DefaultLXDBridge ||
 			name == network.DefaultKVMBridge ||
+			name == network.DefaultNspawnBridge ||
 			name == network.DefaultDockerBridge {
 			continue
 		}
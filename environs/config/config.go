@@ -294,6 +294,21 @@ const (
 	// this model will accept connections to the SSH service
 	SSHAllowKey = "ssh-allow"
 
+	// FirewallDriftCheckIntervalKey is how often the firewaller compares
+	// the provider's firewall rules with the rules Juju wants, to detect
+	// changes made outside of Juju. Zero disables the check.
+	FirewallDriftCheckIntervalKey = "firewall-drift-check-interval"
+
+	// FirewallDriftModeKey sets what the firewaller does when the
+	// provider's firewall rules have drifted: "report" only records the
+	// drift, "correct" also restores the rules Juju wants.
+	FirewallDriftModeKey = "firewall-drift-mode"
+
+	// PreferredAddressFamilyKey sets which address family, "ipv4" or
+	// "ipv6", is preferred when choosing between addresses of equal
+	// scope, for example on dual-stack networks.
+	PreferredAddressFamilyKey = "preferred-address-family"
+
 	// SAASIngressAllowKey is a comma separated list of CIDRs
 	// specifying what ingress can be applied to offers in this model
 	SAASIngressAllowKey = "saas-ingress-allow"
@@ -523,6 +538,14 @@ const (
 	// UpdateStatusHookInterval
 	DefaultUpdateStatusHookInterval = "5m"
 
+	// FirewallDriftReport is the firewall-drift-mode value which
+	// records drift without correcting it.
+	FirewallDriftReport = "report"
+
+	// FirewallDriftCorrect is the firewall-drift-mode value which
+	// records drift and restores the rules Juju wants.
+	FirewallDriftCorrect = "correct"
+
 	// DefaultActionResultsAge is the default for the age of the results for an
 	// action.
 	DefaultActionResultsAge = "336h" // 2 weeks
@@ -797,6 +820,29 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[FirewallDriftCheckIntervalKey].(string); ok && v != "" {
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid firewall drift check interval in model configuration")
+		}
+		if duration != 0 && duration < time.Minute {
+			return errors.Errorf("firewall drift check interval %v cannot be less than 1m", duration)
+		}
+	}
+
+	if v, ok := cfg.defined[FirewallDriftModeKey].(string); ok && v != "" {
+		if v != FirewallDriftReport && v != FirewallDriftCorrect {
+			return errors.NotValidf("firewall drift mode %q", v)
+		}
+	}
+
+	if v, ok := cfg.defined[PreferredAddressFamilyKey].(string); ok && v != "" {
+		family := network.AddressType(v)
+		if family != network.IPv4Address && family != network.IPv6Address {
+			return errors.NotValidf("preferred address family %q", v)
+		}
+	}
+
 	if v, ok := cfg.defined[EgressSubnets].(string); ok && v != "" {
 		cidrs := strings.Split(v, ",")
 		for _, cidr := range cidrs {
@@ -1636,6 +1682,32 @@ func (c *Config) SSHAllow() []string {
 	return strings.Split(allowList, ",")
 }
 
+// FirewallDriftCheckInterval returns how often the firewaller checks
+// for drift in the provider's firewall rules. Zero means never.
+func (c *Config) FirewallDriftCheckInterval() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(FirewallDriftCheckIntervalKey))
+	return val
+}
+
+// FirewallDriftMode returns what the firewaller does with drift in the
+// provider's firewall rules, FirewallDriftReport or FirewallDriftCorrect.
+func (c *Config) FirewallDriftMode() string {
+	if mode := c.asString(FirewallDriftModeKey); mode != "" {
+		return mode
+	}
+	return FirewallDriftReport
+}
+
+// PreferredAddressFamily returns the address family preferred when
+// choosing between addresses of equal scope. It defaults to IPv4.
+func (c *Config) PreferredAddressFamily() network.AddressType {
+	if family := c.asString(PreferredAddressFamilyKey); family != "" {
+		return network.AddressType(family)
+	}
+	return network.IPv4Address
+}
+
 // SAASIngressAllow returns a slice of CIDRs specifying what
 // ingress can be applied to offers in this model
 func (c *Config) SAASIngressAllow() []string {
@@ -1916,6 +1988,10 @@ var alwaysOptional = schema.Defaults{
 	SSHAllowKey:         schema.Omit,
 	SAASIngressAllowKey: schema.Omit,
 
+	FirewallDriftCheckIntervalKey: schema.Omit,
+	FirewallDriftModeKey:          schema.Omit,
+	PreferredAddressFamilyKey:     schema.Omit,
+
 	"logging-config":                schema.Omit,
 	ProvisionerHarvestModeKey:       schema.Omit,
 	NumProvisionWorkersKey:          schema.Omit,
@@ -2461,6 +2537,26 @@ CIDRs specifying what ingress can be applied to offers in this model.`,
 		Type:  environschema.Tstring,
 		Group: environschema.EnvironGroup,
 	},
+	FirewallDriftCheckIntervalKey: {
+		Description: `How often to compare the provider's firewall rules with the rules
+Juju wants, to detect changes made outside of Juju, in human-readable time
+format (minimum 1m). Zero or unset disables the check.`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	FirewallDriftModeKey: {
+		Description: `What to do when the provider's firewall rules have drifted from the
+rules Juju wants: 'report' only records the drift in the model status
+history, 'correct' also restores the rules Juju wants (default "report")`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
+	PreferredAddressFamilyKey: {
+		Description: `The address family preferred when choosing between addresses of
+equal scope, such as on dual-stack networks: 'ipv4' or 'ipv6' (default "ipv4")`,
+		Type:  environschema.Tstring,
+		Group: environschema.EnvironGroup,
+	},
 	TypeKey: {
 		Description: "Type of model, e.g. local, ec2",
 		Type:        environschema.Tstring,
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
@@ -701,6 +702,27 @@ var configTests = []configTest{
 			"saas-ingress-allow": "blah",
 		}),
 		err: `cidr "blah" not valid`,
+	}, {
+		about:       "Invalid firewall-drift-check-interval",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"firewall-drift-check-interval": "10s",
+		}),
+		err: `firewall drift check interval 10s cannot be less than 1m`,
+	}, {
+		about:       "Invalid firewall-drift-mode",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"firewall-drift-mode": "ignore",
+		}),
+		err: `firewall drift mode "ignore" not valid`,
+	}, {
+		about:       "Invalid preferred-address-family",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"preferred-address-family": "hostname",
+		}),
+		err: `preferred address family "hostname" not valid`,
 	},
 }
 
@@ -1343,6 +1365,29 @@ func (s *ConfigSuite) TestSSHAllow(c *gc.C) {
 	c.Assert(allowlist, gc.HasLen, 0)
 }
 
+func (s *ConfigSuite) TestFirewallDrift(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.FirewallDriftCheckInterval(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.FirewallDriftMode(), gc.Equals, config.FirewallDriftReport)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		config.FirewallDriftCheckIntervalKey: "15m",
+		config.FirewallDriftModeKey:          config.FirewallDriftCorrect,
+	})
+	c.Assert(cfg.FirewallDriftCheckInterval(), gc.Equals, 15*time.Minute)
+	c.Assert(cfg.FirewallDriftMode(), gc.Equals, config.FirewallDriftCorrect)
+}
+
+func (s *ConfigSuite) TestPreferredAddressFamily(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.PreferredAddressFamily(), gc.Equals, network.IPv4Address)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		config.PreferredAddressFamilyKey: "ipv6",
+	})
+	c.Assert(cfg.PreferredAddressFamily(), gc.Equals, network.IPv6Address)
+}
+
 func (s *ConfigSuite) TestApplicationOfferAllowList(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{})
 	allowlist := cfg.SAASIngressAllow()
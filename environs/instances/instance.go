@@ -4,6 +4,8 @@
 package instances
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/core/instance"
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/network/firewall"
@@ -41,3 +43,33 @@ type InstanceFirewaller interface {
 	// address rules for that port range.
 	IngressRules(ctx context.ProviderCallContext, machineId string) (firewall.IngressRules, error)
 }
+
+// InstanceEgressFirewaller provides instance-level control of outbound
+// traffic. Instances whose provider can't restrict outbound traffic don't
+// implement it; use EgressFirewaller to find out.
+type InstanceEgressFirewaller interface {
+	// OpenEgressPorts allows outbound traffic matching the given rules
+	// from the instance, which should have been started with the given
+	// machine id.
+	OpenEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error
+
+	// CloseEgressPorts stops allowing outbound traffic matching the
+	// given rules from the instance, which should have been started with
+	// the given machine id.
+	CloseEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error
+
+	// EgressRules returns the set of egress rules for the instance,
+	// which should have been applied to the given machine id. The
+	// rules are returned sorted, with one rule per port range.
+	EgressRules(ctx context.ProviderCallContext, machineId string) (firewall.EgressRules, error)
+}
+
+// EgressFirewaller returns the instance as an InstanceEgressFirewaller,
+// or a NotSupported error if its provider can't restrict outbound traffic.
+func EgressFirewaller(inst Instance) (InstanceEgressFirewaller, error) {
+	fw, ok := inst.(InstanceEgressFirewaller)
+	if !ok {
+		return nil, errors.NotSupportedf("egress rules on instance %q", inst.Id())
+	}
+	return fw, nil
+}
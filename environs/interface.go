@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/assumes"
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/core/constraints"
@@ -525,6 +526,15 @@ type FirewallFeatureQuerier interface {
 	SupportsRulesWithIPV6CIDRs(ctx context.ProviderCallContext) (bool, error)
 }
 
+// EgressRulesSupporter is implemented by providers whose instances
+// restrict outbound traffic to the egress rules of the applications on
+// them. Egress rules are not enforced by other providers.
+type EgressRulesSupporter interface {
+	// SupportsEgressRules returns true if the provider's instances
+	// implement instances.InstanceEgressFirewaller.
+	SupportsEgressRules() bool
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -671,3 +681,12 @@ type CloudEndpointChecker interface {
 	// endpoint and returns nil if no problems.
 	ValidateCloudEndpoint(ctx context.ProviderCallContext) error
 }
+
+// UserDataRenderer is an optional interface implemented by environs
+// which encode the userdata of the instances they start, so that the
+// userdata can be previewed as the provider receives it.
+type UserDataRenderer interface {
+	// UserDataRenderer returns the renderer used to encode the userdata
+	// of instances started by the environ.
+	UserDataRenderer() renderers.ProviderRenderer
+}
@@ -9,6 +9,8 @@ import (
 	"io"
 	"time"
 
+	corebase "github.com/juju/juju/core/base"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/rpc/params"
 )
 
@@ -53,6 +55,14 @@ type ProvisionMachineArgs struct {
 	// machine.
 	PrivateKey string
 
+	// Base, if set, is the base the machine is expected to be running.
+	// Provisioning fails if the machine is running a different base.
+	Base corebase.Base
+
+	// Constraints are recorded against the machine. Only the tags and
+	// spaces constraints are meaningful for manually provisioned machines.
+	Constraints constraints.Value
+
 	*params.UpdateBehavior
 }
 
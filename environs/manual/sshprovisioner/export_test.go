@@ -1,9 +0,0 @@
-// Copyright 2016 Canonical Ltd.
-// Copyright 2016 Cloudbase Solutions SRL
-// Licensed under the AGPLv3, see LICENCE file for details.
-
-package sshprovisioner
-
-const (
-	DetectionScript = detectionScript
-)
@@ -12,6 +12,7 @@ import (
 
 	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/environs/manual/sshprovisioner"
+	sshtesting "github.com/juju/juju/environs/manual/sshprovisioner/testing"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/testing"
 )
@@ -30,7 +31,7 @@ func (s *initialisationSuite) TestDetectBase(c *gc.C) {
 		"MemTotal: 4096 kB",
 		"processor: 0",
 	}, "\n")
-	defer installFakeSSH(c, sshprovisioner.DetectionScript, response, 0)()
+	defer sshtesting.InstallFakeSSH(c, sshprovisioner.DetectionScript, response, 0)()
 	_, base, err := sshprovisioner.DetectBaseAndHardwareCharacteristics("whatever")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(base, gc.Equals, corebase.MustParseBaseFromString("ubuntu@6.10"))
@@ -46,11 +47,11 @@ func (s *initialisationSuite) TestDetectionError(c *gc.C) {
 	}, "\n")
 	// if the script fails for whatever reason, then checkProvisioned
 	// will return an error. stderr will be included in the error message.
-	defer installFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "oh noes"}, 33)()
+	defer sshtesting.InstallFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "oh noes"}, 33)()
 	_, _, err := sshprovisioner.DetectBaseAndHardwareCharacteristics("hostname")
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 33 \\(oh noes\\)")
 	// if the script doesn't fail, stderr is simply ignored.
-	defer installFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "non-empty-stderr"}, 0)()
+	defer sshtesting.InstallFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "non-empty-stderr"}, 0)()
 	hc, _, err := sshprovisioner.DetectBaseAndHardwareCharacteristics("hostname")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hc.String(), gc.Equals, "arch=ppc64el cores=1 mem=4M")
@@ -122,7 +123,7 @@ func (s *initialisationSuite) TestDetectHardwareCharacteristics(c *gc.C) {
 	for i, test := range tests {
 		c.Logf("test %d: %s", i, test.summary)
 		scriptResponse := strings.Join(test.scriptResponse, "\n")
-		defer installFakeSSH(c, sshprovisioner.DetectionScript, scriptResponse, 0)()
+		defer sshtesting.InstallFakeSSH(c, sshprovisioner.DetectionScript, scriptResponse, 0)()
 		hc, _, err := sshprovisioner.DetectBaseAndHardwareCharacteristics("hostname")
 		c.Assert(err, jc.ErrorIsNil)
 		c.Assert(hc.String(), gc.Equals, test.expectedHc)
@@ -131,49 +132,49 @@ func (s *initialisationSuite) TestDetectHardwareCharacteristics(c *gc.C) {
 
 func (s *initialisationSuite) TestCheckProvisioned(c *gc.C) {
 	listCmd := service.ListServicesScript()
-	defer installFakeSSH(c, listCmd, "", 0)()
+	defer sshtesting.InstallFakeSSH(c, listCmd, "", 0)()
 	provisioned, err := sshprovisioner.CheckProvisioned("example.com")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsFalse)
 
-	defer installFakeSSH(c, listCmd, "snap.juju.fetch-oci", 0)()
+	defer sshtesting.InstallFakeSSH(c, listCmd, "snap.juju.fetch-oci", 0)()
 	provisioned, err = sshprovisioner.CheckProvisioned("example.com")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsFalse)
 
-	defer installFakeSSH(c, listCmd, "jujud-machine-42", 0)()
+	defer sshtesting.InstallFakeSSH(c, listCmd, "jujud-machine-42", 0)()
 	provisioned, err = sshprovisioner.CheckProvisioned("example.com")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsTrue)
 
 	// stderr should not affect result.
-	defer installFakeSSH(c, listCmd, []string{"", "non-empty-stderr"}, 0)()
+	defer sshtesting.InstallFakeSSH(c, listCmd, []string{"", "non-empty-stderr"}, 0)()
 	provisioned, err = sshprovisioner.CheckProvisioned("example.com")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsFalse)
 
 	// if the script fails for whatever reason, then checkProvisioned
 	// will return an error. stderr will be included in the error message.
-	defer installFakeSSH(c, listCmd, []string{"non-empty-stdout", "non-empty-stderr"}, 255)()
+	defer sshtesting.InstallFakeSSH(c, listCmd, []string{"non-empty-stdout", "non-empty-stderr"}, 255)()
 	_, err = sshprovisioner.CheckProvisioned("example.com")
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 255 \\(non-empty-stderr\\)")
 }
 
 func (s *initialisationSuite) TestInitUbuntuUserNonExisting(c *gc.C) {
-	defer installFakeSSH(c, "", "", 0)() // successful creation of ubuntu user
-	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
+	defer sshtesting.InstallFakeSSH(c, "", "", 0)() // successful creation of ubuntu user
+	defer sshtesting.InstallFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
 	err := sshprovisioner.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *initialisationSuite) TestInitUbuntuUserExisting(c *gc.C) {
-	defer installFakeSSH(c, "", nil, 0)()
+	defer sshtesting.InstallFakeSSH(c, "", nil, 0)()
 	sshprovisioner.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 }
 
 func (s *initialisationSuite) TestInitUbuntuUserError(c *gc.C) {
-	defer installFakeSSH(c, "", []string{"", "failed to create ubuntu user"}, 123)()
-	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
+	defer sshtesting.InstallFakeSSH(c, "", []string{"", "failed to create ubuntu user"}, 123)()
+	defer sshtesting.InstallFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
 	err := sshprovisioner.InitUbuntuUser("testhost", "testuser", "", "", nil, nil)
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 123 \\(failed to create ubuntu user\\)")
 }
@@ -5,8 +5,10 @@
 package sshprovisioner
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
+	corebase "github.com/juju/juju/core/base"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/rpc/params"
 )
@@ -45,6 +47,17 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	if err != nil {
 		return "", err
 	}
+	if !args.Base.Empty() {
+		machineBase, err := corebase.ParseBase(machineParams.Base.Name, machineParams.Base.Channel)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if !machineBase.IsCompatible(args.Base) {
+			return "", errors.Errorf("host %q is running %s, not %s",
+				args.Host, machineBase.DisplayString(), args.Base.DisplayString())
+		}
+	}
+	machineParams.Constraints = args.Constraints
 
 	// Inform Juju that the machine exists.
 	machineId, err = manual.RecordMachineInState(args.Client, *machineParams)
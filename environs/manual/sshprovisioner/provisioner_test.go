@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/environs/manual/sshprovisioner"
+	sshtesting "github.com/juju/juju/environs/manual/sshprovisioner/testing"
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/juju/testing"
@@ -58,12 +59,12 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	defaultToolsURL := envtools.DefaultBaseURL
 	envtools.DefaultBaseURL = ""
 
-	defer fakeSSH{
+	defer sshtesting.FakeSSH{
 		Base:               base,
 		Arch:               arch,
 		InitUbuntuUser:     true,
 		SkipProvisionAgent: true,
-	}.install(c).Restore()
+	}.Install(c).Restore()
 
 	// Attempt to provision a machine with no tools available, expect it to fail.
 	machineId, err := sshprovisioner.ProvisionMachine(args)
@@ -83,12 +84,12 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 
 	for i, errorCode := range []int{255, 0} {
 		c.Logf("test %d: code %d", i, errorCode)
-		defer fakeSSH{
+		defer sshtesting.FakeSSH{
 			Base:                   base,
 			Arch:                   arch,
 			InitUbuntuUser:         true,
 			ProvisionAgentExitCode: errorCode,
-		}.install(c).Restore()
+		}.Install(c).Restore()
 		machineId, err = sshprovisioner.ProvisionMachine(args)
 		if errorCode != 0 {
 			c.Assert(err, gc.ErrorMatches, fmt.Sprintf("subprocess encountered error code %d", errorCode))
@@ -109,21 +110,21 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 
 	// Attempting to provision a machine twice should fail. We effect
 	// this by checking for existing juju systemd configurations.
-	defer fakeSSH{
+	defer sshtesting.FakeSSH{
 		Provisioned:        true,
 		InitUbuntuUser:     true,
 		SkipDetection:      true,
 		SkipProvisionAgent: true,
-	}.install(c).Restore()
+	}.Install(c).Restore()
 	_, err = sshprovisioner.ProvisionMachine(args)
 	c.Assert(err, gc.Equals, manual.ErrProvisioned)
-	defer fakeSSH{
+	defer sshtesting.FakeSSH{
 		Provisioned:              true,
 		CheckProvisionedExitCode: 255,
 		InitUbuntuUser:           true,
 		SkipDetection:            true,
 		SkipProvisionAgent:       true,
-	}.install(c).Restore()
+	}.Install(c).Restore()
 	_, err = sshprovisioner.ProvisionMachine(args)
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
@@ -131,11 +132,11 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 func (s *provisionerSuite) TestFinishInstanceConfig(c *gc.C) {
 	base := jujuversion.DefaultSupportedLTSBase()
 	const arch = "amd64"
-	defer fakeSSH{
+	defer sshtesting.FakeSSH{
 		Base:           base,
 		Arch:           arch,
 		InitUbuntuUser: true,
-	}.install(c).Restore()
+	}.Install(c).Restore()
 
 	machineId, err := sshprovisioner.ProvisionMachine(s.getArgs(c))
 	c.Assert(err, jc.ErrorIsNil)
@@ -155,11 +156,11 @@ func (s *provisionerSuite) TestFinishInstanceConfig(c *gc.C) {
 func (s *provisionerSuite) TestProvisioningScript(c *gc.C) {
 	base := jujuversion.DefaultSupportedLTSBase()
 	const arch = "amd64"
-	defer fakeSSH{
+	defer sshtesting.FakeSSH{
 		Base:           base,
 		Arch:           arch,
 		InitUbuntuUser: true,
-	}.install(c).Restore()
+	}.Install(c).Restore()
 
 	machineId, err := sshprovisioner.ProvisionMachine(s.getArgs(c))
 	c.Assert(err, jc.ErrorIsNil)
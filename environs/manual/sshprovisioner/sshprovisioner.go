@@ -110,7 +110,7 @@ func detectBaseAndHardwareCharacteristics(host string) (hc instance.HardwareChar
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	cmd.Stdin = bytes.NewBufferString(detectionScript)
+	cmd.Stdin = bytes.NewBufferString(DetectionScript)
 	if err := cmd.Run(); err != nil {
 		if stderr.Len() != 0 {
 			err = fmt.Errorf("%v (%v)", err, strings.TrimSpace(stderr.String()))
@@ -199,9 +199,9 @@ func checkProvisioned(host string) (bool, error) {
 	return provisioned, nil
 }
 
-// detectionScript is the script to run on the remote machine to
+// DetectionScript is the script to run on the remote machine to
 // detect the OS base and hardware characteristics.
-const detectionScript = `#!/bin/bash
+const DetectionScript = `#!/bin/bash
 set -e
 echo "$(grep '^ID=' /etc/os-release | tr -d '"' | cut -d= -f2)"
 echo "$(grep '^VERSION_ID=' /etc/os-release | tr -d '"' | cut -d= -f2)"
@@ -2,7 +2,7 @@
 // Copyright 2016 Cloudbase Solutions SRL
 // Licensed under the AGPLv3, see LICENCE file for details.
 
-package sshprovisioner_test
+package testing
 
 import (
 	"fmt"
@@ -10,7 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/juju/testing"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -46,7 +46,7 @@ else
     exec ssh $*
 fi`
 
-// installFakeSSH creates a fake "ssh" command in a new $PATH,
+// InstallFakeSSH creates a fake "ssh" command in a new $PATH,
 // updates $PATH, and returns a function to reset $PATH to its
 // original value when called.
 //
@@ -58,7 +58,7 @@ fi`
 //   - nil (no output)
 //   - a string (stdout)
 //   - a slice of strings, of length two (stdout, stderr)
-func installFakeSSH(c *gc.C, input, output interface{}, rc int) testing.Restorer {
+func InstallFakeSSH(c *gc.C, input, output interface{}, rc int) jujutesting.Restorer {
 	fakebin := c.MkDir()
 	ssh := filepath.Join(fakebin, "ssh")
 	switch input := input.(type) {
@@ -83,13 +83,13 @@ func installFakeSSH(c *gc.C, input, output interface{}, rc int) testing.Restorer
 	script := fmt.Sprintf(sshscript, stdout, stderr, rc)
 	err := os.WriteFile(ssh, []byte(script), 0777)
 	c.Assert(err, jc.ErrorIsNil)
-	return testing.PatchEnvPathPrepend(fakebin)
+	return jujutesting.PatchEnvPathPrepend(fakebin)
 }
 
-// installDetectionFakeSSH installs a fake SSH command, which will respond
+// InstallDetectionFakeSSH installs a fake SSH command, which will respond
 // to the base/hardware detection script with the specified
 // base/arch.
-func installDetectionFakeSSH(c *gc.C, base corebase.Base, arch string) testing.Restorer {
+func InstallDetectionFakeSSH(c *gc.C, base corebase.Base, arch string) jujutesting.Restorer {
 	if arch == "" {
 		arch = "amd64"
 	}
@@ -100,11 +100,11 @@ func installDetectionFakeSSH(c *gc.C, base corebase.Base, arch string) testing.R
 		"MemTotal: 4096 kB",
 		"processor: 0",
 	}, "\n")
-	return installFakeSSH(c, sshprovisioner.DetectionScript, detectionoutput, 0)
+	return InstallFakeSSH(c, sshprovisioner.DetectionScript, detectionoutput, 0)
 }
 
-// fakeSSH wraps the invocation of InstallFakeSSH based on the parameters.
-type fakeSSH struct {
+// FakeSSH wraps the invocation of InstallFakeSSH based on the parameters.
+type FakeSSH struct {
 	Base corebase.Base
 	Arch string
 
@@ -133,19 +133,19 @@ type fakeSSH struct {
 	SkipDetection bool
 }
 
-// install installs fake SSH commands, which will respond to
+// Install installs fake SSH commands, which will respond to
 // manual provisioning/bootstrapping commands with the specified
 // output and exit codes.
-func (r fakeSSH) install(c *gc.C) testing.Restorer {
-	var restore testing.Restorer
+func (r FakeSSH) Install(c *gc.C) jujutesting.Restorer {
+	var restore jujutesting.Restorer
 	add := func(input, output interface{}, rc int) {
-		restore = restore.Add(installFakeSSH(c, input, output, rc))
+		restore = restore.Add(InstallFakeSSH(c, input, output, rc))
 	}
 	if !r.SkipProvisionAgent {
 		add(nil, nil, r.ProvisionAgentExitCode)
 	}
 	if !r.SkipDetection {
-		restore.Add(installDetectionFakeSSH(c, r.Base, r.Arch))
+		restore.Add(InstallDetectionFakeSSH(c, r.Base, r.Arch))
 	}
 	var checkProvisionedOutput interface{}
 	if r.Provisioned {
@@ -54,6 +54,10 @@ type SyncContext struct {
 
 	// ChosenVersion is the requested version to upload.
 	ChosenVersion version.Number
+
+	// SignatureVerifier, if not nil, verifies the signatures of the
+	// agent binaries before they are copied.
+	SignatureVerifier *envtools.SignatureVerifier
 }
 
 // ToolsFinder provides an interface for finding tools of a specified version.
@@ -65,8 +69,9 @@ type ToolsFinder interface {
 // ToolsUploader provides an interface for uploading tools and associated
 // metadata.
 type ToolsUploader interface {
-	// UploadTools uploads the tools with the specified version and tarball contents.
-	UploadTools(toolsDir, stream string, tools *coretools.Tools, data []byte) error
+	// UploadTools uploads the tools with the specified version and tarball
+	// contents, along with their detached signature if it isn't empty.
+	UploadTools(toolsDir, stream string, tools *coretools.Tools, data, signature []byte) error
 }
 
 // SyncTools copies the Juju tools tarball from the official bucket
@@ -150,7 +155,7 @@ func SyncTools(syncContext *SyncContext) error {
 		return nil
 	}
 
-	err = copyTools(toolsDir, syncContext.Stream, chosenList, syncContext.TargetToolsUploader)
+	err = copyTools(toolsDir, syncContext.Stream, chosenList, syncContext.SignatureVerifier, syncContext.TargetToolsUploader)
 	if err != nil {
 		return err
 	}
@@ -183,10 +188,10 @@ func selectSourceDatasource(syncContext *SyncContext) (simplestreams.DataSource,
 }
 
 // copyTools copies a set of tools from the source to the target.
-func copyTools(toolsDir, stream string, tools []*coretools.Tools, u ToolsUploader) error {
+func copyTools(toolsDir, stream string, tools []*coretools.Tools, verifier *envtools.SignatureVerifier, u ToolsUploader) error {
 	for _, tool := range tools {
 		logger.Infof("copying %s from %s", tool.Version, tool.URL)
-		if err := copyOneToolsPackage(toolsDir, stream, tool, u); err != nil {
+		if err := copyOneToolsPackage(toolsDir, stream, tool, verifier, u); err != nil {
 			return err
 		}
 	}
@@ -194,7 +199,7 @@ func copyTools(toolsDir, stream string, tools []*coretools.Tools, u ToolsUploade
 }
 
 // copyOneToolsPackage copies one tool from the source to the target.
-func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, u ToolsUploader) error {
+func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, verifier *envtools.SignatureVerifier, u ToolsUploader) error {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
 	logger.Infof("downloading %q %v (%v)", stream, toolsName, tools.URL)
 	client := http.NewClient()
@@ -214,9 +219,16 @@ func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, u Tool
 	} else if sha256 != tools.SHA256 {
 		return errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, tools.SHA256)
 	}
+	signature, err := verifier.Signature(tools.URL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := verifier.VerifySignature(tools.URL, bytes.NewReader(buf.Bytes()), signature); err != nil {
+		return errors.Trace(err)
+	}
 	sizeInKB := (size + 512) / 1024
 	logger.Infof("uploading %v (%dkB) to model", toolsName, sizeInKB)
-	return u.UploadTools(toolsDir, stream, tools, buf.Bytes())
+	return u.UploadTools(toolsDir, stream, tools, buf.Bytes(), signature)
 }
 
 // UploadFunc is the type of Upload, which may be
@@ -422,7 +434,7 @@ type StorageToolsUploader struct {
 	WriteMirrors  envtools.ShouldWriteMirrors
 }
 
-func (u StorageToolsUploader) UploadTools(toolsDir, stream string, tools *coretools.Tools, data []byte) error {
+func (u StorageToolsUploader) UploadTools(toolsDir, stream string, tools *coretools.Tools, data, _ []byte) error {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
 	if err := u.Storage.Put(toolsName, bytes.NewReader(data), int64(len(data))); err != nil {
 		return err
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/arch"
 	corebase "github.com/juju/juju/core/base"
 	coreos "github.com/juju/juju/core/os"
@@ -197,12 +198,75 @@ func (s *syncSuite) TestSyncToolsOldPatchVersion(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *syncSuite) TestSyncToolsVerifiesSignatures(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	signer, signers := toolstesting.NewAgentBinarySigner(c)
+	cfg := coretesting.FakeControllerConfig()
+	cfg[controller.AgentBinarySigners] = signers
+	cfg[controller.RequireSignedAgentBinaries] = true
+	verifier, err := envtools.NewSignatureVerifier(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	sign := func(vers version.Binary, data []byte) {
+		path := filepath.Join(s.localStorage, "tools", "released", fmt.Sprintf("juju-%s.tgz", vers))
+		if data == nil {
+			var err error
+			data, err = os.ReadFile(path)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		err := os.WriteFile(path+envtools.SignatureSuffix, toolstesting.SignAgentBinary(c, signer, data), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	var uploader *fakeToolsUploader
+	syncTools := func() (map[version.Binary]bool, error) {
+		uploader = &fakeToolsUploader{
+			uploaded:   make(map[version.Binary]bool),
+			signatures: make(map[version.Binary][]byte),
+		}
+		err := sync.SyncTools(&sync.SyncContext{
+			Source:              s.localStorage,
+			ChosenVersion:       version.MustParse("1.8.0"),
+			TargetToolsUploader: uploader,
+			SignatureVerifier:   verifier,
+		})
+		return uploader.uploaded, err
+	}
+
+	sign(v180u64, nil)
+	_, err = syncTools()
+	c.Assert(err, gc.ErrorMatches, `require-signed-agent-binaries requires signed agent binaries: signature for .*juju-1.8.0-ubuntu-arm64.tgz not found`)
+
+	sign(v180u32, []byte("tampered"))
+	_, err = syncTools()
+	c.Assert(err, gc.ErrorMatches, `verifying signature of .*juju-1.8.0-ubuntu-arm64.tgz: invalid signature: .*`)
+
+	sign(v180u32, nil)
+	uploaded, err := syncTools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uploaded, jc.DeepEquals, map[version.Binary]bool{v180u64: true, v180u32: true})
+
+	// The signatures are uploaded with the agent binaries.
+	for vers, signature := range uploader.signatures {
+		path := filepath.Join(s.localStorage, "tools", "released", fmt.Sprintf("juju-%s.tgz", vers))
+		expected, err := os.ReadFile(path + envtools.SignatureSuffix)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(signature, jc.DeepEquals, expected)
+	}
+	c.Assert(uploader.signatures, gc.HasLen, 2)
+}
+
 type fakeToolsUploader struct {
-	uploaded map[version.Binary]bool
+	uploaded   map[version.Binary]bool
+	signatures map[version.Binary][]byte
 }
 
-func (u *fakeToolsUploader) UploadTools(_, _ string, tools *coretools.Tools, _ []byte) error {
+func (u *fakeToolsUploader) UploadTools(_, _ string, tools *coretools.Tools, _, signature []byte) error {
 	u.uploaded[tools.Version] = true
+	if u.signatures != nil {
+		u.signatures[tools.Version] = signature
+	}
 	return nil
 }
 
@@ -597,7 +661,7 @@ func (s *uploadSuite) testStorageToolsUploaderWriteMirrors(c *gc.C, writeMirrors
 			Version: coretesting.CurrentVersion(),
 			Size:    7,
 			SHA256:  "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73",
-		}, []byte("content"))
+		}, []byte("content"), nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	mirrorsPath := simplestreams.MirrorsPath(envtools.StreamsVersionV1) + simplestreams.UnsignedSuffix
@@ -0,0 +1,131 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujuhttp "github.com/juju/http/v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/pki/ssh"
+)
+
+const (
+	// SignatureSuffix is appended to the URL of an agent binary to
+	// form the URL of its detached signature.
+	SignatureSuffix = ".sig"
+
+	// SignatureNamespace is the namespace in which agent binaries are
+	// signed, as with "ssh-keygen -Y sign -n juju-agent-binary".
+	SignatureNamespace = "juju-agent-binary"
+)
+
+// maxSignatureSize bounds the size of the signatures read.
+const maxSignatureSize = 64 * 1024
+
+// SignatureVerifier verifies the detached signatures of agent binaries
+// against the signers trusted by the controller.
+type SignatureVerifier struct {
+	signers  ssh.AllowedSigners
+	required bool
+}
+
+// NewSignatureVerifier returns a SignatureVerifier trusting the agent
+// binary signers in the controller config.
+func NewSignatureVerifier(cfg controller.Config) (*SignatureVerifier, error) {
+	signers, err := ssh.ParseAllowedSigners(cfg.AgentBinarySigners())
+	if err != nil {
+		return nil, errors.Annotatef(err, "parsing %s", controller.AgentBinarySigners)
+	}
+	return &SignatureVerifier{
+		signers:  signers,
+		required: cfg.RequireSignedAgentBinaries(),
+	}, nil
+}
+
+// Verify checks the data of the agent binary at the URL against the
+// signature next to it. If no signers are trusted, the binary is not
+// checked. If the binary isn't signed, an error is returned only if
+// signatures are required.
+func (v *SignatureVerifier) Verify(url string, data io.Reader) error {
+	signature, err := v.Signature(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := url
+	if name == "" {
+		name = "uploaded agent binary"
+	}
+	return errors.Trace(v.VerifySignature(name, data, signature))
+}
+
+// Signature returns the signature next to the agent binary at the URL,
+// or nil if the binary isn't signed or signatures aren't checked.
+func (v *SignatureVerifier) Signature(url string) ([]byte, error) {
+	if !v.enabled() || url == "" {
+		return nil, nil
+	}
+	signature, err := fetchSignature(url + SignatureSuffix)
+	if errors.Is(err, errors.NotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "fetching signature of %s", url)
+	}
+	return signature, nil
+}
+
+// VerifySignature checks the data of the named agent binary against the
+// signature supplied with it, as when agent binaries are uploaded. An empty
+// signature is treated as the binary not being signed.
+func (v *SignatureVerifier) VerifySignature(name string, data io.Reader, signature []byte) error {
+	if !v.enabled() {
+		return nil
+	}
+	if len(signature) == 0 {
+		return v.unsigned(name)
+	}
+	principals, err := v.signers.Verify(data, signature, SignatureNamespace, time.Now())
+	if err != nil {
+		return errors.Annotatef(err, "verifying signature of %s", name)
+	}
+	logger.Infof("agent binary %s signed by %s", name, strings.Join(principals, ","))
+	return nil
+}
+
+// enabled reports whether signatures are checked at all.
+func (v *SignatureVerifier) enabled() bool {
+	return v != nil && (len(v.signers) > 0 || v.required)
+}
+
+func (v *SignatureVerifier) unsigned(name string) error {
+	if v.required {
+		return errors.Annotatef(errors.NotFoundf("signature for %s", name),
+			"%s requires signed agent binaries", controller.RequireSignedAgentBinaries)
+	}
+	logger.Warningf("agent binary %s is not signed", name)
+	return nil
+}
+
+// fetchSignature returns the contents of the signature at the URL.
+var fetchSignature = func(url string) ([]byte, error) {
+	resp, err := jujuhttp.NewClient().Get(context.TODO(), url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.NotFoundf("signature %s", url)
+	default:
+		return nil, errors.Errorf("bad HTTP response: %v", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+}
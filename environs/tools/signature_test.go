@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/tools"
+	toolstesting "github.com/juju/juju/environs/tools/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SignatureSuite struct {
+	coretesting.BaseSuite
+
+	files   map[string][]byte
+	baseURL string
+	signers string
+}
+
+var _ = gc.Suite(&SignatureSuite{})
+
+var agentBinary = []byte("agent binary")
+
+func (s *SignatureSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	signer, signers := toolstesting.NewAgentBinarySigner(c)
+	s.signers = signers
+	s.files = map[string][]byte{
+		"/juju-3.5.0-ubuntu-amd64.tgz.sig": toolstesting.SignAgentBinary(c, signer, agentBinary),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := s.files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	s.baseURL = server.URL
+}
+
+func (s *SignatureSuite) newVerifier(c *gc.C, signers string, required bool) *tools.SignatureVerifier {
+	cfg := coretesting.FakeControllerConfig()
+	cfg[controller.AgentBinarySigners] = signers
+	cfg[controller.RequireSignedAgentBinaries] = required
+	verifier, err := tools.NewSignatureVerifier(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return verifier
+}
+
+func (s *SignatureSuite) TestVerify(c *gc.C) {
+	for _, required := range []bool{false, true} {
+		verifier := s.newVerifier(c, s.signers, required)
+		err := verifier.Verify(s.baseURL+"/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader(agentBinary))
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *SignatureSuite) TestVerifyTampered(c *gc.C) {
+	verifier := s.newVerifier(c, s.signers, false)
+	err := verifier.Verify(s.baseURL+"/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader([]byte("tampered")))
+	c.Check(err, gc.ErrorMatches, `verifying signature of .*/juju-3.5.0-ubuntu-amd64.tgz: invalid signature: .*`)
+}
+
+func (s *SignatureSuite) TestVerifyUntrusted(c *gc.C) {
+	_, otherSigners := toolstesting.NewAgentBinarySigner(c)
+	verifier := s.newVerifier(c, otherSigners, false)
+	err := verifier.Verify(s.baseURL+"/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader(agentBinary))
+	c.Check(err, gc.ErrorMatches, `verifying signature of .*: ssh-ed25519 key .* not trusted`)
+	c.Check(err, jc.ErrorIs, errors.Unauthorized)
+}
+
+func (s *SignatureSuite) TestVerifyUnsigned(c *gc.C) {
+	verifier := s.newVerifier(c, s.signers, false)
+	err := verifier.Verify(s.baseURL+"/juju-3.5.1-ubuntu-amd64.tgz", bytes.NewReader(agentBinary))
+	c.Check(err, jc.ErrorIsNil)
+	err = verifier.Verify("", bytes.NewReader(agentBinary))
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *SignatureSuite) TestVerifyUnsignedRequired(c *gc.C) {
+	verifier := s.newVerifier(c, s.signers, true)
+	err := verifier.Verify(s.baseURL+"/juju-3.5.1-ubuntu-amd64.tgz", bytes.NewReader(agentBinary))
+	c.Check(err, gc.ErrorMatches, `require-signed-agent-binaries requires signed agent binaries: signature for .*/juju-3.5.1-ubuntu-amd64.tgz not found`)
+	c.Check(err, jc.ErrorIs, errors.NotFound)
+
+	err = verifier.Verify("", bytes.NewReader(agentBinary))
+	c.Check(err, gc.ErrorMatches, `require-signed-agent-binaries requires signed agent binaries: signature for uploaded agent binary not found`)
+}
+
+func (s *SignatureSuite) TestVerifySignature(c *gc.C) {
+	signature, err := s.newVerifier(c, s.signers, true).Signature(s.baseURL + "/juju-3.5.0-ubuntu-amd64.tgz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signature, gc.Not(gc.HasLen), 0)
+
+	verifier := s.newVerifier(c, s.signers, true)
+	err = verifier.VerifySignature("juju-3.5.0-ubuntu-amd64", bytes.NewReader(agentBinary), signature)
+	c.Check(err, jc.ErrorIsNil)
+	err = verifier.VerifySignature("juju-3.5.0-ubuntu-amd64", bytes.NewReader([]byte("tampered")), signature)
+	c.Check(err, gc.ErrorMatches, `verifying signature of juju-3.5.0-ubuntu-amd64: invalid signature: .*`)
+	err = verifier.VerifySignature("juju-3.5.0-ubuntu-amd64", bytes.NewReader(agentBinary), nil)
+	c.Check(err, gc.ErrorMatches, `require-signed-agent-binaries requires signed agent binaries: signature for juju-3.5.0-ubuntu-amd64 not found`)
+	c.Check(err, jc.ErrorIs, errors.NotFound)
+}
+
+func (s *SignatureSuite) TestVerifyWithoutSigners(c *gc.C) {
+	// Without signers, signatures aren't even fetched.
+	verifier := s.newVerifier(c, "", false)
+	err := verifier.Verify("http://0.1.2.3/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader([]byte("tampered")))
+	c.Check(err, jc.ErrorIsNil)
+
+	var nilVerifier *tools.SignatureVerifier
+	err = nilVerifier.Verify("http://0.1.2.3/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader([]byte("tampered")))
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *SignatureSuite) TestVerifyFetchError(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	verifier := s.newVerifier(c, s.signers, false)
+	err := verifier.Verify(server.URL+"/juju-3.5.0-ubuntu-amd64.tgz", bytes.NewReader(agentBinary))
+	c.Check(err, gc.ErrorMatches, `fetching signature of .*: bad HTTP response: 500 Internal Server Error`)
+}
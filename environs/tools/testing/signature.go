@@ -0,0 +1,35 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	cryptossh "golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/pki/ssh"
+)
+
+// NewAgentBinarySigner returns a new key with which to sign agent
+// binaries, and the allowed signers entry trusting it.
+func NewAgentBinarySigner(c *gc.C) (cryptossh.Signer, string) {
+	pk, err := ssh.ED25519()
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := cryptossh.NewSignerFromKey(pk)
+	c.Assert(err, jc.ErrorIsNil)
+	allowedSigner := fmt.Sprintf("juju@example.com namespaces=%q %s",
+		tools.SignatureNamespace, cryptossh.MarshalAuthorizedKey(signer.PublicKey()))
+	return signer, allowedSigner
+}
+
+// SignAgentBinary returns the detached signature of the agent binary.
+func SignAgentBinary(c *gc.C, signer cryptossh.Signer, data []byte) []byte {
+	signature, err := ssh.Sign(bytes.NewReader(data), signer, tools.SignatureNamespace)
+	c.Assert(err, jc.ErrorIsNil)
+	return signature
+}
@@ -24,6 +24,7 @@ type PrecheckBackend interface {
 	AllModelUUIDs() ([]string, error)
 	IsUpgrading() (bool, error)
 	IsMigrationActive(string) (bool, error)
+	UpgradeRolloutActive() (bool, error)
 	AllMachines() ([]PrecheckMachine, error)
 	AllApplications() ([]PrecheckApplication, error)
 	AllRelations() ([]PrecheckRelation, error)
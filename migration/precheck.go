@@ -383,6 +383,13 @@ func (ctx *precheckSource) checkModel() error {
 	if model.MigrationMode() == state.MigrationModeImporting {
 		return errors.New("model is being imported as part of another migration")
 	}
+	// Machines in a staged upgrade run mixed agent versions, which
+	// the target controller can't represent.
+	if active, err := ctx.backend.UpgradeRolloutActive(); err != nil {
+		return errors.Annotate(err, "checking for staged upgrade")
+	} else if active {
+		return errors.New("staged upgrade in progress")
+	}
 	if credTag, found := model.CloudCredentialTag(); found {
 		creds, err := ctx.backend.CloudCredential(credTag)
 		if err != nil {
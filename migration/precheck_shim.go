@@ -37,6 +37,17 @@ func (s *precheckShim) IsMigrationActive(modelUUID string) (bool, error) {
 	return state.IsMigrationActive(s.State, modelUUID)
 }
 
+// UpgradeRolloutActive implements PrecheckBackend.
+func (s *precheckShim) UpgradeRolloutActive() (bool, error) {
+	rollout, err := s.State.UpgradeRollout()
+	if errors.Is(err, errors.NotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return rollout.Status().Active(), nil
+}
+
 // AgentVersion implements PrecheckBackend.
 func (s *precheckShim) AgentVersion() (version.Number, error) {
 	model, err := s.State.Model()
@@ -171,6 +171,20 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestUpgradeRolloutActiveError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.upgradeRolloutActiveErr = errors.New("boom")
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking for staged upgrade: boom")
+}
+
+func (*SourcePrecheckSuite) TestUpgradeRolloutActive(c *gc.C) {
+	backend := newFakeBackend()
+	backend.upgradeRolloutActive = true
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "staged upgrade in progress")
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -833,6 +847,9 @@ type fakeBackend struct {
 	migrationActive    bool
 	migrationActiveErr error
 
+	upgradeRolloutActive    bool
+	upgradeRolloutActiveErr error
+
 	machines       []migration.PrecheckMachine
 	allMachinesErr error
 
@@ -882,6 +899,10 @@ func (b *fakeBackend) IsMigrationActive(string) (bool, error) {
 	return b.migrationActive, b.migrationActiveErr
 }
 
+func (b *fakeBackend) UpgradeRolloutActive() (bool, error) {
+	return b.upgradeRolloutActive, b.upgradeRolloutActiveErr
+}
+
 func (b *fakeBackend) CloudCredential(_ names.CloudCredentialTag) (state.Credential, error) {
 	return b.credentials, b.credentialsErr
 }
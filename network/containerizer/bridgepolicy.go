@@ -25,6 +25,7 @@ var logger = loggo.GetLogger("juju.network.containerizer")
 var skippedDeviceNames = set.NewStrings(
 	network.DefaultLXDBridge,
 	network.DefaultKVMBridge,
+	network.DefaultNspawnBridge,
 )
 
 // namedNICsBySpace is a type alias for a map of link-layer devices
@@ -529,8 +530,11 @@ func (p *BridgePolicy) PopulateContainerLinkLayerDevices(
 		p.containerNetworkingMethod == "local" {
 
 		localBridgeName := network.DefaultLXDBridge
-		if guest.ContainerType() == instance.KVM {
+		switch guest.ContainerType() {
+		case instance.KVM:
 			localBridgeName = network.DefaultKVMBridge
+		case instance.NSPAWN:
+			localBridgeName = network.DefaultNspawnBridge
 		}
 
 		for _, hostDevice := range devicesPerSpace[corenetwork.AlphaSpaceId] {
@@ -27,6 +27,10 @@ const DefaultLXDBridge = "lxdbr0"
 // Note: we don't import this from 'container' to avoid import loops
 const DefaultKVMBridge = "virbr0"
 
+// DefaultNspawnBridge is the bridge that juju sets up through
+// systemd-networkd for locally networked nspawn containers.
+const DefaultNspawnBridge = "nspawnbr0"
+
 // DefaultDockerBridge is the bridge that is set up by Docker.
 const DefaultDockerBridge = "docker0"
 
@@ -156,6 +160,7 @@ func FilterBridgeAddresses(addresses corenetwork.ProviderAddresses) corenetwork.
 	addressesToRemove := make(map[string][]string)
 	gatherBridgeAddresses(DefaultLXDBridge, addressesToRemove)
 	gatherBridgeAddresses(DefaultKVMBridge, addressesToRemove)
+	gatherBridgeAddresses(DefaultNspawnBridge, addressesToRemove)
 	filtered := filterAddrs(addresses, addressesToRemove)
 	logger.Debugf("addresses after filtering: %v", filtered)
 	return filtered
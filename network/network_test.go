@@ -118,6 +118,10 @@ func (s *NetworkSuite) TestFilterBridgeAddresses(c *gc.C) {
 			return []string{
 				"192.168.122.1",
 			}, nil
+		} else if name == network.DefaultNspawnBridge {
+			return []string{
+				"10.0.7.1/28",
+			}, nil
 		}
 		c.Fatalf("unknown bridge name: %q", name)
 		return nil, nil
@@ -132,6 +136,8 @@ func (s *NetworkSuite) TestFilterBridgeAddresses(c *gc.C) {
 		"10.0.6.10",     // unfiltered
 		"192.168.122.1", // filtered (from virbr0 bridge, 192.168.122.1)
 		"192.168.123.42",
+		"10.0.7.5",     // filtered (from nspawnbr0 bridge, 10.0.7.1/28)
+		"10.0.7.20",    // unfiltered
 		"localhost",    // unfiltered because it isn't an IP address
 		"252.16.134.1", // unfiltered Class E reserved address, used by Fan.
 	}).AsProviderAddresses()
@@ -141,6 +147,7 @@ func (s *NetworkSuite) TestFilterBridgeAddresses(c *gc.C) {
 		"10.0.0.1",
 		"10.0.6.10",
 		"192.168.123.42",
+		"10.0.7.20",
 		"localhost",
 		"252.16.134.1",
 	}).AsProviderAddresses()
@@ -0,0 +1,10 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nftables
+
+// IngressRuleExprs returns the nftables rule expressions rendered for
+// the command, as they are listed by nft.
+func IngressRuleExprs(c IngressRuleCommand) []string {
+	return c.exprs()
+}
@@ -0,0 +1,369 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nftables
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
+)
+
+var logger = loggo.GetLogger("juju.network.nftables")
+
+const (
+	// TableFamily is the nftables address family of the Juju table.
+	// The inet family handles both IPv4 and IPv6 traffic.
+	TableFamily = "inet"
+
+	// TableName is the name of the nftables table holding Juju's
+	// rules, kept apart from tables managed by other tooling.
+	TableName = "juju"
+
+	// ChainName is the name of the chain in the Juju table which is
+	// hooked into input filtering.
+	ChainName = "input"
+
+	// nftablesIngressComment is the comment attached to nftables
+	// rules directly related to ingress rules.
+	nftablesIngressComment = "juju ingress"
+
+	// nftablesInternalComment is the comment attached to nftables
+	// rules that are not directly related to ingress rules.
+	nftablesInternalComment = "juju internal"
+)
+
+// Backend identifies the tooling used to manage a host's firewall.
+type Backend string
+
+const (
+	// BackendIPTables indicates that rules are managed with iptables.
+	BackendIPTables Backend = "iptables"
+
+	// BackendNFTables indicates that rules are managed with nft.
+	BackendNFTables Backend = "nftables"
+)
+
+// DetectBackendCommand is a command which can be executed via bash to
+// find out which firewall backend a host uses. Its output is parsed by
+// ParseBackend. Hosts with nft and no legacy iptables rules use nftables;
+// everything else, including hosts where iptables is the nftables
+// compatibility layer, keeps using iptables so existing rules are
+// still found.
+const DetectBackendCommand = `if command -v nft >/dev/null 2>&1 && ! (sudo iptables -V 2>/dev/null | grep -q legacy) && ! (sudo iptables-save 2>/dev/null | grep -q 'juju '); then echo nftables; else echo iptables; fi`
+
+// ParseBackend parses the output of DetectBackendCommand.
+func ParseBackend(output string) (Backend, error) {
+	switch backend := Backend(strings.TrimSpace(output)); backend {
+	case BackendIPTables, BackendNFTables:
+		return backend, nil
+	}
+	return "", errors.NotValidf("firewall backend %q", strings.TrimSpace(output))
+}
+
+// chainRef is the table and chain that all of Juju's rules go in.
+var chainRef = fmt.Sprintf("%s %s %s", TableFamily, TableName, ChainName)
+
+// SetupCommand represents the nft commands which create the Juju table
+// and chain. Adding a table or chain that already exists is a no-op, so
+// the commands are idempotent.
+type SetupCommand struct{}
+
+// Render renders the command to a string which can be executed via
+// bash in order to create the Juju table and chain.
+func (SetupCommand) Render() string {
+	return fmt.Sprintf(
+		"sudo nft add table %s %s && sudo nft '%s'",
+		TableFamily, TableName,
+		fmt.Sprintf("add chain %s { type filter hook input priority 0; policy accept; }", chainRef),
+	)
+}
+
+// ListCommand represents the nft command which lists the rules in the
+// Juju chain, in the form accepted by ParseIngressRules. Nothing is
+// listed if the chain does not exist yet.
+type ListCommand struct{}
+
+// Render renders the command to a string which can be executed via
+// bash in order to list the rules.
+func (ListCommand) Render() string {
+	return fmt.Sprintf("sudo nft list chain %s 2>/dev/null || true", chainRef)
+}
+
+// DropCommand represents an nftables drop verdict command.
+type DropCommand struct {
+	DestinationAddress string
+	Interface          string
+}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install the nftables rule.
+func (c DropCommand) Render() string {
+	var args []string
+	if c.Interface != "" {
+		args = append(args, "iifname", c.Interface)
+	}
+	if c.DestinationAddress != "" {
+		args = append(args, addressFamily(c.DestinationAddress), "daddr", c.DestinationAddress)
+	}
+	args = append(args, "ct state new drop", comment(nftablesInternalComment))
+	return insertRule(strings.Join(args, " "))
+}
+
+// AcceptInternalCommand represents an nftables accept verdict command,
+// for accepting traffic, optionally specifying a protocol, destination
+// address, and destination port.
+//
+// This is intended only for allowing traffic according to Juju's internal
+// rules, e.g. for API or SSH. This should not be used for managing the
+// ingress rules for exposing applications.
+type AcceptInternalCommand struct {
+	DestinationAddress string
+	DestinationPort    int
+	Protocol           string
+}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install the nftables rule.
+func (c AcceptInternalCommand) Render() string {
+	var args []string
+	if c.DestinationAddress != "" {
+		args = append(args, addressFamily(c.DestinationAddress), "daddr", c.DestinationAddress)
+	}
+	if c.Protocol != "" {
+		if c.DestinationPort > 0 {
+			args = append(args, c.Protocol, "dport", fmt.Sprint(c.DestinationPort))
+		} else {
+			args = append(args, "meta l4proto", c.Protocol)
+		}
+	}
+	args = append(args, "accept", comment(nftablesInternalComment))
+	return insertRule(strings.Join(args, " "))
+}
+
+// IngressRuleCommand represents nftables accept verdict commands for
+// ingress rules. A separate nftables rule is rendered for each source
+// CIDR, so that rules are listed by nft exactly as they were rendered.
+type IngressRuleCommand struct {
+	Rule               firewall.IngressRule
+	DestinationAddress string
+	Delete             bool
+}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install or remove the nftables rules.
+func (c IngressRuleCommand) Render() string {
+	exprs := c.exprs()
+	cmds := make([]string, len(exprs))
+	for i, expr := range exprs {
+		// As with iptables, we check for existing rules so that
+		// inserting and deleting are idempotent. Deleting a rule
+		// in nftables requires its handle, which is shown when
+		// listing the chain with -a.
+		check := fmt.Sprintf("sudo nft list chain %s 2>/dev/null | grep -qxF '\t\t%s'", chainRef, expr)
+		if c.Delete {
+			cmds[i] = fmt.Sprintf(
+				"(%s) && (sudo nft -a list chain %s | grep -F '\t\t%s # handle ' | awk '{print $NF}' | xargs -r -n1 sudo nft delete rule %s handle)",
+				check, chainRef, expr, chainRef,
+			)
+		} else {
+			cmds[i] = fmt.Sprintf("(%s) || (%s)", check, insertRule(expr))
+		}
+	}
+	return strings.Join(cmds, " && ")
+}
+
+func (c IngressRuleCommand) exprs() []string {
+	var dest string
+	if c.DestinationAddress != "" {
+		dest = fmt.Sprintf("%s daddr %s ", addressFamily(c.DestinationAddress), c.DestinationAddress)
+	}
+	verdict := "accept " + comment(nftablesIngressComment)
+
+	sources := c.Rule.SourceCIDRs.SortedValues()
+	if len(sources) == 0 {
+		return []string{fmt.Sprintf("%s%s %s", dest, c.ports(c.DestinationAddress), verdict)}
+	}
+	exprs := make([]string, len(sources))
+	for i, cidr := range sources {
+		source := normaliseCIDR(cidr)
+		exprs[i] = fmt.Sprintf("%s saddr %s %s%s %s", addressFamily(source), source, dest, c.ports(source), verdict)
+	}
+	return exprs
+}
+
+// ports returns the expression matching the rule's port range. ICMP
+// echo requests are matched for the address family of the input address,
+// which may be empty for IPv4.
+func (c IngressRuleCommand) ports(addr string) string {
+	switch {
+	case c.Rule.PortRange.Protocol == "icmp" && addressFamily(addr) == "ip6":
+		return "icmpv6 type echo-request"
+	case c.Rule.PortRange.Protocol == "icmp":
+		return "icmp type echo-request"
+	case c.Rule.PortRange.ToPort-c.Rule.PortRange.FromPort > 0:
+		return fmt.Sprintf("%s dport %d-%d",
+			c.Rule.PortRange.Protocol, c.Rule.PortRange.FromPort, c.Rule.PortRange.ToPort)
+	default:
+		return fmt.Sprintf("%s dport %d", c.Rule.PortRange.Protocol, c.Rule.PortRange.FromPort)
+	}
+}
+
+func insertRule(expr string) string {
+	return fmt.Sprintf("sudo nft '%s'", fmt.Sprintf("insert rule %s %s", chainRef, expr))
+}
+
+func comment(text string) string {
+	return fmt.Sprintf("comment %q", text)
+}
+
+// addressFamily returns the nftables payload protocol for the address or
+// CIDR, "ip" or "ip6".
+func addressFamily(addr string) string {
+	if strings.Contains(addr, ":") {
+		return "ip6"
+	}
+	return "ip"
+}
+
+// normaliseCIDR returns the CIDR as nft lists it: the network address,
+// with the prefix length omitted for single hosts.
+func normaliseCIDR(cidr string) string {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		// CIDRs are pre-validated; leave anything else untouched.
+		return cidr
+	}
+	if ones, bits := ipNet.Mask.Size(); ones == bits {
+		return ipNet.IP.String()
+	}
+	return ipNet.String()
+}
+
+// ParseIngressRules parses the output of "nft list chain inet juju input",
+// extracting previously added ingress rules, as rendered by
+// IngressRuleCommand.
+func ParseIngressRules(r io.Reader) (firewall.IngressRules, error) {
+	var rules firewall.IngressRules
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		rule, ok, err := parseIngressRule(strings.TrimSpace(line))
+		if err != nil {
+			logger.Warningf("failed to parse nftables line %q: %v", line, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Annotate(err, "reading nftables output")
+	}
+	return rules, nil
+}
+
+// parseIngressRule parses a single nft output line, extracting an
+// ingress rule if the line represents one, or returning false otherwise.
+//
+// The nftables rules we care about have the following format, and we
+// will skip all other rules:
+//
+//	table inet juju {
+//		chain input {
+//			type filter hook input priority filter; policy accept;
+//			ip saddr 10.0.0.0/8 ip daddr 192.168.0.1 tcp dport 3456-3458 accept comment "juju ingress"
+//			ip daddr 192.168.0.2 udp dport 53 accept comment "juju ingress"
+//			ip daddr 10.0.0.1 icmp type echo-request accept comment "juju ingress"
+//			ip6 saddr fd00::/8 icmpv6 type echo-request accept comment "juju ingress"
+//		}
+//	}
+func parseIngressRule(line string) (firewall.IngressRule, bool, error) {
+	fail := func(err error) (firewall.IngressRule, bool, error) {
+		return firewall.IngressRule{}, false, err
+	}
+
+	// We only care about accept rules with the comment "juju ingress".
+	expr, ok := strings.CutSuffix(line, " accept "+comment(nftablesIngressComment))
+	if !ok {
+		return firewall.IngressRule{}, false, nil
+	}
+
+	fields := strings.Fields(expr)
+	source := firewall.AllNetworksIPV4CIDR
+	var portRange *network.PortRange
+	for len(fields) > 0 {
+		if len(fields) < 3 {
+			return fail(errors.Errorf("unexpected expression %q", strings.Join(fields, " ")))
+		}
+		match, key, value := fields[0], fields[1], fields[2]
+		fields = fields[3:]
+		switch {
+		case (match == "ip" || match == "ip6") && key == "saddr":
+			source = value
+			if !strings.Contains(source, "/") {
+				if match == "ip" {
+					source += "/32"
+				} else {
+					source += "/128"
+				}
+			}
+		case (match == "ip" || match == "ip6") && key == "daddr":
+			// Rules are per address; the destination is not part
+			// of the ingress rule.
+		case (match == "icmp" || match == "icmpv6") && key == "type" && value == "echo-request":
+			portRange = &network.PortRange{FromPort: -1, ToPort: -1, Protocol: "icmp"}
+		case (match == "tcp" || match == "udp") && key == "dport":
+			from, to, err := parsePortRange(value)
+			if err != nil {
+				return fail(errors.Trace(err))
+			}
+			portRange = &network.PortRange{FromPort: from, ToPort: to, Protocol: match}
+		default:
+			return fail(errors.Errorf("unexpected expression %q", strings.Join([]string{match, key, value}, " ")))
+		}
+	}
+	if portRange == nil {
+		return fail(errors.New("could not extract port range"))
+	}
+
+	rule := firewall.NewIngressRule(*portRange, source)
+	if err := rule.Validate(); err != nil {
+		return fail(errors.Trace(err))
+	}
+	return rule, true, nil
+}
+
+func parsePortRange(s string) (int, int, error) {
+	fromStr, toStr, isRange := strings.Cut(s, "-")
+	from, err := parsePort(fromStr)
+	if err != nil {
+		return -1, -1, errors.Trace(err)
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err := parsePort(toStr)
+	if err != nil {
+		return -1, -1, errors.Trace(err)
+	}
+	return from, to, nil
+}
+
+func parsePort(s string) (int, error) {
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return -1, errors.Trace(err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package nftables_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/network/nftables"
+)
+
+type NftablesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&NftablesSuite{})
+
+func (*NftablesSuite) TestSetupCommand(c *gc.C) {
+	assertRender(c,
+		nftables.SetupCommand{},
+		"sudo nft add table inet juju && "+
+			"sudo nft 'add chain inet juju input { type filter hook input priority 0; policy accept; }'",
+	)
+}
+
+func (*NftablesSuite) TestListCommand(c *gc.C) {
+	assertRender(c,
+		nftables.ListCommand{},
+		"sudo nft list chain inet juju input 2>/dev/null || true",
+	)
+}
+
+func (*NftablesSuite) TestDropCommand(c *gc.C) {
+	assertRender(c,
+		nftables.DropCommand{},
+		`sudo nft 'insert rule inet juju input ct state new drop comment "juju internal"'`,
+	)
+	assertRender(c,
+		nftables.DropCommand{DestinationAddress: "1.2.3.4"},
+		`sudo nft 'insert rule inet juju input ip daddr 1.2.3.4 ct state new drop comment "juju internal"'`,
+	)
+	assertRender(c,
+		nftables.DropCommand{Interface: "eth0"},
+		`sudo nft 'insert rule inet juju input iifname eth0 ct state new drop comment "juju internal"'`,
+	)
+}
+
+func (*NftablesSuite) TestAcceptInternalCommand(c *gc.C) {
+	assertRender(c,
+		nftables.AcceptInternalCommand{},
+		`sudo nft 'insert rule inet juju input accept comment "juju internal"'`,
+	)
+	assertRender(c,
+		nftables.AcceptInternalCommand{
+			DestinationAddress: "1.2.3.4",
+			DestinationPort:    17070,
+			Protocol:           "tcp",
+		},
+		`sudo nft 'insert rule inet juju input ip daddr 1.2.3.4 tcp dport 17070 accept comment "juju internal"'`,
+	)
+	assertRender(c,
+		nftables.AcceptInternalCommand{
+			DestinationAddress: "fd00::1",
+			Protocol:           "udp",
+		},
+		`sudo nft 'insert rule inet juju input ip6 daddr fd00::1 meta l4proto udp accept comment "juju internal"'`,
+	)
+}
+
+func (*NftablesSuite) TestIngressRuleCommand(c *gc.C) {
+	assertRender(c,
+		nftables.IngressRuleCommand{
+			Rule: firewall.NewIngressRule(network.MustParsePortRange("icmp")),
+		},
+		"(sudo nft list chain inet juju input 2>/dev/null | grep -qxF '\t\ticmp type echo-request accept comment \"juju ingress\"') || "+
+			`(sudo nft 'insert rule inet juju input icmp type echo-request accept comment "juju ingress"')`,
+	)
+
+	// With "Delete: true", the rule's handle is looked up and the rule
+	// deleted if it exists.
+	assertRender(c,
+		nftables.IngressRuleCommand{
+			Rule:   firewall.NewIngressRule(network.MustParsePortRange("53/udp")),
+			Delete: true,
+		},
+		"(sudo nft list chain inet juju input 2>/dev/null | grep -qxF '\t\tudp dport 53 accept comment \"juju ingress\"') && "+
+			"(sudo nft -a list chain inet juju input | grep -F '\t\tudp dport 53 accept comment \"juju ingress\" # handle ' | "+
+			"awk '{print $NF}' | xargs -r -n1 sudo nft delete rule inet juju input handle)",
+	)
+}
+
+func (*NftablesSuite) TestIngressRuleExprs(c *gc.C) {
+	// TCP, port range.
+	assertExprs(c,
+		nftables.IngressRuleCommand{
+			Rule: firewall.NewIngressRule(network.MustParsePortRange("6001-6007/tcp")),
+		},
+		`tcp dport 6001-6007 accept comment "juju ingress"`,
+	)
+
+	// A rule is rendered per source CIDR, with the CIDRs normalised
+	// as nft lists them.
+	assertExprs(c,
+		nftables.IngressRuleCommand{
+			Rule:               firewall.NewIngressRule(network.MustParsePortRange("80/tcp"), "1.2.3.4/20", "5.6.7.8/32", "fd00::/8"),
+			DestinationAddress: "10.0.0.1",
+		},
+		`ip saddr 1.2.0.0/20 ip daddr 10.0.0.1 tcp dport 80 accept comment "juju ingress"`,
+		`ip saddr 5.6.7.8 ip daddr 10.0.0.1 tcp dport 80 accept comment "juju ingress"`,
+		`ip6 saddr fd00::/8 ip daddr 10.0.0.1 tcp dport 80 accept comment "juju ingress"`,
+	)
+
+	// ICMP echo requests are matched for the address family of each
+	// source, or of the destination if there are no sources.
+	assertExprs(c,
+		nftables.IngressRuleCommand{
+			Rule: firewall.NewIngressRule(network.MustParsePortRange("icmp"), "1.2.3.4/20", "fd00::/8"),
+		},
+		`ip saddr 1.2.0.0/20 icmp type echo-request accept comment "juju ingress"`,
+		`ip6 saddr fd00::/8 icmpv6 type echo-request accept comment "juju ingress"`,
+	)
+	assertExprs(c,
+		nftables.IngressRuleCommand{
+			Rule:               firewall.NewIngressRule(network.MustParsePortRange("icmp")),
+			DestinationAddress: "fd00::1",
+		},
+		`ip6 daddr fd00::1 icmpv6 type echo-request accept comment "juju ingress"`,
+	)
+}
+
+func (*NftablesSuite) TestParseIngressRulesEmpty(c *gc.C) {
+	assertParseIngressRules(c, ``, firewall.IngressRules{})
+}
+
+func (*NftablesSuite) TestParseIngressRulesGarbage(c *gc.C) {
+	assertParseIngressRules(c, `a
+b
+zing accept comment "juju ingress"
+tcp dport 80-x accept comment "juju ingress"
+blargh
+
+`, firewall.IngressRules{})
+}
+
+func (*NftablesSuite) TestParseIngressRulesChecksComment(c *gc.C) {
+	assertParseIngressRules(c, `
+table inet juju {
+	chain input {
+		type filter hook input priority filter; policy accept;
+		tcp dport 53 accept comment "managed by lxd"
+		tcp dport 53 accept comment "juju ingress"
+		udp dport 53 accept comment "juju internal"
+		udp dport 67 accept
+	}
+}
+`[1:], firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("53/tcp"), firewall.AllNetworksIPV4CIDR),
+	})
+}
+
+func (*NftablesSuite) TestParseIngressRules(c *gc.C) {
+	assertParseIngressRules(c, `
+table inet juju {
+	chain input {
+		type filter hook input priority filter; policy accept;
+		ip daddr 10.0.0.1 tcp dport 3456-3458 accept comment "juju ingress"
+		ip saddr 1.2.0.0/20 tcp dport 12345 accept comment "juju ingress"
+		ip saddr 1.2.3.4 udp dport 12345 accept comment "juju ingress"
+		ip6 saddr fd00::/8 udp dport 12345 accept comment "juju ingress"
+		icmp type echo-request accept comment "juju ingress"
+		ip6 saddr fd00::/8 icmpv6 type echo-request accept comment "juju ingress"
+	}
+}
+`[1:],
+		firewall.IngressRules{
+			firewall.NewIngressRule(network.MustParsePortRange("3456-3458/tcp"), firewall.AllNetworksIPV4CIDR),
+			firewall.NewIngressRule(network.MustParsePortRange("12345/tcp"), "1.2.0.0/20"),
+			firewall.NewIngressRule(network.MustParsePortRange("12345/udp"), "1.2.3.4/32"),
+			firewall.NewIngressRule(network.MustParsePortRange("12345/udp"), "fd00::/8"),
+			firewall.NewIngressRule(network.MustParsePortRange("icmp"), firewall.AllNetworksIPV4CIDR),
+			firewall.NewIngressRule(network.MustParsePortRange("icmp"), "fd00::/8"),
+		},
+	)
+}
+
+func (*NftablesSuite) TestRoundTrip(c *gc.C) {
+	rules := firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("80/tcp")),
+		firewall.NewIngressRule(network.MustParsePortRange("8000-8080/tcp"), "192.168.0.0/16"),
+		firewall.NewIngressRule(network.MustParsePortRange("53/udp"), "10.0.0.2/32", "fd00::/8"),
+		firewall.NewIngressRule(network.MustParsePortRange("icmp")),
+		firewall.NewIngressRule(network.MustParsePortRange("icmp"), "fd00::/8"),
+	}
+
+	// Render the rules, list them as nft would, and parse them back.
+	var listing []string
+	for _, rule := range rules {
+		exprs := nftables.IngressRuleExprs(nftables.IngressRuleCommand{
+			Rule:               rule,
+			DestinationAddress: "10.0.0.1",
+		})
+		for _, expr := range exprs {
+			listing = append(listing, "\t\t"+expr)
+		}
+	}
+	parsed, err := nftables.ParseIngressRules(strings.NewReader(strings.Join(listing, "\n")))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Rules without sources come back as applying to all networks,
+	// and rules with several sources come back as one per source.
+	expected := firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("80/tcp"), firewall.AllNetworksIPV4CIDR),
+		firewall.NewIngressRule(network.MustParsePortRange("8000-8080/tcp"), "192.168.0.0/16"),
+		firewall.NewIngressRule(network.MustParsePortRange("53/udp"), "10.0.0.2/32"),
+		firewall.NewIngressRule(network.MustParsePortRange("53/udp"), "fd00::/8"),
+		firewall.NewIngressRule(network.MustParsePortRange("icmp"), firewall.AllNetworksIPV4CIDR),
+		firewall.NewIngressRule(network.MustParsePortRange("icmp"), "fd00::/8"),
+	}
+	c.Assert(parsed, jc.DeepEquals, expected)
+}
+
+func (*NftablesSuite) TestParseBackend(c *gc.C) {
+	backend, err := nftables.ParseBackend("nftables\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backend, gc.Equals, nftables.BackendNFTables)
+
+	backend, err = nftables.ParseBackend("iptables")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(backend, gc.Equals, nftables.BackendIPTables)
+
+	_, err = nftables.ParseBackend("bash: sudo: command not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func assertParseIngressRules(c *gc.C, in string, expect firewall.IngressRules) {
+	rules, err := nftables.ParseIngressRules(strings.NewReader(in))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, expect)
+}
+
+func assertExprs(c *gc.C, cmd nftables.IngressRuleCommand, expect ...string) {
+	c.Assert(nftables.IngressRuleExprs(cmd), jc.DeepEquals, expect)
+}
+
+type renderer interface {
+	Render() string
+}
+
+func assertRender(c *gc.C, r renderer, expect string) {
+	c.Assert(r.Render(), gc.Equals, expect)
+}
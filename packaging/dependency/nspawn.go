@@ -0,0 +1,33 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/base"
+	"github.com/juju/juju/packaging"
+)
+
+// Nspawn returns a dependency instance for installing systemd-nspawn
+// container support.
+func Nspawn() packaging.Dependency {
+	return nspawnDependency{}
+}
+
+type nspawnDependency struct{}
+
+// PackageList implements packaging.Dependency.
+func (dep nspawnDependency) PackageList(b base.Base) ([]packaging.Package, error) {
+	if b.OS != base.UbuntuOS {
+		return nil, errors.NotSupportedf("installing systemd-nspawn on base %q", b)
+	}
+
+	// systemd-container provides machinectl and systemd-nspawn; the
+	// cloud image root filesystems are xz compressed tarballs.
+	return packaging.MakePackageList(packaging.AptPackageManager, "",
+		"systemd-container",
+		"xz-utils",
+	), nil
+}
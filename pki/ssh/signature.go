@@ -0,0 +1,295 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	cryptossh "golang.org/x/crypto/ssh"
+)
+
+// The detached signature format is the one produced by
+// "ssh-keygen -Y sign", described in PROTOCOL.sshsig in the OpenSSH
+// sources.
+const (
+	signatureMagic   = "SSHSIG"
+	signatureVersion = 1
+	signaturePEMType = "SSH SIGNATURE"
+)
+
+// signatureBlob is the signature, following the magic preamble.
+type signatureBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// signedData is the data which is actually signed, following the
+// magic preamble.
+type signedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+func newSignatureHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, errors.NotSupportedf("signature hash algorithm %q", algorithm)
+}
+
+func signedDataFor(message io.Reader, namespace, hashAlgorithm string) ([]byte, error) {
+	h, err := newSignatureHash(hashAlgorithm)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, errors.Annotate(err, "reading signed data")
+	}
+	return append([]byte(signatureMagic), cryptossh.Marshal(signedData{
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...), nil
+}
+
+// Sign returns an armored detached signature of the message, in the
+// format produced by "ssh-keygen -Y sign", for the namespace.
+func Sign(message io.Reader, signer cryptossh.Signer, namespace string) ([]byte, error) {
+	if namespace == "" {
+		return nil, errors.NotValidf("empty namespace")
+	}
+	const hashAlgorithm = "sha512"
+	data, err := signedDataFor(message, namespace, hashAlgorithm)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var sig *cryptossh.Signature
+	// SHA-1 RSA signatures are not allowed.
+	if algSigner, ok := signer.(cryptossh.AlgorithmSigner); ok && signer.PublicKey().Type() == cryptossh.KeyAlgoRSA {
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, data, cryptossh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "signing data")
+	}
+
+	blob := append([]byte(signatureMagic), cryptossh.Marshal(signatureBlob{
+		Version:       signatureVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		Signature:     cryptossh.Marshal(sig),
+	})...)
+	return pem.EncodeToMemory(&pem.Block{Type: signaturePEMType, Bytes: blob}), nil
+}
+
+// parseSignature parses an armored detached signature.
+func parseSignature(armored []byte) (*signatureBlob, cryptossh.PublicKey, *cryptossh.Signature, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != signaturePEMType {
+		return nil, nil, nil, errors.NotValidf("signature without %q armor", signaturePEMType)
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(signatureMagic)) {
+		return nil, nil, nil, errors.NotValidf("signature without %q preamble", signatureMagic)
+	}
+	var blob signatureBlob
+	if err := cryptossh.Unmarshal(block.Bytes[len(signatureMagic):], &blob); err != nil {
+		return nil, nil, nil, errors.NewNotValid(err, "signature")
+	}
+	if blob.Version != signatureVersion {
+		return nil, nil, nil, errors.NotSupportedf("signature version %d", blob.Version)
+	}
+	publicKey, err := cryptossh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return nil, nil, nil, errors.NewNotValid(err, "signature public key")
+	}
+	var sig cryptossh.Signature
+	if err := cryptossh.Unmarshal(blob.Signature, &sig); err != nil {
+		return nil, nil, nil, errors.NewNotValid(err, "signature")
+	}
+	return &blob, publicKey, &sig, nil
+}
+
+// AllowedSigner is an entry in an allowed signers file, as used by
+// "ssh-keygen -Y verify".
+type AllowedSigner struct {
+	// Principals are the patterns matching the identities of the
+	// signer.
+	Principals []string
+
+	// Namespaces, if not empty, are the patterns matching the
+	// namespaces for which the key is trusted.
+	Namespaces []string
+
+	// ValidAfter and ValidBefore, if not zero, bound the times at which
+	// the key is trusted.
+	ValidAfter  time.Time
+	ValidBefore time.Time
+
+	// PublicKey is the signer's key.
+	PublicKey cryptossh.PublicKey
+}
+
+// AllowedSigners is a set of keys trusted to sign data.
+type AllowedSigners []AllowedSigner
+
+// ParseAllowedSigners parses the keys in data, which is in the allowed
+// signers file format described in ssh-keygen(1). To allow keys to be
+// specified without identities, lines containing only a public key in
+// authorized_keys format are also accepted, and trusted for any
+// namespace.
+func ParseAllowedSigners(data string) (AllowedSigners, error) {
+	var signers AllowedSigners
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		signer, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, errors.Annotatef(err, "line %d", lineNum)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, errors.Trace(scanner.Err())
+}
+
+func parseAllowedSigner(line string) (AllowedSigner, error) {
+	if key, _, options, _, err := cryptossh.ParseAuthorizedKey([]byte(line)); err == nil && options == nil {
+		return AllowedSigner{Principals: []string{"*"}, PublicKey: key}, nil
+	}
+
+	principals, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return AllowedSigner{}, errors.NotValidf("allowed signer without key")
+	}
+	key, _, options, _, err := cryptossh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+	if err != nil {
+		return AllowedSigner{}, errors.NewNotValid(err, "allowed signer key")
+	}
+	signer := AllowedSigner{
+		Principals: strings.Split(principals, ","),
+		PublicKey:  key,
+	}
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(name) {
+		case "namespaces":
+			signer.Namespaces = strings.Split(value, ",")
+		case "valid-after":
+			if signer.ValidAfter, err = parseSignerTime(value); err != nil {
+				return AllowedSigner{}, errors.Trace(err)
+			}
+		case "valid-before":
+			if signer.ValidBefore, err = parseSignerTime(value); err != nil {
+				return AllowedSigner{}, errors.Trace(err)
+			}
+		case "cert-authority":
+			return AllowedSigner{}, errors.NotSupportedf("cert-authority allowed signers")
+		default:
+			return AllowedSigner{}, errors.NotValidf("allowed signer option %q", name)
+		}
+	}
+	return signer, nil
+}
+
+// parseSignerTime parses a time in the YYYYMMDD[hhmm[ss]][Z] format used
+// by allowed signers files. Times without the "Z" suffix are local.
+func parseSignerTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") {
+		value, loc = strings.TrimSuffix(value, "Z"), time.UTC
+	}
+	for _, layout := range []string{"20060102", "200601021504", "20060102150405"} {
+		if len(value) == len(layout) {
+			if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, errors.NotValidf("allowed signer time %q", value)
+}
+
+// trusts returns whether the signer is trusted to sign data in the
+// namespace at the time.
+func (s AllowedSigner) trusts(key cryptossh.PublicKey, namespace string, now time.Time) bool {
+	if !bytes.Equal(s.PublicKey.Marshal(), key.Marshal()) {
+		return false
+	}
+	if !s.ValidAfter.IsZero() && now.Before(s.ValidAfter) {
+		return false
+	}
+	if !s.ValidBefore.IsZero() && !now.Before(s.ValidBefore) {
+		return false
+	}
+	if len(s.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range s.Namespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that signature is a valid detached signature of the
+// message for the namespace, made by one of the allowed signers at the
+// specified time. It returns the principals of the signer.
+func (a AllowedSigners) Verify(message io.Reader, signature []byte, namespace string, now time.Time) ([]string, error) {
+	blob, publicKey, sig, err := parseSignature(signature)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if blob.Namespace != namespace {
+		return nil, errors.NotValidf("signature for namespace %q", blob.Namespace)
+	}
+
+	var signer *AllowedSigner
+	for i := range a {
+		if a[i].trusts(publicKey, namespace, now) {
+			signer = &a[i]
+			break
+		}
+	}
+	if signer == nil {
+		return nil, errors.Unauthorizedf("%s key %s not trusted", publicKey.Type(), cryptossh.FingerprintSHA256(publicKey))
+	}
+
+	if sig.Format == cryptossh.KeyAlgoRSA {
+		return nil, errors.NotSupportedf("SHA-1 RSA signature")
+	}
+	data, err := signedDataFor(message, blob.Namespace, blob.HashAlgorithm)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := publicKey.Verify(data, sig); err != nil {
+		return nil, errors.Unauthorizedf("invalid signature: %v", err)
+	}
+	return signer.Principals, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	cryptossh "golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/pki/ssh"
+)
+
+type SignatureSuite struct {
+}
+
+var _ = gc.Suite(&SignatureSuite{})
+
+// keygenSignature was created with
+// "ssh-keygen -Y sign -n juju-agent-binary -f key data", where data
+// contains "agent binary".
+const (
+	keygenPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAICVi7TLYdWEBAJDA9ZLWav8VSZ86YyM52moGTDsyK7cB test"
+	keygenSignature = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgJWLtMth1YQEAkMD1ktZq/xVJnz
+pjIznaagZMOzIrtwEAAAARanVqdS1hZ2VudC1iaW5hcnkAAAAAAAAABnNoYTUxMgAAAFMA
+AAALc3NoLWVkMjU1MTkAAABAlCGqAtUjioV/yxeZ7p1j7LzLhvKJ5XhIGWe3+aw0f+XLa5
+NvIYoSrOExQt4o6Y1mvSg61+WoMgkacJPBWC2tBA==
+-----END SSH SIGNATURE-----
+`
+	namespace = "juju-agent-binary"
+)
+
+var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newSigner(c *gc.C, profile ssh.KeyProfile) cryptossh.Signer {
+	pk, err := profile()
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := cryptossh.NewSignerFromKey(pk)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+func authorizedKey(signer cryptossh.Signer) string {
+	return strings.TrimSpace(string(cryptossh.MarshalAuthorizedKey(signer.PublicKey())))
+}
+
+func (s *SignatureSuite) TestVerifyKeygenSignature(c *gc.C) {
+	signers, err := ssh.ParseAllowedSigners(`juju@example.com namespaces="juju-*" ` + keygenPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	principals, err := signers.Verify(strings.NewReader("agent binary"), []byte(keygenSignature), namespace, now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(principals, jc.DeepEquals, []string{"juju@example.com"})
+
+	_, err = signers.Verify(strings.NewReader("tampered"), []byte(keygenSignature), namespace, now)
+	c.Check(err, gc.ErrorMatches, "invalid signature: .*")
+	c.Check(err, jc.ErrorIs, errors.Unauthorized)
+}
+
+func (s *SignatureSuite) TestSignVerify(c *gc.C) {
+	for _, profile := range []ssh.KeyProfile{ssh.ED25519, ssh.ECDSAP256, ssh.RSA2048} {
+		signer := newSigner(c, profile)
+		c.Logf("%s key", signer.PublicKey().Type())
+
+		sig, err := ssh.Sign(strings.NewReader("agent binary"), signer, namespace)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(sig), jc.HasPrefix, "-----BEGIN SSH SIGNATURE-----\n")
+
+		signers, err := ssh.ParseAllowedSigners(authorizedKey(signer))
+		c.Assert(err, jc.ErrorIsNil)
+		principals, err := signers.Verify(strings.NewReader("agent binary"), sig, namespace, now)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(principals, jc.DeepEquals, []string{"*"})
+	}
+}
+
+func (s *SignatureSuite) TestVerifyWrongNamespace(c *gc.C) {
+	signers, err := ssh.ParseAllowedSigners(keygenPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = signers.Verify(strings.NewReader("agent binary"), []byte(keygenSignature), "file", now)
+	c.Check(err, gc.ErrorMatches, `signature for namespace "juju-agent-binary" not valid`)
+}
+
+func (s *SignatureSuite) TestVerifyUntrustedKey(c *gc.C) {
+	signer := newSigner(c, ssh.ED25519)
+	signers, err := ssh.ParseAllowedSigners(authorizedKey(signer))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = signers.Verify(strings.NewReader("agent binary"), []byte(keygenSignature), namespace, now)
+	c.Check(err, gc.ErrorMatches, `ssh-ed25519 key SHA256:.* not trusted`)
+	c.Check(err, jc.ErrorIs, errors.Unauthorized)
+}
+
+func (s *SignatureSuite) TestVerifySignerRestrictions(c *gc.C) {
+	for i, test := range []struct {
+		options string
+		trusted bool
+	}{
+		{options: `namespaces="git,file"`},
+		{options: `namespaces="git,juju-agent-binary"`, trusted: true},
+		{options: `valid-after="20240602"`},
+		{options: `valid-after="20240531Z"`, trusted: true},
+		{options: `valid-before="202406010000Z"`},
+		{options: `valid-before="20240601000001Z",namespaces="juju-*"`, trusted: true},
+	} {
+		c.Logf("test %d: %s", i, test.options)
+		signers, err := ssh.ParseAllowedSigners("juju@example.com " + test.options + " " + keygenPublicKey)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = signers.Verify(strings.NewReader("agent binary"), []byte(keygenSignature), namespace, now)
+		if test.trusted {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.ErrorIs, errors.Unauthorized)
+		}
+	}
+}
+
+func (s *SignatureSuite) TestVerifyInvalidSignature(c *gc.C) {
+	signers, err := ssh.ParseAllowedSigners(keygenPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = signers.Verify(strings.NewReader("agent binary"), []byte("not a signature"), namespace, now)
+	c.Check(err, gc.ErrorMatches, `signature without "SSH SIGNATURE" armor not valid`)
+}
+
+func (s *SignatureSuite) TestParseAllowedSigners(c *gc.C) {
+	signers, err := ssh.ParseAllowedSigners(`
+# Comments and blank lines are ignored.
+
+juju@example.com,*@canonical.com namespaces="git" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHmDv0NmGYCW2lZfKlwInnL5y6dsTtyKVRdPgSQx4VQN comment
+` + keygenPublicKey + `
+`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signers, gc.HasLen, 2)
+	c.Check(signers[0].Principals, jc.DeepEquals, []string{"juju@example.com", "*@canonical.com"})
+	c.Check(signers[0].Namespaces, jc.DeepEquals, []string{"git"})
+	c.Check(signers[0].PublicKey.Type(), gc.Equals, "ssh-ed25519")
+	c.Check(signers[1].Principals, jc.DeepEquals, []string{"*"})
+	c.Check(signers[1].Namespaces, gc.HasLen, 0)
+}
+
+func (s *SignatureSuite) TestParseRepositoryAllowedSigners(c *gc.C) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "allowed_signers"))
+	c.Assert(err, jc.ErrorIsNil)
+	signers, err := ssh.ParseAllowedSigners(string(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signers, gc.Not(gc.HasLen), 0)
+	for _, signer := range signers {
+		c.Check(signer.Namespaces, jc.DeepEquals, []string{"git"})
+	}
+}
+
+func (s *SignatureSuite) TestParseAllowedSignersErrors(c *gc.C) {
+	for i, test := range []struct {
+		data string
+		err  string
+	}{{
+		data: "juju@example.com",
+		err:  "line 1: allowed signer without key not valid",
+	}, {
+		data: "\njuju@example.com ssh-ed25519 not-base64",
+		err:  "line 2: allowed signer key: .*",
+	}, {
+		data: `juju@example.com valid-after="yesterday" ` + keygenPublicKey,
+		err:  `line 1: allowed signer time "yesterday" not valid`,
+	}, {
+		data: "juju@example.com cert-authority " + keygenPublicKey,
+		err:  "line 1: cert-authority allowed signers not supported",
+	}, {
+		data: "juju@example.com no-touch-required " + keygenPublicKey,
+		err:  `line 1: allowed signer option "no-touch-required" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.data)
+		_, err := ssh.ParseAllowedSigners(test.data)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/os/ostype"
+	"github.com/juju/juju/environs"
 )
 
 type AzureRenderer struct{}
@@ -25,3 +26,10 @@ func (AzureRenderer) Render(cfg cloudinit.CloudConfig, os ostype.OSType) ([]byte
 		return nil, errors.Errorf("Cannot encode userdata for OS: %s", os)
 	}
 }
+
+var _ environs.UserDataRenderer = (*azureEnviron)(nil)
+
+// UserDataRenderer is part of the environs.UserDataRenderer interface.
+func (*azureEnviron) UserDataRenderer() renderers.ProviderRenderer {
+	return AzureRenderer{}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/network/iptables"
+	"github.com/juju/juju/network/nftables"
 )
 
 // InstanceConfigurator describes methods for manipulating firewall
@@ -34,6 +35,9 @@ type sshInstanceConfigurator struct {
 	client  ssh.Client
 	host    string
 	options *ssh.Options
+
+	// backend is the host's firewall backend, detected on first use.
+	backend nftables.Backend
 }
 
 // NewSshInstanceConfigurator creates new sshInstanceConfigurator.
@@ -68,17 +72,53 @@ func (c *sshInstanceConfigurator) runCommand(cmd string) (string, error) {
 	return string(output), nil
 }
 
+// firewallBackend returns the host's firewall backend, detecting it if
+// that hasn't already been done.
+func (c *sshInstanceConfigurator) firewallBackend() (nftables.Backend, error) {
+	if c.backend != "" {
+		return c.backend, nil
+	}
+	output, err := c.runCommand(nftables.DetectBackendCommand)
+	if err != nil {
+		return "", errors.Errorf("failed to detect firewall backend: %s", output)
+	}
+	if c.backend, err = nftables.ParseBackend(output); err != nil {
+		return "", errors.Trace(err)
+	}
+	logger.Debugf("using %s firewall backend on %s", c.backend, c.host)
+	return c.backend, nil
+}
+
 // DropAllPorts implements InstanceConfigurator interface.
 func (c *sshInstanceConfigurator) DropAllPorts(exceptPorts []int, addr string) error {
-	cmds := []string{
-		iptables.DropCommand{DestinationAddress: addr}.Render(),
+	backend, err := c.firewallBackend()
+	if err != nil {
+		return errors.Trace(err)
 	}
-	for _, port := range exceptPorts {
-		cmds = append(cmds, iptables.AcceptInternalCommand{
-			Protocol:           "tcp",
-			DestinationAddress: addr,
-			DestinationPort:    port,
-		}.Render())
+	var cmds []string
+	if backend == nftables.BackendNFTables {
+		cmds = []string{
+			nftables.SetupCommand{}.Render(),
+			nftables.DropCommand{DestinationAddress: addr}.Render(),
+		}
+		for _, port := range exceptPorts {
+			cmds = append(cmds, nftables.AcceptInternalCommand{
+				Protocol:           "tcp",
+				DestinationAddress: addr,
+				DestinationPort:    port,
+			}.Render())
+		}
+	} else {
+		cmds = []string{
+			iptables.DropCommand{DestinationAddress: addr}.Render(),
+		}
+		for _, port := range exceptPorts {
+			cmds = append(cmds, iptables.AcceptInternalCommand{
+				Protocol:           "tcp",
+				DestinationAddress: addr,
+				DestinationPort:    port,
+			}.Render())
+		}
 	}
 
 	output, err := c.runCommand(strings.Join(cmds, "\n"))
@@ -91,13 +131,28 @@ func (c *sshInstanceConfigurator) DropAllPorts(exceptPorts []int, addr string) e
 
 // ChangeIngressRules implements InstanceConfigurator interface.
 func (c *sshInstanceConfigurator) ChangeIngressRules(ipAddress string, insert bool, rules firewall.IngressRules) error {
+	backend, err := c.firewallBackend()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	var cmds []string
-	for _, rule := range rules {
-		cmds = append(cmds, iptables.IngressRuleCommand{
-			Rule:               rule,
-			DestinationAddress: ipAddress,
-			Delete:             !insert,
-		}.Render())
+	if backend == nftables.BackendNFTables {
+		cmds = append(cmds, nftables.SetupCommand{}.Render())
+		for _, rule := range rules {
+			cmds = append(cmds, nftables.IngressRuleCommand{
+				Rule:               rule,
+				DestinationAddress: ipAddress,
+				Delete:             !insert,
+			}.Render())
+		}
+	} else {
+		for _, rule := range rules {
+			cmds = append(cmds, iptables.IngressRuleCommand{
+				Rule:               rule,
+				DestinationAddress: ipAddress,
+				Delete:             !insert,
+			}.Render())
+		}
 	}
 
 	output, err := c.runCommand(strings.Join(cmds, "\n"))
@@ -110,10 +165,21 @@ func (c *sshInstanceConfigurator) ChangeIngressRules(ipAddress string, insert bo
 
 // FindIngressRules implements InstanceConfigurator interface.
 func (c *sshInstanceConfigurator) FindIngressRules() (firewall.IngressRules, error) {
-	output, err := c.runCommand("sudo iptables -L INPUT -n")
+	backend, err := c.firewallBackend()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	listCommand := "sudo iptables -L INPUT -n"
+	if backend == nftables.BackendNFTables {
+		listCommand = nftables.ListCommand{}.Render()
+	}
+	output, err := c.runCommand(listCommand)
 	if err != nil {
 		return nil, errors.Errorf("failed to list open ports: %s", output)
 	}
 	logger.Tracef("find open ports output: %s", output)
+	if backend == nftables.BackendNFTables {
+		return nftables.ParseIngressRules(strings.NewReader(output))
+	}
 	return iptables.ParseIngressRules(strings.NewReader(output))
 }
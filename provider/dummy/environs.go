@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/jsonschema"
 	"github.com/juju/loggo"
@@ -1860,6 +1861,7 @@ func (*environ) Provider() environs.EnvironProvider {
 type dummyInstance struct {
 	state        *environState
 	rules        firewall.IngressRules
+	egressRules  firewall.EgressRules
 	id           instance.Id
 	status       string
 	machineId    string
@@ -2052,6 +2054,107 @@ func (inst *dummyInstance) IngressRules(ctx context.ProviderCallContext, machine
 	return
 }
 
+func (inst *dummyInstance) OpenEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	defer delay()
+	logger.Infof("openEgressPorts %s, %#v", machineId, rules)
+	if inst.firewallMode != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for opening egress ports on instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("OpenEgressPorts with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("OpenEgressPorts"); err != nil {
+		return err
+	}
+	for _, newRule := range rules {
+		newRule.DestinationCIDRs = set.NewStrings(newRule.DestinationCIDRs.Values()...)
+		if len(newRule.DestinationCIDRs) == 0 {
+			newRule.DestinationCIDRs.Add(firewall.AllNetworksIPV4CIDR)
+			newRule.DestinationCIDRs.Add(firewall.AllNetworksIPV6CIDR)
+		}
+		found := false
+
+		for i, existingRule := range inst.egressRules {
+			if newRule.PortRange != existingRule.PortRange {
+				continue
+			}
+
+			// Append CIDRs from incoming rule
+			inst.egressRules[i].DestinationCIDRs = existingRule.DestinationCIDRs.Union(newRule.DestinationCIDRs)
+			found = true
+			break
+		}
+
+		if !found {
+			inst.egressRules = append(inst.egressRules, newRule)
+		}
+	}
+	return nil
+}
+
+func (inst *dummyInstance) CloseEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for closing egress ports on instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("CloseEgressPorts with mismatched machine id, expected %s got %s", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("CloseEgressPorts"); err != nil {
+		return err
+	}
+
+	var updatedRules firewall.EgressRules
+
+nextRule:
+	for _, existingRule := range inst.egressRules {
+		for _, removeRule := range rules {
+			if removeRule.PortRange != existingRule.PortRange {
+				continue // port not matched
+			}
+
+			existingRule.DestinationCIDRs = existingRule.DestinationCIDRs.Difference(removeRule.DestinationCIDRs)
+
+			// If the rule is empty, OR the entry to be removed
+			// has no CIDRs, drop the rule.
+			if len(existingRule.DestinationCIDRs) == 0 || len(removeRule.DestinationCIDRs) == 0 {
+				continue nextRule // drop existing rule
+			}
+		}
+
+		updatedRules = append(updatedRules, existingRule)
+	}
+	inst.egressRules = updatedRules
+	return nil
+}
+
+func (inst *dummyInstance) EgressRules(ctx context.ProviderCallContext, machineId string) (rules firewall.EgressRules, err error) {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("EgressRules with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("EgressRules"); err != nil {
+		return nil, err
+	}
+	for _, r := range inst.egressRules {
+		rules = append(rules, r)
+	}
+	rules.Sort()
+	return
+}
+
 // providerDelay controls the delay before dummy responds.
 // non empty values in JUJU_DUMMY_DELAY will be parsed as
 // time.Durations into this value.
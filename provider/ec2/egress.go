@@ -0,0 +1,241 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+// allTrafficProtocol is the EC2 protocol name matching all traffic. New
+// security groups have an egress rule allowing all traffic to anywhere.
+const allTrafficProtocol = "-1"
+
+var (
+	_ environs.EgressRulesSupporter      = environProvider{}
+	_ instances.InstanceEgressFirewaller = (*sdkInstance)(nil)
+)
+
+// SupportsEgressRules implements environs.EgressRulesSupporter.
+func (environProvider) SupportsEgressRules() bool {
+	return true
+}
+
+// OpenEgressPorts implements instances.InstanceEgressFirewaller.
+func (inst *sdkInstance) OpenEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for opening egress ports on instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	if err := inst.e.openEgressPortsInGroup(ctx, name, rules); err != nil {
+		return err
+	}
+	logger.Infof("opened egress ports in security group %s: %v", name, rules)
+	return nil
+}
+
+// CloseEgressPorts implements instances.InstanceEgressFirewaller.
+func (inst *sdkInstance) CloseEgressPorts(ctx context.ProviderCallContext, machineId string, rules firewall.EgressRules) error {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for closing egress ports on instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	if err := inst.e.closeEgressPortsInGroup(ctx, name, rules); err != nil {
+		return err
+	}
+	logger.Infof("closed egress ports in security group %s: %v", name, rules)
+	return nil
+}
+
+// EgressRules implements instances.InstanceEgressFirewaller.
+func (inst *sdkInstance) EgressRules(ctx context.ProviderCallContext, machineId string) (firewall.EgressRules, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	rules, err := inst.e.egressRulesInGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func egressRulesToIPPerms(rules firewall.EgressRules) []types.IpPermission {
+	ipPerms := make([]types.IpPermission, len(rules))
+	for i, r := range rules {
+		ipPerms[i] = types.IpPermission{
+			IpProtocol: aws.String(r.PortRange.Protocol),
+			FromPort:   aws.Int32(int32(r.PortRange.FromPort)),
+			ToPort:     aws.Int32(int32(r.PortRange.ToPort)),
+		}
+		if len(r.DestinationCIDRs) == 0 {
+			ipPerms[i].IpRanges = []types.IpRange{{CidrIp: aws.String(defaultRouteIpv4CIDRBlock), Description: egressRangeDescription(r, defaultRouteIpv4CIDRBlock)}}
+			ipPerms[i].Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: aws.String(defaultRouteIPv6CIDRBlock), Description: egressRangeDescription(r, defaultRouteIPv6CIDRBlock)}}
+			continue
+		}
+		for _, cidr := range r.DestinationCIDRs.SortedValues() {
+			// CIDRs are pre-validated; if an invalid CIDR
+			// reaches this loop, it will be skipped.
+			addrType, _ := network.CIDRAddressType(cidr)
+			if addrType == network.IPv4Address {
+				ipPerms[i].IpRanges = append(ipPerms[i].IpRanges, types.IpRange{CidrIp: aws.String(cidr), Description: egressRangeDescription(r, cidr)})
+			} else if addrType == network.IPv6Address {
+				ipPerms[i].Ipv6Ranges = append(ipPerms[i].Ipv6Ranges, types.Ipv6Range{CidrIpv6: aws.String(cidr), Description: egressRangeDescription(r, cidr)})
+			}
+		}
+	}
+	return ipPerms
+}
+
+func egressRangeDescription(rule firewall.EgressRule, cidr string) *string {
+	if cidr == "" || cidr == firewall.AllNetworksIPV4CIDR || cidr == firewall.AllNetworksIPV6CIDR {
+		return aws.String(fmt.Sprintf("juju egress to %s", rule.PortRange))
+	}
+	return aws.String(fmt.Sprintf("juju egress to %s at %s", rule.PortRange, cidr))
+}
+
+// allTrafficEgressPerms returns the egress permissions in the group that
+// allow all traffic, as created by default with every security group.
+func allTrafficEgressPerms(group types.SecurityGroup) []types.IpPermission {
+	var perms []types.IpPermission
+	for _, p := range group.IpPermissionsEgress {
+		if aws.ToString(p.IpProtocol) == allTrafficProtocol {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+// openEgressPortsInGroup allows outbound traffic matching the rules from
+// the named group. The default rule allowing all outbound traffic is
+// removed first, otherwise the rules would have no effect.
+func (e *environ) openEgressPortsInGroup(ctx context.ProviderCallContext, name string, rules firewall.EgressRules) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	g, err := e.groupByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	ipPerms := egressRulesToIPPerms(rules)
+	_, err = e.ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+		GroupId:       g.GroupId,
+		IpPermissions: ipPerms,
+	})
+	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" && len(rules) > 1 {
+		// As with ingress, a duplicate causes the whole request to be
+		// ignored, so authorize each rule individually.
+		for i := range ipPerms {
+			_, err := e.ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+				GroupId:       g.GroupId,
+				IpPermissions: ipPerms[i : i+1],
+			})
+			if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+				return errors.Annotatef(maybeConvertCredentialError(err, ctx), "cannot open egress port %v", ipPerms[i])
+			}
+		}
+	} else if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot open egress ports")
+	}
+
+	// Only revoke the allow-all rule once the new rules are in place, so
+	// that traffic they allow is never interrupted.
+	if allTraffic := allTrafficEgressPerms(g); len(allTraffic) > 0 {
+		_, err = e.ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:       g.GroupId,
+			IpPermissions: allTraffic,
+		})
+		if err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot restrict egress")
+		}
+	}
+	return nil
+}
+
+// closeEgressPortsInGroup stops allowing outbound traffic matching the
+// rules from the named group. If no egress rules remain, the group goes
+// back to allowing all outbound traffic.
+func (e *environ) closeEgressPortsInGroup(ctx context.ProviderCallContext, name string, rules firewall.EgressRules) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	g, err := e.groupByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = e.ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+		GroupId:       g.GroupId,
+		IpPermissions: egressRulesToIPPerms(rules),
+	})
+	if err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot close egress ports")
+	}
+
+	remaining, err := e.egressRulesInGroup(ctx, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(remaining) > 0 {
+		return nil
+	}
+	_, err = e.ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+		GroupId: g.GroupId,
+		IpPermissions: []types.IpPermission{{
+			IpProtocol: aws.String(allTrafficProtocol),
+			IpRanges:   []types.IpRange{{CidrIp: aws.String(defaultRouteIpv4CIDRBlock)}},
+			Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String(defaultRouteIPv6CIDRBlock)}},
+		}},
+	})
+	if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot unrestrict egress")
+	}
+	return nil
+}
+
+// egressRulesInGroup returns the egress rules added to the named group
+// by Juju. The default rule allowing all outbound traffic is not included.
+func (e *environ) egressRulesInGroup(ctx context.ProviderCallContext, name string) (firewall.EgressRules, error) {
+	group, err := e.groupByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var rules firewall.EgressRules
+	for _, p := range group.IpPermissionsEgress {
+		if aws.ToString(p.IpProtocol) == allTrafficProtocol || len(p.UserIdGroupPairs) > 0 {
+			continue
+		}
+		var destinationCIDRs []string
+		for _, r := range p.IpRanges {
+			destinationCIDRs = append(destinationCIDRs, aws.ToString(r.CidrIp))
+		}
+		for _, r := range p.Ipv6Ranges {
+			destinationCIDRs = append(destinationCIDRs, aws.ToString(r.CidrIpv6))
+		}
+		portRange := network.PortRange{
+			Protocol: aws.ToString(p.IpProtocol),
+			FromPort: int(aws.ToInt32(p.FromPort)),
+			ToPort:   int(aws.ToInt32(p.ToPort)),
+		}
+		rules = append(rules, firewall.NewEgressRule(portRange, destinationCIDRs...))
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules.Sort()
+	return rules, nil
+}
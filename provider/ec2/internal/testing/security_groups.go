@@ -32,6 +32,8 @@ func (srv *Server) CreateSecurityGroup(ctx context.Context, in *ec2.CreateSecuri
 		description: aws.ToString(in.Description),
 		id:          fmt.Sprintf("sg-%d", srv.groupId.next()),
 		perms:       make(map[permKey]bool),
+		// As with EC2, new groups allow all outbound traffic.
+		egressPerms: map[permKey]bool{{protocol: "-1", ipAddr: "0.0.0.0/0"}: true},
 		tags:        tagSpecForType(types.ResourceTypeSecurityGroup, in.TagSpecifications).Tags,
 	}
 	vpcId := aws.ToString(in.VpcId)
@@ -159,14 +161,62 @@ func (srv *Server) RevokeSecurityGroupIngress(ctx context.Context, in *ec2.Revok
 	return &ec2.RevokeSecurityGroupIngressOutput{}, nil
 }
 
+// AuthorizeSecurityGroupEgress implements ec2.Client.
+func (srv *Server) AuthorizeSecurityGroupEgress(ctx context.Context, in *ec2.AuthorizeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupEgressOutput, error) {
+	srv.groupMutatingCalls.next()
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	g := srv.group(types.GroupIdentifier{GroupId: in.GroupId})
+	if g == nil {
+		return nil, apiError("InvalidGroup.NotFound", "group not found")
+	}
+
+	perms, err := srv.parsePerms(in.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range perms {
+		if g.egressPerms[p] {
+			return nil, apiError("InvalidPermission.Duplicate", "Permission has already been authorized on the specified group")
+		}
+	}
+	for _, p := range perms {
+		g.egressPerms[p] = true
+	}
+	return &ec2.AuthorizeSecurityGroupEgressOutput{}, nil
+}
+
+// RevokeSecurityGroupEgress implements ec2.Client.
+func (srv *Server) RevokeSecurityGroupEgress(ctx context.Context, in *ec2.RevokeSecurityGroupEgressInput, opts ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	srv.groupMutatingCalls.next()
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	g := srv.group(types.GroupIdentifier{GroupId: in.GroupId})
+	if g == nil {
+		return nil, apiError("InvalidGroup.NotFound", "group not found")
+	}
+
+	perms, err := srv.parsePerms(in.IpPermissions)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range perms {
+		delete(g.egressPerms, p)
+	}
+	return &ec2.RevokeSecurityGroupEgressOutput{}, nil
+}
+
 type securityGroup struct {
 	id          string
 	name        string
 	description string
 	vpcId       string
 
-	perms map[permKey]bool
-	tags  []types.Tag
+	perms       map[permKey]bool
+	egressPerms map[permKey]bool
+	tags        []types.Tag
 }
 
 // permKey represents permission for a given security group.
@@ -240,15 +290,25 @@ func (g *securityGroup) hasPerm(test func(k permKey) bool) bool {
 	return false
 }
 
-// ec2Perms returns the list of EC2 permissions granted
+// ec2Perms returns the list of EC2 ingress permissions granted
 // to g. It groups permissions by port range and protocol.
 func (g *securityGroup) ec2Perms() (perms []types.IpPermission) {
+	return permsToEC2(g.perms)
+}
+
+// ec2EgressPerms returns the list of EC2 egress permissions granted
+// to g. It groups permissions by port range and protocol.
+func (g *securityGroup) ec2EgressPerms() (perms []types.IpPermission) {
+	return permsToEC2(g.egressPerms)
+}
+
+func permsToEC2(keys map[permKey]bool) (perms []types.IpPermission) {
 	// The grouping is held in result. We use permKey for convenience,
 	// (ensuring that the ipAddr of each key is zero). For each
 	// protocol/port range combination, we build up the permission set
 	// in the associated value.
 	result := make(map[permKey]*types.IpPermission)
-	for k := range g.perms {
+	for k := range keys {
 		groupKey := k
 		groupKey.ipAddr = ""
 
@@ -304,11 +364,12 @@ func (srv *Server) DescribeSecurityGroups(ctx context.Context, in *ec2.DescribeS
 		ok, err := f.ok(group)
 		if ok {
 			resp.SecurityGroups = append(resp.SecurityGroups, types.SecurityGroup{
-				OwnerId:       aws.String(ownerId),
-				GroupId:       aws.String(group.id),
-				GroupName:     aws.String(group.name),
-				Description:   aws.String(group.description),
-				IpPermissions: group.ec2Perms(),
+				OwnerId:             aws.String(ownerId),
+				GroupId:             aws.String(group.id),
+				GroupName:           aws.String(group.name),
+				Description:         aws.String(group.description),
+				IpPermissions:       group.ec2Perms(),
+				IpPermissionsEgress: group.ec2EgressPerms(),
 			})
 		} else if err != nil {
 			return nil, apiError("InvalidParameterValue", "describe security groups: %v", err)
@@ -2931,6 +2931,86 @@ func (t *localServerSuite) TestPorts(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid firewall mode "instance" for retrieving ingress rules from model`)
 }
 
+func (t *localServerSuite) TestEgressPorts(c *gc.C) {
+	t.prepareAndBootstrap(c)
+
+	inst1, _ := testing.AssertStartInstance(c, t.Env, t.ProviderCallContext, t.ControllerUUID, "1")
+	c.Assert(inst1, gc.NotNil)
+	defer func() { _ = t.Env.StopInstances(t.ProviderCallContext, inst1.Id()) }()
+	fwInst1, err := instances.EgressFirewaller(inst1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	allowsAllEgress := func() bool {
+		groupsResp, err := t.client.DescribeSecurityGroups(t.callCtx, &awsec2.DescribeSecurityGroupsInput{
+			GroupNames: []string{"juju-" + t.Env.Config().UUID() + "-1"},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(groupsResp.SecurityGroups, gc.HasLen, 1)
+		for _, p := range groupsResp.SecurityGroups[0].IpPermissionsEgress {
+			if aws.ToString(p.IpProtocol) == "-1" {
+				return true
+			}
+		}
+		return false
+	}
+
+	rules, err := fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+	c.Assert(allowsAllEgress(), jc.IsTrue)
+
+	// Opening egress ports restricts outbound traffic to them.
+	err = fwInst1.OpenEgressPorts(t.ProviderCallContext,
+		"1", firewall.EgressRules{
+			firewall.NewEgressRule(network.MustParsePortRange("443/tcp")),
+			firewall.NewEgressRule(network.MustParsePortRange("5432/tcp"), "10.0.0.0/8"),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), firewall.AllNetworksIPV4CIDR),
+		firewall.NewEgressRule(network.MustParsePortRange("5432/tcp"), "10.0.0.0/8"),
+	})
+	c.Assert(allowsAllEgress(), jc.IsFalse)
+
+	// Opening the same ports again is fine.
+	err = fwInst1.OpenEgressPorts(t.ProviderCallContext,
+		"1", firewall.EgressRules{
+			firewall.NewEgressRule(network.MustParsePortRange("443/tcp")),
+			firewall.NewEgressRule(network.MustParsePortRange("53/udp")),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 3)
+
+	// Closing some ports leaves outbound traffic restricted.
+	err = fwInst1.CloseEgressPorts(t.ProviderCallContext,
+		"1", firewall.EgressRules{
+			firewall.NewEgressRule(network.MustParsePortRange("53/udp")),
+			firewall.NewEgressRule(network.MustParsePortRange("5432/tcp"), "10.0.0.0/8"),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), firewall.AllNetworksIPV4CIDR),
+	})
+	c.Assert(allowsAllEgress(), jc.IsFalse)
+
+	// Closing the last port allows all outbound traffic again.
+	err = fwInst1.CloseEgressPorts(t.ProviderCallContext,
+		"1", firewall.EgressRules{
+			firewall.NewEgressRule(network.MustParsePortRange("443/tcp")),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = fwInst1.EgressRules(t.ProviderCallContext, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+	c.Assert(allowsAllEgress(), jc.IsTrue)
+}
+
 func (t *localServerSuite) TestGlobalPorts(c *gc.C) {
 	t.prepareAndBootstrap(c)
 
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/os/ostype"
+	"github.com/juju/juju/environs"
 )
 
 type EquinixRenderer struct{}
@@ -21,3 +22,10 @@ func (EquinixRenderer) Render(cfg cloudinit.CloudConfig, os ostype.OSType) ([]by
 		return nil, errors.Errorf("Cannot encode userdata for OS: %s", os.String())
 	}
 }
+
+var _ environs.UserDataRenderer = (*environ)(nil)
+
+// UserDataRenderer is part of the environs.UserDataRenderer interface.
+func (*environ) UserDataRenderer() renderers.ProviderRenderer {
+	return EquinixRenderer{}
+}
@@ -4,22 +4,69 @@
 package lxd
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"github.com/juju/utils/v3"
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/environs/config"
 )
 
+const (
+	cfgProject               = "project"
+	cfgProjectPerModel       = "project-per-model"
+	cfgProjectLimitInstances = "project-limits-instances"
+	cfgProjectLimitCPU       = "project-limits-cpu"
+	cfgProjectLimitMemory    = "project-limits-memory"
+	cfgProjectLimitDisk      = "project-limits-disk"
+)
+
 var configSchema = environschema.Fields{
-	"project": {
+	cfgProject: {
 		Description: "The LXD project name to use for Juju's resources.",
 		Type:        environschema.Tstring,
 	},
+	cfgProjectPerModel: {
+		Description: "Whether to create a separate LXD project for the model, " +
+			"so that its instances, images, profiles and storage volumes are isolated " +
+			"from other models. The project is created within the LXD server used " +
+			"for the \"project\" setting, and is deleted when the model is destroyed.",
+		Type:      environschema.Tbool,
+		Immutable: true,
+	},
+	cfgProjectLimitInstances: {
+		Description: "The maximum number of instances in the model's LXD project. " +
+			"Only used with project-per-model; 0 means no limit.",
+		Type: environschema.Tint,
+	},
+	cfgProjectLimitCPU: {
+		Description: "The maximum number of CPUs which may be allocated to instances in " +
+			"the model's LXD project. Only used with project-per-model; 0 means no limit. " +
+			"Instances without a cores constraint are allocated 1 CPU.",
+		Type: environschema.Tint,
+	},
+	cfgProjectLimitMemory: {
+		Description: "The maximum memory which may be allocated to instances in the " +
+			"model's LXD project, for example \"32G\". Only used with project-per-model. " +
+			"Instances without a mem constraint are allocated 1GiB.",
+		Type: environschema.Tstring,
+	},
+	cfgProjectLimitDisk: {
+		Description: "The maximum disk space which may be used by instances and volumes " +
+			"in the model's LXD project, for example \"200G\". Only used with project-per-model.",
+		Type: environschema.Tstring,
+	},
 }
 
 var configDefaults = schema.Defaults{
-	"project": "default",
+	cfgProject:               "default",
+	cfgProjectPerModel:       false,
+	cfgProjectLimitInstances: schema.Omit,
+	cfgProjectLimitCPU:       schema.Omit,
+	cfgProjectLimitMemory:    schema.Omit,
+	cfgProjectLimitDisk:      schema.Omit,
 }
 
 var configFields = func() schema.Fields {
@@ -70,14 +117,87 @@ func (c *environConfig) validate() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	// There are currently no known extra fields for LXD
+	for _, name := range []string{cfgProjectLimitInstances, cfgProjectLimitCPU} {
+		if v, _ := c.attr(name).(int); v < 0 {
+			return errors.NotValidf("%s %d", name, v)
+		}
+	}
+	for _, name := range []string{cfgProjectLimitMemory, cfgProjectLimitDisk} {
+		if _, err := c.sizeMiB(name); err != nil {
+			return errors.Annotatef(err, "invalid %s", name)
+		}
+	}
+	return nil
+}
+
+// validateChange checks that the immutable attributes in c are unchanged
+// from old.
+func (c *environConfig) validateChange(old *environConfig) error {
+	if c.projectPerModel() != old.projectPerModel() {
+		return errors.Errorf("cannot change %s from %v to %v",
+			cfgProjectPerModel, old.projectPerModel(), c.projectPerModel())
+	}
 	return nil
 }
 
+// attr returns the value of the named attribute, coerced to the type in
+// its schema. Nil is returned if the attribute isn't set, or doesn't
+// coerce; the latter is reported by validate.
+func (c *environConfig) attr(name string) interface{} {
+	value, ok := c.attrs[name]
+	if !ok || value == nil {
+		return nil
+	}
+	coerced, err := configFields[name].Coerce(value, []string{name})
+	if err != nil {
+		return nil
+	}
+	return coerced
+}
+
+// sizeMiB returns the named size attribute in MiB, or 0 if it isn't set.
+func (c *environConfig) sizeMiB(name string) (uint64, error) {
+	value, _ := c.attr(name).(string)
+	if value == "" {
+		return 0, nil
+	}
+	return utils.ParseSize(value)
+}
+
 func (c *environConfig) project() string {
-	project := c.attrs["project"]
+	project := c.attrs[cfgProject]
 	if project == nil {
 		return ""
 	}
 	return project.(string)
 }
+
+func (c *environConfig) projectPerModel() bool {
+	perModel, _ := c.attr(cfgProjectPerModel).(bool)
+	return perModel
+}
+
+// projectLimits returns the LXD limits config for the model's project.
+// Limits which aren't set are returned with empty values, so that they
+// are removed from an existing project.
+func (c *environConfig) projectLimits() map[string]string {
+	limits := map[string]string{
+		"limits.instances": "",
+		"limits.cpu":       "",
+		"limits.memory":    "",
+		"limits.disk":      "",
+	}
+	if v, _ := c.attr(cfgProjectLimitInstances).(int); v > 0 {
+		limits["limits.instances"] = fmt.Sprint(v)
+	}
+	if v, _ := c.attr(cfgProjectLimitCPU).(int); v > 0 {
+		limits["limits.cpu"] = fmt.Sprint(v)
+	}
+	if v, _ := c.sizeMiB(cfgProjectLimitMemory); v > 0 {
+		limits["limits.memory"] = fmt.Sprintf("%dMiB", v)
+	}
+	if v, _ := c.sizeMiB(cfgProjectLimitDisk); v > 0 {
+		limits["limits.disk"] = fmt.Sprintf("%dMiB", v)
+	}
+	return limits
+}
@@ -251,6 +251,64 @@ func (s *configSuite) TestSetConfig(c *gc.C) {
 	}
 }
 
+func (s *configSuite) TestValidateProjectLimits(c *gc.C) {
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"project-limits-instances": -1},
+		err:   `invalid base config: project-limits-instances -1 not valid`,
+	}, {
+		attrs: testing.Attrs{"project-limits-cpu": "lots"},
+		err:   `invalid base config: project-limits-cpu: expected number, got string\("lots"\)`,
+	}, {
+		attrs: testing.Attrs{"project-limits-memory": "lots"},
+		err:   `invalid base config: invalid project-limits-memory: .*`,
+	}, {
+		attrs: testing.Attrs{"project-limits-disk": "1X"},
+		err:   `invalid base config: invalid project-limits-disk: .*`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := s.config.Apply(test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = s.provider.Validate(cfg, nil)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *configSuite) TestProjectLimits(c *gc.C) {
+	cfg, err := s.config.Apply(testing.Attrs{
+		"project-per-model":        true,
+		"project-limits-instances": 5,
+		"project-limits-cpu":       "4",
+		"project-limits-memory":    "8G",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.provider.Validate(cfg, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(lxd.NewConfig(cfg).ProjectLimits(), jc.DeepEquals, map[string]string{
+		"limits.instances": "5",
+		"limits.cpu":       "4",
+		"limits.memory":    "8192MiB",
+		"limits.disk":      "",
+	})
+}
+
+func (s *configSuite) TestValidateChangeProjectPerModel(c *gc.C) {
+	cfg, err := s.config.Apply(testing.Attrs{"project-per-model": true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.provider.Validate(cfg, s.config)
+	c.Assert(err, gc.ErrorMatches, "invalid config change: cannot change project-per-model from false to true")
+
+	// Limits may be changed.
+	newCfg, err := cfg.Apply(testing.Attrs{"project-limits-cpu": 2})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.provider.Validate(newCfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *configSuite) TestSchema(c *gc.C) {
 	fields := s.provider.(interface {
 		Schema() environschema.Fields
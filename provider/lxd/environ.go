@@ -7,6 +7,7 @@ import (
 	stdcontext "context"
 	"net"
 	"net/url"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -87,7 +88,11 @@ func newEnviron(
 	}
 	env.base = common.DefaultProvider{Env: env}
 
-	err = env.SetCloudSpec(stdcontext.TODO(), spec)
+	// The model's project is created by Create or Bootstrap, which are
+	// called on the opened environ.
+	env.lock.Lock()
+	err = env.setCloudSpec(spec, true)
+	env.lock.Unlock()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -154,8 +159,20 @@ func (env *environ) SetConfig(cfg *config.Config) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	old := env.ecfgUnlocked
 	env.ecfgUnlocked = ecfg
-	return nil
+
+	// Apply any change to the limits of the model's project. The server
+	// is nil if the cloud spec hasn't been set yet, in which case the
+	// limits are set when the project is created.
+	if env.serverUnlocked == nil || !ecfg.projectPerModel() {
+		return nil
+	}
+	limits := ecfg.projectLimits()
+	if reflect.DeepEqual(limits, old.projectLimits()) {
+		return nil
+	}
+	return errors.Trace(env.updateModelProject(env.serverUnlocked, limits))
 }
 
 // SetCloudSpec is specified in the environs.Environ interface.
@@ -163,16 +180,46 @@ func (env *environ) SetCloudSpec(_ stdcontext.Context, spec environscloudspec.Cl
 	env.lock.Lock()
 	defer env.lock.Unlock()
 
+	return env.setCloudSpec(spec, false)
+}
+
+// setCloudSpec connects the environ to the LXD server in the input spec.
+// If the model has its own project, it must exist unless
+// allowMissingProject is set.
+// The caller must hold env.lock.
+func (env *environ) setCloudSpec(spec environscloudspec.CloudSpec, allowMissingProject bool) error {
 	serverFactory := env.provider.serverFactory
 	server, err := serverFactory.RemoteServer(CloudSpec{CloudSpec: spec, Project: env.ecfgUnlocked.project()})
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if env.ecfgUnlocked.projectPerModel() {
+		err := env.useModelProject(server)
+		if allowMissingProject && errors.Is(err, errors.NotFound) {
+			// Use the missing project regardless, so that the environ
+			// can't act on instances in the configured project.
+			logger.Debugf("%v, waiting for it to be created", err)
+			server.UseProject(env.modelProjectName())
+			env.cloud = spec
+			env.serverUnlocked = server
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "using LXD project for model")
+		}
+	}
 
+	env.cloud = spec
 	env.serverUnlocked = server
 	return env.initProfile()
 }
 
+func (env *environ) ecfg() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	return env.ecfgUnlocked
+}
+
 func (env *environ) server() Server {
 	env.lock.Lock()
 	defer env.lock.Unlock()
@@ -207,19 +254,44 @@ func (env *environ) PrepareForBootstrap(_ environs.BootstrapContext, _ string) e
 }
 
 // Create implements environs.Environ.
-func (env *environ) Create(context.ProviderCallContext, environs.CreateParams) error {
+func (env *environ) Create(ctx context.ProviderCallContext, params environs.CreateParams) error {
+	if !env.ecfg().projectPerModel() {
+		return nil
+	}
+	if err := env.createModelProject(params.ControllerUUID); err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return errors.Trace(err)
+	}
 	return nil
 }
 
 // Bootstrap implements environs.Environ.
 func (env *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.ProviderCallContext, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
 	ctx.Infof("%s", bootstrapMessage)
+	if env.ecfg().projectPerModel() {
+		if err := env.createModelProject(params.ControllerConfig.ControllerUUID()); err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, callCtx)
+			return nil, errors.Trace(err)
+		}
+	}
 	return env.base.BootstrapEnv(ctx, callCtx, params)
 }
 
 // Destroy shuts down all known machines and destroys the rest of the
 // known environment.
 func (env *environ) Destroy(ctx context.ProviderCallContext) error {
+	if env.ecfg().projectPerModel() {
+		// Nothing can have been created for the model if its project
+		// was never created.
+		hasProject, err := env.server().HasProject(env.modelProjectName())
+		if err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return errors.Trace(err)
+		}
+		if !hasProject {
+			return nil
+		}
+	}
 	if err := env.base.DestroyEnv(ctx); err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return errors.Trace(err)
@@ -230,6 +302,12 @@ func (env *environ) Destroy(ctx context.ProviderCallContext) error {
 			return errors.Annotate(err, "destroying LXD filesystems for model")
 		}
 	}
+	if env.ecfg().projectPerModel() {
+		if err := env.destroyModelProject(); err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return errors.Annotate(err, "destroying LXD project for model")
+		}
+	}
 	return nil
 }
 
@@ -243,7 +321,7 @@ func (env *environ) DestroyController(ctx context.ProviderCallContext, controlle
 		return errors.Trace(err)
 	}
 	if env.storageSupported() {
-		if err := destroyControllerFilesystems(env, controllerUUID); err != nil {
+		if err := destroyControllerFilesystems(env.server(), controllerUUID); err != nil {
 			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 			return errors.Annotate(err, "destroying LXD filesystems for controller")
 		}
@@ -252,27 +330,57 @@ func (env *environ) DestroyController(ctx context.ProviderCallContext, controlle
 }
 
 func (env *environ) destroyHostedModelResources(controllerUUID string) error {
+	if err := removeHostedModelInstances(env.server(), env.uuid, controllerUUID); err != nil {
+		return errors.Trace(err)
+	}
+
+	// If the controller model has its own project, hosted models without
+	// one have their instances in the project from the model config.
+	if ecfg := env.ecfg(); ecfg.projectPerModel() {
+		server, err := env.provider.serverFactory.RemoteServer(CloudSpec{
+			CloudSpec: env.cloud,
+			Project:   ecfg.project(),
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := removeHostedModelInstances(server, env.uuid, controllerUUID); err != nil {
+			return errors.Trace(err)
+		}
+		if server.StorageSupported() {
+			if err := destroyControllerFilesystems(server, controllerUUID); err != nil {
+				return errors.Annotate(err, "destroying LXD filesystems for controller")
+			}
+		}
+	}
+	return errors.Trace(env.destroyHostedModelProjects(controllerUUID))
+}
+
+// removeHostedModelInstances removes the instances managed by the
+// controller with the input UUID, other than those in the model with
+// the input UUID.
+func removeHostedModelInstances(server Server, modelUUID, controllerUUID string) error {
 	// Destroy all instances with juju-controller-uuid
 	// matching the specified UUID.
 	const prefix = "juju-"
-	instances, err := env.prefixedInstances(prefix)
+	containers, err := server.AliveContainers(prefix)
 	if err != nil {
 		return errors.Annotate(err, "listing instances")
 	}
 
 	var names []string
-	for _, inst := range instances {
-		if inst.container.Metadata(tags.JujuModel) == env.uuid {
+	for _, c := range containers {
+		if c.Metadata(tags.JujuModel) == modelUUID {
 			continue
 		}
-		if inst.container.Metadata(tags.JujuController) != controllerUUID {
+		if c.Metadata(tags.JujuController) != controllerUUID {
 			continue
 		}
-		names = append(names, string(inst.Id()))
+		names = append(names, c.Name)
 	}
 	logger.Debugf("removing instances: %v", names)
 
-	return errors.Trace(env.server().RemoveContainers(names))
+	return errors.Trace(server.RemoveContainers(names))
 }
 
 // lxdAvailabilityZone wraps a LXD cluster member as an availability zone.
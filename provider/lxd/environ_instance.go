@@ -70,7 +70,10 @@ func findInst(id instance.Id, instances []*environInstance) instances.Instance {
 // instances returns a list of all "alive" instances in the environment.
 // We match machine names to the pattern "juju-<model-UUID>-machine-*"
 // to ensure that only machines for the environment are returned. This
-// is necessary to isolate multiple models within the same LXD.
+// is necessary to isolate multiple models within the same LXD project.
+// When project-per-model is set, the server is already scoped to the
+// model's own project, but the prefix is still matched so that any
+// instances created there by hand are ignored.
 func (env *environ) allInstances() ([]*environInstance, error) {
 	prefix := env.namespace.Prefix()
 	insts, err := env.prefixedInstances(prefix)
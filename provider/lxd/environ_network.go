@@ -303,9 +303,11 @@ func makeInterfaceInfo(container *lxdapi.Instance, guestNetworkName string, netI
 	}
 
 	// Iterate the list of addresses assigned to this interface ignoring
-	// any link-local ones. The first non link-local address is treated as
-	// the primary address and is used to populate the interface CIDR and
-	// subnet ID fields.
+	// any link-local ones. Each address is associated with the subnet
+	// that it is in, so that both families on a dual-stack interface can
+	// be matched to their spaces. The first non link-local address is
+	// treated as the primary address and is used to populate the
+	// interface subnet ID field.
 	for _, addr := range netInfo.Addresses {
 		netAddr := network.NewMachineAddress(addr.Address).AsProviderAddress()
 		if netAddr.Scope == network.ScopeLinkLocal || netAddr.Scope == network.ScopeMachineLocal {
@@ -321,11 +323,10 @@ func makeInterfaceInfo(container *lxdapi.Instance, guestNetworkName string, netI
 
 		netAddr.CIDR = cidr
 		netAddr.ConfigType = configType
+		netAddr.ProviderSubnetID = network.Id(subnetID)
 		ni.Addresses = append(ni.Addresses, netAddr)
 
-		// Only set provider IDs based on the first address.
-		// TODO (manadart 2021-03-24): We should associate the provider ID for
-		// the subnet with the address.
+		// Only set interface provider IDs based on the first address.
 		if len(ni.Addresses) > 1 {
 			continue
 		}
@@ -139,6 +139,67 @@ func (s *environNetSuite) TestSubnetsForKnownContainer(c *gc.C) {
 	c.Assert(subnets, gc.DeepEquals, expSubnets)
 }
 
+func (s *environNetSuite) TestSubnetsDualStack(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	srv := lxd.NewMockServer(ctrl)
+	srv.EXPECT().IsClustered().Return(false)
+	srv.EXPECT().Name().Return("locutus")
+	srv.EXPECT().GetNetworks().Return([]lxdapi.Network{
+		{
+			Name: "lxdbr0",
+			Type: "bridge",
+		},
+	}, nil)
+	srv.EXPECT().GetNetworkState("lxdbr0").Return(&lxdapi.NetworkState{
+		Type:  "broadcast",
+		State: "up",
+		Addresses: []lxdapi.NetworkStateAddress{
+			{
+				Family:  "inet",
+				Address: "10.55.158.1",
+				Netmask: "24",
+				Scope:   "global",
+			},
+			{
+				Family:  "inet6",
+				Address: "fd42:9b2b:7e65:1a5c::1",
+				Netmask: "64",
+				Scope:   "global",
+			},
+			{
+				Family:  "inet6",
+				Address: "fe80::c876:d1ff:fe9c:fa46",
+				Netmask: "64",
+				Scope:   "link",
+			},
+		},
+	}, nil)
+
+	env := s.NewEnviron(c, srv, nil, environscloudspec.CloudSpec{}).(environs.Networking)
+
+	ctx := context.NewEmptyCloudCallContext()
+	subnets, err := env.Subnets(ctx, instance.UnknownId, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expSubnets := []network.SubnetInfo{
+		{
+			CIDR:              "10.55.158.0/24",
+			ProviderId:        "subnet-lxdbr0-10.55.158.0/24",
+			ProviderNetworkId: "net-lxdbr0",
+			AvailabilityZones: []string{"locutus"},
+		},
+		{
+			CIDR:              "fd42:9b2b:7e65:1a5c::/64",
+			ProviderId:        "subnet-lxdbr0-fd42:9b2b:7e65:1a5c::/64",
+			ProviderNetworkId: "net-lxdbr0",
+			AvailabilityZones: []string{"locutus"},
+		},
+	}
+	c.Assert(subnets, gc.DeepEquals, expSubnets)
+}
+
 func (s *environNetSuite) TestSubnetsForKnownContainerAndClustered(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -472,9 +533,12 @@ func (s *environNetSuite) TestNetworkInterfaces(c *gc.C) {
 				ProviderId:          "nic-00:16:3e:19:29:cb",
 				ProviderSubnetId:    "subnet-lxdbr0-10.55.158.0/24",
 				ProviderNetworkId:   "net-lxdbr0",
-				Addresses: network.ProviderAddresses{network.NewMachineAddress(
-					"10.55.158.99", network.WithCIDR("10.55.158.0/24"), network.WithConfigType(network.ConfigStatic),
-				).AsProviderAddress()},
+				Addresses: network.ProviderAddresses{{
+					MachineAddress: network.NewMachineAddress(
+						"10.55.158.99", network.WithCIDR("10.55.158.0/24"), network.WithConfigType(network.ConfigStatic),
+					),
+					ProviderSubnetID: "subnet-lxdbr0-10.55.158.0/24",
+				}},
 			},
 			{
 				DeviceIndex:         1,
@@ -487,15 +551,89 @@ func (s *environNetSuite) TestNetworkInterfaces(c *gc.C) {
 				ProviderId:          "nic-00:16:3e:fe:fe:fe",
 				ProviderSubnetId:    "subnet-ovsbr0-10.42.42.0/24",
 				ProviderNetworkId:   "net-ovsbr0",
-				Addresses: network.ProviderAddresses{network.NewMachineAddress(
-					"10.42.42.99", network.WithCIDR("10.42.42.0/24"), network.WithConfigType(network.ConfigStatic),
-				).AsProviderAddress()},
+				Addresses: network.ProviderAddresses{{
+					MachineAddress: network.NewMachineAddress(
+						"10.42.42.99", network.WithCIDR("10.42.42.0/24"), network.WithConfigType(network.ConfigStatic),
+					),
+					ProviderSubnetID: "subnet-ovsbr0-10.42.42.0/24",
+				}},
 			},
 		},
 	}
 	c.Assert(infos, gc.DeepEquals, expInfos)
 }
 
+func (s *environNetSuite) TestNetworkInterfacesDualStack(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	srv := lxd.NewMockServer(ctrl)
+	srv.EXPECT().GetInstance("woot").Return(&lxdapi.Instance{
+		ExpandedDevices: map[string]map[string]string{
+			"eth0": {
+				"name":    "eth0",
+				"network": "lxdbr0",
+				"type":    "nic",
+			},
+		},
+	}, "etag", nil)
+	srv.EXPECT().GetInstanceState("woot").Return(&lxdapi.InstanceState{
+		Network: map[string]lxdapi.InstanceStateNetwork{
+			"eth0": {
+				Type:   "broadcast",
+				State:  "up",
+				Mtu:    1500,
+				Hwaddr: "00:16:3e:19:29:cb",
+				Addresses: []lxdapi.InstanceStateNetworkAddress{
+					{
+						Family:  "inet",
+						Address: "10.55.158.99",
+						Netmask: "24",
+						Scope:   "global",
+					},
+					{
+						Family:  "inet6",
+						Address: "fd42:9b2b:7e65:1a5c:216:3eff:fe19:29cb",
+						Netmask: "64",
+						Scope:   "global",
+					},
+					{
+						Family:  "inet6",
+						Address: "fe80::216:3eff:fe19:29cb",
+						Netmask: "64",
+						Scope:   "link",
+					},
+				},
+			},
+		},
+	}, "etag", nil)
+
+	env := s.NewEnviron(c, srv, nil, environscloudspec.CloudSpec{}).(environs.Networking)
+
+	ctx := context.NewEmptyCloudCallContext()
+	infos, err := env.NetworkInterfaces(ctx, []instance.Id{"woot"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 1)
+	c.Assert(infos[0], gc.HasLen, 1)
+
+	// The interface takes its subnet from the primary address,
+	// but each address is associated with its own subnet.
+	nic := infos[0][0]
+	c.Check(nic.ProviderSubnetId, gc.Equals, network.Id("subnet-lxdbr0-10.55.158.0/24"))
+	c.Check(nic.Addresses, gc.DeepEquals, network.ProviderAddresses{{
+		MachineAddress: network.NewMachineAddress(
+			"10.55.158.99", network.WithCIDR("10.55.158.0/24"), network.WithConfigType(network.ConfigStatic),
+		),
+		ProviderSubnetID: "subnet-lxdbr0-10.55.158.0/24",
+	}, {
+		MachineAddress: network.NewMachineAddress(
+			"fd42:9b2b:7e65:1a5c:216:3eff:fe19:29cb",
+			network.WithCIDR("fd42:9b2b:7e65:1a5c::/64"), network.WithConfigType(network.ConfigStatic),
+		),
+		ProviderSubnetID: "subnet-lxdbr0-fd42:9b2b:7e65:1a5c::/64",
+	}})
+}
+
 func (s *environNetSuite) TestNetworkInterfacesPartialResults(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -548,9 +686,12 @@ func (s *environNetSuite) TestNetworkInterfacesPartialResults(c *gc.C) {
 				ProviderId:          "nic-00:16:3e:19:29:cb",
 				ProviderSubnetId:    "subnet-lxdbr0-10.55.158.0/24",
 				ProviderNetworkId:   "net-lxdbr0",
-				Addresses: network.ProviderAddresses{network.NewMachineAddress(
-					"10.55.158.99", network.WithCIDR("10.55.158.0/24"), network.WithConfigType(network.ConfigStatic),
-				).AsProviderAddress()},
+				Addresses: network.ProviderAddresses{{
+					MachineAddress: network.NewMachineAddress(
+						"10.55.158.99", network.WithCIDR("10.55.158.0/24"), network.WithConfigType(network.ConfigStatic),
+					),
+					ProviderSubnetID: "subnet-lxdbr0-10.55.158.0/24",
+				}},
 			},
 		},
 		nil, // slot for second instance is nil as the container was not found
@@ -0,0 +1,244 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/environs/tags"
+)
+
+// projectFeatures is the config for projects created for models.
+// Instances, images, profiles and storage volumes are confined to the
+// model's project, but networks are shared with the default project so
+// that instances can use the existing bridges.
+var projectFeatures = map[string]string{
+	"features.images":          "true",
+	"features.profiles":        "true",
+	"features.storage.volumes": "true",
+	"features.networks":        "false",
+}
+
+// LXD requires instances in a project with CPU or memory limits to
+// declare their own limits. Instances with cores or mem constraints have
+// them set in their config; these defaults are set on the project's
+// default profile for the others.
+const (
+	defaultInstanceCPULimit    = "1"
+	defaultInstanceMemoryLimit = "1GiB"
+)
+
+// modelProjectName returns the name of the LXD project used by the model
+// when project-per-model is set.
+func (env *environ) modelProjectName() string {
+	return env.namespace.Value(env.name)
+}
+
+// useModelProject switches the server to use the model's LXD project.
+// A NotFound error is returned if the project doesn't exist.
+// The caller must hold env.lock.
+func (env *environ) useModelProject(server Server) error {
+	if !server.HasExtension("projects") {
+		return errors.NotSupportedf("project-per-model with an LXD server without projects")
+	}
+
+	name := env.modelProjectName()
+	hasProject, err := server.HasProject(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !hasProject {
+		return errors.NotFoundf("LXD project %q for model %q", name, env.name)
+	}
+	server.UseProject(name)
+	return nil
+}
+
+// createModelProject creates the model's LXD project, if it doesn't
+// already exist, records the controller managing the model on it and
+// switches the environ to use it. The controller is recorded so that
+// the project can be found and removed if the controller is destroyed
+// without first destroying the model.
+func (env *environ) createModelProject(controllerUUID string) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	// The environ's server uses the model's project from when it was
+	// opened, but the project's default profile is copied from the
+	// configured project.
+	server, err := env.provider.serverFactory.RemoteServer(CloudSpec{
+		CloudSpec: env.cloud,
+		Project:   env.ecfgUnlocked.project(),
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := env.ensureModelProject(server); err != nil {
+		return errors.Annotate(err, "setting up LXD project for model")
+	}
+	env.serverUnlocked = server
+	if err := env.initProfile(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(env.updateModelProject(server, map[string]string{
+		lxd.UserNamespacePrefix + tags.JujuController: controllerUUID,
+	}))
+}
+
+// ensureModelProject creates the model's LXD project, if it doesn't
+// already exist, and switches the server to use it. The server must be
+// using the project from the model config, whose default profile is
+// copied to the new project.
+// The caller must hold env.lock.
+func (env *environ) ensureModelProject(server Server) error {
+	err := env.useModelProject(server)
+	if !errors.Is(err, errors.NotFound) {
+		return errors.Trace(err)
+	}
+	name := env.modelProjectName()
+
+	profile, _, err := server.GetProfile("default")
+	if err != nil {
+		return errors.Annotate(err, "reading default profile")
+	}
+
+	cfg := map[string]string{
+		lxd.UserNamespacePrefix + tags.JujuModel: env.ecfgUnlocked.UUID(),
+	}
+	for k, v := range projectFeatures {
+		cfg[k] = v
+	}
+	for k, v := range env.ecfgUnlocked.projectLimits() {
+		if v != "" {
+			cfg[k] = v
+		}
+	}
+
+	logger.Debugf("creating LXD project %q for model %q", name, env.name)
+	description := fmt.Sprintf("Juju model %q", env.name)
+	if err := server.CreateProjectWithConfig(name, description, cfg); err != nil {
+		// As with profiles in initProfile, another environ for the
+		// same model may have created the project concurrently.
+		hasProject, hasErr := server.HasProject(name)
+		if hasErr != nil {
+			logger.Errorf("%s", err)
+			return errors.Trace(hasErr)
+		}
+		if !hasProject {
+			return errors.Annotatef(err, "creating LXD project %q", name)
+		}
+		server.UseProject(name)
+		return nil
+	}
+	server.UseProject(name)
+
+	// The default profile of a new project is empty. Copy the devices
+	// and config from the original project so that instances get the
+	// same root disk and network devices.
+	put := profile.Writable()
+	put.Config = make(map[string]string, len(profile.Config))
+	for k, v := range profile.Config {
+		put.Config[k] = v
+	}
+	if _, ok := put.Config["limits.cpu"]; !ok && cfg["limits.cpu"] != "" {
+		put.Config["limits.cpu"] = defaultInstanceCPULimit
+	}
+	if _, ok := put.Config["limits.memory"]; !ok && cfg["limits.memory"] != "" {
+		put.Config["limits.memory"] = defaultInstanceMemoryLimit
+	}
+	if err := server.UpdateProfile("default", put, ""); err != nil {
+		return errors.Annotatef(err, "updating default profile in LXD project %q", name)
+	}
+	return nil
+}
+
+// updateModelProject sets the input config on the model's LXD project.
+// Keys with empty values are removed.
+func (env *environ) updateModelProject(server Server, cfg map[string]string) error {
+	name := env.modelProjectName()
+	project, eTag, err := server.GetProject(name)
+	if err != nil {
+		return errors.Annotatef(err, "reading LXD project %q", name)
+	}
+
+	put := project.Writable()
+	newConfig := make(map[string]string, len(put.Config))
+	for k, v := range put.Config {
+		newConfig[k] = v
+	}
+	changed := false
+	for k, v := range cfg {
+		if newConfig[k] == v {
+			continue
+		}
+		if v == "" {
+			delete(newConfig, k)
+		} else {
+			newConfig[k] = v
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	put.Config = newConfig
+	return errors.Annotatef(server.UpdateProject(name, put, eTag), "updating LXD project %q", name)
+}
+
+// destroyModelProject removes the model's LXD project. Instances and
+// storage volumes must already have been removed.
+func (env *environ) destroyModelProject() error {
+	server := env.server()
+	if err := server.EmptyProject(); err != nil {
+		return errors.Trace(err)
+	}
+	name := env.modelProjectName()
+	logger.Debugf("deleting LXD project %q", name)
+	return errors.Annotatef(server.DeleteProject(name), "deleting LXD project %q", name)
+}
+
+// destroyHostedModelProjects removes the LXD projects, along with their
+// instances and storage volumes, of models managed by the controller
+// other than this one.
+func (env *environ) destroyHostedModelProjects(controllerUUID string) error {
+	projects, err := env.server().GetProjects()
+	if err != nil {
+		return errors.Annotate(err, "listing LXD projects")
+	}
+	for _, project := range projects {
+		if project.Config[lxd.UserNamespacePrefix+tags.JujuController] != controllerUUID {
+			continue
+		}
+		if project.Config[lxd.UserNamespacePrefix+tags.JujuModel] == env.uuid {
+			continue
+		}
+
+		server, err := env.provider.serverFactory.RemoteServer(CloudSpec{
+			CloudSpec: env.cloud,
+			Project:   project.Name,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := removeHostedModelInstances(server, "", controllerUUID); err != nil {
+			return errors.Trace(err)
+		}
+		if server.StorageSupported() {
+			if err := destroyControllerFilesystems(server, controllerUUID); err != nil {
+				return errors.Annotatef(err, "destroying LXD filesystems in project %q", project.Name)
+			}
+		}
+		if err := server.EmptyProject(); err != nil {
+			return errors.Trace(err)
+		}
+		logger.Debugf("deleting LXD project %q", project.Name)
+		if err := server.DeleteProject(project.Name); err != nil {
+			return errors.Annotatef(err, "deleting LXD project %q", project.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	stdcontext "context"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	containerlxd "github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/environs"
+	envcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/lxd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const (
+	modelUUID    = "2d02eeac-9dbb-11e4-89d3-123b93f75cba"
+	modelProject = "juju-f75cba-controller"
+)
+
+type environProjectSuite struct {
+	lxd.EnvironSuite
+
+	svr        *lxd.MockServer
+	svrFactory *lxd.MockServerFactory
+	env        environs.Environ
+	callCtx    envcontext.ProviderCallContext
+}
+
+var _ = gc.Suite(&environProjectSuite{})
+
+func (s *environProjectSuite) SetUpTest(c *gc.C) {
+	s.EnvironSuite.SetUpTest(c)
+	s.callCtx = envcontext.NewEmptyCloudCallContext()
+}
+
+func (s *environProjectSuite) TestCreateCreatesModelProject(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{
+		"project-per-model":        true,
+		"project-limits-instances": 10,
+		"project-limits-memory":    "16G",
+	}).Finish()
+
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(false, nil),
+		exp.UseProject(modelProject),
+	)
+	err := lxd.SetCloudSpecOnOpen(s.env, lxdCloudSpec())
+	c.Assert(err, jc.ErrorIsNil)
+
+	gomock.InOrder(
+		s.svrFactory.EXPECT().RemoteServer(lxd.CloudSpec{
+			CloudSpec: lxdCloudSpec(),
+			Project:   "default",
+		}).Return(s.svr, nil),
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(false, nil),
+		exp.GetProfile("default").Return(&api.Profile{
+			ProfilePut: api.ProfilePut{
+				Config: map[string]string{"security.nesting": "true"},
+				Devices: map[string]map[string]string{
+					"root": {"type": "disk", "path": "/", "pool": "default"},
+				},
+			},
+		}, "etag", nil),
+		exp.CreateProjectWithConfig(modelProject, `Juju model "controller"`, map[string]string{
+			"user.juju-model-uuid":     modelUUID,
+			"features.images":          "true",
+			"features.profiles":        "true",
+			"features.storage.volumes": "true",
+			"features.networks":        "false",
+			"limits.instances":         "10",
+			"limits.memory":            "16384MiB",
+		}).Return(nil),
+		exp.UseProject(modelProject),
+		exp.UpdateProfile("default", api.ProfilePut{
+			Config: map[string]string{
+				"security.nesting": "true",
+				"limits.memory":    "1GiB",
+			},
+			Devices: map[string]map[string]string{
+				"root": {"type": "disk", "path": "/", "pool": "default"},
+			},
+		}, "").Return(nil),
+		exp.HasProfile("juju-controller").Return(true, nil),
+		exp.GetProject(modelProject).Return(&api.Project{
+			ProjectPut: api.ProjectPut{
+				Config: map[string]string{"user.juju-model-uuid": modelUUID},
+			},
+			Name: modelProject,
+		}, "etag", nil),
+		exp.UpdateProject(modelProject, api.ProjectPut{
+			Config: map[string]string{
+				"user.juju-model-uuid":      modelUUID,
+				"user.juju-controller-uuid": coretesting.ControllerTag.Id(),
+			},
+		}, "etag").Return(nil),
+	)
+
+	err = s.env.Create(s.callCtx, environs.CreateParams{ControllerUUID: coretesting.ControllerTag.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestSetCloudSpecUsesExistingModelProject(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	s.expectExistingProject()
+
+	err := s.env.(environs.CloudSpecSetter).SetCloudSpec(stdcontext.TODO(), lxdCloudSpec())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestSetCloudSpecModelProjectMissing(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(false, nil),
+	)
+
+	err := s.env.(environs.CloudSpecSetter).SetCloudSpec(stdcontext.TODO(), lxdCloudSpec())
+	c.Assert(err, gc.ErrorMatches, `using LXD project for model: LXD project "juju-f75cba-controller" for model "controller" not found`)
+	c.Assert(err, jc.ErrorIs, errors.NotFound)
+}
+
+func (s *environProjectSuite) TestSetCloudSpecProjectsNotSupported(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	s.svr.EXPECT().HasExtension("projects").Return(false)
+
+	err := s.env.(environs.CloudSpecSetter).SetCloudSpec(stdcontext.TODO(), lxdCloudSpec())
+	c.Assert(err, gc.ErrorMatches, "using LXD project for model: project-per-model with an LXD server without projects not supported")
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+}
+
+func (s *environProjectSuite) TestCreateSetsProjectController(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	s.expectExistingProject()
+	s.setCloudSpec(c)
+
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		s.svrFactory.EXPECT().RemoteServer(lxd.CloudSpec{
+			CloudSpec: lxdCloudSpec(),
+			Project:   "default",
+		}).Return(s.svr, nil),
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(true, nil),
+		exp.UseProject(modelProject),
+		exp.HasProfile("juju-controller").Return(true, nil),
+		exp.GetProject(modelProject).Return(&api.Project{
+			ProjectPut: api.ProjectPut{
+				Config: map[string]string{"user.juju-model-uuid": modelUUID},
+			},
+			Name: modelProject,
+		}, "etag", nil),
+		exp.UpdateProject(modelProject, api.ProjectPut{
+			Config: map[string]string{
+				"user.juju-model-uuid":      modelUUID,
+				"user.juju-controller-uuid": coretesting.ControllerTag.Id(),
+			},
+		}, "etag").Return(nil),
+	)
+
+	err := s.env.Create(s.callCtx, environs.CreateParams{ControllerUUID: coretesting.ControllerTag.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestSetConfigUpdatesProjectLimits(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{
+		"project-per-model":  true,
+		"project-limits-cpu": 8,
+	}).Finish()
+	s.expectExistingProject()
+	s.setCloudSpec(c)
+
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.GetProject(modelProject).Return(&api.Project{
+			ProjectPut: api.ProjectPut{
+				Config: map[string]string{"limits.cpu": "8"},
+			},
+			Name: modelProject,
+		}, "etag", nil),
+		exp.UpdateProject(modelProject, api.ProjectPut{
+			Config: map[string]string{"limits.disk": "102400MiB"},
+		}, "etag").Return(nil),
+	)
+
+	cfg, err := s.env.Config().Apply(map[string]interface{}{
+		"project-limits-cpu":  0,
+		"project-limits-disk": "100G",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Setting the same limits again doesn't update the project.
+	err = s.env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestDestroyDeletesModelProject(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	s.expectExistingProject()
+	s.setCloudSpec(c)
+
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.HasProject(modelProject).Return(true, nil),
+		exp.StorageSupported().Return(false),
+		exp.EmptyProject().Return(nil),
+		exp.DeleteProject(modelProject).Return(nil),
+	)
+
+	err := s.env.Destroy(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestDestroyWithoutModelProject(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(false, nil),
+		exp.UseProject(modelProject),
+		exp.HasProject(modelProject).Return(false, nil),
+	)
+	err := lxd.SetCloudSpecOnOpen(s.env, lxdCloudSpec())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.env.Destroy(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) TestDestroyControllerDeletesHostedModelProjects(c *gc.C) {
+	defer s.setup(c, map[string]interface{}{"project-per-model": true}).Finish()
+	s.expectExistingProject()
+	s.setCloudSpec(c)
+
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	baseSvr := lxd.NewMockServer(ctrl)
+	hostedSvr := lxd.NewMockServer(ctrl)
+
+	controllerUUID := coretesting.ControllerTag.Id()
+	hostedMachine := newContainer("juju-aaaaaa-0", "hosted-model", controllerUUID)
+	otherMachine := newContainer("juju-bbbbbb-0", "other-model", "other-controller")
+	hostedProject := "juju-aaaaaa-hosted"
+
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		// Destroy the controller model and its project.
+		exp.HasProject(modelProject).Return(true, nil),
+		exp.StorageSupported().Return(false),
+		exp.EmptyProject().Return(nil),
+		exp.DeleteProject(modelProject).Return(nil),
+		exp.AliveContainers("juju-").Return(nil, nil),
+		exp.RemoveContainers(nil).Return(nil),
+
+		// Remove hosted model instances from the configured project.
+		s.svrFactory.EXPECT().RemoteServer(lxd.CloudSpec{
+			CloudSpec: lxdCloudSpec(),
+			Project:   "default",
+		}).Return(baseSvr, nil),
+		baseSvr.EXPECT().AliveContainers("juju-").Return([]containerlxd.Container{hostedMachine, otherMachine}, nil),
+		baseSvr.EXPECT().RemoveContainers([]string{"juju-aaaaaa-0"}).Return(nil),
+		baseSvr.EXPECT().StorageSupported().Return(false),
+
+		// Remove the projects of hosted models.
+		exp.GetProjects().Return([]api.Project{{
+			Name: modelProject,
+			ProjectPut: api.ProjectPut{Config: map[string]string{
+				"user.juju-model-uuid":      modelUUID,
+				"user.juju-controller-uuid": controllerUUID,
+			}},
+		}, {
+			Name: hostedProject,
+			ProjectPut: api.ProjectPut{Config: map[string]string{
+				"user.juju-model-uuid":      "hosted-model",
+				"user.juju-controller-uuid": controllerUUID,
+			}},
+		}, {
+			Name: "juju-bbbbbb-other",
+			ProjectPut: api.ProjectPut{Config: map[string]string{
+				"user.juju-model-uuid":      "other-model",
+				"user.juju-controller-uuid": "other-controller",
+			}},
+		}, {
+			Name: "default",
+		}}, nil),
+		s.svrFactory.EXPECT().RemoteServer(lxd.CloudSpec{
+			CloudSpec: lxdCloudSpec(),
+			Project:   hostedProject,
+		}).Return(hostedSvr, nil),
+		hostedSvr.EXPECT().AliveContainers("juju-").Return([]containerlxd.Container{hostedMachine}, nil),
+		hostedSvr.EXPECT().RemoveContainers([]string{"juju-aaaaaa-0"}).Return(nil),
+		hostedSvr.EXPECT().StorageSupported().Return(false),
+		hostedSvr.EXPECT().EmptyProject().Return(nil),
+		hostedSvr.EXPECT().DeleteProject(hostedProject).Return(nil),
+
+		exp.StorageSupported().Return(false),
+	)
+
+	err := s.env.DestroyController(s.callCtx, controllerUUID)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) setup(c *gc.C, cfgEdit map[string]interface{}) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.svr = lxd.NewMockServer(ctrl)
+	s.svrFactory = lxd.NewMockServerFactory(ctrl)
+	s.svrFactory.EXPECT().RemoteServer(lxd.CloudSpec{
+		CloudSpec: lxdCloudSpec(),
+		Project:   "default",
+	}).Return(s.svr, nil)
+
+	cfgEdit["project"] = "default"
+	s.env = s.NewEnvironWithServerFactory(c, s.svrFactory, cfgEdit)
+	return ctrl
+}
+
+func (s *environProjectSuite) setCloudSpec(c *gc.C) {
+	err := s.env.(environs.CloudSpecSetter).SetCloudSpec(stdcontext.TODO(), lxdCloudSpec())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environProjectSuite) expectExistingProject() {
+	exp := s.svr.EXPECT()
+	gomock.InOrder(
+		exp.HasExtension("projects").Return(true),
+		exp.HasProject(modelProject).Return(true, nil),
+		exp.UseProject(modelProject),
+		exp.HasProfile("juju-controller").Return(true, nil),
+	)
+}
+
+func newContainer(name, modelUUID, controllerUUID string) containerlxd.Container {
+	return containerlxd.Container{Instance: api.Instance{
+		Name: name,
+		InstancePut: api.InstancePut{
+			Config: map[string]string{
+				"user.juju-model-uuid":      modelUUID,
+				"user.juju-controller-uuid": controllerUUID,
+			},
+		},
+	}}
+}
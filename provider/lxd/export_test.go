@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
+	environscloudspec "github.com/juju/juju/environs/cloudspec"
 )
 
 var (
@@ -87,6 +88,15 @@ func ExposeEnvServer(env *environ) Server {
 	return env.server()
 }
 
+// SetCloudSpecOnOpen sets the cloud spec of the environ as it is when the
+// environ is opened, before the model's project is created.
+func SetCloudSpecOnOpen(env environs.Environ, spec environscloudspec.CloudSpec) error {
+	lxdEnv := env.(*environ)
+	lxdEnv.lock.Lock()
+	defer lxdEnv.lock.Unlock()
+	return lxdEnv.setCloudSpec(spec, true)
+}
+
 func GetImageSources(env environs.Environ) ([]lxd.ServerSpec, error) {
 	lxdEnv, ok := env.(*environ)
 	if !ok {
@@ -160,6 +160,20 @@ func (mr *MockServerMockRecorder) CreateProfileWithConfig(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfileWithConfig", reflect.TypeOf((*MockServer)(nil).CreateProfileWithConfig), arg0, arg1)
 }
 
+// CreateProjectWithConfig mocks base method.
+func (m *MockServer) CreateProjectWithConfig(arg0, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProjectWithConfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProjectWithConfig indicates an expected call of CreateProjectWithConfig.
+func (mr *MockServerMockRecorder) CreateProjectWithConfig(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProjectWithConfig", reflect.TypeOf((*MockServer)(nil).CreateProjectWithConfig), arg0, arg1, arg2)
+}
+
 // CreateVolume mocks base method.
 func (m *MockServer) CreateVolume(arg0, arg1 string, arg2 map[string]string) error {
 	m.ctrl.T.Helper()
@@ -202,6 +216,20 @@ func (mr *MockServerMockRecorder) DeleteProfile(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockServer)(nil).DeleteProfile), arg0)
 }
 
+// DeleteProject mocks base method.
+func (m *MockServer) DeleteProject(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockServerMockRecorder) DeleteProject(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockServer)(nil).DeleteProject), arg0)
+}
+
 // DeleteStoragePoolVolume mocks base method.
 func (m *MockServer) DeleteStoragePoolVolume(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -216,6 +244,20 @@ func (mr *MockServerMockRecorder) DeleteStoragePoolVolume(arg0, arg1, arg2 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStoragePoolVolume", reflect.TypeOf((*MockServer)(nil).DeleteStoragePoolVolume), arg0, arg1, arg2)
 }
 
+// EmptyProject mocks base method.
+func (m *MockServer) EmptyProject() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyProject")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmptyProject indicates an expected call of EmptyProject.
+func (mr *MockServerMockRecorder) EmptyProject() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyProject", reflect.TypeOf((*MockServer)(nil).EmptyProject))
+}
+
 // EnableHTTPSListener mocks base method.
 func (m *MockServer) EnableHTTPSListener() error {
 	m.ctrl.T.Helper()
@@ -433,6 +475,37 @@ func (mr *MockServerMockRecorder) GetProfile(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockServer)(nil).GetProfile), arg0)
 }
 
+// GetProject mocks base method.
+func (m *MockServer) GetProject(arg0 string) (*api.Project, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", arg0)
+	ret0, _ := ret[0].(*api.Project)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockServerMockRecorder) GetProject(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockServer)(nil).GetProject), arg0)
+}
+
+// GetProjects mocks base method.
+func (m *MockServer) GetProjects() ([]api.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjects")
+	ret0, _ := ret[0].([]api.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjects indicates an expected call of GetProjects.
+func (mr *MockServerMockRecorder) GetProjects() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjects", reflect.TypeOf((*MockServer)(nil).GetProjects))
+}
+
 // GetServer mocks base method.
 func (m *MockServer) GetServer() (*api.Server, string, error) {
 	m.ctrl.T.Helper()
@@ -540,6 +613,21 @@ func (mr *MockServerMockRecorder) HasProfile(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasProfile", reflect.TypeOf((*MockServer)(nil).HasProfile), arg0)
 }
 
+// HasProject mocks base method.
+func (m *MockServer) HasProject(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasProject", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasProject indicates an expected call of HasProject.
+func (mr *MockServerMockRecorder) HasProject(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasProject", reflect.TypeOf((*MockServer)(nil).HasProject), arg0)
+}
+
 // HostArch mocks base method.
 func (m *MockServer) HostArch() string {
 	m.ctrl.T.Helper()
@@ -722,6 +810,34 @@ func (mr *MockServerMockRecorder) UpdateContainerProfiles(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainerProfiles", reflect.TypeOf((*MockServer)(nil).UpdateContainerProfiles), arg0, arg1)
 }
 
+// UpdateProfile mocks base method.
+func (m *MockServer) UpdateProfile(arg0 string, arg1 api.ProfilePut, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockServerMockRecorder) UpdateProfile(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockServer)(nil).UpdateProfile), arg0, arg1, arg2)
+}
+
+// UpdateProject mocks base method.
+func (m *MockServer) UpdateProject(arg0 string, arg1 api.ProjectPut, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockServerMockRecorder) UpdateProject(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockServer)(nil).UpdateProject), arg0, arg1, arg2)
+}
+
 // UpdateServerConfig mocks base method.
 func (m *MockServer) UpdateServerConfig(arg0 map[string]string) error {
 	m.ctrl.T.Helper()
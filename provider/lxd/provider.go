@@ -205,9 +205,15 @@ func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*con
 
 // Validate implements environs.EnvironProvider.
 func (*environProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
-	if _, err := newValidConfig(cfg); err != nil {
+	ecfg, err := newValidConfig(cfg)
+	if err != nil {
 		return nil, errors.Annotate(err, "invalid base config")
 	}
+	if old != nil {
+		if err := ecfg.validateChange(newConfig(old)); err != nil {
+			return nil, errors.Annotate(err, "invalid config change")
+		}
+	}
 	return cfg, nil
 }
 
@@ -91,6 +91,15 @@ type Server interface {
 	// UseProject ensures that this server will use the input project.
 	// See: https://documentation.ubuntu.com/lxd/en/latest/projects.
 	UseProject(string)
+
+	HasProject(name string) (bool, error)
+	CreateProjectWithConfig(name, description string, cfg map[string]string) error
+	GetProject(name string) (*lxdapi.Project, string, error)
+	GetProjects() ([]lxdapi.Project, error)
+	UpdateProject(name string, project lxdapi.ProjectPut, ETag string) error
+	DeleteProject(name string) error
+	EmptyProject() error
+	UpdateProfile(name string, profile lxdapi.ProfilePut, ETag string) error
 }
 
 // CloudSpec describes the cloud configuration for use with the LXD provider.
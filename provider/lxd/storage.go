@@ -319,20 +319,19 @@ func parseFilesystemId(id string) (lxdPool, volumeName string, _ error) {
 
 // TODO (manadart 2018-06-28) Add a test for DestroyController that properly
 // verifies this behaviour.
-func destroyControllerFilesystems(env *environ, controllerUUID string) error {
-	return errors.Trace(destroyFilesystems(env, func(v api.StorageVolume) bool {
+func destroyControllerFilesystems(server Server, controllerUUID string) error {
+	return errors.Trace(destroyFilesystems(server, func(v api.StorageVolume) bool {
 		return v.Config["user."+tags.JujuController] == controllerUUID
 	}))
 }
 
 func destroyModelFilesystems(env *environ) error {
-	return errors.Trace(destroyFilesystems(env, func(v api.StorageVolume) bool {
+	return errors.Trace(destroyFilesystems(env.server(), func(v api.StorageVolume) bool {
 		return v.Config["user."+tags.JujuModel] == env.Config().UUID()
 	}))
 }
 
-func destroyFilesystems(env *environ, match func(api.StorageVolume) bool) error {
-	server := env.server()
+func destroyFilesystems(server Server, match func(api.StorageVolume) bool) error {
 	pools, err := server.GetStoragePools()
 	if err != nil {
 		return errors.Annotate(err, "listing LXD storage pools")
@@ -353,6 +353,10 @@ func (ecfg *Config) Values(c *gc.C) (ConfigValues, map[string]interface{}) {
 	return values, extras
 }
 
+func (ecfg *Config) ProjectLimits() map[string]string {
+	return ecfg.projectLimits()
+}
+
 func (ecfg *Config) Apply(c *gc.C, updates map[string]interface{}) *Config {
 	cfg, err := ecfg.Config.Apply(updates)
 	c.Assert(err, jc.ErrorIsNil)
@@ -706,6 +710,39 @@ func (*StubClient) GetInstanceState(string) (*api.InstanceState, string, error)
 	panic("this stub is deprecated; use mocks instead")
 }
 
+func (*StubClient) HasProject(string) (bool, error) {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (*StubClient) CreateProjectWithConfig(string, string, map[string]string) error {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (*StubClient) GetProject(string) (*api.Project, string, error) {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (conn *StubClient) GetProjects() ([]api.Project, error) {
+	conn.AddCall("GetProjects")
+	return nil, conn.NextErr()
+}
+
+func (*StubClient) UpdateProject(string, api.ProjectPut, string) error {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (*StubClient) DeleteProject(string) error {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (*StubClient) EmptyProject() error {
+	panic("this stub is deprecated; use mocks instead")
+}
+
+func (*StubClient) UpdateProfile(string, api.ProfilePut, string) error {
+	panic("this stub is deprecated; use mocks instead")
+}
+
 // TODO (manadart 2018-07-20): This exists to satisfy the testing stub
 // interface. It is temporary, pending replacement with mocks and
 // should not be called in tests.
@@ -786,7 +823,9 @@ func (s *EnvironSuite) NewEnvironWithServerFactory(c *gc.C, srv ServerFactory, c
 
 	return &environ{
 		name:         "controller",
+		uuid:         cfg.UUID(),
 		provider:     &provid,
+		base:         &stubCommon{stub: &jujutesting.Stub{}},
 		ecfgUnlocked: eCfg,
 		namespace:    namespace,
 	}
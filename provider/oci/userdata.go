@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/os/ostype"
+	"github.com/juju/juju/environs"
 )
 
 // OCIRenderer implements the renderers.ProviderRenderer interface
@@ -33,3 +34,10 @@ func (OCIRenderer) Render(cfg cloudinit.CloudConfig, os ostype.OSType) ([]byte,
 	ret := b64.StdEncoding.EncodeToString(renderedUdata)
 	return []byte(ret), nil
 }
+
+var _ environs.UserDataRenderer = (*Environ)(nil)
+
+// UserDataRenderer is part of the environs.UserDataRenderer interface.
+func (*Environ) UserDataRenderer() renderers.ProviderRenderer {
+	return OCIRenderer{}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/providerinit/renderers"
 	"github.com/juju/juju/core/os/ostype"
+	"github.com/juju/juju/environs"
 )
 
 type OpenstackRenderer struct{}
@@ -23,3 +24,10 @@ func (OpenstackRenderer) Render(cfg cloudinit.CloudConfig, os ostype.OSType) ([]
 		return nil, errors.Errorf("Cannot encode userdata for OS: %s", os.String())
 	}
 }
+
+var _ environs.UserDataRenderer = (*Environ)(nil)
+
+// UserDataRenderer is part of the environs.UserDataRenderer interface.
+func (*Environ) UserDataRenderer() renderers.ProviderRenderer {
+	return OpenstackRenderer{}
+}
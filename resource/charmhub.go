@@ -18,15 +18,16 @@ import (
 )
 
 type charmHubOpener struct {
-	st chClientState
+	st    chClientState
+	cache *charmhub.Cache
 }
 
-func newCharmHubOpener(st chClientState) *charmHubOpener {
-	return &charmHubOpener{st}
+func newCharmHubOpener(st chClientState, cache *charmhub.Cache) *charmHubOpener {
+	return &charmHubOpener{st: st, cache: cache}
 }
 
 func (ch *charmHubOpener) NewClient() (*ResourceRetryClient, error) {
-	client, err := newCharmHubClient(ch.st)
+	client, err := newCharmHubClient(ch.st, ch.cache)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -39,7 +40,7 @@ type chClientState interface {
 	Model() (*state.Model, error)
 }
 
-func newCharmHubClient(st chClientState) (ResourceGetter, error) {
+func newCharmHubClient(st chClientState, cache *charmhub.Cache) (ResourceGetter, error) {
 	m, err := st.Model()
 	if err != nil {
 		return &CharmHubClient{}, errors.Trace(err)
@@ -52,6 +53,7 @@ func newCharmHubClient(st chClientState) (ResourceGetter, error) {
 	chURL, _ := modelCfg.CharmHubURL()
 	chClient, err := charmhub.NewClient(charmhub.Config{
 		URL:    chURL,
+		Cache:  cache,
 		Logger: logger,
 	})
 	if err != nil {
@@ -107,7 +109,10 @@ func (ch *CharmHubClient) GetResource(req ResourceRequest) (ResourceData, error)
 
 	ch.logger.Tracef("Read resource %q from %q", r.Name, resourceURL)
 
-	data.ReadCloser, err = ch.client.DownloadResource(context.TODO(), resourceURL)
+	// Pass the expected hash along so that the download can be served from
+	// the charmhub cache, if there is one.
+	ctx := context.WithValue(context.TODO(), charmhub.DownloadSHA384Key, r.Fingerprint.String())
+	data.ReadCloser, err = ch.client.DownloadResource(ctx, resourceURL)
 	if err != nil {
 		return data, errors.Trace(err)
 	}
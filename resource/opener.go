@@ -13,26 +13,31 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names/v5"
 
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/resources"
 	"github.com/juju/juju/state"
 )
 
 // NewResourceOpener returns a new resource.Opener for the given unit.
+// Resources fetched from charmhub are cached in the given cache, if any.
 //
 // The caller owns the State provided. It is the caller's
 // responsibility to close it.
 func NewResourceOpener(
-	st *state.State, resourceDownloadLimiterFunc func() ResourceDownloadLock, unitName string,
+	st *state.State, charmhubCache *charmhub.Cache, resourceDownloadLimiterFunc func() ResourceDownloadLock, unitName string,
 ) (opener resources.Opener, err error) {
-	return newInternalResourceOpener(st, resourceDownloadLimiterFunc, unitName, "")
+	return newInternalResourceOpener(st, charmhubCache, resourceDownloadLimiterFunc, unitName, "")
 }
 
 // NewResourceOpenerForApplication returns a new resource.Opener for the given app.
+// Resources fetched from charmhub are cached in the given cache, if any.
 //
 // The caller owns the State provided. It is the caller's
 // responsibility to close it.
-func NewResourceOpenerForApplication(st *state.State, applicationName string) (opener resources.Opener, err error) {
-	return newInternalResourceOpener(st, func() ResourceDownloadLock {
+func NewResourceOpenerForApplication(
+	st *state.State, charmhubCache *charmhub.Cache, applicationName string,
+) (opener resources.Opener, err error) {
+	return newInternalResourceOpener(st, charmhubCache, func() ResourceDownloadLock {
 		return noopDownloadResourceLocker{}
 	}, "", applicationName)
 }
@@ -49,7 +54,7 @@ func (noopDownloadResourceLocker) Acquire(string) {}
 func (noopDownloadResourceLocker) Release(appName string) {}
 
 func newInternalResourceOpener(
-	st *state.State, resourceDownloadLimiterFunc func() ResourceDownloadLock, unitName string, appName string,
+	st *state.State, charmhubCache *charmhub.Cache, resourceDownloadLimiterFunc func() ResourceDownloadLock, unitName string, appName string,
 ) (opener resources.Opener, err error) {
 	var unit *state.Unit
 	if unitName != "" {
@@ -89,7 +94,7 @@ func newInternalResourceOpener(
 	}
 	switch {
 	case charm.CharmHub.Matches(charmURL.Schema):
-		resourceClientGetter = newCharmHubOpener(st)
+		resourceClientGetter = newCharmHubOpener(st, charmhubCache)
 	default:
 		// Use the nop opener that performs no store side requests. Instead it
 		// will resort to using the state package only. Any thing else will call
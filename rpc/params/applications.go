@@ -517,6 +517,31 @@ type UnitResult struct {
 	Address    string `json:"address,omitempty"`
 }
 
+// HookSpan records how long each stage of running a uniter operation
+// took.
+type HookSpan struct {
+	Operation string        `json:"operation"`
+	Queued    time.Time     `json:"queued"`
+	LockWait  time.Duration `json:"lock-wait"`
+	Prepare   time.Duration `json:"prepare"`
+	Execute   time.Duration `json:"execute"`
+	Commit    time.Duration `json:"commit"`
+	Skipped   bool          `json:"skipped,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// UnitHookHistoryResult holds the timing of the operations most recently
+// run by a unit agent, oldest first, or a retrieval error.
+type UnitHookHistoryResult struct {
+	History []HookSpan `json:"history,omitempty"`
+	Error   *Error     `json:"error,omitempty"`
+}
+
+// UnitHookHistoryResults holds the hook history of units.
+type UnitHookHistoryResults struct {
+	Results []UnitHookHistoryResult `json:"results"`
+}
+
 // UnitInfoResults holds an unit info result or a retrieval error.
 type UnitInfoResult struct {
 	Result *UnitResult `json:"result,omitempty"`
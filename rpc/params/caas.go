@@ -4,6 +4,8 @@
 package params
 
 import (
+	"time"
+
 	"github.com/juju/names/v5"
 	"github.com/juju/version/v2"
 
@@ -58,6 +60,9 @@ type CAASApplicationProvisioningInfo struct {
 	CharmModifiedVersion int                          `json:"charm-modified-version,omitempty"`
 	CharmURL             string                       `json:"charm-url,omitempty"`
 	Trust                bool                         `json:"trust,omitempty"`
+	Autoscaling          string                       `json:"autoscaling,omitempty"`
+	MaxUnavailable       string                       `json:"max-unavailable,omitempty"`
+	SpreadZones          bool                         `json:"spread-zones,omitempty"`
 	Scale                int                          `json:"scale,omitempty"`
 	Error                *Error                       `json:"error,omitempty"`
 }
@@ -197,3 +202,18 @@ type CAASApplicationProvisionerConfigResult struct {
 	ProvisionerConfig *CAASApplicationProvisionerConfig `json:"provisioner-config,omitempty"`
 	Error             *Error                            `json:"error,omitempty"`
 }
+
+// CAASUnitEvent holds an event recorded by the cloud against a unit.
+type CAASUnitEvent struct {
+	UnitTag string    `json:"unit-tag"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// CAASUnitEvents holds events to record against units.
+type CAASUnitEvents struct {
+	Events []CAASUnitEvent `json:"events"`
+}
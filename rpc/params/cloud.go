@@ -338,6 +338,21 @@ type InvalidateCredentialArg struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// ClusterHealthArg is used to report the health of the API endpoints
+// of a model's cluster.
+type ClusterHealthArg struct {
+	// Endpoint is the API endpoint the model's workers are using. It is
+	// empty if no endpoint is healthy.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Unhealthy describes why each unhealthy endpoint failed its
+	// health check.
+	Unhealthy []string `json:"unhealthy,omitempty"`
+
+	// Error describes why no endpoint is healthy.
+	Error string `json:"error,omitempty"`
+}
+
 // RevokeCredentialArg contains data needed to revoke credential.
 type RevokeCredentialArg struct {
 	// Tag holds credential tag to revoke.
@@ -34,4 +34,8 @@ const (
 const (
 	MachineNonceHeader = "X-Juju-Nonce"
 	JujuClientVersion  = "X-Juju-ClientVersion"
+
+	// AgentBinarySignatureHeader holds the base64 encoded detached
+	// signature of an uploaded agent binary.
+	AgentBinarySignatureHeader = "X-Juju-Agent-Binary-Signature"
 )
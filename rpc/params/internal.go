@@ -266,6 +266,9 @@ type UnitStateResult struct {
 	SecretState string `json:"secret-state,omitempty"`
 	// MeterStatusState encodes the meter status state for this unit.
 	MeterStatusState string `json:"meter-status-state,omitempty"`
+	// HookHistory is the JSON encoded timing of the operations most
+	// recently run by the unit's uniter.
+	HookHistory string `json:"hook-history,omitempty"`
 }
 
 // UnitStateResults holds multiple unit state maps or errors.
@@ -294,6 +297,7 @@ type SetUnitStateArg struct {
 	StorageState     *string            `json:"storage-state,omitempty"`
 	SecretState      *string            `json:"secret-state,omitempty"`
 	MeterStatusState *string            `json:"meter-status-state,omitempty"`
+	HookHistory      *string            `json:"hook-history,omitempty"`
 }
 
 // CommitHookChangesArgs serves as a container for CommitHookChangesArg objects
@@ -506,6 +506,25 @@ type UpgradeModelParams struct {
 	AgentStream         string         `json:"agent-stream,omitempty"`
 	IgnoreAgentVersions bool           `json:"ignore-agent-versions,omitempty"`
 	DryRun              bool           `json:"dry-run,omitempty"`
+
+	// Stages, when set, upgrades the model's machines in stages
+	// rather than all at once.
+	Stages *UpgradeStages `json:"stages,omitempty"`
+}
+
+// UpgradeStages describes how a staged model upgrade releases machines.
+type UpgradeStages struct {
+	// Canaries holds the machine ids or unit names whose machines are
+	// upgraded first.
+	Canaries []string `json:"canaries,omitempty"`
+
+	// BatchSize is the number of machines upgraded together after
+	// the canaries.
+	BatchSize int `json:"batch-size"`
+
+	// ErrorThreshold is the percentage of upgraded machines which may
+	// report errors before the upgrade is halted.
+	ErrorThreshold int `json:"error-threshold"`
 }
 
 // UpgradeModelResult holds the result of a UpgradeModel API call.
@@ -854,6 +854,31 @@ type ApplicationOpenedPortsResults struct {
 	Results []ApplicationOpenedPortsResult `json:"results"`
 }
 
+// ApplicationRelatedEndpoint describes an application endpoint which
+// is related to an endpoint of another application.
+type ApplicationRelatedEndpoint struct {
+	Endpoint            string `json:"endpoint"`
+	RelatedApplication  string `json:"related-application"`
+	RelatedEndpointName string `json:"related-endpoint"`
+
+	// CrossModel is true when the related application is in another
+	// model.
+	CrossModel bool `json:"cross-model,omitempty"`
+}
+
+// ApplicationRelatedEndpointsResult holds a single result of the
+// CAASFirewallerSidecar.ApplicationRelatedEndpoints() API call.
+type ApplicationRelatedEndpointsResult struct {
+	Error     *Error                       `json:"error,omitempty"`
+	Endpoints []ApplicationRelatedEndpoint `json:"endpoints"`
+}
+
+// ApplicationRelatedEndpointsResults holds all the results of the
+// CAASFirewallerSidecar.ApplicationRelatedEndpoints() API call.
+type ApplicationRelatedEndpointsResults struct {
+	Results []ApplicationRelatedEndpointsResult `json:"results"`
+}
+
 // OpenPortRangesByEndpointResults holds the results of a request to the
 // uniter's OpenedMachinePortRangesByEndpoint and OpenedPortRangesByEndpoint API.
 type OpenPortRangesByEndpointResults struct {
@@ -912,6 +937,37 @@ type IngressRule struct {
 	SourceCIDRs []string  `json:"source-cidrs"`
 }
 
+// FirewallDriftDataKey is the key in the status data of a model status
+// history entry under which a serialised FirewallDriftReport is stored.
+const FirewallDriftDataKey = "firewall-drift"
+
+// FirewallDriftReport holds the result of comparing the ingress rules
+// applied by the provider with those the firewaller expects.
+type FirewallDriftReport struct {
+	// Mode is the firewall-drift-mode the check was run with.
+	Mode string `json:"mode"`
+
+	// Corrected is true if the firewaller corrected the drift found.
+	Corrected bool `json:"corrected,omitempty"`
+
+	// Drift holds the differences found, if any.
+	Drift []FirewallDrift `json:"drift,omitempty"`
+}
+
+// FirewallDrift describes the differences found for a single firewall.
+type FirewallDrift struct {
+	// Target is "global" for the firewall shared by all machines in
+	// global firewall mode, "model" for the model firewall, or the tag
+	// of the machine whose firewall drifted.
+	Target string `json:"target"`
+
+	// Missing holds the rules expected but not applied.
+	Missing []IngressRule `json:"missing,omitempty"`
+
+	// Unexpected holds the rules applied but not expected.
+	Unexpected []IngressRule `json:"unexpected,omitempty"`
+}
+
 // APIHostPortsResult holds the result of an APIHostPorts
 // call. Each element in the top level slice holds
 // the addresses for one API server.
@@ -829,6 +829,40 @@ type ProvisioningScriptResult struct {
 	Script string `json:"script"`
 }
 
+// MachineUserDataParams contains the parameters for the
+// MachineUserData client API call.
+type MachineUserDataParams struct {
+	Machines []MachineUserDataParam `json:"machines"`
+}
+
+// MachineUserDataParam identifies a machine whose userdata is
+// previewed. If MachineTag is empty, the userdata of a new machine,
+// as added by add-machine with Base and Constraints, is previewed.
+type MachineUserDataParam struct {
+	MachineTag  string            `json:"machine-tag,omitempty"`
+	Base        *Base             `json:"base,omitempty"`
+	Constraints constraints.Value `json:"constraints"`
+}
+
+// MachineUserDataResults holds the results of the MachineUserData
+// client API call.
+type MachineUserDataResults struct {
+	Results []MachineUserDataResult `json:"results"`
+}
+
+// MachineUserDataResult holds the userdata a provider would receive
+// when starting an instance for a machine.
+type MachineUserDataResult struct {
+	// UserData is the userdata, encoded as the provider receives it.
+	UserData []byte `json:"user-data,omitempty"`
+
+	// BaseUserData is the userdata without the model's
+	// cloudinit-userdata merged in, encoded in the same way.
+	BaseUserData []byte `json:"base-user-data,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
 // DeployerConnectionValues containers the result of deployer.ConnectionInfo
 // API call.
 type DeployerConnectionValues struct {
@@ -994,6 +1028,14 @@ type ControllersChanges struct {
 	Maintained []string `json:"maintained,omitempty"`
 	Removed    []string `json:"removed,omitempty"`
 	Converted  []string `json:"converted,omitempty"`
+
+	// Zones maps the tags of added machines to the availability
+	// zones they were placed in by the controller.
+	Zones map[string]string `json:"zones,omitempty"`
+
+	// Warnings holds any concerns about the resulting placement of
+	// the controllers, such as a zone holding a majority of them.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // FindToolsParams defines parameters for the FindTools method.
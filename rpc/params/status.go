@@ -56,6 +56,32 @@ type ModelStatusInfo struct {
 	ModelStatus      DetailedStatus `json:"model-status"`
 	MeterStatus      MeterStatus    `json:"meter-status"`
 	SLA              string         `json:"sla"`
+
+	// UpgradeRollout describes the model's staged upgrade, if it has
+	// one which isn't complete.
+	UpgradeRollout *UpgradeRolloutInfo `json:"upgrade-rollout,omitempty"`
+
+	// UnenforcedEgress holds the names of applications with egress
+	// rules which the model's cloud doesn't enforce.
+	UnenforcedEgress []string `json:"unenforced-egress,omitempty"`
+}
+
+// UpgradeRolloutInfo describes the progress of a staged model upgrade.
+type UpgradeRolloutInfo struct {
+	FromVersion string `json:"from-version"`
+	ToVersion   string `json:"to-version"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+
+	// Released is the number of machines released to the upgrade,
+	// out of the model's Machines.
+	Released int `json:"released"`
+	Machines int `json:"machines"`
+
+	// Batch holds the ids of the machines being upgraded, and Failed
+	// those of the released machines reporting errors.
+	Batch  []string `json:"batch,omitempty"`
+	Failed []string `json:"failed,omitempty"`
 }
 
 // NetworkInterface holds a /etc/network/interfaces-type data and the
@@ -934,6 +934,94 @@ type ImportStorageDetails struct {
 	StorageTag string `json:"storage-tag"`
 }
 
+// ResizeStorageParams contains the parameters for resizing a collection
+// of storage instances.
+type ResizeStorageParams struct {
+	Storage []ResizeStorageParam `json:"storage"`
+}
+
+// ResizeStorageParam contains the parameters for resizing a storage instance.
+type ResizeStorageParam struct {
+	// StorageTag is the tag of the storage instance to resize.
+	StorageTag string `json:"storage-tag"`
+
+	// Size is the new size of the storage instance, in MiB.
+	Size uint64 `json:"size"`
+}
+
+// StorageSnapshotParams contains the parameters for creating, exporting
+// or restoring a collection of storage snapshots.
+type StorageSnapshotParams struct {
+	Snapshots []StorageSnapshotParam `json:"snapshots"`
+}
+
+// StorageSnapshotParam identifies a snapshot of a storage instance.
+type StorageSnapshotParam struct {
+	// StorageTag is the tag of the storage instance that the
+	// snapshot is of.
+	StorageTag string `json:"storage-tag"`
+
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+}
+
+// StorageSnapshot describes a snapshot of a storage instance.
+type StorageSnapshot struct {
+	// StorageTag is the tag of the storage instance that the
+	// snapshot is of.
+	StorageTag string `json:"storage-tag"`
+
+	// Name is the name of the snapshot.
+	Name string `json:"name"`
+
+	// Size is the size of the snapshot in MiB, if known.
+	Size uint64 `json:"size,omitempty"`
+
+	// Ready reports whether the snapshot may be exported or restored.
+	Ready bool `json:"ready"`
+
+	// Created is the time the snapshot was taken, if known.
+	Created *time.Time `json:"created,omitempty"`
+}
+
+// StorageSnapshotResults contains the results of creating a collection
+// of storage snapshots.
+type StorageSnapshotResults struct {
+	Results []StorageSnapshotResult `json:"results"`
+}
+
+// StorageSnapshotResult contains the result of creating a storage snapshot.
+type StorageSnapshotResult struct {
+	Result *StorageSnapshot `json:"result,omitempty"`
+	Error  *Error           `json:"error,omitempty"`
+}
+
+// StorageSnapshotsResults contains the snapshots of a collection of
+// storage instances.
+type StorageSnapshotsResults struct {
+	Results []StorageSnapshotsResult `json:"results"`
+}
+
+// StorageSnapshotsResult contains the snapshots of a storage instance.
+type StorageSnapshotsResult struct {
+	Result []StorageSnapshot `json:"result,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// ExportStorageSnapshotResults contains the results of exporting a
+// collection of storage snapshots.
+type ExportStorageSnapshotResults struct {
+	Results []ExportStorageSnapshotResult `json:"results"`
+}
+
+// ExportStorageSnapshotResult contains the result of exporting a
+// storage snapshot. VolumeId is the provider's identifier for the
+// volume created from the snapshot.
+type ExportStorageSnapshotResult struct {
+	VolumeId string `json:"volume-id,omitempty"`
+	Error    *Error `json:"error,omitempty"`
+}
+
 // AddStorageResults contains the results of adding storage to units.
 type AddStorageResults struct {
 	Results []AddStorageResult `json:"results"`
@@ -80,6 +80,15 @@ func allCollections() CollectionSchema {
 		// be uploaded to different models without affecting other models.
 		toolsmetadataC: {},
 
+		// This collection holds the metadata of deltas between agent
+		// binaries, which agents fetch in place of full agent binaries
+		// when upgrading.
+		toolsdeltametadataC: {},
+
+		// This collection holds the staged upgrade of each model, which
+		// upgrades its machines a batch at a time.
+		upgradeRolloutsC: {},
+
 		// This collection holds model information; in particular its
 		// Life and its UUID.
 		modelsC: {
@@ -737,10 +746,12 @@ const (
 	linkLayerDevicesC          = "linklayerdevices"
 	ipAddressesC               = "ip.addresses"
 	toolsmetadataC             = "toolsmetadata"
+	toolsdeltametadataC        = "toolsdeltametadata"
 	txnsC                      = "txns"
 	unitsC                     = "units"
 	unitStatesC                = "unitstates"
 	upgradeInfoC               = "upgradeInfo"
+	upgradeRolloutsC           = "upgradeRollouts"
 	userLastLoginC             = "userLastLogin"
 	usermodelnameC             = "usermodelname"
 	usersC                     = "users"
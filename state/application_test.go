@@ -4654,6 +4654,29 @@ func (s *ApplicationSuite) TestWatchCharmConfig(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *ApplicationSuite) TestWatchApplicationConfig(c *gc.C) {
+	w := s.mysql.WatchApplicationConfig()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, w)
+	wc.AssertOneChange()
+
+	err := s.mysql.UpdateApplicationConfig(config.ConfigAttributes{"title": "value"}, nil, sampleApplicationConfigSchema(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Non-change is not reported.
+	err = s.mysql.UpdateApplicationConfig(config.ConfigAttributes{"title": "value"}, nil, sampleApplicationConfigSchema(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Charm config changes are not reported.
+	err = s.mysql.UpdateCharmConfig(model.GenerationMaster, charm.Settings{"dataset-size": "50%"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
 var updateApplicationConfigTests = []struct {
 	about   string
 	initial config.ConfigAttributes
@@ -38,6 +38,14 @@ func (st *State) ToolsStorage() (binarystorage.StorageCloser, error) {
 	}}, nil
 }
 
+// ToolsDeltaStorage returns a new binarystorage.StorageCloser that stores
+// deltas between agent binaries, with their metadata in the "juju"
+// database "toolsdeltametadata" collection. Deltas are stored under the
+// versions returned by binarystorage.DeltaVersion.
+func (st *State) ToolsDeltaStorage() (binarystorage.StorageCloser, error) {
+	return newBinaryStorageCloser(st.database, toolsdeltametadataC, st.ModelUUID()), nil
+}
+
 func newBinaryStorageCloser(db Database, collectionName, uuid string) binarystorage.StorageCloser {
 	db, closer1 := db.CopyForModel(uuid)
 	metadataCollection, closer2 := db.GetCollection(collectionName)
@@ -69,6 +69,8 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 		Size:    metadata.Size,
 		SHA256:  metadata.SHA256,
 		Path:    path,
+
+		ArchiveSHA256: metadata.ArchiveSHA256,
 	}
 
 	// Add or replace metadata. If replacing, record the existing path so we
@@ -99,6 +101,7 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 					"$set", bson.D{
 						{"size", metadata.Size},
 						{"sha256", metadata.SHA256},
+						{"archive-sha256", metadata.ArchiveSHA256},
 						{"path", path},
 					},
 				}}
@@ -133,9 +136,10 @@ func (s *binaryStorage) Open(version string) (Metadata, io.ReadCloser, error) {
 		return Metadata{}, nil, err
 	}
 	metadata := Metadata{
-		Version: metadataDoc.Version,
-		Size:    metadataDoc.Size,
-		SHA256:  metadataDoc.SHA256,
+		Version:       metadataDoc.Version,
+		Size:          metadataDoc.Size,
+		SHA256:        metadataDoc.SHA256,
+		ArchiveSHA256: metadataDoc.ArchiveSHA256,
 	}
 	return metadata, r, nil
 }
@@ -146,9 +150,10 @@ func (s *binaryStorage) Metadata(version string) (Metadata, error) {
 		return Metadata{}, err
 	}
 	return Metadata{
-		Version: metadataDoc.Version,
-		Size:    metadataDoc.Size,
-		SHA256:  metadataDoc.SHA256,
+		Version:       metadataDoc.Version,
+		Size:          metadataDoc.Size,
+		SHA256:        metadataDoc.SHA256,
+		ArchiveSHA256: metadataDoc.ArchiveSHA256,
 	}, nil
 }
 
@@ -160,9 +165,10 @@ func (s *binaryStorage) AllMetadata() ([]Metadata, error) {
 	list := make([]Metadata, len(docs))
 	for i, doc := range docs {
 		list[i] = Metadata{
-			Version: doc.Version,
-			Size:    doc.Size,
-			SHA256:  doc.SHA256,
+			Version:       doc.Version,
+			Size:          doc.Size,
+			SHA256:        doc.SHA256,
+			ArchiveSHA256: doc.ArchiveSHA256,
 		}
 	}
 	return list, nil
@@ -174,6 +180,8 @@ type metadataDoc struct {
 	Size    int64  `bson:"size"`
 	SHA256  string `bson:"sha256,omitempty"`
 	Path    string `bson:"path"`
+
+	ArchiveSHA256 string `bson:"archive-sha256,omitempty"`
 }
 
 func (s *binaryStorage) findMetadata(version string) (metadataDoc, error) {
@@ -94,6 +94,8 @@ func (s *binaryStorageSuite) testAdd(c *gc.C, content string) {
 		Version: current,
 		Size:    int64(len(content)),
 		SHA256:  "hash(" + content + ")",
+
+		ArchiveSHA256: "archive-hash(" + content + ")",
 	}
 	err := s.storage.Add(r, addedMetadata)
 	c.Assert(err, jc.ErrorIsNil)
@@ -386,3 +388,9 @@ type errorTransactionRunner struct {
 func (errorTransactionRunner) Run(transactions jujutxn.TransactionSource) error {
 	return errors.New("Run fails")
 }
+
+func (s *binaryStorageSuite) TestDeltaVersion(c *gc.C) {
+	from := binarystorage.Metadata{Version: "2.0.41-ubuntu-amd64", SHA256: "abc"}
+	to := binarystorage.Metadata{Version: current, SHA256: "def"}
+	c.Assert(binarystorage.DeltaVersion(from, to), gc.Equals, "2.0.41-ubuntu-amd64-abc..2.0.42-ubuntu-amd64-def")
+}
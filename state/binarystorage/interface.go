@@ -4,6 +4,7 @@
 package binarystorage
 
 import (
+	"fmt"
 	"io"
 )
 
@@ -12,6 +13,19 @@ type Metadata struct {
 	Version string
 	Size    int64
 	SHA256  string
+
+	// ArchiveSHA256 is the SHA-256 of the uncompressed tar archive of
+	// agent binaries, against which an archive rebuilt from a delta is
+	// checked. It is empty for other binary files.
+	ArchiveSHA256 string
+}
+
+// DeltaVersion returns the version under which the delta between the
+// binary files with the given metadata is stored. Binary files with the
+// same version may differ between models, so their SHA-256 hashes are
+// part of the version.
+func DeltaVersion(from, to Metadata) string {
+	return fmt.Sprintf("%s-%s..%s-%s", from.Version, from.SHA256, to.Version, to.SHA256)
 }
 
 // Storage provides methods for storing and retrieving binary files by version.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/mgo/v3"
 	"github.com/juju/mgo/v3/bson"
@@ -92,6 +93,19 @@ func (st *State) maintainControllersOps(newIds []string, bootstrapOnly bool) ([]
 func (st *State) EnableHA(
 	numControllers int, cons constraints.Value, base Base, placement []string,
 ) (ControllersChanges, error) {
+	return st.EnableHAInZones(numControllers, cons, base, placement, nil)
+}
+
+// EnableHAInZones behaves like EnableHA, but prefers to start each new
+// controller machine that is not started according to a placement directive
+// in the next of the given availability zones, until the zones are exhausted.
+// Such machines are constrained to all of the given zones, with the preferred
+// zone first, so that the provisioner can fall back to another of them should
+// the preferred zone fail. The preferred zones are reported in the returned
+// changes.
+func (st *State) EnableHAInZones(
+	numControllers int, cons constraints.Value, base Base, placement []string, zones []string,
+) (ControllersChanges, error) {
 
 	if numControllers < 0 || (numControllers != 0 && numControllers%2 != 1) {
 		return ControllersChanges{}, errors.New("number of controllers must be odd and non-negative")
@@ -125,6 +139,7 @@ func (st *State) EnableHA(
 		if err != nil {
 			return nil, err
 		}
+		intent.zones = zones
 		voteCount := 0
 		for _, m := range intent.maintain {
 			if m.WantsVote() {
@@ -161,6 +176,10 @@ type ControllersChanges struct {
 	Removed    []string
 	Maintained []string
 	Converted  []string
+
+	// Zones maps the ids of added machines to the
+	// availability zones they are preferably started in.
+	Zones map[string]string
 }
 
 // enableHAIntentionOps returns operations to fulfil the desired intent.
@@ -211,9 +230,40 @@ func (st *State) enableHAIntentionOps(
 		return result, constraints.Value{}
 	}
 
+	// Prefer the zones that have been provided for machines started
+	// without a placement directive, until they are used up.
+	zoneCount := 0
+	getZone := func() string {
+		if zoneCount >= len(intent.zones) {
+			return ""
+		}
+		result := intent.zones[zoneCount]
+		zoneCount++
+		return result
+	}
+	distinctZones := set.NewStrings()
+	var allZones []string
+	for _, zone := range intent.zones {
+		if !distinctZones.Contains(zone) {
+			distinctZones.Add(zone)
+			allZones = append(allZones, zone)
+		}
+	}
+
 	var controllerIds []string
 	for i := 0; i < intent.newCount; i++ {
 		placement, cons := getPlacementConstraints()
+		var zone string
+		if placement == "" {
+			if zone = getZone(); zone != "" {
+				cons.Zones = &[]string{zone}
+				for _, other := range allZones {
+					if other != zone {
+						*cons.Zones = append(*cons.Zones, other)
+					}
+				}
+			}
+		}
 		template := MachineTemplate{
 			Base: base,
 			Jobs: []MachineJob{
@@ -243,6 +293,12 @@ func (st *State) enableHAIntentionOps(
 		}
 		ops = append(ops, addOps...)
 		change.Added = append(change.Added, mdoc.Id)
+		if zone != "" {
+			if change.Zones == nil {
+				change.Zones = make(map[string]string)
+			}
+			change.Zones[mdoc.Id] = zone
+		}
 		if controllerApp != nil {
 			_, unitOps, err := controllerApp.addUnitOps("", AddUnitParams{
 				UnitName:  &controllerUnitName,
@@ -269,6 +325,7 @@ func (st *State) enableHAIntentionOps(
 type enableHAIntent struct {
 	newCount  int
 	placement []string
+	zones     []string
 
 	maintain []ControllerNode
 	convert  []*Machine
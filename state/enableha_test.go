@@ -105,6 +105,36 @@ func (s *EnableHASuite) TestEnableHAAddsNewMachines(c *gc.C) {
 	s.assertControllerInfo(c, ids, ids, nil)
 }
 
+func (s *EnableHASuite) TestEnableHAInZones(c *gc.C) {
+	_, err := s.State.AddMachine(state.UbuntuBase("18.04"), state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.Value{
+		Mem: newUint64(100),
+	}
+	changes, err := s.State.EnableHAInZones(5, cons, state.UbuntuBase("18.04"), nil, []string{"az1", "az2", "az3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, jc.SameContents, []string{"1", "2", "3", "4"})
+
+	// Only as many machines as there are zones prefer one. Each may
+	// fall back to the other zones.
+	c.Assert(changes.Zones, jc.DeepEquals, map[string]string{"1": "az1", "2": "az2", "3": "az3"})
+	expectedCons := []constraints.Value{
+		{Mem: newUint64(100), Zones: &[]string{"az1", "az2", "az3"}},
+		{Mem: newUint64(100), Zones: &[]string{"az2", "az1", "az3"}},
+		{Mem: newUint64(100), Zones: &[]string{"az3", "az1", "az2"}},
+		cons,
+	}
+	for i, expected := range expectedCons {
+		m, err := s.State.Machine(fmt.Sprint(i + 1))
+		c.Assert(err, jc.ErrorIsNil)
+		gotCons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(gotCons, jc.DeepEquals, expected)
+	}
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3", "4"}, []string{"0", "1", "2", "3", "4"}, nil)
+}
+
 func (s *EnableHASuite) TestEnableHAAddsControllerCharm(c *gc.C) {
 	state.AddTestingApplicationForBase(c, s.State, state.UbuntuBase("20.04"), "controller",
 		state.AddTestingCharmMultiSeries(c, s.State, "juju-controller"))
@@ -1589,7 +1589,9 @@ func (m *Machine) setPreferredAddressOps(addr address, isPublic bool) []txn.Op {
 	return ops
 }
 
-func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPublicAddressOps(
+	providerAddresses []address, machineAddresses []address, preferred network.AddressType,
+) ([]txn.Op, *address) {
 	publicAddress := m.doc.PreferredPublicAddress
 	logger.Tracef(
 		"machine %v: current public address: %#v \nprovider addresses: %#v \nmachine addresses: %#v",
@@ -1597,11 +1599,13 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
-		return network.ExactScopeMatch(addr.networkAddress(), network.ScopePublic)
+		return network.ExactScopeMatch(addr.networkAddress(), network.ScopePublic) &&
+			matchesPreferredFamily(addr, preferred)
 	}
 	// Without an exact match, prefer a fallback match.
 	getAddr := func(addresses []address) network.SpaceAddress {
-		addr, _ := networkAddresses(addresses).OneMatchingScope(network.ScopeMatchPublic)
+		addr, _ := networkAddresses(addresses).OneMatchingScope(
+			network.PreferAddressType(network.ScopeMatchPublic, preferred))
 		return addr
 	}
 
@@ -1615,16 +1619,20 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 	return ops, &newAddr
 }
 
-func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPrivateAddressOps(
+	providerAddresses []address, machineAddresses []address, preferred network.AddressType,
+) ([]txn.Op, *address) {
 	privateAddress := m.doc.PreferredPrivateAddress
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
 		return network.ExactScopeMatch(
-			addr.networkAddress(), network.ScopeMachineLocal, network.ScopeCloudLocal, network.ScopeFanLocal)
+			addr.networkAddress(), network.ScopeMachineLocal, network.ScopeCloudLocal, network.ScopeFanLocal) &&
+			matchesPreferredFamily(addr, preferred)
 	}
 	// Without an exact match, prefer a fallback match.
 	getAddr := func(addresses []address) network.SpaceAddress {
-		addr, _ := networkAddresses(addresses).OneMatchingScope(network.ScopeMatchCloudLocal)
+		addr, _ := networkAddresses(addresses).OneMatchingScope(
+			network.PreferAddressType(network.ScopeMatchCloudLocal, preferred))
 		return addr
 	}
 
@@ -1637,6 +1645,48 @@ func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddre
 	return ops, &newAddr
 }
 
+// matchesPreferredFamily returns false if IPv6 is the preferred address
+// family and the input is not an IPv6 address, so that a current IPv4
+// preferred address is replaced when an IPv6 one becomes available.
+// Any address matches the default IPv4 preference, so that existing
+// preferred addresses are retained as they always have been.
+func matchesPreferredFamily(addr address, preferred network.AddressType) bool {
+	if preferred != network.IPv6Address {
+		return true
+	}
+	return network.AddressType(addr.AddressType) == network.IPv6Address
+}
+
+// preferredAddressFamily returns the address family that the model
+// config prefers when choosing between addresses of equal scope.
+// Callers should only ask when the machine has IPv6 addresses, as the
+// preference makes no difference otherwise; see lazyAddressFamily.
+func (m *Machine) preferredAddressFamily() (network.AddressType, error) {
+	model, err := m.st.Model()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return cfg.PreferredAddressFamily(), nil
+}
+
+// lazyAddressFamily returns a function that reads the model's preferred
+// address family the first time it is called.
+func (m *Machine) lazyAddressFamily() func() (network.AddressType, error) {
+	var family network.AddressType
+	return func() (network.AddressType, error) {
+		if family != "" {
+			return family, nil
+		}
+		var err error
+		family, err = m.preferredAddressFamily()
+		return family, errors.Trace(err)
+	}
+}
+
 // SetProviderAddresses records any addresses related to the machine, sourced
 // by asking the provider.
 func (m *Machine) SetProviderAddresses(addresses ...network.SpaceAddress) error {
@@ -1679,6 +1729,7 @@ func (m *Machine) setAddresses(machineAddresses, providerAddresses *[]network.Sp
 		newPrivate, newPublic                         *address
 		err                                           error
 	)
+	preferred := m.lazyAddressFamily()
 	machine := m
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt != 0 {
@@ -1688,7 +1739,7 @@ func (m *Machine) setAddresses(machineAddresses, providerAddresses *[]network.Sp
 		}
 		var ops []txn.Op
 		ops, machineStateAddresses, providerStateAddresses, newPrivate, newPublic, err = machine.setAddressesOps(
-			machineAddresses, providerAddresses,
+			machineAddresses, providerAddresses, preferred,
 		)
 		if err != nil {
 			return nil, err
@@ -1743,7 +1794,7 @@ func (st *State) maybeUpdateControllerCharm(publicAddr string) error {
 }
 
 func (m *Machine) setAddressesOps(
-	machineAddresses, providerAddresses *[]network.SpaceAddress,
+	machineAddresses, providerAddresses *[]network.SpaceAddress, preferredFamily func() (network.AddressType, error),
 ) (_ []txn.Op, machineStateAddresses, providerStateAddresses []address, newPrivate, newPublic *address, _ error) {
 
 	if m.doc.Life == Dead {
@@ -1776,13 +1827,32 @@ func (m *Machine) setAddressesOps(
 		Update: bson.D{{"$set", set}},
 	}}
 
-	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses)
-	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses)
+	// The preferred address family only chooses between IPv4 and IPv6
+	// addresses, so the model config is not read for IPv4-only machines.
+	preferred := network.IPv4Address
+	if hasIPv6Address(providerStateAddresses) || hasIPv6Address(machineStateAddresses) {
+		var err error
+		if preferred, err = preferredFamily(); err != nil {
+			return nil, nil, nil, nil, nil, errors.Trace(err)
+		}
+	}
+
+	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses, preferred)
+	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses, preferred)
 	ops = append(ops, setPrivateAddressOps...)
 	ops = append(ops, setPublicAddressOps...)
 	return ops, machineStateAddresses, providerStateAddresses, newPrivate, newPublic, nil
 }
 
+func hasIPv6Address(addrs []address) bool {
+	for _, addr := range addrs {
+		if network.AddressType(addr.AddressType) == network.IPv6Address {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckProvisioned returns true if the machine was provisioned with the given nonce.
 func (m *Machine) CheckProvisioned(nonce string) bool {
 	return nonce == m.doc.Nonce && nonce != ""
@@ -2258,7 +2328,8 @@ func (op *UpdateMachineOperation) Build(attempt int) ([]txn.Op, error) {
 	}
 
 	if op.MachineAddresses != nil || op.ProviderAddresses != nil {
-		ops, _, _, _, _, err := op.m.setAddressesOps(op.MachineAddresses, op.ProviderAddresses)
+		preferred := op.m.lazyAddressFamily()
+		ops, _, _, _, _, err := op.m.setAddressesOps(op.MachineAddresses, op.ProviderAddresses, preferred)
 		if err != nil {
 			return nil, errors.Annotate(err, "cannot set addresses")
 		}
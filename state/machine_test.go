@@ -2167,6 +2167,68 @@ func (s *MachineSuite) TestPublicAddressBetterMatch(c *gc.C) {
 	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
 }
 
+func (s *MachineSuite) TestPreferredAddressesDualStack(c *gc.C) {
+	machine, err := s.State.AddMachine(state.UbuntuBase("12.10"), state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses := network.SpaceAddresses{
+		network.NewSpaceAddress("8.8.8.8", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("2001:db8::1", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("10.0.0.1", network.WithScope(network.ScopeCloudLocal)),
+		network.NewSpaceAddress("fd00::1", network.WithScope(network.ScopeCloudLocal)),
+	}
+	err = machine.SetProviderAddresses(addresses...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// IPv4 is preferred by default.
+	addr, err := machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, "8.8.8.8")
+	addr, err = machine.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, "10.0.0.1")
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{
+		"preferred-address-family": "ipv6",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The IPv6 addresses replace the IPv4 ones when addresses are next set.
+	err = machine.SetProviderAddresses(addresses...)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, "2001:db8::1")
+	addr, err = machine.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, "fd00::1")
+}
+
+func (s *MachineSuite) TestUpdateOperationPreferredAddressFamily(c *gc.C) {
+	machine, err := s.State.AddMachine(state.UbuntuBase("12.10"), state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Updating addresses honours the model's preference, as setting them does.
+	err = s.Model.UpdateModelConfig(map[string]interface{}{
+		"preferred-address-family": "ipv6",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	addresses := []network.SpaceAddress{
+		network.NewSpaceAddress("8.8.8.8", network.WithScope(network.ScopePublic)),
+		network.NewSpaceAddress("2001:db8::1", network.WithScope(network.ScopePublic)),
+	}
+	op := machine.UpdateOperation()
+	op.ProviderAddresses = &addresses
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addr.Value, gc.Equals, "2001:db8::1")
+}
+
 func (s *MachineSuite) TestPrivateAddressBetterMatch(c *gc.C) {
 	machine, err := s.State.AddMachine(state.UbuntuBase("12.10"), state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
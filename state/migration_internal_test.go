@@ -135,6 +135,11 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Not exported, but the tools will possibly need to be either bundled
 		// with the representation or sent separately.
 		toolsmetadataC,
+		// Deltas between agent binaries are generated again on demand.
+		toolsdeltametadataC,
+		// Models with a staged upgrade underway have agents running
+		// mixed versions, and can't be migrated.
+		upgradeRolloutsC,
 		// Bakery storage items are non-critical. We store root keys for
 		// temporary credentials in there; after migration you'll just have
 		// to log back in.
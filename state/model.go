@@ -777,6 +777,23 @@ func (m *Model) StatusHistory(filter status.StatusHistoryFilter) ([]status.Statu
 	return statusHistory(args)
 }
 
+// AddStatusHistory records the supplied status in the model's status
+// history without changing the current status of the model. This allows
+// findings such as periodic checks to be kept alongside status changes.
+func (m *Model) AddStatusHistory(sInfo status.StatusInfo) error {
+	if !status.ValidModelStatus(sInfo.Status) {
+		return errors.Errorf("cannot record invalid status %q", sInfo.Status)
+	}
+	doc := statusDoc{
+		Status:     sInfo.Status,
+		StatusInfo: sInfo.Message,
+		StatusData: utils.EscapeKeys(sInfo.Data),
+		Updated:    timeOrNow(sInfo.Since, m.st.clock()).UnixNano(),
+	}
+	_, err := probablyUpdateStatusHistory(m.st.db(), m.globalKey(), doc)
+	return errors.Annotate(err, "cannot record status history")
+}
+
 // Config returns the config for the model.
 func (m *Model) Config() (*config.Config, error) {
 	return getModelConfig(m.st.db(), m.UUID())
@@ -153,6 +153,35 @@ func (s *ModelStatusSuite) checkGetSetStatus(c *gc.C) {
 	c.Check(statusInfo.Since, gc.NotNil)
 }
 
+func (s *ModelStatusSuite) TestAddStatusHistory(c *gc.C) {
+	err := s.model.AddStatusHistory(status.StatusInfo{
+		Status:  status.Available,
+		Message: "firewall drift detected",
+		Data: map[string]interface{}{
+			"pew.pew": "zap",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The current status is unchanged.
+	s.checkInitialStatus(c)
+
+	history, err := s.model.StatusHistory(status.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Message, gc.Equals, "firewall drift detected")
+	c.Check(history[0].Data, jc.DeepEquals, map[string]interface{}{
+		"pew.pew": "zap",
+	})
+}
+
+func (s *ModelStatusSuite) TestAddStatusHistoryInvalidStatus(c *gc.C) {
+	err := s.model.AddStatusHistory(status.StatusInfo{
+		Status: status.Status("vliegkat"),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot record invalid status "vliegkat"`)
+}
+
 func (s *ModelStatusSuite) TestModelStatusForModel(c *gc.C) {
 	ms, err := s.model.LoadModelStatus()
 	c.Assert(err, jc.ErrorIsNil)
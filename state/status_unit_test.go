@@ -214,6 +214,34 @@ func (s *UnitStatusSuite) TestStatusSinceDoesNotChangeWhenReceivedStatusIsTheSam
 	}
 }
 
+func (s *UnitStatusSuite) TestRecordStatusHistory(c *gc.C) {
+	lastStatus, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := lastStatus.Since.Add(time.Minute)
+	err = s.unit.RecordStatusHistory(status.StatusInfo{
+		Status:  lastStatus.Status,
+		Message: "BackOff: back-off restarting failed container",
+		Data:    map[string]interface{}{"reason": "BackOff"},
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit's status is unchanged.
+	statusInfo, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Message, gc.Equals, lastStatus.Message)
+	c.Assert(statusInfo.Since.Equal(*lastStatus.Since), jc.IsTrue)
+
+	history, err := s.unit.StatusHistory(status.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, lastStatus.Status)
+	c.Assert(history[0].Message, gc.Equals, "BackOff: back-off restarting failed container")
+	c.Assert(history[0].Data, jc.DeepEquals, map[string]interface{}{"reason": "BackOff"})
+	c.Assert(history[0].Since.Equal(now), jc.IsTrue)
+}
+
 func (s *UnitStatusSuite) TestStatusHistoryInitial(c *gc.C) {
 	history, err := s.unit.StatusHistory(status.StatusHistoryFilter{Size: 1})
 	c.Check(err, jc.ErrorIsNil)
@@ -1410,6 +1410,22 @@ func (u *Unit) StatusHistory(filter status.StatusHistoryFilter) ([]status.Status
 	return statusHistory(args)
 }
 
+// RecordStatusHistory records the supplied status in the unit's workload
+// status history without changing the unit's status. It is used to surface
+// problems reported by the cloud, such as Kubernetes warning events,
+// alongside the unit's own status changes.
+func (u *Unit) RecordStatusHistory(info status.StatusInfo) error {
+	since := timeOrNow(info.Since, u.st.clock())
+	doc := statusDoc{
+		Status:     info.Status,
+		StatusInfo: info.Message,
+		StatusData: mgoutils.EscapeKeys(info.Data),
+		Updated:    since.UnixNano(),
+	}
+	_, err := probablyUpdateStatusHistory(u.st.db(), u.globalKey(), doc)
+	return errors.Annotatef(err, "recording status history for unit %q", u.Name())
+}
+
 // Status returns the status of the unit.
 // This method relies on globalKey instead of globalAgentKey since it is part of
 // the effort to separate Unit from UnitAgent. Now the Status for UnitAgent is in
@@ -138,6 +138,10 @@ func (op *unitSetStateOperation) newUnitStateDoc(unitGlobalKey string) (unitStat
 		newStDoc.MeterStatusState = meterStatusState
 		quotaChecker.Check(meterStatusState)
 	}
+	if hookHistory, found := op.newState.HookHistory(); found {
+		newStDoc.HookHistory = hookHistory
+		quotaChecker.Check(hookHistory)
+	}
 	if err := quotaChecker.Outcome(); err != nil {
 		return unitStateDoc{}, errors.Annotatef(err, "persisting uniter state")
 	}
@@ -231,6 +235,15 @@ func (op *unitSetStateOperation) fields(currentDoc unitStateDoc) (bson.D, bson.D
 		}
 	}
 
+	if hookHistory, found := op.newState.HookHistory(); found {
+		if hookHistory == "" {
+			unsetFields = append(unsetFields, bson.DocElem{Name: "hook-history"})
+		} else if hookHistory != currentDoc.HookHistory {
+			setFields = append(setFields, bson.DocElem{"hook-history", hookHistory})
+			quotaChecker.Check(hookHistory)
+		}
+	}
+
 	if err := quotaChecker.Outcome(); err != nil {
 		if errors.IsQuotaLimitExceeded(err) {
 			return nil, nil, errors.Annotatef(err, "persisting internal uniter state")
@@ -44,6 +44,10 @@ type unitStateDoc struct {
 	// MeterStatusState is a serialized yaml string containing the internal
 	// state for this unit's meter status worker.
 	MeterStatusState string `bson:"meter-status-state,omitempty"`
+
+	// HookHistory is a serialized json string containing the timing of
+	// the operations most recently run by the unit's uniter.
+	HookHistory string `bson:"hook-history,omitempty"`
 }
 
 // charmStateMatches returns true if the State map within the unitStateDoc matches
@@ -138,6 +142,11 @@ type UnitState struct {
 	// state for the meter status worker for this unit.
 	meterStatusState    string
 	meterStatusStateSet bool
+
+	// hookHistory is a serialized json string containing the timing of
+	// the operations most recently run by the unit's uniter.
+	hookHistory    string
+	hookHistorySet bool
 }
 
 // NewUnitState returns a new UnitState struct.
@@ -152,7 +161,8 @@ func (u *UnitState) Modified() bool {
 		u.secretStateSet ||
 		u.charmStateSet ||
 		u.uniterStateSet ||
-		u.meterStatusStateSet
+		u.meterStatusStateSet ||
+		u.hookHistorySet
 }
 
 // SetCharmState sets the charm state value.
@@ -237,6 +247,18 @@ func (u *UnitState) MeterStatusState() (string, bool) {
 	return u.meterStatusState, u.meterStatusStateSet
 }
 
+// SetHookHistory sets the hook history value.
+func (u *UnitState) SetHookHistory(history string) {
+	u.hookHistorySet = true
+	u.hookHistory = history
+}
+
+// HookHistory returns the hook history and a bool to indicate
+// whether the data was set.
+func (u *UnitState) HookHistory() (string, bool) {
+	return u.hookHistory, u.hookHistorySet
+}
+
 // SetState replaces the currently stored state for a unit with the contents
 // of the provided UnitState.
 //
@@ -296,6 +318,7 @@ func (u *Unit) State() (*UnitState, error) {
 	us.SetStorageState(stDoc.StorageState)
 	us.SetSecretState(stDoc.SecretState)
 	us.SetMeterStatusState(stDoc.MeterStatusState)
+	us.SetHookHistory(stDoc.HookHistory)
 
 	return us, nil
 }
@@ -0,0 +1,657 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+/*
+This file defines staged model upgrades, which upgrade the agents of a
+model's machines a batch at a time rather than all at once.
+
+A staged upgrade leaves the model's agent-version unchanged until every
+machine has been upgraded. Instead, the machines released to the upgrade
+are recorded in the model's "upgradeRollouts" document, and the upgrader
+facade tells the agents of those machines, and of the units on them, to
+run the target version.
+
+1. StartUpgradeRollout releases the canary machines.
+
+2. The upgraderollout worker calls AdvanceUpgradeRollout periodically
+while the upgrade is running. Once every machine of the current batch
+runs the target version with its machine agent started and its units
+idle, with no errors, the next batch is released. These are the statuses
+"juju wait-for" queries.
+
+3. When more of the released machines report errors than the error
+threshold allows, the upgrade is halted. Machines of the current batch
+which haven't settled within RolloutSettleTimeout of its release count
+as reporting errors. The upgrade can be resumed or aborted.
+
+4. Once no machines remain, the model's agent-version is set to the
+target version and the upgrade is complete.
+*/
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v3"
+	"github.com/juju/mgo/v3/bson"
+	"github.com/juju/mgo/v3/txn"
+	"github.com/juju/names/v5"
+	"github.com/juju/naturalsort"
+	jujutxn "github.com/juju/txn/v3"
+	"github.com/juju/version/v2"
+
+	"github.com/juju/juju/core/status"
+)
+
+// UpgradeRolloutStatus describes the states a staged model upgrade
+// may be in.
+type UpgradeRolloutStatus string
+
+const (
+	// RolloutRunning indicates that batches of machines are being
+	// released to the upgrade.
+	RolloutRunning UpgradeRolloutStatus = "running"
+
+	// RolloutPaused indicates that no more machines will be released
+	// until the upgrade is resumed.
+	RolloutPaused UpgradeRolloutStatus = "paused"
+
+	// RolloutHalted indicates that the upgrade was stopped because too
+	// many upgraded machines reported errors.
+	RolloutHalted UpgradeRolloutStatus = "halted"
+
+	// RolloutAborted indicates that the upgrade was abandoned. Machines
+	// already released keep running the target version.
+	RolloutAborted UpgradeRolloutStatus = "aborted"
+
+	// RolloutComplete indicates that all machines were upgraded and the
+	// model's agent version set to the target version.
+	RolloutComplete UpgradeRolloutStatus = "complete"
+
+	// upgradeRolloutKey is the key of a model's staged upgrade document.
+	upgradeRolloutKey = "upgrade-rollout"
+
+	// RolloutSettleTimeout is how long the machines of a batch have to
+	// settle after being released, or after the upgrade is resumed,
+	// before those which haven't count as failed.
+	RolloutSettleTimeout = 30 * time.Minute
+)
+
+// Active reports whether the upgrade can still release machines.
+func (s UpgradeRolloutStatus) Active() bool {
+	return s == RolloutRunning || s == RolloutPaused || s == RolloutHalted
+}
+
+type upgradeRolloutDoc struct {
+	DocID          string               `bson:"_id"`
+	ModelUUID      string               `bson:"model-uuid"`
+	TxnRevno       int64                `bson:"txn-revno"`
+	FromVersion    version.Number       `bson:"from-version"`
+	ToVersion      version.Number       `bson:"to-version"`
+	BatchSize      int                  `bson:"batch-size"`
+	ErrorThreshold int                  `bson:"error-threshold"`
+	Status         UpgradeRolloutStatus `bson:"status"`
+	Message        string               `bson:"message,omitempty"`
+	Canaries       []string             `bson:"canaries"`
+	Released       []string             `bson:"released"`
+	Batch          []string             `bson:"batch"`
+	Failed         []string             `bson:"failed,omitempty"`
+	BatchReleased  time.Time            `bson:"batch-released"`
+	Started        time.Time            `bson:"started"`
+	Updated        time.Time            `bson:"updated"`
+}
+
+// UpgradeRollout is a staged upgrade of a model's machines.
+type UpgradeRollout struct {
+	st  *State
+	doc upgradeRolloutDoc
+}
+
+// FromVersion returns the version being upgraded from.
+func (r *UpgradeRollout) FromVersion() version.Number {
+	return r.doc.FromVersion
+}
+
+// ToVersion returns the version being upgraded to.
+func (r *UpgradeRollout) ToVersion() version.Number {
+	return r.doc.ToVersion
+}
+
+// BatchSize returns the number of machines released together after
+// the canaries.
+func (r *UpgradeRollout) BatchSize() int {
+	return r.doc.BatchSize
+}
+
+// ErrorThreshold returns the percentage of released machines which may
+// report errors without the upgrade being halted.
+func (r *UpgradeRollout) ErrorThreshold() int {
+	return r.doc.ErrorThreshold
+}
+
+// Status returns the status of the upgrade.
+func (r *UpgradeRollout) Status() UpgradeRolloutStatus {
+	return r.doc.Status
+}
+
+// Message returns why the upgrade was halted or aborted, if it was.
+func (r *UpgradeRollout) Message() string {
+	return r.doc.Message
+}
+
+// Canaries returns the ids of the machines upgraded first.
+func (r *UpgradeRollout) Canaries() []string {
+	return copyStrings(r.doc.Canaries)
+}
+
+// Released returns the ids of the machines released to the upgrade.
+func (r *UpgradeRollout) Released() []string {
+	return copyStrings(r.doc.Released)
+}
+
+// Batch returns the ids of the machines released most recently, which
+// must settle before more are released.
+func (r *UpgradeRollout) Batch() []string {
+	return copyStrings(r.doc.Batch)
+}
+
+// Failed returns the ids of the released machines which reported errors,
+// or didn't settle in time, when the upgrade was halted.
+func (r *UpgradeRollout) Failed() []string {
+	return copyStrings(r.doc.Failed)
+}
+
+// BatchReleased returns the time from which the current batch has been
+// given to settle.
+func (r *UpgradeRollout) BatchReleased() time.Time {
+	return r.doc.BatchReleased
+}
+
+// Started returns the time at which the upgrade was started.
+func (r *UpgradeRollout) Started() time.Time {
+	return r.doc.Started
+}
+
+// Updated returns the time at which the upgrade last changed.
+func (r *UpgradeRollout) Updated() time.Time {
+	return r.doc.Updated
+}
+
+// AgentVersion returns the version the agents of the machine with the
+// given id should run, given the model's agent version.
+func (r *UpgradeRollout) AgentVersion(machineId string, modelVersion version.Number) version.Number {
+	// Once the model's agent version moves on, by the upgrade
+	// completing or another upgrade, the rollout no longer applies.
+	if modelVersion != r.doc.FromVersion {
+		return modelVersion
+	}
+	if set.NewStrings(r.doc.Released...).Contains(machineId) {
+		return r.doc.ToVersion
+	}
+	return modelVersion
+}
+
+// Refresh updates the contents of the UpgradeRollout from underlying
+// state.
+func (r *UpgradeRollout) Refresh() error {
+	doc, err := r.st.upgradeRolloutDoc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.doc = *doc
+	return nil
+}
+
+// UpgradeRolloutArgs holds the parameters of a staged model upgrade.
+type UpgradeRolloutArgs struct {
+	// ToVersion is the version the model is upgraded to.
+	ToVersion version.Number
+
+	// Canaries holds the ids of the machines, or names of the units
+	// whose machines, are upgraded first. Without canaries, the first
+	// batch of machines is upgraded first.
+	Canaries []string
+
+	// BatchSize is the number of machines released together after
+	// the canaries.
+	BatchSize int
+
+	// ErrorThreshold is the percentage of released machines which may
+	// report errors without the upgrade being halted.
+	ErrorThreshold int
+
+	// IgnoreAgentVersions skips checking that all agents run the
+	// model's current agent version.
+	IgnoreAgentVersions bool
+}
+
+// Validate checks the parameters of a staged model upgrade.
+func (args UpgradeRolloutArgs) Validate() error {
+	if args.BatchSize < 1 {
+		return errors.NotValidf("batch size %d", args.BatchSize)
+	}
+	if args.ErrorThreshold < 0 || args.ErrorThreshold > 100 {
+		return errors.NotValidf("error threshold %d%%", args.ErrorThreshold)
+	}
+	return nil
+}
+
+// UpgradeRollout returns the model's most recent staged upgrade.
+func (st *State) UpgradeRollout() (*UpgradeRollout, error) {
+	doc, err := st.upgradeRolloutDoc()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UpgradeRollout{st: st, doc: *doc}, nil
+}
+
+func (st *State) upgradeRolloutDoc() (*upgradeRolloutDoc, error) {
+	coll, closer := st.db().GetCollection(upgradeRolloutsC)
+	defer closer()
+
+	var doc upgradeRolloutDoc
+	err := coll.FindId(upgradeRolloutKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("staged model upgrade")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read staged model upgrade")
+	}
+	return &doc, nil
+}
+
+// WatchUpgradeRollout returns a watcher for observing changes to the
+// model's staged upgrade.
+func (st *State) WatchUpgradeRollout() NotifyWatcher {
+	return newEntityWatcher(st, upgradeRolloutsC, st.docID(upgradeRolloutKey))
+}
+
+// StartUpgradeRollout starts a staged upgrade of the model's machines,
+// releasing the canaries to the upgrade. The model's agent version is
+// left unchanged until all machines are upgraded.
+func (st *State) StartUpgradeRollout(args UpgradeRolloutArgs) (*UpgradeRollout, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Type() != ModelTypeIAAS {
+		return nil, errors.NotSupportedf("staged upgrade of a %s model", model.Type())
+	}
+	if model.IsControllerModel() {
+		return nil, errors.NotSupportedf("staged upgrade of the controller model")
+	}
+	canaries, err := st.rolloutCanaries(args.Canaries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var doc *upgradeRolloutDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		settings, err := readSettings(st.db(), settingsC, modelGlobalKey)
+		if err != nil {
+			return nil, errors.Annotatef(err, "model %q", st.modelTag.Id())
+		}
+		currentVersion, _ := settings.Get("agent-version")
+		current, err := version.Parse(fmt.Sprint(currentVersion))
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid model agent version")
+		}
+		if current == args.ToVersion {
+			return nil, errors.Errorf("model is already running %s", current)
+		}
+		if !args.IgnoreAgentVersions {
+			if err := st.checkCanUpgradeIAAS(current.String(), args.ToVersion.String()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+
+		batch := canaries
+		if len(batch) == 0 {
+			remaining, err := st.rolloutRemaining(nil)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			batch = remaining[:min(args.BatchSize, len(remaining))]
+		}
+		now := st.clock().Now().UTC()
+		doc = &upgradeRolloutDoc{
+			DocID:          st.docID(upgradeRolloutKey),
+			ModelUUID:      st.ModelUUID(),
+			FromVersion:    current,
+			ToVersion:      args.ToVersion,
+			BatchSize:      args.BatchSize,
+			ErrorThreshold: args.ErrorThreshold,
+			Status:         RolloutRunning,
+			Canaries:       canaries,
+			Released:       batch,
+			Batch:          batch,
+			BatchReleased:  now,
+			Started:        now,
+			Updated:        now,
+		}
+
+		ops := []txn.Op{{
+			C:      settingsC,
+			Id:     st.docID(modelGlobalKey),
+			Assert: bson.D{{"version", settings.version}},
+		}}
+		existing, err := st.upgradeRolloutDoc()
+		if errors.Is(err, errors.NotFound) {
+			return append(ops, txn.Op{
+				C:      upgradeRolloutsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if existing.Status.Active() {
+			return nil, errors.Errorf("staged upgrade to %s is %s", existing.ToVersion, existing.Status)
+		}
+		return append(ops, txn.Op{
+			C:      upgradeRolloutsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"txn-revno", existing.TxnRevno}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"from-version", doc.FromVersion},
+					{"to-version", doc.ToVersion},
+					{"batch-size", doc.BatchSize},
+					{"error-threshold", doc.ErrorThreshold},
+					{"status", doc.Status},
+					{"canaries", doc.Canaries},
+					{"released", doc.Released},
+					{"batch", doc.Batch},
+					{"batch-released", doc.BatchReleased},
+					{"started", doc.Started},
+					{"updated", doc.Updated},
+				}},
+				{"$unset", bson.D{{"message", nil}, {"failed", nil}}},
+			},
+		}), nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotate(err, "cannot start staged model upgrade")
+	}
+	return st.UpgradeRollout()
+}
+
+// rolloutCanaries returns the ids of the machines named by the canaries,
+// which are machine ids or unit names.
+func (st *State) rolloutCanaries(canaries []string) ([]string, error) {
+	ids := set.NewStrings()
+	for _, canary := range canaries {
+		switch {
+		case names.IsValidMachine(canary):
+			m, err := st.Machine(canary)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if m.Life() != Alive {
+				return nil, errors.NotValidf("canary machine %q which is not alive", canary)
+			}
+			ids.Add(canary)
+		case names.IsValidUnit(canary):
+			u, err := st.Unit(canary)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			id, err := u.AssignedMachineId()
+			if err != nil {
+				return nil, errors.Annotatef(err, "canary unit %q", canary)
+			}
+			ids.Add(id)
+		default:
+			return nil, errors.NotValidf("canary %q", canary)
+		}
+	}
+	return naturalsort.Sort(ids.Values()), nil
+}
+
+// rolloutRemaining returns the ids of the live machines which haven't
+// been released to the upgrade, in order.
+func (st *State) rolloutRemaining(released []string) ([]string, error) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	done := set.NewStrings(released...)
+	var remaining []string
+	for _, m := range machines {
+		if m.Life() == Alive && !done.Contains(m.Id()) {
+			remaining = append(remaining, m.Id())
+		}
+	}
+	return naturalsort.Sort(remaining), nil
+}
+
+// PauseUpgradeRollout stops the model's staged upgrade releasing more
+// machines until it is resumed.
+func (st *State) PauseUpgradeRollout() error {
+	return st.setUpgradeRolloutStatus(RolloutPaused, "", RolloutRunning)
+}
+
+// ResumeUpgradeRollout resumes the model's paused or halted staged
+// upgrade. A halted upgrade halts again if the errors remain, while the
+// current batch is given RolloutSettleTimeout again to settle.
+func (st *State) ResumeUpgradeRollout() error {
+	return st.setUpgradeRolloutStatus(RolloutRunning, "", RolloutPaused, RolloutHalted)
+}
+
+// AbortUpgradeRollout abandons the model's staged upgrade. Machines
+// already released keep running the target version, while the model's
+// agent version is unchanged.
+func (st *State) AbortUpgradeRollout() error {
+	return st.setUpgradeRolloutStatus(RolloutAborted, "aborted by user", RolloutRunning, RolloutPaused, RolloutHalted)
+}
+
+func (st *State) setUpgradeRolloutStatus(
+	newStatus UpgradeRolloutStatus, message string, from ...UpgradeRolloutStatus,
+) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := st.upgradeRolloutDoc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc.Status == newStatus {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if !set.NewStrings(statusStrings(from)...).Contains(string(doc.Status)) {
+			return nil, errors.Errorf("staged upgrade to %s is %s", doc.ToVersion, doc.Status)
+		}
+		now := st.clock().Now().UTC()
+		fields := bson.D{{"status", newStatus}, {"updated", now}}
+		if message != "" {
+			fields = append(fields, bson.DocElem{"message", message})
+		}
+		if newStatus == RolloutRunning {
+			fields = append(fields, bson.DocElem{"batch-released", now})
+		}
+		update := bson.D{{"$set", fields}}
+		if newStatus == RolloutRunning {
+			update = append(update, bson.DocElem{"$unset", bson.D{{"message", nil}, {"failed", nil}}})
+		}
+		return []txn.Op{{
+			C:      upgradeRolloutsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+			Update: update,
+		}}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+func statusStrings(statuses []UpgradeRolloutStatus) []string {
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out
+}
+
+// AdvanceUpgradeRollout moves the model's running staged upgrade on:
+// it halts the upgrade when too many released machines report errors
+// or don't settle in time, releases the next batch of machines once the
+// current batch has settled, and completes the upgrade once no machines
+// remain. It reports whether the upgrade is still running, and so needs
+// advancing again.
+func (st *State) AdvanceUpgradeRollout() (bool, error) {
+	doc, err := st.upgradeRolloutDoc()
+	if errors.Is(err, errors.NotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	if doc.Status != RolloutRunning {
+		return false, nil
+	}
+
+	now := st.clock().Now()
+	batch := set.NewStrings(doc.Batch...)
+	settled := set.NewStrings()
+	var failed []string
+	var unsettled int
+	for _, id := range doc.Released {
+		health, err := st.rolloutMachineHealth(id, doc.ToVersion)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		switch health {
+		case machineSettled:
+			settled.Add(id)
+		case machineFailed:
+			failed = append(failed, id)
+		case machineSettling:
+			// A machine of the current batch stuck settling
+			// would stop the upgrade forever, so it counts
+			// as failed once it has had time to settle.
+			if batch.Contains(id) && now.Sub(doc.BatchReleased) >= RolloutSettleTimeout {
+				failed = append(failed, id)
+				unsettled++
+			}
+		}
+	}
+	if len(failed)*100 > doc.ErrorThreshold*len(doc.Released) {
+		message := fmt.Sprintf("%d of %d upgraded machines reported errors", len(failed), len(doc.Released))
+		if unsettled > 0 {
+			message = fmt.Sprintf("%d of %d upgraded machines reported errors or did not settle within %v",
+				len(failed), len(doc.Released), RolloutSettleTimeout)
+		}
+		return false, errors.Trace(st.updateUpgradeRollout(doc, bson.D{
+			{"status", RolloutHalted},
+			{"message", message},
+			{"failed", failed},
+		}))
+	}
+	for _, id := range doc.Batch {
+		if !settled.Contains(id) {
+			return true, nil
+		}
+	}
+
+	remaining, err := st.rolloutRemaining(doc.Released)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(remaining) == 0 {
+		// All agents now run either version, so the model's agent
+		// version can be changed without ignoring any.
+		if err := st.SetModelAgentVersion(doc.ToVersion, nil, false); err != nil {
+			return false, errors.Trace(err)
+		}
+		return false, errors.Trace(st.updateUpgradeRollout(doc, bson.D{
+			{"status", RolloutComplete},
+			{"batch", []string{}},
+		}))
+	}
+	next := remaining[:min(doc.BatchSize, len(remaining))]
+	return true, errors.Trace(st.updateUpgradeRollout(doc, bson.D{
+		{"released", append(copyStrings(doc.Released), next...)},
+		{"batch", next},
+		{"batch-released", now.UTC()},
+	}))
+}
+
+func (st *State) updateUpgradeRollout(doc *upgradeRolloutDoc, update bson.D) error {
+	update = append(update, bson.DocElem{"updated", st.clock().Now().UTC()})
+	ops := []txn.Op{{
+		C:      upgradeRolloutsC,
+		Id:     doc.DocID,
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+		Update: bson.D{{"$set", update}},
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		// The upgrade changed, it's considered again next time.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+type machineHealth int
+
+const (
+	machineSettling machineHealth = iota
+	machineSettled
+	machineFailed
+)
+
+// rolloutMachineHealth reports whether the released machine with the
+// given id has settled after upgrading to the version: its agent runs the
+// version and is started, and its units are idle, without errors.
+func (st *State) rolloutMachineHealth(id string, vers version.Number) (machineHealth, error) {
+	m, err := st.Machine(id)
+	if errors.Is(err, errors.NotFound) {
+		return machineSettled, nil
+	} else if err != nil {
+		return machineSettling, errors.Trace(err)
+	}
+	if m.Life() != Alive {
+		return machineSettled, nil
+	}
+	agentStatus, err := m.Status()
+	if err != nil {
+		return machineSettling, errors.Trace(err)
+	}
+	if agentStatus.Status == status.Error {
+		return machineFailed, nil
+	}
+	health := machineSettled
+	tools, err := m.AgentTools()
+	if errors.Is(err, errors.NotFound) {
+		health = machineSettling
+	} else if err != nil {
+		return machineSettling, errors.Trace(err)
+	} else if tools.Version.Number != vers || agentStatus.Status != status.Started {
+		health = machineSettling
+	}
+
+	units, err := m.Units()
+	if err != nil {
+		return machineSettling, errors.Trace(err)
+	}
+	for _, u := range units {
+		unitAgentStatus, err := u.AgentStatus()
+		if err != nil {
+			return machineSettling, errors.Trace(err)
+		}
+		workloadStatus, err := u.Status()
+		if err != nil {
+			return machineSettling, errors.Trace(err)
+		}
+		if unitAgentStatus.Status == status.Error || workloadStatus.Status == status.Error {
+			return machineFailed, nil
+		}
+		if unitAgentStatus.Status != status.Idle {
+			health = machineSettling
+		}
+	}
+	return health, nil
+}
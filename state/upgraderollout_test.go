@@ -0,0 +1,261 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type UpgradeRolloutSuite struct {
+	ConnSuite
+
+	st       *state.State
+	factory  *factory.Factory
+	from, to version.Number
+}
+
+var _ = gc.Suite(&UpgradeRolloutSuite{})
+
+func (s *UpgradeRolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.st = s.Factory.MakeModel(c, nil)
+	s.AddCleanup(func(*gc.C) { s.st.Close() })
+	s.factory = factory.NewFactory(s.st, s.StatePool)
+
+	s.to = jujuversion.Current
+	s.from = s.to
+	s.from.Patch = 0
+	s.from.Minor--
+	err := s.st.SetModelAgentVersion(s.from, nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeRolloutSuite) makeMachine(c *gc.C) *state.Machine {
+	m := s.factory.MakeMachine(c, nil)
+	s.setMachineAgent(c, m, s.from, status.Started)
+	return m
+}
+
+func (s *UpgradeRolloutSuite) setMachineAgent(c *gc.C, m *state.Machine, vers version.Number, agentStatus status.Status) {
+	err := m.SetAgentVersion(version.Binary{Number: vers, Release: "ubuntu", Arch: "amd64"})
+	c.Assert(err, jc.ErrorIsNil)
+	now := testing.ZeroTime()
+	err = m.SetStatus(status.StatusInfo{Status: agentStatus, Message: "agent", Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeRolloutSuite) start(c *gc.C, canaries ...string) *state.UpgradeRollout {
+	rollout, err := s.st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: s.to,
+		Canaries:  canaries,
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return rollout
+}
+
+func (s *UpgradeRolloutSuite) TestUpgradeRolloutNotFound(c *gc.C) {
+	_, err := s.st.UpgradeRollout()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeRolloutSuite) TestStartControllerModel(c *gc.C) {
+	_, err := s.State.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: s.to,
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *UpgradeRolloutSuite) TestStartInvalidArgs(c *gc.C) {
+	_, err := s.st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: s.to,
+	})
+	c.Assert(err, gc.ErrorMatches, "batch size 0 not valid")
+}
+
+func (s *UpgradeRolloutSuite) TestStartReleasesCanaries(c *gc.C) {
+	s.makeMachine(c)
+	s.makeMachine(c)
+	s.makeMachine(c)
+
+	rollout := s.start(c, "2")
+	c.Check(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Check(rollout.FromVersion(), gc.Equals, s.from)
+	c.Check(rollout.ToVersion(), gc.Equals, s.to)
+	c.Check(rollout.Canaries(), jc.DeepEquals, []string{"2"})
+	c.Check(rollout.Released(), jc.DeepEquals, []string{"2"})
+	c.Check(rollout.Batch(), jc.DeepEquals, []string{"2"})
+
+	c.Check(rollout.AgentVersion("2", s.from), gc.Equals, s.to)
+	c.Check(rollout.AgentVersion("0", s.from), gc.Equals, s.from)
+	// Once the model moves on, the rollout no longer applies.
+	c.Check(rollout.AgentVersion("2", s.to), gc.Equals, s.to)
+
+	// The model's agent version is unchanged.
+	assertAgentVersion(c, s.st, s.from.String(), "released")
+}
+
+func (s *UpgradeRolloutSuite) TestStartWithoutCanariesReleasesFirstBatch(c *gc.C) {
+	s.makeMachine(c)
+	s.makeMachine(c)
+	s.makeMachine(c)
+
+	rollout, err := s.st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: s.to,
+		BatchSize: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rollout.Released(), jc.DeepEquals, []string{"0", "1"})
+}
+
+func (s *UpgradeRolloutSuite) TestStartWhileActive(c *gc.C) {
+	s.makeMachine(c)
+	s.start(c)
+
+	_, err := s.st.StartUpgradeRollout(state.UpgradeRolloutArgs{
+		ToVersion: s.to,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot start staged model upgrade: staged upgrade to .* is running`)
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceWaitsForBatch(c *gc.C) {
+	m0 := s.makeMachine(c)
+	s.makeMachine(c)
+	rollout := s.start(c, "0")
+
+	// The canary hasn't upgraded yet.
+	running, err := s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Batch(), jc.DeepEquals, []string{"0"})
+
+	s.setMachineAgent(c, m0, s.to, status.Started)
+	running, err = s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Check(rollout.Released(), jc.DeepEquals, []string{"0", "1"})
+	c.Check(rollout.Batch(), jc.DeepEquals, []string{"1"})
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceHaltsOnErrors(c *gc.C) {
+	m0 := s.makeMachine(c)
+	s.makeMachine(c)
+	rollout := s.start(c, "0")
+
+	s.setMachineAgent(c, m0, s.to, status.Error)
+	running, err := s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutHalted)
+	c.Check(rollout.Message(), gc.Equals, "1 of 1 upgraded machines reported errors")
+	c.Check(rollout.Failed(), jc.DeepEquals, []string{"0"})
+	c.Check(rollout.Released(), jc.DeepEquals, []string{"0"})
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceHaltsWhenBatchDoesNotSettle(c *gc.C) {
+	clock := testclock.NewClock(testing.NonZeroTime().Round(time.Second))
+	err := s.st.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	m0 := s.makeMachine(c)
+	s.makeMachine(c)
+	rollout := s.start(c, "0")
+	c.Check(rollout.BatchReleased().UTC(), gc.Equals, clock.Now().UTC())
+
+	// The canary upgrades, but its agent never starts.
+	s.setMachineAgent(c, m0, s.to, status.Pending)
+	clock.Advance(state.RolloutSettleTimeout - time.Second)
+	running, err := s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutRunning)
+
+	clock.Advance(time.Second)
+	running, err = s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutHalted)
+	c.Check(rollout.Message(), gc.Equals, "1 of 1 upgraded machines reported errors or did not settle within 30m0s")
+	c.Check(rollout.Failed(), jc.DeepEquals, []string{"0"})
+
+	// Resuming gives the batch time to settle again.
+	err = s.st.ResumeUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Check(rollout.BatchReleased().UTC(), gc.Equals, clock.Now().UTC())
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceNotRunning(c *gc.C) {
+	running, err := s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+
+	s.makeMachine(c)
+	s.start(c)
+	err = s.st.PauseUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+}
+
+func (s *UpgradeRolloutSuite) TestAdvanceCompletes(c *gc.C) {
+	m0 := s.makeMachine(c)
+	rollout := s.start(c)
+
+	s.setMachineAgent(c, m0, s.to, status.Started)
+	running, err := s.st.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutComplete)
+	assertAgentVersion(c, s.st, s.to.String(), "released")
+}
+
+func (s *UpgradeRolloutSuite) TestPauseResumeAbort(c *gc.C) {
+	s.makeMachine(c)
+	rollout := s.start(c)
+
+	err := s.st.PauseUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutPaused)
+
+	err = s.st.ResumeUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutRunning)
+
+	err = s.st.AbortUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Refresh(), jc.ErrorIsNil)
+	c.Check(rollout.Status(), gc.Equals, state.RolloutAborted)
+	c.Check(rollout.Message(), gc.Equals, "aborted by user")
+
+	err = s.st.PauseUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "staged upgrade to .* is aborted")
+}
@@ -220,6 +220,43 @@ type VolumeImporter interface {
 	) (VolumeInfo, error)
 }
 
+// VolumeResizer provides an interface for growing volumes in place.
+type VolumeResizer interface {
+	// ResizeVolume grows the volume with the specified volume
+	// provider ID to at least the given size, in MiB. Volumes
+	// cannot be shrunk.
+	//
+	// Resizing may complete asynchronously; the new size will be
+	// reported by DescribeVolumes once the provider has finished.
+	ResizeVolume(ctx context.ProviderCallContext, volumeId string, size uint64) error
+}
+
+// VolumeSnapshotter provides an interface for taking point in time
+// snapshots of volumes, for restoring them, and for exporting them to
+// new volumes.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshot takes a snapshot with the given name of
+	// the volume with the specified volume provider ID.
+	CreateVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) (VolumeSnapshot, error)
+
+	// ListVolumeSnapshots returns the snapshots taken of the volume
+	// with the specified volume provider ID.
+	ListVolumeSnapshots(ctx context.ProviderCallContext, volumeId string) ([]VolumeSnapshot, error)
+
+	// ExportVolumeSnapshot creates a new volume from the named
+	// snapshot of the volume with the specified volume provider ID.
+	// The provider specific identifier of the new volume is returned;
+	// the original volume is left untouched. The new volume is not
+	// managed by Juju.
+	ExportVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) (string, error)
+
+	// RestoreVolumeSnapshot replaces the contents of the volume with
+	// the specified volume provider ID with the named snapshot of it.
+	// Workloads using the volume may be restarted, and the provider ID
+	// of the restored volume may change.
+	RestoreVolumeSnapshot(ctx context.ProviderCallContext, volumeId, name string) error
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
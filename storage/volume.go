@@ -3,7 +3,11 @@
 
 package storage
 
-import "github.com/juju/names/v5"
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+)
 
 type DeviceType string
 
@@ -43,6 +47,26 @@ type VolumeInfo struct {
 	Persistent bool
 }
 
+// VolumeSnapshot describes a point in time snapshot of a volume.
+type VolumeSnapshot struct {
+	// Name is the unique name of the snapshot.
+	Name string
+
+	// VolumeId is the provider ID of the volume that the
+	// snapshot was taken of.
+	VolumeId string
+
+	// Size is the size of the snapshot, in MiB. Not all providers
+	// report the size of a snapshot, so this may be zero.
+	Size uint64
+
+	// Ready reports whether the snapshot may be exported or restored.
+	Ready bool
+
+	// Created is the time that the snapshot was taken, if known.
+	Created time.Time
+}
+
 // VolumeAttachment identifies and describes machine-specific volume
 // attachment information, including how the volume is exposed on the
 // machine.
@@ -0,0 +1,439 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package delta implements binary deltas between agent binary tarballs,
+// so that an agent upgrading from one version to another only needs to
+// download what has changed.
+//
+// A delta describes the uncompressed tar archive of the target version
+// as a sequence of copies from the files of the base version, which the
+// agent already has unpacked, and inserts of new data. The agent
+// rebuilds the archive, checking it against the SHA-256 of the target's
+// uncompressed archive recorded when the target was stored, and
+// compresses it exactly as the target tarball was compressed, so the
+// result can be checked against the target's SHA-256 like any
+// downloaded tarball.
+package delta
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ContentType is the MIME type with which deltas are served.
+const ContentType = "application/x-juju-agent-binaries-delta"
+
+// magic starts every delta, identifying the format.
+const magic = "juju-agent-binaries-delta-v1\n"
+
+// Operations which make up the body of a delta.
+const (
+	opCopy   = 'c'
+	opInsert = 'i'
+	opEnd    = 'e'
+)
+
+// maxArchiveSize is the largest uncompressed archive, of the base or
+// target agent binaries, for which a delta is generated. Both archives
+// are held in memory while the delta is generated.
+var maxArchiveSize int64 = 512 << 20
+
+// header describes the base files a delta is applied to, the archive
+// rebuilt, and how the rebuilt archive is compressed.
+type header struct {
+	Base          []baseFile `json:"base"`
+	ArchiveSHA256 string     `json:"archive-sha256"`
+	Gzip          gzipParams `json:"gzip"`
+}
+
+// baseFile is a file of the base version, as unpacked by the agent.
+// The base data copied from is the content of the base files in order.
+type baseFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// gzipParams holds the gzip header fields and compression level with
+// which the target tarball is reproduced.
+type gzipParams struct {
+	Level   int    `json:"level"`
+	Name    string `json:"name,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	Extra   []byte `json:"extra,omitempty"`
+	ModTime int64  `json:"mod-time,omitempty"`
+	OS      byte   `json:"os"`
+}
+
+// compressionLevels are the levels tried when reproducing the target
+// tarball, in order. Agent binaries built by Juju use the default.
+var compressionLevels = []int{
+	gzip.DefaultCompression,
+	gzip.BestCompression,
+	gzip.BestSpeed,
+}
+
+// ArchiveSHA256 returns the SHA-256 of the uncompressed tar archive in
+// the tarball read from r, which is recorded with agent binaries so that
+// an archive rebuilt from a delta can be checked.
+func ArchiveSHA256(r io.Reader) (string, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() { _ = zr.Close() }()
+	zr.Multistream(false)
+	hash := sha256.New()
+	if _, err := io.Copy(hash, zr); err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// Generate returns a delta which rebuilds the target tarball from the
+// files of the base tarball. The target tarball must have the given
+// SHA-256, and its uncompressed archive archiveSHA256. An error
+// satisfying errors.NotSupported is returned when either archive is
+// larger than can be held in memory, when the target tarball can't be
+// reproduced by compressing its archive again, or when the delta would
+// be more than half its size.
+func Generate(base, target io.Reader, targetSHA256, archiveSHA256 string) ([]byte, error) {
+	files, baseData, err := readBase(base)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading base agent binaries")
+	}
+
+	targetSize := &countWriter{}
+	targetHash := sha256.New()
+	tr := io.TeeReader(target, io.MultiWriter(targetSize, targetHash))
+	zr, err := gzip.NewReader(tr)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading target agent binaries")
+	}
+	zr.Multistream(false)
+	archive, err := io.ReadAll(io.LimitReader(zr, maxArchiveSize+1))
+	if err != nil {
+		return nil, errors.Annotate(err, "reading target agent binaries")
+	}
+	if int64(len(archive)) > maxArchiveSize {
+		return nil, errors.NotSupportedf("delta of agent binaries larger than %d bytes", maxArchiveSize)
+	}
+	// Anything following the first gzip member can't be reproduced,
+	// but it must still be hashed.
+	if _, err := io.Copy(io.Discard, tr); err != nil {
+		return nil, errors.Annotate(err, "reading target agent binaries")
+	}
+	if sum := fmt.Sprintf("%x", targetHash.Sum(nil)); sum != targetSHA256 {
+		return nil, errors.Errorf("target agent binaries SHA-256 %s, expected %s", sum, targetSHA256)
+	}
+	if sum := fmt.Sprintf("%x", sha256.Sum256(archive)); sum != archiveSHA256 {
+		return nil, errors.Errorf("target agent binaries archive SHA-256 %s, expected %s", sum, archiveSHA256)
+	}
+
+	params := gzipParams{
+		Name:    zr.Header.Name,
+		Comment: zr.Header.Comment,
+		Extra:   zr.Header.Extra,
+		OS:      zr.Header.OS,
+	}
+	if !zr.Header.ModTime.IsZero() {
+		params.ModTime = zr.Header.ModTime.Unix()
+	}
+	if params.Level, err = reproduce(archive, params, targetHash.Sum(nil)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := bufio.NewWriter(zw)
+	if _, err := w.WriteString(magic); err != nil {
+		return nil, errors.Trace(err)
+	}
+	hdr, err := json.Marshal(header{Base: files, ArchiveSHA256: archiveSHA256, Gzip: params})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := w.Write(append(hdr, '\n')); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := encode(w, baseData, archive); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// A delta which saves little isn't worth rebuilding the tarball.
+	if int64(buf.Len()) > targetSize.n/2 {
+		return nil, errors.NotSupportedf("delta more than half the size of the agent binaries")
+	}
+	return buf.Bytes(), nil
+}
+
+// readBase returns the regular files in the base tarball, along with
+// their concatenated content, which may be no larger than maxArchiveSize.
+func readBase(r io.Reader) ([]baseFile, []byte, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	var (
+		files []baseFile
+		data  bytes.Buffer
+	)
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !validName(hdr.Name) {
+			continue
+		}
+		n, err := io.CopyN(&data, tr, maxArchiveSize-int64(data.Len())+1)
+		if err != nil && err != io.EOF {
+			return nil, nil, errors.Trace(err)
+		}
+		if int64(data.Len()) > maxArchiveSize {
+			return nil, nil, errors.NotSupportedf("delta of agent binaries larger than %d bytes", maxArchiveSize)
+		}
+		files = append(files, baseFile{Name: hdr.Name, Size: n})
+	}
+	return files, data.Bytes(), nil
+}
+
+// reproduce returns the compression level with which compressing the
+// archive with the gzip header params gives a tarball with the expected
+// SHA-256.
+func reproduce(archive []byte, params gzipParams, sum []byte) (int, error) {
+	for _, level := range compressionLevels {
+		params.Level = level
+		hash := sha256.New()
+		zw, err := newGzipWriter(hash, params)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if _, err := zw.Write(archive); err != nil {
+			return 0, errors.Trace(err)
+		}
+		if err := zw.Close(); err != nil {
+			return 0, errors.Trace(err)
+		}
+		if bytes.Equal(hash.Sum(nil), sum) {
+			return level, nil
+		}
+	}
+	return 0, errors.NotSupportedf("delta of agent binaries which can't be compressed identically")
+}
+
+func newGzipWriter(w io.Writer, params gzipParams) (*gzip.Writer, error) {
+	zw, err := gzip.NewWriterLevel(w, params.Level)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zw.Header = gzip.Header{
+		Name:    params.Name,
+		Comment: params.Comment,
+		Extra:   params.Extra,
+		OS:      params.OS,
+	}
+	if params.ModTime != 0 {
+		zw.Header.ModTime = time.Unix(params.ModTime, 0)
+	}
+	return zw, nil
+}
+
+// Apply reads a delta from r and writes the tarball it rebuilds to w,
+// reading the base files from dir. The rebuilt archive is checked
+// against the SHA-256 recorded in the delta before it is compressed, but
+// as the delta comes from an unverified peer, the tarball written must
+// still be checked against the SHA-256 of the target agent binaries.
+func Apply(w io.Writer, r io.Reader, dir string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Annotate(err, "reading delta")
+	}
+	defer func() { _ = zr.Close() }()
+	br := bufio.NewReader(zr)
+
+	prefix := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, prefix); err != nil || string(prefix) != magic {
+		return errors.NotValidf("agent binaries delta")
+	}
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return errors.Annotate(err, "reading delta header")
+	}
+	var hdr header
+	if err := json.Unmarshal(line, &hdr); err != nil {
+		return errors.Annotate(err, "reading delta header")
+	}
+
+	base, err := openBase(dir, hdr.Base)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer base.Close()
+
+	zw, err := newGzipWriter(w, hdr.Gzip)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archiveHash := sha256.New()
+	aw := io.MultiWriter(zw, archiveHash)
+	for {
+		op, err := br.ReadByte()
+		if err != nil {
+			return errors.Annotate(err, "reading delta")
+		}
+		switch op {
+		case opCopy:
+			offset, err := binary.ReadUvarint(br)
+			if err != nil {
+				return errors.Annotate(err, "reading delta")
+			}
+			length, err := binary.ReadUvarint(br)
+			if err != nil {
+				return errors.Annotate(err, "reading delta")
+			}
+			if offset+length > uint64(base.size) || offset+length < offset {
+				return errors.NotValidf("copy beyond the end of the base agent binaries")
+			}
+			section := io.NewSectionReader(base, int64(offset), int64(length))
+			if _, err := io.Copy(aw, section); err != nil {
+				return errors.Trace(err)
+			}
+		case opInsert:
+			length, err := binary.ReadUvarint(br)
+			if err != nil {
+				return errors.Annotate(err, "reading delta")
+			}
+			if n, err := io.CopyN(aw, br, int64(length)); err != nil {
+				if uint64(n) < length && err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return errors.Annotate(err, "reading delta")
+			}
+		case opEnd:
+			// The tarball isn't completed unless the archive matches.
+			if sum := fmt.Sprintf("%x", archiveHash.Sum(nil)); sum != hdr.ArchiveSHA256 {
+				return errors.Errorf("rebuilt agent binaries archive SHA-256 %s, expected %q", sum, hdr.ArchiveSHA256)
+			}
+			return errors.Trace(zw.Close())
+		default:
+			return errors.NotValidf("delta operation %q", op)
+		}
+	}
+}
+
+// validName reports whether name is a file name which can be safely
+// joined to a directory, as checked when agent binaries are unpacked.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\")
+}
+
+// baseReader reads the content of the base files as if concatenated.
+type baseReader struct {
+	files   []*os.File
+	offsets []int64
+	size    int64
+}
+
+func openBase(dir string, files []baseFile) (_ *baseReader, err error) {
+	base := &baseReader{}
+	defer func() {
+		if err != nil {
+			base.Close()
+		}
+	}()
+	for _, file := range files {
+		if !validName(file.Name) {
+			return nil, errors.NotValidf("base file name %q", file.Name)
+		}
+		f, err := os.Open(filepath.Join(dir, file.Name))
+		if err != nil {
+			return nil, errors.Annotate(err, "opening base agent binaries")
+		}
+		base.files = append(base.files, f)
+		info, err := f.Stat()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info.Size() != file.Size {
+			return nil, errors.Errorf("base file %q is %d bytes, expected %d", file.Name, info.Size(), file.Size)
+		}
+		base.offsets = append(base.offsets, base.size)
+		base.size += file.Size
+	}
+	return base, nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (b *baseReader) ReadAt(p []byte, off int64) (int, error) {
+	var read int
+	for i, f := range b.files {
+		end := b.size
+		if i+1 < len(b.offsets) {
+			end = b.offsets[i+1]
+		}
+		if off >= end || len(p) == 0 {
+			continue
+		}
+		want := p
+		if int64(len(want)) > end-off {
+			want = want[:end-off]
+		}
+		n, err := f.ReadAt(want, off-b.offsets[i])
+		read += n
+		if err != nil && err != io.EOF {
+			return read, err
+		}
+		if n < len(want) {
+			return read, io.ErrUnexpectedEOF
+		}
+		p = p[n:]
+		off += int64(n)
+	}
+	if len(p) > 0 {
+		return read, io.EOF
+	}
+	return read, nil
+}
+
+// Close closes the base files.
+func (b *baseReader) Close() {
+	for _, f := range b.files {
+		_ = f.Close()
+	}
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
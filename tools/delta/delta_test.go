@@ -0,0 +1,212 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package delta_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tools/delta"
+)
+
+type deltaSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&deltaSuite{})
+
+type file struct {
+	name    string
+	content []byte
+}
+
+// tarball returns the files in a gzipped tar archive, compressed at
+// the given level.
+func tarball(c *gc.C, level int, files ...file) []byte {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	c.Assert(err, jc.ErrorIsNil)
+	tw := tar.NewWriter(zw)
+	for _, f := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.name,
+			Mode:     0755,
+			Size:     int64(len(f.content)),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write(f.content)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(zw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+// generate returns the delta from the base to the target tarball, with
+// the target's SHA-256 hashes as recorded when it's stored.
+func generate(c *gc.C, base, target []byte) ([]byte, error) {
+	archiveSHA256, err := delta.ArchiveSHA256(bytes.NewReader(target))
+	c.Assert(err, jc.ErrorIsNil)
+	targetSHA256 := fmt.Sprintf("%x", sha256.Sum256(target))
+	return delta.Generate(bytes.NewReader(base), bytes.NewReader(target), targetSHA256, archiveSHA256)
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	data := make([]byte, n)
+	_, _ = r.Read(data)
+	return data
+}
+
+// unpack writes the files to a new directory, as an agent would have
+// unpacked them.
+func unpack(c *gc.C, files ...file) string {
+	dir := c.MkDir()
+	for _, f := range files {
+		err := os.WriteFile(filepath.Join(dir, f.name), f.content, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return dir
+}
+
+// versions returns the files of a base version and of a target version
+// which changes some of them.
+func versions() ([]file, []file) {
+	r := rand.New(rand.NewSource(0))
+	jujud := randomBytes(r, 512*1024)
+	jujuc := randomBytes(r, 64*1024)
+
+	newJujud := append([]byte(nil), jujud[:100000]...)
+	newJujud = append(newJujud, randomBytes(r, 3000)...)
+	newJujud = append(newJujud, jujud[100000:300000]...)
+	newJujud = append(newJujud, jujud[310000:]...)
+	for i := 400000; i < len(newJujud); i += 50000 {
+		newJujud[i] ^= 0xff
+	}
+
+	base := []file{{"jujud", jujud}, {"jujuc", jujuc}}
+	target := []file{{"jujud", newJujud}, {"jujuc", jujuc}, {"containeragent", randomBytes(r, 1000)}}
+	return base, target
+}
+
+func (s *deltaSuite) TestGenerateApply(c *gc.C) {
+	baseFiles, targetFiles := versions()
+	for _, level := range []int{gzip.DefaultCompression, gzip.BestCompression, gzip.BestSpeed} {
+		c.Logf("compression level %d", level)
+		base := tarball(c, gzip.DefaultCompression, baseFiles...)
+		target := tarball(c, level, targetFiles...)
+
+		d, err := generate(c, base, target)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(len(d) < len(target)/10, jc.IsTrue, gc.Commentf("delta is %d bytes, target %d", len(d), len(target)))
+
+		var rebuilt bytes.Buffer
+		err = delta.Apply(&rebuilt, bytes.NewReader(d), unpack(c, baseFiles...))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(rebuilt.Bytes(), jc.DeepEquals, target)
+	}
+}
+
+func (s *deltaSuite) TestGenerateIrreproducible(c *gc.C) {
+	// Random data isn't compressed at any level, so compressible
+	// content is needed for the compression level to matter.
+	content := bytes.Repeat([]byte("juju agent binaries "), 10000)
+	base := tarball(c, gzip.DefaultCompression, file{"jujud", content})
+	target := tarball(c, 3, file{"jujud", append(content, "changed"...)})
+
+	_, err := generate(c, base, target)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	c.Assert(err, gc.ErrorMatches, "delta of agent binaries which can't be compressed identically not supported")
+}
+
+func (s *deltaSuite) TestGenerateTooLarge(c *gc.C) {
+	r := rand.New(rand.NewSource(1))
+	base := tarball(c, gzip.DefaultCompression, file{"jujud", randomBytes(r, 64*1024)})
+	target := tarball(c, gzip.DefaultCompression, file{"jujud", randomBytes(r, 64*1024)})
+
+	_, err := generate(c, base, target)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	c.Assert(err, gc.ErrorMatches, "delta more than half the size of the agent binaries not supported")
+}
+
+func (s *deltaSuite) TestGenerateArchiveTooLarge(c *gc.C) {
+	s.PatchValue(delta.MaxArchiveSize, int64(64*1024))
+	baseFiles, targetFiles := versions()
+	base := tarball(c, gzip.DefaultCompression, baseFiles...)
+	target := tarball(c, gzip.DefaultCompression, targetFiles...)
+
+	_, err := generate(c, base, target)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	c.Assert(err, gc.ErrorMatches, "reading base agent binaries: delta of agent binaries larger than 65536 bytes not supported")
+
+	_, err = generate(c, tarball(c, gzip.DefaultCompression, file{"jujud", []byte("jujud")}), target)
+	c.Assert(err, jc.ErrorIs, errors.NotSupported)
+	c.Assert(err, gc.ErrorMatches, "delta of agent binaries larger than 65536 bytes not supported")
+}
+
+func (s *deltaSuite) TestGenerateWrongSHA256(c *gc.C) {
+	baseFiles, targetFiles := versions()
+	base := tarball(c, gzip.DefaultCompression, baseFiles...)
+	target := tarball(c, gzip.DefaultCompression, targetFiles...)
+	archiveSHA256, err := delta.ArchiveSHA256(bytes.NewReader(target))
+	c.Assert(err, jc.ErrorIsNil)
+	targetSHA256 := fmt.Sprintf("%x", sha256.Sum256(target))
+
+	_, err = delta.Generate(bytes.NewReader(base), bytes.NewReader(target), "abc", archiveSHA256)
+	c.Assert(err, gc.ErrorMatches, "target agent binaries SHA-256 [0-9a-f]+, expected abc")
+	_, err = delta.Generate(bytes.NewReader(base), bytes.NewReader(target), targetSHA256, "abc")
+	c.Assert(err, gc.ErrorMatches, "target agent binaries archive SHA-256 [0-9a-f]+, expected abc")
+}
+
+func (s *deltaSuite) TestApplyChangedBaseContent(c *gc.C) {
+	baseFiles, targetFiles := versions()
+	base := tarball(c, gzip.DefaultCompression, baseFiles...)
+	target := tarball(c, gzip.DefaultCompression, targetFiles...)
+	d, err := generate(c, base, target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A base file of the same size with different content rebuilds a
+	// different archive, which is rejected before it's compressed.
+	dir := unpack(c, baseFiles...)
+	err = os.WriteFile(filepath.Join(dir, "jujuc"), make([]byte, len(baseFiles[1].content)), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = delta.Apply(&bytes.Buffer{}, bytes.NewReader(d), dir)
+	c.Assert(err, gc.ErrorMatches, `rebuilt agent binaries archive SHA-256 [0-9a-f]+, expected "[0-9a-f]+"`)
+}
+
+func (s *deltaSuite) TestApplyChangedBase(c *gc.C) {
+	baseFiles, targetFiles := versions()
+	base := tarball(c, gzip.DefaultCompression, baseFiles...)
+	target := tarball(c, gzip.DefaultCompression, targetFiles...)
+	d, err := generate(c, base, target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dir := unpack(c, baseFiles...)
+	err = os.WriteFile(filepath.Join(dir, "jujuc"), []byte("changed"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = delta.Apply(&bytes.Buffer{}, bytes.NewReader(d), dir)
+	c.Assert(err, gc.ErrorMatches, `base file "jujuc" is 7 bytes, expected 65536`)
+
+	err = os.Remove(filepath.Join(dir, "jujuc"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = delta.Apply(&bytes.Buffer{}, bytes.NewReader(d), dir)
+	c.Assert(err, gc.ErrorMatches, "opening base agent binaries: .*no such file or directory")
+}
+
+func (s *deltaSuite) TestApplyNotDelta(c *gc.C) {
+	target := tarball(c, gzip.DefaultCompression, file{"jujud", []byte("jujud")})
+	err := delta.Apply(&bytes.Buffer{}, bytes.NewReader(target), c.MkDir())
+	c.Assert(err, jc.ErrorIs, errors.NotValid)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/juju/errors"
+)
+
+// blockSize is the length of the blocks of base data which are indexed
+// to find copies. Shorter blocks find more copies, at the cost of a
+// larger index.
+const blockSize = 64
+
+// hashPrime is the multiplier of the rolling hash.
+const hashPrime = 1099511628211
+
+// blockIndex maps the hash of each aligned block of the base data to the
+// block's offset. Colliding blocks are dropped, as matches are always
+// compared byte for byte.
+type blockIndex struct {
+	slots []int32
+	shift uint
+}
+
+func newBlockIndex(base []byte) *blockIndex {
+	blocks := len(base) / blockSize
+	size := bits.Len(uint(2*blocks + 1))
+	idx := &blockIndex{
+		slots: make([]int32, 1<<size),
+		shift: uint(64 - size),
+	}
+	for off := 0; off+blockSize <= len(base); off += blockSize {
+		slot := idx.slot(hashBlock(base[off : off+blockSize]))
+		if idx.slots[slot] == 0 {
+			// Offsets are stored plus one so that zero is empty.
+			idx.slots[slot] = int32(off + 1)
+		}
+	}
+	return idx
+}
+
+func (idx *blockIndex) slot(h uint64) uint64 {
+	return (h * 0x9e3779b97f4a7c15) >> idx.shift
+}
+
+func (idx *blockIndex) lookup(h uint64) (int, bool) {
+	off := idx.slots[idx.slot(h)]
+	return int(off - 1), off != 0
+}
+
+func hashBlock(block []byte) uint64 {
+	var h uint64
+	for _, b := range block {
+		h = h*hashPrime + uint64(b)
+	}
+	return h
+}
+
+// outFactor removes the contribution of the byte leaving the window
+// from the rolling hash.
+var outFactor = func() uint64 {
+	f := uint64(1)
+	for i := 0; i < blockSize-1; i++ {
+		f *= hashPrime
+	}
+	return f
+}()
+
+// encode writes the operations which build target from base to w.
+func encode(w *bufio.Writer, base, target []byte) error {
+	if len(base) > 1<<31-2 {
+		return errors.NotSupportedf("delta from agent binaries larger than 2GiB")
+	}
+	ops := &opWriter{w: w}
+	idx := newBlockIndex(base)
+
+	var (
+		h       uint64
+		hashed  bool
+		literal int
+	)
+	for i := 0; i+blockSize <= len(target); {
+		if !hashed {
+			h = hashBlock(target[i : i+blockSize])
+			hashed = true
+		}
+		if off, ok := idx.lookup(h); ok && bytes.Equal(base[off:off+blockSize], target[i:i+blockSize]) {
+			// Extend the match backwards over pending literal data,
+			// and forwards as far as it goes.
+			start, baseStart := i, off
+			for start > literal && baseStart > 0 && target[start-1] == base[baseStart-1] {
+				start--
+				baseStart--
+			}
+			end, baseEnd := i+blockSize, off+blockSize
+			for end < len(target) && baseEnd < len(base) && target[end] == base[baseEnd] {
+				end++
+				baseEnd++
+			}
+			ops.insert(target[literal:start])
+			ops.copy(baseStart, end-start)
+			i, literal, hashed = end, end, false
+			continue
+		}
+		if i+blockSize < len(target) {
+			h = (h-uint64(target[i])*outFactor)*hashPrime + uint64(target[i+blockSize])
+		}
+		i++
+	}
+	ops.insert(target[literal:])
+	ops.end()
+	return errors.Trace(ops.err)
+}
+
+// opWriter writes delta operations, holding on to the first error.
+type opWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (o *opWriter) copy(offset, length int) {
+	o.op(opCopy)
+	o.uvarint(uint64(offset))
+	o.uvarint(uint64(length))
+}
+
+func (o *opWriter) insert(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	o.op(opInsert)
+	o.uvarint(uint64(len(data)))
+	if o.err == nil {
+		_, o.err = o.w.Write(data)
+	}
+}
+
+func (o *opWriter) end() {
+	o.op(opEnd)
+}
+
+func (o *opWriter) op(op byte) {
+	if o.err == nil {
+		o.err = o.w.WriteByte(op)
+	}
+}
+
+func (o *opWriter) uvarint(v uint64) {
+	if o.err == nil {
+		n := binary.PutUvarint(o.buf[:], v)
+		_, o.err = o.w.Write(o.buf[:n])
+	}
+}
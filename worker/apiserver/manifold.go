@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"net/http"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/clock"
@@ -19,7 +20,9 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/authentication/macaroon"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/cmd/juju/commands"
+	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
 	coredatabase "github.com/juju/juju/core/database"
@@ -27,6 +30,7 @@ import (
 	"github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/gate"
 	workerstate "github.com/juju/juju/worker/state"
@@ -55,6 +59,7 @@ type ManifoldConfig struct {
 	Hub                               *pubsub.StructuredHub
 	Presence                          presence.Recorder
 
+	GetControllerConfig func(*state.StatePool) (jujucontroller.Config, error)
 	NewWorker           func(Config) (worker.Worker, error)
 	NewMetricsCollector func() *apiserver.Collector
 }
@@ -112,6 +117,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.Presence == nil {
 		return errors.NotValidf("nil Presence")
 	}
+	if config.GetControllerConfig == nil {
+		return errors.NotValidf("nil GetControllerConfig")
+	}
 	if config.NewWorker == nil {
 		return errors.NotValidf("nil NewWorker")
 	}
@@ -216,12 +224,6 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
-	// Register the metrics collector against the prometheus register.
-	metricsCollector := config.NewMetricsCollector()
-	if err := config.PrometheusRegisterer.Register(metricsCollector); err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	execEmbeddedCommand := func(ctx *cmd.Context, store jujuclient.ClientStore, whitelist []string, cmdPlusARgs string) int {
 		jujuCmd := commands.NewJujuCommandWithStore(ctx, store, nil, "", `Type "help" to see a list of commands`, whitelist, true)
 		return cmd.Main(jujuCmd, ctx, strings.Split(cmdPlusARgs, " "))
@@ -233,9 +235,44 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	controllerConfig, err := config.GetControllerConfig(statePool)
+	if err != nil {
+		_ = stTracker.Done()
+		return nil, errors.Annotate(err, "unable to get controller config")
+	}
+
+	// Register the metrics collector against the prometheus register.
+	metricsCollector := config.NewMetricsCollector()
+	if err := config.PrometheusRegisterer.Register(metricsCollector); err != nil {
+		_ = stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+
+	// The charmhub cache is shared by every charmhub client the facades
+	// create, and its hit/miss metrics are exported alongside ours.
+	agentConfig := agent.CurrentConfig()
+	cacheDir := controllerConfig.CharmhubCacheDir()
+	if !filepath.IsAbs(cacheDir) {
+		cacheDir = filepath.Join(agentConfig.DataDir(), cacheDir)
+	}
+	charmhubCache, err := charmhub.NewCache(charmhub.CacheConfig{
+		Dir:     cacheDir,
+		MaxSize: int64(controllerConfig.CharmhubCacheSizeMB()) << 20,
+		Clock:   clock,
+	})
+	if err != nil {
+		_ = stTracker.Done()
+		_ = config.PrometheusRegisterer.Unregister(metricsCollector)
+		return nil, errors.Trace(err)
+	}
+	if err := config.PrometheusRegisterer.Register(charmhubCache); err != nil {
+		_ = stTracker.Done()
+		_ = config.PrometheusRegisterer.Unregister(metricsCollector)
+		return nil, errors.Trace(err)
+	}
 
 	w, err := config.NewWorker(Config{
-		AgentConfig:                       agent.CurrentConfig(),
+		AgentConfig:                       agentConfig,
 		Clock:                             clock,
 		Mux:                               mux,
 		StatePool:                         statePool,
@@ -253,13 +290,15 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		EmbeddedCommand:                   execEmbeddedCommand,
 		SysLogger:                         sysLogger,
 		CharmhubHTTPClient:                charmhubHTTPClient,
+		CharmhubCache:                     charmhubCache,
 		DBGetter:                          dbGetter,
 	})
 	if err != nil {
 		// Ensure we clean up the resources we've registered with. This includes
-		// the state pool and the metrics collector.
+		// the state pool, the metrics collector and the charmhub cache.
 		_ = stTracker.Done()
 		_ = config.PrometheusRegisterer.Unregister(metricsCollector)
+		_ = config.PrometheusRegisterer.Unregister(charmhubCache)
 
 		return nil, errors.Trace(err)
 	}
@@ -268,8 +307,9 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		mux.ClientDone()
 
 		// Ensure we clean up the resources we've registered with. This includes
-		// the state pool and the metrics collector.
+		// the state pool, the metrics collector and the charmhub cache.
 		_ = stTracker.Done()
 		_ = config.PrometheusRegisterer.Unregister(metricsCollector)
+		_ = config.PrometheusRegisterer.Unregister(charmhubCache)
 	}), nil
 }
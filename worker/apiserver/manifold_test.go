@@ -5,6 +5,8 @@ package apiserver_test
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/clock/testclock"
@@ -61,6 +63,7 @@ type ManifoldSuite struct {
 	sysLogger            syslogger.SysLogger
 	charmhubHTTPClient   *http.Client
 	dbGetter             stubDBGetter
+	controllerConfig     controller.Config
 
 	stub testing.Stub
 }
@@ -70,7 +73,7 @@ var _ = gc.Suite(&ManifoldSuite{})
 func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 	s.IsolationSuite.SetUpTest(c)
 
-	s.agent = &mockAgent{}
+	s.agent = &mockAgent{conf: mockAgentConfig{dataDir: c.MkDir()}}
 	s.authenticator = &mockAuthenticator{}
 	s.clock = testclock.NewClock(time.Time{})
 	controller, err := cache.NewController(cache.ControllerConfig{
@@ -87,7 +90,9 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 	s.leaseManager = &lease.Manager{}
 	s.sysLogger = &mockSysLogger{}
 	s.charmhubHTTPClient = &http.Client{}
+	s.controllerConfig = nil
 	s.stub.ResetCalls()
+	s.prometheusRegisterer.ResetCalls()
 
 	s.context = s.newContext(nil)
 	s.manifold = apiserver.Manifold(apiserver.ManifoldConfig{
@@ -108,11 +113,20 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 		RegisterIntrospectionHTTPHandlers: func(func(string, http.Handler)) {},
 		Hub:                               &s.hub,
 		Presence:                          presence.New(s.clock),
+		GetControllerConfig:               s.getControllerConfig,
 		NewWorker:                         s.newWorker,
 		NewMetricsCollector:               s.newMetricsCollector,
 	})
 }
 
+func (s *ManifoldSuite) getControllerConfig(*state.StatePool) (controller.Config, error) {
+	s.stub.MethodCall(s, "GetControllerConfig")
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.controllerConfig, nil
+}
+
 func (s *ManifoldSuite) newContext(overlay map[string]interface{}) dependency.Context {
 	resources := map[string]interface{}{
 		"agent":                s.agent,
@@ -185,8 +199,8 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 	w := s.startWorkerClean(c)
 	workertest.CleanKill(c, w)
 
-	s.stub.CheckCallNames(c, "NewWorker")
-	args := s.stub.Calls()[0].Args
+	s.stub.CheckCallNames(c, "GetControllerConfig", "NewWorker")
+	args := s.stub.Calls()[1].Args
 	c.Assert(args, gc.HasLen, 1)
 	c.Assert(args[0], gc.FitsTypeOf, apiserver.Config{})
 	config := args[0].(apiserver.Config)
@@ -214,6 +228,10 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 	c.Assert(config.EmbeddedCommand, gc.NotNil)
 	config.EmbeddedCommand = nil
 
+	// The charmhub cache is created by the manifold.
+	c.Assert(config.CharmhubCache, gc.NotNil)
+	config.CharmhubCache = nil
+
 	c.Assert(config, jc.DeepEquals, apiserver.Config{
 		AgentConfig:                &s.agent.conf,
 		LocalMacaroonAuthenticator: s.authenticator,
@@ -231,6 +249,50 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 	})
 }
 
+func (s *ManifoldSuite) TestRegistersCharmhubCacheMetrics(c *gc.C) {
+	w := s.startWorkerClean(c)
+	defer workertest.CleanKill(c, w)
+
+	s.prometheusRegisterer.CheckCallNames(c, "Register", "Register")
+	args := s.stub.Calls()[1].Args
+	cache := args[0].(apiserver.Config).CharmhubCache
+	c.Assert(cache, gc.NotNil)
+	s.prometheusRegisterer.CheckCall(c, 0, "Register", s.metricsCollector)
+	s.prometheusRegisterer.CheckCall(c, 1, "Register", cache)
+
+	workertest.CleanKill(c, w)
+	s.prometheusRegisterer.CheckCallNames(c, "Register", "Register", "Unregister", "Unregister")
+	s.prometheusRegisterer.CheckCall(c, 3, "Unregister", cache)
+}
+
+func (s *ManifoldSuite) TestCharmhubCacheDirFromControllerConfig(c *gc.C) {
+	cacheDir := filepath.Join(c.MkDir(), "charmhub")
+	s.controllerConfig = controller.Config{
+		controller.CharmhubCacheDir: cacheDir,
+	}
+	w := s.startWorkerClean(c)
+	workertest.CleanKill(c, w)
+
+	_, err := os.Stat(cacheDir)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestCharmhubCacheDirRelativeToDataDir(c *gc.C) {
+	w := s.startWorkerClean(c)
+	workertest.CleanKill(c, w)
+
+	_, err := os.Stat(filepath.Join(s.agent.conf.dataDir, controller.DefaultCharmhubCacheDir))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestControllerConfigError(c *gc.C) {
+	s.stub.SetErrors(errors.New("boom"))
+	_, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.ErrorMatches, "unable to get controller config: boom")
+	s.state.CheckCallNames(c, "Use", "Done")
+	s.prometheusRegisterer.CheckNoCalls(c)
+}
+
 func (s *ManifoldSuite) TestStopWorkerClosesState(c *gc.C) {
 	w := s.startWorkerClean(c)
 	defer workertest.CleanKill(c, w)
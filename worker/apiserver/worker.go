@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/authentication/jwt"
 	"github.com/juju/juju/apiserver/authentication/macaroon"
+	"github.com/juju/juju/charmhub"
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
@@ -49,6 +50,7 @@ type Config struct {
 	MetricsCollector                  *apiserver.Collector
 	EmbeddedCommand                   apiserver.ExecEmbeddedCommandFunc
 	CharmhubHTTPClient                HTTPClient
+	CharmhubCache                     *charmhub.Cache
 	// DBGetter supplies sql.DB references on request, for named databases.
 	DBGetter coredatabase.DBGetter
 }
@@ -178,6 +180,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		ExecEmbeddedCommand:           config.EmbeddedCommand,
 		SysLogger:                     config.SysLogger,
 		CharmhubHTTPClient:            config.CharmhubHTTPClient,
+		CharmhubCache:                 config.CharmhubCache,
 		DBGetter:                      config.DBGetter,
 	}
 	return config.NewServer(serverConfig)
@@ -197,6 +200,16 @@ func gatherJWTAuthenticator(controllerConfig jujucontroller.Config) (jwt.Authent
 	return jwtAuthenticator, nil
 }
 
+// GetControllerConfig returns the controller config from the
+// pool's system state.
+func GetControllerConfig(pool *state.StatePool) (jujucontroller.Config, error) {
+	st, err := pool.SystemState()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.ControllerConfig()
+}
+
 func newServerShim(config apiserver.ServerConfig) (worker.Worker, error) {
 	return apiserver.NewServer(config)
 }
@@ -88,6 +88,46 @@ func NewAppWorker(config AppWorkerConfig) func() (worker.Worker, error) {
 	}
 }
 
+// downscaleStabilisationWindow matches the default scale down
+// stabilisation window of the kubernetes autoscaler.
+const downscaleStabilisationWindow = 300 * time.Second
+
+type scaleRecommendation struct {
+	scale int
+	at    time.Time
+}
+
+// newScaleStabiliser returns a function that smooths autoscaling
+// recommendations in the same way as the kubernetes autoscaler. Scaling up
+// is applied at once, but scaling down only goes as low as the highest
+// recommendation made within the window, so a brief dip in load doesn't
+// remove units. The application's scale when the first recommendation is
+// made counts as a recommendation too.
+func newScaleStabiliser(clk clock.Clock, window time.Duration) func(current, recommended int) int {
+	var history []scaleRecommendation
+	return func(current, recommended int) int {
+		now := clk.Now()
+		if history == nil {
+			history = []scaleRecommendation{{scale: current, at: now}}
+		}
+		var kept []scaleRecommendation
+		for _, r := range history {
+			if now.Sub(r.at) < window {
+				kept = append(kept, r)
+			}
+		}
+		history = append(kept, scaleRecommendation{scale: recommended, at: now})
+
+		stabilised := recommended
+		for _, r := range history {
+			if r.scale > stabilised {
+				stabilised = r.scale
+			}
+		}
+		return stabilised
+	}
+}
+
 func (a *appWorker) Notify() {
 	select {
 	case a.changes <- struct{}{}:
@@ -195,13 +235,19 @@ func (a *appWorker) loop() error {
 		scaleTries          int
 		trustChan           <-chan time.Time
 		trustTries          int
+		autoscaleChan       <-chan time.Time
 		reconcileDeadChan   <-chan time.Time
 		stateAppChangedChan <-chan time.Time
 	)
 	const (
 		maxRetries = 20
 		retryDelay = 3 * time.Second
+
+		// autoscaleInterval matches the default sync period of the
+		// kubernetes autoscaler.
+		autoscaleInterval = 15 * time.Second
 	)
+	stabiliseScale := newScaleStabiliser(a.clock, downscaleStabilisationWindow)
 
 	handleChange := func() error {
 		appLife, err = a.facade.Life(a.name)
@@ -347,7 +393,18 @@ func (a *appWorker) loop() error {
 				break
 			}
 			err := a.ops.EnsureTrust(a.name, app, a.unitFacade, a.logger)
-			if errors.Is(err, errors.NotFound) {
+			if err == nil {
+				// The trust hash watcher fires on any application config
+				// change, including the autoscaling policy.
+				var autoscaled bool
+				autoscaled, err = a.ops.EnsureAutoscaling(a.name, app, a.facade, a.logger)
+				if err == nil && !autoscaled {
+					autoscaleChan = nil
+				} else if err == nil && autoscaleChan == nil {
+					autoscaleChan = a.clock.After(0)
+				}
+			}
+			if errors.Is(err, errors.NotFound) || errors.Is(err, errors.NotProvisioned) {
 				if trustTries >= maxRetries {
 					return errors.Annotatef(err, "more than %d retries ensuring trust", maxRetries)
 				}
@@ -419,6 +476,14 @@ func (a *appWorker) loop() error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+		case <-autoscaleChan:
+			// The autoscaler only measures the workload, Juju applies
+			// its recommendation so units are removed cleanly.
+			if err := a.ops.SyncAutoscaledScale(a.name, app, a.unitFacade, stabiliseScale, a.logger); err != nil {
+				return errors.Trace(err)
+			}
+			autoscaleChan = a.clock.After(autoscaleInterval)
+			shouldRefresh = false
 		case <-a.clock.After(10 * time.Second):
 			// Force refresh of application status.
 		}
@@ -223,7 +223,11 @@ func (s *ApplicationWorkerSuite) TestWorker(c *gc.C) {
 
 		// trustChan fired
 		ops.EXPECT().EnsureTrust("test", app, unitFacade, s.logger).Return(errors.NotFound),
-		ops.EXPECT().EnsureTrust("test", app, unitFacade, s.logger).DoAndReturn(func(_, _, _, _ any) error {
+		ops.EXPECT().EnsureTrust("test", app, unitFacade, s.logger).Return(nil),
+		ops.EXPECT().EnsureAutoscaling("test", app, facade, s.logger).Return(true, nil),
+
+		// autoscaleChan fired
+		ops.EXPECT().SyncAutoscaledScale("test", app, unitFacade, gomock.Any(), s.logger).DoAndReturn(func(_, _, _, _, _ any) error {
 			appUnitsChan <- nil
 			return nil
 		}),
@@ -265,6 +269,8 @@ func (s *ApplicationWorkerSuite) TestWorker(c *gc.C) {
 			return nil
 		}),
 	)
+	// The autoscaling recommendation is applied periodically.
+	ops.EXPECT().SyncAutoscaledScale("test", app, unitFacade, gomock.Any(), s.logger).Return(nil).AnyTimes()
 
 	appWorker := s.startAppWorker(c, clk, facade, broker, unitFacade, ops, false)
 	s.waitDone(c, done)
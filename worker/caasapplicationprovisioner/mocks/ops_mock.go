@@ -85,6 +85,21 @@ func (mr *MockApplicationOpsMockRecorder) AppDying(arg0, arg1, arg2, arg3, arg4,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppDying", reflect.TypeOf((*MockApplicationOps)(nil).AppDying), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// EnsureAutoscaling mocks base method.
+func (m *MockApplicationOps) EnsureAutoscaling(arg0 string, arg1 caas.Application, arg2 caasapplicationprovisioner.CAASProvisionerFacade, arg3 caasapplicationprovisioner.Logger) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureAutoscaling", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureAutoscaling indicates an expected call of EnsureAutoscaling.
+func (mr *MockApplicationOpsMockRecorder) EnsureAutoscaling(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureAutoscaling", reflect.TypeOf((*MockApplicationOps)(nil).EnsureAutoscaling), arg0, arg1, arg2, arg3)
+}
+
 // EnsureScale mocks base method.
 func (m *MockApplicationOps) EnsureScale(arg0 string, arg1 caas.Application, arg2 life.Value, arg3 caasapplicationprovisioner.CAASProvisionerFacade, arg4 caasapplicationprovisioner.CAASUnitProvisionerFacade, arg5 caasapplicationprovisioner.Logger) error {
 	m.ctrl.T.Helper()
@@ -141,6 +156,20 @@ func (mr *MockApplicationOpsMockRecorder) RefreshApplicationStatus(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshApplicationStatus", reflect.TypeOf((*MockApplicationOps)(nil).RefreshApplicationStatus), arg0, arg1, arg2, arg3, arg4)
 }
 
+// SyncAutoscaledScale mocks base method.
+func (m *MockApplicationOps) SyncAutoscaledScale(arg0 string, arg1 caas.Application, arg2 caasapplicationprovisioner.CAASUnitProvisionerFacade, arg3 func(int, int) int, arg4 caasapplicationprovisioner.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncAutoscaledScale", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncAutoscaledScale indicates an expected call of SyncAutoscaledScale.
+func (mr *MockApplicationOpsMockRecorder) SyncAutoscaledScale(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncAutoscaledScale", reflect.TypeOf((*MockApplicationOps)(nil).SyncAutoscaledScale), arg0, arg1, arg2, arg3, arg4)
+}
+
 // UpdateState mocks base method.
 func (m *MockApplicationOps) UpdateState(arg0 string, arg1 caas.Application, arg2 map[string]status.StatusInfo, arg3 caasapplicationprovisioner.CAASBroker, arg4 caasapplicationprovisioner.CAASProvisionerFacade, arg5 caasapplicationprovisioner.CAASUnitProvisionerFacade, arg6 caasapplicationprovisioner.Logger) (map[string]status.StatusInfo, error) {
 	m.ctrl.T.Helper()
@@ -44,6 +44,12 @@ type ApplicationOps interface {
 	EnsureTrust(appName string, app caas.Application,
 		unitFacade CAASUnitProvisionerFacade, logger Logger) error
 
+	EnsureAutoscaling(appName string, app caas.Application,
+		facade CAASProvisionerFacade, logger Logger) (autoscaled bool, err error)
+
+	SyncAutoscaledScale(appName string, app caas.Application,
+		unitFacade CAASUnitProvisionerFacade, stabilise func(current, recommended int) int, logger Logger) error
+
 	UpdateState(appName string, app caas.Application, lastReportedStatus map[string]status.StatusInfo,
 		broker CAASBroker, facade CAASProvisionerFacade, unitFacade CAASUnitProvisionerFacade, logger Logger) (map[string]status.StatusInfo, error)
 
@@ -93,6 +99,16 @@ func (applicationOps) EnsureTrust(appName string, app caas.Application,
 	return ensureTrust(appName, app, unitFacade, logger)
 }
 
+func (applicationOps) EnsureAutoscaling(appName string, app caas.Application,
+	facade CAASProvisionerFacade, logger Logger) (bool, error) {
+	return ensureAutoscaling(appName, app, facade, logger)
+}
+
+func (applicationOps) SyncAutoscaledScale(appName string, app caas.Application,
+	unitFacade CAASUnitProvisionerFacade, stabilise func(current, recommended int) int, logger Logger) error {
+	return syncAutoscaledScale(appName, app, unitFacade, stabilise, logger)
+}
+
 func (applicationOps) UpdateState(appName string, app caas.Application, lastReportedStatus map[string]status.StatusInfo,
 	broker CAASBroker, facade CAASProvisionerFacade, unitFacade CAASUnitProvisionerFacade, logger Logger) (map[string]status.StatusInfo, error) {
 	return updateState(appName, app, lastReportedStatus, broker, facade, unitFacade, logger)
@@ -210,6 +226,8 @@ func appAlive(appName string, app caas.Application, password string, lastApplied
 		Containers:           containers,
 		CharmModifiedVersion: provisionInfo.CharmModifiedVersion,
 		Trust:                provisionInfo.Trust,
+		MaxUnavailable:       provisionInfo.MaxUnavailable,
+		SpreadZones:          provisionInfo.SpreadZones,
 		InitialScale:         provisionInfo.Scale,
 	}
 	switch ch.Meta().CharmUser {
@@ -411,6 +429,78 @@ func ensureTrust(appName string, app caas.Application,
 	return nil
 }
 
+// ensureAutoscaling applies the application's autoscaling policy to the
+// workload, removing any autoscaler when the policy is unset. It reports
+// whether the application is now autoscaled.
+func ensureAutoscaling(appName string, app caas.Application,
+	facade CAASProvisionerFacade, logger Logger) (bool, error) {
+	info, err := facade.ProvisioningInfo(appName)
+	if err != nil {
+		return false, errors.Annotatef(err, "fetching application %q autoscaling policy", appName)
+	}
+
+	var policy *caas.AutoscalingPolicy
+	if info.Autoscaling != "" {
+		policy, err = caas.ParseAutoscalingPolicy(info.Autoscaling)
+		if err != nil {
+			return false, errors.Annotatef(err, "parsing application %q autoscaling policy", appName)
+		}
+		logger.Debugf("updating application %q autoscaling policy to %q", appName, policy)
+	} else {
+		logger.Debugf("removing application %q autoscaling policy", appName)
+	}
+	if err := app.Autoscale(policy); err != nil {
+		return false, errors.Annotatef(err, "updating application %q autoscaling policy", appName)
+	}
+	return policy != nil, nil
+}
+
+// syncAutoscaledScale sets the application's scale to the number of units
+// recommended by its autoscaler, as smoothed by stabilise. The workload is
+// then scaled through the usual scaling path, which tears down units before
+// removing their pods.
+func syncAutoscaledScale(appName string, app caas.Application,
+	unitFacade CAASUnitProvisionerFacade, stabilise func(current, recommended int) int, logger Logger) error {
+	recommended, err := app.AutoscaleRecommendation()
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "fetching application %q autoscaling recommendation", appName)
+	}
+	desiredScale, err := unitFacade.ApplicationScale(appName)
+	if err != nil {
+		return errors.Annotatef(err, "fetching application %q scale", appName)
+	}
+	recommended = stabilise(desiredScale, recommended)
+	if recommended == desiredScale {
+		return nil
+	}
+	svc, err := app.Service()
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Infof("autoscaling application %q from %d to %d units", appName, desiredScale, recommended)
+	scale := recommended
+	err = unitFacade.UpdateApplicationService(params.UpdateApplicationServiceArg{
+		ApplicationTag: names.NewApplicationTag(appName).String(),
+		ProviderId:     svc.Id,
+		Addresses:      params.FromProviderAddresses(svc.Addresses...),
+		Scale:          &scale,
+	})
+	if errors.Is(err, errors.Forbidden) {
+		// A scale change is already in progress; the next
+		// recommendation will try again.
+		logger.Debugf("application %q scale not updated: %v", appName, err)
+		return nil
+	} else if errors.Is(err, errors.NotFound) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
 // updateState reports back information about the CAAS application into state, such as
 // status, IP addresses and volume info.
 func updateState(appName string, app caas.Application, lastReportedStatus map[string]status.StatusInfo,
@@ -4,6 +4,8 @@
 package caasapplicationprovisioner_test
 
 import (
+	"time"
+
 	"github.com/juju/charm/v12"
 	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
@@ -211,6 +213,134 @@ func (s *OpsSuite) TestEnsureTrust(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *OpsSuite) TestEnsureAutoscaling(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	facade := mocks.NewMockCAASProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	gomock.InOrder(
+		facade.EXPECT().ProvisioningInfo("test").Return(api.ProvisioningInfo{Autoscaling: "max=5,cpu=70"}, nil),
+		app.EXPECT().Autoscale(&caas.AutoscalingPolicy{MinUnits: 1, MaxUnits: 5, CPUPercent: 70}).Return(nil),
+	)
+
+	autoscaled, err := caasapplicationprovisioner.AppOps.EnsureAutoscaling("test", app, facade, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(autoscaled, jc.IsTrue)
+}
+
+func (s *OpsSuite) TestEnsureAutoscalingUnset(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	facade := mocks.NewMockCAASProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	gomock.InOrder(
+		facade.EXPECT().ProvisioningInfo("test").Return(api.ProvisioningInfo{}, nil),
+		app.EXPECT().Autoscale(nil).Return(nil),
+	)
+
+	autoscaled, err := caasapplicationprovisioner.AppOps.EnsureAutoscaling("test", app, facade, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(autoscaled, jc.IsFalse)
+}
+
+func (s *OpsSuite) TestSyncAutoscaledScale(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	unitFacade := mocks.NewMockCAASUnitProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	scale := 4
+	gomock.InOrder(
+		app.EXPECT().AutoscaleRecommendation().Return(4, nil),
+		unitFacade.EXPECT().ApplicationScale("test").Return(2, nil),
+		app.EXPECT().Service().Return(&caas.Service{Id: "provider-id"}, nil),
+		unitFacade.EXPECT().UpdateApplicationService(params.UpdateApplicationServiceArg{
+			ApplicationTag: "application-test",
+			ProviderId:     "provider-id",
+			Addresses:      params.FromProviderAddresses(),
+			Scale:          &scale,
+		}).Return(nil),
+	)
+
+	err := caasapplicationprovisioner.AppOps.SyncAutoscaledScale("test", app, unitFacade, noStabilisation, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OpsSuite) TestSyncAutoscaledScaleStabilised(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	unitFacade := mocks.NewMockCAASUnitProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	gomock.InOrder(
+		app.EXPECT().AutoscaleRecommendation().Return(2, nil),
+		unitFacade.EXPECT().ApplicationScale("test").Return(4, nil),
+	)
+
+	stabilise := func(current, recommended int) int {
+		c.Check(current, gc.Equals, 4)
+		c.Check(recommended, gc.Equals, 2)
+		return current
+	}
+	err := caasapplicationprovisioner.AppOps.SyncAutoscaledScale("test", app, unitFacade, stabilise, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OpsSuite) TestScaleStabiliser(c *gc.C) {
+	clk := testclock.NewClock(time.Time{})
+	stabilise := caasapplicationprovisioner.NewScaleStabiliser(clk, 5*time.Minute)
+
+	// The scale when autoscaling starts holds off scaling down.
+	c.Check(stabilise(4, 2), gc.Equals, 4)
+	clk.Advance(time.Minute)
+	c.Check(stabilise(4, 3), gc.Equals, 4)
+
+	// Scaling up is immediate.
+	clk.Advance(time.Minute)
+	c.Check(stabilise(4, 6), gc.Equals, 6)
+
+	// Scaling down waits for the higher recommendations to leave the window.
+	clk.Advance(4 * time.Minute)
+	c.Check(stabilise(6, 2), gc.Equals, 6)
+	clk.Advance(time.Minute)
+	c.Check(stabilise(6, 2), gc.Equals, 2)
+}
+
+func (s *OpsSuite) TestSyncAutoscaledScaleUnchanged(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	unitFacade := mocks.NewMockCAASUnitProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	gomock.InOrder(
+		app.EXPECT().AutoscaleRecommendation().Return(2, nil),
+		unitFacade.EXPECT().ApplicationScale("test").Return(2, nil),
+	)
+
+	err := caasapplicationprovisioner.AppOps.SyncAutoscaledScale("test", app, unitFacade, noStabilisation, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OpsSuite) TestSyncAutoscaledScaleNotAutoscaled(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	unitFacade := mocks.NewMockCAASUnitProvisionerFacade(ctrl)
+	app := caasmocks.NewMockApplication(ctrl)
+
+	app.EXPECT().AutoscaleRecommendation().Return(0, errors.NotFoundf("autoscaler"))
+
+	err := caasapplicationprovisioner.AppOps.SyncAutoscaledScale("test", app, unitFacade, noStabilisation, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *OpsSuite) TestUpdateState(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -706,3 +836,7 @@ func (s *OpsSuite) TestAppDead(c *gc.C) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func noStabilisation(current, recommended int) int {
+	return recommended
+}
@@ -15,3 +15,4 @@ func TestPackage(t *stdtesting.T) {
 
 var NewProvisionerWorkerForTest = newProvisionerWorker
 var AppOps = &applicationOps{}
+var NewScaleStabiliser = newScaleStabiliser
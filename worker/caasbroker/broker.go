@@ -5,10 +5,15 @@ package caasbroker
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v3/catacomb"
+	"github.com/juju/worker/v3/dependency"
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/controller"
@@ -34,6 +39,17 @@ type ConfigAPI interface {
 	WatchCloudSpecChanges() (watcher.NotifyWatcher, error)
 }
 
+// CredentialAPI exposes the methods used to report the health of the
+// model's cluster and to invalidate a cloud credential it rejects.
+type CredentialAPI interface {
+	InvalidateModelCredential(reason string) error
+	ReportClusterHealth(endpoint string, unhealthy []string, healthErr error) error
+}
+
+// ClusterHealthCheckInterval is how often the health of the cluster is
+// checked, for brokers which can fail over between API endpoints.
+const ClusterHealthCheckInterval = time.Minute
+
 // Config describes the dependencies of a Tracker.
 //
 // It's arguable that it should be called TrackerConfig, because of the heavy
@@ -42,6 +58,11 @@ type Config struct {
 	ConfigAPI              ConfigAPI
 	NewContainerBrokerFunc caas.NewContainerBrokerFunc
 	Logger                 Logger
+
+	// CredentialAPI and Clock are used to periodically check the health
+	// of the cluster. If CredentialAPI is nil, no checks are made.
+	CredentialAPI CredentialAPI
+	Clock         clock.Clock
 }
 
 // Validate returns an error if the config cannot be used to start a Tracker.
@@ -55,6 +76,9 @@ func (config Config) Validate() error {
 	if config.Logger == nil {
 		return errors.NotValidf("nil Logger")
 	}
+	if config.CredentialAPI != nil && config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
 	return nil
 }
 
@@ -65,6 +89,14 @@ type Tracker struct {
 	catacomb         catacomb.Catacomb
 	broker           caas.Broker
 	currentCloudSpec environscloudspec.CloudSpec
+
+	// endpoint is the cluster API endpoint the broker was using when
+	// the tracker started, for brokers which can fail over.
+	endpoint string
+
+	// credentialInvalidated is set once the cloud credential has been
+	// invalidated because the cluster rejected it.
+	credentialInvalidated bool
 }
 
 // NewTracker returns a new Tracker, or an error if anything goes wrong.
@@ -102,6 +134,9 @@ func NewTracker(config Config) (*Tracker, error) {
 		broker:           broker,
 		currentCloudSpec: cloudSpec,
 	}
+	if checker, ok := broker.(caas.ClusterHealthChecker); ok {
+		t.endpoint = checker.ClusterEndpoint()
+	}
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &t.catacomb,
 		Work: t.loop,
@@ -148,6 +183,26 @@ func (t *Tracker) loop() error {
 		cloudWatcherChanges = cloudWatcher.Changes()
 	}
 
+	// Some brokers can fail over between several API endpoints for the
+	// cluster. Check their health periodically while they have failover
+	// endpoints configured.
+	var (
+		healthChecker caas.ClusterHealthChecker
+		healthCheck   <-chan time.Time
+	)
+	if t.config.CredentialAPI != nil {
+		healthChecker, _ = t.broker.(caas.ClusterHealthChecker)
+	}
+	scheduleHealthCheck := func() {
+		switch {
+		case healthChecker == nil || !healthChecker.HasFailoverEndpoints():
+			healthCheck = nil
+		case healthCheck == nil:
+			healthCheck = t.config.Clock.After(0)
+		}
+	}
+	scheduleHealthCheck()
+
 	for {
 		logger.Debugf("waiting for config and credential notifications")
 		select {
@@ -165,6 +220,10 @@ func (t *Tracker) loop() error {
 			if err = t.broker.SetConfig(modelConfig); err != nil {
 				return errors.Annotate(err, "cannot update model config")
 			}
+			if err := t.checkEndpoint(); err != nil {
+				return errors.Trace(err)
+			}
+			scheduleHealthCheck()
 		case _, ok := <-cloudWatcherChanges:
 			if !ok {
 				return errors.New("cloud watch closed")
@@ -181,10 +240,71 @@ func (t *Tracker) loop() error {
 				return errors.Annotate(err, "cannot update broker cloud spec")
 			}
 			t.currentCloudSpec = cloudSpec
+			t.credentialInvalidated = false
+			if err := t.checkEndpoint(); err != nil {
+				return errors.Trace(err)
+			}
+		case <-healthCheck:
+			t.checkClusterHealth(healthChecker)
+			if err := t.checkEndpoint(); err != nil {
+				return errors.Trace(err)
+			}
+			healthCheck = t.config.Clock.After(ClusterHealthCheckInterval)
 		}
 	}
 }
 
+// checkEndpoint returns dependency.ErrBounce if the broker has switched to
+// another cluster API endpoint. Watchers started by workers using the broker
+// keep the endpoint they were started with, so restarting the tracker makes
+// the engine restart them too.
+func (t *Tracker) checkEndpoint() error {
+	checker, ok := t.broker.(caas.ClusterHealthChecker)
+	if !ok {
+		return nil
+	}
+	if endpoint := checker.ClusterEndpoint(); endpoint != t.endpoint {
+		t.config.Logger.Warningf("cluster API endpoint changed from %q to %q, restarting", t.endpoint, endpoint)
+		return dependency.ErrBounce
+	}
+	return nil
+}
+
+// checkClusterHealth checks the health of the cluster's API endpoints and
+// reports it to the controller. Failures are logged rather than returned,
+// so that an unhealthy cluster doesn't stop the broker from being used.
+func (t *Tracker) checkClusterHealth(checker caas.ClusterHealthChecker) {
+	logger := t.config.Logger
+	health, healthErr := checker.CheckClusterHealth()
+	if errors.Is(healthErr, errors.Unauthorized) {
+		if t.credentialInvalidated {
+			return
+		}
+		logger.Warningf("invalidating cloud credential: %v", healthErr)
+		if err := t.config.CredentialAPI.InvalidateModelCredential(healthErr.Error()); err != nil {
+			logger.Warningf("cannot invalidate cloud credential: %v", err)
+			return
+		}
+		t.credentialInvalidated = true
+		return
+	}
+	if healthErr != nil {
+		logger.Warningf("cluster is unhealthy: %v", healthErr)
+	} else {
+		t.credentialInvalidated = false
+	}
+
+	var unhealthy []string
+	for endpoint, reason := range health.Unhealthy {
+		unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", endpoint, reason))
+	}
+	sort.Strings(unhealthy)
+	err := t.config.CredentialAPI.ReportClusterHealth(health.Endpoint, unhealthy, healthErr)
+	if err != nil && !errors.Is(err, errors.NotSupported) {
+		logger.Warningf("cannot report cluster health: %v", err)
+	}
+}
+
 // Kill is part of the worker.Worker interface.
 func (t *Tracker) Kill() {
 	t.catacomb.Kill(nil)
@@ -7,10 +7,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/v3"
+	"github.com/juju/worker/v3/dependency"
 	"github.com/juju/worker/v3/workertest"
 	gc "gopkg.in/check.v1"
 
@@ -64,6 +67,15 @@ func (s *TrackerSuite) TestValidateLogger(c *gc.C) {
 	})
 }
 
+func (s *TrackerSuite) TestValidateClock(c *gc.C) {
+	config := s.validConfig()
+	config.CredentialAPI = newMockCredentialAPI()
+	s.testValidate(c, config, func(err error) {
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, "nil Clock not valid")
+	})
+}
+
 func (s *TrackerSuite) testValidate(c *gc.C, config caasbroker.Config, check func(err error)) {
 	err := config.Validate()
 	check(err)
@@ -393,3 +405,170 @@ func (s *TrackerSuite) TestWatchedCloudSpecUpdates(c *gc.C) {
 		}
 	})
 }
+
+func (s *TrackerSuite) assertCredentialCall(c *gc.C, credentialAPI *mockCredentialAPI, expected testing.StubCall) {
+	select {
+	case call := <-credentialAPI.calls:
+		c.Assert(call, jc.DeepEquals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %s call", expected.FuncName)
+	}
+}
+
+func (s *TrackerSuite) TestClusterHealthReported(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(runContext *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		credentialAPI := newMockCredentialAPI()
+		var broker *mockHealthBroker
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI: runContext,
+			NewContainerBrokerFunc: func(ctx context.Context, args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(ctx, args)
+				broker = &mockHealthBroker{
+					mockBroker: mock.(*mockBroker),
+					health: caas.ClusterHealth{
+						Endpoint:  "https://10.0.0.2:6443",
+						Unhealthy: map[string]string{"https://10.0.0.1:6443": "connection refused"},
+					},
+					endpoint: "https://10.0.0.2:6443",
+					failover: true,
+				}
+				return broker, err
+			},
+			Logger:        loggo.GetLogger("test"),
+			CredentialAPI: credentialAPI,
+			Clock:         clock,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		s.assertCredentialCall(c, credentialAPI, testing.StubCall{
+			FuncName: "ReportClusterHealth",
+			Args:     []interface{}{"https://10.0.0.2:6443", []string{"https://10.0.0.1:6443: connection refused"}, ""},
+		})
+
+		broker.setHealth(caas.ClusterHealth{}, errors.New("no healthy cluster API endpoint"))
+		err = clock.WaitAdvance(caasbroker.ClusterHealthCheckInterval, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertCredentialCall(c, credentialAPI, testing.StubCall{
+			FuncName: "ReportClusterHealth",
+			Args:     []interface{}{"", []string(nil), "no healthy cluster API endpoint"},
+		})
+	})
+}
+
+func (s *TrackerSuite) TestClusterCredentialRejected(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(runContext *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		credentialAPI := newMockCredentialAPI()
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI: runContext,
+			NewContainerBrokerFunc: func(ctx context.Context, args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(ctx, args)
+				return &mockHealthBroker{
+					mockBroker: mock.(*mockBroker),
+					healthErr:  errors.Unauthorizedf("cloud credential rejected"),
+					failover:   true,
+				}, err
+			},
+			Logger:        loggo.GetLogger("test"),
+			CredentialAPI: credentialAPI,
+			Clock:         clock,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		s.assertCredentialCall(c, credentialAPI, testing.StubCall{
+			FuncName: "InvalidateModelCredential",
+			Args:     []interface{}{"cloud credential rejected"},
+		})
+
+		// The credential is only invalidated once.
+		err = clock.WaitAdvance(caasbroker.ClusterHealthCheckInterval, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		err = clock.WaitAdvance(caasbroker.ClusterHealthCheckInterval, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case call := <-credentialAPI.calls:
+			c.Fatalf("unexpected %s call", call.FuncName)
+		default:
+		}
+	})
+}
+
+func (s *TrackerSuite) TestClusterHealthNotCheckedWithoutFailover(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(runContext *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		credentialAPI := newMockCredentialAPI()
+		var broker *mockHealthBroker
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI: runContext,
+			NewContainerBrokerFunc: func(ctx context.Context, args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(ctx, args)
+				broker = &mockHealthBroker{
+					mockBroker: mock.(*mockBroker),
+					health:     caas.ClusterHealth{Endpoint: "https://10.0.0.1:6443"},
+					endpoint:   "https://10.0.0.1:6443",
+				}
+				return broker, err
+			},
+			Logger:        loggo.GetLogger("test"),
+			CredentialAPI: credentialAPI,
+			Clock:         clock,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		select {
+		case call := <-credentialAPI.calls:
+			c.Fatalf("unexpected %s call", call.FuncName)
+		case <-time.After(coretesting.ShortWait):
+		}
+
+		// Configuring failover endpoints starts the health checks.
+		broker.setFailover(true)
+		runContext.SendModelConfigNotify()
+		s.assertCredentialCall(c, credentialAPI, testing.StubCall{
+			FuncName: "ReportClusterHealth",
+			Args:     []interface{}{"https://10.0.0.1:6443", []string(nil), ""},
+		})
+	})
+}
+
+func (s *TrackerSuite) TestClusterEndpointChangeBounces(c *gc.C) {
+	fix := s.validFixture()
+	fix.Run(c, func(runContext *runContext) {
+		clock := testclock.NewClock(time.Time{})
+		credentialAPI := newMockCredentialAPI()
+		tracker, err := caasbroker.NewTracker(caasbroker.Config{
+			ConfigAPI: runContext,
+			NewContainerBrokerFunc: func(ctx context.Context, args environs.OpenParams) (caas.Broker, error) {
+				mock, err := newMockBroker(ctx, args)
+				return &mockHealthBroker{
+					mockBroker: mock.(*mockBroker),
+					health: caas.ClusterHealth{
+						Endpoint:  "https://10.0.0.2:6443",
+						Unhealthy: map[string]string{"https://10.0.0.1:6443": "connection refused"},
+					},
+					endpoint: "https://10.0.0.1:6443",
+					failover: true,
+				}, err
+			},
+			Logger:        loggo.GetLogger("test"),
+			CredentialAPI: credentialAPI,
+			Clock:         clock,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		defer workertest.DirtyKill(c, tracker)
+
+		s.assertCredentialCall(c, credentialAPI, testing.StubCall{
+			FuncName: "ReportClusterHealth",
+			Args:     []interface{}{"https://10.0.0.2:6443", []string{"https://10.0.0.1:6443: connection refused"}, ""},
+		})
+		err = workertest.CheckKilled(c, tracker)
+		c.Assert(errors.Is(err, dependency.ErrBounce), jc.IsTrue)
+	})
+}
@@ -262,3 +262,68 @@ func (e *mockBroker) SetConfig(cfg *config.Config) error {
 	e.cfg = cfg
 	return nil
 }
+
+type mockHealthBroker struct {
+	*mockBroker
+	health    caas.ClusterHealth
+	healthErr error
+	endpoint  string
+	failover  bool
+}
+
+func (e *mockHealthBroker) CheckClusterHealth() (caas.ClusterHealth, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.MethodCall(e, "CheckClusterHealth")
+	if e.healthErr == nil {
+		e.endpoint = e.health.Endpoint
+	}
+	return e.health, e.healthErr
+}
+
+func (e *mockHealthBroker) ClusterEndpoint() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.endpoint
+}
+
+func (e *mockHealthBroker) HasFailoverEndpoints() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.failover
+}
+
+func (e *mockHealthBroker) setFailover(failover bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failover = failover
+}
+
+func (e *mockHealthBroker) setHealth(health caas.ClusterHealth, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.health = health
+	e.healthErr = err
+}
+
+type mockCredentialAPI struct {
+	calls chan testing.StubCall
+}
+
+func newMockCredentialAPI() *mockCredentialAPI {
+	return &mockCredentialAPI{calls: make(chan testing.StubCall, 10)}
+}
+
+func (a *mockCredentialAPI) InvalidateModelCredential(reason string) error {
+	a.calls <- testing.StubCall{FuncName: "InvalidateModelCredential", Args: []interface{}{reason}}
+	return nil
+}
+
+func (a *mockCredentialAPI) ReportClusterHealth(endpoint string, unhealthy []string, healthErr error) error {
+	var errMessage string
+	if healthErr != nil {
+		errMessage = healthErr.Error()
+	}
+	a.calls <- testing.StubCall{FuncName: "ReportClusterHealth", Args: []interface{}{endpoint, unhealthy, errMessage}}
+	return nil
+}
@@ -4,11 +4,13 @@
 package caasbroker
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v3"
 	"github.com/juju/worker/v3/dependency"
 
 	"github.com/juju/juju/api/agent/caasagent"
+	"github.com/juju/juju/api/agent/credentialvalidator"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/environs"
@@ -28,6 +30,18 @@ type ManifoldConfig struct {
 	APICallerName          string
 	NewContainerBrokerFunc caas.NewContainerBrokerFunc
 	Logger                 Logger
+
+	// NewCredentialAPI and Clock are used to periodically check the
+	// health of the cluster. If NewCredentialAPI is nil, no checks
+	// are made.
+	NewCredentialAPI func(base.APICaller) CredentialAPI
+	Clock            clock.Clock
+}
+
+// NewCredentialAPI returns a CredentialAPI backed by the
+// CredentialValidator facade.
+func NewCredentialAPI(apiCaller base.APICaller) CredentialAPI {
+	return credentialvalidator.NewFacade(apiCaller)
 }
 
 // Manifold returns a Manifold that encapsulates a *Tracker and exposes it as
@@ -47,10 +61,16 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			var credentialAPI CredentialAPI
+			if config.NewCredentialAPI != nil {
+				credentialAPI = config.NewCredentialAPI(apiCaller)
+			}
 			w, err := NewTracker(Config{
 				ConfigAPI:              api,
 				NewContainerBrokerFunc: config.NewContainerBrokerFunc,
 				Logger:                 config.Logger,
+				CredentialAPI:          credentialAPI,
+				Clock:                  config.Clock,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/worker/caasevents (interfaces: CAASBroker)
+//
+// Generated by this command:
+//
+//	mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasevents CAASBroker
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	caas "github.com/juju/juju/caas"
+	watcher "github.com/juju/juju/core/watcher"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCAASBroker is a mock of CAASBroker interface.
+type MockCAASBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCAASBrokerMockRecorder
+}
+
+// MockCAASBrokerMockRecorder is the mock recorder for MockCAASBroker.
+type MockCAASBrokerMockRecorder struct {
+	mock *MockCAASBroker
+}
+
+// NewMockCAASBroker creates a new mock instance.
+func NewMockCAASBroker(ctrl *gomock.Controller) *MockCAASBroker {
+	mock := &MockCAASBroker{ctrl: ctrl}
+	mock.recorder = &MockCAASBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCAASBroker) EXPECT() *MockCAASBrokerMockRecorder {
+	return m.recorder
+}
+
+// Events mocks base method.
+func (m *MockCAASBroker) Events() ([]caas.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].([]caas.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockCAASBrokerMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockCAASBroker)(nil).Events))
+}
+
+// WatchEvents mocks base method.
+func (m *MockCAASBroker) WatchEvents() (watcher.NotifyWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchEvents")
+	ret0, _ := ret[0].(watcher.NotifyWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchEvents indicates an expected call of WatchEvents.
+func (mr *MockCAASBrokerMockRecorder) WatchEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchEvents", reflect.TypeOf((*MockCAASBroker)(nil).WatchEvents))
+}
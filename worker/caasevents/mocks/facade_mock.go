@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/juju/juju/worker/caasevents (interfaces: Facade)
+//
+// Generated by this command:
+//
+//	mockgen -package mocks -destination mocks/facade_mock.go github.com/juju/juju/worker/caasevents Facade
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	params "github.com/juju/juju/rpc/params"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFacade is a mock of Facade interface.
+type MockFacade struct {
+	ctrl     *gomock.Controller
+	recorder *MockFacadeMockRecorder
+}
+
+// MockFacadeMockRecorder is the mock recorder for MockFacade.
+type MockFacadeMockRecorder struct {
+	mock *MockFacade
+}
+
+// NewMockFacade creates a new mock instance.
+func NewMockFacade(ctrl *gomock.Controller) *MockFacade {
+	mock := &MockFacade{ctrl: ctrl}
+	mock.recorder = &MockFacadeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFacade) EXPECT() *MockFacadeMockRecorder {
+	return m.recorder
+}
+
+// RecordUnitEvents mocks base method.
+func (m *MockFacade) RecordUnitEvents(arg0 []params.CAASUnitEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordUnitEvents", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordUnitEvents indicates an expected call of RecordUnitEvents.
+func (mr *MockFacadeMockRecorder) RecordUnitEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordUnitEvents", reflect.TypeOf((*MockFacade)(nil).RecordUnitEvents), arg0)
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasevents
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v5"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/catacomb"
+
+	"github.com/juju/juju/api/base"
+	api "github.com/juju/juju/api/controller/caasmodelevents"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/rpc/params"
+)
+
+// RateLimitInterval is the minimum time between reports of repeated
+// events with the same reason for the same object. Repeats within the
+// interval are counted and reported once it has passed.
+const RateLimitInterval = time.Minute
+
+// Logger represents the methods used by the worker to log details.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Warningf(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/facade_mock.go github.com/juju/juju/worker/caasevents Facade
+type Facade interface {
+	RecordUnitEvents([]params.CAASUnitEvent) error
+}
+
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasevents CAASBroker
+type CAASBroker interface {
+	caas.EventWatcher
+}
+
+// Config holds the configuration and dependencies for a worker.
+type Config struct {
+	Facade Facade
+	Broker CAASBroker
+	Logger Logger
+	Clock  clock.Clock
+}
+
+// Validate returns an error if the config cannot be expected
+// to drive a functional worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("Facade is missing")
+	}
+	if config.Broker == nil {
+		return errors.NotValidf("Broker is missing")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("Logger is missing")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("Clock is missing")
+	}
+	return nil
+}
+
+// limit tracks the reports of events with the same reason for the
+// same object.
+type limit struct {
+	reported   time.Time
+	suppressed int32
+	last       caas.Event
+}
+
+type eventsWorker struct {
+	catacomb catacomb.Catacomb
+
+	config Config
+	logger Logger
+	clock  clock.Clock
+
+	// seen holds the count of each event which has been handled.
+	seen   map[string]int32
+	limits map[string]*limit
+}
+
+// NewFacade returns a facade for the caasevents worker to use.
+func NewFacade(caller base.APICaller) (Facade, error) {
+	return api.NewClient(caller)
+}
+
+// NewWorker returns a worker which reports the events recorded by
+// the cloud against the model's workloads. Events are written to the
+// model's log, and warnings are also added to the status history of
+// the unit they relate to.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &eventsWorker{
+		config: config,
+		logger: config.Logger,
+		clock:  config.Clock,
+		seen:   make(map[string]int32),
+		limits: make(map[string]*limit),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *eventsWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *eventsWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *eventsWorker) loop() error {
+	watcher, err := w.config.Broker.WatchEvents()
+	if err != nil {
+		return errors.Annotate(err, "watching events")
+	}
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Events which occurred before the worker started have either been
+	// reported already or are stale, so they are not reported again.
+	started := w.clock.Now()
+	initial := true
+	flush := w.clock.After(RateLimitInterval)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("events watcher closed")
+			}
+			events, err := w.config.Broker.Events()
+			if err != nil {
+				return errors.Annotate(err, "getting events")
+			}
+			if initial {
+				w.ignoreEventsBefore(events, started)
+				initial = false
+			}
+			w.report(w.handleEvents(events))
+		case <-flush:
+			w.report(w.flushSuppressed())
+			flush = w.clock.After(RateLimitInterval)
+		}
+	}
+}
+
+func (w *eventsWorker) ignoreEventsBefore(events []caas.Event, t time.Time) {
+	for _, event := range events {
+		if event.Time.Before(t) {
+			w.seen[event.UID] = event.Count
+		}
+	}
+}
+
+// handleEvents returns the events which haven't been handled before and
+// aren't suppressed by the rate limit.
+func (w *eventsWorker) handleEvents(events []caas.Event) []caas.Event {
+	now := w.clock.Now()
+	current := make(map[string]int32, len(events))
+	var result []caas.Event
+	for _, event := range events {
+		current[event.UID] = event.Count
+		occurred := event.Count - w.seen[event.UID]
+		if occurred <= 0 {
+			continue
+		}
+
+		key := eventKey(event)
+		l, ok := w.limits[key]
+		if !ok {
+			l = &limit{}
+			w.limits[key] = l
+		}
+		if now.Sub(l.reported) < RateLimitInterval {
+			l.suppressed += occurred
+			l.last = event
+			continue
+		}
+		event.Count = occurred + l.suppressed
+		l.reported = now
+		l.suppressed = 0
+		result = append(result, event)
+	}
+	// Forget about events which have expired from the cloud.
+	w.seen = current
+	return result
+}
+
+// flushSuppressed returns a summary of the events suppressed by the rate
+// limit which are due to be reported, and forgets about limits which
+// haven't been needed for a while.
+func (w *eventsWorker) flushSuppressed() []caas.Event {
+	now := w.clock.Now()
+	var result []caas.Event
+	for key, l := range w.limits {
+		if now.Sub(l.reported) < RateLimitInterval {
+			continue
+		}
+		if l.suppressed == 0 {
+			delete(w.limits, key)
+			continue
+		}
+		event := l.last
+		event.Count = l.suppressed
+		l.reported = now
+		l.suppressed = 0
+		result = append(result, event)
+	}
+	return result
+}
+
+func (w *eventsWorker) report(events []caas.Event) {
+	var unitEvents []params.CAASUnitEvent
+	for _, event := range events {
+		entity := event.ApplicationName
+		if event.UnitName != "" {
+			entity = event.UnitName
+		}
+		message := event.Message
+		if event.Count > 1 {
+			message = fmt.Sprintf("%s (%d times)", message, event.Count)
+		}
+		if !event.IsWarning() {
+			w.logger.Debugf("%s: %s %s: %s: %s",
+				entity, strings.ToLower(event.ObjectKind), event.ObjectName, event.Reason, message)
+			continue
+		}
+		w.logger.Warningf("%s: %s %s: %s: %s",
+			entity, strings.ToLower(event.ObjectKind), event.ObjectName, event.Reason, message)
+		if !names.IsValidUnit(event.UnitName) {
+			continue
+		}
+		when := event.Time
+		if when.IsZero() {
+			when = w.clock.Now()
+		}
+		unitEvents = append(unitEvents, params.CAASUnitEvent{
+			UnitTag: names.NewUnitTag(event.UnitName).String(),
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: message,
+			Count:   event.Count,
+			Time:    when,
+		})
+	}
+	if len(unitEvents) == 0 {
+		return
+	}
+	// Units may be removed while their events are reported, so failing
+	// to record events isn't fatal to the worker.
+	if err := w.config.Facade.RecordUnitEvents(unitEvents); err != nil {
+		w.logger.Errorf("recording unit events: %v", err)
+	}
+}
+
+func eventKey(event caas.Event) string {
+	return strings.Join([]string{event.ObjectKind, event.ObjectName, event.Reason}, "/")
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package caasevents_test
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/workertest"
+	"go.uber.org/mock/gomock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/rpc/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/caasevents"
+	"github.com/juju/juju/worker/caasevents/mocks"
+)
+
+var _ = gc.Suite(&workerSuite{})
+
+type workerSuite struct {
+	testing.IsolationSuite
+
+	logger  *recordingLogger
+	clock   *testclock.Clock
+	start   time.Time
+	changes chan struct{}
+
+	facade *mocks.MockFacade
+	broker *mocks.MockCAASBroker
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.logger = &recordingLogger{}
+	s.start = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.clock = testclock.NewClock(s.start)
+	s.changes = make(chan struct{})
+}
+
+func (s *workerSuite) TestConfigValidate(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+
+	cfg := caasevents.Config{}
+	c.Check(cfg.Validate(), gc.ErrorMatches, `Facade is missing not valid`)
+
+	cfg.Facade = s.facade
+	c.Check(cfg.Validate(), gc.ErrorMatches, `Broker is missing not valid`)
+
+	cfg.Broker = s.broker
+	c.Check(cfg.Validate(), gc.ErrorMatches, `Logger is missing not valid`)
+
+	cfg.Logger = s.logger
+	c.Check(cfg.Validate(), gc.ErrorMatches, `Clock is missing not valid`)
+
+	cfg.Clock = s.clock
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) setupMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.facade = mocks.NewMockFacade(ctrl)
+	s.broker = mocks.NewMockCAASBroker(ctrl)
+	return ctrl
+}
+
+func (s *workerSuite) startWorker(c *gc.C) worker.Worker {
+	s.broker.EXPECT().WatchEvents().Return(watchertest.NewMockNotifyWatcher(s.changes), nil)
+	w, err := caasevents.NewWorker(caasevents.Config{
+		Facade: s.facade,
+		Broker: s.broker,
+		Logger: s.logger,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *workerSuite) sendChange(c *gc.C) {
+	select {
+	case s.changes <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+}
+
+func (s *workerSuite) waitFor(c *gc.C, done chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker")
+	}
+}
+
+func (s *workerSuite) event(uid, unitName, eventType, reason string, count int32, when time.Time) caas.Event {
+	return caas.Event{
+		UID:             uid,
+		ApplicationName: "mariadb",
+		UnitName:        unitName,
+		ObjectKind:      "Pod",
+		ObjectName:      "mariadb-0",
+		Type:            eventType,
+		Reason:          reason,
+		Message:         reason + " happened",
+		Count:           count,
+		Time:            when,
+	}
+}
+
+func (s *workerSuite) TestReportsNewEvents(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+
+	later := s.start.Add(time.Second)
+	done := make(chan struct{})
+	s.broker.EXPECT().Events().Return([]caas.Event{
+		s.event("uid-old", "mariadb/0", caas.EventTypeWarning, "FailedScheduling", 5, s.start.Add(-time.Hour)),
+		s.event("uid-1", "mariadb/0", caas.EventTypeWarning, "BackOff", 1, later),
+		s.event("uid-2", "", caas.EventTypeNormal, "Pulled", 1, later),
+	}, nil)
+	s.facade.EXPECT().RecordUnitEvents([]params.CAASUnitEvent{{
+		UnitTag: "unit-mariadb-0",
+		Type:    caas.EventTypeWarning,
+		Reason:  "BackOff",
+		Message: "BackOff happened",
+		Count:   1,
+		Time:    later,
+	}}).DoAndReturn(func([]params.CAASUnitEvent) error {
+		close(done)
+		return nil
+	})
+
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.sendChange(c)
+	s.waitFor(c, done)
+
+	c.Assert(s.logger.lines(), jc.DeepEquals, []string{
+		"WARNING mariadb/0: pod mariadb-0: BackOff: BackOff happened",
+		"DEBUG mariadb: pod mariadb-0: Pulled: Pulled happened",
+	})
+}
+
+func (s *workerSuite) TestRateLimitsRepeatedEvents(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+
+	later := s.start.Add(time.Second)
+	repeated := s.start.Add(10 * time.Second)
+	reported := make(chan struct{})
+	flushed := make(chan struct{})
+	settled := make(chan struct{})
+	gomock.InOrder(
+		s.broker.EXPECT().Events().Return([]caas.Event{
+			s.event("uid-1", "mariadb/0", caas.EventTypeWarning, "BackOff", 1, later),
+		}, nil),
+		s.facade.EXPECT().RecordUnitEvents(gomock.Any()).DoAndReturn(func([]params.CAASUnitEvent) error {
+			close(reported)
+			return nil
+		}),
+		s.broker.EXPECT().Events().Return([]caas.Event{
+			s.event("uid-1", "mariadb/0", caas.EventTypeWarning, "BackOff", 3, repeated),
+		}, nil),
+		s.broker.EXPECT().Events().DoAndReturn(func() ([]caas.Event, error) {
+			close(settled)
+			return []caas.Event{
+				s.event("uid-1", "mariadb/0", caas.EventTypeWarning, "BackOff", 3, repeated),
+			}, nil
+		}),
+		s.facade.EXPECT().RecordUnitEvents([]params.CAASUnitEvent{{
+			UnitTag: "unit-mariadb-0",
+			Type:    caas.EventTypeWarning,
+			Reason:  "BackOff",
+			Message: "BackOff happened (2 times)",
+			Count:   2,
+			Time:    repeated,
+		}}).DoAndReturn(func([]params.CAASUnitEvent) error {
+			close(flushed)
+			return nil
+		}),
+	)
+
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.sendChange(c)
+	s.waitFor(c, reported)
+
+	// The repeats are counted but not reported until the interval passes.
+	s.sendChange(c)
+	s.sendChange(c)
+	s.waitFor(c, settled)
+	c.Assert(s.clock.WaitAdvance(caasevents.RateLimitInterval, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitFor(c, flushed)
+
+	c.Assert(s.logger.lines(), jc.DeepEquals, []string{
+		"WARNING mariadb/0: pod mariadb-0: BackOff: BackOff happened",
+		"WARNING mariadb/0: pod mariadb-0: BackOff: BackOff happened (2 times)",
+	})
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) record(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.record("DEBUG", format, args...)
+}
+
+func (l *recordingLogger) Warningf(format string, args ...interface{}) {
+	l.record("WARNING", format, args...)
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.record("ERROR", format, args...)
+}
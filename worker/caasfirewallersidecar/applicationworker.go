@@ -4,13 +4,17 @@
 package caasfirewallersidecar
 
 import (
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/worker/v3"
 	"github.com/juju/worker/v3/catacomb"
 
+	"github.com/juju/juju/api/controller/caasfirewaller"
 	"github.com/juju/juju/caas"
+	k8sconstants "github.com/juju/juju/caas/kubernetes/provider/constants"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/watcher"
 )
@@ -23,12 +27,15 @@ type applicationWorker struct {
 
 	firewallerAPI CAASFirewallerAPI
 
-	broker         CAASBroker
-	portMutator    PortMutator
-	serviceUpdater ServiceUpdater
+	broker               CAASBroker
+	portMutator          PortMutator
+	serviceUpdater       ServiceUpdater
+	networkPolicyManager NetworkPolicyManager
 
-	appWatcher   watcher.NotifyWatcher
-	portsWatcher watcher.StringsWatcher
+	appWatcher         watcher.NotifyWatcher
+	portsWatcher       watcher.StringsWatcher
+	relationsWatcher   watcher.StringsWatcher
+	modelConfigWatcher watcher.NotifyWatcher
 
 	lifeGetter LifeGetter
 
@@ -37,6 +44,13 @@ type applicationWorker struct {
 
 	currentPorts network.GroupedPortRanges
 
+	// networkPolicyKnown is true once the network policy has been
+	// ensured or removed, with networkPolicyEnabled and currentIngress
+	// recording which.
+	networkPolicyKnown   bool
+	networkPolicyEnabled bool
+	currentIngress       []caas.NetworkPolicyIngress
+
 	logger Logger
 }
 
@@ -95,10 +109,27 @@ func (w *applicationWorker) setUp() (err error) {
 		return errors.Trace(err)
 	}
 
+	w.relationsWatcher, err = w.firewallerAPI.WatchApplicationRelations(w.appName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(w.relationsWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	w.modelConfigWatcher, err = w.firewallerAPI.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(w.modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
 	// TODO(sidecar): support deployment other than statefulset
 	app := w.broker.Application(w.appName, caas.DeploymentStateful)
 	w.portMutator = app
 	w.serviceUpdater = app
+	w.networkPolicyManager = app
 
 	if w.currentPorts, err = w.firewallerAPI.GetOpenedPorts(w.appName); err != nil {
 		return errors.Annotatef(err, "failed to get initial openned ports for application")
@@ -147,6 +178,20 @@ func (w *applicationWorker) loop() (err error) {
 			if err := w.onPortChanged(); err != nil {
 				return errors.Trace(err)
 			}
+		case _, ok := <-w.relationsWatcher.Changes():
+			if !ok {
+				return errors.New("relations watcher closed")
+			}
+			if err := w.onNetworkPolicyChanged(); err != nil {
+				return errors.Trace(err)
+			}
+		case _, ok := <-w.modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			if err := w.onNetworkPolicyChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -188,6 +233,106 @@ func (w *applicationWorker) onPortChanged() error {
 	}
 
 	w.currentPorts = changedPortRanges
+	if !w.networkPolicyEnabled {
+		return nil
+	}
+	// The ports allowed from related applications may have changed.
+	return errors.Trace(w.onNetworkPolicyChanged())
+}
+
+// toNetworkPolicyIngress returns the network policy ingress allowing the
+// related applications, keyed by endpoint, to connect to the ports opened
+// for that endpoint. Related applications are allowed to connect to any
+// port if no ports are opened for the endpoint they are related on.
+// Applications in other models connect from outside the cluster, so they
+// can't be selected by pod and connections from anywhere are allowed to
+// their ports instead. Likewise, an exposed application allows connections
+// from anywhere to all of its opened ports.
+func toNetworkPolicyIngress(
+	related map[string][]caasfirewaller.RelatedApplication, ports network.GroupedPortRanges, exposed bool,
+) []caas.NetworkPolicyIngress {
+	// A nil entry means any port is allowed. Connections from anywhere
+	// are keyed by the empty application name.
+	allowed := make(map[string][]network.PortRange)
+	allow := func(appName string, appPorts []network.PortRange) {
+		current, ok := allowed[appName]
+		if ok && current == nil {
+			return
+		}
+		if len(appPorts) == 0 {
+			allowed[appName] = nil
+			return
+		}
+		allowed[appName] = append(current, appPorts...)
+	}
+	for endpoint, apps := range related {
+		var endpointPorts []network.PortRange
+		endpointPorts = append(endpointPorts, ports[endpoint]...)
+		endpointPorts = append(endpointPorts, ports[""]...)
+		for _, app := range apps {
+			if app.CrossModel {
+				allow("", endpointPorts)
+				continue
+			}
+			allow(app.Name, endpointPorts)
+		}
+	}
+	if exposed {
+		allow("", ports.UniquePortRanges())
+	}
+
+	ingress := make([]caas.NetworkPolicyIngress, 0, len(allowed))
+	for appName, appPorts := range allowed {
+		in := caas.NetworkPolicyIngress{Application: appName}
+		if len(appPorts) > 0 {
+			in.Ports = network.UniquePortRanges(appPorts)
+			network.SortPortRanges(in.Ports)
+		}
+		ingress = append(ingress, in)
+	}
+	sort.Slice(ingress, func(i, j int) bool {
+		return ingress[i].Application < ingress[j].Application
+	})
+	return ingress
+}
+
+func (w *applicationWorker) onNetworkPolicyChanged() error {
+	cfg, err := w.firewallerAPI.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	enabled, _ := cfg.AllAttrs()[k8sconstants.NetworkPoliciesKey].(bool)
+	if !enabled {
+		if w.networkPolicyKnown && !w.networkPolicyEnabled {
+			return nil
+		}
+		if err := w.networkPolicyManager.RemoveNetworkPolicy(); err != nil {
+			return errors.Annotatef(err, "cannot remove network policy for application %q", w.appName)
+		}
+		w.networkPolicyKnown = true
+		w.networkPolicyEnabled = false
+		w.currentIngress = nil
+		return nil
+	}
+
+	related, err := w.firewallerAPI.RelatedApplications(w.appName)
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ingress := toNetworkPolicyIngress(related, w.currentPorts, w.previouslyExposed)
+	if w.networkPolicyEnabled && reflect.DeepEqual(ingress, w.currentIngress) {
+		w.logger.Debugf("no network policy changes for app %q", w.appName)
+		return nil
+	}
+	if err := w.networkPolicyManager.EnsureNetworkPolicy(ingress); err != nil {
+		return errors.Annotatef(err, "cannot ensure network policy for application %q", w.appName)
+	}
+	w.networkPolicyKnown = true
+	w.networkPolicyEnabled = true
+	w.currentIngress = ingress
 	return nil
 }
 
@@ -215,12 +360,22 @@ func (w *applicationWorker) onApplicationChanged() (err error) {
 		return nil
 	}
 
+	exposedChanged := exposed != w.previouslyExposed
 	w.initial = false
 	w.previouslyExposed = exposed
 	if exposed {
-		return errors.Trace(exposeService(w.serviceUpdater))
+		err = exposeService(w.serviceUpdater)
+	} else {
+		err = unExposeService(w.serviceUpdater)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !exposedChanged || !w.networkPolicyEnabled {
+		return nil
 	}
-	return errors.Trace(unExposeService(w.serviceUpdater))
+	// Exposing the application allows connections from anywhere.
+	return errors.Trace(w.onNetworkPolicyChanged())
 }
 
 func exposeService(app ServiceUpdater) error {
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/mock/gomock"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api/controller/caasfirewaller"
 	"github.com/juju/juju/caas"
 	caasmocks "github.com/juju/juju/caas/mocks"
 	"github.com/juju/juju/core/network"
@@ -35,9 +36,13 @@ type appWorkerSuite struct {
 
 	applicationChanges chan struct{}
 	portsChanges       chan []string
+	relationsChanges   chan []string
+	configChanges      chan struct{}
 
-	appsWatcher  watcher.NotifyWatcher
-	portsWatcher watcher.StringsWatcher
+	appsWatcher      watcher.NotifyWatcher
+	portsWatcher     watcher.StringsWatcher
+	relationsWatcher watcher.StringsWatcher
+	configWatcher    watcher.NotifyWatcher
 }
 
 var _ = gc.Suite(&appWorkerSuite{})
@@ -48,6 +53,8 @@ func (s *appWorkerSuite) SetUpTest(c *gc.C) {
 	s.appName = "app1"
 	s.applicationChanges = make(chan struct{})
 	s.portsChanges = make(chan []string)
+	s.relationsChanges = make(chan []string)
+	s.configChanges = make(chan struct{})
 }
 
 func (s *appWorkerSuite) getController(c *gc.C) *gomock.Controller {
@@ -55,6 +62,8 @@ func (s *appWorkerSuite) getController(c *gc.C) *gomock.Controller {
 
 	s.appsWatcher = watchertest.NewMockNotifyWatcher(s.applicationChanges)
 	s.portsWatcher = watchertest.NewMockStringsWatcher(s.portsChanges)
+	s.relationsWatcher = watchertest.NewMockStringsWatcher(s.relationsChanges)
+	s.configWatcher = watchertest.NewMockNotifyWatcher(s.configChanges)
 
 	s.firewallerAPI = mocks.NewMockCAASFirewallerAPI(ctrl)
 
@@ -114,6 +123,8 @@ func (s *appWorkerSuite) TestWorker(c *gc.C) {
 	gomock.InOrder(
 		s.firewallerAPI.EXPECT().WatchApplication(s.appName).Return(s.appsWatcher, nil),
 		s.firewallerAPI.EXPECT().WatchOpenedPorts().Return(s.portsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchApplicationRelations(s.appName).Return(s.relationsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchForModelConfigChanges().Return(s.configWatcher, nil),
 		s.broker.EXPECT().Application(s.appName, caas.DeploymentStateful).Return(s.brokerApp),
 
 		// initial fetch.
@@ -165,3 +176,242 @@ func (s *appWorkerSuite) TestWorker(c *gc.C) {
 	}
 	workertest.CleanKill(c, w)
 }
+
+func (s *appWorkerSuite) TestNetworkPolicy(c *gc.C) {
+	ctrl := s.getController(c)
+	defer ctrl.Finish()
+
+	done := make(chan struct{})
+
+	go func() {
+		// Initial event, network policies are disabled.
+		s.configChanges <- struct{}{}
+		// Network policies are enabled.
+		s.configChanges <- struct{}{}
+		// A relation is added.
+		s.relationsChanges <- []string{"app1:db mysql:server"}
+		// A port is opened for the related endpoint.
+		s.portsChanges <- []string{"port changes"}
+		// Network policies are disabled.
+		s.configChanges <- struct{}{}
+	}()
+
+	disabled := testing.ModelConfig(c)
+	enabled := testing.CustomModelConfig(c, testing.Attrs{"network-policies": true})
+	gpr := network.GroupedPortRanges{
+		"db": []network.PortRange{
+			network.MustParsePortRange("3306/tcp"),
+		},
+	}
+
+	gomock.InOrder(
+		s.firewallerAPI.EXPECT().WatchApplication(s.appName).Return(s.appsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchOpenedPorts().Return(s.portsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchApplicationRelations(s.appName).Return(s.relationsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchForModelConfigChanges().Return(s.configWatcher, nil),
+		s.broker.EXPECT().Application(s.appName, caas.DeploymentStateful).Return(s.brokerApp),
+		s.firewallerAPI.EXPECT().GetOpenedPorts(s.appName).Return(network.GroupedPortRanges{}, nil),
+
+		// Any existing policy is removed on startup.
+		s.firewallerAPI.EXPECT().ModelConfig().Return(disabled, nil),
+		s.brokerApp.EXPECT().RemoveNetworkPolicy().Return(nil),
+
+		// Network policies are enabled.
+		s.firewallerAPI.EXPECT().ModelConfig().Return(enabled, nil),
+		s.firewallerAPI.EXPECT().RelatedApplications(s.appName).Return(map[string][]caasfirewaller.RelatedApplication{}, nil),
+		s.brokerApp.EXPECT().EnsureNetworkPolicy([]caas.NetworkPolicyIngress{}).Return(nil),
+
+		// A relation is added.
+		s.firewallerAPI.EXPECT().ModelConfig().Return(enabled, nil),
+		s.firewallerAPI.EXPECT().RelatedApplications(s.appName).Return(map[string][]caasfirewaller.RelatedApplication{
+			"db": {{Name: "mysql"}},
+		}, nil),
+		s.brokerApp.EXPECT().EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+			Application: "mysql",
+		}}).Return(nil),
+
+		// A port is opened for the related endpoint.
+		s.firewallerAPI.EXPECT().GetOpenedPorts(s.appName).Return(gpr, nil),
+		s.brokerApp.EXPECT().UpdatePorts([]caas.ServicePort{{
+			Name:       "3306-tcp",
+			Port:       3306,
+			TargetPort: 3306,
+			Protocol:   "tcp",
+		}}, false).Return(nil),
+		s.firewallerAPI.EXPECT().ModelConfig().Return(enabled, nil),
+		s.firewallerAPI.EXPECT().RelatedApplications(s.appName).Return(map[string][]caasfirewaller.RelatedApplication{
+			"db": {{Name: "mysql"}},
+		}, nil),
+		s.brokerApp.EXPECT().EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+			Application: "mysql",
+			Ports:       []network.PortRange{network.MustParsePortRange("3306/tcp")},
+		}}).Return(nil),
+
+		// Network policies are disabled.
+		s.firewallerAPI.EXPECT().ModelConfig().Return(disabled, nil),
+		s.brokerApp.EXPECT().RemoveNetworkPolicy().DoAndReturn(func() error {
+			close(done)
+			return nil
+		}),
+	)
+
+	w := s.getWorker(c)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Errorf("timed out waiting for worker")
+	}
+	workertest.CleanKill(c, w)
+}
+
+func (s *appWorkerSuite) TestNetworkPolicyExposed(c *gc.C) {
+	ctrl := s.getController(c)
+	defer ctrl.Finish()
+
+	done := make(chan struct{})
+
+	go func() {
+		// Network policies are enabled.
+		s.configChanges <- struct{}{}
+		// The application is exposed.
+		s.applicationChanges <- struct{}{}
+	}()
+
+	enabled := testing.CustomModelConfig(c, testing.Attrs{"network-policies": true})
+	gpr := network.GroupedPortRanges{
+		"website": []network.PortRange{
+			network.MustParsePortRange("80/tcp"),
+		},
+	}
+	related := map[string][]caasfirewaller.RelatedApplication{
+		"website": {{Name: "haproxy"}},
+	}
+
+	gomock.InOrder(
+		s.firewallerAPI.EXPECT().WatchApplication(s.appName).Return(s.appsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchOpenedPorts().Return(s.portsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchApplicationRelations(s.appName).Return(s.relationsWatcher, nil),
+		s.firewallerAPI.EXPECT().WatchForModelConfigChanges().Return(s.configWatcher, nil),
+		s.broker.EXPECT().Application(s.appName, caas.DeploymentStateful).Return(s.brokerApp),
+		s.firewallerAPI.EXPECT().GetOpenedPorts(s.appName).Return(gpr, nil),
+
+		s.firewallerAPI.EXPECT().ModelConfig().Return(enabled, nil),
+		s.firewallerAPI.EXPECT().RelatedApplications(s.appName).Return(related, nil),
+		s.brokerApp.EXPECT().EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+			Application: "haproxy",
+			Ports:       []network.PortRange{network.MustParsePortRange("80/tcp")},
+		}}).Return(nil),
+
+		// Exposing the application allows connections from anywhere.
+		s.firewallerAPI.EXPECT().IsExposed(s.appName).Return(true, nil),
+		s.firewallerAPI.EXPECT().ModelConfig().Return(enabled, nil),
+		s.firewallerAPI.EXPECT().RelatedApplications(s.appName).Return(related, nil),
+		s.brokerApp.EXPECT().EnsureNetworkPolicy([]caas.NetworkPolicyIngress{{
+			Ports: []network.PortRange{network.MustParsePortRange("80/tcp")},
+		}, {
+			Application: "haproxy",
+			Ports:       []network.PortRange{network.MustParsePortRange("80/tcp")},
+		}}).DoAndReturn(func([]caas.NetworkPolicyIngress) error {
+			close(done)
+			return nil
+		}),
+	)
+
+	w := s.getWorker(c)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Errorf("timed out waiting for worker")
+	}
+	workertest.CleanKill(c, w)
+}
+
+func (s *appWorkerSuite) TestToNetworkPolicyIngress(c *gc.C) {
+	ingress := caasfirewallersidecar.ToNetworkPolicyIngress(
+		map[string][]caasfirewaller.RelatedApplication{
+			"db":      {{Name: "mysql"}, {Name: "wordpress"}},
+			"website": {{Name: "haproxy"}, {Name: "wordpress"}},
+		},
+		network.GroupedPortRanges{
+			"": []network.PortRange{
+				network.MustParsePortRange("8080/tcp"),
+			},
+			"website": []network.PortRange{
+				network.MustParsePortRange("80/tcp"),
+				network.MustParsePortRange("443/tcp"),
+			},
+		},
+		false,
+	)
+	c.Assert(ingress, jc.DeepEquals, []caas.NetworkPolicyIngress{{
+		Application: "haproxy",
+		Ports: []network.PortRange{
+			network.MustParsePortRange("80/tcp"),
+			network.MustParsePortRange("443/tcp"),
+			network.MustParsePortRange("8080/tcp"),
+		},
+	}, {
+		Application: "mysql",
+		Ports: []network.PortRange{
+			network.MustParsePortRange("8080/tcp"),
+		},
+	}, {
+		Application: "wordpress",
+		Ports: []network.PortRange{
+			network.MustParsePortRange("80/tcp"),
+			network.MustParsePortRange("443/tcp"),
+			network.MustParsePortRange("8080/tcp"),
+		},
+	}})
+
+	// Without any opened ports, related applications can connect to any port.
+	ingress = caasfirewallersidecar.ToNetworkPolicyIngress(
+		map[string][]caasfirewaller.RelatedApplication{"db": {{Name: "mysql"}}}, nil, false,
+	)
+	c.Assert(ingress, jc.DeepEquals, []caas.NetworkPolicyIngress{{
+		Application: "mysql",
+	}})
+}
+
+func (s *appWorkerSuite) TestToNetworkPolicyIngressFromAnywhere(c *gc.C) {
+	ports := network.GroupedPortRanges{
+		"db": []network.PortRange{
+			network.MustParsePortRange("5432/tcp"),
+		},
+		"metrics": []network.PortRange{
+			network.MustParsePortRange("9090/tcp"),
+		},
+	}
+
+	// Cross model applications connect from anywhere to the ports of
+	// their endpoint.
+	ingress := caasfirewallersidecar.ToNetworkPolicyIngress(
+		map[string][]caasfirewaller.RelatedApplication{
+			"db": {{Name: "mysql"}, {Name: "remote-app", CrossModel: true}},
+		},
+		ports, false,
+	)
+	c.Assert(ingress, jc.DeepEquals, []caas.NetworkPolicyIngress{{
+		Ports: []network.PortRange{network.MustParsePortRange("5432/tcp")},
+	}, {
+		Application: "mysql",
+		Ports:       []network.PortRange{network.MustParsePortRange("5432/tcp")},
+	}})
+
+	// Exposed applications allow connections from anywhere to all their
+	// opened ports.
+	ingress = caasfirewallersidecar.ToNetworkPolicyIngress(nil, ports, true)
+	c.Assert(ingress, jc.DeepEquals, []caas.NetworkPolicyIngress{{
+		Ports: []network.PortRange{
+			network.MustParsePortRange("5432/tcp"),
+			network.MustParsePortRange("9090/tcp"),
+		},
+	}})
+
+	// Without any opened ports, connections from anywhere are allowed to
+	// any port.
+	ingress = caasfirewallersidecar.ToNetworkPolicyIngress(nil, nil, true)
+	c.Assert(ingress, jc.DeepEquals, []caas.NetworkPolicyIngress{{}})
+}
@@ -21,3 +21,9 @@ type PortMutator interface {
 type ServiceUpdater interface {
 	UpdateService(caas.ServiceParam) error
 }
+
+// NetworkPolicyManager exposes CAAS application functionality to a worker.
+type NetworkPolicyManager interface {
+	EnsureNetworkPolicy(ingress []caas.NetworkPolicyIngress) error
+	RemoveNetworkPolicy() error
+}
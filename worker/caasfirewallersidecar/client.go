@@ -5,10 +5,12 @@ package caasfirewallersidecar
 
 import (
 	charmscommon "github.com/juju/juju/api/common/charms"
+	"github.com/juju/juju/api/controller/caasfirewaller"
 	"github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/watcher"
+	environsconfig "github.com/juju/juju/environs/config"
 )
 
 //go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/client_mock.go github.com/juju/juju/worker/caasfirewallersidecar Client,CAASFirewallerAPI,LifeGetter
@@ -37,6 +39,12 @@ type CAASFirewallerAPI interface {
 	ApplicationConfig(string) (config.ConfigAttributes, error)
 
 	ApplicationCharmInfo(appName string) (*charmscommon.CharmInfo, error)
+
+	WatchApplicationRelations(appName string) (watcher.StringsWatcher, error)
+	RelatedApplications(appName string) (map[string][]caasfirewaller.RelatedApplication, error)
+
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*environsconfig.Config, error)
 }
 
 // LifeGetter provides an interface for getting the
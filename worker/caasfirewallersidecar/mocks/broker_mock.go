@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/juju/juju/worker/caasfirewallersidecar (interfaces: CAASBroker,PortMutator,ServiceUpdater)
+// Source: github.com/juju/juju/worker/caasfirewallersidecar (interfaces: CAASBroker,PortMutator,ServiceUpdater,NetworkPolicyManager)
 //
 // Generated by this command:
 //
-//	mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasfirewallersidecar CAASBroker,PortMutator,ServiceUpdater
+//	mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasfirewallersidecar CAASBroker,PortMutator,ServiceUpdater,NetworkPolicyManager
 //
 
 // Package mocks is a generated GoMock package.
@@ -126,3 +126,54 @@ func (mr *MockServiceUpdaterMockRecorder) UpdateService(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceUpdater)(nil).UpdateService), arg0)
 }
+
+// MockNetworkPolicyManager is a mock of NetworkPolicyManager interface.
+type MockNetworkPolicyManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockNetworkPolicyManagerMockRecorder
+}
+
+// MockNetworkPolicyManagerMockRecorder is the mock recorder for MockNetworkPolicyManager.
+type MockNetworkPolicyManagerMockRecorder struct {
+	mock *MockNetworkPolicyManager
+}
+
+// NewMockNetworkPolicyManager creates a new mock instance.
+func NewMockNetworkPolicyManager(ctrl *gomock.Controller) *MockNetworkPolicyManager {
+	mock := &MockNetworkPolicyManager{ctrl: ctrl}
+	mock.recorder = &MockNetworkPolicyManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNetworkPolicyManager) EXPECT() *MockNetworkPolicyManagerMockRecorder {
+	return m.recorder
+}
+
+// EnsureNetworkPolicy mocks base method.
+func (m *MockNetworkPolicyManager) EnsureNetworkPolicy(arg0 []caas.NetworkPolicyIngress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureNetworkPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureNetworkPolicy indicates an expected call of EnsureNetworkPolicy.
+func (mr *MockNetworkPolicyManagerMockRecorder) EnsureNetworkPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureNetworkPolicy", reflect.TypeOf((*MockNetworkPolicyManager)(nil).EnsureNetworkPolicy), arg0)
+}
+
+// RemoveNetworkPolicy mocks base method.
+func (m *MockNetworkPolicyManager) RemoveNetworkPolicy() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetworkPolicy")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetworkPolicy indicates an expected call of RemoveNetworkPolicy.
+func (mr *MockNetworkPolicyManagerMockRecorder) RemoveNetworkPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkPolicy", reflect.TypeOf((*MockNetworkPolicyManager)(nil).RemoveNetworkPolicy))
+}
@@ -12,7 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 )
 
-//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasfirewallersidecar CAASBroker,PortMutator,ServiceUpdater
+//go:generate go run go.uber.org/mock/mockgen -package mocks -destination mocks/broker_mock.go github.com/juju/juju/worker/caasfirewallersidecar CAASBroker,PortMutator,ServiceUpdater,NetworkPolicyManager
 
 func TestAll(t *testing.T) {
 	gc.TestingT(t)
@@ -23,7 +23,8 @@ type (
 )
 
 var (
-	NewApplicationWorker = newApplicationWorker
+	NewApplicationWorker   = newApplicationWorker
+	ToNetworkPolicyIngress = toNetworkPolicyIngress
 )
 
 func NewWorkerForTest(config Config, f ApplicationWorkerCreator) (worker.Worker, error) {
@@ -0,0 +1,251 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	stdcontext "context"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/rpc/params"
+)
+
+const (
+	// globalDriftTarget identifies the firewall shared by all machines
+	// in global firewall mode in a drift report.
+	globalDriftTarget = "global"
+
+	// modelDriftTarget identifies the model firewall in a drift report.
+	modelDriftTarget = "model"
+)
+
+// driftConfigChanged reads the firewall drift settings from model
+// config, returning true if the check interval changed.
+func (fw *Firewaller) driftConfigChanged() (bool, error) {
+	cfg, err := fw.firewallerApi.ModelConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	fw.driftMode = cfg.FirewallDriftMode()
+	interval := cfg.FirewallDriftCheckInterval()
+	if interval == fw.driftCheckInterval {
+		return false, nil
+	}
+	fw.driftCheckInterval = interval
+	if interval == 0 {
+		fw.logger.Infof("firewall drift checks disabled")
+	} else {
+		fw.logger.Infof("checking for firewall drift every %v", interval)
+	}
+	return true, nil
+}
+
+// nextDriftCheck returns a channel which fires when the next firewall
+// drift check is due, or nil if drift checks are disabled.
+func (fw *Firewaller) nextDriftCheck() <-chan time.Time {
+	if fw.driftCheckInterval <= 0 {
+		return nil
+	}
+	return fw.clk.After(fw.driftCheckInterval)
+}
+
+// checkDrift compares the ingress rules applied by the provider with
+// those the firewaller expects, correcting any differences when the
+// drift mode is "correct", and records the result in the model's
+// status history.
+func (fw *Firewaller) checkDrift() error {
+	correct := fw.driftMode == config.FirewallDriftCorrect
+
+	var drift []params.FirewallDrift
+	modelDrift, err := fw.modelDrift(correct)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	drift = append(drift, modelDrift...)
+
+	var machineDrift []params.FirewallDrift
+	if fw.globalMode {
+		machineDrift, err = fw.globalDrift(correct)
+	} else {
+		machineDrift, err = fw.instanceDrift(correct)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	drift = append(drift, machineDrift...)
+
+	report := params.FirewallDriftReport{
+		Mode:      fw.driftMode,
+		Corrected: correct && len(drift) > 0,
+		Drift:     drift,
+	}
+	if len(drift) > 0 {
+		fw.logger.Warningf("found firewall drift on %d firewalls (mode %q)", len(drift), fw.driftMode)
+	} else {
+		fw.logger.Debugf("no firewall drift found")
+	}
+	err = fw.firewallerApi.RecordFirewallDrift(report)
+	if errors.Is(err, errors.NotSupported) {
+		fw.logger.Debugf("controller does not support recording firewall drift")
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// modelDrift returns the drift of the model firewall, if the
+// environ has one.
+func (fw *Firewaller) modelDrift(correct bool) ([]params.FirewallDrift, error) {
+	if fw.environModelFirewaller == nil {
+		return nil, nil
+	}
+	want, err := fw.firewallerApi.ModelFirewallRules()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ctx := stdcontext.Background()
+	have, err := fw.environModelFirewaller.ModelIngressRules(fw.cloudCallContextFunc(ctx))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	missing, unexpected := have.Diff(want)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil, nil
+	}
+	if correct {
+		if len(missing) > 0 {
+			if err := fw.environModelFirewaller.OpenModelPorts(fw.cloudCallContextFunc(ctx), missing); err != nil {
+				return nil, errors.Annotatef(err, "failed to open port ranges %v on model firewall", missing)
+			}
+		}
+		if len(unexpected) > 0 {
+			if err := fw.environModelFirewaller.CloseModelPorts(fw.cloudCallContextFunc(ctx), unexpected); err != nil {
+				return nil, errors.Annotatef(err, "failed to close port ranges %v on model firewall", unexpected)
+			}
+		}
+	}
+	return []params.FirewallDrift{newFirewallDrift(modelDriftTarget, missing, unexpected)}, nil
+}
+
+// globalDrift returns the drift of the firewall shared by all machines
+// in global firewall mode.
+func (fw *Firewaller) globalDrift(correct bool) ([]params.FirewallDrift, error) {
+	var machines []*machineData
+	for _, machined := range fw.machineds {
+		machines = append(machines, machined)
+	}
+	want, err := fw.gatherIngressRules(machines...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ctx := stdcontext.Background()
+	have, err := fw.environFirewaller.IngressRules(fw.cloudCallContextFunc(ctx))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	missing, unexpected := have.Diff(want)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil, nil
+	}
+	if correct {
+		if len(missing) > 0 {
+			if err := fw.environFirewaller.OpenPorts(fw.cloudCallContextFunc(ctx), missing); err != nil {
+				return nil, errors.Annotatef(err, "failed to open global ports %v", missing)
+			}
+		}
+		if len(unexpected) > 0 {
+			if err := fw.environFirewaller.ClosePorts(fw.cloudCallContextFunc(ctx), unexpected); err != nil {
+				return nil, errors.Annotatef(err, "failed to close global ports %v", unexpected)
+			}
+		}
+	}
+	return []params.FirewallDrift{newFirewallDrift(globalDriftTarget, missing, unexpected)}, nil
+}
+
+// instanceDrift returns the drift of the firewall of each provisioned
+// machine in instance firewall mode.
+func (fw *Firewaller) instanceDrift(correct bool) ([]params.FirewallDrift, error) {
+	var drift []params.FirewallDrift
+	for _, machined := range fw.machineds {
+		m, err := machined.machine()
+		if params.IsCodeNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		instanceId, err := m.InstanceId()
+		if errors.Is(err, errors.NotProvisioned) || params.IsCodeNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx := stdcontext.Background()
+		envInstances, err := fw.environInstances.Instances(fw.cloudCallContextFunc(ctx), []instance.Id{instanceId})
+		if err == environs.ErrNoInstances {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		fwInstance, ok := envInstances[0].(instances.InstanceFirewaller)
+		if !ok {
+			continue
+		}
+
+		machineId := machined.tag.Id()
+		have, err := fwInstance.IngressRules(fw.cloudCallContextFunc(ctx), machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		missing, unexpected := have.Diff(machined.ingressRules)
+		if len(missing) == 0 && len(unexpected) == 0 {
+			continue
+		}
+		if correct {
+			if len(missing) > 0 {
+				if err := fwInstance.OpenPorts(fw.cloudCallContextFunc(ctx), machineId, missing); err != nil {
+					return nil, errors.Annotatef(err, "failed to open instance ports %v for %q", missing, machined.tag)
+				}
+			}
+			if len(unexpected) > 0 {
+				if err := fwInstance.ClosePorts(fw.cloudCallContextFunc(ctx), machineId, unexpected); err != nil {
+					return nil, errors.Annotatef(err, "failed to close instance ports %v for %q", unexpected, machined.tag)
+				}
+			}
+		}
+		drift = append(drift, newFirewallDrift(machined.tag.String(), missing, unexpected))
+	}
+	return drift, nil
+}
+
+func newFirewallDrift(target string, missing, unexpected firewall.IngressRules) params.FirewallDrift {
+	missing.Sort()
+	unexpected.Sort()
+	return params.FirewallDrift{
+		Target:     target,
+		Missing:    toParamsIngressRules(missing),
+		Unexpected: toParamsIngressRules(unexpected),
+	}
+}
+
+func toParamsIngressRules(rules firewall.IngressRules) []params.IngressRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]params.IngressRule, len(rules))
+	for i, rule := range rules {
+		result[i] = params.IngressRule{
+			PortRange:   params.FromNetworkPortRange(rule.PortRange),
+			SourceCIDRs: rule.SourceCIDRs.SortedValues(),
+		}
+	}
+	return result
+}
@@ -45,6 +45,8 @@ type FirewallerAPI interface {
 	WatchModelFirewallRules() (watcher.NotifyWatcher, error)
 	ModelFirewallRules() (firewall.IngressRules, error)
 	ModelConfig() (*config.Config, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	RecordFirewallDrift(report params.FirewallDriftReport) error
 	Machine(tag names.MachineTag) (*firewaller.Machine, error)
 	Unit(tag names.UnitTag) (*firewaller.Unit, error)
 	Relation(tag names.RelationTag) (*firewaller.Relation, error)
@@ -163,19 +165,30 @@ type Firewaller struct {
 	portsWatcher         watcher.StringsWatcher
 	subnetWatcher        watcher.StringsWatcher
 	modelFirewallWatcher watcher.NotifyWatcher
+	modelConfigWatcher   watcher.NotifyWatcher
 	machineds            map[names.MachineTag]*machineData
 	unitsChange          chan *unitsChange
 	unitds               map[names.UnitTag]*unitData
 	applicationids       map[names.ApplicationTag]*applicationData
 	exposedChange        chan *exposedChange
+	egressChange         chan *egressChange
 	spaceInfos           network.SpaceInfos
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
 
+	// Machines whose egress rules could not be applied yet, as they
+	// have no instance, and the timer for trying them again.
+	egressPending map[names.MachineTag]*machineData
+	egressRetry   <-chan time.Time
+
 	// Set to true if the environment supports ingress rules containing
 	// IPV6 CIDRs.
 	envIPV6CIDRSupport bool
 
+	// The firewall drift settings, read from model config.
+	driftCheckInterval time.Duration
+	driftMode          string
+
 	modelUUID                  string
 	newRemoteFirewallerAPIFunc newCrossModelFacadeFunc
 	remoteRelationsWatcher     watcher.StringsWatcher
@@ -216,6 +229,8 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		unitds:                     make(map[names.UnitTag]*unitData),
 		applicationids:             make(map[names.ApplicationTag]*applicationData),
 		exposedChange:              make(chan *exposedChange),
+		egressChange:               make(chan *egressChange),
+		egressPending:              make(map[names.MachineTag]*machineData),
 		relationIngress:            make(map[names.RelationTag]*remoteRelationData),
 		localRelationsChange:       make(chan *remoteRelationNetworkChange),
 		clk:                        clk,
@@ -300,6 +315,14 @@ func (fw *Firewaller) setUp() error {
 		}
 	}
 
+	fw.modelConfigWatcher, err = fw.firewallerApi.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Annotatef(err, "failed to start model config watcher")
+	}
+	if err := fw.catacomb.Add(fw.modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
 	if fw.spaceInfos, err = fw.firewallerApi.AllSpaceInfos(); err != nil {
 		return errors.Trace(err)
 	}
@@ -324,6 +347,10 @@ func (fw *Firewaller) loop() error {
 		modelFirewallChanges = fw.modelFirewallWatcher.Changes()
 	}
 
+	// Drift checks only start once the initial reconciliation is done,
+	// as until then the expected rules are not known.
+	var checkDrift <-chan time.Time
+
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -344,6 +371,24 @@ func (fw *Firewaller) loop() error {
 			if ensureModelFirewalls == nil {
 				ensureModelFirewalls = fw.clk.After(0)
 			}
+		case _, ok := <-fw.modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			intervalChanged, err := fw.driftConfigChanged()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if intervalChanged && reconciled {
+				checkDrift = fw.nextDriftCheck()
+			}
+		case <-checkDrift:
+			// A failed check is retried at the next interval rather
+			// than stopping the firewaller.
+			if err := fw.checkDrift(); err != nil {
+				fw.logger.Warningf("cannot check firewall drift: %v", err)
+			}
+			checkDrift = fw.nextDriftCheck()
 		case change, ok := <-fw.machinesWatcher.Changes():
 			if !ok {
 				return errors.New("machines watcher closed")
@@ -364,6 +409,7 @@ func (fw *Firewaller) loop() error {
 				if err != nil {
 					return errors.Trace(err)
 				}
+				checkDrift = fw.nextDriftCheck()
 			}
 			// After first machine exists, make sure to trigger the model firewall flush.
 			if len(change) > 0 && !modelGroupInitiallyConfigured {
@@ -420,6 +466,22 @@ func (fw *Firewaller) loop() error {
 			if err := fw.flushUnits(unitds); err != nil {
 				return errors.Annotate(err, "cannot change firewall ports")
 			}
+		case change := <-fw.egressChange:
+			change.applicationd.egressRules = change.egressRules
+			var unitds []*unitData
+			for _, unitd := range change.applicationd.unitds {
+				unitds = append(unitds, unitd)
+			}
+			if err := fw.flushUnits(unitds); err != nil {
+				return errors.Annotate(err, "cannot change egress rules")
+			}
+		case <-fw.egressRetry:
+			fw.egressRetry = nil
+			for _, machined := range fw.egressPending {
+				if err := fw.flushMachineEgress(machined); err != nil {
+					return errors.Annotate(err, "cannot change egress rules")
+				}
+			}
 		}
 	}
 }
@@ -606,11 +668,18 @@ func (fw *Firewaller) startApplication(app *firewaller.Application) error {
 	if err != nil {
 		return err
 	}
+	egressRules, err := app.EgressRules()
+	if errors.Is(err, errors.NotSupported) {
+		fw.logger.Debugf("not applying egress rules for %q: %v", app.Name(), err)
+	} else if err != nil {
+		return err
+	}
 	applicationd := &applicationData{
 		fw:               fw,
 		application:      app,
 		exposed:          exposed,
 		exposedEndpoints: exposedEndpoints,
+		egressRules:      egressRules,
 		unitds:           make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
@@ -618,7 +687,7 @@ func (fw *Firewaller) startApplication(app *firewaller.Application) error {
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed, exposedEndpoints)
+			return applicationd.watchLoop(exposed, exposedEndpoints, egressRules)
 		},
 	})
 	if err != nil {
@@ -726,6 +795,41 @@ func (fw *Firewaller) reconcileInstances() error {
 				return errors.Annotatef(err, "failed to close instance ports %v for %q", toOpen, machined.tag)
 			}
 		}
+
+		if err := fw.reconcileInstanceEgress(machined, envInstances[0]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// reconcileInstanceEgress compares the egress rules of the instance with
+// the ones the firewaller wants on the machine, and opens and closes
+// the appropriate egress ports.
+func (fw *Firewaller) reconcileInstanceEgress(machined *machineData, inst instances.Instance) error {
+	fwInstance, err := instances.EgressFirewaller(inst)
+	if errors.Is(err, errors.NotSupported) {
+		return nil
+	}
+	ctx := stdcontext.Background()
+	machineId := machined.tag.Id()
+	initialRules, err := fwInstance.EgressRules(fw.cloudCallContextFunc(ctx), machineId)
+	if err != nil {
+		return err
+	}
+
+	toOpen, toClose := initialRules.Diff(machined.egressRules)
+	if len(toOpen) > 0 {
+		fw.logger.Infof("opening instance egress port ranges %v for %q", toOpen, machined.tag)
+		if err := fwInstance.OpenEgressPorts(fw.cloudCallContextFunc(ctx), machineId, toOpen); err != nil {
+			return errors.Annotatef(err, "failed to open instance egress ports %v for %q", toOpen, machined.tag)
+		}
+	}
+	if len(toClose) > 0 {
+		fw.logger.Infof("closing instance egress port ranges %v for %q", toClose, machined.tag)
+		if err := fwInstance.CloseEgressPorts(fw.cloudCallContextFunc(ctx), machineId, toClose); err != nil {
+			return errors.Annotatef(err, "failed to close instance egress ports %v for %q", toClose, machined.tag)
+		}
 	}
 	return nil
 }
@@ -856,9 +960,114 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := machined.ingressRules.Diff(want)
 	machined.ingressRules = want
 	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
+		err = fw.flushGlobalPorts(toOpen, toClose)
+	} else {
+		err = fw.flushInstancePorts(machined, toOpen, toClose)
+	}
+	if err != nil {
+		return err
+	}
+	return fw.flushMachineEgress(machined)
+}
+
+// flushMachineEgress restricts the outbound traffic of the passed machine
+// to the egress rules of the applications with units on it. Co-located
+// applications share the machine's firewall, so the machine is given
+// the union of their rules.
+func (fw *Firewaller) flushMachineEgress(machined *machineData) error {
+	want := fw.gatherEgressRules(machined)
+	toOpen, toClose := machined.egressRules.Diff(want)
+	if len(toOpen) == 0 && len(toClose) == 0 {
+		delete(fw.egressPending, machined.tag)
+		return nil
+	}
+	if fw.globalMode {
+		fw.logger.Warningf("not applying egress rules %v to %q: not supported in global firewall mode",
+			want, machined.tag)
+		machined.egressRules = want
+		return nil
+	}
+
+	err := fw.flushInstanceEgress(machined, toOpen, toClose)
+	if errors.IsNotProvisioned(err) {
+		// Egress rules may be set before the machine has an instance,
+		// so keep trying until it does.
+		fw.logger.Debugf("egress rules for %q pending provisioning", machined.tag)
+		fw.egressPending[machined.tag] = machined
+		if fw.egressRetry == nil {
+			fw.egressRetry = fw.clk.After(egressRetryDelay)
+		}
+		return nil
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	delete(fw.egressPending, machined.tag)
+	if params.IsCodeNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	machined.egressRules = want
+	return nil
+}
+
+// egressRetryDelay is how long to wait before trying again to apply the
+// egress rules of machines that are not yet provisioned.
+const egressRetryDelay = 10 * time.Second
+
+// gatherEgressRules returns the egress rules wanted on the specified
+// machine.
+func (fw *Firewaller) gatherEgressRules(machined *machineData) firewall.EgressRules {
+	var want firewall.EgressRules
+	seen := set.NewStrings()
+	for _, unitd := range machined.unitds {
+		applicationd := unitd.applicationd
+		if applicationd == nil || seen.Contains(applicationd.application.Name()) {
+			continue
+		}
+		seen.Add(applicationd.application.Name())
+		want = append(want, applicationd.egressRules...)
+	}
+	return want
+}
+
+// flushInstanceEgress opens and closes egress ports on the machine's
+// instance.
+func (fw *Firewaller) flushInstanceEgress(machined *machineData, toOpen, toClose firewall.EgressRules) error {
+	fw.logger.Debugf("flush instance egress: to open %v, to close %v", toOpen, toClose)
+	m, err := machined.machine()
+	if err != nil {
+		return err
+	}
+	instanceId, err := m.InstanceId()
+	if err != nil {
+		return err
+	}
+	ctx := stdcontext.Background()
+	envInstances, err := fw.environInstances.Instances(fw.cloudCallContextFunc(ctx), []instance.Id{instanceId})
+	if err != nil {
+		return err
+	}
+	fwInstance, err := instances.EgressFirewaller(envInstances[0])
+	if errors.Is(err, errors.NotSupported) {
+		fw.logger.Warningf("not applying egress rules to %q: %v", machined.tag, err)
+		return nil
+	}
+
+	machineId := machined.tag.Id()
+	if len(toOpen) > 0 {
+		toOpen.Sort()
+		if err := fwInstance.OpenEgressPorts(fw.cloudCallContextFunc(ctx), machineId, toOpen); err != nil {
+			return err
+		}
+		fw.logger.Infof("opened egress port ranges %v on %q", toOpen, machined.tag)
+	}
+	if len(toClose) > 0 {
+		toClose.Sort()
+		if err := fwInstance.CloseEgressPorts(fw.cloudCallContextFunc(ctx), machineId, toClose); err != nil {
+			return err
+		}
+		fw.logger.Infof("closed egress port ranges %v on %q", toClose, machined.tag)
+	}
+	return nil
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -1227,6 +1436,7 @@ func (fw *Firewaller) forgetMachine(machined *machineData) error {
 	// watch loop has stopped before we nuke the last data and return.
 	_ = worker.Stop(machined)
 	delete(fw.machineds, machined.tag)
+	delete(fw.egressPending, machined.tag)
 	fw.logger.Debugf("stopped watching %q", machined.tag)
 	return nil
 }
@@ -1284,6 +1494,7 @@ type machineData struct {
 	tag          names.MachineTag
 	unitds       map[names.UnitTag]*unitData
 	ingressRules firewall.IngressRules
+	egressRules  firewall.EgressRules
 	// ports defined by units on this machine
 	openedPortRangesByEndpoint map[names.UnitTag]network.GroupedPortRanges
 }
@@ -1340,18 +1551,30 @@ type exposedChange struct {
 	exposedEndpoints map[string]params.ExposedEndpoint
 }
 
-// applicationData holds application details and watches exposure changes.
+// egressChange contains the changed egress rules for one specific
+// application.
+type egressChange struct {
+	applicationd *applicationData
+	egressRules  firewall.EgressRules
+}
+
+// applicationData holds application details and watches exposure and
+// egress rule changes.
 type applicationData struct {
 	catacomb         catacomb.Catacomb
 	fw               *Firewaller
 	application      *firewaller.Application
 	exposed          bool
 	exposedEndpoints map[string]params.ExposedEndpoint
+	egressRules      firewall.EgressRules
 	unitds           map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(curExposed bool, curExposedEndpoints map[string]params.ExposedEndpoint) error {
+// watchLoop watches the application's exposed flag and egress rules for
+// changes.
+func (ad *applicationData) watchLoop(
+	curExposed bool, curExposedEndpoints map[string]params.ExposedEndpoint, curEgressRules firewall.EgressRules,
+) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1362,10 +1585,43 @@ func (ad *applicationData) watchLoop(curExposed bool, curExposedEndpoints map[st
 	if err := ad.catacomb.Add(appWatcher); err != nil {
 		return errors.Trace(err)
 	}
+	var egressChanges watcher.NotifyChannel
+	egressWatcher, err := ad.application.WatchEgressRules()
+	if params.IsCodeNotFound(err) {
+		return nil
+	} else if err != nil && !errors.Is(err, errors.NotSupported) {
+		return errors.Trace(err)
+	} else if err == nil {
+		if err := ad.catacomb.Add(egressWatcher); err != nil {
+			return errors.Trace(err)
+		}
+		egressChanges = egressWatcher.Changes()
+	}
 	for {
 		select {
 		case <-ad.catacomb.Dying():
 			return ad.catacomb.ErrDying()
+		case _, ok := <-egressChanges:
+			if !ok {
+				return errors.New("application egress rules watcher closed")
+			}
+			newEgressRules, err := ad.application.EgressRules()
+			if errors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			if toOpen, toClose := curEgressRules.Diff(newEgressRules); len(toOpen) == 0 && len(toClose) == 0 {
+				continue
+			}
+			ad.fw.logger.Tracef("application(%q) egress rules changed: %v", ad.application.Name(), newEgressRules)
+
+			curEgressRules = newEgressRules
+			select {
+			case <-ad.catacomb.Dying():
+				return ad.catacomb.ErrDying()
+			case ad.fw.egressChange <- &egressChange{ad, newEgressRules}:
+			}
 		case _, ok := <-appWatcher.Changes():
 			if !ok {
 				return errors.New("application watcher closed")
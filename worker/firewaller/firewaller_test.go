@@ -21,6 +21,7 @@ import (
 	"github.com/juju/utils/v3"
 	"github.com/juju/worker/v3"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/agent/credentialvalidator"
@@ -29,6 +30,7 @@ import (
 	apifirewaller "github.com/juju/juju/api/controller/firewaller"
 	"github.com/juju/juju/api/controller/remoterelations"
 	apitesting "github.com/juju/juju/api/testing"
+	coreconfig "github.com/juju/juju/core/config"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/network/firewall"
@@ -140,6 +142,41 @@ func (s *firewallerBaseSuite) assertIngressRules(c *gc.C, inst instances.Instanc
 
 // assertEnvironPorts retrieves the open ports of environment and compares them
 // to the expected.
+// assertEgressRules retrieves the egress rules from the provided instance
+// and compares them to the expected value.
+func (s *firewallerBaseSuite) assertEgressRules(c *gc.C, inst instances.Instance, machineId string,
+	expected firewall.EgressRules) {
+	fwInst, err := instances.EgressFirewaller(inst)
+	c.Assert(err, jc.ErrorIsNil)
+
+	start := time.Now()
+	for {
+		time.Sleep(coretesting.ShortWait)
+
+		got, err := fwInst.EgressRules(s.callCtx, machineId)
+		if err != nil {
+			c.Fatal(err)
+		}
+		if got.EqualTo(expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
+// setEgressRules sets the egress rules in the application's config.
+func (s *firewallerBaseSuite) setEgressRules(c *gc.C, app *state.Application, rules string) {
+	schema := environschema.Fields{
+		"egress": environschema.Attr{Type: environschema.Tstring},
+	}
+	err := app.UpdateApplicationConfig(coreconfig.ConfigAttributes{"egress": rules}, nil, schema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected firewall.IngressRules) {
 	fwEnv, ok := s.Environ.(environs.Firewaller)
 	c.Assert(ok, gc.Equals, true)
@@ -746,6 +783,96 @@ func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	})
 }
 
+func (s *InstanceModeSuite) TestEgressRules(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	s.setEgressRules(c, app, "443/tcp to 10.0.0.0/8;53/udp to 10.0.0.2/32")
+	_, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	s.assertEgressRules(c, inst, m.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+		firewall.NewEgressRule(network.MustParsePortRange("53/udp"), "10.0.0.2/32"),
+	})
+
+	s.setEgressRules(c, app, "443/tcp to 10.0.0.0/8,192.168.0.0/16")
+	s.assertEgressRules(c, inst, m.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8", "192.168.0.0/16"),
+	})
+
+	s.setEgressRules(c, app, "")
+	s.assertEgressRules(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestEgressRulesMachineWithoutInstanceId(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	s.setEgressRules(c, app, "443/tcp to 10.0.0.0/8")
+
+	// add a unit but don't start its instance yet.
+	_, m1 := s.addUnit(c, app)
+
+	// add another unit and start its instance, so that
+	// we're sure the firewaller has seen the first machine.
+	_, m2 := s.addUnit(c, app)
+	inst2 := s.startInstance(c, m2)
+	s.assertEgressRules(c, inst2, m2.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+	})
+
+	inst1 := s.startInstance(c, m1)
+	s.assertEgressRules(c, inst1, m1.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+	})
+}
+
+func (s *InstanceModeSuite) TestEgressRulesRemoveUnit(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	s.setEgressRules(c, app, "443/tcp to 10.0.0.0/8")
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	s.assertEgressRules(c, inst, m.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+	})
+
+	err := u.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEgressRules(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestStartWithEgressState(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	s.setEgressRules(c, app, "443/tcp to 10.0.0.0/8")
+	_, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	// Leave a stale rule on the instance.
+	fwInst, err := instances.EgressFirewaller(inst)
+	c.Assert(err, jc.ErrorIsNil)
+	err = fwInst.OpenEgressPorts(s.callCtx, m.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("8080/tcp"), "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Starting the firewaller reconciles the instance.
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertEgressRules(c, inst, m.Id(), firewall.EgressRules{
+		firewall.NewEgressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8"),
+	})
+}
+
 func (s *InstanceModeSuite) TestRemoveApplication(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -1784,6 +1911,7 @@ var _ = gc.Suite(&GlobalModeSuite{})
 
 func (s *GlobalModeSuite) SetUpTest(c *gc.C) {
 	s.firewallerBaseSuite.setUpTest(c, config.FwGlobal)
+	s.clock = nil
 }
 
 func (s *GlobalModeSuite) TearDownTest(c *gc.C) {
@@ -1813,6 +1941,7 @@ func (s *GlobalModeSuite) newFirewallerWithIPV6CIDRSupport(c *gc.C, supportIPV6C
 		NewCrossModelFacadeFunc: func(*api.Info) (firewaller.CrossModelFirewallerFacadeCloser, error) {
 			return s.crossmodelFirewaller, nil
 		},
+		Clock:         s.clock,
 		Logger:        loggo.GetLogger("test"),
 		CredentialAPI: s.credentialsFacade,
 	}
@@ -2145,3 +2274,79 @@ func mustClosePortRanges(c *gc.C, st *state.State, u *state.Unit, endpointName s
 
 	c.Assert(st.ApplyOperation(unitPortRanges.Changes()), jc.ErrorIsNil)
 }
+
+func (s *GlobalModeSuite) setUpFirewallDrift(c *gc.C, mode string) worker.Worker {
+	s.clock = testclock.NewDilatedWallClock(testing.ShortWait)
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.UpdateModelConfig(map[string]interface{}{
+		config.FirewallDriftCheckIntervalKey: "1m",
+		config.FirewallDriftModeKey:          mode,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw := s.newFirewaller(c)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		allEndpoints: {ExposeToCIDRs: []string{firewall.AllNetworksIPV4CIDR}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	s.startInstance(c, m)
+	mustOpenPortRanges(c, s.State, u, allEndpoints, []network.PortRange{
+		network.MustParsePortRange("80/tcp"),
+	})
+	s.assertEnvironPorts(c, firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("80/tcp"), firewall.AllNetworksIPV4CIDR),
+	})
+
+	// Open a port behind Juju's back.
+	fwEnv, ok := s.Environ.(environs.Firewaller)
+	c.Assert(ok, gc.Equals, true)
+	err = fwEnv.OpenPorts(s.callCtx, firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("22/tcp"), "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return fw
+}
+
+func (s *GlobalModeSuite) assertFirewallDriftRecorded(c *gc.C, expected string) {
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		// Later checks may have recorded further entries.
+		history, err := model.StatusHistory(status.StatusHistoryFilter{Size: 10})
+		c.Assert(err, jc.ErrorIsNil)
+		for _, h := range history {
+			if h.Message == expected {
+				c.Check(h.Data[params.FirewallDriftDataKey], gc.Not(gc.Equals), "")
+				return
+			}
+		}
+	}
+	c.Fatalf("timed out waiting for firewall drift %q to be recorded", expected)
+}
+
+func (s *GlobalModeSuite) TestFirewallDriftReport(c *gc.C) {
+	fw := s.setUpFirewallDrift(c, config.FirewallDriftReport)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertFirewallDriftRecorded(c, "firewall drift: 0 missing and 1 unexpected rules on 1 firewalls")
+
+	// The drift is left in place.
+	s.assertEnvironPorts(c, firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("22/tcp"), "10.0.0.0/8"),
+		firewall.NewIngressRule(network.MustParsePortRange("80/tcp"), firewall.AllNetworksIPV4CIDR),
+	})
+}
+
+func (s *GlobalModeSuite) TestFirewallDriftCorrect(c *gc.C) {
+	fw := s.setUpFirewallDrift(c, config.FirewallDriftCorrect)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertEnvironPorts(c, firewall.IngressRules{
+		firewall.NewIngressRule(network.MustParsePortRange("80/tcp"), firewall.AllNetworksIPV4CIDR),
+	})
+	s.assertFirewallDriftRecorded(c, "firewall drift: 0 missing and 1 unexpected rules on 1 firewalls (corrected)")
+}
@@ -82,13 +82,14 @@ func (m *mutater) startMachines(tags []names.MachineTag) error {
 			}
 			id := api.Tag().Id()
 
-			// Ensure we do not watch any KVM containers.
+			// Ensure we do not watch any KVM or nspawn containers;
+			// LXD profiles only apply to LXD containers.
 			containerType, err := api.ContainerType()
 			if err != nil {
 				return errors.Trace(err)
 			}
-			if containerType == instance.KVM {
-				m.logger.Tracef("ignoring KVM container machine-%s", id)
+			if containerType == instance.KVM || containerType == instance.NSPAWN {
+				m.logger.Tracef("ignoring %s container machine-%s", containerType, id)
 				continue
 			}
 
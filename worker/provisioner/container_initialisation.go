@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/container/broker"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/container/nspawn"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/network"
@@ -143,8 +144,11 @@ var getContainerInitialiser = func(
 	containerNetworkingMethod string,
 ) container.Initialiser {
 
-	if ct == instance.LXD {
+	switch ct {
+	case instance.LXD:
 		return lxd.NewContainerInitialiser(snapChannels["lxd"], containerNetworkingMethod)
+	case instance.NSPAWN:
+		return nspawn.NewContainerInitialiser(containerNetworkingMethod)
 	}
 	return kvm.NewContainerInitialiser()
 }
@@ -5,7 +5,9 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/worker/uniter/remotestate"
@@ -32,6 +34,8 @@ type executor struct {
 	state              *State
 	acquireMachineLock func(string, string) (func(), error)
 	logger             Logger
+	clock              clock.Clock
+	spans              *SpanRecorder
 }
 
 // ExecutorConfig defines configuration for an Executor.
@@ -40,6 +44,13 @@ type ExecutorConfig struct {
 	InitialState    State
 	AcquireLock     func(string, string) (func(), error)
 	Logger          Logger
+
+	// Clock is used to time operations. If nil, the wall clock is used.
+	Clock clock.Clock
+
+	// Spans, if set, records the timing of every operation run or
+	// skipped by the executor.
+	Spans *SpanRecorder
 }
 
 func (e ExecutorConfig) validate() error {
@@ -66,12 +77,18 @@ func NewExecutor(unitName string, cfg ExecutorConfig) (Executor, error) {
 	} else if err != nil {
 		return nil, err
 	}
+	clk := cfg.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
 	return &executor{
 		unitName:           unitName,
 		stateOps:           stateOps,
 		state:              state,
 		acquireMachineLock: cfg.AcquireLock,
 		logger:             cfg.Logger,
+		clock:              clk,
+		spans:              cfg.Spans,
 	}, nil
 }
 
@@ -81,12 +98,21 @@ func (x *executor) State() State {
 }
 
 // Run is part of the Executor interface.
-func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapshot) error {
+func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapshot) (err error) {
 	x.logger.Debugf("running operation %v for %s", op, x.unitName)
 
+	span := Span{Operation: op.String(), Queued: x.clock.Now()}
+	committing := false
+	defer func() {
+		if !committing {
+			x.record(span, err)
+		}
+	}()
+
 	if op.NeedsGlobalMachineLock() {
 		x.logger.Debugf("acquiring machine lock for %s", x.unitName)
 		releaser, err := x.acquireMachineLock(op.String(), op.ExecutionGroup())
+		span.LockWait = x.clock.Now().Sub(span.Queued)
 		if err != nil {
 			return errors.Annotatef(err, "acquiring %q lock for %s", op, x.unitName)
 		}
@@ -96,7 +122,7 @@ func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapsh
 		x.logger.Debugf("no machine lock needed for %s", x.unitName)
 	}
 
-	switch err := x.do(op, stepPrepare); errors.Cause(err) {
+	switch err := x.do(op, stepPrepare, &span.Prepare); errors.Cause(err) {
 	case ErrSkipExecute:
 	case nil:
 		done := make(chan struct{})
@@ -113,7 +139,7 @@ func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapsh
 				}
 			}
 		}()
-		if err := x.do(op, stepExecute); err != nil {
+		if err := x.do(op, stepExecute, &span.Execute); err != nil {
 			close(done)
 			return err
 		}
@@ -121,19 +147,46 @@ func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapsh
 	default:
 		return err
 	}
-	return x.do(op, stepCommit)
+	committing = true
+	return x.commit(op, &span)
 }
 
 // Skip is part of the Executor interface.
-func (x *executor) Skip(op Operation) error {
+func (x *executor) Skip(op Operation) (err error) {
 	x.logger.Debugf("skipping operation %v for %s", op, x.unitName)
-	return x.do(op, stepCommit)
+
+	span := Span{Operation: op.String(), Queued: x.clock.Now(), Skipped: true}
+	return x.commit(op, &span)
 }
 
-func (x *executor) do(op Operation, step executorStep) (err error) {
+// do runs the step of the operation, adding the time it took to elapsed.
+func (x *executor) do(op Operation, step executorStep, elapsed *time.Duration) (err error) {
+	start := x.clock.Now()
+	defer func() { *elapsed = x.clock.Now().Sub(start) }()
+
 	message := step.message(op, x.unitName)
 	x.logger.Debugf(message)
 	newState, firstErr := step.run(op, *x.state)
+	return x.write(message, newState, firstErr)
+}
+
+// commit commits the operation, recording its span before the state
+// written by the commit, so that the hook history is saved along with
+// that state rather than by another call to the controller. The time
+// spent committing doesn't include writing the state.
+func (x *executor) commit(op Operation, span *Span) error {
+	start := x.clock.Now()
+	message := stepCommit.message(op, x.unitName)
+	x.logger.Debugf(message)
+	newState, err := stepCommit.run(op, *x.state)
+	span.Commit = x.clock.Now().Sub(start)
+	x.record(*span, errors.Annotatef(err, message))
+	return x.write(message, newState, err)
+}
+
+// write writes the new state left by a step, if any, returning the
+// step's error in preference to any error writing the state.
+func (x *executor) write(message string, newState *State, firstErr error) error {
 	if newState != nil {
 		writeErr := x.writeState(*newState)
 		if firstErr == nil {
@@ -145,6 +198,22 @@ func (x *executor) do(op Operation, step executorStep) (err error) {
 	return errors.Annotatef(firstErr, message)
 }
 
+func (x *executor) record(span Span, err error) {
+	if x.spans == nil {
+		return
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	x.spans.Record(span)
+
+	// The history is only diagnostic, so failing to save it must not
+	// fail the operation. It's saved with the next state written.
+	if err := x.stateOps.SetHookHistory(x.spans.Spans()); err != nil {
+		x.logger.Warningf("cannot save hook history for %s: %v", x.unitName, err)
+	}
+}
+
 func (x *executor) writeState(newState State) error {
 	if err := newState.Validate(); err != nil {
 		return err
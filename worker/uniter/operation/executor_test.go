@@ -4,9 +4,11 @@
 package operation_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/juju/charm/v12/hooks"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
@@ -594,6 +596,131 @@ func (s *ExecutorSuite) TestLockUnlocksOnError_Commit(c *gc.C) {
 	c.Assert(mockLock.stepsCalledOnUnlock, gc.DeepEquals, expectedStepsOnUnlock)
 }
 
+func (s *ExecutorSuite) TestSpansRecorded(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	initialState := justInstalledState()
+	s.expectState(c, initialState)
+	spans := operation.NewSpanRecorder(10)
+	clk := &steppingClock{Clock: testclock.NewClock(time.Time{}), step: time.Second}
+	commitState := operation.State{
+		Kind: operation.RunHook,
+		Step: operation.Queued,
+		Hook: &hook.Info{Kind: hooks.Start},
+	}
+	op := &mockOperation{
+		needsLock: true,
+		prepare:   newStep(nil, nil),
+		execute:   newStep(nil, nil),
+		commit:    newStep(&commitState, nil),
+	}
+	mockLock := &mockLockFunc{op: op}
+	executor, err := operation.NewExecutor("test", operation.ExecutorConfig{
+		StateReadWriter: s.mockStateRW,
+		InitialState:    operation.State{Step: operation.Queued},
+		AcquireLock:     mockLock.newSucceedingLock(),
+		Logger:          loggo.GetLogger("test"),
+		Clock:           clk,
+		Spans:           spans,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The history is saved on the controller with the state written by
+	// the commit.
+	var saved []string
+	s.mockStateRW.EXPECT().SetState(gomock.Any()).DoAndReturn(func(arg params.SetUnitStateArg) error {
+		c.Check(arg.UniterState, gc.NotNil)
+		c.Assert(arg.HookHistory, gc.NotNil)
+		saved = append(saved, *arg.HookHistory)
+		return nil
+	})
+
+	err = executor.Run(op, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = executor.Skip(&mockOperation{commit: newStep(nil, errors.New("boom"))})
+	c.Assert(err, gc.ErrorMatches, `committing operation "mock operation" for test: boom`)
+
+	c.Assert(spans.Spans(), jc.DeepEquals, []operation.Span{{
+		Operation: "mock operation",
+		Queued:    time.Time{}.Add(time.Second),
+		LockWait:  time.Second,
+		Prepare:   time.Second,
+		Execute:   time.Second,
+		Commit:    time.Second,
+	}, {
+		Operation: "mock operation",
+		Queued:    time.Time{}.Add(9 * time.Second),
+		Commit:    time.Second,
+		Skipped:   true,
+		Error:     `committing operation "mock operation" for test: boom`,
+	}})
+
+	c.Assert(saved, gc.HasLen, 1)
+	var history []params.HookSpan
+	err = json.Unmarshal([]byte(saved[0]), &history)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0], jc.DeepEquals, spans.Spans()[0].Params())
+}
+
+func (s *ExecutorSuite) TestSpansSavedWithNextState(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+
+	initialState := justInstalledState()
+	s.expectState(c, initialState)
+	spans := operation.NewSpanRecorder(10)
+	executor, err := operation.NewExecutor("test", operation.ExecutorConfig{
+		StateReadWriter: s.mockStateRW,
+		InitialState:    operation.State{Step: operation.Queued},
+		AcquireLock:     (&mockLockFunc{}).newSucceedingLock(),
+		Logger:          loggo.GetLogger("test"),
+		Spans:           spans,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Skipping the operation writes no state, so nothing is saved.
+	err = executor.Skip(&mockOperation{commit: newStep(nil, nil)})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spans.Spans(), gc.HasLen, 1)
+
+	var saved []string
+	s.mockStateRW.EXPECT().SetState(gomock.Any()).DoAndReturn(func(arg params.SetUnitStateArg) error {
+		c.Assert(arg.HookHistory, gc.NotNil)
+		saved = append(saved, *arg.HookHistory)
+		return nil
+	})
+	prepareState := operation.State{
+		Kind: operation.RunHook,
+		Step: operation.Pending,
+		Hook: &hook.Info{Kind: hooks.ConfigChanged},
+	}
+	err = executor.Run(&mockOperation{
+		prepare: newStep(&prepareState, nil),
+		execute: newStep(nil, nil),
+		commit:  newStep(nil, nil),
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(saved, gc.HasLen, 1)
+	var history []params.HookSpan
+	err = json.Unmarshal([]byte(saved[0]), &history)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Skipped, jc.IsTrue)
+	c.Assert(spans.Spans(), gc.HasLen, 2)
+}
+
+// steppingClock advances by step every time Now is called.
+type steppingClock struct {
+	*testclock.Clock
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.Clock.Advance(c.step)
+	return c.Clock.Now()
+}
+
 type mockLockFunc struct {
 	noStepsCalledOnLock bool
 	stepsCalledOnUnlock []bool
@@ -175,6 +175,18 @@ func (rh *runHook) Execute(state State) (*State, error) {
 			Hook:     &rh.info,
 			HookStep: &step,
 		}.apply(state), runner.ErrTerminated
+	case cause == runner.ErrTimedOut:
+		// Record that the hook timed out so the failure can be reported
+		// as such; it is retried in the same way as any failed hook.
+		rh.logger.Errorf("hook %q (via %s) failed: %v", rh.name, handlerType, err)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		step = Pending
+		return stateChange{
+			Kind:         RunHook,
+			Step:         step,
+			Hook:         &rh.info,
+			HookTimedOut: true,
+		}.apply(state), ErrHookFailed
 	case err == nil:
 	default:
 		rh.logger.Errorf("hook %q (via %s) failed: %v", rh.name, handlerType, err)
@@ -4,6 +4,8 @@
 package operation
 
 import (
+	"encoding/json"
+
 	"github.com/juju/errors"
 	"gopkg.in/yaml.v2"
 
@@ -96,6 +98,10 @@ type State struct {
 	// state when initialising the agent and running any upgrade operation.
 	HookStep *Step `yaml:"hook-step,omitempty"`
 
+	// HookTimedOut is true if the hook in a RunHook operation failed
+	// because it was killed for running longer than its timeout.
+	HookTimedOut bool `yaml:"hook-timed-out,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
@@ -216,6 +222,7 @@ type stateChange struct {
 	ActionId        *string
 	CharmURL        string
 	HasRunStatusSet bool
+	HookTimedOut    bool
 }
 
 func (change stateChange) apply(state State) *State {
@@ -225,6 +232,7 @@ func (change stateChange) apply(state State) *State {
 	state.HookStep = change.HookStep
 	state.ActionId = change.ActionId
 	state.CharmURL = change.CharmURL
+	state.HookTimedOut = change.HookTimedOut
 	state.StatusSet = state.StatusSet || change.HasRunStatusSet
 	return &state
 }
@@ -232,6 +240,9 @@ func (change stateChange) apply(state State) *State {
 // StateOps reads and writes uniter state from/to the controller.
 type StateOps struct {
 	unitStateRW UnitStateReadWriter
+
+	// hookHistory, if set, is saved with the next state written.
+	hookHistory *string
 }
 
 // NewStateOps returns a new StateOps.
@@ -278,5 +289,30 @@ func (f *StateOps) Write(st *State) error {
 		return errors.Trace(err)
 	}
 	s := string(data)
-	return f.unitStateRW.SetState(params.SetUnitStateArg{UniterState: &s})
+	err = f.unitStateRW.SetState(params.SetUnitStateArg{
+		UniterState: &s,
+		HookHistory: f.hookHistory,
+	})
+	if err != nil {
+		return err
+	}
+	f.hookHistory = nil
+	return nil
+}
+
+// SetHookHistory sets the spans to store on the controller with the next
+// state written, replacing any previously stored history, so that they
+// can be shown to users.
+func (f *StateOps) SetHookHistory(spans []Span) error {
+	history := make([]params.HookSpan, len(spans))
+	for i, span := range spans {
+		history[i] = span.Params()
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s := string(data)
+	f.hookHistory = &s
+	return nil
 }
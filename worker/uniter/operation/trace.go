@@ -0,0 +1,131 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/juju/rpc/params"
+)
+
+// DefaultSpanHistory is the number of spans kept by a SpanRecorder when
+// no size is given.
+const DefaultSpanHistory = 100
+
+// Span records how long each stage of running an operation took.
+type Span struct {
+	// Operation is the string representation of the operation.
+	Operation string
+
+	// Queued is when the operation was handed to the executor.
+	Queued time.Time
+
+	// LockWait is the time spent waiting for the machine lock. It is
+	// zero for operations that don't need the lock.
+	LockWait time.Duration
+
+	// Prepare, Execute and Commit are the times spent in each step of
+	// the operation. Execute is the hook, action or command runtime.
+	Prepare time.Duration
+	Execute time.Duration
+	Commit  time.Duration
+
+	// Skipped is true if the operation was skipped rather than run.
+	Skipped bool
+
+	// Error holds the error that ended the operation, if any.
+	Error string
+}
+
+// Total returns the time from the operation being queued to it finishing.
+func (s Span) Total() time.Duration {
+	return s.LockWait + s.Prepare + s.Execute + s.Commit
+}
+
+// Report returns a map describing the span, for use in engine reports.
+func (s Span) Report() map[string]interface{} {
+	result := map[string]interface{}{
+		"operation": s.Operation,
+		"queued":    s.Queued.Format(time.RFC3339Nano),
+		"lock-wait": s.LockWait.String(),
+		"prepare":   s.Prepare.String(),
+		"execute":   s.Execute.String(),
+		"commit":    s.Commit.String(),
+		"total":     s.Total().String(),
+	}
+	if s.Skipped {
+		result["skipped"] = true
+	}
+	if s.Error != "" {
+		result["error"] = s.Error
+	}
+	return result
+}
+
+// Params returns the span as sent to the controller.
+func (s Span) Params() params.HookSpan {
+	return params.HookSpan{
+		Operation: s.Operation,
+		Queued:    s.Queued,
+		LockWait:  s.LockWait,
+		Prepare:   s.Prepare,
+		Execute:   s.Execute,
+		Commit:    s.Commit,
+		Skipped:   s.Skipped,
+		Error:     s.Error,
+	}
+}
+
+// SpanRecorder keeps the most recent operation spans in a bounded ring
+// buffer. It is safe for concurrent use.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []Span
+	next  int
+	full  bool
+}
+
+// NewSpanRecorder returns a SpanRecorder that keeps the last size spans.
+// If size is not positive, DefaultSpanHistory is used.
+func NewSpanRecorder(size int) *SpanRecorder {
+	if size <= 0 {
+		size = DefaultSpanHistory
+	}
+	return &SpanRecorder{spans: make([]Span, size)}
+}
+
+// Record adds a span, discarding the oldest span if the buffer is full.
+func (r *SpanRecorder) Record(span Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[r.next] = span
+	r.next = (r.next + 1) % len(r.spans)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Spans returns the recorded spans, oldest first.
+func (r *SpanRecorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Span(nil), r.spans[:r.next]...)
+	}
+	result := make([]Span, 0, len(r.spans))
+	result = append(result, r.spans[r.next:]...)
+	return append(result, r.spans[:r.next]...)
+}
+
+// Report returns the recorded spans, oldest first, for use in engine
+// reports.
+func (r *SpanRecorder) Report() []map[string]interface{} {
+	spans := r.Spans()
+	result := make([]map[string]interface{}, len(spans))
+	for i, span := range spans {
+		result[i] = span.Report()
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+type SpanRecorderSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SpanRecorderSuite{})
+
+func (s *SpanRecorderSuite) TestEmpty(c *gc.C) {
+	spans := operation.NewSpanRecorder(3)
+	c.Assert(spans.Spans(), gc.HasLen, 0)
+	c.Assert(spans.Report(), gc.HasLen, 0)
+}
+
+func (s *SpanRecorderSuite) TestRingBuffer(c *gc.C) {
+	spans := operation.NewSpanRecorder(3)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		spans.Record(operation.Span{Operation: name})
+	}
+	var names []string
+	for _, span := range spans.Spans() {
+		names = append(names, span.Operation)
+	}
+	c.Assert(names, jc.DeepEquals, []string{"c", "d", "e"})
+}
+
+func (s *SpanRecorderSuite) TestReport(c *gc.C) {
+	spans := operation.NewSpanRecorder(0)
+	spans.Record(operation.Span{
+		Operation: "run install hook",
+		Queued:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LockWait:  2 * time.Second,
+		Execute:   3 * time.Second,
+		Error:     "hook failed",
+	})
+	c.Assert(spans.Report(), jc.DeepEquals, []map[string]interface{}{{
+		"operation": "run install hook",
+		"queued":    "2024-01-02T03:04:05Z",
+		"lock-wait": "2s",
+		"prepare":   "0s",
+		"execute":   "3s",
+		"commit":    "0s",
+		"total":     "5s",
+		"error":     "hook failed",
+	}})
+}
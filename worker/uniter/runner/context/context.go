@@ -128,6 +128,7 @@ type HookUnit interface {
 	Application() (*uniter.Application, error)
 	ApplicationName() string
 	ConfigSettings() (charm.Settings, error)
+	HookTimeouts() (string, error)
 	LogActionMessage(names.ActionTag, string) error
 	Name() string
 	NetworkInfo(bindings []string, relationId *int) (map[string]params.NetworkInfoResult, error)
@@ -813,6 +814,16 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// HookTimeouts returns the hook timeouts set in the application config,
+// or an empty string if none are set or the controller can't report them.
+func (ctx *HookContext) HookTimeouts() (string, error) {
+	timeouts, err := ctx.unit.HookTimeouts()
+	if errors.Is(err, errors.NotImplemented) {
+		return "", nil
+	}
+	return timeouts, errors.Trace(err)
+}
+
 func (ctx *HookContext) getSecretsBackend() (secrets.BackendsClient, error) {
 	if ctx.secretsBackend != nil {
 		return ctx.secretsBackend, nil
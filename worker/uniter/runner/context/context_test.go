@@ -968,6 +968,26 @@ func (s *mockHookContextSuite) TestClosePortRange(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *mockHookContextSuite) TestHookTimeouts(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockUnit.EXPECT().HookTimeouts().Return("config-changed=10m", nil)
+
+	hookContext := context.NewMockUnitHookContext(s.mockUnit, model.IAAS, s.mockLeadership)
+	timeouts, err := hookContext.HookTimeouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeouts, gc.Equals, "config-changed=10m")
+}
+
+func (s *mockHookContextSuite) TestHookTimeoutsNotImplemented(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockUnit.EXPECT().HookTimeouts().Return("", errors.NotImplementedf("HookTimeouts"))
+
+	hookContext := context.NewMockUnitHookContext(s.mockUnit, model.IAAS, s.mockLeadership)
+	timeouts, err := hookContext.HookTimeouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeouts, gc.Equals, "")
+}
+
 func (s *mockHookContextSuite) setupMocks(c *gc.C) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 	s.mockUnit = mocks.NewMockHookUnit(ctrl)
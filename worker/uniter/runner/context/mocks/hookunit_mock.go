@@ -101,6 +101,21 @@ func (mr *MockHookUnitMockRecorder) ConfigSettings() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigSettings", reflect.TypeOf((*MockHookUnit)(nil).ConfigSettings))
 }
 
+// HookTimeouts mocks base method.
+func (m *MockHookUnit) HookTimeouts() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookTimeouts")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookTimeouts indicates an expected call of HookTimeouts.
+func (mr *MockHookUnitMockRecorder) HookTimeouts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookTimeouts", reflect.TypeOf((*MockHookUnit)(nil).HookTimeouts))
+}
+
 // LogActionMessage mocks base method.
 func (m *MockHookUnit) LogActionMessage(arg0 names.ActionTag, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -4,6 +4,8 @@
 package runner
 
 import (
+	"time"
+
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
@@ -15,3 +17,9 @@ var (
 func RunnerPaths(rnr Runner) context.Paths {
 	return rnr.(*runner).paths
 }
+
+// RunCharmHookOnRemote runs the named hook with the runner's remote
+// executor and the given timeout.
+func RunCharmHookOnRemote(rnr Runner, hookName string, timeout time.Duration) (HookHandlerType, error) {
+	return rnr.(*runner).runCharmHookWithLocation(hookName, "hooks", runOnRemote, timeout, 0)
+}
@@ -32,12 +32,14 @@ type Factory interface {
 }
 
 // NewFactory returns a Factory capable of creating runners for executing
-// charm hooks, actions and commands.
+// charm hooks, actions and commands. The options are passed to every
+// runner it creates.
 func NewFactory(
 	paths context.Paths,
 	contextFactory context.ContextFactory,
 	newProcessRunner NewRunnerFunc,
 	remoteExecutor ExecFunc,
+	options ...Option,
 ) (
 	Factory, error,
 ) {
@@ -46,6 +48,7 @@ func NewFactory(
 		contextFactory:   contextFactory,
 		newProcessRunner: newProcessRunner,
 		remoteExecutor:   remoteExecutor,
+		options:          options,
 	}
 
 	return f, nil
@@ -58,6 +61,7 @@ type factory struct {
 	paths            context.Paths
 	newProcessRunner NewRunnerFunc
 	remoteExecutor   ExecFunc
+	options          []Option
 }
 
 // NewCommandRunner exists to satisfy the Factory interface.
@@ -66,7 +70,7 @@ func (f *factory) NewCommandRunner(commandInfo context.CommandInfo) (Runner, err
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor)
+	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor, f.options...)
 	return runner, nil
 }
 
@@ -80,7 +84,7 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor)
+	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor, f.options...)
 	return runner, nil
 }
 
@@ -112,7 +116,7 @@ func (f *factory) NewActionRunner(action *uniter.Action, cancel <-chan struct{})
 	if err != nil {
 		return nil, charmrunner.NewBadActionError(name, err.Error())
 	}
-	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor)
+	runner := f.newProcessRunner(ctx, f.paths, f.remoteExecutor, f.options...)
 	return runner, nil
 }
 
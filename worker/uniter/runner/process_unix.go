@@ -0,0 +1,23 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//go:build !windows
+
+package runner
+
+import (
+	"os"
+	"syscall"
+)
+
+// hookSysProcAttr returns the attributes for hook processes, which are
+// started in their own process group.
+func hookSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends the signal to the process group led by the
+// process.
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return syscall.Kill(-process.Pid, sig)
+}
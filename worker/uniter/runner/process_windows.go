@@ -0,0 +1,22 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+//go:build windows
+
+package runner
+
+import (
+	"os"
+	"syscall"
+)
+
+// hookSysProcAttr returns the attributes for hook processes. There are
+// no process groups on windows.
+func hookSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// signalProcessGroup sends the signal to the process only.
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return process.Signal(sig)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/kballard/go-shellquote"
 
 	"github.com/juju/juju/core/actions"
+	corecharm "github.com/juju/juju/core/charm"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/worker/common/charmrunner"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -114,6 +115,7 @@ type Option func(*options)
 
 type options struct {
 	tokenGenerator TokenGenerator
+	clock          clock.Clock
 }
 
 // WithTokenGenerator returns an Option that sets the token generator for the
@@ -124,9 +126,18 @@ func WithTokenGenerator(tg TokenGenerator) Option {
 	}
 }
 
+// WithClock returns an Option that sets the clock the runner uses to
+// time out hooks and commands.
+func WithClock(clock clock.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
 func newOptions() *options {
 	return &options{
 		tokenGenerator: &tokenGenerator{},
+		clock:          clock.WallClock,
 	}
 }
 
@@ -141,6 +152,7 @@ func NewRunner(context context.Context, paths context.Paths, remoteExecutor Exec
 		paths:          paths,
 		remoteExecutor: remoteExecutor,
 		tokenGenerator: opts.tokenGenerator,
+		clock:          opts.clock,
 	}
 }
 
@@ -196,6 +208,7 @@ type runner struct {
 	// remoteExecutor executes commands on a remote workload pod for CAAS.
 	remoteExecutor ExecFunc
 	tokenGenerator TokenGenerator
+	clock          clock.Clock
 }
 
 func (runner *runner) logger() loggo.Logger {
@@ -238,13 +251,13 @@ func (runner *runner) RunCommands(commands string, runLocation RunLocation) (*ut
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	result, err := runner.runCommandsWithTimeout(commands, 0, clock.WallClock, rMode, nil)
+	result, err := runner.runCommandsWithTimeout(commands, 0, rMode, nil)
 	return result, runner.context.Flush("run commands", err)
 }
 
 // runCommandsWithTimeout is a helper to abstract common code between run commands and
 // juju-exec as an action
-func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Duration, clock clock.Clock, rMode runMode, abort <-chan struct{}) (*utilexec.ExecResponse, error) {
+func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Duration, rMode runMode, abort <-chan struct{}) (*utilexec.ExecResponse, error) {
 	token, err := runner.tokenGenerator.Generate(rMode == runOnRemote)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -291,7 +304,7 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 	if timeout != 0 {
 		cancel = make(chan struct{})
 		go func() {
-			<-clock.After(timeout)
+			<-runner.clock.After(timeout)
 			close(cancel)
 		}()
 	}
@@ -305,7 +318,7 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 		Commands:      []string{commands},
 		Env:           env,
 		WorkingDir:    runner.paths.GetCharmDir(),
-		Clock:         clock,
+		Clock:         runner.clock,
 		ProcessSetter: runner.context.SetProcess,
 		Cancel:        cancel,
 		Stdout:        &stdout,
@@ -343,7 +356,7 @@ func (runner *runner) runJujuExecAction() (err error) {
 		return errors.Trace(err)
 	}
 
-	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), clock.WallClock, rMode, data.Cancel)
+	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), rMode, data.Cancel)
 	if results != nil {
 		if err := runner.updateActionResults(results); err != nil {
 			return runner.context.Flush("juju-exec", err)
@@ -412,15 +425,49 @@ func (runner *runner) RunAction(actionName string) (HookHandlerType, error) {
 		return InvalidHookHandler, errors.Trace(err)
 	}
 	runner.logger().Debugf("running action %q on %v", actionName, rMode)
-	return runner.runCharmHookWithLocation(actionName, "actions", rMode)
+	return runner.runCharmHookWithLocation(actionName, "actions", rMode, 0, 0)
 }
 
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) (HookHandlerType, error) {
-	return runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal)
+	policy := runner.hookTimeoutPolicy()
+	return runner.runCharmHookWithLocation(hookName, "hooks", runOnLocal, policy.Timeout(hookName), policy.KillGrace)
 }
 
-func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, rMode runMode) (hookHandlerType HookHandlerType, err error) {
+// hookTimeoutsGetter is implemented by contexts that can read the hook
+// timeouts set in the application config.
+type hookTimeoutsGetter interface {
+	HookTimeouts() (string, error)
+}
+
+// hookTimeoutPolicy returns the hook timeouts declared in the charm
+// metadata, overridden by any set in the application config.
+func (runner *runner) hookTimeoutPolicy() HookTimeoutPolicy {
+	policy, err := ReadHookTimeoutPolicy(runner.paths.GetCharmDir())
+	if err != nil {
+		runner.logger().Warningf("ignoring hook timeouts in charm metadata: %v", err)
+		policy = HookTimeoutPolicy{KillGrace: DefaultHookKillGrace}
+	}
+	getter, ok := runner.context.(hookTimeoutsGetter)
+	if !ok {
+		return policy
+	}
+	value, err := getter.HookTimeouts()
+	if err != nil {
+		runner.logger().Warningf("ignoring hook timeouts in application config: %v", err)
+		return policy
+	}
+	overrides, err := corecharm.ParseHookTimeouts(value)
+	if err != nil {
+		runner.logger().Warningf("ignoring hook timeouts in application config: %v", err)
+		return policy
+	}
+	return policy.Override(overrides)
+}
+
+func (runner *runner) runCharmHookWithLocation(
+	hookName, charmLocation string, rMode runMode, timeout, killGrace time.Duration,
+) (hookHandlerType HookHandlerType, err error) {
 	token, err := runner.tokenGenerator.Generate(rMode == runOnRemote)
 	if err != nil {
 		return InvalidHookHandler, errors.Trace(err)
@@ -498,9 +545,9 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, r
 		return InvalidHookHandler, err
 	}
 	if rMode == runOnRemote {
-		return hookHandlerType, runner.runCharmProcessOnRemote(hookScript, hookName, charmDir, env)
+		return hookHandlerType, runner.runCharmProcessOnRemote(hookScript, hookName, charmDir, env, timeout)
 	}
-	return hookHandlerType, runner.runCharmProcessOnLocal(hookScript, hookName, charmDir, env)
+	return hookHandlerType, runner.runCharmProcessOnLocal(hookScript, hookName, charmDir, env, timeout, killGrace)
 }
 
 // loggerAdaptor implements MessageReceiver and
@@ -555,7 +602,7 @@ func (b *bufferAdaptor) Bytes() []byte {
 	return b.outCopy.Bytes()
 }
 
-func (runner *runner) runCharmProcessOnRemote(hook, hookName, charmDir string, env []string) error {
+func (runner *runner) runCharmProcessOnRemote(hook, hookName, charmDir string, env []string, timeout time.Duration) error {
 	var cancel <-chan struct{}
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
@@ -599,6 +646,14 @@ func (runner *runner) runCharmProcessOnRemote(hook, hookName, charmDir string, e
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// A remote process can't be signalled, so a hook that times out is
+	// cancelled straight away rather than given a kill grace period.
+	timedOut := make(chan struct{})
+	if timeout > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		cancel = runner.cancelOnTimeout(cancel, hookName, timeout, timedOut, done)
+	}
 	resp, err := executor(
 		ExecParams{
 			Commands:     []string{hook},
@@ -618,20 +673,51 @@ func (runner *runner) runCharmProcessOnRemote(hook, hookName, charmDir string, e
 			return errors.Trace(err)
 		}
 	}
+	select {
+	case <-timedOut:
+		return errors.Annotatef(ErrTimedOut, "hook %q after %v", hookName, timeout)
+	default:
+	}
 
 	return errors.Trace(err)
 }
 
+// cancelOnTimeout returns a channel that is closed when the given cancel
+// channel is closed, or when the timeout passes before done is closed.
+// The timedOut channel is closed when the timeout passes.
+func (runner *runner) cancelOnTimeout(
+	cancel <-chan struct{}, hookName string, timeout time.Duration, timedOut chan<- struct{}, done <-chan struct{},
+) <-chan struct{} {
+	abort := make(chan struct{})
+	go func() {
+		select {
+		case <-runner.clock.After(timeout):
+			runner.logger().Warningf("hook %q timed out after %v, cancelling", hookName, timeout)
+			close(timedOut)
+		case <-cancel:
+		case <-done:
+			return
+		}
+		close(abort)
+	}()
+	return abort
+}
+
 const (
 	// ErrTerminated indicate the hook or action exited due to a SIGTERM or SIGKILL signal.
 	ErrTerminated = errors.ConstError("terminated")
 )
 
 // Check still tested
-func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, env []string) error {
+func (runner *runner) runCharmProcessOnLocal(
+	hook, hookName, charmDir string, env []string, timeout, killGrace time.Duration,
+) error {
 	ps := exec.Command(hook)
 	ps.Env = env
 	ps.Dir = charmDir
+	// Run the hook in its own process group, so that any processes it
+	// starts are signalled with it when it is cancelled or times out.
+	ps.SysProcAttr = hookSysProcAttr()
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
@@ -673,17 +759,21 @@ func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, en
 
 	err = ps.Start()
 	var exitErr error
+	timedOut := make(chan struct{})
 	if err == nil {
 		done := make(chan struct{})
 		if cancel != nil {
 			go func() {
 				select {
 				case <-cancel:
-					_ = ps.Process.Kill()
+					_ = signalProcessGroup(ps.Process, syscall.SIGKILL)
 				case <-done:
 				}
 			}()
 		}
+		if timeout > 0 {
+			go runner.killOnTimeout(ps.Process, hookName, timeout, killGrace, timedOut, done)
+		}
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
@@ -709,6 +799,11 @@ func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, en
 			return errors.Trace(err)
 		}
 	}
+	select {
+	case <-timedOut:
+		return errors.Annotatef(ErrTimedOut, "hook %q after %v", hookName, timeout)
+	default:
+	}
 	if exitError, ok := exitErr.(*exec.ExitError); ok && exitError != nil {
 		waitStatus := exitError.ProcessState.Sys().(syscall.WaitStatus)
 		if waitStatus.Signal() == syscall.SIGTERM || waitStatus.Signal() == syscall.SIGKILL {
@@ -719,6 +814,31 @@ func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, en
 	return errors.Trace(exitErr)
 }
 
+// killOnTimeout sends SIGTERM to the hook's process group if the hook is
+// still running after the timeout, and SIGKILL if it is still running
+// killGrace later. The timedOut channel is closed before any signal is
+// sent.
+func (runner *runner) killOnTimeout(
+	process *os.Process, hookName string, timeout, killGrace time.Duration, timedOut chan<- struct{}, done <-chan struct{},
+) {
+	select {
+	case <-runner.clock.After(timeout):
+	case <-done:
+		return
+	}
+	close(timedOut)
+	runner.logger().Warningf("hook %q timed out after %v, sending SIGTERM", hookName, timeout)
+	_ = signalProcessGroup(process, syscall.SIGTERM)
+
+	select {
+	case <-runner.clock.After(killGrace):
+	case <-done:
+		return
+	}
+	runner.logger().Warningf("hook %q did not exit within %v of SIGTERM, sending SIGKILL", hookName, killGrace)
+	_ = signalProcessGroup(process, syscall.SIGKILL)
+}
+
 // discoverHookHandler checks to see if the dispatch script exists, if not,
 // check for the given hookName.  Based on what is discovered, return the
 // HookHandlerType and the actual script to be run.
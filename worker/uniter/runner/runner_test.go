@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/juju/charm/v12/hooks"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	envtesting "github.com/juju/testing"
@@ -234,6 +236,7 @@ type MockContext struct {
 	flushFailure    error
 	flushResult     error
 	modelType       model.ModelType
+	hookTimeouts    string
 }
 
 func (ctx *MockContext) Id() string {
@@ -290,6 +293,10 @@ func (ctx *MockContext) UpdateActionResults(keys []string, value interface{}) er
 	return nil
 }
 
+func (ctx *MockContext) HookTimeouts() (string, error) {
+	return ctx.hookTimeouts, nil
+}
+
 func (ctx *MockContext) ModelType() model.ModelType {
 	if ctx.modelType == "" {
 		return model.IAAS
@@ -413,6 +420,186 @@ func (s *RunMockContextSuite) TestRunHookFlushSuccess(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	ctx := &MockContext{}
+	s.makeSlowHook(c, "", `
+hook-timeout:
+  kill-grace: 30s
+  hooks:
+    something-happened: 1m
+`)
+	clock := testclock.NewClock(time.Now())
+	result := s.runHook(runner.NewRunner(ctx, s.paths, nil, runner.WithClock(clock)))
+
+	c.Assert(clock.WaitAdvance(time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(errors.Cause(ctx.flushFailure), gc.Equals, runner.ErrTimedOut)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" after 1m0s: timed out`)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutIgnoresSIGTERM(c *gc.C) {
+	ctx := &MockContext{}
+	s.makeSlowHook(c, "trap '' TERM", `
+hook-timeout:
+  kill-grace: 30s
+  hooks:
+    something-happened: 1m
+`)
+	clock := testclock.NewClock(time.Now())
+	result := s.runHook(runner.NewRunner(ctx, s.paths, nil, runner.WithClock(clock)))
+
+	c.Assert(clock.WaitAdvance(time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case <-result:
+		c.Fatalf("hook exited on SIGTERM")
+	case <-time.After(testing.ShortWait):
+	}
+	c.Assert(clock.WaitAdvance(30*time.Second, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(errors.Cause(ctx.flushFailure), gc.Equals, runner.ErrTimedOut)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutKillsChildren(c *gc.C) {
+	ctx := &MockContext{}
+	pidFile := filepath.Join(c.MkDir(), "child.pid")
+	s.makeSlowHook(c, "/bin/sleep 60 &\necho $! > "+pidFile, `
+hook-timeout:
+  kill-grace: 30s
+  hooks:
+    something-happened: 1m
+`)
+	clock := testclock.NewClock(time.Now())
+	result := s.runHook(runner.NewRunner(ctx, s.paths, nil, runner.WithClock(clock)))
+
+	c.Assert(clock.WaitAdvance(time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(errors.Cause(ctx.flushFailure), gc.Equals, runner.ErrTimedOut)
+
+	data, err := os.ReadFile(pidFile)
+	c.Assert(err, jc.ErrorIsNil)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if !processRunning(pid) {
+			return
+		}
+	}
+	c.Fatalf("hook child process %d still running", pid)
+}
+
+// processRunning reports whether the process is running. Zombies,
+// which may not be reaped in test containers, don't count.
+func processRunning(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutFromApplicationConfig(c *gc.C) {
+	ctx := &MockContext{hookTimeouts: "something-happened=2m"}
+	s.makeSlowHook(c, "", `
+hook-timeout:
+  hooks:
+    something-happened: 1m
+`)
+	clock := testclock.NewClock(time.Now())
+	result := s.runHook(runner.NewRunner(ctx, s.paths, nil, runner.WithClock(clock)))
+
+	c.Assert(clock.WaitAdvance(2*time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" after 2m0s: timed out`)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutInvalidApplicationConfig(c *gc.C) {
+	ctx := &MockContext{hookTimeouts: "something-happened=soon"}
+	s.makeSlowHook(c, "", `
+hook-timeout:
+  hooks:
+    something-happened: 1m
+`)
+	clock := testclock.NewClock(time.Now())
+	result := s.runHook(runner.NewRunner(ctx, s.paths, nil, runner.WithClock(clock)))
+
+	c.Assert(clock.WaitAdvance(time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" after 1m0s: timed out`)
+}
+
+func (s *RunMockContextSuite) TestRunHookOnRemoteTimeout(c *gc.C) {
+	ctx := &MockContext{modelType: model.CAAS}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	execCount := 0
+	execFunc := func(params runner.ExecParams) (*exec.ExecResponse, error) {
+		execCount++
+		if execCount == 1 {
+			// Reading the remote environment.
+			return &exec.ExecResponse{}, nil
+		}
+		select {
+		case <-params.Cancel:
+			return &exec.ExecResponse{Code: -1}, errors.New("cancelled")
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for cancel")
+		}
+		return nil, nil
+	}
+	clock := testclock.NewClock(time.Now())
+	rnr := runner.NewRunner(ctx, s.paths, execFunc, runner.WithClock(clock))
+	result := make(chan error, 1)
+	go func() {
+		_, err := runner.RunCharmHookOnRemote(rnr, "something-happened", time.Minute)
+		result <- err
+	}()
+
+	c.Assert(clock.WaitAdvance(time.Minute, testing.LongWait, 1), jc.ErrorIsNil)
+	s.assertHookResult(c, result)
+	c.Assert(execCount, gc.Equals, 2)
+	c.Assert(errors.Cause(ctx.flushFailure), gc.Equals, runner.ErrTimedOut)
+}
+
+// runHook runs the something-happened hook in the background, returning
+// a channel that receives its result.
+func (s *RunMockContextSuite) runHook(rnr runner.Runner) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := rnr.RunHook("something-happened")
+		result <- err
+	}()
+	return result
+}
+
+func (s *RunMockContextSuite) assertHookResult(c *gc.C, result <-chan error) {
+	select {
+	case err := <-result:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for hook")
+	}
+}
+
+// makeSlowHook writes a hook that runs for far longer than the test,
+// and charm metadata holding the given hook-timeout section.
+func (s *RunMockContextSuite) makeSlowHook(c *gc.C, prelude, metadata string) {
+	charmDir := s.paths.GetCharmDir()
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, charmDir)
+	err := os.WriteFile(filepath.Join(charmDir, "metadata.yaml"), []byte(metadata), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.WriteFile(filepath.Join(charmDir, "hooks", hookName), []byte(
+		"#!/bin/bash\n"+prelude+"\n/bin/sleep 60\n"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	corecharm "github.com/juju/juju/core/charm"
+)
+
+const (
+	// ErrTimedOut indicates the hook was killed because it ran for longer
+	// than its timeout.
+	ErrTimedOut = errors.ConstError("timed out")
+
+	// DefaultHookKillGrace is how long a hook has to exit after being
+	// sent SIGTERM on timeout, before it is sent SIGKILL.
+	DefaultHookKillGrace = 30 * time.Second
+)
+
+// HookTimeoutPolicy describes how long hooks may run before they are
+// killed. A zero timeout means the hook may run forever.
+type HookTimeoutPolicy struct {
+	// Default is the timeout for hooks not named in Hooks.
+	Default time.Duration
+
+	// Hooks holds timeouts for individual hooks, keyed by hook name.
+	Hooks map[string]time.Duration
+
+	// KillGrace is the time between sending SIGTERM and SIGKILL to a
+	// hook that has timed out.
+	KillGrace time.Duration
+}
+
+// Timeout returns the timeout for the named hook.
+func (p HookTimeoutPolicy) Timeout(hookName string) time.Duration {
+	if timeout, ok := p.Hooks[hookName]; ok {
+		return timeout
+	}
+	return p.Default
+}
+
+// Override returns a copy of the policy with the given timeouts, as
+// returned by corecharm.ParseHookTimeouts, taking precedence.
+func (p HookTimeoutPolicy) Override(timeouts map[string]time.Duration) HookTimeoutPolicy {
+	result := HookTimeoutPolicy{
+		Default:   p.Default,
+		KillGrace: p.KillGrace,
+	}
+	for name, timeout := range p.Hooks {
+		result.setHookTimeout(name, timeout)
+	}
+	for name, timeout := range timeouts {
+		switch name {
+		case corecharm.HookTimeoutDefault:
+			result.Default = timeout
+		case corecharm.HookTimeoutKillGrace:
+			result.KillGrace = timeout
+		default:
+			result.setHookTimeout(name, timeout)
+		}
+	}
+	return result
+}
+
+func (p *HookTimeoutPolicy) setHookTimeout(hookName string, timeout time.Duration) {
+	if p.Hooks == nil {
+		p.Hooks = make(map[string]time.Duration)
+	}
+	p.Hooks[hookName] = timeout
+}
+
+// hookTimeoutMeta is the "hook-timeout" section of a charm's
+// metadata.yaml, for example:
+//
+//	hook-timeout:
+//	  default: 1h
+//	  kill-grace: 1m
+//	  hooks:
+//	    config-changed: 10m
+type hookTimeoutMeta struct {
+	HookTimeout struct {
+		Default   string            `yaml:"default"`
+		KillGrace string            `yaml:"kill-grace"`
+		Hooks     map[string]string `yaml:"hooks"`
+	} `yaml:"hook-timeout"`
+}
+
+// ReadHookTimeoutPolicy reads the hook timeout policy from the
+// "hook-timeout" section of the metadata.yaml in the charm directory.
+// Charms without that section get a policy with no timeouts.
+func ReadHookTimeoutPolicy(charmDir string) (HookTimeoutPolicy, error) {
+	policy := HookTimeoutPolicy{KillGrace: DefaultHookKillGrace}
+	data, err := os.ReadFile(filepath.Join(charmDir, "metadata.yaml"))
+	if os.IsNotExist(err) {
+		return policy, nil
+	} else if err != nil {
+		return policy, errors.Trace(err)
+	}
+
+	var meta hookTimeoutMeta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return policy, errors.Annotate(err, "parsing hook-timeout")
+	}
+	parse := func(key, value string) (time.Duration, error) {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, errors.NotValidf("hook-timeout %s %q", key, value)
+		}
+		return d, nil
+	}
+	if v := meta.HookTimeout.Default; v != "" {
+		if policy.Default, err = parse("default", v); err != nil {
+			return policy, errors.Trace(err)
+		}
+	}
+	if v := meta.HookTimeout.KillGrace; v != "" {
+		if policy.KillGrace, err = parse("kill-grace", v); err != nil {
+			return policy, errors.Trace(err)
+		}
+	}
+	for name, v := range meta.HookTimeout.Hooks {
+		d, err := parse(name, v)
+		if err != nil {
+			return policy, errors.Trace(err)
+		}
+		policy.setHookTimeout(name, d)
+	}
+	return policy, nil
+}
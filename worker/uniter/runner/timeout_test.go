@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	envtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+type HookTimeoutSuite struct {
+	envtesting.IsolationSuite
+}
+
+var _ = gc.Suite(&HookTimeoutSuite{})
+
+func (s *HookTimeoutSuite) writeMetadata(c *gc.C, content string) string {
+	dir := c.MkDir()
+	err := os.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return dir
+}
+
+func (s *HookTimeoutSuite) TestNoMetadata(c *gc.C) {
+	policy, err := runner.ReadHookTimeoutPolicy(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Timeout("install"), gc.Equals, time.Duration(0))
+	c.Assert(policy.KillGrace, gc.Equals, runner.DefaultHookKillGrace)
+}
+
+func (s *HookTimeoutSuite) TestNoHookTimeout(c *gc.C) {
+	dir := s.writeMetadata(c, "name: wordpress\nsummary: blog\n")
+	policy, err := runner.ReadHookTimeoutPolicy(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, runner.HookTimeoutPolicy{KillGrace: runner.DefaultHookKillGrace})
+}
+
+func (s *HookTimeoutSuite) TestPolicy(c *gc.C) {
+	dir := s.writeMetadata(c, `
+name: wordpress
+hook-timeout:
+  default: 1h
+  kill-grace: 1m
+  hooks:
+    config-changed: 10m
+`)
+	policy, err := runner.ReadHookTimeoutPolicy(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Timeout("config-changed"), gc.Equals, 10*time.Minute)
+	c.Assert(policy.Timeout("install"), gc.Equals, time.Hour)
+	c.Assert(policy.KillGrace, gc.Equals, time.Minute)
+}
+
+func (s *HookTimeoutSuite) TestInvalidDuration(c *gc.C) {
+	dir := s.writeMetadata(c, `
+hook-timeout:
+  hooks:
+    config-changed: soon
+`)
+	_, err := runner.ReadHookTimeoutPolicy(dir)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `hook-timeout config-changed "soon" not valid`)
+}
+
+func (s *HookTimeoutSuite) TestOverride(c *gc.C) {
+	policy := runner.HookTimeoutPolicy{
+		Default:   time.Hour,
+		KillGrace: time.Minute,
+		Hooks: map[string]time.Duration{
+			"config-changed": 10 * time.Minute,
+			"install":        20 * time.Minute,
+		},
+	}
+	overridden := policy.Override(map[string]time.Duration{
+		"kill-grace":     5 * time.Second,
+		"config-changed": 2 * time.Minute,
+	})
+	c.Assert(overridden, jc.DeepEquals, runner.HookTimeoutPolicy{
+		Default:   time.Hour,
+		KillGrace: 5 * time.Second,
+		Hooks: map[string]time.Duration{
+			"config-changed": 2 * time.Minute,
+			"install":        20 * time.Minute,
+		},
+	})
+	// The original policy is unchanged.
+	c.Assert(policy.Timeout("config-changed"), gc.Equals, 10*time.Minute)
+}
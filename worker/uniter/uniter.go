@@ -109,6 +109,7 @@ type Uniter struct {
 
 	operationFactory        operation.Factory
 	operationExecutor       operation.Executor
+	operationSpans          *operation.SpanRecorder
 	newOperationExecutor    NewOperationExecutorFunc
 	newProcessRunner        runner.NewRunnerFunc
 	newDeployer             charm.NewDeployerFunc
@@ -920,7 +921,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		remoteExecutor = u.newRemoteRunnerExecutor(u.unit, u.paths)
 	}
 	runnerFactory, err := runner.NewFactory(
-		u.paths, contextFactory, u.newProcessRunner, remoteExecutor,
+		u.paths, contextFactory, u.newProcessRunner, remoteExecutor, runner.WithClock(u.clock),
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -946,11 +947,14 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		CharmURL: charmURL,
 	}
 
+	u.operationSpans = operation.NewSpanRecorder(operation.DefaultSpanHistory)
 	operationExecutor, err := u.newOperationExecutor(u.unit.Name(), operation.ExecutorConfig{
 		StateReadWriter: u.unit,
 		InitialState:    initialState,
 		AcquireLock:     u.acquireExecutionLock,
 		Logger:          u.logger.Child("operation"),
+		Clock:           u.clock,
+		Spans:           u.operationSpans,
 	})
 	if err != nil {
 		return errors.Trace(err)
@@ -1071,6 +1075,9 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookMessage)
+	if u.operationExecutor != nil && u.operationExecutor.State().HookTimedOut {
+		statusMessage = fmt.Sprintf("hook timed out: %q", hookMessage)
+	}
 	return setAgentStatus(u, status.Error, statusMessage, statusData)
 }
 
@@ -1096,6 +1103,9 @@ func (u *Uniter) Report() map[string]interface{} {
 	if u.operationExecutor != nil {
 		result["local-state"] = u.operationExecutor.State().Report()
 	}
+	if u.operationSpans != nil {
+		result["hook-history"] = u.operationSpans.Report()
+	}
 	if u.relationStateTracker != nil {
 		result["relations"] = u.relationStateTracker.Report()
 	}
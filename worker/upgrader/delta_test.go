@@ -0,0 +1,215 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrader_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version/v2"
+	gc "gopkg.in/check.v1"
+
+	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/core/arch"
+	coreos "github.com/juju/juju/core/os"
+	"github.com/juju/juju/core/os/ostype"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/tools/delta"
+	jujuversion "github.com/juju/juju/version"
+	"github.com/juju/juju/worker/upgrader"
+)
+
+type deltaSuite struct {
+	testing.IsolationSuite
+
+	dataDir     string
+	baseVersion version.Binary
+	baseFiles   []*coretesting.TarFile
+	base        []byte
+	baseSHA256  string
+
+	targetFiles []*coretesting.TarFile
+	target      []byte
+	agentTools  *coretools.Tools
+
+	// served is sent in response to requests for a delta, with the
+	// content type deltaContentType.
+	served           []byte
+	deltaContentType string
+
+	mu       sync.Mutex
+	requests []string
+}
+
+var _ = gc.Suite(&deltaSuite{})
+
+func (s *deltaSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.requests = nil
+
+	s.baseVersion = version.MustParseBinary("5.4.3-ubuntu-amd64")
+	s.PatchValue(&arch.HostArch, func() string { return s.baseVersion.Arch })
+	s.PatchValue(&coreos.HostOS, func() ostype.OSType { return ostype.Ubuntu })
+	s.PatchValue(&jujuversion.Current, s.baseVersion.Number)
+
+	r := rand.New(rand.NewSource(0))
+	jujud := randomString(r, 256*1024)
+	jujuc := randomString(r, 32*1024)
+	newJujud := jujud[:100000] + randomString(r, 2000) + jujud[110000:]
+	s.baseFiles = []*coretesting.TarFile{
+		coretesting.NewTarFile("jujud", 0755, jujud),
+		coretesting.NewTarFile("jujuc", 0755, jujuc),
+	}
+	s.targetFiles = []*coretesting.TarFile{
+		coretesting.NewTarFile("jujud", 0755, newJujud),
+		coretesting.NewTarFile("jujuc", 0755, jujuc),
+	}
+
+	s.base, s.baseSHA256 = coretesting.TarGz(s.baseFiles...)
+	var targetSHA256 string
+	s.target, targetSHA256 = coretesting.TarGz(s.targetFiles...)
+
+	server := httptest.NewServer(http.HandlerFunc(s.serveTools))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	s.agentTools = &coretools.Tools{
+		Version: version.MustParseBinary("5.4.5-ubuntu-amd64"),
+		URL:     server.URL + "/tools/5.4.5-ubuntu-amd64",
+		SHA256:  targetSHA256,
+		Size:    int64(len(s.target)),
+	}
+	s.served = s.generateDelta(c, s.target)
+	s.deltaContentType = delta.ContentType
+}
+
+func randomString(r *rand.Rand, n int) string {
+	data := make([]byte, n)
+	_, _ = r.Read(data)
+	return string(data)
+}
+
+func (s *deltaSuite) generateDelta(c *gc.C, target []byte) []byte {
+	archiveSHA256, err := delta.ArchiveSHA256(bytes.NewReader(target))
+	c.Assert(err, jc.ErrorIsNil)
+	targetSHA256 := fmt.Sprintf("%x", sha256.Sum256(target))
+	d, err := delta.Generate(bytes.NewReader(s.base), bytes.NewReader(target), targetSHA256, archiveSHA256)
+	c.Assert(err, jc.ErrorIsNil)
+	return d
+}
+
+// serveTools records the delta-from parameter of each request, and
+// serves the delta when one is requested.
+func (s *deltaSuite) serveTools(w http.ResponseWriter, r *http.Request) {
+	deltaFrom := r.URL.Query().Get("delta-from")
+	s.mu.Lock()
+	s.requests = append(s.requests, deltaFrom)
+	s.mu.Unlock()
+
+	if deltaFrom != "" {
+		w.Header().Set("Content-Type", s.deltaContentType)
+		_, _ = w.Write(s.served)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-tar-gz")
+	_, _ = w.Write(s.target)
+}
+
+// installBase unpacks the base agent binaries, as if the agent was
+// running them.
+func (s *deltaSuite) installBase(c *gc.C) {
+	err := agenttools.UnpackTools(s.dataDir, &coretools.Tools{
+		Version: s.baseVersion,
+		SHA256:  s.baseSHA256,
+		Size:    int64(len(s.base)),
+	}, bytes.NewReader(s.base))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *deltaSuite) ensureTools(c *gc.C) {
+	err := upgrader.EnsureTools(s.dataDir, loggo.GetLogger("test"), s.agentTools)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *deltaSuite) checkRequests(c *gc.C, expected ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Check(s.requests, jc.DeepEquals, expected)
+}
+
+// checkTarget checks that the target agent binaries were unpacked.
+func (s *deltaSuite) checkTarget(c *gc.C) {
+	gotTools, err := agenttools.ReadTools(s.dataDir, s.agentTools.Version)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(gotTools.SHA256, gc.Equals, s.agentTools.SHA256)
+
+	dir := agenttools.SharedToolsDir(s.dataDir, s.agentTools.Version)
+	for _, f := range s.targetFiles {
+		content, err := os.ReadFile(filepath.Join(dir, f.Header.Name))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(content) == f.Contents, jc.IsTrue, gc.Commentf("%s differs", f.Header.Name))
+	}
+}
+
+func (s *deltaSuite) TestAppliesDelta(c *gc.C) {
+	s.installBase(c)
+	s.ensureTools(c)
+	s.checkRequests(c, "5.4.3-ubuntu-amd64")
+	s.checkTarget(c)
+}
+
+func (s *deltaSuite) TestNoDeltaWithoutBase(c *gc.C) {
+	s.ensureTools(c)
+	s.checkRequests(c, "")
+	s.checkTarget(c)
+}
+
+func (s *deltaSuite) TestFullTarballServedForDelta(c *gc.C) {
+	// Controllers without delta support ignore delta-from.
+	s.served = s.target
+	s.deltaContentType = "application/x-tar-gz"
+	s.installBase(c)
+	s.ensureTools(c)
+	s.checkRequests(c, "5.4.3-ubuntu-amd64")
+	s.checkTarget(c)
+}
+
+func (s *deltaSuite) TestFallsBackOnHashMismatch(c *gc.C) {
+	// A delta which rebuilds some other tarball fails the target's
+	// SHA-256 check, so the full tarball is fetched.
+	other, _ := coretesting.TarGz(append(s.targetFiles,
+		coretesting.NewTarFile("containeragent", 0755, "extra"))...)
+	s.served = s.generateDelta(c, other)
+	s.installBase(c)
+	s.ensureTools(c)
+	s.checkRequests(c, "5.4.3-ubuntu-amd64", "")
+	s.checkTarget(c)
+}
+
+func (s *deltaSuite) TestFallsBackOnChangedBase(c *gc.C) {
+	s.installBase(c)
+	err := os.WriteFile(filepath.Join(agenttools.SharedToolsDir(s.dataDir, s.baseVersion), "jujud"), []byte("changed"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.ensureTools(c)
+	s.checkRequests(c, "5.4.3-ubuntu-amd64", "")
+	s.checkTarget(c)
+}
+
+func (s *deltaSuite) TestFallsBackOnInvalidDelta(c *gc.C) {
+	s.served = []byte("not a delta")
+	s.installBase(c)
+	s.ensureTools(c)
+	s.checkRequests(c, "5.4.3-ubuntu-amd64", "")
+	s.checkTarget(c)
+}
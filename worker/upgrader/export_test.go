@@ -0,0 +1,18 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrader
+
+import (
+	coretools "github.com/juju/juju/tools"
+)
+
+// EnsureTools downloads and unpacks the agent binaries into the data
+// directory as the upgrader does, without starting the worker.
+func EnsureTools(dataDir string, logger Logger, agentTools *coretools.Tools) error {
+	u := &Upgrader{
+		dataDir: dataDir,
+		config:  Config{Logger: logger},
+	}
+	return u.ensureTools(agentTools)
+}
@@ -27,6 +27,7 @@ type Clock interface {
 type Logger interface {
 	Debugf(string, ...interface{})
 	Infof(string, ...interface{})
+	Warningf(string, ...interface{})
 	Errorf(string, ...interface{})
 }
 
@@ -6,7 +6,9 @@ package upgrader
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/juju/juju/core/arch"
 	coreos "github.com/juju/juju/core/os"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/tools/delta"
 	"github.com/juju/juju/upgrades"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/worker/gate"
@@ -246,11 +249,38 @@ func (u *Upgrader) newUpgradeReadyError(haveVersion version.Number, newVersion v
 }
 
 func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
-	u.config.Logger.Infof("fetching agent binaries from %q", agentTools.URL)
+	// Request a delta from the agent binaries we're running, if they
+	// were unpacked from a tarball, falling back to the full agent
+	// binaries if the delta can't be applied.
+	current := toBinaryVersion(jujuversion.Current, coreos.HostOSTypeName())
+	if currentTools, err := agenttools.ReadTools(u.dataDir, current); err == nil {
+		err := u.fetchTools(agentTools, currentTools.Version)
+		if err == nil {
+			return nil
+		}
+		u.config.Logger.Warningf("cannot upgrade from %s using a delta, fetching full agent binaries: %v", currentTools.Version, err)
+	}
+	return u.fetchTools(agentTools, version.Binary{})
+}
+
+// fetchTools downloads and unpacks the agent binaries. If deltaFrom is
+// set, a delta from those agent binaries is requested, which the
+// controller may send in place of the full agent binaries.
+func (u *Upgrader) fetchTools(agentTools *coretools.Tools, deltaFrom version.Binary) error {
+	toolsURL, err := url.Parse(agentTools.URL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if deltaFrom != (version.Binary{}) {
+		query := toolsURL.Query()
+		query.Set("delta-from", deltaFrom.String())
+		toolsURL.RawQuery = query.Encode()
+	}
+	u.config.Logger.Infof("fetching agent binaries from %q", toolsURL)
 	// The reader MUST verify the tools' hash, so there is no
 	// need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
 	client := jujuhttp.NewClient(jujuhttp.WithSkipHostnameVerification(true))
-	resp, err := client.Get(context.TODO(), agentTools.URL)
+	resp, err := client.Get(context.TODO(), toolsURL.String())
 	if err != nil {
 		return err
 	}
@@ -258,7 +288,22 @@ func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad HTTP response: %v", resp.Status)
 	}
-	err = agenttools.UnpackTools(u.dataDir, agentTools, resp.Body)
+
+	var body io.Reader = resp.Body
+	if deltaFrom != (version.Binary{}) && resp.Header.Get("Content-Type") == delta.ContentType {
+		// Rebuild the tarball from the delta as it's unpacked. The
+		// rebuilt archive is checked against the SHA-256 recorded by
+		// the controller, and the tarball against its SHA-256 as for
+		// a downloaded tarball.
+		u.config.Logger.Infof("applying agent binaries delta from %s", deltaFrom)
+		pr, pw := io.Pipe()
+		defer func() { _ = pr.Close() }()
+		go func() {
+			_ = pw.CloseWithError(delta.Apply(pw, resp.Body, agenttools.SharedToolsDir(u.dataDir, deltaFrom)))
+		}()
+		body = pr
+	}
+	err = agenttools.UnpackTools(u.dataDir, agentTools, body)
 	if err != nil {
 		return fmt.Errorf("cannot unpack agent binaries: %v", err)
 	}
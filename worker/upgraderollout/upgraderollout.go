@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v3"
+	"github.com/juju/worker/v3/catacomb"
+
+	"github.com/juju/juju/core/watcher"
+)
+
+// period is the amount of time to wait before advancing a running
+// staged upgrade, since the last time it was advanced. The rollout
+// document only changes when the rollout itself does, so machine and
+// unit status changes are picked up by polling.
+const period = 15 * time.Second
+
+// Facade exposes the controller methods needed by the worker.
+type Facade interface {
+	AdvanceUpgradeRollout() (bool, error)
+	WatchUpgradeRollout() (watcher.NotifyWatcher, error)
+}
+
+// Worker advances the model's staged upgrade, releasing the next
+// batch of machines once the previous batch has settled, and halting
+// the rollout when too many upgraded machines report errors.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	facade   Facade
+	watcher  watcher.NotifyWatcher
+	clock    clock.Clock
+	logger   Logger
+}
+
+// NewWorker returns a worker.Worker that calls AdvanceUpgradeRollout
+// whenever the staged upgrade changes, and periodically while it is
+// running.
+func NewWorker(facade Facade, clock clock.Clock, logger Logger) (worker.Worker, error) {
+	watcher, err := facade.WatchUpgradeRollout()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := Worker{
+		facade:  facade,
+		watcher: watcher,
+		clock:   clock,
+		logger:  logger,
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{watcher},
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &w, nil
+}
+
+func (w *Worker) loop() error {
+	// Models without a running staged upgrade aren't polled; starting
+	// or resuming one changes the rollout document.
+	var timeout <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-w.watcher.Changes():
+			if !ok {
+				return errors.New("change channel closed")
+			}
+		case <-timeout:
+		}
+		running, err := w.facade.AdvanceUpgradeRollout()
+		if err != nil {
+			// Failing to advance is retried when the timer
+			// next fires; there's no need to bounce the worker.
+			w.logger.Errorf("cannot advance staged upgrade: %v", err)
+			running = true
+		}
+		timeout = nil
+		if running {
+			timeout = w.clock.After(period)
+		}
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v3"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/tomb.v2"
+
+	"github.com/juju/juju/core/watcher"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/upgraderollout"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade *mockFacade
+	clock  *testclock.Clock
+	logger loggo.Logger
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &mockFacade{
+		calls: make(chan string, 1),
+	}
+	s.facade.watcher = s.newMockNotifyWatcher()
+	s.clock = testclock.NewClock(time.Time{})
+	s.logger = loggo.GetLogger("test")
+}
+
+func (s *WorkerSuite) assertReceived(c *gc.C, expect string) {
+	select {
+	case call := <-s.facade.calls:
+		c.Assert(call, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %s", expect)
+	}
+}
+
+func (s *WorkerSuite) assertEmpty(c *gc.C) {
+	select {
+	case call, ok := <-s.facade.calls:
+		c.Fatalf("unexpected %s (ok: %v)", call, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestAdvancesOnChange(c *gc.C) {
+	w, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.assertEmpty(c)
+
+	s.facade.watcher.Change()
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.assertEmpty(c)
+}
+
+func (s *WorkerSuite) TestAdvancesPeriodically(c *gc.C) {
+	s.facade.setRunning(true)
+	w, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.assertEmpty(c)
+
+	for i := 0; i < 2; i++ {
+		s.clock.WaitAdvance(14*time.Second, coretesting.LongWait, 1)
+		s.assertEmpty(c)
+		s.clock.WaitAdvance(1*time.Second, coretesting.LongWait, 1)
+		s.assertReceived(c, "AdvanceUpgradeRollout")
+		s.assertEmpty(c)
+	}
+}
+
+func (s *WorkerSuite) TestNoPollingWhenNotRunning(c *gc.C) {
+	w, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.clock.Advance(time.Hour)
+	s.assertEmpty(c)
+
+	// Starting an upgrade changes the rollout, after which it's
+	// polled until it stops running.
+	s.facade.setRunning(true)
+	s.facade.watcher.Change()
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.facade.setRunning(false)
+	s.clock.WaitAdvance(15*time.Second, coretesting.LongWait, 1)
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.clock.Advance(time.Hour)
+	s.assertEmpty(c)
+}
+
+func (s *WorkerSuite) TestWatchError(c *gc.C) {
+	s.facade.err = []error{errors.New("hello")}
+	_, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, gc.ErrorMatches, "hello")
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertEmpty(c)
+}
+
+func (s *WorkerSuite) TestAdvanceErrorIsLogged(c *gc.C) {
+	s.facade.err = []error{nil, errors.New("hello")}
+	w, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	err = worker.Stop(w)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, "ERROR test cannot advance staged upgrade: hello")
+}
+
+func (s *WorkerSuite) TestAdvanceErrorIsRetried(c *gc.C) {
+	s.facade.err = []error{nil, errors.New("hello")}
+	w, err := upgraderollout.NewWorker(s.facade, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertReceived(c, "WatchUpgradeRollout")
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+	s.clock.WaitAdvance(15*time.Second, coretesting.LongWait, 1)
+	s.assertReceived(c, "AdvanceUpgradeRollout")
+}
+
+func (s *WorkerSuite) newMockNotifyWatcher() *mockNotifyWatcher {
+	m := &mockNotifyWatcher{
+		changes: make(chan struct{}, 1),
+	}
+	m.tomb.Go(func() error {
+		<-m.tomb.Dying()
+		return nil
+	})
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(m), jc.ErrorIsNil)
+	})
+	m.Change()
+	return m
+}
+
+type mockNotifyWatcher struct {
+	watcher.NotifyWatcher
+
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func (m *mockNotifyWatcher) Kill() {
+	m.tomb.Kill(nil)
+}
+
+func (m *mockNotifyWatcher) Wait() error {
+	return m.tomb.Wait()
+}
+
+func (m *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return m.changes
+}
+
+func (m *mockNotifyWatcher) Change() {
+	m.changes <- struct{}{}
+}
+
+type mockFacade struct {
+	watcher *mockNotifyWatcher
+	calls   chan string
+	err     []error
+
+	mu      sync.Mutex
+	running bool
+}
+
+func (m *mockFacade) setRunning(running bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = running
+}
+
+func (m *mockFacade) nextError() (err error) {
+	if len(m.err) > 0 {
+		err, m.err = m.err[0], m.err[1:]
+	}
+	return err
+}
+
+func (m *mockFacade) AdvanceUpgradeRollout() (bool, error) {
+	m.mu.Lock()
+	running := m.running
+	m.mu.Unlock()
+	m.calls <- "AdvanceUpgradeRollout"
+	return running, m.nextError()
+}
+
+func (m *mockFacade) WatchUpgradeRollout() (watcher.NotifyWatcher, error) {
+	m.calls <- "WatchUpgradeRollout"
+	return m.watcher, m.nextError()
+}